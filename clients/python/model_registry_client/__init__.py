@@ -0,0 +1,169 @@
+# Code generated by go run ./cmd/genclients from the Model ID Cheatsheet REST API 1.4.0 spec. DO NOT EDIT.
+"""Client for the model registry REST API (standard library only)."""
+
+from __future__ import annotations
+
+import json
+import urllib.error
+import urllib.parse
+import urllib.request
+from typing import Any, NotRequired, TypedDict
+
+
+class Change(TypedDict):
+    date: str
+    fields: NotRequired[list[str] | None]
+    kind: str
+    model: NotRequired[Model]
+    "The model's current state, for added and changed entries"
+    model_id: str
+    seq: int
+
+
+class ChangesResponse(TypedDict):
+    changes: list[Change] | None
+    "Registry mutations after since, oldest first"
+    cursor: str
+    "Cursor to pass as since on the next poll"
+    resync: bool
+    "True when since is unknown to this server; refetch /api/registry"
+
+
+class Health(TypedDict):
+    build_date: NotRequired[str]
+    "Build time, RFC 3339"
+    commit: NotRequired[str]
+    "VCS revision the server was built from"
+    models: int
+    registry: str
+    "Content hash of the base registry; changes whenever model data does"
+    status: str
+    tenants: int
+    transport: str
+    uptime_secs: int
+    version: str
+    "Server build version; dev when not stamped at build time"
+
+
+class Model(TypedDict):
+    agent_guidance: NotRequired[list[str] | None]
+    announcement_url: NotRequired[str]
+    batch_api: bool
+    context_window: int
+    deprecation_date: NotRequired[str]
+    display_name: str
+    docs_url: NotRequired[str]
+    eu_hosted: bool
+    id: str
+    json_mode: bool
+    knowledge_cutoff: str
+    maturity: str
+    max_output_tokens: int
+    model_card_url: NotRequired[str]
+    notes: str
+    pricing_batch_input: NotRequired[float]
+    pricing_batch_output: NotRequired[float]
+    pricing_cached_input: NotRequired[float]
+    pricing_input: float
+    pricing_output: float
+    provider: str
+    provider_default: NotRequired[bool]
+    reasoning: bool
+    release_date: str
+    retirement_date: NotRequired[str]
+    status: str
+    structured_output: bool
+    system_prompt: str
+    tool_calling: bool
+    vision: bool
+
+
+class ModelList(TypedDict):
+    count: int
+    models: list[Model] | None
+    "Matching models, grouped by provider and newest first; /api/v1/compare keeps the requested order"
+
+
+class RecommendResponse(TypedDict):
+    budget: str
+    "Normalized budget: cheap, moderate, or expensive"
+    recommendations: list[Recommendation] | None
+    "Up to 3 picks, best first"
+    task: str
+
+
+class Recommendation(TypedDict):
+    churn_risk: NotRequired[str]
+    "Predecessors deprecated within months of release, for tasks that ask for stability"
+    model: Model
+    rank: int
+    "1 for the best pick"
+    score: float
+    "Relative score from the recommend_model heuristics; only comparable within one response"
+
+
+class RegistryResponse(TypedDict):
+    cursor: str
+    "Changelog cursor this snapshot corresponds to; pass it to /api/changes as since"
+    models: dict[str, Model]
+    "Every model in the registry, keyed by model ID"
+
+
+class RegistryError(Exception):
+    """Raised for non-2xx responses."""
+
+    def __init__(self, status: int, body: str) -> None:
+        super().__init__(f"registry API returned {status}: {body}")
+        self.status = status
+        self.body = body
+
+
+class RegistryClient:
+    """Client for the model registry REST API."""
+
+    def __init__(self, base_url: str, timeout: float = 30.0) -> None:
+        self.base_url = base_url.rstrip("/")
+        self.timeout = timeout
+
+    def _get(self, path: str, query: dict[str, Any] | None = None) -> Any:
+        params = {k: v for k, v in (query or {}).items() if v is not None}
+        url = self.base_url + path
+        if params:
+            url += "?" + urllib.parse.urlencode(params)
+        try:
+            with urllib.request.urlopen(url, timeout=self.timeout) as res:
+                return json.load(res)
+        except urllib.error.HTTPError as err:
+            raise RegistryError(err.code, err.read().decode("utf-8", "replace")) from err
+
+    def get_changes(self, *, since: int) -> ChangesResponse:
+        "Registry mutations after a cursor, for delta sync"
+        return self._get("/api/changes", {"since": since})
+
+    def get_registry(self) -> RegistryResponse:
+        "Full registry snapshot plus the changelog cursor it corresponds to"
+        return self._get("/api/registry")
+
+    def compare_models(self, *, ids: str) -> ModelList:
+        "Two to five models side by side, in the order requested"
+        return self._get("/api/v1/compare", {"ids": ids})
+
+    def list_models(self, *, provider: str | None = None, status: str | None = None, capability: str | None = None, sovereignty: str | None = None) -> ModelList:
+        "Models matching optional filters, after org policy"
+        return self._get("/api/v1/models", {"provider": provider, "status": status, "capability": capability, "sovereignty": sovereignty})
+
+    def get_model(self, *, model_id: str) -> Model:
+        "One model by ID or alias, with the same fallback matching as get_model_info"
+        return self._get(f"/api/v1/models/{urllib.parse.quote(str(model_id), safe='')}")
+
+    def recommend_models(self, *, task: str, budget: str | None = None, sovereignty: str | None = None, min_providers: int | None = None) -> RecommendResponse:
+        "Top picks for a task, scored like the recommend_model tool"
+        return self._get("/api/v1/recommend", {"task": task, "budget": budget, "sovereignty": sovereignty, "min_providers": min_providers})
+
+    def search_models(self, *, q: str) -> ModelList:
+        "Models whose names, provider, notes, capabilities, or aliases match every word of a query"
+        return self._get("/api/v1/search", {"q": q})
+
+    def get_health(self) -> Health:
+        "Liveness, registry size, and registry content hash"
+        return self._get("/health")
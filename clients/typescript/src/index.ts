@@ -0,0 +1,175 @@
+// Code generated by go run ./cmd/genclients from the Model ID Cheatsheet REST API 1.4.0 spec. DO NOT EDIT.
+
+export interface Change {
+  date: string;
+  fields?: string[] | null;
+  kind: string;
+  /** The model's current state, for added and changed entries */
+  model?: Model;
+  model_id: string;
+  seq: number;
+}
+
+export interface ChangesResponse {
+  /** Registry mutations after since, oldest first */
+  changes: Change[] | null;
+  /** Cursor to pass as since on the next poll */
+  cursor: string;
+  /** True when since is unknown to this server; refetch /api/registry */
+  resync: boolean;
+}
+
+export interface Health {
+  /** Build time, RFC 3339 */
+  build_date?: string;
+  /** VCS revision the server was built from */
+  commit?: string;
+  models: number;
+  /** Content hash of the base registry; changes whenever model data does */
+  registry: string;
+  status: string;
+  tenants: number;
+  transport: string;
+  uptime_secs: number;
+  /** Server build version; dev when not stamped at build time */
+  version: string;
+}
+
+export interface Model {
+  agent_guidance?: string[] | null;
+  announcement_url?: string;
+  batch_api: boolean;
+  context_window: number;
+  deprecation_date?: string;
+  display_name: string;
+  docs_url?: string;
+  eu_hosted: boolean;
+  id: string;
+  json_mode: boolean;
+  knowledge_cutoff: string;
+  maturity: string;
+  max_output_tokens: number;
+  model_card_url?: string;
+  notes: string;
+  pricing_batch_input?: number;
+  pricing_batch_output?: number;
+  pricing_cached_input?: number;
+  pricing_input: number;
+  pricing_output: number;
+  provider: string;
+  provider_default?: boolean;
+  reasoning: boolean;
+  release_date: string;
+  retirement_date?: string;
+  status: string;
+  structured_output: boolean;
+  system_prompt: string;
+  tool_calling: boolean;
+  vision: boolean;
+}
+
+export interface ModelList {
+  count: number;
+  /** Matching models, grouped by provider and newest first; /api/v1/compare keeps the requested order */
+  models: Model[] | null;
+}
+
+export interface RecommendResponse {
+  /** Normalized budget: cheap, moderate, or expensive */
+  budget: string;
+  /** Up to 3 picks, best first */
+  recommendations: Recommendation[] | null;
+  task: string;
+}
+
+export interface Recommendation {
+  /** Predecessors deprecated within months of release, for tasks that ask for stability */
+  churn_risk?: string;
+  model: Model;
+  /** 1 for the best pick */
+  rank: number;
+  /** Relative score from the recommend_model heuristics; only comparable within one response */
+  score: number;
+}
+
+export interface RegistryResponse {
+  /** Changelog cursor this snapshot corresponds to; pass it to /api/changes as since */
+  cursor: string;
+  /** Every model in the registry, keyed by model ID */
+  models: Record<string, Model>;
+}
+
+/** Thrown for non-2xx responses. */
+export class RegistryError extends Error {
+  constructor(
+    public readonly status: number,
+    public readonly body: string,
+  ) {
+    super(`registry API returned ${status}: ${body}`);
+    this.name = "RegistryError";
+  }
+}
+
+/** Client for the model registry REST API. */
+export class RegistryClient {
+  private readonly baseUrl: string;
+
+  constructor(
+    baseUrl: string,
+    private readonly fetchImpl: typeof fetch = globalThis.fetch,
+  ) {
+    this.baseUrl = baseUrl.replace(/\/+$/, "");
+  }
+
+  private async get<T>(path: string, query: Record<string, string | number | boolean | undefined> = {}): Promise<T> {
+    const params = new URLSearchParams();
+    for (const [key, value] of Object.entries(query)) {
+      if (value !== undefined) params.set(key, String(value));
+    }
+    const qs = params.toString();
+    const res = await this.fetchImpl(this.baseUrl + path + (qs ? "?" + qs : ""));
+    if (!res.ok) throw new RegistryError(res.status, await res.text());
+    return (await res.json()) as T;
+  }
+
+  /** Registry mutations after a cursor, for delta sync */
+  getChanges(params: { since: number }): Promise<ChangesResponse> {
+    return this.get("/api/changes", params);
+  }
+
+  /** Full registry snapshot plus the changelog cursor it corresponds to */
+  getRegistry(): Promise<RegistryResponse> {
+    return this.get("/api/registry");
+  }
+
+  /** Two to five models side by side, in the order requested */
+  compareModels(params: { ids: string }): Promise<ModelList> {
+    return this.get("/api/v1/compare", params);
+  }
+
+  /** Models matching optional filters, after org policy */
+  listModels(params: { provider?: string; status?: string; capability?: string; sovereignty?: string } = {}): Promise<ModelList> {
+    return this.get("/api/v1/models", params);
+  }
+
+  /** One model by ID or alias, with the same fallback matching as get_model_info */
+  getModel(params: { model_id: string }): Promise<Model> {
+    const { model_id } = params;
+    return this.get(`/api/v1/models/${encodeURIComponent(String(model_id))}`);
+  }
+
+  /** Top picks for a task, scored like the recommend_model tool */
+  recommendModels(params: { task: string; budget?: string; sovereignty?: string; min_providers?: number }): Promise<RecommendResponse> {
+    return this.get("/api/v1/recommend", params);
+  }
+
+  /** Models whose names, provider, notes, capabilities, or aliases match every word of a query */
+  searchModels(params: { q: string }): Promise<ModelList> {
+    return this.get("/api/v1/search", params);
+  }
+
+  /** Liveness, registry size, and registry content hash */
+  getHealth(): Promise<Health> {
+    return this.get("/health");
+  }
+}
@@ -0,0 +1,215 @@
+// Command bench measures output throughput and time to first token for
+// registry models by streaming a short completion from each provider's
+// OpenAI-compatible chat API. Only providers whose API key is set are probed.
+//
+// Usage:
+//
+//	OPENAI_API_KEY=... go run ./cmd/bench [-models gpt-4.1,gpt-4o] [-runs 3]
+//
+// Results are printed as lines for models.Speeds in internal/models/speed.go.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"go-server/internal/models"
+)
+
+// chatEndpoints are the OpenAI-compatible streaming chat APIs per provider.
+var chatEndpoints = map[string]struct {
+	URL    string
+	EnvKey string
+}{
+	"OpenAI":   {URL: "https://api.openai.com/v1/chat/completions", EnvKey: "OPENAI_API_KEY"},
+	"Google":   {URL: "https://generativelanguage.googleapis.com/v1beta/openai/chat/completions", EnvKey: "GEMINI_API_KEY"},
+	"Mistral":  {URL: "https://api.mistral.ai/v1/chat/completions", EnvKey: "MISTRAL_API_KEY"},
+	"xAI":      {URL: "https://api.x.ai/v1/chat/completions", EnvKey: "XAI_API_KEY"},
+	"DeepSeek": {URL: "https://api.deepseek.com/chat/completions", EnvKey: "DEEPSEEK_API_KEY"},
+}
+
+// benchPrompt asks for a long-enough answer to measure steady-state throughput.
+const benchPrompt = "Write a 300-word explanation of how TCP congestion control works."
+
+// probeResult is one streamed completion's timing.
+type probeResult struct {
+	TTFT         time.Duration
+	Tokens       int
+	TokensPerSec float64
+}
+
+func main() {
+	only := flag.String("models", "", "comma-separated model IDs to probe (default: every current model with a configured key)")
+	runs := flag.Int("runs", 3, "probes per model; the median is reported")
+	maxTokens := flag.Int("max-tokens", 512, "max output tokens per probe")
+	flag.Parse()
+
+	client := &http.Client{Timeout: 2 * time.Minute}
+	ctx := context.Background()
+	month := time.Now().UTC().Format("2006-01")
+
+	ids := selectModels(*only)
+	if len(ids) == 0 {
+		fmt.Fprintln(os.Stderr, "bench: no models to probe (set a provider API key, e.g. OPENAI_API_KEY)")
+		os.Exit(2)
+	}
+
+	failed := false
+	for _, id := range ids {
+		ep := chatEndpoints[models.Models[id].Provider]
+		var results []probeResult
+		for i := 0; i < *runs; i++ {
+			r, err := probe(ctx, client, ep.URL, os.Getenv(ep.EnvKey), id, *maxTokens)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "bench: %s run %d: %v\n", id, i+1, err)
+				continue
+			}
+			results = append(results, r)
+		}
+		if len(results) == 0 {
+			failed = true
+			continue
+		}
+		m := median(results)
+		fmt.Printf("\t%q: {OutputTokensPerSec: %.0f, TTFTMillis: %d, Source: \"cmd/bench\", MeasuredAt: %q},\n",
+			id, m.TokensPerSec, m.TTFT.Milliseconds(), month)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// selectModels returns the model IDs to probe: the explicit list, or every
+// current model whose provider has an endpoint and a key in the environment.
+func selectModels(only string) []string {
+	var ids []string
+	if only != "" {
+		for _, id := range strings.Split(only, ",") {
+			id = strings.TrimSpace(id)
+			m, ok := models.Models[id]
+			if !ok {
+				fmt.Fprintf(os.Stderr, "bench: unknown model %q, skipping\n", id)
+				continue
+			}
+			ep, ok := chatEndpoints[m.Provider]
+			if !ok || os.Getenv(ep.EnvKey) == "" {
+				fmt.Fprintf(os.Stderr, "bench: no API key for %s (%s), skipping\n", id, m.Provider)
+				continue
+			}
+			ids = append(ids, id)
+		}
+		return ids
+	}
+	for id, m := range models.Models {
+		ep, ok := chatEndpoints[m.Provider]
+		if m.Status == "current" && ok && os.Getenv(ep.EnvKey) != "" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// probe streams one completion and measures time to the first content token
+// and output throughput after it. Token counts come from the final usage
+// chunk when the API reports one, otherwise from the number of content chunks.
+func probe(ctx context.Context, client *http.Client, url, key, model string, maxTokens int) (probeResult, error) {
+	payload, _ := json.Marshal(map[string]any{
+		"model":          model,
+		"messages":       []map[string]string{{"role": "user", "content": benchPrompt}},
+		"max_tokens":     maxTokens,
+		"stream":         true,
+		"stream_options": map[string]bool{"include_usage": true},
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return probeResult{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+key)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return probeResult{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return probeResult{}, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	var first, last time.Time
+	chunks, usageTokens := 0, 0
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok || data == "[DONE]" {
+			continue
+		}
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+			Usage *struct {
+				CompletionTokens int `json:"completion_tokens"`
+			} `json:"usage"`
+		}
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			continue
+		}
+		if chunk.Usage != nil {
+			usageTokens = chunk.Usage.CompletionTokens
+		}
+		if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+			now := time.Now()
+			if first.IsZero() {
+				first = now
+			}
+			last = now
+			chunks++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return probeResult{}, err
+	}
+	if first.IsZero() {
+		return probeResult{}, errors.New("no content in stream")
+	}
+
+	tokens := usageTokens
+	if tokens == 0 {
+		tokens = chunks
+	}
+	r := probeResult{TTFT: first.Sub(start), Tokens: tokens}
+	if gen := last.Sub(first).Seconds(); gen > 0 {
+		r.TokensPerSec = float64(tokens) / gen
+	}
+	return r, nil
+}
+
+// median returns the run with the median throughput, paired with the median
+// TTFT across runs.
+func median(rs []probeResult) probeResult {
+	tps := make([]float64, len(rs))
+	ttft := make([]time.Duration, len(rs))
+	for i, r := range rs {
+		tps[i], ttft[i] = r.TokensPerSec, r.TTFT
+	}
+	sort.Float64s(tps)
+	sort.Slice(ttft, func(i, j int) bool { return ttft[i] < ttft[j] })
+	return probeResult{TokensPerSec: tps[len(tps)/2], TTFT: ttft[len(ttft)/2]}
+}
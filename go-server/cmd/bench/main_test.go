@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestProbe_StreamWithUsage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("missing bearer token")
+		}
+		flusher := w.(http.Flusher)
+		w.Header().Set("Content-Type", "text/event-stream")
+		time.Sleep(20 * time.Millisecond)
+		for i := 0; i < 5; i++ {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":\"tok%d \"}}]}\n\n", i)
+			flusher.Flush()
+			time.Sleep(5 * time.Millisecond)
+		}
+		fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"completion_tokens\":40}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	r, err := probe(context.Background(), srv.Client(), srv.URL, "key", "gpt-4.1", 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Tokens != 40 {
+		t.Errorf("Tokens = %d, want usage count 40", r.Tokens)
+	}
+	if r.TTFT < 20*time.Millisecond {
+		t.Errorf("TTFT = %v, want >= 20ms", r.TTFT)
+	}
+	if r.TokensPerSec <= 0 {
+		t.Errorf("TokensPerSec = %v, want > 0", r.TokensPerSec)
+	}
+}
+
+func TestProbe_Errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/empty" {
+			fmt.Fprint(w, "data: [DONE]\n\n")
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	if _, err := probe(context.Background(), srv.Client(), srv.URL, "bad", "gpt-4.1", 64); err == nil {
+		t.Error("expected error for HTTP 401")
+	}
+	if _, err := probe(context.Background(), srv.Client(), srv.URL+"/empty", "key", "gpt-4.1", 64); err == nil {
+		t.Error("expected error for stream without content")
+	}
+}
+
+func TestMedian(t *testing.T) {
+	m := median([]probeResult{
+		{TokensPerSec: 100, TTFT: 300 * time.Millisecond},
+		{TokensPerSec: 50, TTFT: 900 * time.Millisecond},
+		{TokensPerSec: 80, TTFT: 400 * time.Millisecond},
+	})
+	if m.TokensPerSec != 80 || m.TTFT != 400*time.Millisecond {
+		t.Errorf("median = %+v, want 80 tok/s and 400ms", m)
+	}
+}
+
+func TestSelectModels_RequiresKey(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	if ids := selectModels("gpt-4.1"); len(ids) != 0 {
+		t.Errorf("expected no models without a key, got %v", ids)
+	}
+	t.Setenv("OPENAI_API_KEY", "x")
+	if ids := selectModels("gpt-4.1, nonexistent"); len(ids) != 1 || ids[0] != "gpt-4.1" {
+		t.Errorf("expected [gpt-4.1], got %v", ids)
+	}
+}
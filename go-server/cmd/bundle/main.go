@@ -0,0 +1,226 @@
+// Command bundle writes the registry as a versioned static JSON bundle, plus
+// its JSON Schema and npm and PyPI package skeletons, so offline consumers
+// can pin a registry version without calling the server.
+//
+// Usage:
+//
+//	bundle [-out DIR] [-version VERSION]
+//
+// DIR (default ../dist/bundle) receives:
+//
+//	registry-VERSION.json        the bundle, for GitHub Release assets and Pages
+//	registry.json                the same bundle under a stable name
+//	registry.schema.json         JSON Schema for the bundle
+//	npm/                         package.json, registry.json, schema, index.js, index.d.ts
+//	python/                      pyproject.toml and the model_registry_data package
+//
+// VERSION defaults to 1.CURSOR.0, where CURSOR is the changelog cursor the
+// registry corresponds to, so every changelog entry bumps the minor version.
+// Release builds pass the tag instead. Output is deterministic: the same
+// registry and version produce byte-identical files.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"go-server/internal/changelog"
+	"go-server/internal/models"
+	"go-server/internal/tools"
+)
+
+// schemaURL is where registry.schema.json is published on GitHub Pages.
+const schemaURL = "https://aezizhu.github.io/universal-model-registry/registry.schema.json"
+
+// Package names on npm and PyPI.
+const (
+	npmName = "model-registry-data"
+	pyName  = "model-registry-data"
+)
+
+// Bundle is the content of registry.json.
+type Bundle struct {
+	Schema   string                  `json:"$schema"`
+	Version  string                  `json:"version" jsonschema:"Bundle version; pin this to get the same registry again"`
+	Registry string                  `json:"registry" jsonschema:"Content hash of the models; the same value /health reports as registry"`
+	Cursor   string                  `json:"cursor" jsonschema:"Changelog cursor of this snapshot; pass it to /api/changes as since to catch up"`
+	Models   map[string]models.Model `json:"models" jsonschema:"Every model in the registry, keyed by model ID"`
+}
+
+// semver matches the versions npm and PyPI both accept.
+var semver = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+
+func main() {
+	out := flag.String("out", "../dist/bundle", "directory to write the bundle into")
+	version := flag.String("version", "", "bundle version (default 1.<changelog cursor>.0)")
+	flag.Parse()
+
+	v := *version
+	if v == "" {
+		v = defaultVersion(changelog.Cursor())
+	}
+	if !semver.MatchString(v) {
+		fmt.Fprintf(os.Stderr, "bundle: version %q must be MAJOR.MINOR.PATCH\n", v)
+		os.Exit(2)
+	}
+
+	files, err := build(v, models.Models, tools.BaseRegistry().Info().Version, changelog.Cursor())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bundle: %v\n", err)
+		os.Exit(1)
+	}
+	for _, name := range sortedKeys(files) {
+		path := filepath.Join(*out, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			fmt.Fprintf(os.Stderr, "bundle: %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(path, files[name], 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "bundle: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "bundle: wrote %s %s (%d models) to %s\n", npmName, v, len(models.Models), *out)
+}
+
+func defaultVersion(cursor int) string {
+	return fmt.Sprintf("1.%d.0", cursor)
+}
+
+// build renders every bundle file, keyed by path relative to the output
+// directory. hash is the registry content hash of ms.
+func build(version string, ms map[string]models.Model, hash string, cursor int) (map[string][]byte, error) {
+	data, err := json.MarshalIndent(Bundle{
+		Schema:   schemaURL,
+		Version:  version,
+		Registry: hash,
+		Cursor:   strconv.Itoa(cursor),
+		Models:   ms,
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	schema, err := bundleSchema()
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := json.MarshalIndent(map[string]any{
+		"name":        npmName,
+		"version":     version,
+		"description": "Static snapshot of the Model ID Cheatsheet registry",
+		"license":     "MIT",
+		"main":        "index.js",
+		"types":       "index.d.ts",
+		"files":       []string{"index.js", "index.d.ts", "registry.json", "registry.schema.json"},
+		"repository":  "github:aezizhu/universal-model-registry",
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	data, schema, pkg = append(data, '\n'), append(schema, '\n'), append(pkg, '\n')
+	return map[string][]byte{
+		"registry-" + version + ".json": data,
+		"registry.json":                 data,
+		"registry.schema.json":          schema,
+
+		"npm/package.json":         pkg,
+		"npm/registry.json":        data,
+		"npm/registry.schema.json": schema,
+		"npm/index.js":             []byte(npmIndex),
+		"npm/index.d.ts":           []byte(npmTypes),
+
+		"python/pyproject.toml":                           []byte(fmt.Sprintf(pyproject, pyName, version)),
+		"python/model_registry_data/__init__.py":          []byte(pyInit),
+		"python/model_registry_data/registry.json":        data,
+		"python/model_registry_data/registry.schema.json": schema,
+	}, nil
+}
+
+// bundleSchema returns the JSON Schema for Bundle.
+func bundleSchema() ([]byte, error) {
+	s, err := jsonschema.For[Bundle](nil)
+	if err != nil {
+		return nil, err
+	}
+	s.Schema = "https://json-schema.org/draft/2020-12/schema"
+	s.ID = schemaURL
+	s.Title = "Model ID Cheatsheet registry bundle"
+	return json.MarshalIndent(s, "", "  ")
+}
+
+func sortedKeys(m map[string][]byte) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+const npmIndex = `"use strict";
+// Static snapshot of the model registry. See registry.schema.json.
+module.exports = require("./registry.json");
+`
+
+const npmTypes = `export interface Model {
+  id: string;
+  display_name: string;
+  provider: string;
+  context_window: number;
+  max_output_tokens: number;
+  vision: boolean;
+  reasoning: boolean;
+  pricing_input: number;
+  pricing_output: number;
+  knowledge_cutoff: string;
+  release_date: string;
+  status: string;
+  notes: string;
+  [field: string]: unknown;
+}
+
+export interface Bundle {
+  version: string;
+  registry: string;
+  cursor: string;
+  models: Record<string, Model>;
+}
+
+declare const bundle: Bundle;
+export = bundle;
+`
+
+const pyproject = `[project]
+name = %q
+version = %q
+description = "Static snapshot of the Model ID Cheatsheet registry"
+license = "MIT"
+requires-python = ">=3.9"
+dependencies = []
+
+[build-system]
+requires = ["hatchling"]
+build-backend = "hatchling.build"
+
+[tool.hatch.build.targets.wheel]
+packages = ["model_registry_data"]
+`
+
+const pyInit = `"""Static snapshot of the model registry. See registry.schema.json."""
+
+import json
+from importlib import resources
+
+BUNDLE = json.loads(resources.files(__name__).joinpath("registry.json").read_text("utf-8"))
+VERSION = BUNDLE["version"]
+MODELS = BUNDLE["models"]
+`
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"go-server/internal/models"
+)
+
+func TestBuildIsDeterministic(t *testing.T) {
+	a, err := build("1.2.0", models.Models, "abc123", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := build("1.2.0", models.Models, "abc123", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range a {
+		if !bytes.Equal(data, b[name]) {
+			t.Errorf("%s differs between identical builds", name)
+		}
+	}
+	if !bytes.Equal(a["registry-1.2.0.json"], a["npm/registry.json"]) ||
+		!bytes.Equal(a["registry.json"], a["python/model_registry_data/registry.json"]) {
+		t.Error("every copy of the bundle should be identical")
+	}
+}
+
+func TestBundleContent(t *testing.T) {
+	files, err := build("1.2.0", models.Models, "abc123", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b Bundle
+	if err := json.Unmarshal(files["registry.json"], &b); err != nil {
+		t.Fatal(err)
+	}
+	if b.Version != "1.2.0" || b.Registry != "abc123" || b.Cursor != "2" || len(b.Models) != len(models.Models) {
+		t.Errorf("unexpected bundle header: version %q registry %q cursor %q with %d models", b.Version, b.Registry, b.Cursor, len(b.Models))
+	}
+	var pkg struct{ Name, Version string }
+	if err := json.Unmarshal(files["npm/package.json"], &pkg); err != nil {
+		t.Fatal(err)
+	}
+	if pkg.Name != npmName || pkg.Version != "1.2.0" {
+		t.Errorf("package.json = %+v", pkg)
+	}
+	if !strings.Contains(string(files["python/pyproject.toml"]), `version = "1.2.0"`) {
+		t.Error("pyproject.toml should carry the bundle version")
+	}
+}
+
+func TestBundleMatchesSchema(t *testing.T) {
+	files, err := build("1.2.0", models.Models, "abc123", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s jsonschema.Schema
+	if err := json.Unmarshal(files["registry.schema.json"], &s); err != nil {
+		t.Fatal(err)
+	}
+	// Resolve without fetching the published $id.
+	s.ID = ""
+	resolved, err := s.Resolve(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var instance map[string]any
+	if err := json.Unmarshal(files["registry.json"], &instance); err != nil {
+		t.Fatal(err)
+	}
+	if err := resolved.Validate(instance); err != nil {
+		t.Errorf("bundle does not match its schema: %v", err)
+	}
+	delete(instance, "models")
+	if err := resolved.Validate(instance); err == nil {
+		t.Error("schema should require models")
+	}
+}
+
+func TestDefaultVersion(t *testing.T) {
+	for cursor, want := range map[int]string{0: "1.0.0", 7: "1.7.0"} {
+		if got := defaultVersion(cursor); got != want || !semver.MatchString(got) {
+			t.Errorf("defaultVersion(%d) = %q, want %q", cursor, got, want)
+		}
+	}
+	for _, bad := range []string{"v1.2.0", "1.2", "1.2.0-rc1"} {
+		if semver.MatchString(bad) {
+			t.Errorf("%q should not be accepted as a bundle version", bad)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+// Command genclients generates the TypeScript and Python REST clients under
+// clients/ from the OpenAPI spec.
+//
+// Usage:
+//
+//	genclients [-spec FILE] [-out DIR]
+//
+// The spec defaults to the one compiled into this binary (internal/openapi,
+// also served at /api/openapi.json), and DIR to ../clients, so running
+// `go run ./cmd/genclients` from go-server regenerates the checked-in
+// clients. Both clients use only their language's standard library.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"go-server/internal/openapi"
+)
+
+// Generated client paths, relative to the output directory.
+const (
+	tsFile = "typescript/src/index.ts"
+	pyFile = "python/model_registry_client/__init__.py"
+)
+
+func main() {
+	specFile := flag.String("spec", "", "read the OpenAPI spec from this file instead of the built-in one")
+	out := flag.String("out", "../clients", "directory to write the clients into")
+	flag.Parse()
+
+	data := openapi.JSON()
+	if *specFile != "" {
+		var err error
+		if data, err = os.ReadFile(*specFile); err != nil {
+			fmt.Fprintf(os.Stderr, "genclients: %v\n", err)
+			os.Exit(2)
+		}
+	}
+	files, err := generate(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "genclients: %v\n", err)
+		os.Exit(2)
+	}
+	for _, name := range []string{tsFile, pyFile} {
+		path := filepath.Join(*out, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			fmt.Fprintf(os.Stderr, "genclients: %v\n", err)
+			os.Exit(2)
+		}
+		if err := os.WriteFile(path, []byte(files[name]), 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "genclients: %v\n", err)
+			os.Exit(2)
+		}
+		fmt.Fprintf(os.Stderr, "genclients: wrote %s\n", path)
+	}
+}
+
+// spec is the subset of an OpenAPI 3.1 document the generator reads.
+type spec struct {
+	Info struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	} `json:"info"`
+	Paths map[string]struct {
+		Get *struct {
+			OperationID string `json:"operationId"`
+			Summary     string `json:"summary"`
+			Parameters  []struct {
+				Name        string  `json:"name"`
+				In          string  `json:"in"`
+				Required    bool    `json:"required"`
+				Description string  `json:"description"`
+				Schema      *schema `json:"schema"`
+			} `json:"parameters"`
+			Responses map[string]struct {
+				Content map[string]struct {
+					Schema *schema `json:"schema"`
+				} `json:"content"`
+			} `json:"responses"`
+		} `json:"get"`
+	} `json:"paths"`
+	Components struct {
+		Schemas map[string]*schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// schema is the subset of a JSON schema the generator maps to types.
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 json.RawMessage    `json:"type"` // a type name or a list of them
+	Description          string             `json:"description"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	Items                *schema            `json:"items"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"` // a schema or false
+}
+
+// types returns the schema's type names and whether null is among them.
+func (s *schema) types() (names []string, nullable bool) {
+	var one string
+	if json.Unmarshal(s.Type, &one) == nil {
+		return []string{one}, false
+	}
+	var many []string
+	_ = json.Unmarshal(s.Type, &many)
+	for _, t := range many {
+		if t == "null" {
+			nullable = true
+		} else {
+			names = append(names, t)
+		}
+	}
+	return names, nullable
+}
+
+// valueSchema returns the additionalProperties schema of a map type, or nil.
+func (s *schema) valueSchema() *schema {
+	var v schema
+	if len(s.AdditionalProperties) == 0 || s.AdditionalProperties[0] != '{' || json.Unmarshal(s.AdditionalProperties, &v) != nil {
+		return nil
+	}
+	return &v
+}
+
+// refName returns the component name a $ref points at.
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// operation is one GET endpoint, flattened for the templates. pathParams
+// fill the {name} segments of path; params are query parameters.
+type operation struct {
+	id, path, summary, response string
+	pathParams, params          []param
+}
+
+type param struct {
+	name, description string
+	required          bool
+	schema            *schema
+}
+
+// generate renders both clients from spec JSON, keyed by output path.
+func generate(data []byte) (map[string]string, error) {
+	var doc spec
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+	var ops []operation
+	for path, item := range doc.Paths {
+		get := item.Get
+		if get == nil {
+			continue
+		}
+		op := operation{id: get.OperationID, path: path, summary: get.Summary}
+		if s := get.Responses["200"].Content["application/json"].Schema; s != nil && s.Ref != "" {
+			op.response = refName(s.Ref)
+		} else {
+			return nil, fmt.Errorf("%s: 200 response must reference a component schema", path)
+		}
+		for _, p := range get.Parameters {
+			pp := param{name: p.Name, description: p.Description, required: p.Required, schema: p.Schema}
+			switch p.In {
+			case "query":
+				op.params = append(op.params, pp)
+			case "path":
+				if !strings.Contains(path, "{"+p.Name+"}") {
+					return nil, fmt.Errorf("%s: path parameter %q has no {%s} segment", path, p.Name, p.Name)
+				}
+				pp.required = true
+				op.pathParams = append(op.pathParams, pp)
+			default:
+				return nil, fmt.Errorf("%s: only path and query parameters are supported, got %s %q", path, p.In, p.Name)
+			}
+		}
+		if n := strings.Count(path, "{"); n != len(op.pathParams) {
+			return nil, fmt.Errorf("%s: %d path segments but %d path parameters", path, n, len(op.pathParams))
+		}
+		ops = append(ops, op)
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].path < ops[j].path })
+
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	header := fmt.Sprintf("Code generated by go run ./cmd/genclients from the %s %s spec. DO NOT EDIT.", doc.Info.Title, doc.Info.Version)
+	return map[string]string{
+		tsFile: typescript(header, names, doc.Components.Schemas, ops),
+		pyFile: python(header, names, doc.Components.Schemas, ops),
+	}, nil
+}
+
+// expandPath replaces each {name} segment of op.path with expr(name).
+func (op operation) expandPath(expr func(name string) string) string {
+	path := op.path
+	for _, p := range op.pathParams {
+		path = strings.ReplaceAll(path, "{"+p.name+"}", expr(p.name))
+	}
+	return path
+}
+
+// sortedProps returns a schema's property names in order.
+func sortedProps(s *schema) []string {
+	props := make([]string, 0, len(s.Properties))
+	for p := range s.Properties {
+		props = append(props, p)
+	}
+	sort.Strings(props)
+	return props
+}
+
+func isRequired(s *schema, prop string) bool {
+	for _, r := range s.Required {
+		if r == prop {
+			return true
+		}
+	}
+	return false
+}
+
+// snake converts a lowerCamelCase operationId to snake_case.
+func snake(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
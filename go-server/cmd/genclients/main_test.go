@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-server/internal/openapi"
+)
+
+func TestGeneratedClientsUpToDate(t *testing.T) {
+	files, err := generate(openapi.JSON())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range files {
+		got, err := os.ReadFile(filepath.Join("..", "..", "..", "clients", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("clients/%s is stale; run `go run ./cmd/genclients` from go-server", name)
+		}
+	}
+}
+
+func TestGenerateCoversOperations(t *testing.T) {
+	files, err := generate(openapi.JSON())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, op := range openapi.Operations {
+		if !strings.Contains(files[tsFile], "  "+op.ID+"(") {
+			t.Errorf("TypeScript client has no %s method", op.ID)
+		}
+		if !strings.Contains(files[pyFile], "def "+snake(op.ID)+"(") {
+			t.Errorf("Python client has no %s method", snake(op.ID))
+		}
+	}
+}
+
+func TestGenerateRejectsUnsupportedParams(t *testing.T) {
+	for name, params := range map[string]string{
+		"header":      `[{"name":"id","in":"header"}]`,
+		"unused path": `[{"name":"other","in":"path"}]`,
+		"unfilled":    `[]`,
+		"cookie":      `[{"name":"id","in":"path"},{"name":"session","in":"cookie"}]`,
+	} {
+		spec := `{"paths":{"/x/{id}":{"get":{"operationId":"getX","parameters":` + params + `,
+			"responses":{"200":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/X"}}}}}}}},
+			"components":{"schemas":{"X":{"type":"object"}}}}`
+		if _, err := generate([]byte(spec)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestGeneratePathParams(t *testing.T) {
+	files, err := generate(openapi.JSON())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "return this.get(`/api/v1/models/${encodeURIComponent(String(model_id))}`);"; !strings.Contains(files[tsFile], want) {
+		t.Errorf("TypeScript client does not escape the path parameter; want %s", want)
+	}
+	if want := `def get_model(self, *, model_id: str) -> Model:`; !strings.Contains(files[pyFile], want) {
+		t.Errorf("Python client has no keyword-only model_id; want %s", want)
+	}
+	if want := `self._get(f"/api/v1/models/{urllib.parse.quote(str(model_id), safe='')}")`; !strings.Contains(files[pyFile], want) {
+		t.Errorf("Python client does not escape the path parameter; want %s", want)
+	}
+}
+
+func TestSnake(t *testing.T) {
+	for in, want := range map[string]string{"getRegistry": "get_registry", "getHealth": "get_health", "x": "x"} {
+		if got := snake(in); got != want {
+			t.Errorf("snake(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// pyType maps a schema to a Python type annotation.
+func pyType(s *schema) string {
+	if s == nil {
+		return "Any"
+	}
+	if s.Ref != "" {
+		return refName(s.Ref)
+	}
+	names, nullable := s.types()
+	t := "Any"
+	if len(names) == 1 {
+		switch names[0] {
+		case "string":
+			t = "str"
+		case "integer":
+			t = "int"
+		case "number":
+			t = "float"
+		case "boolean":
+			t = "bool"
+		case "array":
+			t = "list[" + pyType(s.Items) + "]"
+		case "object":
+			t = "dict[str, " + pyType(s.valueSchema()) + "]"
+		}
+	}
+	if nullable {
+		t += " | None"
+	}
+	return t
+}
+
+// python renders the Python client: a TypedDict per component schema and a
+// RegistryClient with one method per operation, using urllib.
+func python(header string, names []string, schemas map[string]*schema, ops []operation) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", header)
+	b.WriteString(`"""Client for the model registry REST API (standard library only)."""
+
+from __future__ import annotations
+
+import json
+import urllib.error
+import urllib.parse
+import urllib.request
+from typing import Any, NotRequired, TypedDict
+`)
+	for _, name := range names {
+		s := schemas[name]
+		fmt.Fprintf(&b, "\n\nclass %s(TypedDict):\n", name)
+		if s.Description != "" {
+			fmt.Fprintf(&b, "    %q\n\n", s.Description)
+		}
+		props := sortedProps(s)
+		if len(props) == 0 {
+			b.WriteString("    pass\n")
+		}
+		for _, p := range props {
+			t := pyType(s.Properties[p])
+			if !isRequired(s, p) {
+				t = "NotRequired[" + t + "]"
+			}
+			fmt.Fprintf(&b, "    %s: %s\n", p, t)
+			if d := s.Properties[p].Description; d != "" {
+				fmt.Fprintf(&b, "    %q\n", d)
+			}
+		}
+	}
+
+	b.WriteString(`
+
+class RegistryError(Exception):
+    """Raised for non-2xx responses."""
+
+    def __init__(self, status: int, body: str) -> None:
+        super().__init__(f"registry API returned {status}: {body}")
+        self.status = status
+        self.body = body
+
+
+class RegistryClient:
+    """Client for the model registry REST API."""
+
+    def __init__(self, base_url: str, timeout: float = 30.0) -> None:
+        self.base_url = base_url.rstrip("/")
+        self.timeout = timeout
+
+    def _get(self, path: str, query: dict[str, Any] | None = None) -> Any:
+        params = {k: v for k, v in (query or {}).items() if v is not None}
+        url = self.base_url + path
+        if params:
+            url += "?" + urllib.parse.urlencode(params)
+        try:
+            with urllib.request.urlopen(url, timeout=self.timeout) as res:
+                return json.load(res)
+        except urllib.error.HTTPError as err:
+            raise RegistryError(err.code, err.read().decode("utf-8", "replace")) from err
+`)
+	for _, op := range ops {
+		args := []string{"self"}
+		var query []string
+		if len(op.pathParams)+len(op.params) > 0 {
+			args = append(args, "*")
+		}
+		for _, p := range op.pathParams {
+			args = append(args, fmt.Sprintf("%s: %s", p.name, pyType(p.schema)))
+		}
+		for _, p := range op.params {
+			if p.required {
+				args = append(args, fmt.Sprintf("%s: %s", p.name, pyType(p.schema)))
+			} else {
+				args = append(args, fmt.Sprintf("%s: %s | None = None", p.name, pyType(p.schema)))
+			}
+			query = append(query, fmt.Sprintf("%q: %s", p.name, p.name))
+		}
+		fmt.Fprintf(&b, "\n    def %s(%s) -> %s:\n", snake(op.id), strings.Join(args, ", "), op.response)
+		fmt.Fprintf(&b, "        %q\n", op.summary)
+		path := fmt.Sprintf("%q", op.path)
+		if len(op.pathParams) > 0 {
+			path = "f" + fmt.Sprintf("%q", op.expandPath(func(name string) string {
+				return "{urllib.parse.quote(str(" + name + "), safe='')}"
+			}))
+		}
+		if len(query) == 0 {
+			fmt.Fprintf(&b, "        return self._get(%s)\n", path)
+		} else {
+			fmt.Fprintf(&b, "        return self._get(%s, {%s})\n", path, strings.Join(query, ", "))
+		}
+	}
+	return b.String()
+}
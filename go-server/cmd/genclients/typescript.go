@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tsType maps a schema to a TypeScript type.
+func tsType(s *schema) string {
+	if s == nil {
+		return "unknown"
+	}
+	if s.Ref != "" {
+		return refName(s.Ref)
+	}
+	names, nullable := s.types()
+	t := "unknown"
+	if len(names) == 1 {
+		switch names[0] {
+		case "string":
+			t = "string"
+		case "integer", "number":
+			t = "number"
+		case "boolean":
+			t = "boolean"
+		case "array":
+			t = tsType(s.Items) + "[]"
+		case "object":
+			t = "Record<string, " + tsType(s.valueSchema()) + ">"
+		}
+	}
+	if nullable {
+		t += " | null"
+	}
+	return t
+}
+
+// typescript renders the TypeScript client: an interface per component
+// schema and a RegistryClient with one method per operation, using fetch.
+func typescript(header string, names []string, schemas map[string]*schema, ops []operation) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s\n", header)
+	for _, name := range names {
+		s := schemas[name]
+		b.WriteString("\n")
+		if s.Description != "" {
+			fmt.Fprintf(&b, "/** %s */\n", s.Description)
+		}
+		fmt.Fprintf(&b, "export interface %s {\n", name)
+		for _, p := range sortedProps(s) {
+			ps := s.Properties[p]
+			if ps.Description != "" {
+				fmt.Fprintf(&b, "  /** %s */\n", ps.Description)
+			}
+			opt := "?"
+			if isRequired(s, p) {
+				opt = ""
+			}
+			fmt.Fprintf(&b, "  %s%s: %s;\n", p, opt, tsType(ps))
+		}
+		b.WriteString("}\n")
+	}
+
+	b.WriteString(`
+/** Thrown for non-2xx responses. */
+export class RegistryError extends Error {
+  constructor(
+    public readonly status: number,
+    public readonly body: string,
+  ) {
+    super(` + "`registry API returned ${status}: ${body}`" + `);
+    this.name = "RegistryError";
+  }
+}
+
+/** Client for the model registry REST API. */
+export class RegistryClient {
+  private readonly baseUrl: string;
+
+  constructor(
+    baseUrl: string,
+    private readonly fetchImpl: typeof fetch = globalThis.fetch,
+  ) {
+    this.baseUrl = baseUrl.replace(/\/+$/, "");
+  }
+
+  private async get<T>(path: string, query: Record<string, string | number | boolean | undefined> = {}): Promise<T> {
+    const params = new URLSearchParams();
+    for (const [key, value] of Object.entries(query)) {
+      if (value !== undefined) params.set(key, String(value));
+    }
+    const qs = params.toString();
+    const res = await this.fetchImpl(this.baseUrl + path + (qs ? "?" + qs : ""));
+    if (!res.ok) throw new RegistryError(res.status, await res.text());
+    return (await res.json()) as T;
+  }
+`)
+	for _, op := range ops {
+		fmt.Fprintf(&b, "\n  /** %s */\n", op.summary)
+		if len(op.pathParams)+len(op.params) == 0 {
+			fmt.Fprintf(&b, "  %s(): Promise<%s> {\n    return this.get(%q);\n  }\n", op.id, op.response, op.path)
+			continue
+		}
+		var fields []string
+		byDefault := " = {}" // params may be omitted when none are required
+		for _, p := range append(op.pathParams, op.params...) {
+			opt := "?"
+			if p.required {
+				opt, byDefault = "", ""
+			}
+			fields = append(fields, fmt.Sprintf("%s%s: %s", p.name, opt, tsType(p.schema)))
+		}
+		fmt.Fprintf(&b, "  %s(params: { %s }%s): Promise<%s> {\n", op.id, strings.Join(fields, "; "), byDefault, op.response)
+		if len(op.pathParams) == 0 {
+			fmt.Fprintf(&b, "    return this.get(%q, params);\n  }\n", op.path)
+			continue
+		}
+		names := make([]string, len(op.pathParams))
+		for i, p := range op.pathParams {
+			names[i] = p.name
+		}
+		path := op.expandPath(func(name string) string { return "${encodeURIComponent(String(" + name + "))}" })
+		if len(op.params) == 0 {
+			fmt.Fprintf(&b, "    const { %s } = params;\n    return this.get(`%s`);\n  }\n", strings.Join(names, ", "), path)
+		} else {
+			fmt.Fprintf(&b, "    const { %s, ...query } = params;\n    return this.get(`%s`, query);\n  }\n", strings.Join(names, ", "), path)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
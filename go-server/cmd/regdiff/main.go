@@ -0,0 +1,111 @@
+// Command regdiff compares two registry JSON snapshots and prints the added,
+// removed, and changed models as markdown.
+//
+// Usage:
+//
+//	regdiff [-exit-code] [-changelog FILE] OLD [NEW]
+//
+// OLD and NEW are file paths or http(s) URLs of registry snapshots in the
+// model://registry/all format. When NEW is omitted, OLD is compared against
+// the registry compiled into this binary. Exits 1 if the snapshots differ
+// and -exit-code is set, 2 on error.
+//
+// -changelog appends the differences to a changelog file (normally
+// internal/changelog/changelog.json), which backs the server's /api/changes
+// delta sync endpoint.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go-server/internal/changelog"
+	"go-server/internal/models"
+	"go-server/internal/tools"
+)
+
+func main() {
+	exitCode := flag.Bool("exit-code", false, "exit with status 1 when the snapshots differ")
+	changelogFile := flag.String("changelog", "", "append the differences to this changelog file")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: regdiff [-exit-code] [-changelog FILE] OLD [NEW]\n\nOLD and NEW are snapshot files or URLs; NEW defaults to the built-in registry.\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 1 || flag.NArg() > 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	oldLabel := flag.Arg(0)
+	old, err := loadSnapshot(ctx, client, oldLabel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "regdiff: %s: %v\n", oldLabel, err)
+		os.Exit(2)
+	}
+
+	newLabel, current := "built-in registry", models.Models
+	if flag.NArg() == 2 {
+		newLabel = flag.Arg(1)
+		if current, err = loadSnapshot(ctx, client, newLabel); err != nil {
+			fmt.Fprintf(os.Stderr, "regdiff: %s: %v\n", newLabel, err)
+			os.Exit(2)
+		}
+	}
+
+	d := tools.DiffRegistries(old, current)
+	fmt.Println(tools.FormatRegistryDiff(d, oldLabel, newLabel))
+	if *changelogFile != "" && !d.Empty() {
+		n, err := appendChangelog(*changelogFile, d, time.Now().UTC().Format("2006-01-02"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "regdiff: %s: %v\n", *changelogFile, err)
+			os.Exit(2)
+		}
+		fmt.Fprintf(os.Stderr, "regdiff: appended %d entries to %s\n", n, *changelogFile)
+	}
+	if *exitCode && !d.Empty() {
+		os.Exit(1)
+	}
+}
+
+// loadSnapshot reads a snapshot from a file path or an http(s) URL.
+func loadSnapshot(ctx context.Context, client *http.Client, src string) (map[string]models.Model, error) {
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		return tools.FetchSnapshot(ctx, client, src)
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return nil, err
+	}
+	return tools.ParseSnapshot(data)
+}
+
+// appendChangelog adds entries for d to the changelog file at path, dated
+// date, and returns how many were added.
+func appendChangelog(path string, d tools.RegistryDiff, date string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	log, err := changelog.Parse(data)
+	if err != nil {
+		return 0, err
+	}
+	before := len(log)
+	log = changelog.Append(log, d, date)
+	out, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	return len(log) - before, os.WriteFile(path, append(out, '\n'), 0o644)
+}
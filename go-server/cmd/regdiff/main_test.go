@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-server/internal/changelog"
+	"go-server/internal/tools"
+)
+
+func TestLoadSnapshot_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snap.json")
+	if err := os.WriteFile(path, []byte(`{"gpt-5":{"id":"gpt-5","provider":"OpenAI"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	snap, err := loadSnapshot(context.Background(), http.DefaultClient, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap["gpt-5"].Provider != "OpenAI" {
+		t.Errorf("unexpected snapshot: %+v", snap)
+	}
+}
+
+func TestLoadSnapshot_MissingFile(t *testing.T) {
+	if _, err := loadSnapshot(context.Background(), http.DefaultClient, filepath.Join(t.TempDir(), "nope.json")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestAppendChangelog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "changelog.json")
+	if err := os.WriteFile(path, []byte("[]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	d := tools.RegistryDiff{Added: []string{"gpt-5"}, Removed: []string{"gpt-3.5-turbo"}}
+	for i, want := range []int{2, 2} {
+		n, err := appendChangelog(path, d, "2026-03-01")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != want {
+			t.Errorf("run %d: appended %d entries, want %d", i, n, want)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log, err := changelog.Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log) != 4 || log[3].Seq != 4 || log[2].Kind != changelog.KindRemoved {
+		t.Errorf("unexpected changelog: %+v", log)
+	}
+}
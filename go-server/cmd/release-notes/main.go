@@ -0,0 +1,201 @@
+// Command release-notes renders registry changelog entries as markdown
+// release notes, grouped by provider and change type, for pasting into a
+// GitHub Release.
+//
+// Usage:
+//
+//	release-notes [-changelog FILE] [-from BOUND] [-to BOUND]
+//
+// A BOUND is either a date (YYYY-MM-DD) or a changelog cursor, the sequence
+// number /api/changes hands out. -from is exclusive for cursors, like
+// /api/changes?since=, and inclusive for dates; -to is inclusive for both.
+// Without bounds every entry is included. The changelog defaults to the one
+// compiled into this binary.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-server/internal/changelog"
+	"go-server/internal/models"
+)
+
+func main() {
+	file := flag.String("changelog", "", "read this changelog file instead of the built-in one")
+	from := flag.String("from", "", "start after this cursor, or on this date (YYYY-MM-DD)")
+	to := flag.String("to", "", "end at this cursor or date, inclusive")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: release-notes [-changelog FILE] [-from BOUND] [-to BOUND]\n\nA BOUND is a date (YYYY-MM-DD) or a changelog cursor.\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	entries := changelog.Entries
+	if *file != "" {
+		data, err := os.ReadFile(*file)
+		if err == nil {
+			entries, err = changelog.Parse(data)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "release-notes: %s: %v\n", *file, err)
+			os.Exit(2)
+		}
+	}
+
+	lo, err := parseBound(*from)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "release-notes: -from: %v\n", err)
+		os.Exit(2)
+	}
+	hi, err := parseBound(*to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "release-notes: -to: %v\n", err)
+		os.Exit(2)
+	}
+	fmt.Println(render(between(entries, lo, hi), models.Models))
+}
+
+// bound is one end of a range: a changelog cursor or a date. The zero value
+// is unbounded.
+type bound struct {
+	cursor int
+	date   string
+}
+
+var datePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+// parseBound reads a date or a non-negative cursor. "" is unbounded.
+func parseBound(s string) (bound, error) {
+	switch {
+	case s == "":
+		return bound{}, nil
+	case datePattern.MatchString(s):
+		return bound{date: s}, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return bound{}, fmt.Errorf("%q is neither a date (YYYY-MM-DD) nor a changelog cursor", s)
+	}
+	return bound{cursor: n}, nil
+}
+
+// between returns the entries after lo and up to hi.
+func between(entries []changelog.Entry, lo, hi bound) []changelog.Entry {
+	var out []changelog.Entry
+	for _, e := range entries {
+		if lo.date != "" && e.Date < lo.date || lo.date == "" && e.Seq <= lo.cursor {
+			continue
+		}
+		if hi.date != "" && e.Date > hi.date || hi.cursor > 0 && e.Seq > hi.cursor {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// kindHeadings orders and titles the change types within a provider.
+var kindHeadings = []struct {
+	kind  changelog.Kind
+	title string
+}{
+	{changelog.KindAdded, "Added"},
+	{changelog.KindChanged, "Changed"},
+	{changelog.KindRemoved, "Removed"},
+}
+
+// render formats entries as release notes. Providers come from ms; models
+// no longer in the registry are grouped under "Other". A model changed
+// several times is listed once with every field that changed, and changes to
+// a model added in the same range are folded into its addition.
+func render(entries []changelog.Entry, ms map[string]models.Model) string {
+	if len(entries) == 0 {
+		return "No registry changes in this range."
+	}
+	first, last := entries[0], entries[len(entries)-1]
+	lines := []string{
+		"## Model registry changes",
+		"",
+		fmt.Sprintf("Changelog entries %d-%d (%s to %s).", first.Seq, last.Seq, first.Date, last.Date),
+	}
+
+	added := make(map[string]bool)
+	for _, e := range entries {
+		if e.Kind == changelog.KindAdded {
+			added[e.ModelID] = true
+		}
+	}
+	// provider -> kind -> model ID -> changed fields
+	groups := make(map[string]map[changelog.Kind]map[string][]string)
+	for _, e := range entries {
+		if e.Kind == changelog.KindChanged && added[e.ModelID] {
+			continue
+		}
+		provider := "Other"
+		if m, ok := ms[e.ModelID]; ok {
+			provider = m.Provider
+		}
+		if groups[provider] == nil {
+			groups[provider] = make(map[changelog.Kind]map[string][]string)
+		}
+		if groups[provider][e.Kind] == nil {
+			groups[provider][e.Kind] = make(map[string][]string)
+		}
+		fields := groups[provider][e.Kind][e.ModelID]
+		for _, f := range e.Fields {
+			if !slices.Contains(fields, f) {
+				fields = append(fields, f)
+			}
+		}
+		groups[provider][e.Kind][e.ModelID] = fields
+	}
+
+	providers := make([]string, 0, len(groups))
+	for p := range groups {
+		if p != "Other" {
+			providers = append(providers, p)
+		}
+	}
+	sort.Strings(providers)
+	if groups["Other"] != nil {
+		providers = append(providers, "Other")
+	}
+
+	for _, p := range providers {
+		lines = append(lines, "", "### "+p)
+		for _, h := range kindHeadings {
+			ids := groups[p][h.kind]
+			if len(ids) == 0 {
+				continue
+			}
+			sorted := make([]string, 0, len(ids))
+			for id := range ids {
+				sorted = append(sorted, id)
+			}
+			sort.Strings(sorted)
+			lines = append(lines, "", "**"+h.title+"**", "")
+			for _, id := range sorted {
+				line := "- `" + id + "`"
+				switch {
+				case h.kind == changelog.KindChanged && len(ids[id]) > 0:
+					line += ": " + strings.Join(ids[id], ", ")
+				case h.kind == changelog.KindAdded && ms[id].DisplayName != "":
+					line += " (" + ms[id].DisplayName + ")"
+				}
+				lines = append(lines, line)
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
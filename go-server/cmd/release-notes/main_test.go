@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go-server/internal/changelog"
+	"go-server/internal/models"
+)
+
+var testLog = []changelog.Entry{
+	{Seq: 1, Date: "2026-03-01", Kind: changelog.KindAdded, ModelID: "gpt-5"},
+	{Seq: 2, Date: "2026-03-01", Kind: changelog.KindChanged, ModelID: "gpt-5", Fields: []string{"pricing_input"}},
+	{Seq: 3, Date: "2026-04-01", Kind: changelog.KindChanged, ModelID: "claude-sonnet-4-6", Fields: []string{"status"}},
+	{Seq: 4, Date: "2026-04-01", Kind: changelog.KindChanged, ModelID: "claude-sonnet-4-6", Fields: []string{"status", "notes"}},
+	{Seq: 5, Date: "2026-04-15", Kind: changelog.KindRemoved, ModelID: "retired-model"},
+}
+
+func TestParseBound(t *testing.T) {
+	if b, err := parseBound("2026-04-01"); err != nil || b.date != "2026-04-01" {
+		t.Errorf("date bound = %+v, %v", b, err)
+	}
+	if b, err := parseBound("12"); err != nil || b.cursor != 12 {
+		t.Errorf("cursor bound = %+v, %v", b, err)
+	}
+	for _, bad := range []string{"-1", "April", "2026-4-1"} {
+		if _, err := parseBound(bad); err == nil {
+			t.Errorf("parseBound(%q): expected error", bad)
+		}
+	}
+}
+
+func TestBetween(t *testing.T) {
+	seqs := func(es []changelog.Entry) []int {
+		var out []int
+		for _, e := range es {
+			out = append(out, e.Seq)
+		}
+		return out
+	}
+	for _, tt := range []struct {
+		lo, hi bound
+		want   string
+	}{
+		{bound{}, bound{}, "[1 2 3 4 5]"},
+		{bound{cursor: 2}, bound{cursor: 4}, "[3 4]"},
+		{bound{date: "2026-04-01"}, bound{}, "[3 4 5]"},
+		{bound{}, bound{date: "2026-04-01"}, "[1 2 3 4]"},
+	} {
+		if got := fmt.Sprint(seqs(between(testLog, tt.lo, tt.hi))); got != tt.want {
+			t.Errorf("between(%+v, %+v) = %s, want %s", tt.lo, tt.hi, got, tt.want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	ms := map[string]models.Model{
+		"gpt-5":             {ID: "gpt-5", DisplayName: "GPT-5", Provider: "OpenAI"},
+		"claude-sonnet-4-6": {ID: "claude-sonnet-4-6", Provider: "Anthropic"},
+	}
+	out := render(testLog, ms)
+	for _, want := range []string{
+		"Changelog entries 1-5 (2026-03-01 to 2026-04-15).",
+		"### Anthropic\n\n**Changed**\n\n- `claude-sonnet-4-6`: status, notes",
+		"### OpenAI\n\n**Added**\n\n- `gpt-5` (GPT-5)",
+		"### Other\n\n**Removed**\n\n- `retired-model`",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "pricing_input") {
+		t.Error("changes to a model added in the same range should fold into the addition")
+	}
+	if strings.Index(out, "### Anthropic") > strings.Index(out, "### OpenAI") || !strings.HasSuffix(out, "`retired-model`") {
+		t.Errorf("expected providers sorted with Other last:\n%s", out)
+	}
+	if got := render(nil, ms); got != "No registry changes in this range." {
+		t.Errorf("empty range = %q", got)
+	}
+}
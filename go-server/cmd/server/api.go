@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-server/internal/changelog"
+	"go-server/internal/middleware"
+	"go-server/internal/models"
+	"go-server/internal/openapi"
+	"go-server/internal/tools"
+)
+
+// registryHandler serves GET /api/registry: the full registry plus the
+// changelog cursor it corresponds to. Mirrors fetch this once, then poll
+// /api/changes with the cursor. The body is encoded once per registry
+// version and cursor, and served with an ETag and Range support, so a large
+// download can be resumed and an unchanged one revalidated with a 304.
+func registryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		middleware.Error(w, r, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, etag := registryExport.get()
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag)
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(body))
+}
+
+// registryExport caches the /api/registry body for the ETag it was built
+// for, rebuilding it when the registry data or changelog cursor moves.
+var registryExport exportCache
+
+type exportCache struct {
+	mu   sync.Mutex
+	etag string
+	body []byte
+}
+
+// get returns the body and ETag for the current registry. The registry is
+// loaded once, so a reload between computing the ETag and encoding the body
+// can't pair one version's tag with another's data.
+func (c *exportCache) get() ([]byte, string) {
+	reg := tools.BaseRegistry()
+	cursor := strconv.Itoa(changelog.Cursor())
+	etag := fmt.Sprintf(`"%s-%s"`, reg.Info().Version, cursor)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.etag != etag {
+		var b bytes.Buffer
+		_ = json.NewEncoder(&b).Encode(openapi.RegistryResponse{
+			Cursor: cursor,
+			Models: reg.Models(),
+		})
+		c.etag, c.body = etag, b.Bytes()
+	}
+	return c.body, c.etag
+}
+
+// changesHandler serves GET /api/changes?since=<cursor>: registry mutations
+// after the cursor, oldest first. Apply them in order — upsert "model" for
+// added and changed entries, delete model_id for removed ones — then store
+// the returned cursor. resync is true when the cursor is unknown to this
+// server; refetch /api/registry in that case.
+func changesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		middleware.Error(w, r, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	since, err := strconv.Atoi(r.URL.Query().Get("since"))
+	if err != nil || since < 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error":      "since must be a cursor from /api/registry or a previous /api/changes response",
+			"request_id": middleware.RequestIDFrom(r.Context()),
+		})
+		return
+	}
+
+	entries, ok := changelog.Since(since)
+	ms := tools.BaseRegistry().Models()
+	changes := make([]openapi.Change, len(entries))
+	for i, e := range entries {
+		changes[i] = openapi.Change{Entry: e}
+		if m, found := ms[e.ModelID]; found && e.Kind != changelog.KindRemoved {
+			changes[i].Model = &m
+		}
+	}
+	writeJSON(w, http.StatusOK, openapi.ChangesResponse{
+		Cursor:  strconv.Itoa(changelog.Cursor()),
+		Resync:  !ok,
+		Changes: changes,
+	})
+}
+
+// openAPIHandler serves GET /api/openapi.json, the spec the clients under
+// clients/ are generated from.
+func openAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		middleware.Error(w, r, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openapi.JSON())
+}
+
+// registerQueryAPI adds the /api/v1 query endpoints: plain JSON versions of
+// list_models, get_model_info, search_models, compare_models, and
+// recommend_model over the base registry, for dashboards and scripts without
+// an MCP client. Org policy applies as it does to the tools.
+func registerQueryAPI(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/models", listModelsHandler)
+	// model_id may contain slashes (openai/gpt-5.2, nvidia/...).
+	mux.HandleFunc("/api/v1/models/{model_id...}", getModelHandler)
+	mux.HandleFunc("/api/v1/search", searchHandler)
+	mux.HandleFunc("/api/v1/compare", compareHandler)
+	mux.HandleFunc("/api/v1/recommend", recommendHandler)
+}
+
+// listModelsHandler serves GET /api/v1/models?provider=&status=&capability=&sovereignty=.
+func listModelsHandler(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+	q := r.URL.Query()
+	if msg := tools.CheckCapability(q.Get("capability")); msg != "" {
+		apiError(w, r, http.StatusBadRequest, msg, nil)
+		return
+	}
+	ms := tools.BaseRegistry().FilterModels(q.Get("provider"), q.Get("status"), q.Get("capability"), q.Get("sovereignty"), tools.Exclusions{})
+	writeJSON(w, http.StatusOK, modelList(tools.SortedByProvider(ms)))
+}
+
+// getModelHandler serves GET /api/v1/models/{model_id}. IDs resolve like
+// get_model_info, aliases and partial matches included; the response's id is
+// the canonical one.
+func getModelHandler(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+	id := r.PathValue("model_id")
+	reg := tools.BaseRegistry()
+	m, ok := reg.FindModel(id)
+	if !ok {
+		apiError(w, r, http.StatusNotFound, fmt.Sprintf("model %q not found", id), reg.SuggestModels(id, 3))
+		return
+	}
+	writeJSON(w, http.StatusOK, m)
+}
+
+// searchHandler serves GET /api/v1/search?q=.
+func searchHandler(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		apiError(w, r, http.StatusBadRequest, "q is required", nil)
+		return
+	}
+	writeJSON(w, http.StatusOK, modelList(tools.BaseRegistry().SearchMatches(q)))
+}
+
+// compareHandler serves GET /api/v1/compare?ids=a,b[,...], up to 5 models in
+// the order given. Any unknown ID fails the whole request.
+func compareHandler(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+	var ids []string
+	for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) < 2 || len(ids) > 5 {
+		apiError(w, r, http.StatusBadRequest, "ids must list 2 to 5 comma-separated model IDs", nil)
+		return
+	}
+	reg := tools.BaseRegistry()
+	var found []models.Model
+	var missing, suggestions []string
+	for _, id := range ids {
+		m, ok := reg.FindModel(id)
+		if !ok {
+			missing = append(missing, id)
+			suggestions = append(suggestions, reg.SuggestModels(id, 3)...)
+			continue
+		}
+		found = append(found, m)
+	}
+	if len(missing) > 0 {
+		apiError(w, r, http.StatusNotFound, "models not found: "+strings.Join(missing, ", "), suggestions)
+		return
+	}
+	writeJSON(w, http.StatusOK, modelList(found))
+}
+
+// recommendHandler serves GET /api/v1/recommend?task=&budget=&sovereignty=&min_providers=.
+// Filters that leave no current models yield an empty recommendations list.
+func recommendHandler(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+	q := r.URL.Query()
+	task := strings.TrimSpace(q.Get("task"))
+	if task == "" {
+		apiError(w, r, http.StatusBadRequest, "task is required", nil)
+		return
+	}
+	minProviders := 0
+	if v := q.Get("min_providers"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			apiError(w, r, http.StatusBadRequest, "min_providers must be a non-negative integer", nil)
+			return
+		}
+		minProviders = n
+	}
+	recs, err := tools.BaseRegistry().Recommend(tools.RecommendQuery{
+		Task:         task,
+		Budget:       q.Get("budget"),
+		Sovereignty:  q.Get("sovereignty"),
+		MinProviders: minProviders,
+	})
+	if err != nil {
+		apiError(w, r, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	writeJSON(w, http.StatusOK, recommendResponse(task, q.Get("budget"), recs))
+}
+
+// recommendResponse ranks recs for /api/v1/recommend and recommend_model's
+// structured content.
+func recommendResponse(task, budget string, recs []tools.Recommendation) openapi.RecommendResponse {
+	resp := openapi.RecommendResponse{
+		Task:            task,
+		Budget:          tools.NormalizeBudget(budget),
+		Recommendations: make([]openapi.Recommendation, len(recs)),
+	}
+	for i, rec := range recs {
+		resp.Recommendations[i] = openapi.Recommendation{
+			Rank:      i + 1,
+			Score:     rec.Score,
+			ChurnRisk: rec.ChurnRisk(),
+			Model:     rec.Model,
+		}
+	}
+	return resp
+}
+
+// modelList wraps ms for a response; an empty result encodes as [], not null.
+func modelList(ms []models.Model) openapi.ModelList {
+	if ms == nil {
+		ms = []models.Model{}
+	}
+	return openapi.ModelList{Count: len(ms), Models: ms}
+}
+
+// allowGet rejects anything but GET with 405, reporting whether the request
+// may proceed.
+func allowGet(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method == http.MethodGet {
+		return true
+	}
+	middleware.Error(w, r, "method not allowed", http.StatusMethodNotAllowed)
+	return false
+}
+
+// apiError writes a JSON error body like changesHandler's, with "did you
+// mean" model IDs when there are any.
+func apiError(w http.ResponseWriter, r *http.Request, status int, msg string, suggestions []string) {
+	writeJSON(w, status, struct {
+		Error       string   `json:"error"`
+		Suggestions []string `json:"suggestions,omitempty"`
+		RequestID   string   `json:"request_id"`
+	}{msg, suggestions, middleware.RequestIDFrom(r.Context())})
+}
+
+// writeJSON writes v as a JSON response with the given status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"go-server/internal/models"
+	"go-server/internal/tools"
+)
+
+// toolExample is one sample call shown in a tool description.
+type toolExample struct {
+	args string // JSON arguments
+	note string // what the call returns
+}
+
+// toolGuide holds the examples, expected output shape, and common mistakes
+// appended to a tool's base description.
+type toolGuide struct {
+	examples []toolExample
+	returns  string
+	avoid    string
+}
+
+// guides caches buildToolGuides for the base registry version it was built
+// from, so a reload of the registry data refreshes the examples.
+var guides struct {
+	sync.Mutex
+	version string
+	byTool  map[string]toolGuide
+}
+
+func toolGuides() map[string]toolGuide {
+	version := tools.BaseRegistry().Info().Version
+	guides.Lock()
+	defer guides.Unlock()
+	if guides.byTool == nil || guides.version != version {
+		guides.version, guides.byTool = version, buildToolGuides()
+	}
+	return guides.byTool
+}
+
+// buildToolGuides generates usage examples from live registry data so sample
+// IDs are always real. Weaker client models otherwise call compare_models with
+// a single ID or pass provider names as model_id.
+func buildToolGuides() map[string]toolGuide {
+	newest := newestCurrentPerProvider()
+	var providers []string
+	for p := range newest {
+		providers = append(providers, p)
+	}
+	// Most recently updated providers first, for the most relevant examples.
+	sort.Slice(providers, func(i, j int) bool {
+		a, b := newest[providers[i]], newest[providers[j]]
+		if a.ReleaseDate != b.ReleaseDate {
+			return a.ReleaseDate > b.ReleaseDate
+		}
+		return providers[i] < providers[j]
+	})
+	first, second := newest[providers[0]], newest[providers[len(providers)-1]]
+	if len(providers) > 1 {
+		second = newest[providers[1]]
+	}
+	retired := latestRetiredModel()
+
+	return map[string]toolGuide{
+		"list_models": {
+			examples: []toolExample{
+				{fmt.Sprintf(`{"provider": %q}`, strings.ToLower(first.Provider)), "every " + first.Provider + " model"},
+				{`{"status": "current", "capability": "vision"}`, "current vision models"},
+				{`{"capability": "default"}`, "the model each provider recommends as its default, a conservative alternative to the newest release"},
+				{`{"status": "current", "maturity": "stable"}`, "current GA models only, so ★ marks the newest stable release rather than a preview"},
+				{`{"retiring_within_days": 90}`, "models the provider retires in the next 90 days, with their dates, soonest first"},
+			},
+			returns: "a markdown table (Model ID, name, provider, status, context, pricing) with the newest per provider marked ★",
+		},
+		"get_model_info": {
+			examples: []toolExample{
+				{fmt.Sprintf(`{"model_id": %q}`, first.ID), "full specs for " + first.DisplayName},
+			},
+			returns: "any do/don't guidance for agents followed by a field/value markdown table, including the OpenAI SDK base_url for the provider when known and, for OpenAI models, which APIs (chat/completions, responses, assistants) accept it",
+			avoid:   fmt.Sprintf("model_id must be a model ID such as %q, not a provider name — use list_models with provider for that", first.ID),
+		},
+		"get_usage_example": {
+			examples: []toolExample{
+				{fmt.Sprintf(`{"model_id": %q}`, first.ID), "a Python OpenAI SDK call to " + first.DisplayName + " with valid parameters"},
+			},
+			returns: "a Python code block followed by notes on parameter restrictions, reasoning settings, and the endpoint",
+			avoid:   "use get_model_info for pricing and limits; this tool only shows how to call the model",
+		},
+		"search_models": {
+			examples: []toolExample{
+				{`{"query": "reasoning"}`, "all reasoning models"},
+				{fmt.Sprintf(`{"query": "%s vision"}`, strings.ToLower(second.Provider)), "models matching both words"},
+			},
+			returns: "a markdown table of matches, with an Also Known As column when a model matched through an alias",
+		},
+		"recommend_model": {
+			examples: []toolExample{
+				{`{"task": "coding", "budget": "cheap"}`, "top 3 low-cost coding models"},
+				{`{"task": "long document analysis", "min_providers": 2}`, "top 3 spanning at least two providers"},
+				{`{"task": "math reasoning", "weights": {"recency": 0}}`, "top 3 without favoring the newest releases"},
+			},
+			returns: "a ranked list with model IDs, pricing, and reasons",
+		},
+		"check_model_status": {
+			examples: []toolExample{
+				{fmt.Sprintf(`{"model_id": %q}`, retired.ID), "its " + retired.Status + " status and the recommended replacement"},
+			},
+			returns: "one status line with any deprecation and retirement dates, a sunset warning when retirement is under 90 days away, and a replacement for legacy, deprecated, or retiring models",
+		},
+		"compare_models": {
+			examples: []toolExample{
+				{fmt.Sprintf(`{"model_ids": [%q, %q]}`, first.ID, second.ID), "a side-by-side table"},
+				{fmt.Sprintf(`{"model_ids": [%q, %q], "format": "html", "chart": true}`, first.ID, second.ID), "an HTML table plus an SVG price chart, for a decision record"},
+			},
+			returns: "a markdown table with one column per model, including the output/input price ratio",
+			avoid:   "model_ids needs 2-5 IDs in an array; for a single model use get_model_info",
+		},
+		"monthly_cost_projection": {
+			examples: []toolExample{
+				{fmt.Sprintf(`{"model_ids": [%q, %q], "requests_per_day": 5000, "input_tokens": 1500, "output_tokens": 400}`, first.ID, second.ID), "monthly spend for each and the savings of the cheaper one"},
+			},
+			returns: "a markdown table, cheapest first, with input, output, and total monthly cost and savings vs the most expensive model, plus warnings for models whose spend is mostly output tokens and for prompts past a long-context pricing breakpoint",
+			avoid:   "token counts are per request, not per day or month",
+		},
+		"estimate_cost": {
+			examples: []toolExample{
+				{fmt.Sprintf(`{"model_id": %q, "input_tokens": 2000, "output_tokens": 500}`, first.ID), "the cost of one such request on " + first.ID},
+				{fmt.Sprintf(`{"model_ids": [%q, %q], "input_tokens": 2000, "output_tokens": 500, "requests": 10000}`, first.ID, second.ID), "the cost of 10,000 requests on each, cheapest first"},
+				{fmt.Sprintf(`{"model_id": %q, "input_tokens": 20000, "output_tokens": 500, "requests": 1000, "cache_hit_rate": 0.9}`, first.ID), "the cost when 90% of a long, reused prompt is served from the prompt cache"},
+			},
+			returns: "a markdown table of input, output, total, per-request, and batch API cost per model, with how much more each costs than the cheapest; with cache_hit_rate, cache hits bill at each model's cached-input rate",
+			avoid:   "token counts are per request; for spend from a daily volume use monthly_cost_projection",
+		},
+		"fastest_models": {
+			examples: []toolExample{
+				{`{}`, "the 10 highest-throughput models"},
+				{`{"metric": "ttft", "limit": 3}`, "the 3 models with the lowest time to first token"},
+			},
+			returns: "a ranked markdown table with tokens/sec, TTFT, and the benchmark source",
+		},
+		"get_coverage": {
+			examples: []toolExample{
+				{`{}`, "tracked vs scraped model counts for every provider"},
+				{fmt.Sprintf(`{"provider": %q}`, strings.ToLower(first.Provider)), "coverage for " + first.Provider + " only"},
+			},
+			returns: "a markdown table of tracked, scraped, and coverage percentage per provider",
+		},
+		"get_similar_models": {
+			examples: []toolExample{
+				{fmt.Sprintf(`{"model_id": %q}`, first.ID), "the 5 current models closest to " + first.ID},
+				{fmt.Sprintf(`{"model_id": %q, "other_providers_only": true}`, first.ID), "alternatives to " + first.ID + " from other providers"},
+			},
+			returns: "a markdown table ranked by similarity with price, context, capability, and release-date deltas against the given model",
+			avoid:   "use compare_models when you already know which models to weigh against each other",
+		},
+		"get_task_shortlist": {
+			examples: []toolExample{
+				{`{}`, "every curated task with its top pick"},
+				{fmt.Sprintf(`{"task": %q}`, models.TaskShortlists[0].Task), "the curated " + strings.ToLower(models.TaskShortlists[0].Name) + " shortlist"},
+			},
+			returns: "a ranked markdown table of curated picks with role, price, context, and why each was chosen",
+			avoid:   "use recommend_model for tasks without a shortlist or to rank against your own budget and weights",
+		},
+		"get_registry_version": {
+			examples: []toolExample{
+				{`{}`, "the server build and the hash, size, and changelog cursor of the registry it serves"},
+			},
+			returns: "a markdown table of server version, commit, build date, registry hash, model and provider counts, and changelog cursor",
+			avoid:   "use /api/changes with the cursor to see what changed, not just whether it did",
+		},
+		"deprecation_impact": {
+			examples: []toolExample{
+				{fmt.Sprintf(`{"model_id": %q}`, retired.ID), "every alias and platform ID for " + retired.ID + " plus a grep command to find them"},
+			},
+			returns: "a markdown table of names that resolve to the model, a grep command, and a migration checklist",
+			avoid:   "use check_model_status to learn whether a model is retired; this tool scopes where it is used",
+		},
+		"canonicalize_id": {
+			examples: []toolExample{
+				{`{"model_id": "us.anthropic.claude-opus-4-6-v1:0"}`, "a Bedrock inference profile ID mapped to its registry ID"},
+				{`{"model_id": "openai/gpt-5.2:free"}`, "an OpenRouter variant mapped to its registry ID, with the OpenRouter and LiteLLM forms"},
+			},
+			returns: "the canonical ID, each rewrite rule that fired, and the model's IDs on router platforms",
+			avoid:   "use get_model_info for specs; this tool never guesses, so typos come back unresolved with suggestions",
+		},
+		"list_providers": {
+			examples: []toolExample{
+				{`{}`, "every provider with model counts, cheapest and flagship models, capability coverage, last-verified date, recent churn, API base URL, and auth env var"},
+			},
+			returns: "three markdown tables with one row per provider: an overview, capability coverage, and API access",
+		},
+		"get_provider_info": {
+			examples: []toolExample{
+				{fmt.Sprintf(`{"provider": %q}`, strings.ToLower(first.Provider)), first.Provider + "'s endpoints, docs, auth env var, status page, and current models"},
+				{`{"provider": "kimi"}`, "aliases resolve, so this describes Moonshot"},
+			},
+			returns: "a markdown table of provider metadata followed by its current model IDs",
+			avoid:   "use check_provider_status for live incidents; this tool reports static metadata",
+		},
+		"check_provider_status": {
+			examples: []toolExample{
+				{`{}`, "status of every provider with a status feed"},
+				{`{"provider": "openai"}`, "OpenAI status and active incidents"},
+			},
+			returns: "a status table followed by any active incidents",
+		},
+		"diff_registries": {
+			examples: []toolExample{
+				{`{"snapshot_url": "https://example.com/registry.json"}`, "changes from that snapshot to the live registry"},
+			},
+			returns: "added, removed, and changed models with per-field old/new values",
+		},
+	}
+}
+
+// latestRetiredModel returns the most recently released legacy or deprecated
+// model, for check_model_status examples.
+func latestRetiredModel() models.Model {
+	var best models.Model
+	for _, m := range tools.BaseRegistry().Models() {
+		if m.Status == "current" {
+			continue
+		}
+		if best.ID == "" || m.ReleaseDate > best.ReleaseDate ||
+			(m.ReleaseDate == best.ReleaseDate && m.ID < best.ID) {
+			best = m
+		}
+	}
+	return best
+}
+
+// describe appends generated examples and the expected output shape to a
+// tool's base description.
+func describe(tool, base string) string {
+	g, ok := toolGuides()[tool]
+	if !ok {
+		return base
+	}
+	var b strings.Builder
+	b.WriteString(base)
+	b.WriteString("\n\nExamples:")
+	for _, ex := range g.examples {
+		fmt.Fprintf(&b, "\n- %s → %s", ex.args, ex.note)
+	}
+	fmt.Fprintf(&b, "\n\nReturns %s.", g.returns)
+	if g.avoid != "" {
+		fmt.Fprintf(&b, "\n\nNote: %s.", g.avoid)
+	}
+	return b.String()
+}
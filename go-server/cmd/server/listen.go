@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+// listenFDsStart is the first file descriptor systemd passes to a
+// socket-activated service (SD_LISTEN_FDS_START).
+const listenFDsStart = 3
+
+// listen opens the HTTP listener for the configured mode:
+//
+//   - tcp: a plain socket on addr.
+//   - reuseport: a socket on addr with SO_REUSEPORT, so during an upgrade the
+//     new process binds the same port and starts accepting while the old one
+//     drains its SSE streams.
+//   - systemd: the socket passed by a systemd .socket unit. systemd keeps it
+//     open across service restarts, so connections queue instead of being
+//     refused while the new process starts.
+func listen(mode, addr string) (net.Listener, error) {
+	switch mode {
+	case "reuseport":
+		lc := net.ListenConfig{Control: reusePort}
+		return lc.Listen(context.Background(), "tcp", addr)
+	case "systemd":
+		return systemdListener(os.Getenv, os.Getpid())
+	default:
+		return net.Listen("tcp", addr)
+	}
+}
+
+// systemdListener returns the first socket passed by systemd socket
+// activation. It checks LISTEN_PID so a socket meant for a parent process
+// isn't taken, and clears the LISTEN_* variables so children don't inherit
+// them.
+func systemdListener(getenv func(string) string, pid int) (net.Listener, error) {
+	if p, err := strconv.Atoi(getenv("LISTEN_PID")); err != nil || p != pid {
+		return nil, errors.New("listener systemd: no socket passed to this process (LISTEN_PID unset or for another process); start the server from a .socket unit")
+	}
+	n, err := strconv.Atoi(getenv("LISTEN_FDS"))
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("listener systemd: LISTEN_FDS=%q, want at least 1", getenv("LISTEN_FDS"))
+	}
+	for _, k := range []string{"LISTEN_PID", "LISTEN_FDS", "LISTEN_FDNAMES"} {
+		os.Unsetenv(k)
+	}
+	f := os.NewFile(listenFDsStart, "systemd-socket")
+	defer f.Close()
+	ln, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("listener systemd: fd %d: %w", listenFDsStart, err)
+	}
+	return ln, nil
+}
@@ -1,25 +1,39 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
+	"go-server/internal/buildinfo"
+	"go-server/internal/changelog"
+	"go-server/internal/config"
+	"go-server/internal/middleware"
 	"go-server/internal/models"
+	"go-server/internal/openapi"
+	"go-server/internal/resources"
+	"go-server/internal/tools"
 )
 
 func TestNewServerReturnsDistinctInstances(t *testing.T) {
-	s1 := newServer()
-	s2 := newServer()
-	s3 := newServer()
+	s1 := newServer(tools.BaseRegistry())
+	s2 := newServer(tools.BaseRegistry())
+	s3 := newServer(tools.BaseRegistry())
 
 	if s1 == s2 {
 		t.Fatal("s1 and s2 are the same instance")
@@ -32,6 +46,42 @@ func TestNewServerReturnsDistinctInstances(t *testing.T) {
 	}
 }
 
+func TestServerInstructionsGeneratedFromData(t *testing.T) {
+	instr := serverInstructions()
+
+	providers := make(map[string]bool)
+	for _, m := range models.Models {
+		providers[m.Provider] = true
+	}
+	for p := range providers {
+		if !strings.Contains(instr, p+" (newest: ") {
+			t.Errorf("instructions missing provider %q", p)
+		}
+	}
+	for p, m := range newestCurrentPerProvider() {
+		if m.Status != "current" {
+			t.Errorf("newest model for %s is %s, not current", p, m.Status)
+		}
+		if !strings.Contains(instr, m.ID) {
+			t.Errorf("instructions missing newest %s model %q", p, m.ID)
+		}
+	}
+	if !strings.Contains(instr, fmt.Sprintf("%d models", len(models.Models))) {
+		t.Error("instructions should state the registry model count")
+	}
+
+	newer, older, ok := newestVsOlderExample(newestCurrentPerProvider())
+	if !ok {
+		t.Fatal("expected a newest-vs-older example")
+	}
+	if models.Models[newer].ReleaseDate <= models.Models[older].ReleaseDate {
+		t.Errorf("example %s should be newer than %s", newer, older)
+	}
+	if !strings.Contains(instr, "use "+newer+" (newest) NOT "+older) {
+		t.Error("instructions should include the generated newest-vs-older example")
+	}
+}
+
 // TestConcurrentSSESessions verifies that multiple concurrent SSE connections
 // each get their own server instance and can complete the full MCP lifecycle
 // (initialize → notifications/initialized → tools/call) without interfering
@@ -111,7 +161,7 @@ func TestConcurrentSSESessions(t *testing.T) {
 
 // newTestMux builds the same mux as serveHTTP: /health (unprotected) + /sse + /mcp.
 func newTestMux() http.Handler {
-	getServer := func(_ *http.Request) *mcp.Server { return newServer() }
+	getServer := func(_ *http.Request) *mcp.Server { return newServer(tools.BaseRegistry()) }
 	sseHandler := mcp.NewSSEHandler(getServer, nil)
 
 	mcpMux := http.NewServeMux()
@@ -120,14 +170,7 @@ func newTestMux() http.Handler {
 	mcpMux.Handle("/mcp", mcp.NewStreamableHTTPHandler(getServer, nil))
 
 	topMux := http.NewServeMux()
-	topMux.HandleFunc("/health", func(w http.ResponseWriter, _ *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(map[string]any{
-			"status":  "ok",
-			"models":  len(models.Models),
-			"version": "1.3.0",
-		})
-	})
+	topMux.Handle("/health", healthHandler("both", 0))
 	topMux.Handle("/", mcpMux)
 	return topMux
 }
@@ -164,8 +207,12 @@ func TestHealthEndpoint(t *testing.T) {
 	if health["status"] != "ok" {
 		t.Errorf("expected status 'ok', got %v", health["status"])
 	}
-	if health["version"] != "1.3.0" {
-		t.Errorf("expected version '1.3.0', got %v", health["version"])
+	build := buildinfo.Get()
+	if health["version"] != build.Version || health["version"] == "" {
+		t.Errorf("expected version %q, got %v", build.Version, health["version"])
+	}
+	if got, _ := health["commit"].(string); got != build.Commit {
+		t.Errorf("expected commit %q, got %q", build.Commit, got)
 	}
 	if int(health["models"].(float64)) != len(models.Models) {
 		t.Errorf("expected models %d, got %v", len(models.Models), health["models"])
@@ -207,7 +254,7 @@ func TestHealthDoesNotAffectSSE(t *testing.T) {
 }
 
 func TestCORSPreflight(t *testing.T) {
-	srv := httptest.NewServer(corsMiddleware(newTestMux()))
+	srv := httptest.NewServer(corsMiddleware(newTestMux(), config.CORS{}))
 	defer srv.Close()
 
 	req, err := http.NewRequest(http.MethodOptions, srv.URL+"/mcp", nil)
@@ -231,6 +278,79 @@ func TestCORSPreflight(t *testing.T) {
 	}
 }
 
+func TestCORSAllowedOrigins(t *testing.T) {
+	srv := httptest.NewServer(corsMiddleware(newTestMux(), config.CORS{AllowedOrigins: []string{"https://app.example.com"}}))
+	defer srv.Close()
+
+	for origin, want := range map[string]string{
+		"https://app.example.com":  "https://app.example.com",
+		"https://evil.example.com": "",
+	} {
+		req, err := http.NewRequest(http.MethodOptions, srv.URL+"/mcp", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Origin", origin)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if got := resp.Header.Get("Access-Control-Allow-Origin"); got != want {
+			t.Errorf("origin %s: expected ACAO %q, got %q", origin, want, got)
+		}
+	}
+}
+
+func TestCORSPrivateNetworkAndCredentials(t *testing.T) {
+	srv := httptest.NewServer(corsMiddleware(newTestMux(), config.CORS{
+		AllowedOrigins: []string{"https://app.example.com"},
+		PrivateNetwork: true,
+		Origins: map[string]config.CORSOrigin{
+			"https://ide.example.dev": {AllowCredentials: true},
+		},
+	}))
+	defer srv.Close()
+
+	preflight := func(origin string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodOptions, srv.URL+"/mcp", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		req.Header.Set("Access-Control-Request-Private-Network", "true")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	resp := preflight("https://app.example.com")
+	if resp.Header.Get("Access-Control-Allow-Private-Network") != "true" {
+		t.Error("expected private network access to be granted")
+	}
+	if resp.Header.Get("Access-Control-Allow-Credentials") != "" {
+		t.Error("credentials were not enabled for app.example.com")
+	}
+
+	resp = preflight("https://ide.example.dev")
+	if resp.Header.Get("Access-Control-Allow-Origin") != "https://ide.example.dev" {
+		t.Error("origins entries should be allowed")
+	}
+	if resp.Header.Get("Access-Control-Allow-Credentials") != "true" || resp.Header.Get("Access-Control-Allow-Private-Network") != "true" {
+		t.Errorf("expected credentials and private network headers, got %v", resp.Header)
+	}
+
+	resp = preflight("https://evil.example.com")
+	if resp.Header.Get("Access-Control-Allow-Private-Network") != "" || resp.Header.Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("disallowed origin got CORS headers: %v", resp.Header)
+	}
+}
+
 func TestStreamableHTTPEndpoint(t *testing.T) {
 	srv := httptest.NewServer(newTestMux())
 	defer srv.Close()
@@ -254,3 +374,1135 @@ func TestStreamableHTTPEndpoint(t *testing.T) {
 		t.Errorf("expected 200 from /mcp, got %d", resp.StatusCode)
 	}
 }
+
+func TestRegistryAndChangesAPI(t *testing.T) {
+	saved := changelog.Entries
+	t.Cleanup(func() { changelog.Entries = saved })
+	changelog.Entries = []changelog.Entry{
+		{Seq: 1, Date: "2026-03-01", Kind: changelog.KindAdded, ModelID: "gpt-4.1"},
+		{Seq: 2, Date: "2026-03-02", Kind: changelog.KindRemoved, ModelID: "retired-model"},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/registry", registryHandler)
+	mux.HandleFunc("/api/changes", changesHandler)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	var reg struct {
+		Cursor string                  `json:"cursor"`
+		Models map[string]models.Model `json:"models"`
+	}
+	getJSON(t, srv.URL+"/api/registry", http.StatusOK, &reg)
+	if reg.Cursor != "2" || len(reg.Models) != len(models.Models) {
+		t.Errorf("registry: cursor %q with %d models", reg.Cursor, len(reg.Models))
+	}
+
+	var changes struct {
+		Cursor  string `json:"cursor"`
+		Resync  bool   `json:"resync"`
+		Changes []struct {
+			Seq     int           `json:"seq"`
+			Kind    string        `json:"kind"`
+			ModelID string        `json:"model_id"`
+			Model   *models.Model `json:"model"`
+		} `json:"changes"`
+	}
+	getJSON(t, srv.URL+"/api/changes?since=0", http.StatusOK, &changes)
+	if changes.Cursor != "2" || changes.Resync || len(changes.Changes) != 2 {
+		t.Fatalf("unexpected changes response: %+v", changes)
+	}
+	if c := changes.Changes[0]; c.Kind != "added" || c.Model == nil || c.Model.ID != "gpt-4.1" {
+		t.Errorf("added entry should carry the current model, got %+v", c)
+	}
+	if c := changes.Changes[1]; c.Kind != "removed" || c.Model != nil {
+		t.Errorf("removed entry should not carry a model, got %+v", c)
+	}
+
+	changes.Changes = nil
+	getJSON(t, srv.URL+"/api/changes?since=2", http.StatusOK, &changes)
+	if len(changes.Changes) != 0 || changes.Resync {
+		t.Errorf("expected no changes at the current cursor, got %+v", changes)
+	}
+	getJSON(t, srv.URL+"/api/changes?since=9", http.StatusOK, &changes)
+	if !changes.Resync {
+		t.Error("expected resync for a cursor ahead of the log")
+	}
+	getJSON(t, srv.URL+"/api/changes", http.StatusBadRequest, nil)
+}
+
+// getJSON fetches url, checks the status code, and decodes the body into v.
+
+func TestRegistryAPI_RangeAndETag(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(registryHandler))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	full, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || etag == "" || resp.Header.Get("Accept-Ranges") != "bytes" {
+		t.Fatalf("GET: status %d, ETag %q, Accept-Ranges %q", resp.StatusCode, etag, resp.Header.Get("Accept-Ranges"))
+	}
+
+	do := func(method string, header map[string]string) (*http.Response, []byte) {
+		t.Helper()
+		req, _ := http.NewRequest(method, srv.URL, nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, body
+	}
+
+	resp, body := do(http.MethodGet, map[string]string{"Range": "bytes=100-199"})
+	if resp.StatusCode != http.StatusPartialContent || string(body) != string(full[100:200]) {
+		t.Errorf("Range: status %d, got %d bytes", resp.StatusCode, len(body))
+	}
+	resp, body = do(http.MethodGet, map[string]string{"Range": "bytes=100-", "If-Range": etag})
+	if resp.StatusCode != http.StatusPartialContent || string(body) != string(full[100:]) {
+		t.Errorf("resume with If-Range: status %d, got %d bytes", resp.StatusCode, len(body))
+	}
+	resp, _ = do(http.MethodGet, map[string]string{"If-None-Match": etag})
+	if resp.StatusCode != http.StatusNotModified {
+		t.Errorf("If-None-Match: status %d, want 304", resp.StatusCode)
+	}
+	resp, body = do(http.MethodHead, nil)
+	if resp.StatusCode != http.StatusOK || len(body) != 0 || resp.ContentLength != int64(len(full)) {
+		t.Errorf("HEAD: status %d, Content-Length %d, body %d bytes", resp.StatusCode, resp.ContentLength, len(body))
+	}
+}
+func getJSON(t *testing.T, url string, wantStatus int, v any) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != wantStatus {
+		t.Fatalf("GET %s: status %d, want %d", url, resp.StatusCode, wantStatus)
+	}
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("GET %s: %v", url, err)
+		}
+	}
+}
+
+func TestStreamableHTTPStatelessJSON(t *testing.T) {
+	opts := streamableOptions(true)
+	if opts == nil || !opts.Stateless || !opts.JSONResponse {
+		t.Fatalf("expected stateless JSON options, got %+v", opts)
+	}
+
+	getServer := func(_ *http.Request) *mcp.Server { return newServer(tools.BaseRegistry()) }
+	srv := httptest.NewServer(mcp.NewStreamableHTTPHandler(getServer, opts))
+	defer srv.Close()
+
+	// A one-shot tools/call with no initialize and no session ID.
+	body := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_model_info","arguments":{"model_id":"gpt-5.2"}}}`)
+	req, err := http.NewRequest(http.MethodPost, srv.URL, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("expected application/json response, got %q", ct)
+	}
+	if sid := resp.Header.Get("Mcp-Session-Id"); sid != "" {
+		t.Errorf("stateless mode should not issue a session ID, got %q", sid)
+	}
+	data, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(data), "gpt-5.2") {
+		t.Errorf("expected tool result for gpt-5.2, got %s", data)
+	}
+}
+
+func TestStreamableOptionsDefaultStateful(t *testing.T) {
+	opts := streamableOptions(false)
+	if opts.Stateless || opts.JSONResponse || opts.SessionTimeout != serverConfig.Sessions.IdleTimeout {
+		t.Errorf("expected stateful options with the idle timeout, got %+v", opts)
+	}
+}
+
+func TestToolDescriptionsIncludeExamples(t *testing.T) {
+	ctx := context.Background()
+	ct, st := mcp.NewInMemoryTransports()
+	if _, err := newServer(tools.BaseRegistry()).Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "1.0"}, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	res, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tool := range res.Tools {
+		if !strings.Contains(tool.Description, "\n\nExamples:\n- {") || !strings.Contains(tool.Description, "Returns ") {
+			t.Errorf("%s: description lacks generated examples:\n%s", tool.Name, tool.Description)
+		}
+	}
+
+	// Example IDs must be real registry entries, and compare_models must
+	// show more than one ID.
+	g := toolGuides()["compare_models"]
+	var ids []string
+	if err := json.Unmarshal([]byte(g.examples[0].args), &struct {
+		ModelIDs *[]string `json:"model_ids"`
+	}{&ids}); err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) < 2 || ids[0] == ids[1] {
+		t.Errorf("compare_models example needs two distinct IDs, got %v", ids)
+	}
+	for _, id := range append(ids, latestRetiredModel().ID) {
+		if _, ok := models.Models[id]; !ok {
+			t.Errorf("example ID %q is not in the registry", id)
+		}
+	}
+	if latestRetiredModel().Status == "current" {
+		t.Error("check_model_status example should use a retired model")
+	}
+}
+
+func TestTenantEndpoint(t *testing.T) {
+	dir := t.TempDir()
+	overlay := filepath.Join(dir, "acme.json")
+	policy := filepath.Join(dir, "acme-policy.json")
+	if err := os.WriteFile(overlay, []byte(`[
+		{"id":"acme-router-v2","display_name":"Acme Router v2","provider":"Acme","status":"current"},
+		{"id":"acme-finetune-7","display_name":"Acme Fine-tune 7","provider":"Acme","status":"current","internal":true}
+	]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(policy, []byte(`{"banned_models":["gpt-5"]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ACME_MCP_KEYS", "k1,k2")
+	tenants, err := loadTenants(map[string]config.Tenant{"acme": {OverlayFile: overlay, PolicyFile: policy, APIKeysEnv: "ACME_MCP_KEYS"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/mcp/{tenant}", tenantHandler(tenants, true))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	callAs := func(auth, path, tool, modelID string) (int, string) {
+		t.Helper()
+		body := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":%q,"arguments":{"model_id":%q}}}`, tool, modelID)
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+	call := func(path, tool, modelID string) (int, string) {
+		t.Helper()
+		return callAs("", path, tool, modelID)
+	}
+
+	if code, body := call("/mcp/acme", "get_model_info", "acme-router-v2"); code != http.StatusOK || !strings.Contains(body, "Acme Router v2") {
+		t.Errorf("tenant should serve its overlay model, got %d: %s", code, body)
+	}
+	if _, body := call("/mcp/acme", "check_model_status", "gpt-5"); !strings.Contains(body, "Blocked by org policy") {
+		t.Errorf("tenant policy should block gpt-5, got %s", body)
+	}
+	if code, _ := call("/mcp/unknown", "get_model_info", "gpt-5"); code != http.StatusNotFound {
+		t.Errorf("unknown tenant should 404, got %d", code)
+	}
+
+	// Internal overlay models are only served with one of the tenant's keys.
+	if _, body := call("/mcp/acme", "get_model_info", "acme-finetune-7"); strings.Contains(body, "Acme Fine-tune 7") {
+		t.Errorf("internal model served without a key: %s", body)
+	}
+	if code, body := callAs("Bearer k2", "/mcp/acme", "get_model_info", "acme-finetune-7"); code != http.StatusOK || !strings.Contains(body, "Acme Fine-tune 7") {
+		t.Errorf("internal model should be served with a key, got %d: %s", code, body)
+	}
+	if code, _ := callAs("Bearer wrong", "/mcp/acme", "get_model_info", "acme-router-v2"); code != http.StatusUnauthorized {
+		t.Errorf("a wrong key should get 401, got %d", code)
+	}
+}
+
+func TestInternalIDs(t *testing.T) {
+	for _, data := range []string{
+		`{"a":{"provider":"Acme","internal":true},"b":{"provider":"Acme"}}`,
+		`[{"id":"a","internal":true},{"id":"b","internal":false}]`,
+	} {
+		ids, err := internalIDs([]byte(data))
+		if err != nil || len(ids) != 1 || !ids["a"] {
+			t.Errorf("internalIDs(%s) = %v, %v; want only a", data, ids, err)
+		}
+	}
+}
+
+func TestToolMetricsRecorded(t *testing.T) {
+	ctx := context.Background()
+	ct, st := mcp.NewInMemoryTransports()
+	if _, err := newServer(tools.BaseRegistry()).Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "1.0"}, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	if _, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "check_model_status", Arguments: map[string]any{"model_id": "gpt-5"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "no_such_tool"}); err == nil {
+		t.Fatal("expected error for unknown tool")
+	}
+
+	rec := httptest.NewRecorder()
+	toolMetrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	out := rec.Body.String()
+	if !strings.Contains(out, `mcp_tool_call_duration_seconds_count{tool="check_model_status"}`) {
+		t.Errorf("expected check_model_status series:\n%s", out)
+	}
+	if strings.Contains(out, "no_such_tool") {
+		t.Error("unknown tools must not create series")
+	}
+}
+
+func TestCountSessions(t *testing.T) {
+	ctx := context.Background()
+	var active atomic.Int64
+	ct, st := mcp.NewInMemoryTransports()
+	if _, err := countSessions(newServer(tools.BaseRegistry()), &active).Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "1.0"}, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitFor := func(want int64) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for active.Load() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("active sessions = %d, want %d", active.Load(), want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	waitFor(1)
+	session.Close()
+	waitFor(0)
+
+	// A client that sends its initialized notification twice is one session.
+	ct, st = mcp.NewInMemoryTransports()
+	if _, err := countSessions(newServer(tools.BaseRegistry()), &active).Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "1.0"}, nil)
+	client.AddSendingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method == "notifications/initialized" {
+				if _, err := next(ctx, method, req); err != nil {
+					return nil, err
+				}
+			}
+			return next(ctx, method, req)
+		}
+	})
+	session, err = client.Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A call round-trips after both notifications, so both have been handled.
+	if _, err := session.ListTools(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(1)
+	if n := active.Load(); n != 1 {
+		t.Errorf("repeated initialized notifications: active sessions = %d, want 1", n)
+	}
+	session.Close()
+	waitFor(0)
+}
+
+func TestToolOutputFormats(t *testing.T) {
+	ctx := context.Background()
+	ct, st := mcp.NewInMemoryTransports()
+	if _, err := newServer(tools.BaseRegistry()).Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "1.0"}, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	call := func(format string) *mcp.CallToolResult {
+		t.Helper()
+		res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "list_models", Arguments: map[string]any{"provider": "anthropic", "format": format}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	text := func(res *mcp.CallToolResult) string { return res.Content[0].(*mcp.TextContent).Text }
+
+	// list_models has typed output: numbers stay numbers, and
+	// structuredContent is set whatever the format.
+	var list struct {
+		Count  int `json:"count"`
+		Models []struct {
+			Provider     string  `json:"provider"`
+			PricingInput float64 `json:"pricing_input"`
+		} `json:"models"`
+	}
+	jsonRes := call("json")
+	if err := json.Unmarshal([]byte(text(jsonRes)), &list); err != nil {
+		t.Fatalf("json format is not valid JSON: %v", err)
+	}
+	if list.Count == 0 || list.Count != len(list.Models) || list.Models[0].Provider != "Anthropic" || list.Models[0].PricingInput <= 0 {
+		t.Errorf("expected typed Anthropic models, got %+v", list)
+	}
+	structured, err := json.Marshal(jsonRes.StructuredContent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !jsonEqual(t, structured, []byte(text(jsonRes))) {
+		t.Errorf("structuredContent should match the json text block, got %s", structured)
+	}
+
+	mdRes := call("")
+	if md, _ := json.Marshal(mdRes.StructuredContent); !jsonEqual(t, md, structured) {
+		t.Errorf("markdown output should carry the same structuredContent, got %s", md)
+	}
+	tools, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tool := range tools.Tools {
+		typed := map[string]bool{"list_models": true, "get_model_info": true, "search_models": true, "compare_models": true, "recommend_model": true}[tool.Name]
+		if (tool.OutputSchema != nil) != typed {
+			t.Errorf("%s: output schema declared = %v, want %v", tool.Name, tool.OutputSchema != nil, typed)
+		}
+	}
+
+	// Tools without typed output return the parsed document for json only.
+	var doc struct {
+		Blocks []struct {
+			Type string              `json:"type"`
+			Rows []map[string]string `json:"rows"`
+		} `json:"blocks"`
+	}
+	providers := func(format string) *mcp.CallToolResult {
+		t.Helper()
+		res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "list_providers", Arguments: map[string]any{"format": format}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	docRes := providers("json")
+	if err := json.Unmarshal([]byte(text(docRes)), &doc); err != nil || len(doc.Blocks) == 0 || doc.Blocks[0].Type != "table" {
+		t.Errorf("expected a document with a table first, got %+v (%v)", doc.Blocks, err)
+	}
+	if docRes.StructuredContent == nil || providers("").StructuredContent != nil {
+		t.Error("document structuredContent should be set for json only")
+	}
+
+	md, compact := text(mdRes), text(call("compact"))
+	if len(compact) >= len(md) || strings.Contains(compact, "|---") || strings.Contains(compact, "**") {
+		t.Errorf("compact output should be shorter and undecorated:\n%s", compact)
+	}
+
+	if html := text(call("html")); !strings.HasPrefix(html, "<!DOCTYPE html>") || !strings.Contains(html, "<td>Anthropic</td>") {
+		t.Errorf("expected an HTML document, got:\n%s", html)
+	}
+
+	if res := call("xml"); !res.IsError || !strings.Contains(text(res), "markdown, json, compact, html") {
+		t.Errorf("expected an error naming the accepted formats, got %q", text(res))
+	}
+}
+
+func TestTypedOutputNotes(t *testing.T) {
+	ctx := context.Background()
+	ct, st := mcp.NewInMemoryTransports()
+	if _, err := newServer(tools.BaseRegistry()).Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "1.0"}, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	notes := func(name string, args map[string]any) string {
+		t.Helper()
+		args["format"] = "json"
+		res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out struct {
+			Notes []string `json:"notes"`
+		}
+		if err := json.Unmarshal([]byte(res.Content[0].(*mcp.TextContent).Text), &out); err != nil {
+			t.Fatalf("%s: json text is not valid JSON: %v", name, err)
+		}
+		return strings.Join(out.Notes, "\n")
+	}
+
+	if got := notes("get_model_info", map[string]any{"model_id": "gpt-5-mni"}); !strings.Contains(got, "Did you mean: gpt-5-mini") {
+		t.Errorf("a miss should keep its suggestions, got %q", got)
+	}
+	if got := notes("get_model_info", map[string]any{"model_id": "gpt-5"}); got != "" {
+		t.Errorf("a hit has nothing beyond the model, got %q", got)
+	}
+	if got := notes("list_models", map[string]any{"provider": "openai"}); !strings.Contains(got, "USE IN CODE") {
+		t.Errorf("list_models should keep its footer, got %q", got)
+	}
+	if got := notes("recommend_model", map[string]any{"task": "coding", "weights": map[string]any{"speed": 1}}); !strings.Contains(got, "Invalid weights") {
+		t.Errorf("recommend_model should explain invalid weights, got %q", got)
+	}
+	if got := notes("recommend_model", map[string]any{"task": "coding", "sovereignty": "eu"}); !strings.Contains(got, "Sovereignty: eu") || strings.Contains(got, "1. ") {
+		t.Errorf("recommend_model notes should keep the settings and leave out the picks, got %q", got)
+	}
+}
+
+func TestNewModelsOutput_Budget(t *testing.T) {
+	ms := tools.SortedByProvider(tools.BaseRegistry().FilterModels("", "", "", "", tools.Exclusions{}))
+	out := newModelsOutput(ms, nil, 3000)
+	if n := len(encodeOutput(out)); n > 3000 {
+		t.Errorf("encoded output is %d bytes, want <= 3000", n)
+	}
+	if out.Count == 0 || out.Omitted == 0 || out.Count+out.Omitted != len(ms) || out.Models[0].ID != ms[0].ID {
+		t.Errorf("expected the first models kept and the rest counted, got count %d omitted %d of %d", out.Count, out.Omitted, len(ms))
+	}
+	if all := newModelsOutput(ms, nil, 0); all.Count != len(ms) || all.Omitted != 0 {
+		t.Errorf("budget 0 should keep every model, got %d", all.Count)
+	}
+	if empty := encodeOutput(newModelsOutput(nil, nil, 3000)); !strings.Contains(empty, `"models": []`) {
+		t.Errorf("no models should encode as an empty list, got %s", empty)
+	}
+}
+
+func TestGetModelInfoSamplingAssessment(t *testing.T) {
+	saved := serverConfig
+	t.Cleanup(func() { serverConfig = saved })
+
+	var sampled int
+	connect := func(sampling bool) *mcp.ClientSession {
+		t.Helper()
+		ctx := context.Background()
+		ct, st := mcp.NewInMemoryTransports()
+		if _, err := newServer(tools.BaseRegistry()).Connect(ctx, st, nil); err != nil {
+			t.Fatal(err)
+		}
+		var opts *mcp.ClientOptions
+		if sampling {
+			opts = &mcp.ClientOptions{CreateMessageHandler: func(_ context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+				sampled++
+				if !strings.Contains(req.Params.Messages[0].Content.(*mcp.TextContent).Text, `"gpt-5-mni"`) {
+					t.Errorf("prompt should name the missed ID: %+v", req.Params.Messages[0].Content)
+				}
+				return &mcp.CreateMessageResult{Model: "test-llm", Role: "assistant", Content: &mcp.TextContent{Text: "**Typo**: one letter off gpt-5-mini"}}, nil
+			}}
+		}
+		session, err := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "1.0"}, opts).Connect(ctx, ct, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { session.Close() })
+		return session
+	}
+	lookup := func(session *mcp.ClientSession, id string) string {
+		t.Helper()
+		res, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "get_model_info", Arguments: map[string]any{"model_id": id}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.Content[0].(*mcp.TextContent).Text
+	}
+
+	serverConfig.Features.SamplingEnrichment = false
+	if got := lookup(connect(true), "gpt-5-mni"); strings.Contains(got, "Assessment") || sampled != 0 {
+		t.Errorf("sampling should be off by default, got:\n%s", got)
+	}
+
+	serverConfig.Features.SamplingEnrichment = true
+	got := lookup(connect(true), "gpt-5-mni")
+	if !strings.Contains(got, "not found") || !strings.Contains(got, "**Assessment (unverified, from `test-llm` via MCP sampling):** this looks like a typo of a tracked model ID — one letter off gpt-5-mini") {
+		t.Errorf("expected the client model's assessment, got:\n%s", got)
+	}
+	if lookup(connect(true), "gpt-5-mini"); sampled != 1 {
+		t.Errorf("hits should not sample, got %d sampling calls", sampled)
+	}
+	if got := lookup(connect(false), "gpt-5-mni"); strings.Contains(got, "Assessment") {
+		t.Errorf("clients without sampling get the plain miss, got:\n%s", got)
+	}
+}
+
+func TestAllowMissLog(t *testing.T) {
+	const key = "get_model_info\x00test-allow-miss-log"
+	now := time.Now()
+	if ok, _ := allowMissLog(key, now); !ok {
+		t.Fatal("the first miss should be logged")
+	}
+	for i := 1; i <= 3; i++ {
+		if ok, _ := allowMissLog(key, now.Add(time.Duration(i)*time.Minute)); ok {
+			t.Fatalf("repeat %d within %v should not be logged", i, missLogInterval)
+		}
+	}
+	ok, repeats := allowMissLog(key, now.Add(missLogInterval+time.Minute))
+	if !ok || repeats != 3 {
+		t.Errorf("after the interval: got ok=%v repeats=%d, want true and 3", ok, repeats)
+	}
+}
+
+func TestParseAssessment(t *testing.T) {
+	for reply, want := range map[string]string{
+		"new_model: follows the gpt-5.x naming": verdictNewModel,
+		"TYPO: close to o4-mini\nmore text":     verdictTypo,
+		"`typo`: close to o4-mini":              verdictTypo,
+		"I think it's a typo":                   "",
+		"":                                      "",
+	} {
+		a, ok := parseAssessment(reply, "")
+		if ok != (want != "") || a.Verdict != want {
+			t.Errorf("parseAssessment(%q) = %+v, %v; want %q", reply, a, ok, want)
+		}
+	}
+}
+
+// jsonEqual reports whether two JSON documents decode to the same value.
+func jsonEqual(t *testing.T, a, b []byte) bool {
+	t.Helper()
+	var x, y any
+	if err := json.Unmarshal(a, &x); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &y); err != nil {
+		t.Fatal(err)
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+func TestToolTimeout(t *testing.T) {
+	saved := serverConfig
+	t.Cleanup(func() { serverConfig = saved })
+	serverConfig.ToolTimeout = config.ToolTimeout{Default: time.Minute, PerTool: map[string]time.Duration{"stuck": 20 * time.Millisecond}}
+
+	release := make(chan struct{})
+	defer close(release)
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0"}, nil)
+	server.AddReceivingMiddleware(enforceToolTimeout)
+	mcp.AddTool(server, &mcp.Tool{Name: "stuck"}, func(context.Context, *mcp.CallToolRequest, struct{}) (*mcp.CallToolResult, any, error) {
+		<-release // ignores ctx, like a hung dependency
+		return textResult("stuck", "", "late"), nil, nil
+	})
+	mcp.AddTool(server, &mcp.Tool{Name: "deadline"}, func(ctx context.Context, _ *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, any, error) {
+		dl, ok := ctx.Deadline()
+		return textResult("deadline", "", fmt.Sprint(ok && time.Until(dl) > 30*time.Second)), nil, nil
+	})
+
+	ctx := context.Background()
+	ct, st := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "1.0"}, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "stuck"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := res.Content[0].(*mcp.TextContent).Text; !res.IsError || !strings.Contains(text, "stuck timed out after 20ms") {
+		t.Errorf("expected timeout error, got %q (IsError=%v)", text, res.IsError)
+	}
+
+	res, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "deadline"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := res.Content[0].(*mcp.TextContent).Text; text != "true" {
+		t.Error("expected handlers to see the default tool deadline on ctx")
+	}
+}
+
+func TestInitializeAnnouncesRegistry(t *testing.T) {
+	ctx := context.Background()
+	ct, st := mcp.NewInMemoryTransports()
+	if _, err := newServer(tools.BaseRegistry()).Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "1.0"}, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	meta := session.InitializeResult().Meta
+	data, err := json.Marshal(meta["registry"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var info tools.RegistryInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		t.Fatal(err)
+	}
+	if want := tools.BaseRegistry().Info(); info.Version != want.Version || info.Models != want.Models || info.Capabilities["reasoning"] != want.Capabilities["reasoning"] {
+		t.Errorf("registry meta = %+v, want %+v", info, want)
+	}
+	if meta["cursor"] != strconv.Itoa(changelog.Cursor()) {
+		t.Errorf("cursor meta = %v, want %d", meta["cursor"], changelog.Cursor())
+	}
+}
+
+func TestListModelsCapabilityAliases(t *testing.T) {
+	ctx := context.Background()
+	ct, st := mcp.NewInMemoryTransports()
+	if _, err := newServer(tools.BaseRegistry()).Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "1.0"}, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	// Aliases and other spellings are resolved by the tool, so the input
+	// schema must not restrict capability to the canonical names.
+	for _, capability := range []string{"vision", "Vision", "thinking", "function-calling", "provider_default"} {
+		res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "list_models", Arguments: map[string]any{"capability": capability}})
+		if err != nil || res.IsError {
+			t.Fatalf("capability %q should be accepted: %v", capability, err)
+		}
+		if text := res.Content[0].(*mcp.TextContent).Text; strings.Contains(text, "Unknown capability") {
+			t.Errorf("capability %q was not resolved: %s", capability, text)
+		}
+	}
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "list_models", Arguments: map[string]any{"capability": "teleportation"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := res.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "Unknown capability") || !strings.Contains(text, "vision") {
+		t.Errorf("expected an unknown capability to list the accepted ones, got: %s", text)
+	}
+}
+
+func TestPricingResourceTemplate(t *testing.T) {
+	ctx := context.Background()
+	ct, st := mcp.NewInMemoryTransports()
+	if _, err := newServer(tools.BaseRegistry()).Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "1.0"}, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	templates, err := session.ListResourceTemplates(ctx, nil)
+	if err != nil || len(templates.ResourceTemplates) != 1 || templates.ResourceTemplates[0].URITemplate != resources.PricingTemplate {
+		t.Fatalf("expected the pricing template to be listed: %+v, %v", templates, err)
+	}
+
+	full, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "model://registry/pricing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	scoped, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "model://registry/pricing?sort=output&provider=anthropic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := scoped.Contents[0].Text; strings.Contains(text, "| OpenAI |") || !strings.Contains(text, "| Anthropic |") || len(text) >= len(full.Contents[0].Text) {
+		t.Errorf("expected only Anthropic rows:\n%s", text)
+	}
+	if _, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "model://registry/pricing?sort=vibes"}); err == nil {
+		t.Error("expected an unknown sort to be an error")
+	}
+}
+
+func TestCompareModelsChart(t *testing.T) {
+	ctx := context.Background()
+	ct, st := mcp.NewInMemoryTransports()
+	if _, err := newServer(tools.BaseRegistry()).Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "1.0"}, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	call := func(args map[string]any) *mcp.CallToolResult {
+		res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "compare_models", Arguments: args})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	res := call(map[string]any{"model_ids": []string{"gpt-5", "claude-sonnet-4-6"}, "chart": true})
+	if len(res.Content) != 2 {
+		t.Fatalf("expected the table and a chart, got %d content blocks", len(res.Content))
+	}
+	img, ok := res.Content[1].(*mcp.EmbeddedResource)
+	if !ok || img.Resource.MIMEType != "image/svg+xml" || !strings.HasPrefix(img.Resource.URI, "model://registry/chart/compare/") || !strings.Contains(img.Resource.Text, "<title>Output $/1M: $") {
+		t.Errorf("expected an SVG price chart, got %#v", res.Content[1])
+	}
+	if res := call(map[string]any{"model_ids": []string{"gpt-5", "claude-sonnet-4-6"}}); len(res.Content) != 1 {
+		t.Error("the chart should be opt-in")
+	}
+	if res := call(map[string]any{"model_ids": []string{"gpt-5", "no-such-model"}, "chart": true}); len(res.Content) != 1 {
+		t.Error("no chart when a model is not found")
+	}
+}
+
+func TestOpenAPIHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/openapi.json", openAPIHandler)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	var spec struct {
+		OpenAPI string         `json:"openapi"`
+		Paths   map[string]any `json:"paths"`
+	}
+	getJSON(t, srv.URL+"/api/openapi.json", http.StatusOK, &spec)
+	if spec.OpenAPI != "3.1.0" {
+		t.Errorf("openapi version = %q, want 3.1.0", spec.OpenAPI)
+	}
+	for _, op := range openapi.Operations {
+		if _, ok := spec.Paths[op.Path]; !ok {
+			t.Errorf("spec is missing %s", op.Path)
+		}
+	}
+}
+
+func TestRequestIDReachesToolErrors(t *testing.T) {
+	getServer := func(_ *http.Request) *mcp.Server { return newServer(tools.BaseRegistry()) }
+	srv := httptest.NewServer(middleware.RequestID(mcp.NewStreamableHTTPHandler(getServer, streamableOptions(true))))
+	defer srv.Close()
+
+	post := func(requestID, format string) (*http.Response, string) {
+		t.Helper()
+		body := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_model_info","arguments":{"model_id":"gpt-5.2","format":"` + format + `"}}}`)
+		req, err := http.NewRequest(http.MethodPost, srv.URL, body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if requestID != "" {
+			req.Header.Set(middleware.RequestIDHeader, requestID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp, string(data)
+	}
+
+	resp, body := post("abc123", "xml")
+	if got := resp.Header.Get(middleware.RequestIDHeader); got != "abc123" {
+		t.Errorf("response should echo the client's request ID, got %q", got)
+	}
+	if !strings.Contains(body, "Request ID: abc123") {
+		t.Errorf("tool error should name the request ID, got %s", body)
+	}
+
+	resp, body = post("", "markdown")
+	if resp.Header.Get(middleware.RequestIDHeader) == "" {
+		t.Error("expected a generated request ID")
+	}
+	if strings.Contains(body, "Request ID") {
+		t.Errorf("successful results should not carry the request ID, got %s", body)
+	}
+}
+
+func TestListenReusePort(t *testing.T) {
+	first, err := listen("reuseport", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("reuseport unavailable: %v", err)
+	}
+	defer first.Close()
+	// A second process taking over during an upgrade binds the same port
+	// while the first is still open.
+	second, err := listen("reuseport", first.Addr().String())
+	if err != nil {
+		t.Fatalf("second reuseport listener on %s: %v", first.Addr(), err)
+	}
+	second.Close()
+
+	if ln, err := listen("tcp", first.Addr().String()); err == nil {
+		ln.Close()
+		t.Error("a plain tcp listener should not share a reuseport socket's port")
+	}
+}
+
+func TestSystemdListenerChecksEnv(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(k string) string { return vars[k] }
+	}
+	if _, err := systemdListener(env(nil), 42); err == nil || !strings.Contains(err.Error(), "LISTEN_PID") {
+		t.Errorf("expected a LISTEN_PID error without socket activation, got %v", err)
+	}
+	if _, err := systemdListener(env(map[string]string{"LISTEN_PID": "41", "LISTEN_FDS": "1"}), 42); err == nil {
+		t.Error("a socket passed to another process should be rejected")
+	}
+	if _, err := systemdListener(env(map[string]string{"LISTEN_PID": "42", "LISTEN_FDS": "0"}), 42); err == nil || !strings.Contains(err.Error(), "LISTEN_FDS") {
+		t.Errorf("expected a LISTEN_FDS error, got %v", err)
+	}
+}
+
+// openSSE starts an SSE session on srv and returns the stream and the
+// session's message endpoint.
+func openSSE(t *testing.T, srv *httptest.Server) (*http.Response, string) {
+	t.Helper()
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		if endpoint, ok := strings.CutPrefix(sc.Text(), "data: "); ok {
+			return resp, srv.URL + endpoint
+		}
+	}
+	t.Fatalf("no endpoint event: %v", sc.Err())
+	return nil, ""
+}
+
+// waitClosed fails unless the SSE stream ends within d.
+func waitClosed(t *testing.T, resp *http.Response, d time.Duration) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		io.Copy(io.Discard, resp.Body)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(d):
+		t.Fatal("idle SSE session was not closed")
+	}
+}
+
+func TestSSEIdleSessionReaped(t *testing.T) {
+	srv := httptest.NewServer(newSSEHandler(config.Sessions{IdleTimeout: 100 * time.Millisecond}))
+	defer srv.Close()
+
+	resp, _ := openSSE(t, srv)
+	waitClosed(t, resp, 5*time.Second)
+}
+
+func TestSSEActivityKeepsSessionOpen(t *testing.T) {
+	srv := httptest.NewServer(newSSEHandler(config.Sessions{IdleTimeout: 300 * time.Millisecond}))
+	defer srv.Close()
+
+	resp, endpoint := openSSE(t, srv)
+	go io.Copy(io.Discard, resp.Body)
+	for i := range 8 {
+		time.Sleep(100 * time.Millisecond)
+		post, err := http.Post(endpoint, "application/json", strings.NewReader(fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"ping"}`, i+1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		post.Body.Close()
+		if post.StatusCode == http.StatusNotFound {
+			t.Fatalf("session reaped after %d active requests", i)
+		}
+	}
+}
+
+func TestIsClientActivity(t *testing.T) {
+	for body, want := range map[string]bool{
+		`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`:         true,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`: true,
+		`{"jsonrpc":"2.0","id":"ping-1","result":{}}`:            false,
+		`not json`: true,
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/sse?sessionid=x", strings.NewReader(body))
+		if got := isClientActivity(r); got != want {
+			t.Errorf("isClientActivity(%s) = %v, want %v", body, got, want)
+		}
+		if rest, _ := io.ReadAll(r.Body); string(rest) != body {
+			t.Errorf("body not restored: got %q", rest)
+		}
+	}
+}
+
+func TestQueryAPI(t *testing.T) {
+	mux := http.NewServeMux()
+	registerQueryAPI(mux)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	var list openapi.ModelList
+	getJSON(t, srv.URL+"/api/v1/models?provider=anthropic&status=current", http.StatusOK, &list)
+	if list.Count == 0 || list.Count != len(list.Models) {
+		t.Fatalf("expected current Anthropic models, got count %d with %d models", list.Count, len(list.Models))
+	}
+	for _, m := range list.Models {
+		if m.Provider != "Anthropic" || m.Status != "current" {
+			t.Errorf("filter leaked %s (%s, %s)", m.ID, m.Provider, m.Status)
+		}
+	}
+	getJSON(t, srv.URL+"/api/v1/models?capability=telepathy", http.StatusBadRequest, nil)
+
+	var m models.Model
+	getJSON(t, srv.URL+"/api/v1/models/openai/gpt-5.2", http.StatusOK, &m)
+	if m.ID != "gpt-5.2" {
+		t.Errorf("alias with a slash resolved to %q, want gpt-5.2", m.ID)
+	}
+	var notFound struct {
+		Error       string   `json:"error"`
+		Suggestions []string `json:"suggestions"`
+	}
+	getJSON(t, srv.URL+"/api/v1/models/gtp-5.2x", http.StatusNotFound, &notFound)
+	if len(notFound.Suggestions) == 0 {
+		t.Errorf("expected suggestions for a typo, got %+v", notFound)
+	}
+
+	getJSON(t, srv.URL+"/api/v1/search?q=claude+sonnet", http.StatusOK, &list)
+	if list.Count == 0 {
+		t.Error("expected search results for claude sonnet")
+	}
+	var empty map[string]any
+	getJSON(t, srv.URL+"/api/v1/search?q=zzzz-no-such-model", http.StatusOK, &empty)
+	if ms, ok := empty["models"].([]any); !ok || len(ms) != 0 {
+		t.Errorf("expected an empty, non-null models list, got %v", empty)
+	}
+	getJSON(t, srv.URL+"/api/v1/search", http.StatusBadRequest, nil)
+
+	getJSON(t, srv.URL+"/api/v1/compare?ids=gpt-5.2,+claude-opus-4-6", http.StatusOK, &list)
+	if list.Count != 2 || list.Models[0].ID != "gpt-5.2" || list.Models[1].ID != "claude-opus-4-6" {
+		t.Errorf("compare should keep the requested order, got %+v", list.Models)
+	}
+	getJSON(t, srv.URL+"/api/v1/compare?ids=gpt-5.2", http.StatusBadRequest, nil)
+	getJSON(t, srv.URL+"/api/v1/compare?ids=gpt-5.2,zzzz-no-such-model", http.StatusNotFound, nil)
+
+	var rec openapi.RecommendResponse
+	getJSON(t, srv.URL+"/api/v1/recommend?task=coding&budget=low&min_providers=2", http.StatusOK, &rec)
+	if rec.Budget != "cheap" || len(rec.Recommendations) != 3 || rec.Recommendations[0].Rank != 1 {
+		t.Fatalf("unexpected recommend response: %+v", rec)
+	}
+	providers := map[string]bool{}
+	for _, r := range rec.Recommendations {
+		providers[r.Model.Provider] = true
+	}
+	if len(providers) < 2 {
+		t.Errorf("min_providers=2 not honored: %v", providers)
+	}
+	getJSON(t, srv.URL+"/api/v1/recommend?task=coding&min_providers=two", http.StatusBadRequest, nil)
+	getJSON(t, srv.URL+"/api/v1/recommend", http.StatusBadRequest, nil)
+}
+
+func TestWatchModelsFile_ReloadsRegistry(t *testing.T) {
+	saved, _ := models.Snapshot()
+	t.Cleanup(func() { tools.UseModels(saved) })
+
+	path := filepath.Join(t.TempDir(), "models.json")
+	write := func(ms map[string]models.Model, mtime time.Time) {
+		t.Helper()
+		data, err := models.Encode(ms)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	start := time.Now()
+	write(saved, start)
+
+	tenants, err := loadTenants(map[string]config.Tenant{"acme": {}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := tools.BaseRegistry()
+	reloaded := make(chan struct{}, 1)
+	apply := reloadRegistry(path, tenants, map[string]config.Tenant{"acme": {}})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watchModelsFile(ctx, path, 5*time.Millisecond, func(ms map[string]models.Model) {
+		apply(ms)
+		reloaded <- struct{}{}
+	})
+
+	edited := make(map[string]models.Model, len(saved))
+	for id, m := range saved {
+		edited[id] = m
+	}
+	delete(edited, "gpt-4.1-nano")
+	write(edited, start.Add(time.Minute))
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("registry was not reloaded after the file changed")
+	}
+	if _, ok := tools.BaseRegistry().Models()["gpt-4.1-nano"]; ok || tools.BaseRegistry() == before {
+		t.Error("base registry should serve the reloaded data")
+	}
+	if _, ok := before.Models()["gpt-4.1-nano"]; !ok {
+		t.Error("a registry handed out before the reload must keep its data")
+	}
+	reg, _ := tenants["acme"].registryFor(httptest.NewRequest(http.MethodGet, "/mcp/acme", nil))
+	if _, ok := reg.Models()["gpt-4.1-nano"]; ok {
+		t.Error("tenant registries should be rebuilt over the reloaded data")
+	}
+
+	// An invalid edit is logged and the current data kept.
+	if err := os.WriteFile(path, []byte(`{"broken": `), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Chtimes(path, start.Add(2*time.Minute), start.Add(2*time.Minute))
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case <-reloaded:
+		t.Error("an invalid file should not be applied")
+	default:
+	}
+	if got := len(tools.BaseRegistry().Models()); got != len(edited) {
+		t.Errorf("after an invalid edit the registry has %d models, want %d", got, len(edited))
+	}
+}
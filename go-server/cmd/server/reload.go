@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	"go-server/internal/config"
+	"go-server/internal/models"
+	"go-server/internal/tools"
+)
+
+// watchModelsFile checks path every interval and, when its modification
+// time or size changes, loads it and passes the models to apply if they
+// differ from the ones in use. The first check always loads the file, so an
+// edit made between startup and the first tick isn't missed. A file that is
+// missing or fails to load is logged and the data in use kept, so a
+// half-written or invalid edit never takes the registry down. It returns
+// when ctx ends.
+func watchModelsFile(ctx context.Context, path string, interval time.Duration, apply func(map[string]models.Model)) {
+	var last os.FileInfo
+	missing := false
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			if !missing {
+				fmt.Fprintf(os.Stderr, "Registry reload: %v; keeping the current data\n", err)
+			}
+			last, missing = nil, true
+			continue
+		}
+		missing = false
+		if last != nil && fi.ModTime().Equal(last.ModTime()) && fi.Size() == last.Size() {
+			continue
+		}
+		last = fi
+		ms, err := models.LoadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Registry reload failed, keeping the current data: %v\n", err)
+			continue
+		}
+		if current, _ := models.Snapshot(); !reflect.DeepEqual(ms, current) {
+			apply(ms)
+		}
+	}
+}
+
+// reloadRegistry swaps ms in as the base registry and rebuilds the tenant
+// registries over it. Sessions already open keep the registry they started
+// with; new sessions and API requests get the new data.
+func reloadRegistry(path string, tenants map[string]*tenant, cfgs map[string]config.Tenant) func(map[string]models.Model) {
+	return func(ms map[string]models.Model) {
+		tools.UseModels(ms)
+		if err := reloadTenants(tenants, cfgs); err != nil {
+			fmt.Fprintf(os.Stderr, "Registry reload: tenants keep their previous data: %v\n", err)
+		}
+		info := tools.BaseRegistry().Info()
+		fmt.Fprintf(os.Stderr, "Registry data reloaded from %s: %d models, version %s\n", path, info.Models, info.Version)
+		if conflicts := models.AliasConflicts(); len(conflicts) > 0 {
+			fmt.Fprintf(os.Stderr, "Registry data warning: %d alias conflicts, first: %s\n", len(conflicts), conflicts[0])
+		}
+	}
+}
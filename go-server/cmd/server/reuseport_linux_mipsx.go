@@ -0,0 +1,7 @@
+//go:build linux && (mips || mipsle || mips64 || mips64le)
+
+package main
+
+// soReusePort is SO_REUSEPORT, which the frozen syscall package omits on
+// Linux.
+const soReusePort = 0x200
@@ -0,0 +1,14 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+)
+
+// reusePort fails: this platform has no SO_REUSEPORT.
+func reusePort(_, _ string, _ syscall.RawConn) error {
+	return fmt.Errorf("listener reuseport: SO_REUSEPORT is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// samplingTimeout bounds the sampling round trip. The assessment only
+// decorates a reply that has already missed, so a slow or unattended client
+// gets the plain reply quickly rather than waiting on its own model.
+const samplingTimeout = 3 * time.Second
+
+// missLogInterval is how long after logging a miss for one tool and ID
+// repeats of it are only counted, so a client retrying a bad ID can't flood
+// the log.
+const missLogInterval = 10 * time.Minute
+
+// maxMissLogKeys bounds how many tool and ID pairs missLog remembers.
+const maxMissLogKeys = 4096
+
+// maxAssessmentReason caps the reason kept from the client model's reply.
+const maxAssessmentReason = 200
+
+// Verdicts a client model may give for an unknown model ID.
+const (
+	verdictNewModel = "new_model"
+	verdictTypo     = "typo"
+)
+
+// missAssessment is the client model's opinion of a model ID the registry
+// doesn't track.
+type missAssessment struct {
+	Verdict string // verdictNewModel or verdictTypo
+	Reason  string
+	Model   string // the client model that answered, if it said
+}
+
+// note renders the assessment for a "not found" reply, labelled as an
+// unverified opinion so it isn't mistaken for registry data.
+func (a missAssessment) note() string {
+	what := "a plausible new model the registry doesn't track yet"
+	if a.Verdict == verdictTypo {
+		what = "a typo of a tracked model ID"
+	}
+	from := "the client's model"
+	if a.Model != "" {
+		from = "`" + a.Model + "`"
+	}
+	note := fmt.Sprintf("**Assessment (unverified, from %s via MCP sampling):** this looks like %s", from, what)
+	if a.Reason != "" {
+		note += " — " + a.Reason
+	}
+	return note
+}
+
+// assessMiss asks the client's model, through MCP sampling, whether modelID
+// looks like a real model the registry hasn't added yet or a misspelling of
+// one of suggestions. ok is false when sampling_enrichment is off, the
+// client doesn't support sampling, or the request fails or gets an answer
+// that isn't one of the two verdicts; the lookup then answers as it would
+// without sampling.
+func assessMiss(ctx context.Context, session *mcp.ServerSession, modelID string, suggestions []string) (a missAssessment, ok bool) {
+	if !serverConfig.Features.SamplingEnrichment || session == nil {
+		return a, false
+	}
+	if p := session.InitializeParams(); p == nil || p.Capabilities == nil || p.Capabilities.Sampling == nil {
+		return a, false
+	}
+	ctx, cancel := context.WithTimeout(ctx, samplingTimeout)
+	defer cancel()
+	res, err := session.CreateMessage(ctx, &mcp.CreateMessageParams{
+		SystemPrompt: "You classify AI model API identifiers. Reply with one line: \"" + verdictNewModel + ": <short reason>\" if the ID is plausibly a real model a registry hasn't added yet, or \"" + verdictTypo + ": <short reason>\" if it is most likely a misspelling of a listed ID.",
+		Messages: []*mcp.SamplingMessage{{
+			Role: "user",
+			Content: &mcp.TextContent{Text: fmt.Sprintf("A model registry has no model with the ID %q. Its closest tracked IDs are: %s. Is the ID a new model or a typo?",
+				modelID, strings.Join(suggestions, ", "))},
+		}},
+		MaxTokens:        100,
+		ModelPreferences: &mcp.ModelPreferences{SpeedPriority: 1, CostPriority: 1},
+	})
+	if err != nil {
+		return a, false
+	}
+	text, isText := res.Content.(*mcp.TextContent)
+	if !isText {
+		return a, false
+	}
+	return parseAssessment(text.Text, res.Model)
+}
+
+// parseAssessment reads a "verdict: reason" reply, tolerating case, quotes,
+// and markdown emphasis around the verdict.
+func parseAssessment(reply, model string) (missAssessment, bool) {
+	line, _, _ := strings.Cut(strings.TrimSpace(reply), "\n")
+	verdict, reason, _ := strings.Cut(line, ":")
+	verdict = strings.ToLower(strings.Trim(verdict, " *`\"'"))
+	if verdict != verdictNewModel && verdict != verdictTypo {
+		return missAssessment{}, false
+	}
+	return missAssessment{Verdict: verdict, Reason: truncate(strings.TrimSpace(reason), maxAssessmentReason), Model: model}, true
+}
+
+// missLog tracks when each tool and ID pair was last logged and how many
+// repeats have been dropped since.
+var missLog = struct {
+	sync.Mutex
+	seen map[string]*missLogEntry
+}{seen: make(map[string]*missLogEntry)}
+
+type missLogEntry struct {
+	logged  time.Time
+	repeats int
+}
+
+// allowMissLog reports whether a miss for key should be logged at now, and
+// how many repeats were suppressed since it last was.
+func allowMissLog(key string, now time.Time) (ok bool, repeats int) {
+	missLog.Lock()
+	defer missLog.Unlock()
+	if e := missLog.seen[key]; e != nil && now.Sub(e.logged) < missLogInterval {
+		e.repeats++
+		return false, 0
+	} else if e != nil {
+		repeats = e.repeats
+	}
+	if len(missLog.seen) >= maxMissLogKeys {
+		for k, e := range missLog.seen {
+			if now.Sub(e.logged) >= missLogInterval {
+				delete(missLog.seen, k)
+			}
+		}
+		if len(missLog.seen) >= maxMissLogKeys {
+			clear(missLog.seen)
+		}
+	}
+	missLog.seen[key] = &missLogEntry{logged: now}
+	return true, repeats
+}
+
+// logLookupMiss records a lookup of an unknown model ID, with the client
+// model's assessment when sampling produced one. Misses show which new
+// models callers are asking for before the updater finds them. Each tool and
+// ID is logged at most once per missLogInterval, with the count of repeats
+// in between.
+func logLookupMiss(tool, modelID string, a missAssessment, assessed bool) {
+	ok, repeats := allowMissLog(tool+"\x00"+modelID, time.Now())
+	if !ok {
+		return
+	}
+	line := fmt.Sprintf("lookup miss: %s %q", tool, modelID)
+	if assessed {
+		line += fmt.Sprintf(" (sampling: %s — %s)", a.Verdict, a.Reason)
+	}
+	if repeats > 0 {
+		line += fmt.Sprintf(" [%d repeats not logged]", repeats)
+	}
+	log.Print(line)
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"go-server/internal/config"
+	"go-server/internal/tools"
+)
+
+// Open MCP sessions per transport, served on /metrics.
+var sseSessions, streamableSessions atomic.Int64
+
+// countSessions counts server's sessions in active, each from its client's
+// first initialized notification until the session closes; a client that
+// repeats the notification is still one session. Stateless streamable HTTP
+// requests never initialize, so they aren't counted.
+func countSessions(server *mcp.Server, active *atomic.Int64) *mcp.Server {
+	var counted sync.Map // *mcp.ServerSession → struct{}
+	server.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if ss, ok := req.GetSession().(*mcp.ServerSession); ok && method == "notifications/initialized" {
+				if _, seen := counted.LoadOrStore(ss, struct{}{}); !seen {
+					active.Add(1)
+					go func() {
+						_ = ss.Wait()
+						active.Add(-1)
+						counted.Delete(ss)
+					}()
+				}
+			}
+			return next(ctx, method, req)
+		}
+	})
+	return server
+}
+
+// newSSEHandler serves the SSE transport with keepalive pings and idle
+// session reaping from the sessions config.
+func newSSEHandler(s config.Sessions) http.Handler {
+	getServer := func(_ *http.Request) *mcp.Server {
+		return countSessions(newKeepAliveServer(tools.BaseRegistry(), s.KeepAlive), &sseSessions)
+	}
+	return reapIdleSSE(mcp.NewSSEHandler(getServer, nil), s.IdleTimeout)
+}
+
+// sseIdleReaper closes SSE sessions whose client has sent no request or
+// notification for timeout. Replies to keepalive pings don't count, so a
+// connected but unused session is reclaimed and its connection slot freed.
+type sseIdleReaper struct {
+	next    http.Handler
+	timeout time.Duration
+
+	mu     sync.Mutex
+	timers map[string]*time.Timer // by session ID
+}
+
+// reapIdleSSE wraps an SSE handler with idle reaping. A zero timeout
+// returns next unchanged.
+func reapIdleSSE(next http.Handler, timeout time.Duration) http.Handler {
+	if timeout <= 0 {
+		return next
+	}
+	return &sseIdleReaper{next: next, timeout: timeout, timers: make(map[string]*time.Timer)}
+}
+
+func (s *sseIdleReaper) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		// The SSE handler ends the session when the stream's context ends.
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		sw := &sessionSniffer{ResponseWriter: w}
+		sw.onSession = func(id string) {
+			s.mu.Lock()
+			s.timers[id] = time.AfterFunc(s.timeout, cancel)
+			s.mu.Unlock()
+		}
+		s.next.ServeHTTP(sw, r.WithContext(ctx))
+		if sw.id != "" {
+			s.mu.Lock()
+			s.timers[sw.id].Stop()
+			delete(s.timers, sw.id)
+			s.mu.Unlock()
+		}
+	case http.MethodPost:
+		if id := r.URL.Query().Get("sessionid"); id != "" && isClientActivity(r) {
+			s.mu.Lock()
+			if t, ok := s.timers[id]; ok {
+				t.Reset(s.timeout)
+			}
+			s.mu.Unlock()
+		}
+		s.next.ServeHTTP(w, r)
+	default:
+		s.next.ServeHTTP(w, r)
+	}
+}
+
+// isClientActivity reports whether a POSTed message is a request or
+// notification rather than a reply to a server ping. It peeks at the body
+// and restores it; anything it can't parse counts as activity.
+func isClientActivity(r *http.Request) bool {
+	const peek = 64 << 10
+	head, err := io.ReadAll(io.LimitReader(r.Body, peek))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), r.Body), r.Body}
+	if err != nil {
+		return true
+	}
+	var msg struct {
+		Method string `json:"method"`
+	}
+	if json.Unmarshal(head, &msg) != nil {
+		return true
+	}
+	return msg.Method != ""
+}
+
+var sessionIDRe = regexp.MustCompile(`sessionid=([A-Za-z0-9_-]+)`)
+
+// sessionSniffer passes an SSE stream through, calling onSession with the
+// session ID from the stream's endpoint event.
+type sessionSniffer struct {
+	http.ResponseWriter
+	id        string
+	onSession func(id string)
+}
+
+func (w *sessionSniffer) Write(p []byte) (int, error) {
+	if w.id == "" {
+		if m := sessionIDRe.FindSubmatch(p); m != nil {
+			w.id = string(m[1])
+			w.onSession(w.id)
+		}
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *sessionSniffer) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *sessionSniffer) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"go-server/internal/models"
+	"go-server/internal/openapi"
+	"go-server/internal/render"
+	"go-server/internal/tools"
+)
+
+// modelsOutput is the structured content of the tools that return models:
+// list_models, get_model_info, search_models, and compare_models. It is the
+// /api/v1/models body plus how many models the output budget dropped and
+// the notes of the text output.
+type modelsOutput struct {
+	openapi.ModelList
+	Omitted int      `json:"omitted,omitempty" jsonschema:"Models dropped from the end to fit the tool's output budget; narrow the filters to see them"`
+	Notes   []string `json:"notes,omitempty" jsonschema:"What the text output says beyond the data: why nothing matched and which IDs were meant, the USE IN CODE footer, retirement dates, coverage, and org policy notes"`
+}
+
+// recommendOutput is the structured content of recommend_model: the
+// /api/v1/recommend body plus the notes of the text output.
+type recommendOutput struct {
+	openapi.RecommendResponse
+	Notes []string `json:"notes,omitempty" jsonschema:"What the text output says beyond the picks: invalid weights, why nothing is left, skipped providers, the stability and weight settings used, and org policy notes"`
+}
+
+// newModelsOutput keeps as many of ms as fit in maxBytes of encoded JSON
+// along with notes, in order. maxBytes <= 0 means no limit.
+func newModelsOutput(ms []models.Model, notes []string, maxBytes int) modelsOutput {
+	if ms == nil {
+		ms = []models.Model{}
+	}
+	keep := func(n int) modelsOutput {
+		return modelsOutput{ModelList: openapi.ModelList{Count: n, Models: ms[:n]}, Omitted: len(ms) - n, Notes: notes}
+	}
+	if maxBytes <= 0 {
+		return keep(len(ms))
+	}
+	tooBig := sort.Search(len(ms)+1, func(n int) bool { return len(encodeOutput(keep(n))) > maxBytes })
+	return keep(max(tooBig-1, 0))
+}
+
+// typedResult is textResult for tools with typed output. The SDK sets
+// structuredContent from out and checks it against the output schema it
+// derives from out's type, so clients get numbers as numbers instead of the
+// strings of a parsed markdown table. With format json the text block is out
+// encoded, as the spec suggests; otherwise it is the rendered text.
+func typedResult[T any](tool, format, text string, out T) (*mcp.CallToolResult, T, error) {
+	if f, ok := render.Normalize(format); ok && f == render.JSON {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: encodeOutput(out)}}}, out, nil
+	}
+	return textResult(tool, format, text), out, nil
+}
+
+// modelsResult is typedResult for the tools that return models, with ms cut
+// to the tool's output budget.
+func modelsResult(tool, format, text string, ms []models.Model) (*mcp.CallToolResult, modelsOutput, error) {
+	return typedResult(tool, format, text, newModelsOutput(ms, notesOf(text), tools.OutputBudget(tool)))
+}
+
+// numberedItem matches the first line of a numbered list item.
+var numberedItem = regexp.MustCompile(`^\d+\. `)
+
+// notesOf returns the prose of a tool's markdown that its typed output
+// doesn't carry as data, one paragraph per note: the text after the last
+// table, or all of it when there is no table, as for a lookup miss or an
+// invalid filter. Numbered items are left out; they are recommend_model's
+// picks.
+func notesOf(markdown string) []string {
+	blocks := render.Parse(markdown)
+	for i := len(blocks) - 1; i >= 0; i-- {
+		if blocks[i].Type == "table" {
+			blocks = blocks[i+1:]
+			break
+		}
+	}
+	var notes []string
+	for _, b := range blocks {
+		if b.Type == "text" && !numberedItem.MatchString(b.Text) {
+			notes = append(notes, b.Text)
+		}
+	}
+	return notes
+}
+
+// comparedModels returns the models compare_models compares: the first 5
+// of ids that resolve, in order.
+func comparedModels(reg *tools.Registry, ids []string) []models.Model {
+	if len(ids) < 2 {
+		return nil
+	}
+	var found []models.Model
+	for _, id := range ids[:min(len(ids), 5)] {
+		if m, ok := reg.FindModel(id); ok {
+			found = append(found, m)
+		}
+	}
+	return found
+}
+
+// encodeOutput encodes a typed tool output the way the json format does.
+func encodeOutput(v any) string {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		// Outputs hold only strings, numbers, bools, and slices of them.
+		panic(err)
+	}
+	return string(out)
+}
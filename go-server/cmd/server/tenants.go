@@ -0,0 +1,187 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"go-server/internal/config"
+	"go-server/internal/middleware"
+	"go-server/internal/models"
+	"go-server/internal/tools"
+)
+
+// tenant is a tenant's registry in two views: public, without the overlay
+// models marked internal, and private, with them, for requests carrying one
+// of the tenant's API keys. Without internal models both views are the same.
+// The views are swapped when the registry data is reloaded.
+type tenant struct {
+	mu              sync.RWMutex
+	public, private *tools.Registry
+	keys            []string
+	hidden          int // overlay models marked internal
+}
+
+// loadTenants builds the registries for each configured tenant from its
+// overlay and policy files.
+func loadTenants(cfgs map[string]config.Tenant) (map[string]*tenant, error) {
+	tenants := make(map[string]*tenant, len(cfgs))
+	for name, tc := range cfgs {
+		t, err := buildTenant(name, tc)
+		if err != nil {
+			return nil, err
+		}
+		if len(t.keys) == 0 && t.hidden > 0 {
+			fmt.Fprintf(os.Stderr, "Tenant %s: %d internal overlay models are hidden from every request; set api_keys_env to serve them\n", name, t.hidden)
+		}
+		tenants[name] = t
+	}
+	return tenants, nil
+}
+
+// reloadTenants rebuilds each tenant's views over the base registry now in
+// use, re-reading overlay and policy files, and swaps them in. A tenant that
+// fails to build keeps its previous views; the errors are joined.
+func reloadTenants(tenants map[string]*tenant, cfgs map[string]config.Tenant) error {
+	var errs []error
+	for name, t := range tenants {
+		nt, err := buildTenant(name, cfgs[name])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		t.mu.Lock()
+		t.public, t.private = nt.public, nt.private
+		t.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// buildTenant builds one tenant's views from its overlay and policy files.
+func buildTenant(name string, tc config.Tenant) (*tenant, error) {
+	var overlay map[string]models.Model
+	var internal map[string]bool
+	if tc.OverlayFile != "" {
+		data, err := os.ReadFile(tc.OverlayFile)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", name, err)
+		}
+		if overlay, err = tools.ParseSnapshot(data); err != nil {
+			return nil, fmt.Errorf("tenant %s: overlay %s: %w", name, tc.OverlayFile, err)
+		}
+		if internal, err = internalIDs(data); err != nil {
+			return nil, fmt.Errorf("tenant %s: overlay %s: %w", name, tc.OverlayFile, err)
+		}
+	}
+	var policy *tools.Policy
+	if tc.PolicyFile != "" {
+		p, err := tools.LoadPolicy(tc.PolicyFile)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", name, err)
+		}
+		policy = p
+	}
+	t := &tenant{keys: tc.APIKeys(), hidden: len(internal)}
+	var err error
+	if t.private, err = tools.NewTenantRegistry(name, overlay, policy); err != nil {
+		return nil, err
+	}
+	t.public = t.private
+	if len(internal) > 0 {
+		public := maps.Clone(overlay)
+		maps.DeleteFunc(public, func(id string, _ models.Model) bool { return internal[id] })
+		if t.public, err = tools.NewTenantRegistry(name, public, policy); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// internalIDs returns the IDs of overlay entries marked "internal": true. It
+// accepts the same map and array forms as tools.ParseSnapshot.
+func internalIDs(data []byte) (map[string]bool, error) {
+	type entry struct {
+		ID       string `json:"id"`
+		Internal bool   `json:"internal"`
+	}
+	var list []entry
+	var byID map[string]entry
+	if err := json.Unmarshal(data, &byID); err == nil {
+		for id, e := range byID {
+			e.ID = id
+			list = append(list, e)
+		}
+	} else if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	ids := make(map[string]bool)
+	for _, e := range list {
+		if e.Internal {
+			ids[e.ID] = true
+		}
+	}
+	return ids, nil
+}
+
+// registryFor picks the view for r: private when it carries one of the
+// tenant's keys, as a bearer token or in X-API-Key, else public. ok is false
+// when r carries a key that doesn't match, so a misconfigured client is
+// told rather than silently served the public view. Tenants without keys
+// ignore credentials, which a gateway in front may have added.
+func (t *tenant) registryFor(r *http.Request) (reg *tools.Registry, ok bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if len(t.keys) == 0 {
+		return t.public, true
+	}
+	key := r.Header.Get("X-API-Key")
+	if auth := r.Header.Get("Authorization"); key == "" && auth != "" {
+		scheme, token, _ := strings.Cut(auth, " ")
+		if !strings.EqualFold(scheme, "Bearer") {
+			return nil, false
+		}
+		key = strings.TrimSpace(token)
+	}
+	if key == "" {
+		return t.public, true
+	}
+	for _, k := range t.keys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(k)) == 1 {
+			return t.private, true
+		}
+	}
+	return nil, false
+}
+
+// tenantHandler serves /mcp/{tenant}, giving each session a server backed by
+// that tenant's registry. Unknown tenants get a 404 rather than falling back
+// to the base registry, so a typo can't bypass a tenant's policy. The API
+// key is checked when a session starts; the session keeps that view.
+func tenantHandler(tenants map[string]*tenant, stateless bool) http.Handler {
+	getServer := func(r *http.Request) *mcp.Server {
+		reg, _ := tenants[r.PathValue("tenant")].registryFor(r)
+		return countSessions(newServer(reg), &streamableSessions)
+	}
+	streamable := mcp.NewStreamableHTTPHandler(getServer, streamableOptions(stateless))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t, ok := tenants[r.PathValue("tenant")]
+		if !ok {
+			middleware.Error(w, r, "unknown tenant", http.StatusNotFound)
+			return
+		}
+		if _, ok := t.registryFor(r); !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mcp"`)
+			middleware.Error(w, r, "invalid API key", http.StatusUnauthorized)
+			return
+		}
+		streamable.ServeHTTP(w, r)
+	})
+}
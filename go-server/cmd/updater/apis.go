@@ -0,0 +1,273 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// apiStyle is how a provider's model-list endpoint authenticates and pages.
+type apiStyle int
+
+const (
+	styleOpenAI    apiStyle = iota // Bearer key; {"data": [{"id"}]}, one page
+	styleAnthropic                 // x-api-key; {"data": [{"id"}], "has_more", "last_id"}
+	styleGemini                    // x-goog-api-key; {"models": [{"name": "models/..."}], "nextPageToken"}
+	styleCohere                    // Bearer key; {"models": [{"name"}], "next_page_token"}
+)
+
+// ModelsAPI is a provider's authenticated model-list endpoint. When one of
+// EnvKeys is set the updater lists models from it instead of scraping docs,
+// and falls back to the docs if the call fails or lists nothing it keeps.
+type ModelsAPI struct {
+	URL     string
+	EnvKeys []string // tried in order; the first is the provider's usual SDK variable
+	Style   apiStyle
+	Keep    *regexp.Regexp // IDs to track; the lists also hold embedding, audio, and image models
+}
+
+// maxAPIPages bounds pagination in case an endpoint keeps returning a cursor.
+const maxAPIPages = 20
+
+// modelsAPIs maps provider name to its model-list endpoint. Listed IDs then go
+// through the cleanup of the provider's configured source, if it has one, so
+// API and docs runs report the same IDs.
+var modelsAPIs = map[string]ModelsAPI{
+	"OpenAI": {
+		URL:     "https://api.openai.com/v1/models",
+		EnvKeys: []string{"OPENAI_API_KEY"},
+		Keep:    regexp.MustCompile(`^(?:gpt-[0-9][a-z0-9._-]*|o[0-9](?:-[a-z0-9-]+)*)$`),
+	},
+	"Anthropic": {
+		URL:     "https://api.anthropic.com/v1/models",
+		EnvKeys: []string{"ANTHROPIC_API_KEY"},
+		Style:   styleAnthropic,
+		Keep:    regexp.MustCompile(`^claude-(?:opus|sonnet|haiku)-[0-9]+(?:-[0-9]+)*(?:-[0-9]{8})?$`),
+	},
+	"Google": {
+		URL:     "https://generativelanguage.googleapis.com/v1beta/models",
+		EnvKeys: []string{"GEMINI_API_KEY", "GOOGLE_API_KEY"},
+		Style:   styleGemini,
+		Keep:    regexp.MustCompile(`^gemini-[0-9]+\.?[0-9]*-(?:pro|pro-image|flash|flash-lite)(?:-preview)?$`),
+	},
+	"Mistral": {
+		URL:     "https://api.mistral.ai/v1/models",
+		EnvKeys: []string{"MISTRAL_API_KEY"},
+		Keep:    regexp.MustCompile(`^(?:mistral|devstral|codestral|ministral|magistral)(?:-[a-z0-9]+)*-[0-9]{2,4}$`),
+	},
+	"xAI": {
+		URL:     "https://api.x.ai/v1/models",
+		EnvKeys: []string{"XAI_API_KEY"},
+		Keep:    regexp.MustCompile(`^grok-`),
+	},
+	"DeepSeek": {
+		URL:     "https://api.deepseek.com/models",
+		EnvKeys: []string{"DEEPSEEK_API_KEY"},
+		Keep:    regexp.MustCompile(`^deepseek-`),
+	},
+	"Cohere": {
+		URL:     "https://api.cohere.com/v1/models?endpoint=chat",
+		EnvKeys: []string{"CO_API_KEY", "COHERE_API_KEY"},
+		Style:   styleCohere,
+		Keep:    regexp.MustCompile(`^command-`),
+	},
+	// Providers below have no scrapable docs, so without a key they are
+	// only cross-checked against the aggregator catalog.
+	"Moonshot": {
+		URL:     "https://api.moonshot.ai/v1/models",
+		EnvKeys: []string{"MOONSHOT_API_KEY"},
+		Keep:    regexp.MustCompile(`^kimi-`),
+	},
+	"NVIDIA": {
+		URL:     "https://integrate.api.nvidia.com/v1/models",
+		EnvKeys: []string{"NVIDIA_API_KEY"},
+		Keep:    regexp.MustCompile(`^nvidia/.*nemotron`),
+	},
+}
+
+// modelsAPIFor returns the provider's model-list endpoint and the API key to
+// call it with, or false when it has no endpoint or no key is set.
+func modelsAPIFor(provider string) (ModelsAPI, string, bool) {
+	api, ok := modelsAPIs[provider]
+	if !ok {
+		return ModelsAPI{}, "", false
+	}
+	for _, env := range api.EnvKeys {
+		if key := os.Getenv(env); key != "" {
+			return api, key, true
+		}
+	}
+	return ModelsAPI{}, "", false
+}
+
+// fetch lists the provider's model IDs, following pagination, and keeps the
+// ones matching Keep.
+func (api ModelsAPI) fetch(ctx context.Context, client *http.Client, apiKey string) ([]string, error) {
+	var ids []string
+	var err error
+	switch api.Style {
+	case styleAnthropic:
+		ids, err = api.pages(ctx, client, func(req *http.Request) {
+			req.Header.Set("x-api-key", apiKey)
+			req.Header.Set("anthropic-version", "2023-06-01")
+		}, "limit", "1000", func(body []byte) ([]string, url.Values, error) {
+			var page struct {
+				Data []struct {
+					ID string `json:"id"`
+				} `json:"data"`
+				HasMore bool   `json:"has_more"`
+				LastID  string `json:"last_id"`
+			}
+			if err := json.Unmarshal(body, &page); err != nil {
+				return nil, nil, err
+			}
+			var ids []string
+			for _, m := range page.Data {
+				ids = append(ids, m.ID)
+			}
+			if !page.HasMore || page.LastID == "" {
+				return ids, nil, nil
+			}
+			return ids, url.Values{"after_id": {page.LastID}}, nil
+		})
+	case styleGemini:
+		ids, err = api.pages(ctx, client, func(req *http.Request) {
+			req.Header.Set("x-goog-api-key", apiKey)
+		}, "pageSize", "1000", func(body []byte) ([]string, url.Values, error) {
+			var page struct {
+				Models []struct {
+					Name string `json:"name"`
+				} `json:"models"`
+				NextPageToken string `json:"nextPageToken"`
+			}
+			if err := json.Unmarshal(body, &page); err != nil {
+				return nil, nil, err
+			}
+			var ids []string
+			for _, m := range page.Models {
+				ids = append(ids, strings.TrimPrefix(m.Name, "models/"))
+			}
+			if page.NextPageToken == "" {
+				return ids, nil, nil
+			}
+			return ids, url.Values{"pageToken": {page.NextPageToken}}, nil
+		})
+	case styleCohere:
+		ids, err = api.pages(ctx, client, bearer(apiKey), "page_size", "1000", func(body []byte) ([]string, url.Values, error) {
+			var page struct {
+				Models []struct {
+					Name string `json:"name"`
+				} `json:"models"`
+				NextPageToken string `json:"next_page_token"`
+			}
+			if err := json.Unmarshal(body, &page); err != nil {
+				return nil, nil, err
+			}
+			var ids []string
+			for _, m := range page.Models {
+				ids = append(ids, m.Name)
+			}
+			if page.NextPageToken == "" {
+				return ids, nil, nil
+			}
+			return ids, url.Values{"page_token": {page.NextPageToken}}, nil
+		})
+	default:
+		ids, err = fetchModelsFromAPI(ctx, client, api.URL, apiKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if api.Keep == nil {
+		return ids, nil
+	}
+	kept := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if api.Keep.MatchString(id) {
+			kept = append(kept, id)
+		}
+	}
+	return kept, nil
+}
+
+// pages GETs api.URL with sizeParam=size, decoding each page with decode,
+// which returns the page's IDs and the query that fetches the next page (nil
+// on the last).
+func (api ModelsAPI) pages(ctx context.Context, client *http.Client, auth func(*http.Request), sizeParam, size string,
+	decode func(body []byte) ([]string, url.Values, error)) ([]string, error) {
+	next := url.Values{}
+	var ids []string
+	for range maxAPIPages {
+		u, err := url.Parse(api.URL)
+		if err != nil {
+			return nil, err
+		}
+		q := u.Query()
+		q.Set(sizeParam, size)
+		for k, v := range next {
+			q[k] = v
+		}
+		u.RawQuery = q.Encode()
+
+		body, err := getAPI(ctx, client, u.String(), auth)
+		if err != nil {
+			return nil, err
+		}
+		page, more, err := decode(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse API response: %w", err)
+		}
+		ids = append(ids, page...)
+		if more == nil {
+			return ids, nil
+		}
+		next = more
+	}
+	return ids, fmt.Errorf("API still paginating after %d pages", maxAPIPages)
+}
+
+func bearer(apiKey string) func(*http.Request) {
+	return func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+}
+
+// getAPI performs an authenticated GET and returns the body of a 200
+// response, read up to defaultMaxBodyBytes.
+func getAPI(ctx context.Context, client *http.Client, endpoint string, auth func(*http.Request)) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	auth(req)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned HTTP %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, defaultMaxBodyBytes))
+}
+
+// undocumented lists providers with no scrapable model listing, with where to
+// check them by hand. Those in modelsAPIs are checked when their key is set.
+var undocumented = []struct{ Name, Hint string }{
+	{"Meta", "models are provider-hosted"},
+	{"AI21", "check docs.ai21.com"},
+	{"Moonshot", "check platform.moonshot.cn"},
+	{"NVIDIA", "check build.nvidia.com"},
+	{"Tencent", "check cloud.tencent.com/product/hunyuan"},
+	{"Microsoft", "check ai.azure.com"},
+	{"Xiaomi", "check platform.xiaomimimo.com"},
+	{"Kuaishou", "check kwaipilot.com"},
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"go-server/internal/forge"
+	"go-server/internal/models"
+)
+
+// autoAddMode reports whether newly detected models should be written into
+// the registry as stub entries and proposed in a draft pull request
+// (UPDATER_AUTO_ADD=true), instead of only being reported in an issue.
+func autoAddMode() bool {
+	on, _ := strconv.ParseBool(os.Getenv("UPDATER_AUTO_ADD"))
+	return on
+}
+
+// stubNote starts the Notes of every generated entry, so an unedited stub is
+// easy to find in review.
+const stubNote = "TODO: generated by the updater."
+
+// stub is a generated registry entry and the ID of the model its limits and
+// capabilities were copied from, empty when the provider had none.
+type stub struct {
+	models.Model
+	From string
+}
+
+// stubEntry builds a best-guess registry entry for a newly detected id. Its
+// limits and capabilities are copied from the provider's template model (see
+// templateModel) and its pricing is left at zero, so every field is a
+// starting point for the maintainer rather than a claim about the model.
+func stubEntry(ms map[string]models.Model, provider, id string, now time.Time) stub {
+	m := models.Model{
+		ID:              id,
+		DisplayName:     guessDisplayName(id),
+		Provider:        provider,
+		ContextWindow:   128000,
+		MaxOutputTokens: 8192,
+		SystemPrompt:    models.SystemPromptFull,
+		Maturity:        models.MaturityStable,
+		Notes:           stubNote + " Set pricing and check every field against the provider's docs.",
+	}
+	var from string
+	if t, ok := templateModel(ms, provider); ok {
+		from = t.ID
+		m.ContextWindow, m.MaxOutputTokens = t.ContextWindow, t.MaxOutputTokens
+		m.Vision, m.Reasoning, m.ToolCalling = t.Vision, t.Reasoning, t.ToolCalling
+		m.StructuredOutput, m.JSONMode, m.BatchAPI = t.StructuredOutput, t.JSONMode, t.BatchAPI
+		m.EUHosted, m.SystemPrompt = t.EUHosted, t.SystemPrompt
+		m.Notes = fmt.Sprintf("%s Limits and capabilities copied from %s; set pricing and check every field against the provider's docs.", stubNote, t.ID)
+	}
+	m.ReleaseDate = now.Format("2006-01")
+	m.Status = "current"
+	if strings.Contains(id, "preview") || strings.Contains(id, "beta") {
+		m.Maturity = models.MaturityPreview
+	}
+	return stub{m, from}
+}
+
+// templateModel picks the model a provider's stubs copy from: its default
+// model, else its most recently released current model.
+func templateModel(ms map[string]models.Model, provider string) (models.Model, bool) {
+	var best models.Model
+	found := false
+	for _, m := range ms {
+		if m.Provider != provider || m.Status != "current" {
+			continue
+		}
+		if m.ProviderDefault {
+			return m, true
+		}
+		if !found || m.ReleaseDate > best.ReleaseDate || (m.ReleaseDate == best.ReleaseDate && m.ID < best.ID) {
+			best, found = m, true
+		}
+	}
+	return best, found
+}
+
+var (
+	stubDateRe    = regexp.MustCompile(`-(?:\d{8}|\d{4}-\d{2}-\d{2}|\d{2}-\d{4})$`)
+	stubVersionRe = regexp.MustCompile(`^\d{1,2}$`)
+	stubSizeRe    = regexp.MustCompile(`^\d+(?:\.\d+)?[bkm]$`)
+)
+
+// stubBrands spells ID words the way providers write them. Words marked
+// with a trailing "-" are joined to the next word, as in "GPT-5.2".
+var stubBrands = map[string]string{
+	"gpt":      "GPT-",
+	"glm":      "GLM-",
+	"deepseek": "DeepSeek",
+	"minimax":  "MiniMax",
+	"mimo":     "MiMo",
+}
+
+// guessDisplayName turns a model ID into a display name in the registry's
+// style: "claude-opus-5-1" becomes "Claude Opus 5.1", "gpt-5.5-mini" becomes
+// "GPT-5.5 Mini", and "gemini-4-pro-preview" becomes "Gemini 4 Pro (Preview)".
+// Namespaces, snapshot dates, and "-latest" are dropped.
+func guessDisplayName(id string) string {
+	if i := strings.LastIndex(id, "/"); i >= 0 {
+		id = id[i+1:]
+	}
+	id = strings.TrimSuffix(id, "-latest")
+	id = stubDateRe.ReplaceAllString(id, "")
+	preview := false
+	if t := strings.TrimSuffix(id, "-preview"); t != id {
+		id, preview = t, true
+	}
+
+	var words []string
+	join := false
+	prevVersion := false
+	for _, w := range strings.Split(id, "-") {
+		if w == "" {
+			continue
+		}
+		version := stubVersionRe.MatchString(w)
+		switch {
+		case join:
+			words[len(words)-1] += w
+			join = false
+		case version && prevVersion:
+			words[len(words)-1] += "." + w
+		case stubBrands[w] != "":
+			words = append(words, stubBrands[w])
+			join = strings.HasSuffix(stubBrands[w], "-")
+		case stubSizeRe.MatchString(w):
+			words = append(words, strings.ToUpper(w))
+		case len(w) == 2 && w[0] == 'o' && unicode.IsDigit(rune(w[1])):
+			words = append(words, w) // OpenAI's o-series stays lowercase
+		default:
+			words = append(words, strings.ToUpper(w[:1])+w[1:])
+		}
+		prevVersion = version
+	}
+	name := strings.TrimSuffix(strings.Join(words, " "), "-")
+	if preview {
+		name += " (Preview)"
+	}
+	return name
+}
+
+// addEntries adds a stub entry for each new ID not already in the registry
+// data file and re-encodes it. It returns the new file and the stubs added,
+// sorted by provider and ID.
+func addEntries(src []byte, newByProvider map[string][]string, now time.Time) ([]byte, []stub, error) {
+	ms, err := models.Parse(src)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", dataFile, err)
+	}
+	var added []stub
+	for provider, ids := range newByProvider {
+		for _, id := range ids {
+			if _, ok := ms[id]; ok {
+				continue
+			}
+			added = append(added, stubEntry(ms, provider, id, now))
+		}
+	}
+	for _, s := range added {
+		ms[s.ID] = s.Model
+	}
+	slices.SortFunc(added, func(a, b stub) int {
+		return cmp.Or(strings.Compare(a.Provider, b.Provider), strings.Compare(a.ID, b.ID))
+	})
+	out, err := models.Encode(ms)
+	return out, added, err
+}
+
+// addKnownModel inserts id at the top of provider's block in the knownModels
+// literal, the reverse of removeKnownModel. Providers without a block are
+// left alone: the updater doesn't track them.
+func addKnownModel(src []byte, provider, id string) []byte {
+	re := regexp.MustCompile(`(var knownModels = map\[string\]map\[string\]bool\{(?s:.*?)\n\t` + regexp.QuoteMeta(strconv.Quote(provider)) + `: \{)`)
+	loc := re.FindIndex(src)
+	if loc == nil {
+		return src
+	}
+	line := "\n\t\t" + strconv.Quote(id) + ": true,"
+	return slices.Concat(src[:loc[1]], []byte(line), src[loc[1]:])
+}
+
+// stageNewModels writes stub entries for the new models into the registry
+// data file and knownModels. It returns the files it changed and the stubs.
+func stageNewModels(newByProvider map[string][]string, now time.Time) ([]string, []stub, error) {
+	data, err := os.ReadFile(dataFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	updater, err := os.ReadFile(updaterFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, added, err := addEntries(data, newByProvider, now)
+	if err != nil || len(added) == 0 {
+		return nil, nil, err
+	}
+	for _, s := range added {
+		updater = addKnownModel(updater, s.Provider, s.ID)
+	}
+	if err := os.WriteFile(dataFile, data, 0o644); err != nil {
+		return nil, nil, err
+	}
+	if err := os.WriteFile(updaterFile, updater, 0o644); err != nil {
+		return nil, nil, err
+	}
+	return []string{dataFile, updaterFile}, added, nil
+}
+
+// newModelsPRBody lists the generated stubs by provider with what was
+// guessed, and the checklist for finishing them.
+func newModelsPRBody(added []stub, fp string) string {
+	var b strings.Builder
+	b.WriteString("## New models (generated entries)\n\n")
+	b.WriteString("The updater found these model IDs in provider documentation or APIs and wrote stub entries for them. ")
+	b.WriteString("Display names are guessed from the ID, limits and capabilities are copied from another model of the same provider, and pricing is zero. ")
+	b.WriteString("Edit the entries on this branch, then mark the pull request ready.\n\n")
+	b.WriteString("| Provider | ID | Guessed name | Copied from |\n|----------|----|--------------|-------------|\n")
+	for _, s := range added {
+		from := "defaults"
+		if s.From != "" {
+			from = "`" + s.From + "`"
+		}
+		fmt.Fprintf(&b, "| %s | `%s` | %s | %s |\n", s.Provider, s.ID, s.DisplayName, from)
+	}
+	b.WriteString("\n### Before merging\n\n")
+	b.WriteString("- [ ] Set pricing, limits, capabilities, dates, and docs_url for each entry in `go-server/internal/models/models.json`\n")
+	b.WriteString("- [ ] Replace each `" + stubNote + "` note with a real one\n")
+	b.WriteString("- [ ] Update `TestTotalModelCount` and `TestProviderCounts` in `data_test.go`\n")
+	b.WriteString("- [ ] Run `go test ./... -v` to verify\n")
+	if runProvenance != nil {
+		b.WriteString(runProvenance.section())
+	}
+	b.WriteString("\n<!-- fingerprint:" + fp + " -->\n")
+	return b.String()
+}
+
+// openNewModelsPR stages stub entries for the new models, pushes them on a
+// new branch to each host's git remote, and opens a draft pull request on
+// each host that has no open issue or pull request for the same models yet.
+// With no host configured the entries are only staged. It reports false when no entries were generated, so the
+// caller can fall back to the new-models issue.
+func openNewModelsPR(ctx context.Context, hosts []forge.Forge, newByProvider map[string][]string, now time.Time) (bool, error) {
+	var all []string
+	for _, ids := range newByProvider {
+		all = append(all, ids...)
+	}
+	fp := fingerprintModels(all)
+	var open []forge.Forge
+	for _, host := range hosts {
+		if host == nil {
+			continue
+		}
+		if existingIssueWithFingerprint(ctx, host, fp) {
+			fmt.Printf("[%s] Existing open issue already covers these new models (fingerprint match), skipping.\n", host.Name())
+			continue
+		}
+		if existingPullRequestWithFingerprint(ctx, host, fp) {
+			fmt.Printf("[%s] Existing open pull request already covers these new models (fingerprint match), skipping.\n", host.Name())
+			continue
+		}
+		open = append(open, host)
+	}
+	if len(open) == 0 && len(hosts) > 0 {
+		return true, nil
+	}
+
+	files, added, err := stageNewModels(newByProvider, now)
+	if err != nil || len(added) == 0 {
+		return false, err
+	}
+	if len(open) == 0 {
+		fmt.Printf("Staged %d stub entries in %s. No forge is configured; edit them and open a pull request by hand.\n", len(added), strings.Join(files, ", "))
+		return true, nil
+	}
+	branch := "updater/new-models-" + now.UTC().Format("20060102-150405")
+	title := fmt.Sprintf("Add %d new model(s) - %s", len(added), now.Format("2006-01-02"))
+	open, err = pushToHosts(ctx, open, branch, title, files)
+	if err != nil {
+		return false, err
+	}
+	body := newModelsPRBody(added, fp)
+	forEachHost(open, func(host forge.Forge) {
+		pr, err := host.CreatePullRequest(ctx, branch, reviewBase(), title, body, []string{"auto-update"}, true)
+		switch {
+		case pr != nil && err != nil:
+			fmt.Printf("[%s] Draft pull request opened: %s (not labelled: %v)\n", host.Name(), pr.URL, err)
+		case err != nil:
+			fmt.Printf("[%s] Failed to open draft pull request: %v\n", host.Name(), err)
+		default:
+			fmt.Printf("[%s] Draft pull request opened: %s\n", host.Name(), pr.URL)
+		}
+	})
+	return true, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// defaultMaxBatchModels caps how many missing models one deprecation issue
+// lists. Override with UPDATER_MAX_BATCH_MODELS.
+const defaultMaxBatchModels = 10
+
+// verificationLabel is added to deprecation issues that look like a scrape
+// failure or had to be split, so nobody bulk-deprecates from them.
+const verificationLabel = "requires human verification"
+
+// missingBatch is one provider's missing models, or one chunk of them when
+// the provider has more than the batch cap.
+type missingBatch struct {
+	Provider string
+	IDs      []string
+	Tracked  int // models we track for the provider
+	Missing  int // total missing for the provider, across all parts
+	Part     int
+	Parts    int
+}
+
+// maxBatchModels returns the per-issue model cap from the environment,
+// falling back to defaultMaxBatchModels for unset or invalid values.
+func maxBatchModels() int {
+	if n, err := strconv.Atoi(os.Getenv("UPDATER_MAX_BATCH_MODELS")); err == nil && n > 0 {
+		return n
+	}
+	return defaultMaxBatchModels
+}
+
+// splitMissingBatches groups missing model IDs by provider and splits each
+// provider into chunks of at most max IDs. Providers and IDs are sorted so
+// the batches (and their fingerprints) are stable between runs.
+func splitMissingBatches(byProvider map[string][]string, tracked map[string]int, max int) []missingBatch {
+	var providers []string
+	for p, ids := range byProvider {
+		if len(ids) > 0 {
+			providers = append(providers, p)
+		}
+	}
+	sort.Strings(providers)
+
+	var batches []missingBatch
+	for _, p := range providers {
+		ids := append([]string(nil), byProvider[p]...)
+		sort.Strings(ids)
+		parts := (len(ids) + max - 1) / max
+		for i := 0; i < parts; i++ {
+			end := min((i+1)*max, len(ids))
+			batches = append(batches, missingBatch{
+				Provider: p,
+				IDs:      ids[i*max : end],
+				Tracked:  tracked[p],
+				Missing:  len(ids),
+				Part:     i + 1,
+				Parts:    parts,
+			})
+		}
+	}
+	return batches
+}
+
+// confidence estimates how likely the batch's models were really deprecated.
+// When a large share of a provider's models vanish at once it is usually
+// the scraper that broke, not a mass deprecation.
+func (b missingBatch) confidence() (level, note string) {
+	share := 0.0
+	if b.Tracked > 0 {
+		share = float64(b.Missing) / float64(b.Tracked)
+	}
+	switch {
+	case share >= 0.5:
+		return "low", fmt.Sprintf("%d of %d tracked %s models missing at once — likely a scrape failure", b.Missing, b.Tracked, b.Provider)
+	case share >= 0.25 || b.Parts > 1:
+		return "medium", fmt.Sprintf("%d of %d tracked %s models missing — check the docs page still lists models", b.Missing, b.Tracked, b.Provider)
+	default:
+		return "high", "isolated removal from provider docs"
+	}
+}
+
+// needsVerification reports whether the batch should carry verificationLabel.
+func (b missingBatch) needsVerification() bool {
+	level, _ := b.confidence()
+	return level != "high"
+}
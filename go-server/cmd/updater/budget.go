@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// defaultRunBudget bounds the whole run's fetching, so a CI job can't hang
+// when several providers slow-walk responses. Providers not reached in time
+// are reported as skipped; issues are still filed for the rest. Override
+// with UPDATER_RUN_BUDGET (a Go duration, e.g. 5m).
+const defaultRunBudget = 8 * time.Minute
+
+// defaultProviderTimeout bounds one provider's fetches, including retries
+// and the fall back from API to docs. Override with UPDATER_PROVIDER_TIMEOUT.
+const defaultProviderTimeout = 90 * time.Second
+
+// runBudget returns the run budget from the environment or the default.
+func runBudget() time.Duration {
+	return durationEnv("UPDATER_RUN_BUDGET", defaultRunBudget)
+}
+
+// providerTimeout returns the per-provider timeout from the environment or
+// the default.
+func providerTimeout() time.Duration {
+	return durationEnv("UPDATER_PROVIDER_TIMEOUT", defaultProviderTimeout)
+}
+
+// durationEnv parses a positive duration from key, falling back to def for
+// unset or invalid values.
+func durationEnv(key string, def time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(key)); err == nil && d > 0 {
+		return d
+	}
+	return def
+}
+
+// sleepCtx waits for d or until ctx is done, and reports whether the full
+// wait elapsed. Retry backoff uses it so an expired budget stops retries at
+// once instead of sleeping through them.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
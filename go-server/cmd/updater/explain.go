@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"strconv"
+
+	"go-server/internal/models"
+)
+
+// filteredID is a scraped ID that diff did not report as new, and why.
+type filteredID struct {
+	ID     string
+	Reason string
+}
+
+// filterReason explains why a scraped id that is not itself tracked is kept
+// out of the NEW list, or returns "" if it is a genuinely new model.
+func filterReason(id string, known map[string]bool) string {
+	if isDateStampVariant(id) {
+		return "date-stamped snapshot"
+	}
+	if reason := aliasReason(id, known); reason != "" {
+		return reason
+	}
+	if m, ok := models.Models[id]; ok {
+		return "already in the registry (" + m.Status + ")"
+	}
+	return ""
+}
+
+// explainFiltered reports whether the run report should list every filtered
+// ID with its reason (UPDATER_EXPLAIN_FILTERED=true). It is off by default
+// because providers list many snapshots and aliases.
+func explainFiltered() bool {
+	on, _ := strconv.ParseBool(os.Getenv("UPDATER_EXPLAIN_FILTERED"))
+	return on
+}
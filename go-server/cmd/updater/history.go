@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-server/internal/forge"
+)
+
+// defaultHistoryFile stores per-provider scrape counts between runs.
+// Override with UPDATER_HISTORY_FILE (point it at a persistent volume in
+// containers, or the history resets every run).
+const defaultHistoryFile = "updater-history.json"
+
+// defaultRotThreshold is the fraction of the trailing average below which a
+// scrape count is treated as pattern rot. Override with UPDATER_ROT_THRESHOLD.
+const defaultRotThreshold = 0.5
+
+// historyWindow is how many runs are kept per provider source, and
+// minHistoryRuns how many are needed before rot detection kicks in.
+const (
+	historyWindow  = 14
+	minHistoryRuns = 3
+)
+
+// scraperHealthLabel marks issues about broken scrapers rather than models.
+const scraperHealthLabel = "scraper-health"
+
+// countSample is one run's scraped ID count.
+type countSample struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// scrapeHistory holds recent counts keyed by "<provider>/<source>", where
+// source is "api" or "docs" — the two return very different counts, so they
+// are tracked separately.
+type scrapeHistory struct {
+	Counts map[string][]countSample `json:"counts"`
+}
+
+// rotAlert is a provider whose scrape count collapsed against its history.
+type rotAlert struct {
+	Key     string
+	Count   int
+	Average float64
+	Runs    int
+}
+
+// historyFile returns the history path from the environment or the default.
+func historyFile() string {
+	if p := os.Getenv("UPDATER_HISTORY_FILE"); p != "" {
+		return p
+	}
+	return defaultHistoryFile
+}
+
+// rotThreshold returns the rot threshold from the environment, falling back
+// to defaultRotThreshold for unset or out-of-range values.
+func rotThreshold() float64 {
+	if f, err := strconv.ParseFloat(os.Getenv("UPDATER_ROT_THRESHOLD"), 64); err == nil && f > 0 && f < 1 {
+		return f
+	}
+	return defaultRotThreshold
+}
+
+// loadHistory reads the history file. A missing file is an empty history.
+func loadHistory(path string) (*scrapeHistory, error) {
+	h := &scrapeHistory{Counts: make(map[string][]countSample)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return h, err
+	}
+	if err := json.Unmarshal(data, h); err != nil {
+		return &scrapeHistory{Counts: make(map[string][]countSample)}, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if h.Counts == nil {
+		h.Counts = make(map[string][]countSample)
+	}
+	return h, nil
+}
+
+// save writes the history file.
+func (h *scrapeHistory) save(path string) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// trailingAverage returns the mean count over the stored runs for key,
+// ignoring any sample from date (so a rerun on the same day doesn't compare
+// against itself).
+func (h *scrapeHistory) trailingAverage(key, date string) (avg float64, runs int) {
+	total := 0
+	for _, s := range h.Counts[key] {
+		if s.Date == date {
+			continue
+		}
+		total += s.Count
+		runs++
+	}
+	if runs == 0 {
+		return 0, 0
+	}
+	return float64(total) / float64(runs), runs
+}
+
+// record stores today's count for key, replacing an earlier sample from the
+// same date and keeping only the last historyWindow runs.
+func (h *scrapeHistory) record(key, date string, count int) {
+	samples := h.Counts[key]
+	if n := len(samples); n > 0 && samples[n-1].Date == date {
+		samples = samples[:n-1]
+	}
+	samples = append(samples, countSample{Date: date, Count: count})
+	if len(samples) > historyWindow {
+		samples = samples[len(samples)-historyWindow:]
+	}
+	h.Counts[key] = samples
+}
+
+// patternRotted reports whether count collapsed below threshold times the
+// trailing average. A drop this sharp is almost always the page layout or
+// extraction pattern changing, not a provider retiring most of its lineup.
+// Smaller drops are left to the normal missing-model report.
+func patternRotted(count int, avg float64, runs int, threshold float64) bool {
+	return runs >= minHistoryRuns && float64(count) < threshold*avg
+}
+
+// createScraperRotIssue opens one issue listing every provider source whose
+// scrape count collapsed. Deduplicated by fingerprint over the affected
+// sources, so a scraper that stays broken doesn't open an issue a day.
+func createScraperRotIssue(ctx context.Context, host forge.Forge, alerts []rotAlert, threshold float64, reportBody string) {
+	if host == nil || len(alerts) == 0 {
+		return
+	}
+
+	keys := make([]string, len(alerts))
+	for i, a := range alerts {
+		keys[i] = "rot:" + a.Key
+	}
+	fp := fingerprintModels(keys)
+	if existingIssueWithFingerprint(ctx, host, fp) {
+		fmt.Printf("[%s] Existing open issue already covers these scraper failures (fingerprint match), skipping.\n", host.Name())
+		return
+	}
+
+	body := scraperRotIssueBody(alerts, threshold, reportBody, fp)
+	var providers []string
+	for _, a := range alerts {
+		provider, _, _ := strings.Cut(a.Key, "/")
+		if !slices.Contains(providers, provider) {
+			providers = append(providers, provider)
+		}
+	}
+	body = withSnapshots(body, providers...)
+	title := "Scraper pattern may be broken - " + time.Now().Format("2006-01-02")
+	createIssue(ctx, host, title, body, scraperHealthLabel)
+}
+
+// scraperRotIssueBody renders the issue body for createScraperRotIssue.
+func scraperRotIssueBody(alerts []rotAlert, threshold float64, reportBody, fp string) string {
+	var body strings.Builder
+	body.WriteString("## Scraper Pattern Rot\n\n")
+	body.WriteString(fmt.Sprintf("These sources returned fewer than %.0f%% of their trailing average model count. ", threshold*100))
+	body.WriteString("That usually means the docs page changed or the extraction pattern stopped matching, not that the provider removed models. ")
+	body.WriteString("Their diffs were skipped, so no deprecation issues were opened for them.\n\n")
+	body.WriteString("| Source | Today | Trailing average | Runs |\n|---|---|---|---|\n")
+	for _, a := range alerts {
+		body.WriteString(fmt.Sprintf("| `%s` | %d | %.1f | %d |\n", a.Key, a.Count, a.Average, a.Runs))
+	}
+	body.WriteString("\n### Action Items\n\n")
+	body.WriteString("- [ ] Open each docs page and check the model IDs are still present\n")
+	body.WriteString("- [ ] Update the provider's source URL or pattern in `providerSources` in `go-server/cmd/updater/main.go`\n")
+	body.WriteString("- [ ] Add a regression test with a fixture of the new page layout\n")
+	body.WriteString("\n<details>\n<summary>Full update report</summary>\n\n```\n")
+	body.WriteString(reportBody)
+	body.WriteString("\n```\n</details>\n")
+	body.WriteString("\n<!-- fingerprint:" + fp + " -->\n")
+	return body.String()
+}
@@ -1,26 +1,31 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"crypto/sha256"
 	_ "embed"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
 	"os"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"go-server/internal/forge"
+	"go-server/internal/modelid"
 	"go-server/internal/models"
 )
 
-// DocSource describes a public documentation page to scrape for model IDs.
-// No API keys needed — these are all publicly accessible pages.
+// DocSource describes a public documentation page to scrape for model IDs
+// with a regex: the ProviderSource most providers use. No API keys needed —
+// these are all publicly accessible pages.
 type DocSource struct {
 	URLs           []string                // URLs to try in order (fallbacks)
 	Pattern        *regexp.Regexp          // Regex to extract model IDs from page content
@@ -29,6 +34,24 @@ type DocSource struct {
 	NormalizeRe    *regexp.Regexp          // Optional: normalize extracted IDs (regex)
 	NormalizeRepl  string                  // Replacement for NormalizeRe
 	NormalizeFunc  func(string) string     // Optional: custom normalization function applied after NormalizeRe
+	MarkdownURLs   []string                // Optional: raw markdown/MDX docs (e.g. raw.githubusercontent.com), tried before URLs
+	IDColumn       *regexp.Regexp          // Header of the table column holding API model IDs (MarkdownURLs, TableSource)
+	OfferURLs      []string                // Optional: AWS Price List offer files (JSON), tried before MarkdownURLs
+	OfferAttribute string                  // Product attribute of the OfferURLs naming the model
+	MaxBodyBytes   int64                   // Optional: page size cap; 0 uses defaultMaxBodyBytes
+}
+
+// defaultMaxBodyBytes caps how much of a page the updater reads. Pages
+// larger than their source's cap are truncated, and IDs listed past the
+// cutoff are missed, so each truncation is logged.
+const defaultMaxBodyBytes int64 = 2 << 20
+
+// bodyLimit returns the source's page size cap.
+func (src DocSource) bodyLimit() int64 {
+	if src.MaxBodyBytes > 0 {
+		return src.MaxBodyBytes
+	}
+	return defaultMaxBodyBytes
 }
 
 // normalizeMistralID converts Mistral's long-form versioned API names to our
@@ -132,33 +155,41 @@ func applyNormalization(provider string, ids []string) []string {
 	return result
 }
 
-// docSources maps provider name to its public documentation source.
-// Each entry contains public URLs and a regex pattern to extract model IDs.
-var docSources = map[string]DocSource{
-	"OpenAI": {
-		URLs: []string{
-			"https://raw.githubusercontent.com/openai/openai-python/main/src/openai/types/shared/chat_model.py",
-			"https://cdn.jsdelivr.net/gh/openai/openai-python@main/src/openai/types/shared/chat_model.py",
-			"https://raw.githubusercontent.com/openai/openai-python/main/src/openai/types/shared/all_models.py",
-			"https://cdn.jsdelivr.net/gh/openai/openai-python@main/src/openai/types/shared/all_models.py",
+// providerSources maps provider name to its public model listing: a docs
+// page scraped with a regex (DocSource), an HTML table (TableSource), or a
+// file in the provider's GitHub repository (GitHubFileSource). Providers with
+// a model-list API try it first when its key is set; see sourcesFor.
+var providerSources = map[string]ProviderSource{
+	"OpenAI": GitHubFileSource{
+		Repo: "openai/openai-python",
+		Paths: []string{
+			"src/openai/types/shared/chat_model.py",
+			"src/openai/types/shared/all_models.py",
+		},
+		DocSource: DocSource{
+			Pattern:        regexp.MustCompile(`(?:"|')((?:gpt-[0-9][a-z0-9._-]*|o[0-9](?:-[a-z0-9-]+)*))`),
+			ExcludePattern: regexp.MustCompile(`^gpt-(?:3\.|4o?(?:-|$))|^o1(?:-|$)`),
 		},
-		Pattern:        regexp.MustCompile(`(?:"|')((?:gpt-[0-9][a-z0-9._-]*|o[0-9](?:-[a-z0-9-]+)*))`),
-		ExcludePattern: regexp.MustCompile(`^gpt-(?:3\.|4o?(?:-|$))|^o1(?:-|$)`),
 	},
-	"Anthropic": {
+	"Anthropic": DocSource{
 		URLs: []string{
 			"https://docs.anthropic.com/en/docs/about-claude/models",
 		},
 		Pattern: regexp.MustCompile(`(claude-(?:opus|sonnet|haiku)-[0-9]+(?:-[0-9]+)*(?:-[0-9]{8})?)`),
 	},
-	"Google": {
+	"Google": DocSource{
 		URLs: []string{
 			"https://ai.google.dev/gemini-api/docs/models",
 		},
 		Pattern:        regexp.MustCompile(`(gemini-[0-9]+\.?[0-9]*-(?:pro|pro-image|flash|flash-lite)(?:-preview)?)`),
 		ExcludePattern: regexp.MustCompile(`^gemini-[0-9]+-(?:pro|flash)$`),
+		MaxBodyBytes:   8 << 20, // the models page inlines every model card and sits near 2MB
 	},
-	"Mistral": {
+	"Mistral": DocSource{
+		MarkdownURLs: []string{
+			"https://raw.githubusercontent.com/mistralai/platform-docs-public/main/docs/getting-started/models/models_overview.md",
+		},
+		IDColumn: regexp.MustCompile(`(?i)api endpoint`),
 		URLs: []string{
 			"https://docs.mistral.ai/getting-started/models/models_overview/",
 			"https://docs.mistral.ai/getting-started/models/",
@@ -167,7 +198,7 @@ var docSources = map[string]DocSource{
 		ExcludePattern: regexp.MustCompile(`(?:embed|moderation|ocr|nemo)`),
 		NormalizeFunc:  normalizeMistralID,
 	},
-	"xAI": {
+	"xAI": DocSource{
 		URLs: []string{
 			"https://docs.x.ai/docs/models",
 		},
@@ -176,14 +207,14 @@ var docSources = map[string]DocSource{
 		NormalizeRe:    regexp.MustCompile(`(\d)-(\d)([^0-9]|$)`),
 		NormalizeRepl:  "${1}.${2}${3}",
 	},
-	"DeepSeek": {
+	"DeepSeek": DocSource{
 		URLs: []string{
 			"https://api-docs.deepseek.com/quick_start/pricing",
 			"https://api-docs.deepseek.com/",
 		},
 		Pattern: regexp.MustCompile(`(deepseek-(?:chat|reasoner|r1|coder|v[0-9]+))`),
 	},
-	"Zhipu": {
+	"Zhipu": DocSource{
 		URLs: []string{
 			"https://docs.z.ai/guides/overview/pricing",
 		},
@@ -191,7 +222,21 @@ var docSources = map[string]DocSource{
 		ExcludePattern: regexp.MustCompile(`(?i)^glm-4(?:\.[0-6])?(?:-|$)`),
 		Lowercase:      true,
 	},
-	"MiniMax": {
+	"Amazon": DocSource{
+		// Bedrock has no public model list page, but its Price List offer
+		// names every model AWS bills for. Only Amazon's own text models
+		// are tracked; media models and other vendors' Bedrock listings
+		// don't match the pattern or are excluded.
+		OfferURLs: []string{
+			"https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AmazonBedrock/current/us-east-1/index.json",
+		},
+		OfferAttribute: "model",
+		Pattern:        regexp.MustCompile(`^(?:Amazon )?(Nova(?: [0-9]+(?:\.[0-9]+)?)? [A-Za-z]+)\b`),
+		ExcludePattern: regexp.MustCompile(`(?i)(?:canvas|reel|sonic|embed|multimodal)`),
+		NormalizeFunc:  normalizeBedrockID,
+		MaxBodyBytes:   64 << 20, // the offer lists every SKU in the region
+	},
+	"MiniMax": DocSource{
 		URLs: []string{
 			"https://platform.minimax.io/docs/guides/models-intro",
 			"https://intl.minimaxi.com/",
@@ -200,6 +245,29 @@ var docSources = map[string]DocSource{
 		ExcludePattern: regexp.MustCompile(`(?i)^minimax-m1(?:-|$)`),
 		Lowercase:      true,
 	},
+	// Cohere and Perplexity list models in tables whose other columns name
+	// context sizes, endpoints, and retired models, so only the ID column
+	// is read.
+	"Cohere": TableSource{DocSource{
+		URLs: []string{
+			"https://docs.cohere.com/docs/models",
+		},
+		IDColumn:       regexp.MustCompile(`(?i)^model name$`),
+		Pattern:        regexp.MustCompile(`(command-[a-z0-9-]+)`),
+		ExcludePattern: regexp.MustCompile(`(?:light|nightly)`),
+		Lowercase:      true,
+	}},
+	"Perplexity": TableSource{DocSource{
+		URLs: []string{
+			"https://docs.perplexity.ai/getting-started/pricing",
+			"https://docs.perplexity.ai/getting-started/models",
+		},
+		IDColumn:      regexp.MustCompile(`(?i)^model$`),
+		Pattern:       regexp.MustCompile(`(?i)(sonar(?:[- ](?:pro|reasoning|deep[- ]research))*)`),
+		NormalizeRe:   regexp.MustCompile(` `),
+		NormalizeRepl: "-",
+		Lowercase:     true,
+	}},
 }
 
 // knownModels maps provider -> set of model IDs we track in the registry.
@@ -346,16 +414,6 @@ var knownModels = map[string]map[string]bool{
 	},
 }
 
-var apiEndpoints = map[string]struct {
-	URL    string
-	EnvKey string
-}{
-	"OpenAI":   {URL: "https://api.openai.com/v1/models", EnvKey: "OPENAI_API_KEY"},
-	"DeepSeek": {URL: "https://api.deepseek.com/models", EnvKey: "DEEPSEEK_API_KEY"},
-	"Mistral":  {URL: "https://api.mistral.ai/v1/models", EnvKey: "MISTRAL_API_KEY"},
-	"xAI":      {URL: "https://api.x.ai/v1/models", EnvKey: "XAI_API_KEY"},
-}
-
 const maxRetries = 3
 
 func main() {
@@ -364,12 +422,14 @@ func main() {
 
 	hasChanges := false
 	hasErrors := false
-	providerOrder := []string{"OpenAI", "Anthropic", "Google", "Mistral", "xAI", "DeepSeek", "Zhipu", "MiniMax"}
+	providerOrder := []string{"OpenAI", "Anthropic", "Google", "Mistral", "xAI", "DeepSeek", "Zhipu", "MiniMax", "Amazon", "Cohere", "Perplexity"}
 
-	// Capture report output for GitHub issue creation.
+	// Capture report output for issue creation.
 	var report strings.Builder
-	var allMissing []string
+	missingByProvider := make(map[string][]string)
+	newByProvider := make(map[string][]string)
 	var allNew []string
+	var rotAlerts []rotAlert
 
 	logf := func(format string, args ...any) {
 		line := fmt.Sprintf(format, args...)
@@ -377,53 +437,118 @@ func main() {
 		report.WriteString(line)
 	}
 
+	// snapshot saves the pages behind an anomaly so the page state that
+	// caused it can be replayed when the pattern is fixed.
+	snapshot := func(provider, reason string, urls ...string) {
+		for _, path := range runSnapshots.capture(provider, reason, urls...) {
+			logf("[%s] Saved page snapshot to %s\n", provider, path)
+		}
+	}
+
+	started := time.Now()
+	runProvenance = newProvenance(started)
+	runSnapshots = newSnapshotStore()
+
 	logf("=== Model Registry Update Check ===\n")
-	logf("Time: %s\n\n", time.Now().UTC().Format(time.RFC3339))
+	logf("Time: %s\n", started.UTC().Format(time.RFC3339))
+	logf("Run: %s (extraction rules %s)\n\n", runProvenance.RunID, runProvenance.PatternVersion)
+
+	// Fetching runs under the run budget; filing issues afterwards does not,
+	// so a run that ran out of time still reports what it found.
+	budget, perProvider := runBudget(), providerTimeout()
+	fetchCtx, cancelFetch := context.WithTimeout(ctx, budget)
+	defer cancelFetch()
+	var skipped []string
+	explain := explainFiltered()
+
+	today := time.Now().UTC().Format("2006-01-02")
+	threshold := rotThreshold()
+	history, err := loadHistory(historyFile())
+	if err != nil {
+		logf("WARNING: could not read scrape history (%v); pattern rot checks start fresh\n\n", err)
+	}
+	syncState, err := loadSyncStatus(statusFile())
+	if err != nil {
+		logf("WARNING: could not read previous sync status (%v); last-verified times start fresh\n\n", err)
+	}
+
+	// Providers without scrapable docs are checked through their model-list
+	// API when a key is set.
+	for _, u := range undocumented {
+		if _, _, ok := modelsAPIFor(u.Name); ok {
+			providerOrder = append(providerOrder, u.Name)
+		}
+	}
 
 	for _, name := range providerOrder {
-		src, ok := docSources[name]
-		if !ok {
-			logf("[%s] SKIP: no doc source configured\n", name)
+		sources := sourcesFor(name)
+		if len(sources) == 0 {
+			logf("[%s] SKIP: no source configured\n", name)
 			continue
 		}
-
-		var ids []string
-		var err error
-
-		// Try API first if endpoint and key are configured
-		if ep, ok := apiEndpoints[name]; ok {
-			if key := os.Getenv(ep.EnvKey); key != "" {
-				ids, err = fetchModelsFromAPI(ctx, client, ep.URL, key)
-				if err == nil && len(ids) > 0 {
-					logf("[%s] Fetched %d models via API\n", name, len(ids))
-				} else {
-					if err != nil {
-						logf("[%s] API fetch failed (%v), falling back to docs scraping\n", name, err)
-					}
-					ids = nil
-					err = nil
-				}
-			}
+		if fetchCtx.Err() != nil {
+			logf("[%s] SKIP: skipped due to budget (run budget %s exhausted)\n", name, budget)
+			syncState.failed(name, time.Now(), "skipped due to budget")
+			skipped = append(skipped, name)
+			hasErrors = true
+			continue
+		}
+		pctx, cancel := context.WithTimeout(fetchCtx, perProvider)
+
+		// The provider's model-list API comes first when a key is set, then
+		// its configured source. API IDs get the same cleanup as scraped ones.
+		ids, fetched, err := fetchFromSources(pctx, client, name, sources, logf)
+		switch {
+		case err == nil:
+		case fetchCtx.Err() != nil:
+			err = fmt.Errorf("run budget %s exhausted: %w", budget, err)
+		case pctx.Err() != nil:
+			err = fmt.Errorf("provider timeout %s exceeded: %w", perProvider, err)
+		}
+		cancel()
+		if err != nil {
+			logf("[%s] ERROR: %v\n", name, err)
+			snapshot(name, "fetch failed", allLocations(sources)...)
+			syncState.failed(name, time.Now(), err.Error())
+			hasErrors = true
+			continue
+		}
+		source := "docs"
+		if fetched.Kind == "api" {
+			source = "api"
 		}
 
-		// Fall back to HTML scraping
-		if len(ids) == 0 {
-			ids, err = fetchModelsFromDocs(ctx, client, src)
-			if err != nil {
-				logf("[%s] ERROR: %v\n", name, err)
-				hasErrors = true
-				continue
-			}
+		if fetched.Truncated {
+			logf("[%s] WARNING: %s exceeded the %s-byte page limit and was truncated; IDs listed past the cutoff were missed and may show as MISSING. Raise MaxBodyBytes for this source.\n",
+				name, fetched.URL, models.FormatInt(int(sourceLimit(providerSources[name]))))
 		}
 
 		ids = applyNormalization(name, ids)
+		fetched.Provider, fetched.IDs = name, len(ids)
+		runProvenance.record(fetched)
 
 		known := knownModels[name]
 
+		// Pattern rot: a count far below this source's own history means the
+		// page or pattern changed, not that the provider removed models.
+		historyKey := name + "/" + source
+		avg, runs := history.trailingAverage(historyKey, today)
+		if patternRotted(len(ids), avg, runs, threshold) {
+			logf("[%s] PATTERN ROT: %s returned %d model IDs vs trailing average %.1f over %d runs (threshold %.0f%%). Page or pattern likely changed. Skipping diff.\n\n",
+				name, source, len(ids), avg, runs, threshold*100)
+			rotAlerts = append(rotAlerts, rotAlert{Key: historyKey, Count: len(ids), Average: avg, Runs: runs})
+			snapshot(name, "pattern rot", fetched.URL)
+			syncState.failed(name, time.Now(), fmt.Sprintf("pattern rot: %d IDs vs trailing average %.1f", len(ids), avg))
+			hasErrors = true
+			continue
+		}
+
 		// Circuit breaker: if scraper returns 0 models but we track >0,
 		// the scraper likely failed silently (anti-bot, page restructure).
 		if len(ids) == 0 && len(known) > 0 {
 			logf("[%s] CIRCUIT BREAKER: scraper returned 0 models but we track %d. Skipping diff.\n", name, len(known))
+			snapshot(name, "circuit breaker", fetched.URL)
+			syncState.failed(name, time.Now(), "circuit breaker: scraper returned 0 models")
 			continue
 		}
 
@@ -432,23 +557,39 @@ func main() {
 			logf("[%s] WARNING: scraped only %d models vs %d tracked. Results may be incomplete.\n", name, len(ids), len(known))
 		}
 
-		newModels, missing := diff(known, ids)
+		history.record(historyKey, today, len(ids))
+		newModels, missing, filtered := diffExplained(known, ids)
+		newModels, missing, muted := mutes.filter(name, newModels, missing)
 
-		logf("[%s] Docs returned %d model IDs, we track %d\n", name, len(ids), len(known))
+		sourceLabel := "Docs"
+		if source == "api" {
+			sourceLabel = "API"
+		}
+		logf("[%s] %s returned %d model IDs, we track %d\n", name, sourceLabel, len(ids), len(known))
+		if muted > 0 {
+			logf("  Muted in review: %d ID(s) (see muted.json)\n", muted)
+		}
+		if runs >= minHistoryRuns && float64(len(ids)) < avg && len(missing) > 0 {
+			logf("  Count down from trailing average %.1f; above the rot threshold, so treating missing IDs as real removals\n", avg)
+		}
 
 		if len(newModels) > 0 {
 			hasChanges = true
 			sort.Strings(newModels)
 			allNew = append(allNew, newModels...)
+			newByProvider[name] = newModels
 			logf("  NEW (%d):\n", len(newModels))
 			for _, m := range newModels {
 				logf("    + %s\n", m)
 			}
 		}
+		if level, _ := (missingBatch{Provider: name, Tracked: len(known), Missing: len(missing)}).confidence(); level == "low" {
+			snapshot(name, "mass removal", fetched.URL)
+		}
 		if len(missing) > 0 {
 			hasChanges = true
 			sort.Strings(missing)
-			allMissing = append(allMissing, missing...)
+			missingByProvider[name] = missing
 			logf("  MISSING from docs (%d):\n", len(missing))
 			for _, m := range missing {
 				logf("    - %s\n", m)
@@ -457,34 +598,235 @@ func main() {
 		if len(newModels) == 0 && len(missing) == 0 {
 			logf("  OK: in sync\n")
 		}
+		if explain && len(filtered) > 0 {
+			logf("  FILTERED (%d):\n", len(filtered))
+			for _, f := range filtered {
+				logf("    ~ %s: %s\n", f.ID, f.Reason)
+			}
+		}
+		syncState.verified(name, time.Now(), source, len(ids), newModels, missing)
 		logf("\n")
 	}
 
-	// Providers without scrapable documentation — just note them.
-	logf("[Meta] SKIP: no scrapable model listing (models are provider-hosted)\n")
-	logf("[Amazon] SKIP: no scrapable model listing (check AWS Bedrock console)\n")
-	logf("[Cohere] SKIP: no scrapable model listing (check docs.cohere.com)\n")
-	logf("[Perplexity] SKIP: no scrapable model listing (check docs.perplexity.ai)\n")
-	logf("[AI21] SKIP: no scrapable model listing (check docs.ai21.com)\n")
-	logf("[Moonshot] SKIP: no scrapable model listing (check platform.moonshot.cn)\n")
-	logf("[NVIDIA] SKIP: no scrapable model listing (check build.nvidia.com)\n")
-	logf("[Tencent] SKIP: no scrapable model listing (check cloud.tencent.com/product/hunyuan)\n")
-	logf("[Microsoft] SKIP: no scrapable model listing (check ai.azure.com)\n")
-	logf("[Xiaomi] SKIP: no scrapable model listing (check platform.xiaomimimo.com)\n")
-	logf("[Kuaishou] SKIP: no scrapable model listing (check kwaipilot.com)\n")
+	// The aggregator catalog: a cross-check for providers without scrapable
+	// documentation or an API key, and a directory of untracked providers.
+	var catalog []aggregatorModel
+	if fetchCtx.Err() != nil {
+		logf("[OpenRouter] SKIP: catalog skipped due to budget\n")
+	} else if catalog, err = fetchAggregatorCatalog(fetchCtx, client, aggregatorModelsURL); err != nil {
+		logf("[OpenRouter] WARNING: could not fetch catalog (%v)\n", err)
+	} else {
+		runProvenance.record(sourceRecord{Provider: "OpenRouter", Kind: "aggregator", URL: aggregatorModelsURL, IDs: len(catalog)})
+	}
+
+	// Providers without scrapable documentation and no API key are checked
+	// against the catalog. It lags the providers and doesn't host every
+	// model, so IDs it lacks are only listed, never reported as removed.
+	for _, u := range undocumented {
+		if slices.Contains(providerOrder, u.Name) {
+			continue // checked through its API above
+		}
+		known := knownModels[u.Name]
+		ids, older := aggregatorIDs(catalog, u.Name, known)
+		ids = applyNormalization(u.Name, ids)
+		if len(ids) == 0 {
+			if api, ok := modelsAPIs[u.Name]; ok {
+				logf("[%s] SKIP: no scrapable model listing (%s); set %s to check via API\n", u.Name, u.Hint, api.EnvKeys[0])
+			} else {
+				logf("[%s] SKIP: no scrapable model listing (%s)\n", u.Name, u.Hint)
+			}
+			continue
+		}
+		runProvenance.record(sourceRecord{Provider: u.Name, Kind: "aggregator", URL: aggregatorModelsURL, IDs: len(ids)})
+		newModels, unlisted, filtered := diffExplained(known, ids)
+		filtered = append(filtered, older...)
+		newModels, _, muted := mutes.filter(u.Name, newModels, nil)
+		logf("[%s] OpenRouter lists %d model IDs, we track %d (cross-check; %s)\n", u.Name, len(ids), len(known), u.Hint)
+		if muted > 0 {
+			logf("  Muted in review: %d ID(s) (see muted.json)\n", muted)
+		}
+		if len(newModels) > 0 {
+			hasChanges = true
+			sort.Strings(newModels)
+			allNew = append(allNew, newModels...)
+			newByProvider[u.Name] = newModels
+			logf("  NEW (%d):\n", len(newModels))
+			for _, m := range newModels {
+				logf("    + %s\n", m)
+			}
+		}
+		if len(unlisted) > 0 {
+			sort.Strings(unlisted)
+			logf("  Not on OpenRouter (%d), not treated as removed:\n", len(unlisted))
+			for _, m := range unlisted {
+				logf("    . %s\n", m)
+			}
+		}
+		if len(newModels) == 0 && len(unlisted) == 0 {
+			logf("  OK: in sync\n")
+		}
+		if explain && len(filtered) > 0 {
+			logf("  FILTERED (%d):\n", len(filtered))
+			for _, f := range filtered {
+				logf("    ~ %s: %s\n", f.ID, f.Reason)
+			}
+		}
+		syncState.verified(u.Name, time.Now(), "aggregator", len(ids), newModels, nil)
+	}
+
+	// Provider-published deprecation notices: explicit announcements, as
+	// opposed to inferring deprecation from a model vanishing from the docs.
+	notices := make(map[string]deprecationNotice)
+	var noticeProviders []string
+	for p := range deprecationPages {
+		noticeProviders = append(noticeProviders, p)
+	}
+	sort.Strings(noticeProviders)
+	logf("\n=== PROVIDER DEPRECATION NOTICES ===\n")
+	for _, name := range noticeProviders {
+		if fetchCtx.Err() != nil {
+			logf("[%s] SKIP: deprecation notices skipped due to budget\n", name)
+			continue
+		}
+		found, truncated, err := fetchDeprecationNotices(fetchCtx, client, name, deprecationPages[name])
+		if err != nil {
+			logf("[%s] WARNING: could not read deprecation notices (%v)\n", name, err)
+			continue
+		}
+		if truncated {
+			logf("[%s] WARNING: deprecation page exceeded the %s-byte page limit and was truncated; later notices were missed\n",
+				name, models.FormatInt(int(defaultMaxBodyBytes)))
+		}
+		runProvenance.record(sourceRecord{Provider: name, Kind: "notices", URL: deprecationPages[name], IDs: len(found), Truncated: truncated})
+		logf("[%s] Deprecation page lists %d tracked models\n", name, len(found))
+		for _, n := range found {
+			if !mutes.mutedMissing(name, n.ModelID) {
+				notices[n.ModelID] = n
+			}
+		}
+	}
+	pending := pendingNotices(notices)
+	if len(pending) == 0 {
+		logf("  OK: every announced deprecation is reflected in the registry\n")
+	}
+	for _, n := range pending {
+		logf("  ! %s (registry: %s): %s\n", n.ModelID, models.Models[n.ModelID].Status, n.describe())
+	}
+	if len(pending) > 0 {
+		hasChanges = true
+	}
+
+	// Coverage gaps: providers listed by the aggregator that we don't track at all.
+	var providerCandidates []providerCandidate
+	if catalog != nil {
+		providerCandidates = newProviderCandidates(slugCounts(catalog))
+		logf("\n=== NEW PROVIDER CANDIDATES ===\n")
+		if len(providerCandidates) == 0 {
+			logf("  OK: every OpenRouter provider is tracked\n")
+		}
+		for _, c := range providerCandidates {
+			logf("  ? %s (%d models on OpenRouter)\n", c.Slug, c.Models)
+		}
+		if len(providerCandidates) > 0 {
+			hasChanges = true
+		}
+	}
+
+	if err := history.save(historyFile()); err != nil {
+		logf("\nWARNING: could not save scrape history (%v)\n", err)
+	}
+	if err := syncState.save(statusFile(), runProvenance.RunID, time.Now()); err != nil {
+		logf("\nWARNING: could not save sync status (%v)\n", err)
+	}
 
 	logf("\n=== Summary ===\n")
+	hosts, err := forge.AllFromEnv(client)
+	if err != nil {
+		logf("WARNING: %v; issues will not be created there.\n", err)
+	}
+	if len(skipped) > 0 {
+		logf("Run budget of %s exhausted; skipped due to budget: %s. They are missing from this report.\n", budget, strings.Join(skipped, ", "))
+	}
+	if len(rotAlerts) > 0 {
+		logf("Scraper pattern rot detected for %d source(s); see PATTERN ROT lines above.\n", len(rotAlerts))
+	}
 	if hasChanges {
 		if hasErrors {
 			logf("WARNING: Some providers failed to respond (see errors above).\n")
 		}
 		logf("Changes detected. Review the output above.\n")
-		if len(allMissing) > 0 {
-			createDeprecationIssue(ctx, client, allMissing, report.String())
+	}
+
+	reportBody := report.String()
+	tracked := make(map[string]int, len(knownModels))
+	for p, ids := range knownModels {
+		tracked[p] = len(ids)
+	}
+	batches := splitMissingBatches(missingByProvider, tracked, maxBatchModels())
+
+	// Review mode replaces the change issues with one pull request holding
+	// the changes a maintainer accepted.
+	if hasChanges && reviewMode() {
+		items := reviewItems(providerOrder, newByProvider, missingByProvider, pending)
+		fmt.Printf("\n=== REVIEW (%d changes) ===\n", len(items))
+		outcome := runReview(items, os.Stdin, os.Stdout)
+		fmt.Printf("\nAccepted %d, rejected %d, deferred %d.\n", len(outcome.Accepted), len(outcome.Rejected), len(outcome.Deferred))
+		files, err := stageReview(outcome, time.Now())
+		switch {
+		case err != nil:
+			fmt.Printf("ERROR: could not stage review edits: %v\n", err)
+			os.Exit(2)
+		case len(files) == 0 && len(outcome.Accepted) > 0:
+			// Only new models were accepted: there is nothing to commit
+			// until someone writes their entries.
+			accepted := make(map[string][]string)
+			for _, it := range outcome.Accepted {
+				accepted[it.Provider] = append(accepted[it.Provider], it.ModelID)
+			}
+			forEachHost(hosts, func(host forge.Forge) { createNewModelsIssue(ctx, host, accepted, reportBody) })
+		case len(files) == 0:
+			fmt.Println("Nothing to stage.")
+		case len(hosts) == 0:
+			fmt.Printf("Staged edits to %s. No forge is configured; review the diff and open a pull request by hand.\n", strings.Join(files, ", "))
+		default:
+			if err := openReviewPR(ctx, hosts, files, outcome, time.Now()); err != nil {
+				fmt.Printf("ERROR: %v\n", err)
+				os.Exit(2)
+			}
 		}
-		if len(allNew) > 0 {
-			createNewModelsIssue(ctx, client, allNew, report.String())
+		forEachHost(hosts, func(host forge.Forge) { createScraperRotIssue(ctx, host, rotAlerts, threshold, reportBody) })
+		os.Exit(0)
+	}
+
+	// Auto-add mode proposes stub entries for new models in a draft pull
+	// request, falling back to the new-models issue if it can't.
+	newModelsPR := false
+	if len(allNew) > 0 && autoAddMode() {
+		opened, err := openNewModelsPR(ctx, hosts, newByProvider, time.Now())
+		if err != nil {
+			fmt.Printf("ERROR: could not propose new model entries: %v\n", err)
 		}
+		newModelsPR = opened
+	}
+
+	forEachHost(hosts, func(host forge.Forge) {
+		createScraperRotIssue(ctx, host, rotAlerts, threshold, reportBody)
+		if !hasChanges {
+			return
+		}
+		for _, b := range batches {
+			createDeprecationIssue(ctx, host, b, notices, reportBody)
+		}
+		createDeprecationNoticeIssue(ctx, host, pending, reportBody)
+		if len(allNew) > 0 && !newModelsPR {
+			createNewModelsIssue(ctx, host, newByProvider, reportBody)
+		}
+		if len(providerCandidates) > 0 {
+			createNewProvidersIssue(ctx, host, providerCandidates, reportBody)
+		}
+	})
+
+	if hasChanges {
 		os.Exit(1)
 	} else if hasErrors {
 		logf("No model changes detected, but some providers could not be checked.\n")
@@ -494,30 +836,20 @@ func main() {
 	os.Exit(0)
 }
 
+// fetchModelsFromAPI lists model IDs from an OpenAI-compatible /v1/models
+// endpoint.
 func fetchModelsFromAPI(ctx context.Context, client *http.Client, endpoint, apiKey string) ([]string, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	body, err := getAPI(ctx, client, endpoint, bearer(apiKey))
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("API request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned HTTP %d", resp.StatusCode)
-	}
 
 	var result struct {
 		Data []struct {
 			ID string `json:"id"`
 		} `json:"data"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := json.Unmarshal(body, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse API response: %w", err)
 	}
 
@@ -530,73 +862,130 @@ func fetchModelsFromAPI(ctx context.Context, client *http.Client, endpoint, apiK
 	return ids, nil
 }
 
-// fetchModelsFromDocs fetches a public documentation page and extracts model IDs
-// using the provider's regex pattern. No API keys needed.
-func fetchModelsFromDocs(ctx context.Context, client *http.Client, src DocSource) ([]string, error) {
+// fetchModelsFromDocs extracts model IDs from a provider's public docs. No
+// API keys needed. Raw markdown sources are tried first, since their tables
+// parse structurally; rendered HTML pages are the fallback. The returned
+// record names the URL that yielded the IDs and the digest of its body.
+func fetchModelsFromDocs(ctx context.Context, client *http.Client, src DocSource) ([]string, sourceRecord, error) {
 	var lastErr error
+	for _, url := range src.OfferURLs {
+		ids, rec, err := fetchAndExtractOffer(ctx, client, url, src.OfferAttribute, src.Pattern, src.bodyLimit())
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if ids = src.clean(ids); len(ids) > 0 {
+			rec.Kind = "offer"
+			return ids, rec, nil
+		}
+	}
+	for _, url := range src.MarkdownURLs {
+		ids, rec, err := fetchAndExtractMarkdown(ctx, client, url, src.IDColumn, src.Pattern, src.bodyLimit())
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if ids = src.clean(ids); len(ids) > 0 {
+			rec.Kind = "markdown"
+			return ids, rec, nil
+		}
+	}
 	for _, url := range src.URLs {
-		ids, err := fetchAndExtract(ctx, client, url, src.Pattern)
+		ids, rec, err := fetchAndExtract(ctx, client, url, src.Pattern, src.bodyLimit())
 		if err != nil {
 			lastErr = err
 			continue
 		}
-		if len(ids) > 0 {
-			if src.ExcludePattern != nil {
-				filtered := make([]string, 0, len(ids))
-				for _, id := range ids {
-					if !src.ExcludePattern.MatchString(id) {
-						filtered = append(filtered, id)
-					}
-				}
-				ids = filtered
-			}
-			if src.NormalizeRe != nil {
-				for i, id := range ids {
-					ids[i] = src.NormalizeRe.ReplaceAllString(id, src.NormalizeRepl)
-				}
-			}
-			if src.NormalizeFunc != nil {
-				for i, id := range ids {
-					ids[i] = src.NormalizeFunc(id)
-				}
-			}
-			if src.Lowercase {
-				for i, id := range ids {
-					ids[i] = strings.ToLower(id)
-				}
-			}
-			// Universal: strip mode suffixes (e.g. -reasoning, -non-reasoning)
-			// so that variants collapse to their base model ID.
-			for i, id := range ids {
-				ids[i] = stripModeSuffixes(id)
-			}
-			seen := make(map[string]bool, len(ids))
-			deduped := make([]string, 0, len(ids))
-			for _, id := range ids {
-				if !seen[id] {
-					seen[id] = true
-					deduped = append(deduped, id)
-				}
-			}
-			ids = deduped
-			if len(ids) > 0 {
-				return ids, nil
-			}
+		if ids = src.clean(ids); len(ids) > 0 {
+			rec.Kind = "docs"
+			return ids, rec, nil
 		}
 	}
 	if lastErr != nil {
-		return nil, fmt.Errorf("all URLs failed: %w", lastErr)
+		return nil, sourceRecord{}, fmt.Errorf("all URLs failed: %w", lastErr)
 	}
-	return nil, fmt.Errorf("no model IDs found in any URL")
+	return nil, sourceRecord{}, fmt.Errorf("no model IDs found in any URL")
 }
 
-// fetchAndExtract fetches a URL and extracts model IDs using a regex pattern.
-func fetchAndExtract(ctx context.Context, client *http.Client, url string, pattern *regexp.Regexp) ([]string, error) {
+// clean applies the source's exclusion and normalization rules to extracted
+// IDs, strips mode suffixes, and removes duplicates.
+func (src DocSource) clean(ids []string) []string {
+	if src.ExcludePattern != nil {
+		filtered := make([]string, 0, len(ids))
+		for _, id := range ids {
+			if !src.ExcludePattern.MatchString(id) {
+				filtered = append(filtered, id)
+			}
+		}
+		ids = filtered
+	}
+	if src.NormalizeRe != nil {
+		for i, id := range ids {
+			ids[i] = src.NormalizeRe.ReplaceAllString(id, src.NormalizeRepl)
+		}
+	}
+	if src.NormalizeFunc != nil {
+		for i, id := range ids {
+			ids[i] = src.NormalizeFunc(id)
+		}
+	}
+	if src.Lowercase {
+		for i, id := range ids {
+			ids[i] = strings.ToLower(id)
+		}
+	}
+	// Universal: strip mode suffixes (e.g. -reasoning, -non-reasoning)
+	// so that variants collapse to their base model ID.
+	for i, id := range ids {
+		ids[i] = stripModeSuffixes(id)
+	}
+	seen := make(map[string]bool, len(ids))
+	deduped := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			deduped = append(deduped, id)
+		}
+	}
+	return deduped
+}
+
+// fetchAndExtract fetches a URL, reading at most limit bytes, and extracts
+// model IDs using a regex pattern. The returned record carries the URL, the
+// SHA-256 of the normalized body, and whether the body was truncated.
+func fetchAndExtract(ctx context.Context, client *http.Client, url string, pattern *regexp.Regexp, limit int64) ([]string, sourceRecord, error) {
+	body, truncated, err := fetchPage(ctx, client, url, limit)
+	if err != nil {
+		return nil, sourceRecord{}, err
+	}
+
+	// Extract unique model IDs using the regex pattern.
+	matches := pattern.FindAllStringSubmatch(body, -1)
+	seen := make(map[string]bool)
+	var ids []string
+	for _, m := range matches {
+		if len(m) >= 2 {
+			id := m[1]
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids, sourceRecord{URL: url, SHA256: bodyDigest(body), Truncated: truncated}, nil
+}
+
+// fetchPage fetches a URL with retries and returns its normalized body, read
+// up to limit bytes. truncated reports whether the page was longer.
+func fetchPage(ctx context.Context, client *http.Client, url string, limit int64) (body string, truncated bool, err error) {
 	var lastErr error
 	for attempt := 1; attempt <= maxRetries; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return "", false, err
+		}
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 		if err != nil {
-			return nil, err
+			return "", false, err
 		}
 		req.Header.Set("User-Agent", "ModelRegistryUpdater/1.0")
 		req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
@@ -604,19 +993,24 @@ func fetchAndExtract(ctx context.Context, client *http.Client, url string, patte
 		resp, err := client.Do(req)
 		if err != nil {
 			lastErr = err
-			if attempt < maxRetries {
-				time.Sleep(time.Duration(attempt) * 2 * time.Second)
+			if attempt < maxRetries && !sleepCtx(ctx, time.Duration(attempt)*2*time.Second) {
+				return "", false, fmt.Errorf("%w (last error: %v)", ctx.Err(), lastErr)
 			}
 			continue
 		}
 
-		body, err := io.ReadAll(io.LimitReader(resp.Body, 2*1024*1024)) // 2MB max
+		// Read one byte past the limit to tell a page of exactly limit bytes
+		// from a truncated one.
+		raw, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
 		resp.Body.Close()
+		if err == nil {
+			runSnapshots.seen(url, resp.StatusCode, raw)
+		}
 
 		if resp.StatusCode != http.StatusOK {
 			lastErr = fmt.Errorf("HTTP %d from %s", resp.StatusCode, url)
-			if attempt < maxRetries {
-				time.Sleep(time.Duration(attempt) * 2 * time.Second)
+			if attempt < maxRetries && !sleepCtx(ctx, time.Duration(attempt)*2*time.Second) {
+				return "", false, fmt.Errorf("%w (last error: %v)", ctx.Err(), lastErr)
 			}
 			continue
 		}
@@ -624,23 +1018,47 @@ func fetchAndExtract(ctx context.Context, client *http.Client, url string, patte
 			lastErr = err
 			continue
 		}
-
-		// Extract unique model IDs using the regex pattern.
-		matches := pattern.FindAllStringSubmatch(string(body), -1)
-		seen := make(map[string]bool)
-		var ids []string
-		for _, m := range matches {
-			if len(m) >= 2 {
-				id := m[1]
-				if !seen[id] {
-					seen[id] = true
-					ids = append(ids, id)
-				}
-			}
+		if truncated = int64(len(raw)) > limit; truncated {
+			raw = raw[:limit]
 		}
-		return ids, nil
+		return normalizeBody(string(raw)), truncated, nil
 	}
-	return nil, fmt.Errorf("all %d attempts failed: %w", maxRetries, lastErr)
+	return "", false, fmt.Errorf("all %d attempts failed: %w", maxRetries, lastErr)
+}
+
+// bodyReplacer folds the typographic characters docs pages use in place of
+// plain ASCII (non-breaking hyphens, en/em dashes, smart quotes, non-breaking
+// and zero-width spaces) so ID patterns written against ASCII still match.
+var bodyReplacer = strings.NewReplacer(
+	"\u2010", "-", // hyphen
+	"\u2011", "-", // non-breaking hyphen
+	"\u2012", "-", // figure dash
+	"\u2013", "-", // en dash
+	"\u2014", "-", // em dash
+	"\u2212", "-", // minus sign
+	"\uFE63", "-", // small hyphen-minus
+	"\uFF0D", "-", // fullwidth hyphen-minus
+	"\u2018", "'", // left single quote
+	"\u2019", "'", // right single quote
+	"\u201C", `"`, // left double quote
+	"\u201D", `"`, // right double quote
+	"\u00A0", " ", // no-break space
+	"\u202F", " ", // narrow no-break space
+	"\u2009", " ", // thin space
+	"\u200B", "", // zero-width space
+	"\u200C", "", // zero-width non-joiner
+	"\u200D", "", // zero-width joiner
+	"\u2060", "", // word joiner
+	"\u00AD", "", // soft hyphen
+	"\uFEFF", "", // byte order mark
+)
+
+// normalizeBody decodes HTML entities (e.g. &#8209;, &ndash;, &rsquo;) and
+// folds typographic Unicode to ASCII before pattern extraction. Unescaping
+// runs twice to handle double-encoded entities such as &amp;#8209;.
+func normalizeBody(body string) string {
+	body = html.UnescapeString(html.UnescapeString(body))
+	return bodyReplacer.Replace(body)
 }
 
 // fingerprintModels computes a deterministic SHA-256 fingerprint for a set of
@@ -657,169 +1075,236 @@ func fingerprintModels(ids []string) string {
 // existingIssueWithFingerprint checks if any open issue with the auto-update
 // label already contains a matching fingerprint comment in its body. Returns
 // true if a matching issue exists (meaning we should skip creating a new one).
-func existingIssueWithFingerprint(ctx context.Context, client *http.Client, token, repo, fingerprint string) bool {
-	searchURL := fmt.Sprintf("https://api.github.com/search/issues?q=repo:%s+state:open+label:auto-update",
-		repo)
-	searchReq, err := http.NewRequestWithContext(ctx, http.MethodGet, searchURL, nil)
-	if err != nil {
-		return false
-	}
-	searchReq.Header.Set("Authorization", "Bearer "+token)
-	searchReq.Header.Set("Accept", "application/vnd.github+json")
-
-	searchResp, err := client.Do(searchReq)
+func existingIssueWithFingerprint(ctx context.Context, host forge.Forge, fingerprint string) bool {
+	issues, err := host.OpenIssues(ctx, "auto-update")
 	if err != nil {
 		return false
 	}
-	defer searchResp.Body.Close()
 
-	if searchResp.StatusCode != http.StatusOK {
-		return false
+	marker := "<!-- fingerprint:" + fingerprint + " -->"
+	for _, issue := range issues {
+		if strings.Contains(issue.Body, marker) {
+			return true
+		}
 	}
+	return false
+}
 
-	var searchResult struct {
-		TotalCount int `json:"total_count"`
-		Items      []struct {
-			Body string `json:"body"`
-		} `json:"items"`
-	}
-	if err := json.NewDecoder(searchResp.Body).Decode(&searchResult); err != nil || searchResult.TotalCount == 0 {
+// existingPullRequestWithFingerprint is existingIssueWithFingerprint for
+// open pull requests, which hosts list apart from issues.
+func existingPullRequestWithFingerprint(ctx context.Context, host forge.Forge, fingerprint string) bool {
+	prs, err := host.OpenPullRequests(ctx, "auto-update")
+	if err != nil {
 		return false
 	}
 
 	marker := "<!-- fingerprint:" + fingerprint + " -->"
-	for _, issue := range searchResult.Items {
-		if strings.Contains(issue.Body, marker) {
+	for _, pr := range prs {
+		if strings.Contains(pr.Body, marker) {
 			return true
 		}
 	}
 	return false
 }
 
-// createGitHubIssue creates a GitHub issue with the given title, body, and
-// the "auto-update" label. Returns silently if GITHUB_TOKEN or GITHUB_REPO
-// environment variables are not set.
-func createGitHubIssue(ctx context.Context, client *http.Client, title, body string) {
-	token := os.Getenv("GITHUB_TOKEN")
-	repo := os.Getenv("GITHUB_REPO")
-	if token == "" || repo == "" {
-		return
+// forEachHost runs fn for every host concurrently and waits for all of
+// them, so a slow or failing mirror doesn't delay reporting upstream. Each
+// host deduplicates against its own open issues.
+func forEachHost(hosts []forge.Forge, fn func(forge.Forge)) {
+	var wg sync.WaitGroup
+	for _, host := range hosts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn(host)
+		}()
 	}
+	wg.Wait()
+}
 
-	issueURL := fmt.Sprintf("https://api.github.com/repos/%s/issues", repo)
-	payload := map[string]any{
-		"title":  title,
-		"body":   body,
-		"labels": []string{"auto-update"},
-	}
-	bodyJSON, err := json.Marshal(payload)
-	if err != nil {
-		fmt.Printf("[GitHub] failed to marshal issue body: %v\n", err)
-		return
+// withSnapshots appends the run's page snapshots of providers to body,
+// filling only the room the issue body limit leaves after body and the
+// provenance createIssue adds.
+func withSnapshots(body string, providers ...string) string {
+	room := maxIssueBody - len(body)
+	if runProvenance != nil {
+		room -= len(runProvenance.section())
 	}
+	return body + runSnapshots.section(room, providers...)
+}
 
-	issueReq, err := http.NewRequestWithContext(ctx, http.MethodPost, issueURL, bytes.NewReader(bodyJSON))
-	if err != nil {
-		fmt.Printf("[GitHub] failed to create issue request: %v\n", err)
-		return
+// capIssueBody cuts body to limit bytes on a line boundary with a note
+// saying so, keeping its fingerprint marker so the issue still matches on
+// the next run.
+func capIssueBody(body string, limit int) string {
+	if len(body) <= limit {
+		return body
+	}
+	marker := ""
+	if i := strings.Index(body, "<!-- fingerprint:"); i >= 0 {
+		if j := strings.Index(body[i:], "-->"); j >= 0 {
+			marker = body[i:i+j+len("-->")] + "\n"
+		}
+	}
+	note := "\n_Report cut to fit the issue size limit; see the run log for the rest._\n"
+	cut := body[:max(limit-len(note)-len(marker), 0)]
+	if i := strings.LastIndexByte(cut, '\n'); i >= 0 {
+		cut = cut[:i+1]
+	}
+	if strings.Contains(cut, marker) {
+		marker = ""
 	}
-	issueReq.Header.Set("Authorization", "Bearer "+token)
-	issueReq.Header.Set("Accept", "application/vnd.github+json")
-	issueReq.Header.Set("Content-Type", "application/json")
+	return cut + note + marker
+}
 
-	issueResp, err := client.Do(issueReq)
+// createIssue creates an issue on host with the given title, body, and
+// the "auto-update" label plus any extra labels. The run's provenance is
+// appended to the body, which is cut to fit the forge's size limit.
+func createIssue(ctx context.Context, host forge.Forge, title, body string, extraLabels ...string) {
+	provenance := ""
+	if runProvenance != nil {
+		provenance = runProvenance.section()
+	}
+	body = capIssueBody(body, maxIssueBody-len(provenance)) + provenance
+	issue, err := host.CreateIssue(ctx, title, body, append([]string{"auto-update"}, extraLabels...))
 	if err != nil {
-		fmt.Printf("[GitHub] failed to create issue: %v\n", err)
+		fmt.Printf("[%s] Failed to create issue: %v\n", host.Name(), err)
 		return
 	}
-	defer issueResp.Body.Close()
-
-	if issueResp.StatusCode == http.StatusCreated {
-		var created struct {
-			HTMLURL string `json:"html_url"`
-		}
-		_ = json.NewDecoder(issueResp.Body).Decode(&created)
-		fmt.Printf("[GitHub] Issue created: %s\n", created.HTMLURL)
-	} else {
-		respBody, _ := io.ReadAll(io.LimitReader(issueResp.Body, 512))
-		fmt.Printf("[GitHub] Failed to create issue (HTTP %d): %s\n", issueResp.StatusCode, string(respBody))
-	}
+	fmt.Printf("[%s] Issue created: %s\n", host.Name(), issue.URL)
 }
 
-// createNewModelsIssue creates a GitHub issue reporting newly detected model IDs.
-// It checks for existing open issues that already cover the same model IDs to
-// avoid duplicates.
-func createNewModelsIssue(ctx context.Context, client *http.Client, newModelIDs []string, reportBody string) {
-	token := os.Getenv("GITHUB_TOKEN")
-	repo := os.Getenv("GITHUB_REPO")
-	if token == "" || repo == "" {
+// createNewModelsIssue creates an issue reporting newly detected model IDs,
+// grouped by provider. It checks for existing open issues that already cover
+// the same model IDs to avoid duplicates. Returns silently if host is nil (no
+// forge configured, see forge.FromEnv).
+func createNewModelsIssue(ctx context.Context, host forge.Forge, newByProvider map[string][]string, reportBody string) {
+	if host == nil {
 		return
 	}
 
-	fp := fingerprintModels(newModelIDs)
-	if existingIssueWithFingerprint(ctx, client, token, repo, fp) {
-		fmt.Printf("[GitHub] Existing open issue already covers these new models (fingerprint match), skipping.\n")
+	var all []string
+	for _, ids := range newByProvider {
+		all = append(all, ids...)
+	}
+	fp := fingerprintModels(all)
+	if existingIssueWithFingerprint(ctx, host, fp) {
+		fmt.Printf("[%s] Existing open issue already covers these new models (fingerprint match), skipping.\n", host.Name())
 		return
 	}
 
-	today := time.Now().Format("2006-01-02")
-	title := "New models detected - " + today
+	title := "New models detected - " + time.Now().Format("2006-01-02")
+	createIssue(ctx, host, title, newModelsIssueBody(newByProvider, reportBody, fp))
+}
+
+// newModelsIssueBody renders one collapsible section per provider with a
+// task checkbox per model, so maintainers can tick models off as their
+// entries land and the issue shows partial progress.
+func newModelsIssueBody(newByProvider map[string][]string, reportBody, fp string) string {
+	providers := make([]string, 0, len(newByProvider))
+	total := 0
+	for p, ids := range newByProvider {
+		if len(ids) > 0 {
+			providers = append(providers, p)
+			total += len(ids)
+		}
+	}
+	sort.Strings(providers)
 
-	sort.Strings(newModelIDs)
 	var body strings.Builder
 	body.WriteString("## New Models Detected\n\n")
-	body.WriteString("The following model IDs were found in provider documentation but are not in the registry:\n\n")
-	for _, id := range newModelIDs {
-		body.WriteString(fmt.Sprintf("- `%s`\n", id))
+	fmt.Fprintf(&body, "%d model ID(s) from %d provider(s) were found in provider documentation or APIs but are not in the registry. Check a box once that model's entry is merged.\n\n", total, len(providers))
+	for _, p := range providers {
+		ids := slices.Sorted(slices.Values(newByProvider[p]))
+		fmt.Fprintf(&body, "<details open>\n<summary><strong>%s</strong> (%d new)</summary>\n\n", p, len(ids))
+		for _, id := range ids {
+			fmt.Fprintf(&body, "- [ ] add `%s`\n", id)
+		}
+		body.WriteString("\n</details>\n\n")
 	}
-	body.WriteString("\n### Action Items\n\n")
-	body.WriteString("- [ ] Add each model to `go-server/internal/models/data.go` (all 13 fields)\n")
-	body.WriteString("- [ ] Add model IDs to `knownModels` in `go-server/cmd/updater/main.go`\n")
-	body.WriteString("- [ ] Update `TestTotalModelCount` and `TestProviderCounts` in `data_test.go`\n")
-	body.WriteString("- [ ] Run `go test ./... -v` to verify\n")
+	body.WriteString("### Adding a model\n\n")
+	body.WriteString("1. Add it to `go-server/internal/models/models.json` (every field plus docs_url)\n")
+	body.WriteString("2. Add its ID to `knownModels` in `go-server/cmd/updater/main.go`\n")
+	body.WriteString("3. Update `TestTotalModelCount` and `TestProviderCounts` in `data_test.go`\n")
+	body.WriteString("4. Run `go test ./... -v` to verify\n")
 	body.WriteString("\n<details>\n<summary>Full update report</summary>\n\n```\n")
 	body.WriteString(reportBody)
 	body.WriteString("\n```\n</details>\n")
 	body.WriteString("\n<!-- fingerprint:" + fp + " -->\n")
-
-	createGitHubIssue(ctx, client, title, body.String())
+	return body.String()
 }
 
-// createDeprecationIssue creates a GitHub issue reporting models that were
+// createDeprecationIssue creates an issue reporting models that were
 // removed from provider documentation and may need deprecation. This replaces
 // the old createDeprecationPR approach which was fundamentally broken: it could
 // only update data.go's Status field via regex, but deprecation also requires
 // updating data_test.go counts, knownModels in main.go, and the model's Notes
 // field. Creating an issue lets a human (or CI-aware tool) handle all the
 // required changes properly.
-func createDeprecationIssue(ctx context.Context, client *http.Client, missingIDs []string, reportBody string) {
-	token := os.Getenv("GITHUB_TOKEN")
-	repo := os.Getenv("GITHUB_REPO")
-	if token == "" || repo == "" {
+//
+// One issue is created per provider batch (see splitMissingBatches), so a
+// scrape failure that drops dozens of models never lands as one giant
+// report. Low-confidence or split batches get verificationLabel.
+func createDeprecationIssue(ctx context.Context, host forge.Forge, b missingBatch, notices map[string]deprecationNotice, reportBody string) {
+	if host == nil {
 		return
 	}
 
-	fp := fingerprintModels(missingIDs)
-	if existingIssueWithFingerprint(ctx, client, token, repo, fp) {
-		fmt.Printf("[GitHub] Existing open issue already covers these missing models (fingerprint match), skipping.\n")
+	fp := fingerprintModels(b.IDs)
+	if existingIssueWithFingerprint(ctx, host, fp) {
+		fmt.Printf("[%s] Existing open issue already covers these missing %s models (fingerprint match), skipping.\n", host.Name(), b.Provider)
 		return
 	}
 
-	today := time.Now().Format("2006-01-02")
-	title := "Models removed from provider docs - " + today
+	body := deprecationIssueBody(b, notices, reportBody, fp)
+	if b.Part == 1 {
+		body = withSnapshots(body, b.Provider)
+	}
+	var labels []string
+	if b.needsVerification() {
+		labels = append(labels, verificationLabel)
+	}
+	createIssue(ctx, host, deprecationIssueTitle(b, time.Now()), body, labels...)
+}
+
+// deprecationIssueTitle names the provider and, for split batches, the part.
+func deprecationIssueTitle(b missingBatch, now time.Time) string {
+	title := fmt.Sprintf("Models removed from %s docs - %s", b.Provider, now.Format("2006-01-02"))
+	if b.Parts > 1 {
+		title += fmt.Sprintf(" (part %d/%d)", b.Part, b.Parts)
+	}
+	return title
+}
+
+// deprecationIssueBody renders the issue body for one batch, with a
+// confidence note per model. Models with a provider deprecation notice are
+// marked confirmed and show the announced dates.
+func deprecationIssueBody(b missingBatch, notices map[string]deprecationNotice, reportBody, fp string) string {
+	level, note := b.confidence()
 
-	sort.Strings(missingIDs)
 	var body strings.Builder
 	body.WriteString("## Models Missing From Provider Documentation\n\n")
-	body.WriteString("The following models are in our registry but were NOT found in their provider's public documentation.\n")
+	body.WriteString(fmt.Sprintf("The following %s models are in our registry but were NOT found in their provider's public documentation.\n", b.Provider))
 	body.WriteString("They may have been deprecated, renamed, or the documentation page may have changed.\n\n")
-	for _, id := range missingIDs {
-		body.WriteString(fmt.Sprintf("- `%s`\n", id))
+	if b.needsVerification() {
+		body.WriteString(fmt.Sprintf("> **Requires human verification:** %s.", note))
+		if b.Parts > 1 {
+			body.WriteString(fmt.Sprintf(" This is part %d of %d; %d models are missing in total.", b.Part, b.Parts, b.Missing))
+		}
+		body.WriteString(" Check each model against the provider docs before deprecating anything.\n\n")
+	}
+	body.WriteString("| Model | Confidence | Note |\n|---|---|---|\n")
+	for _, id := range b.IDs {
+		if n, ok := notices[id]; ok {
+			body.WriteString(fmt.Sprintf("| `%s` | confirmed | provider notice: %s |\n", id, n.describe()))
+			continue
+		}
+		body.WriteString(fmt.Sprintf("| `%s` | %s | %s |\n", id, level, note))
 	}
 	body.WriteString("\n### Action Items\n\n")
 	body.WriteString("For each model, verify whether it has actually been deprecated, then:\n\n")
-	body.WriteString("- [ ] Set `Status: \"deprecated\"` in `go-server/internal/models/data.go`\n")
-	body.WriteString("- [ ] Update the `Notes` field with deprecation context\n")
+	body.WriteString("- [ ] Set `\"status\": \"deprecated\"` in `go-server/internal/models/models.json`\n")
+	body.WriteString("- [ ] Update the model's `notes` with deprecation context\n")
 	body.WriteString("- [ ] Remove the model from `knownModels` in `go-server/cmd/updater/main.go`\n")
 	body.WriteString("- [ ] Update `TestTotalModelCount` and `TestProviderCounts` in `data_test.go`\n")
 	body.WriteString("- [ ] Run `go test ./... -v` to verify\n")
@@ -827,8 +1312,7 @@ func createDeprecationIssue(ctx context.Context, client *http.Client, missingIDs
 	body.WriteString(reportBody)
 	body.WriteString("\n```\n</details>\n")
 	body.WriteString("\n<!-- fingerprint:" + fp + " -->\n")
-
-	createGitHubIssue(ctx, client, title, body.String())
+	return body.String()
 }
 
 // modeSuffixes lists well-known mode/variant suffixes that providers append
@@ -936,21 +1420,38 @@ func isAllDigits(s string) bool {
 }
 
 // isKnownAlias reports whether id is a variant of an already-known model.
-// It checks three heuristics:
+// See aliasReason for the heuristics.
+func isKnownAlias(id string, known map[string]bool) bool {
+	return aliasReason(id, known) != ""
+}
+
+// aliasReason explains which known model id is a variant of, or returns ""
+// if it is not one. It checks three heuristics:
 //  1. id is a prefix of a known ID whose remaining suffix is all-digits
 //     (e.g. known "gpt-5-mini-2025" when id is "gpt-5-mini").
 //  2. id extends a known ID with a well-known alias suffix
 //     (e.g. "gpt-5-chat-latest" when "gpt-5" is known).
-//  3. id shares a base name with a known ID and both have ≥2-digit numeric
-//     suffixes (e.g. "codestral-25" or "codestral-2405" when "codestral-2508" is known).
-func isKnownAlias(id string, known map[string]bool) bool {
+//  3. id is an older release of a known ID: same base name and a numeric
+//     suffix of the same length (≥2 digits) that sorts before the known one
+//     (e.g. "codestral-2405" when "codestral-2508" is known). Newer releases
+//     like "codestral-2510", and suffixes of another length like
+//     "codestral-25", are reported.
+//
+// Known IDs are checked in sorted order so the explanation is the same on
+// every run.
+func aliasReason(id string, known map[string]bool) string {
+	knownIDs := make([]string, 0, len(known))
 	for knownID := range known {
+		knownIDs = append(knownIDs, knownID)
+	}
+	sort.Strings(knownIDs)
+	for _, knownID := range knownIDs {
 		// Heuristic 1: known ID extends scraped ID with an all-digit suffix
 		// e.g. known "gpt-5-mini-2025" when scraped ID is "gpt-5-mini"
 		if knownID != id && strings.HasPrefix(knownID, id+"-") {
 			suffix := knownID[len(id)+1:]
 			if isAllDigits(suffix) {
-				return true
+				return fmt.Sprintf("known %s extends it with numeric suffix -%s", knownID, suffix)
 			}
 		}
 		// Heuristic 2: scraped ID extends known ID with a well-known suffix
@@ -959,7 +1460,7 @@ func isKnownAlias(id string, known map[string]bool) bool {
 		if id != knownID && strings.HasPrefix(id, knownID+"-") {
 			suffix := id[len(knownID)+1:]
 			if aliasSuffixes[suffix] || isCompoundAliasSuffix(suffix) {
-				return true
+				return fmt.Sprintf("extends known %s with alias suffix -%s", knownID, suffix)
 			}
 		}
 		// Heuristic 2b (reverse): known ID extends scraped ID with a well-known suffix
@@ -967,29 +1468,30 @@ func isKnownAlias(id string, known map[string]bool) bool {
 		if knownID != id && strings.HasPrefix(knownID, id+"-") {
 			suffix := knownID[len(id)+1:]
 			if aliasSuffixes[suffix] || isCompoundAliasSuffix(suffix) {
-				return true
+				return fmt.Sprintf("known %s extends it with alias suffix -%s", knownID, suffix)
 			}
 		}
 	}
-	// Heuristic 3: shared base name with numeric suffixes (≥2 digits)
-	// e.g. "codestral-25" matches "codestral-2508"
+	// Heuristic 3: older release sharing a base name (≥2-digit suffixes)
+	// e.g. "codestral-2405" matches "codestral-2508"
 	if lastDash := strings.LastIndex(id, "-"); lastDash > 0 {
 		idBase := id[:lastDash]
 		idSuffix := id[lastDash+1:]
 		if isAllDigits(idSuffix) && len(idSuffix) >= 2 {
 			if known[idBase] {
-				return true
+				return fmt.Sprintf("numeric variant of known %s", idBase)
 			}
-			for knownID := range known {
+			for _, knownID := range knownIDs {
 				if kd := strings.LastIndex(knownID, "-"); kd > 0 {
-					if idBase == knownID[:kd] && isAllDigits(knownID[kd+1:]) {
-						return true
+					knownSuffix := knownID[kd+1:]
+					if idBase == knownID[:kd] && isAllDigits(knownSuffix) && len(knownSuffix) == len(idSuffix) && idSuffix < knownSuffix {
+						return fmt.Sprintf("older release of known %s", knownID)
 					}
 				}
 			}
 		}
 	}
-	return false
+	return ""
 }
 
 // hasVariantInDocs checks whether any doc ID is a variant of the given known
@@ -1026,30 +1528,50 @@ func hasVariantInDocs(knownID string, docSet map[string]bool) bool {
 // and IDs in known but absent from docs (missing), filtering out date-stamp
 // variants and known aliases from the "new" list.
 func diff(known map[string]bool, docIDs []string) (newModels, missing []string) {
+	newModels, missing, _ = diffExplained(known, docIDs)
+	return newModels, missing
+}
+
+// diffExplained is diff that also returns the scraped IDs it left out of
+// newModels and why, so filtering decisions can be audited.
+//
+// IDs are compared by modelid.Fold first, so a scraped ID that differs from
+// a known one only in case or separators (GLM-4.7, glm_4.7) is that model.
+func diffExplained(known map[string]bool, docIDs []string) (newModels, missing []string, filtered []filteredID) {
+	knownFolds := make(map[string]string, len(known))
+	for id := range known {
+		knownFolds[modelid.Fold(id)] = id
+	}
 	docSet := make(map[string]bool, len(docIDs))
+	docFolds := make(map[string]bool, len(docIDs))
 	for _, id := range docIDs {
 		docSet[id] = true
+		docFolds[modelid.Fold(id)] = true
 	}
 
+	newFolds := make(map[string]bool)
 	for _, id := range docIDs {
 		if known[id] {
 			continue
 		}
-		if isDateStampVariant(id) {
+		folded := modelid.Fold(id)
+		if k, ok := knownFolds[folded]; ok {
+			filtered = append(filtered, filteredID{ID: id, Reason: "spelling variant of known " + k})
 			continue
 		}
-		if isKnownAlias(id, known) {
+		if reason := filterReason(id, known); reason != "" {
+			filtered = append(filtered, filteredID{ID: id, Reason: reason})
 			continue
 		}
-		// Skip models already in the registry (e.g. deprecated models still in provider docs).
-		if _, exists := models.Models[id]; exists {
-			continue
+		if newFolds[folded] {
+			continue // another spelling of an ID already reported
 		}
+		newFolds[folded] = true
 		newModels = append(newModels, id)
 	}
 
 	for id := range known {
-		if docSet[id] {
+		if docSet[id] || docFolds[modelid.Fold(id)] {
 			continue
 		}
 		// Before flagging as missing, check if any doc ID is a variant of
@@ -1063,5 +1585,6 @@ func diff(known map[string]bool, docIDs []string) (newModels, missing []string)
 		missing = append(missing, id)
 	}
 
-	return newModels, missing
+	sort.Slice(filtered, func(i, j int) bool { return filtered[i].ID < filtered[j].ID })
+	return newModels, missing, filtered
 }
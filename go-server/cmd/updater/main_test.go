@@ -1,16 +1,29 @@
 package main
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"go-server/internal/forge"
+	"go-server/internal/github"
 	"go-server/internal/models"
 )
 
@@ -250,7 +263,7 @@ func TestIsKnownAlias_AliasSuffix(t *testing.T) {
 }
 
 func TestIsKnownAlias_NumericVariant(t *testing.T) {
-	// Heuristic 3: shared base name with ≥2-digit numeric suffixes.
+	// Heuristic 3: older release sharing a base name.
 	known := map[string]bool{
 		"codestral-2508":       true,
 		"mistral-large-2512":   true,
@@ -262,7 +275,8 @@ func TestIsKnownAlias_NumericVariant(t *testing.T) {
 	}{
 		{"codestral-2405", true},
 		{"codestral-2501", true},
-		{"codestral-25", true},           // 2-digit suffix still matches base "codestral"
+		{"codestral-25", false},          // suffix length differs from 2508: not comparable
+		{"codestral-2510", false},        // newer than the known release
 		{"mistral-large-2407", true},
 		{"magistral-small-2506", true},
 		{"mistral-small-2402", false},    // base "mistral-small" ≠ "mistral-large"
@@ -334,12 +348,28 @@ func TestDiff_KeepsGenuineNewModels(t *testing.T) {
 	}
 }
 
+func TestDiff_SpellingVariants(t *testing.T) {
+	known := map[string]bool{"glm-4.7": true, "MiniMax-M2.1": true}
+	docIDs := []string{"GLM-4.7", "minimax-m2.1", "GLM-5", "glm_5"}
+
+	newModels, missing, filtered := diffExplained(known, docIDs)
+	if len(newModels) != 1 || newModels[0] != "GLM-5" {
+		t.Errorf("spelling variants should not be new, got newModels = %v", newModels)
+	}
+	if len(missing) != 0 {
+		t.Errorf("known models listed under another spelling should not be missing, got %v", missing)
+	}
+	if len(filtered) != 2 || filtered[0].Reason != "spelling variant of known glm-4.7" {
+		t.Errorf("filtered = %v, want both known spellings explained", filtered)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // OpenAI ExcludePattern verification (PR #4 review checklist)
 // ---------------------------------------------------------------------------
 
 func TestOpenAIExcludePattern(t *testing.T) {
-	re := docSources["OpenAI"].ExcludePattern
+	re := sourceRules(t, "OpenAI").ExcludePattern
 
 	// These should be EXCLUDED (regex matches → filtered out as legacy).
 	shouldExclude := []string{
@@ -393,7 +423,7 @@ func TestOpenAIExcludePattern(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestXAINormalizeRe(t *testing.T) {
-	src := docSources["xAI"]
+	src := sourceRules(t, "xAI")
 	re := src.NormalizeRe
 	repl := src.NormalizeRepl
 
@@ -515,6 +545,65 @@ func TestStripModeSuffixes(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// normalizeBody tests
+// ---------------------------------------------------------------------------
+
+func TestNormalizeBody(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"claude&#8209;opus&#8209;4&#8209;6", "claude-opus-4-6"},
+		{"gpt&#x2011;5.2", "gpt-5.2"},
+		{"gemini&ndash;2.5&mdash;pro", "gemini-2.5-pro"},
+		{"&ldquo;gpt-5&rdquo; and &lsquo;o3&rsquo;", `"gpt-5" and 'o3'`},
+		{"&amp;#8209;", "-"},
+		{"grok\u20114\u2013fast", "grok-4-fast"},
+		{"deep\u200Bseek-chat\u00ADx", "deepseek-chatx"},
+		{"model&nbsp;id", "model id"},
+		{"plain-ascii", "plain-ascii"},
+	}
+	for _, tt := range tests {
+		if got := normalizeBody(tt.input); got != tt.want {
+			t.Errorf("normalizeBody(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFetchAndExtract_EntityLadenFixture(t *testing.T) {
+	// Fixture mimics a docs page that encodes hyphens and quotes as entities.
+	fixture := `<table>
+<tr><td><code>claude&#8209;opus&#8209;4&#8209;6</code></td></tr>
+<tr><td>claude‑sonnet‑4‑6</td></tr>
+<tr><td>claude&ndash;haiku&ndash;4&ndash;5&ndash;20251001</td></tr>
+</table>
+<p>&ldquo;gpt&#8209;5.2&rdquo; is the model, &#8216;o4&#8209;mini&#8217; too.</p>`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(fixture))
+	}))
+	defer srv.Close()
+
+	anthropic, openai := sourceRules(t, "Anthropic"), sourceRules(t, "OpenAI")
+	ids, _, err := fetchAndExtract(context.Background(), srv.Client(), srv.URL, anthropic.Pattern, defaultMaxBodyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"claude-opus-4-6", "claude-sonnet-4-6", "claude-haiku-4-5-20251001"}
+	if strings.Join(ids, ",") != strings.Join(want, ",") {
+		t.Errorf("Anthropic IDs = %v, want %v", ids, want)
+	}
+
+	ids, _, err = fetchAndExtract(context.Background(), srv.Client(), srv.URL, openai.Pattern, defaultMaxBodyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(ids)
+	if strings.Join(ids, ",") != "gpt-5.2,o4-mini" {
+		t.Errorf("OpenAI IDs = %v, want [gpt-5.2 o4-mini]", ids)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // isCompoundAliasSuffix tests
 // ---------------------------------------------------------------------------
@@ -633,24 +722,18 @@ func TestDiff_CompoundSuffixNotNew(t *testing.T) {
 }
 
 // ---------------------------------------------------------------------------
-// docSources ExcludePattern / NormalizeRe field presence sanity checks
+// providerSources ExcludePattern / NormalizeRe field presence sanity checks
 // ---------------------------------------------------------------------------
 
 func TestDocSources_OpenAIHasExcludePattern(t *testing.T) {
-	src, ok := docSources["OpenAI"]
-	if !ok {
-		t.Fatal("OpenAI missing from docSources")
-	}
+	src := sourceRules(t, "OpenAI")
 	if src.ExcludePattern == nil {
 		t.Fatal("OpenAI ExcludePattern is nil")
 	}
 }
 
 func TestDocSources_XAIHasNormalizeRe(t *testing.T) {
-	src, ok := docSources["xAI"]
-	if !ok {
-		t.Fatal("xAI missing from docSources")
-	}
+	src := sourceRules(t, "xAI")
 	if src.NormalizeRe == nil {
 		t.Fatal("xAI NormalizeRe is nil")
 	}
@@ -662,7 +745,7 @@ func TestDocSources_XAIHasNormalizeRe(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestXAIPatternAndNormalize_EndToEnd(t *testing.T) {
-	src := docSources["xAI"]
+	src := sourceRules(t, "xAI")
 	// Simulate HTML snippets containing model IDs in various forms.
 	content := `"grok-4-1-fast" and "grok-4-0709" and "grok-3-mini" and "grok-code-prompt-engineering"`
 
@@ -700,7 +783,7 @@ func TestXAIPatternAndNormalize_EndToEnd(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestXAIExcludePattern(t *testing.T) {
-	re := docSources["xAI"].ExcludePattern
+	re := sourceRules(t, "xAI").ExcludePattern
 
 	shouldExclude := []string{
 		"grok-2-vision-1212",
@@ -728,7 +811,7 @@ func TestXAIExcludePattern(t *testing.T) {
 }
 
 func TestXAIPattern_DocsPagePaths(t *testing.T) {
-	re := docSources["xAI"].Pattern
+	re := sourceRules(t, "xAI").Pattern
 	// "grok-code-prompt-engineering" is a docs page URL path, not a model ID.
 	// The pattern should NOT match it.
 	shouldNotMatch := []string{
@@ -757,7 +840,7 @@ func TestXAIPattern_DocsPagePaths(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestOpenAIPattern(t *testing.T) {
-	re := docSources["OpenAI"].Pattern
+	re := sourceRules(t, "OpenAI").Pattern
 
 	// Simulated content from OpenAI's chat_model.py
 	content := `"gpt-4o", "gpt-4o-mini", "gpt-5", "gpt-3.5-turbo", "o1-mini", "o3-pro", "o4-mini"`
@@ -809,6 +892,127 @@ func TestFingerprintModels(t *testing.T) {
 	}
 }
 
+func TestExistingIssueWithFingerprint(t *testing.T) {
+	fp := fingerprintModels([]string{"gpt-9"})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Query().Get("q"), "label:auto-update") {
+			t.Errorf("expected auto-update label in query, got %q", r.URL.Query().Get("q"))
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"items": []map[string]any{
+				{"number": 1, "body": "unrelated"},
+				{"number": 2, "body": "report\n<!-- fingerprint:" + fp + " -->\n"},
+			},
+		})
+	}))
+	defer ts.Close()
+
+	c := github.NewClient(ts.Client(), "token", "owner/repo")
+	c.BaseURL = ts.URL
+	gh := forge.NewGitHub(c)
+
+	if !existingIssueWithFingerprint(context.Background(), gh, fp) {
+		t.Error("expected matching fingerprint to be found")
+	}
+	if existingIssueWithFingerprint(context.Background(), gh, fingerprintModels([]string{"other"})) {
+		t.Error("did not expect a different fingerprint to match")
+	}
+}
+
+func TestExistingPullRequestWithFingerprint(t *testing.T) {
+	fp := fingerprintModels([]string{"gpt-9"})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Query().Get("q"), "is:pr") {
+			t.Errorf("expected a pull request search, got %q", r.URL.Query().Get("q"))
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"items": []map[string]any{
+				{"number": 3, "body": "stubs\n<!-- fingerprint:" + fp + " -->\n"},
+			},
+		})
+	}))
+	defer ts.Close()
+
+	c := github.NewClient(ts.Client(), "token", "owner/repo")
+	c.BaseURL = ts.URL
+	gh := forge.NewGitHub(c)
+
+	if !existingPullRequestWithFingerprint(context.Background(), gh, fp) {
+		t.Error("expected the open pull request's fingerprint to be found")
+	}
+	if existingPullRequestWithFingerprint(context.Background(), gh, fingerprintModels([]string{"other"})) {
+		t.Error("did not expect a different fingerprint to match")
+	}
+}
+
+// gitRepo makes a repository with one commit in a temporary directory and
+// changes into it for the rest of the test.
+func gitRepo(t *testing.T) (dir string, git func(args ...string) string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir = t.TempDir()
+	git = func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "-q", "-b", "main")
+	git("config", "user.email", "updater@example.com")
+	git("config", "user.name", "updater")
+	os.WriteFile(filepath.Join(dir, "models.json"), []byte("[]\n"), 0o644)
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	return dir, git
+}
+
+func TestPushBranch_FailedPushRestoresBranch(t *testing.T) {
+	_, git := gitRepo(t)
+	os.WriteFile("models.json", []byte("[{}]\n"), 0o644)
+	// No origin remote, so the push fails after the commit.
+	if _, err := pushBranch(context.Background(), "updater/x", "msg", []string{"models.json"}, []string{"origin"}); err == nil {
+		t.Fatal("expected the push to fail")
+	}
+	if got := git("symbolic-ref", "--short", "HEAD"); got != "main" {
+		t.Errorf("checked-out branch = %s, want main", got)
+	}
+}
+
+func TestPushBranch_PushesEachRemote(t *testing.T) {
+	_, git := gitRepo(t)
+	mirror := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", "--bare", mirror).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v: %s", err, out)
+	}
+	git("remote", "add", "mirror", mirror)
+	os.WriteFile("models.json", []byte("[{}]\n"), 0o644)
+
+	pushed, err := pushBranch(context.Background(), "updater/x", "msg", []string{"models.json"}, []string{"mirror", "origin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pushed["mirror"] || pushed["origin"] {
+		t.Errorf("pushed = %v, want only mirror (origin doesn't exist)", pushed)
+	}
+	cmd := exec.Command("git", "rev-parse", "--verify", "updater/x")
+	cmd.Dir = mirror
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("branch missing on mirror: %v: %s", err, out)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Circuit breaker threshold tests
 // ---------------------------------------------------------------------------
@@ -970,3 +1174,1504 @@ func TestFetchModelsFromAPI(t *testing.T) {
 	}
 }
 
+
+// ---------------------------------------------------------------------------
+// New provider candidate tests
+// ---------------------------------------------------------------------------
+
+func TestNewProviderCandidates(t *testing.T) {
+	counts := map[string]int{
+		"openai":     40,
+		"meta-llama": 12,
+		"x-ai":       5,
+		"qwen":       30,
+		"liquid":     3,
+		"reka":       3,
+		"openrouter": 2,
+	}
+	got := newProviderCandidates(counts)
+	var slugs []string
+	for _, c := range got {
+		slugs = append(slugs, c.Slug)
+	}
+	want := []string{"qwen", "liquid", "reka"}
+	if strings.Join(slugs, ",") != strings.Join(want, ",") {
+		t.Errorf("expected candidates %v, got %v", want, slugs)
+	}
+}
+
+func TestProviderSlugAliases_MapToRegistryProviders(t *testing.T) {
+	providers := make(map[string]bool)
+	for _, m := range models.Models {
+		providers[m.Provider] = true
+	}
+	for slug, p := range providerSlugAliases {
+		if !providers[p] {
+			t.Errorf("slug %q maps to unknown registry provider %q", slug, p)
+		}
+	}
+}
+
+func TestFetchAggregatorCatalog(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"data": []map[string]any{
+				{"id": "qwen/qwen3-max", "created": 1758153600},
+				{"id": "qwen/qwen3-coder"},
+				{"id": "OpenAI/gpt-5"},
+				{"id": "no-slash"},
+			},
+		})
+	}))
+	defer ts.Close()
+
+	catalog, err := fetchAggregatorCatalog(context.Background(), ts.Client(), ts.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(catalog) != 4 {
+		t.Errorf("expected 4 catalog IDs, got %v", catalog)
+	}
+	if got := catalog[0].Created.Format(time.DateOnly); got != "2025-09-18" {
+		t.Errorf("created = %s, want 2025-09-18", got)
+	}
+	if !catalog[1].Created.IsZero() {
+		t.Errorf("missing created should be zero, got %v", catalog[1].Created)
+	}
+	counts := slugCounts(catalog)
+	if counts["qwen"] != 2 || counts["openai"] != 1 || len(counts) != 2 {
+		t.Errorf("unexpected slug counts: %v", counts)
+	}
+}
+
+func TestAggregatorIDs(t *testing.T) {
+	listed := func(month string) time.Time {
+		ts, _ := time.Parse("2006-01", month)
+		return ts
+	}
+	catalog := []aggregatorModel{
+		{ID: "meta-llama/llama-4-scout"},
+		{ID: "meta-llama/llama-4-scout:free"},
+		{ID: "meta-llama/llama-4-maverick"},
+		{ID: "meta-llama/llama-3.3-70b-instruct", Created: listed("2024-12")},
+		{ID: "meta-llama/llama-3.1-8b-instruct", Created: listed("2024-07")},
+		{ID: "meta-llama/llama-guard-3-8b", Created: listed("2025-02")},
+		{ID: "meta-llama/llama-guard-4-12b", Created: listed("2025-04")},
+		{ID: "meta-llama/llama-5-70b-instruct", Created: listed("2026-09")},
+		{ID: "microsoft/phi-4-multimodal-instruct"},
+		{ID: "moonshotai/kimi-k2.5"},
+		{ID: "nvidia/nemotron-3-nano-30b-a3b:free"},
+		{ID: "nvidia/llama-3.1-nemotron-70b-instruct", Created: listed("2024-10")},
+		{ID: "nvidia/nemotron-4-nano"},
+		{ID: "openai/gpt-5"},
+	}
+	tests := []struct {
+		provider string
+		want     []string
+		older    []string
+	}{
+		{"Meta", []string{"llama-3.3-70b", "llama-4-maverick", "llama-4-scout", "llama-5-70b"},
+			[]string{"llama-3.1-8b", "llama-guard-3-8b", "llama-guard-4-12b"}},
+		{"Microsoft", []string{"phi-4-multimodal-instruct"}, nil},
+		{"Moonshot", []string{"kimi-k2.5"}, nil},
+		{"NVIDIA", []string{"nvidia/nemotron-3-nano-30b-a3b", "nvidia/nemotron-4-nano"},
+			[]string{"nvidia/llama-3.1-nemotron-70b"}},
+		{"Tencent", nil, nil},
+	}
+	for _, tt := range tests {
+		got, older := aggregatorIDs(catalog, tt.provider, knownModels[tt.provider])
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("aggregatorIDs(%s) = %v, want %v", tt.provider, got, tt.want)
+		}
+		var olderIDs []string
+		for _, f := range older {
+			if f.Reason == "" {
+				t.Errorf("aggregatorIDs(%s): %s filtered without a reason", tt.provider, f.ID)
+			}
+			olderIDs = append(olderIDs, f.ID)
+		}
+		slices.Sort(olderIDs)
+		if !slices.Equal(olderIDs, tt.older) {
+			t.Errorf("aggregatorIDs(%s) older = %v, want %v", tt.provider, olderIDs, tt.older)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Deprecation batch splitting tests
+// ---------------------------------------------------------------------------
+
+func TestSplitMissingBatches(t *testing.T) {
+	byProvider := map[string][]string{
+		"OpenAI":    {"m5", "m1", "m3", "m2", "m4"},
+		"Anthropic": {"c1"},
+		"Google":    nil,
+	}
+	tracked := map[string]int{"OpenAI": 20, "Anthropic": 10}
+	batches := splitMissingBatches(byProvider, tracked, 2)
+
+	if len(batches) != 4 {
+		t.Fatalf("expected 4 batches (1 Anthropic + 3 OpenAI), got %d: %+v", len(batches), batches)
+	}
+	if batches[0].Provider != "Anthropic" || batches[0].Parts != 1 {
+		t.Errorf("first batch = %+v, want single Anthropic batch", batches[0])
+	}
+	var got []string
+	for _, b := range batches[1:] {
+		if b.Provider != "OpenAI" || b.Parts != 3 || b.Missing != 5 || len(b.IDs) > 2 {
+			t.Errorf("unexpected OpenAI batch %+v", b)
+		}
+		got = append(got, b.IDs...)
+	}
+	if strings.Join(got, ",") != "m1,m2,m3,m4,m5" {
+		t.Errorf("OpenAI IDs across batches = %v, want sorted m1..m5", got)
+	}
+}
+
+func TestMissingBatchConfidence(t *testing.T) {
+	tests := []struct {
+		b      missingBatch
+		level  string
+		verify bool
+	}{
+		{missingBatch{Provider: "OpenAI", Missing: 1, Tracked: 20, Parts: 1}, "high", false},
+		{missingBatch{Provider: "OpenAI", Missing: 6, Tracked: 20, Parts: 1}, "medium", true},
+		{missingBatch{Provider: "OpenAI", Missing: 12, Tracked: 20, Parts: 2}, "low", true},
+		{missingBatch{Provider: "OpenAI", Missing: 3, Tracked: 40, Parts: 2}, "medium", true},
+	}
+	for _, tt := range tests {
+		level, note := tt.b.confidence()
+		if level != tt.level || note == "" {
+			t.Errorf("confidence(%+v) = %q, %q; want level %q", tt.b, level, note, tt.level)
+		}
+		if tt.b.needsVerification() != tt.verify {
+			t.Errorf("needsVerification(%+v) = %v, want %v", tt.b, !tt.verify, tt.verify)
+		}
+	}
+}
+
+func TestMaxBatchModels(t *testing.T) {
+	t.Setenv("UPDATER_MAX_BATCH_MODELS", "")
+	if got := maxBatchModels(); got != defaultMaxBatchModels {
+		t.Errorf("default = %d, want %d", got, defaultMaxBatchModels)
+	}
+	t.Setenv("UPDATER_MAX_BATCH_MODELS", "3")
+	if got := maxBatchModels(); got != 3 {
+		t.Errorf("override = %d, want 3", got)
+	}
+	t.Setenv("UPDATER_MAX_BATCH_MODELS", "-1")
+	if got := maxBatchModels(); got != defaultMaxBatchModels {
+		t.Errorf("invalid value = %d, want default", got)
+	}
+}
+
+func TestDeprecationIssueTitleAndBody(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	b := missingBatch{Provider: "OpenAI", IDs: []string{"gpt-5", "o3"}, Tracked: 20, Missing: 12, Part: 2, Parts: 3}
+	if got := deprecationIssueTitle(b, now); got != "Models removed from OpenAI docs - 2026-03-01 (part 2/3)" {
+		t.Errorf("title = %q", got)
+	}
+	body := deprecationIssueBody(b, nil, "report", "fp")
+	for _, want := range []string{"Requires human verification", "part 2 of 3", "| `gpt-5` | low |", "| `o3` | low |", "<!-- fingerprint:fp -->"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+
+	single := missingBatch{Provider: "OpenAI", IDs: []string{"gpt-5"}, Tracked: 20, Missing: 1, Part: 1, Parts: 1}
+	if got := deprecationIssueTitle(single, now); strings.Contains(got, "part") {
+		t.Errorf("single batch title should not mention parts: %q", got)
+	}
+	if strings.Contains(deprecationIssueBody(single, nil, "", "fp"), "Requires human verification") {
+		t.Error("high-confidence batch should not require verification")
+	}
+}
+
+func TestCreateDeprecationIssue_AddsVerificationLabel(t *testing.T) {
+	var labels []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			json.NewEncoder(w).Encode(map[string]any{"items": []any{}})
+			return
+		}
+		var req struct {
+			Labels []string `json:"labels"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		labels = req.Labels
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]any{"number": 1, "html_url": "https://example.com/1"})
+	}))
+	defer ts.Close()
+
+	c := github.NewClient(ts.Client(), "token", "owner/repo")
+	c.BaseURL = ts.URL
+	gh := forge.NewGitHub(c)
+
+	b := missingBatch{Provider: "OpenAI", IDs: []string{"gpt-5"}, Tracked: 2, Missing: 2, Part: 1, Parts: 1}
+	createDeprecationIssue(context.Background(), gh, b, nil, "report")
+	sort.Strings(labels)
+	if strings.Join(labels, ",") != "auto-update,"+verificationLabel {
+		t.Errorf("labels = %v, want auto-update and %q", labels, verificationLabel)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Scraper pattern rot tests
+// ---------------------------------------------------------------------------
+
+func TestScrapeHistory_RecordAndAverage(t *testing.T) {
+	h := &scrapeHistory{Counts: make(map[string][]countSample)}
+	for i := 1; i <= historyWindow+3; i++ {
+		h.record("OpenAI/docs", fmt.Sprintf("2026-01-%02d", i), 40)
+	}
+	if got := len(h.Counts["OpenAI/docs"]); got != historyWindow {
+		t.Errorf("kept %d samples, want %d", got, historyWindow)
+	}
+
+	// A rerun on the same day replaces that day's sample and is excluded
+	// from the trailing average.
+	h.record("OpenAI/docs", "2026-01-17", 0)
+	avg, runs := h.trailingAverage("OpenAI/docs", "2026-01-17")
+	if avg != 40 || runs != historyWindow-1 {
+		t.Errorf("trailingAverage = %.1f over %d runs, want 40 over %d", avg, runs, historyWindow-1)
+	}
+}
+
+func TestScrapeHistory_SaveLoad(t *testing.T) {
+	path := t.TempDir() + "/history.json"
+	h, err := loadHistory(path)
+	if err != nil {
+		t.Fatalf("missing file should load empty history: %v", err)
+	}
+	h.record("Google/docs", "2026-01-01", 25)
+	if err := h.save(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := got.Counts["Google/docs"]; len(s) != 1 || s[0].Count != 25 {
+		t.Errorf("round trip = %+v", got.Counts)
+	}
+}
+
+func TestPatternRotted(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int
+		avg   float64
+		runs  int
+		want  bool
+	}{
+		{"zero against history", 0, 40, 5, true},
+		{"collapse below threshold", 15, 40, 5, true},
+		{"small drop is a real removal", 36, 40, 5, false},
+		{"growth", 45, 40, 5, false},
+		{"not enough history", 0, 40, minHistoryRuns - 1, false},
+	}
+	for _, tt := range tests {
+		if got := patternRotted(tt.count, tt.avg, tt.runs, defaultRotThreshold); got != tt.want {
+			t.Errorf("%s: patternRotted(%d, %.0f, %d) = %v, want %v", tt.name, tt.count, tt.avg, tt.runs, got, tt.want)
+		}
+	}
+}
+
+func TestRotThreshold(t *testing.T) {
+	t.Setenv("UPDATER_ROT_THRESHOLD", "")
+	if got := rotThreshold(); got != defaultRotThreshold {
+		t.Errorf("default = %v, want %v", got, defaultRotThreshold)
+	}
+	t.Setenv("UPDATER_ROT_THRESHOLD", "0.3")
+	if got := rotThreshold(); got != 0.3 {
+		t.Errorf("override = %v, want 0.3", got)
+	}
+	t.Setenv("UPDATER_ROT_THRESHOLD", "2")
+	if got := rotThreshold(); got != defaultRotThreshold {
+		t.Errorf("out of range = %v, want default", got)
+	}
+}
+
+func TestScraperRotIssueBody(t *testing.T) {
+	alerts := []rotAlert{{Key: "OpenAI/docs", Count: 0, Average: 41.5, Runs: 7}}
+	body := scraperRotIssueBody(alerts, 0.5, "report", "abc")
+	for _, want := range []string{"fewer than 50%", "| `OpenAI/docs` | 0 | 41.5 | 7 |", "<!-- fingerprint:abc -->"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Provider deprecation notice tests
+// ---------------------------------------------------------------------------
+
+const deprecationPageFixture = `
+<h3>2025-10-28: Claude Sonnet 3.7</h3>
+<table>
+<tr><th>Retirement Date</th><th>Deprecated Model</th><th>Recommended Replacement</th></tr>
+<tr><td>February 19, 2026</td><td><code>claude-3-7-sonnet-20250219</code></td><td><code>claude-opus-4-6</code></td></tr>
+</table>
+<h3>2026-01-05: Claude Opus 4</h3>
+<table>
+<tr><td>Sept. 15, 2026</td><td>claude-opus-4-20250514</td><td>claude-opus-4-6</td></tr>
+<tr><td>-</td><td>claude-opus-4-0</td><td></td></tr>
+<tr><td>2026-09-15</td><td>claude-instant-1.2</td><td></td></tr>
+</table>
+<h3>Model status</h3>
+<table>
+<tr><th>API model name</th><th>Current state</th><th>Tentative retirement date</th></tr>
+<tr><td>claude-sonnet-4-6</td><td>Active</td><td>Not sooner than February 17, 2027</td></tr>
+<tr><td>Claude Sonnet 4.5</td><td>Deprecated</td><td>October 28, 2026</td></tr>
+</table>`
+
+func TestParseDeprecationNotices(t *testing.T) {
+	notices := parseDeprecationNotices(deprecationPageFixture, providerModelIDs("Anthropic"))
+	want := []deprecationNotice{
+		{ModelID: "claude-3-7-sonnet-20250219", DeprecationDate: "2025-10-28", SunsetDate: "2026-02-19", Replacement: "claude-opus-4-6"},
+		{ModelID: "claude-opus-4-0", DeprecationDate: "2026-01-05", SunsetDate: "2026-09-15", Replacement: "claude-opus-4-6"},
+	}
+	if len(notices) != len(want) {
+		t.Fatalf("got %d notices, want %d: %+v", len(notices), len(want), notices)
+	}
+	for i, w := range want {
+		if notices[i] != w {
+			t.Errorf("notice %d = %+v, want %+v", i, notices[i], w)
+		}
+	}
+}
+
+func TestPendingNotices(t *testing.T) {
+	notices := map[string]deprecationNotice{
+		"claude-3-7-sonnet-20250219": {ModelID: "claude-3-7-sonnet-20250219", SunsetDate: "2026-02-19"}, // already deprecated
+		"claude-opus-4-0":            {ModelID: "claude-opus-4-0", SunsetDate: "2026-09-15"},
+		"claude-opus-4-1":            {ModelID: "claude-opus-4-1", SunsetDate: "2026-03-01"},
+		"claude-sonnet-4-0":          {ModelID: "claude-sonnet-4-0"},
+	}
+	pending := pendingNotices(notices)
+	var ids []string
+	for _, n := range pending {
+		ids = append(ids, n.ModelID)
+	}
+	if got := strings.Join(ids, ","); got != "claude-opus-4-1,claude-opus-4-0,claude-sonnet-4-0" {
+		t.Errorf("pending = %s, want soonest sunset first and undated last", got)
+	}
+}
+
+func TestNewModelsIssueBody(t *testing.T) {
+	body := newModelsIssueBody(map[string][]string{
+		"xAI":    {"grok-9"},
+		"OpenAI": {"gpt-6-mini", "gpt-6"},
+		"Google": nil,
+	}, "report", "abc")
+	for _, want := range []string{
+		"3 model ID(s) from 2 provider(s)",
+		"<summary><strong>OpenAI</strong> (2 new)</summary>\n\n- [ ] add `gpt-6`\n- [ ] add `gpt-6-mini`\n",
+		"<summary><strong>xAI</strong> (1 new)</summary>\n\n- [ ] add `grok-9`\n",
+		"<!-- fingerprint:abc -->",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "Google") {
+		t.Errorf("providers with no new models should have no section:\n%s", body)
+	}
+	if strings.Index(body, "OpenAI") > strings.Index(body, "xAI") {
+		t.Errorf("provider sections should be sorted:\n%s", body)
+	}
+	// Only the per-model tasks are checkboxes, so the issue's progress
+	// counter tracks models.
+	if n := strings.Count(body, "- [ ]"); n != 3 {
+		t.Errorf("expected 3 task checkboxes, got %d:\n%s", n, body)
+	}
+}
+
+func TestDeprecationIssueBody_ConfirmedByNotice(t *testing.T) {
+	b := missingBatch{Provider: "Anthropic", IDs: []string{"claude-opus-4-0"}, Tracked: 20, Missing: 1, Part: 1, Parts: 1}
+	notices := map[string]deprecationNotice{
+		"claude-opus-4-0": {ModelID: "claude-opus-4-0", DeprecationDate: "2026-01-05", SunsetDate: "2026-09-15"},
+	}
+	body := deprecationIssueBody(b, notices, "", "fp")
+	if !strings.Contains(body, "| `claude-opus-4-0` | confirmed | provider notice: deprecated 2026-01-05, sunset 2026-09-15 |") {
+		t.Errorf("expected confirmed row with notice dates:\n%s", body)
+	}
+}
+
+func TestFetchDeprecationNotices(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, deprecationPageFixture)
+	}))
+	defer srv.Close()
+
+	notices, truncated, err := fetchDeprecationNotices(context.Background(), srv.Client(), "Anthropic", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if truncated {
+		t.Error("small fixture should not be truncated")
+	}
+	if len(notices) != 2 || notices[0].Source != srv.URL {
+		t.Errorf("unexpected notices: %+v", notices)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Provenance tests
+// ---------------------------------------------------------------------------
+
+func TestNewProvenance_CIEnvironments(t *testing.T) {
+	started := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, k := range []string{"GITHUB_RUN_ID", "GITHUB_SERVER_URL", "GITHUB_REPOSITORY", "GITHUB_SHA", "CI_PIPELINE_ID", "CI_PIPELINE_URL", "CI_COMMIT_SHA"} {
+		t.Setenv(k, "")
+	}
+	if p := newProvenance(started); p.RunID != "local-20260301T120000Z" || p.RunURL != "" {
+		t.Errorf("local run = %+v", p)
+	}
+
+	t.Setenv("CI_PIPELINE_ID", "77")
+	t.Setenv("CI_PIPELINE_URL", "https://gitlab.example.com/g/p/-/pipelines/77")
+	if p := newProvenance(started); p.RunID != "77" || p.RunURL != "https://gitlab.example.com/g/p/-/pipelines/77" {
+		t.Errorf("GitLab run = %+v", p)
+	}
+
+	t.Setenv("GITHUB_RUN_ID", "123")
+	t.Setenv("GITHUB_SERVER_URL", "https://github.com")
+	t.Setenv("GITHUB_REPOSITORY", "owner/repo")
+	t.Setenv("GITHUB_SHA", "abc123")
+	p := newProvenance(started)
+	if p.RunURL != "https://github.com/owner/repo/actions/runs/123" || p.Commit != "abc123" {
+		t.Errorf("GitHub run = %+v", p)
+	}
+}
+
+func TestPatternVersion_TracksExtractionRules(t *testing.T) {
+	v := patternVersion()
+	if len(v) != 12 || v != patternVersion() {
+		t.Fatalf("patternVersion should be a stable 12-char hash, got %q", v)
+	}
+	saved := providerSources["OpenAI"]
+	t.Cleanup(func() { providerSources["OpenAI"] = saved })
+	changed := saved.(GitHubFileSource)
+	changed.Pattern = regexp.MustCompile(`(gpt-\d+)`)
+	providerSources["OpenAI"] = changed
+	if patternVersion() == v {
+		t.Error("changing a doc source pattern should change the version")
+	}
+}
+
+func TestFetchModelsFromDocs_RecordsSource(t *testing.T) {
+	const page = `<code>gpt-5</code> <code>gpt-5-mini</code>`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/moved" {
+			fmt.Fprint(w, "<p>This page has moved.</p>")
+			return
+		}
+		fmt.Fprint(w, page)
+	}))
+	defer srv.Close()
+
+	src := DocSource{URLs: []string{srv.URL + "/moved", srv.URL + "/models"}, Pattern: regexp.MustCompile(`<code>(gpt-[\w.-]+)</code>`)}
+	ids, rec, err := fetchModelsFromDocs(context.Background(), srv.Client(), src)
+	if err != nil || len(ids) != 2 {
+		t.Fatalf("ids = %v, err = %v", ids, err)
+	}
+	if rec.URL != srv.URL+"/models" || rec.SHA256 != bodyDigest(page) {
+		t.Errorf("record = %+v, want the fallback URL and its body digest", rec)
+	}
+}
+
+func TestCreateIssue_AppendsProvenance(t *testing.T) {
+	var body string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Body string `json:"body"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		body = req.Body
+		json.NewEncoder(w).Encode(map[string]any{"number": 1, "html_url": "https://example.com/1"})
+	}))
+	defer ts.Close()
+	c := github.NewClient(ts.Client(), "token", "owner/repo")
+	c.BaseURL = ts.URL
+
+	runProvenance = &provenance{RunID: "42", RunURL: "https://ci.example.com/42", PatternVersion: "0123456789ab", Started: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	t.Cleanup(func() { runProvenance = nil })
+	runProvenance.record(sourceRecord{Provider: "OpenAI", Kind: "docs", URL: "https://platform.openai.com/docs/models", SHA256: bodyDigest("x"), IDs: 12})
+
+	createIssue(context.Background(), forge.NewGitHub(c), "t", "report")
+	for _, want := range []string{
+		"report\n<details>\n<summary>Provenance</summary>",
+		"| Run | [42](https://ci.example.com/42) |",
+		"| Extraction rules | `0123456789ab` |",
+		"| OpenAI | docs | https://platform.openai.com/docs/models | 12 | `" + bodyDigest("x")[:16] + "` |",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("issue body missing %q:\n%s", want, body)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Markdown/MDX source tests
+// ---------------------------------------------------------------------------
+
+const mistralMDXFixture = `---
+id: models_overview
+title: Models Overview
+---
+
+import Tabs from '@theme/Tabs';
+
+## Premier models
+
+| Model | Available via API | Description | API Endpoints | Version |
+|:------|:-----------------:|-------------|---------------|---------|
+| Mistral Medium 3 | :heavy_check_mark: | Frontier-class multimodal model | ` + "`mistral-medium-2505`" + ` | 25.05 |
+| Codestral | :heavy_check_mark: | Coding model, see [mistral-fake-9999](https://example.com/codestral-0000) | ` + "`codestral-2501`" + ` \| ` + "`codestral-latest`" + ` | 25.01 |
+
+` + "```python" + `
+| not | a | table |
+|---|---|---|
+| x | y | mistral-small-1111 |
+` + "```" + `
+
+## Free models
+
+| Model | API Endpoints |
+|---|---|
+| Mistral Small 3.1 | ` + "`mistral-small-2503`" + ` |
+| Mistral Embed | ` + "`mistral-embed-2312`" + ` |
+`
+
+func TestMarkdownColumn(t *testing.T) {
+	cells, found := markdownColumn(mistralMDXFixture, regexp.MustCompile(`(?i)api endpoint`))
+	if !found {
+		t.Fatal("expected the API Endpoints column to be found")
+	}
+	got := strings.Join(cells, " / ")
+	want := "`mistral-medium-2505` / `codestral-2501` | `codestral-latest` / `mistral-small-2503` / `mistral-embed-2312`"
+	if got != want {
+		t.Errorf("cells = %q\nwant     %q", got, want)
+	}
+	if _, found := markdownColumn(mistralMDXFixture, regexp.MustCompile(`(?i)model id`)); found {
+		t.Error("expected no match for a missing column")
+	}
+}
+
+func TestSplitMarkdownRow(t *testing.T) {
+	got := splitMarkdownRow(`| a \| b | [link](https://x.example/c-1234) | c |`)
+	if strings.Join(got, ",") != "a | b,[link],c" {
+		t.Errorf("cells = %q", got)
+	}
+	if got := splitMarkdownRow("a | b"); len(got) != 2 || got[1] != "b" {
+		t.Errorf("rows without outer pipes: %q", got)
+	}
+}
+
+func TestFetchModelsFromDocs_MarkdownFirst(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/models_overview.md":
+			fmt.Fprint(w, mistralMDXFixture)
+		case "/renamed.md":
+			fmt.Fprint(w, "| Name | Notes |\n|---|---|\n| x | mistral-large-2411 |\n")
+		default:
+			fmt.Fprint(w, "<code>mistral-large-2411</code>")
+		}
+	}))
+	defer srv.Close()
+
+	src := sourceRules(t, "Mistral")
+	src.MarkdownURLs = []string{srv.URL + "/models_overview.md"}
+	src.URLs = []string{srv.URL + "/models/"}
+	ids, rec, err := fetchModelsFromDocs(context.Background(), srv.Client(), src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(ids)
+	if strings.Join(ids, ",") != "codestral-2501,mistral-medium-2505,mistral-small-2503" || rec.Kind != "markdown" {
+		t.Errorf("ids = %v, record = %+v; want table IDs only (no links, code blocks, or embed)", ids, rec)
+	}
+
+	// A markdown file whose ID column was renamed falls back to the HTML page.
+	src.MarkdownURLs = []string{srv.URL + "/renamed.md"}
+	ids, rec, err = fetchModelsFromDocs(context.Background(), srv.Client(), src)
+	if err != nil || rec.Kind != "docs" || strings.Join(ids, ",") != "mistral-large-2411" {
+		t.Errorf("fallback: ids = %v, record = %+v, err = %v", ids, rec, err)
+	}
+}
+
+// bedrockOfferFixture is a trimmed AWS Price List offer for AmazonBedrock:
+// Amazon's own text models (several SKUs each), media models, and another
+// vendor's model.
+const bedrockOfferFixture = `{
+  "formatVersion": "v1.0",
+  "offerCode": "AmazonBedrock",
+  "products": {
+    "SKU1": {"sku": "SKU1", "attributes": {"servicecode": "AmazonBedrock", "model": "Nova Pro", "inferenceType": "Input tokens"}},
+    "SKU2": {"sku": "SKU2", "attributes": {"servicecode": "AmazonBedrock", "model": "Nova Pro", "inferenceType": "Output tokens"}},
+    "SKU3": {"sku": "SKU3", "attributes": {"servicecode": "AmazonBedrock", "model": "Nova Pro Latency Optimized"}},
+    "SKU4": {"sku": "SKU4", "attributes": {"servicecode": "AmazonBedrock", "model": "Nova 2 Lite"}},
+    "SKU5": {"sku": "SKU5", "attributes": {"servicecode": "AmazonBedrock", "model": "Amazon Nova Micro"}},
+    "SKU6": {"sku": "SKU6", "attributes": {"servicecode": "AmazonBedrock", "model": "Nova Canvas"}},
+    "SKU7": {"sku": "SKU7", "attributes": {"servicecode": "AmazonBedrock", "model": "Nova Multimodal Embeddings"}},
+    "SKU8": {"sku": "SKU8", "attributes": {"servicecode": "AmazonBedrock", "model": "Claude 3.5 Sonnet"}},
+    "SKU9": {"sku": "SKU9", "attributes": {"servicecode": "AmazonBedrock", "usagetype": "USE1-Guardrails"}}
+  }
+}`
+
+func TestFetchModelsFromDocs_Offer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, bedrockOfferFixture)
+	}))
+	defer srv.Close()
+
+	src := sourceRules(t, "Amazon")
+	src.OfferURLs = []string{srv.URL + "/index.json"}
+	ids, rec, err := fetchModelsFromDocs(context.Background(), srv.Client(), src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(ids)
+	if strings.Join(ids, ",") != "amazon-nova-2-lite,amazon-nova-micro,amazon-nova-pro" || rec.Kind != "offer" || rec.SHA256 != bodyDigest(bedrockOfferFixture) {
+		t.Errorf("ids = %v, record = %+v; want Amazon's text models only", ids, rec)
+	}
+	for _, id := range ids {
+		if !knownModels["Amazon"][id] {
+			t.Errorf("%s does not match a tracked Amazon ID", id)
+		}
+	}
+
+	// A truncated offer is not valid JSON; report it rather than a partial list.
+	src.MaxBodyBytes = 100
+	if _, _, err := fetchModelsFromDocs(context.Background(), srv.Client(), src); err == nil || !strings.Contains(err.Error(), "MaxBodyBytes") {
+		t.Errorf("expected a truncation error, got %v", err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Multi-target reporting tests
+// ---------------------------------------------------------------------------
+
+func TestForEachHost_FilesOnEveryTarget(t *testing.T) {
+	ids := []string{"gpt-9"}
+	fp := fingerprintModels(ids)
+	var created sync.Map
+	newHost := func(repo string, existing bool) forge.Forge {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				created.Store(repo, true)
+				json.NewEncoder(w).Encode(map[string]any{"number": 1, "html_url": "https://example.com/" + repo + "/issues/1"})
+				return
+			}
+			var items []map[string]any
+			if existing {
+				items = append(items, map[string]any{"number": 3, "body": "<!-- fingerprint:" + fp + " -->"})
+			}
+			json.NewEncoder(w).Encode(map[string]any{"items": items})
+		}))
+		t.Cleanup(ts.Close)
+		c := github.NewClient(ts.Client(), "token", repo)
+		c.BaseURL = ts.URL
+		return forge.NewGitHub(c)
+	}
+	hosts := []forge.Forge{newHost("upstream/registry", false), newHost("fork/registry", false), newHost("mirror/registry", true)}
+
+	forEachHost(hosts, func(host forge.Forge) {
+		createNewModelsIssue(context.Background(), host, map[string][]string{"OpenAI": ids}, "report")
+	})
+
+	for repo, want := range map[string]bool{"upstream/registry": true, "fork/registry": true, "mirror/registry": false} {
+		if _, got := created.Load(repo); got != want {
+			t.Errorf("%s: issue created = %v, want %v (each target dedupes against its own issues)", repo, got, want)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Page size limits
+// ---------------------------------------------------------------------------
+
+func TestFetchAndExtract_TruncatedPage(t *testing.T) {
+	page := `"gpt-5.2",` + strings.Repeat(" ", 200) + `"o4-mini",`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, page)
+	}))
+	defer srv.Close()
+
+	pattern := sourceRules(t, "OpenAI").Pattern
+	ids, rec, err := fetchAndExtract(context.Background(), srv.Client(), srv.URL, pattern, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rec.Truncated {
+		t.Error("expected the page to be reported as truncated")
+	}
+	if strings.Join(ids, ",") != "gpt-5.2" {
+		t.Errorf("IDs = %v, want only the ID before the cutoff", ids)
+	}
+
+	ids, rec, err = fetchAndExtract(context.Background(), srv.Client(), srv.URL, pattern, int64(len(page)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Truncated {
+		t.Error("a page of exactly the limit should not be truncated")
+	}
+	if len(ids) != 2 {
+		t.Errorf("IDs = %v, want both", ids)
+	}
+}
+
+func TestDocSourceBodyLimit(t *testing.T) {
+	if got := (DocSource{}).bodyLimit(); got != defaultMaxBodyBytes {
+		t.Errorf("default limit = %d, want %d", got, defaultMaxBodyBytes)
+	}
+	if got := sourceRules(t, "Google").bodyLimit(); got <= defaultMaxBodyBytes {
+		t.Errorf("Google limit = %d, want above the %d default", got, defaultMaxBodyBytes)
+	}
+}
+
+func TestProvenanceMarksTruncatedSources(t *testing.T) {
+	p := &provenance{}
+	p.record(sourceRecord{Provider: "Google", Kind: "docs", URL: "https://example.com", IDs: 12, Truncated: true})
+	if md := p.section(); !strings.Contains(md, "| 12 (truncated) |") {
+		t.Errorf("expected truncated marker:\n%s", md)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Sync status
+// ---------------------------------------------------------------------------
+
+func TestSyncStatus_FailureKeepsLastVerification(t *testing.T) {
+	path := t.TempDir() + "/status.json"
+	s, err := loadSyncStatus(path)
+	if err != nil {
+		t.Fatalf("missing file should load empty status: %v", err)
+	}
+	day1 := time.Date(2026, 3, 1, 6, 0, 0, 0, time.UTC)
+	s.verified("Google", day1, "docs", 25, []string{"gemini-9-pro"}, nil)
+	if err := s.save(path, "run-1", day1); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = loadSyncStatus(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	day2 := day1.Add(24 * time.Hour)
+	s.failed("Google", day2, "all 3 attempts failed")
+	if err := s.save(path, "run-2", day2); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := loadSyncStatus(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := got.Providers["Google"]
+	if got.RunID != "run-2" || g.CheckedAt != "2026-03-02T06:00:00Z" {
+		t.Errorf("run = %s, checked_at = %s", got.RunID, g.CheckedAt)
+	}
+	if g.VerifiedAt != "2026-03-01T06:00:00Z" || len(g.New) != 1 || g.Error == "" {
+		t.Errorf("failed run should keep verification and drift and record the error: %+v", g)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Run budget
+// ---------------------------------------------------------------------------
+
+func TestDurationEnv(t *testing.T) {
+	t.Setenv("UPDATER_RUN_BUDGET", "3m")
+	if got := runBudget(); got != 3*time.Minute {
+		t.Errorf("runBudget = %s, want 3m", got)
+	}
+	for _, bad := range []string{"", "soon", "-1m", "0s"} {
+		t.Setenv("UPDATER_PROVIDER_TIMEOUT", bad)
+		if got := providerTimeout(); got != defaultProviderTimeout {
+			t.Errorf("UPDATER_PROVIDER_TIMEOUT=%q: got %s, want the default", bad, got)
+		}
+	}
+}
+
+func TestFetchPage_StopsRetryingWhenContextExpires(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, _, err := fetchPage(ctx, srv.Client(), srv.URL, defaultMaxBodyBytes)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want a deadline error", err)
+	}
+	// Without the context check the retry backoff alone sleeps 6s.
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("fetchPage took %s after its context expired", elapsed)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Alias heuristic properties, real-ID corpus, and filter explanations
+// ---------------------------------------------------------------------------
+
+func TestAliasReason_NamesTheHeuristic(t *testing.T) {
+	known := map[string]bool{"gpt-5": true, "o3-mini-2025": true, "gemini-3-flash-preview": true, "codestral-2508": true, "devstral": true}
+	for id, want := range map[string]string{
+		"o3-mini":        "known o3-mini-2025 extends it with numeric suffix -2025",
+		"gpt-5-latest":   "extends known gpt-5 with alias suffix -latest",
+		"gemini-3-flash": "known gemini-3-flash-preview extends it with alias suffix -preview",
+		"devstral-2507":  "numeric variant of known devstral",
+		"codestral-2405": "older release of known codestral-2508",
+		"codestral-25":   "",
+		"gpt-5-turbo":    "",
+	} {
+		if got := aliasReason(id, known); got != want {
+			t.Errorf("aliasReason(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestAliasReason_Deterministic(t *testing.T) {
+	// "gpt-5-latest" matches both known IDs; map order must not pick the reason.
+	known := map[string]bool{"gpt-5": true, "gpt-5-latest-2026": true, "a": true, "b": true, "c": true}
+	want := aliasReason("gpt-5-latest", known)
+	for i := 0; i < 50; i++ {
+		if got := aliasReason("gpt-5-latest", known); got != want {
+			t.Fatalf("run %d: %q, first run %q", i, got, want)
+		}
+	}
+}
+
+// TestAliasHeuristics_Properties checks invariants against every provider's
+// real known set, so a new entry in knownModels can't quietly break them.
+func TestAliasHeuristics_Properties(t *testing.T) {
+	for provider, known := range knownModels {
+		for id := range known {
+			// A tracked ID is never reported as new or filtered.
+			if newModels, _, filtered := diffExplained(known, []string{id}); len(newModels)+len(filtered) != 0 {
+				t.Errorf("%s: tracked %s reported as new %v / filtered %v", provider, id, newModels, filtered)
+			}
+			// Any tracked ID plus a convenience suffix is an alias of it.
+			for _, suffix := range []string{"-latest", "-preview", "-latest-non-reasoning"} {
+				if !isKnownAlias(id+suffix, known) {
+					t.Errorf("%s: %s%s not recognized as an alias of %s", provider, id, suffix, id)
+				}
+			}
+			// A dated snapshot of a tracked ID is filtered as a snapshot.
+			if got := filterReason(id+"-2099-01-01", known); got != "date-stamped snapshot" {
+				t.Errorf("%s: %s-2099-01-01 filtered as %q", provider, id, got)
+			}
+			// An ID sharing no prefix with anything tracked is never an alias.
+			if reason := aliasReason("zz-unrelated-"+id+"-turbo", known); reason != "" {
+				t.Errorf("%s: unrelated ID matched: %s", provider, reason)
+			}
+		}
+	}
+}
+
+// TestAliasHeuristics_Corpus pins verdicts for IDs providers have actually
+// listed. filtered=false entries are genuine models that must reach the NEW
+// list; a heuristic change that hides one of them fails here.
+func TestAliasHeuristics_Corpus(t *testing.T) {
+	known := map[string]bool{
+		"gpt-5": true, "gpt-5-mini": true, "gpt-4.1-mini": true, "gpt-4.1-nano": true, "o3": true,
+		"gemini-2.5-pro": true, "gemini-3-flash-preview": true,
+		"codestral-2508": true, "devstral-2512": true, "magistral-small-2509": true,
+		"grok-4-fast": true, "claude-sonnet-4-5": true,
+	}
+	for _, tt := range []struct {
+		id       string
+		filtered bool
+	}{
+		{"gpt-5-2025-08-07", true},
+		{"gpt-4.1-mini-20250414", true},
+		{"gpt-5-chat-latest", true},
+		{"gpt-5-mini-latest", true},
+		{"gpt-4.1", true}, // reverse match via gpt-4.1-mini
+		{"gemini-3-flash", true},
+		{"gemini-2.5-pro-preview", true},
+		{"grok-4-fast-reasoning", true},
+		{"grok-4-fast-non-reasoning", true},
+		{"codestral-2501", true},
+		// Current Codestral releases are newer than, or not comparable
+		// with, the tracked codestral-2508 and must be reported.
+		{"codestral-25", false},
+		{"codestral-2512", false},
+		{"devstral-2507", true},
+		{"gpt-5-codex", false},
+		{"gpt-5-pro", false},
+		{"gpt-5.1", false},
+		{"o3-pro", false},
+		{"o4-mini", false},
+		{"gemini-3-pro-preview", false},
+		{"gemini-2.5-flash", false},
+		{"magistral-medium-2509", false},
+		{"devstral-small-2507", false},
+		{"grok-code-fast-1", false},
+		{"claude-opus-4-5", false},
+		{"claude-sonnet-4-6", false},
+	} {
+		got := isDateStampVariant(tt.id) || isKnownAlias(tt.id, known)
+		if got != tt.filtered {
+			t.Errorf("%s: filtered = %v, want %v (reason %q)", tt.id, got, tt.filtered, aliasReason(tt.id, known))
+		}
+	}
+}
+
+func FuzzAliasReason(f *testing.F) {
+	for _, seed := range []string{"gpt-5-latest", "codestral-25", "gemini-3-flash", "gpt-4.1", "-", "a--b", "x-00", ""} {
+		f.Add(seed)
+	}
+	known := map[string]bool{"gpt-5": true, "gpt-4.1-mini": true, "gemini-3-flash-preview": true, "codestral-2508": true, "devstral": true}
+	f.Fuzz(func(t *testing.T, id string) {
+		reason := aliasReason(id, known)
+		if (reason != "") != isKnownAlias(id, known) {
+			t.Fatalf("aliasReason and isKnownAlias disagree on %q", id)
+		}
+		if known[id] && strings.Contains(reason, "known "+id+" ") {
+			t.Fatalf("%q explained as an alias of itself: %s", id, reason)
+		}
+		if aliasReason(id, map[string]bool{}) != "" {
+			t.Fatalf("%q is an alias with nothing known", id)
+		}
+	})
+}
+
+func TestDiffExplained_ReportsFilteredIDs(t *testing.T) {
+	known := map[string]bool{"gpt-5": true}
+	newModels, _, filtered := diffExplained(known, []string{"gpt-5", "gpt-5-latest", "gpt-5-2025-08-07", "gpt-6"})
+	if len(newModels) != 1 || newModels[0] != "gpt-6" {
+		t.Errorf("new = %v, want [gpt-6]", newModels)
+	}
+	want := []filteredID{
+		{"gpt-5-2025-08-07", "date-stamped snapshot"},
+		{"gpt-5-latest", "extends known gpt-5 with alias suffix -latest"},
+	}
+	if fmt.Sprint(filtered) != fmt.Sprint(want) {
+		t.Errorf("filtered = %v, want %v", filtered, want)
+	}
+}
+
+func TestExplainFiltered(t *testing.T) {
+	t.Setenv("UPDATER_EXPLAIN_FILTERED", "")
+	if explainFiltered() {
+		t.Error("explain mode should be off by default")
+	}
+	t.Setenv("UPDATER_EXPLAIN_FILTERED", "true")
+	if !explainFiltered() {
+		t.Error("UPDATER_EXPLAIN_FILTERED=true should enable explain mode")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Review mode: interactive decisions, staged edits, and the mute list
+// ---------------------------------------------------------------------------
+
+func TestRunReview_Decisions(t *testing.T) {
+	items := []reviewItem{
+		{Kind: reviewNew, Provider: "OpenAI", ModelID: "gpt-6"},
+		{Kind: reviewMissing, Provider: "OpenAI", ModelID: "o3"},
+		{Kind: reviewNotice, Provider: "Anthropic", ModelID: "claude-3-haiku", Detail: "sunset 2026-01-01"},
+		{Kind: reviewNew, Provider: "xAI", ModelID: "grok-9"},
+		{Kind: reviewNew, Provider: "xAI", ModelID: "grok-10"},
+	}
+	var out strings.Builder
+	o := runReview(items, strings.NewReader("a\nmaybe\nr\n\nq\n"), &out)
+	if len(o.Accepted) != 1 || o.Accepted[0].ModelID != "gpt-6" {
+		t.Errorf("accepted = %v", o.Accepted)
+	}
+	if len(o.Rejected) != 1 || o.Rejected[0].ModelID != "o3" {
+		t.Errorf("rejected = %v", o.Rejected)
+	}
+	if len(o.Deferred) != 3 {
+		t.Errorf("blank answer and quit should defer the rest, got %v", o.Deferred)
+	}
+	if !strings.Contains(out.String(), "Please answer a, r, d, or q.") || !strings.Contains(out.String(), "[3/5]") {
+		t.Errorf("unexpected prompts:\n%s", out.String())
+	}
+}
+
+func TestRunReview_EndOfInputDefers(t *testing.T) {
+	items := []reviewItem{{Kind: reviewNew, ModelID: "a"}, {Kind: reviewNew, ModelID: "b"}}
+	o := runReview(items, strings.NewReader("a\n"), io.Discard)
+	if len(o.Accepted) != 1 || len(o.Deferred) != 1 || o.Deferred[0].ModelID != "b" {
+		t.Errorf("outcome = %+v", o)
+	}
+}
+
+func TestReviewItems_NoticeCoveredByMissing(t *testing.T) {
+	items := reviewItems([]string{"OpenAI"},
+		map[string][]string{"OpenAI": {"gpt-6"}},
+		map[string][]string{"OpenAI": {"o3"}},
+		[]deprecationNotice{{ModelID: "o3"}, {ModelID: "o4-mini", SunsetDate: "2026-06-01"}})
+	var got []string
+	for _, it := range items {
+		got = append(got, string(it.Kind)+":"+it.ModelID)
+	}
+	if want := "new:gpt-6 missing:o3 notice:o4-mini"; strings.Join(got, " ") != want {
+		t.Errorf("items = %v, want %s", got, want)
+	}
+	if items[2].Provider != "OpenAI" || items[2].Detail != "sunset 2026-06-01" {
+		t.Errorf("notice item = %+v", items[2])
+	}
+}
+
+func TestDeprecateEntry_EditsRealData(t *testing.T) {
+	src, err := os.ReadFile(filepath.Join("..", "..", dataFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := deprecateEntry(src, "gpt-5.4", "Removed from OpenAI docs Oct 2026.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms, err := models.Parse(out)
+	if err != nil {
+		t.Fatalf("edited models.json does not parse: %v", err)
+	}
+	if m := ms["gpt-5.4"]; m.Status != "deprecated" || !strings.HasPrefix(m.Notes, "Removed from OpenAI docs Oct 2026. ") {
+		t.Errorf("entry not deprecated: %+v", m)
+	}
+	if strings.Count(string(out), `"deprecated"`) != strings.Count(string(src), `"deprecated"`)+1 {
+		t.Error("only the one entry should change status")
+	}
+	if _, err := deprecateEntry(src, "no-such-model", ""); err == nil {
+		t.Error("expected an error for an unknown model")
+	}
+}
+
+func TestRemoveKnownModel(t *testing.T) {
+	src := []byte("\t\"OpenAI\": {\n\t\t\"gpt-5\":   true,\n\t\t\"o3\":      true, // legacy\n\t\t\"o3-mini\": true,\n\t},\n")
+	got := string(removeKnownModel(src, "o3"))
+	if want := "\t\"OpenAI\": {\n\t\t\"gpt-5\":   true,\n\t\t\"o3-mini\": true,\n\t},\n"; got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGuessDisplayName(t *testing.T) {
+	tests := map[string]string{
+		"gpt-5.5":                     "GPT-5.5",
+		"gpt-5.5-mini":                "GPT-5.5 Mini",
+		"claude-opus-5-1":             "Claude Opus 5.1",
+		"claude-sonnet-5-20261001":    "Claude Sonnet 5",
+		"gemini-4-pro-preview":        "Gemini 4 Pro (Preview)",
+		"glm-5.1":                     "GLM-5.1",
+		"deepseek-v4":                 "DeepSeek V4",
+		"o5-pro":                      "o5 Pro",
+		"mistral-large-latest":        "Mistral Large",
+		"nvidia/nemotron-4-nano-30b":  "Nemotron 4 Nano 30B",
+		"command-b-reasoning-09-2026": "Command B Reasoning",
+	}
+	for id, want := range tests {
+		if got := guessDisplayName(id); got != want {
+			t.Errorf("guessDisplayName(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestAddEntries_EditsRealData(t *testing.T) {
+	src, err := os.ReadFile(filepath.Join("..", "..", dataFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	out, added, err := addEntries(src, map[string][]string{
+		"OpenAI":  {"gpt-9", "gpt-5.4"},
+		"Acme AI": {"acme-1-preview"},
+	}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms, err := models.Parse(out)
+	if err != nil {
+		t.Fatalf("edited models.json does not parse: %v", err)
+	}
+	if len(added) != 2 || added[0].ID != "acme-1-preview" || added[1].ID != "gpt-9" {
+		t.Fatalf("added = %+v, want stubs for acme-1-preview and gpt-9 only", added)
+	}
+
+	gpt, tmpl := ms["gpt-9"], ms[added[1].From]
+	if added[1].From != "gpt-5.4" || !tmpl.ProviderDefault {
+		t.Errorf("gpt-9 should copy the provider default, got %q", added[1].From)
+	}
+	if gpt.DisplayName != "GPT-9" || gpt.Status != "current" || gpt.ReleaseDate != "2026-10" ||
+		gpt.ContextWindow != tmpl.ContextWindow || gpt.Reasoning != tmpl.Reasoning ||
+		gpt.PricingInput != 0 || gpt.PricingOutput != 0 || !strings.HasPrefix(gpt.Notes, stubNote) {
+		t.Errorf("unexpected stub: %+v", gpt)
+	}
+	if acme := ms["acme-1-preview"]; added[0].From != "" || acme.Maturity != models.MaturityPreview || acme.ContextWindow == 0 {
+		t.Errorf("stub for an unknown provider should use defaults: %+v", acme)
+	}
+	if len(ms) != len(models.Models)+2 {
+		t.Errorf("registry has %d models, want %d", len(ms), len(models.Models)+2)
+	}
+}
+
+func TestAddKnownModel(t *testing.T) {
+	src := []byte("var other = map[string]map[string]bool{\n\t\"OpenAI\": {},\n}\n\nvar knownModels = map[string]map[string]bool{\n\t\"OpenAI\": {\n\t\t\"gpt-5\": true,\n\t},\n}\n")
+	got := string(addKnownModel(src, "OpenAI", "gpt-9"))
+	if want := "var other = map[string]map[string]bool{\n\t\"OpenAI\": {},\n}\n\nvar knownModels = map[string]map[string]bool{\n\t\"OpenAI\": {\n\t\t\"gpt-9\": true,\n\t\t\"gpt-5\": true,\n\t},\n}\n"; got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if got := addKnownModel(src, "Acme AI", "acme-1"); string(got) != string(src) {
+		t.Error("untracked providers should be left alone")
+	}
+
+	real, err := os.ReadFile("main.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(addKnownModel(real, "xAI", "grok-9"), []byte("\t\"xAI\": {\n\t\t\"grok-9\": true,")) {
+		t.Error("grok-9 not added to the real knownModels xAI block")
+	}
+}
+
+func TestMuteList(t *testing.T) {
+	m := loadMutes([]byte(`{"openai": {"new": ["gpt-6-preview"]}}`))
+	m.add(reviewItem{Kind: reviewMissing, Provider: "OpenAI", ModelID: "o3"})
+	m.add(reviewItem{Kind: reviewNotice, Provider: "OpenAI", ModelID: "o3"})
+	m.add(reviewItem{Kind: reviewNew, Provider: "xAI", ModelID: "grok-9"})
+
+	newIDs, missing, muted := m.filter("OpenAI", []string{"gpt-6", "gpt-6-preview"}, []string{"o3", "o4-mini"})
+	if fmt.Sprint(newIDs, missing, muted) != "[gpt-6] [o4-mini] 2" {
+		t.Errorf("filter = %v %v %d", newIDs, missing, muted)
+	}
+	if !m.mutedMissing("OpenAI", "o3") || m.mutedMissing("xAI", "o3") {
+		t.Error("mutedMissing should match per provider")
+	}
+
+	path := filepath.Join(t.TempDir(), "muted.json")
+	if err := m.save(path); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	again := loadMutes(data)
+	if fmt.Sprint(again["openai"].Missing, again["xai"].New) != "[o3] [grok-9]" {
+		t.Errorf("round trip lost entries: %s", data)
+	}
+	if len(loadMutes(mutedJSON)) != len(mutes) {
+		t.Error("embedded muted.json should parse")
+	}
+}
+
+func TestReviewPRBody(t *testing.T) {
+	body := reviewPRBody(reviewOutcome{
+		Accepted: []reviewItem{{Kind: reviewMissing, Provider: "OpenAI", ModelID: "o3"}, {Kind: reviewNew, Provider: "OpenAI", ModelID: "gpt-6"}},
+		Rejected: []reviewItem{{Kind: reviewNew, Provider: "xAI", ModelID: "grok-9"}},
+		Deferred: []reviewItem{{Kind: reviewNotice, Provider: "Anthropic", ModelID: "claude-3-haiku", Detail: "sunset 2026-01-01"}},
+	})
+	for _, want := range []string{
+		"### Deprecated\n\n- `o3` (OpenAI)",
+		"### New models to add\n\n- `gpt-6` (OpenAI)",
+		"### Muted\n\n- `grok-9` (xAI)",
+		"- `claude-3-haiku` (Anthropic): sunset 2026-01-01",
+		"Update `TestTotalModelCount`",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestReviewMode(t *testing.T) {
+	t.Setenv("UPDATER_REVIEW", "")
+	t.Setenv("UPDATER_REVIEW_BASE", "")
+	if reviewMode() || reviewBase() != "main" {
+		t.Error("review mode should be off by default and target main")
+	}
+	t.Setenv("UPDATER_REVIEW", "1")
+	t.Setenv("UPDATER_REVIEW_BASE", "develop")
+	if !reviewMode() || reviewBase() != "develop" {
+		t.Error("UPDATER_REVIEW and UPDATER_REVIEW_BASE should be honored")
+	}
+}
+
+func TestSnapshotCapture(t *testing.T) {
+	challenge := "<html>Checking your browser before accessing the docs...</html>"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, challenge)
+	}))
+	defer srv.Close()
+
+	t.Setenv("UPDATER_SNAPSHOT_DIR", t.TempDir())
+	t.Setenv("UPDATER_SNAPSHOT_BYTES", "20")
+	runSnapshots = newSnapshotStore()
+	defer func() { runSnapshots = nil }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, _, err := fetchPage(ctx, srv.Client(), srv.URL, defaultMaxBodyBytes); err == nil {
+		t.Fatal("expected the 403 to fail the fetch")
+	}
+	paths := runSnapshots.capture("OpenAI", "fetch failed", srv.URL, "https://never.fetched/")
+	if len(paths) != 1 {
+		t.Fatalf("expected one snapshot file, got %v", paths)
+	}
+
+	// The file and the issue section both hold the truncated error page.
+	data, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	gunzip := func(data []byte) (body []byte, comment string) {
+		t.Helper()
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err = io.ReadAll(zr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return body, zr.Comment
+	}
+	body, comment := gunzip(data)
+	if string(body) != challenge[:20] {
+		t.Errorf("snapshot body = %q, want the first 20 bytes of the page", body)
+	}
+	if !strings.Contains(comment, srv.URL) || !strings.Contains(comment, "HTTP 403: fetch failed") {
+		t.Errorf("gzip comment = %q, want the URL, status, and reason", comment)
+	}
+
+	section := runSnapshots.section(maxIssueBody, "OpenAI")
+	if !strings.Contains(section, "<summary>Page snapshot: "+srv.URL+" (fetch failed)</summary>") || !strings.Contains(section, "HTTP 403, first 20 bytes") {
+		t.Errorf("unexpected section:\n%s", section)
+	}
+	encoded := regexp.MustCompile("(?s)```\n(.*)\n```").FindStringSubmatch(section)
+	if encoded == nil {
+		t.Fatalf("no encoded block in section:\n%s", section)
+	}
+	raw, err := base64.StdEncoding.DecodeString(encoded[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body, _ := gunzip(raw); string(body) != challenge[:20] {
+		t.Errorf("decoded section body = %q", body)
+	}
+	if runSnapshots.section(maxIssueBody, "Anthropic") != "" {
+		t.Error("other providers' issues should not carry the snapshot")
+	}
+	// Naming a provider twice, as two alerts on one provider do, still
+	// emits its snapshot once.
+	if twice := runSnapshots.section(maxIssueBody, "OpenAI", "OpenAI"); twice != section {
+		t.Errorf("repeated provider changed the section:\n%s", twice)
+	}
+	if tight := runSnapshots.section(len(section)-1, "OpenAI"); !strings.Contains(tight, "too large for this issue") || len(tight) >= len(section) {
+		t.Errorf("section past its room should point at the snapshot dir, got:\n%s", tight)
+	}
+}
+
+func TestCapIssueBody(t *testing.T) {
+	body := "## Report\n\n<!-- fingerprint:abc -->\n" + strings.Repeat("row\n", 30000)
+	got := capIssueBody(body, maxIssueBody)
+	if len(got) > maxIssueBody {
+		t.Errorf("capped body is %d bytes, want at most %d", len(got), maxIssueBody)
+	}
+	if !strings.Contains(got, "<!-- fingerprint:abc -->") || !strings.Contains(got, "cut to fit") {
+		t.Errorf("capped body lost its marker or note:\n%s", got[len(got)-200:])
+	}
+	if short := "<!-- fingerprint:abc -->\n"; capIssueBody(short, maxIssueBody) != short {
+		t.Error("a body under the limit should be unchanged")
+	}
+	// A marker past the cut is carried over.
+	tail := strings.Repeat("row\n", 30000) + "<!-- fingerprint:xyz -->\n"
+	if got := capIssueBody(tail, 1000); len(got) > 1000 || !strings.HasSuffix(got, "<!-- fingerprint:xyz -->\n") {
+		t.Errorf("marker past the cut was lost: %q", got[max(len(got)-100, 0):])
+	}
+}
+
+func TestModelsAPIPagination(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/anthropic":
+			if r.Header.Get("x-api-key") != "test-key" || r.Header.Get("anthropic-version") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.URL.Query().Get("after_id") == "" {
+				fmt.Fprint(w, `{"data":[{"id":"claude-opus-4-6"},{"id":"claude-embed-1"}],"has_more":true,"last_id":"claude-embed-1"}`)
+				return
+			}
+			fmt.Fprint(w, `{"data":[{"id":"claude-haiku-4-5-20251001"}],"has_more":false}`)
+		case "/gemini":
+			if r.Header.Get("x-goog-api-key") != "test-key" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.URL.Query().Get("pageToken") == "" {
+				fmt.Fprint(w, `{"models":[{"name":"models/gemini-2.5-pro"},{"name":"models/text-embedding-004"}],"nextPageToken":"p2"}`)
+				return
+			}
+			fmt.Fprint(w, `{"models":[{"name":"models/gemini-3-flash-preview"}]}`)
+		}
+	}))
+	defer ts.Close()
+	ctx := context.Background()
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	for name, want := range map[string][]string{
+		"Anthropic": {"claude-opus-4-6", "claude-haiku-4-5-20251001"},
+		"Google":    {"gemini-2.5-pro", "gemini-3-flash-preview"},
+	} {
+		api := modelsAPIs[name]
+		api.URL = ts.URL + map[string]string{"Anthropic": "/anthropic", "Google": "/gemini"}[name]
+		got, err := api.fetch(ctx, client, "test-key")
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+		}
+		if _, err := api.fetch(ctx, client, "wrong-key"); err == nil {
+			t.Errorf("%s: expected an error for a wrong key", name)
+		}
+	}
+}
+
+func TestModelsAPIFor(t *testing.T) {
+	for _, api := range modelsAPIs {
+		for _, env := range api.EnvKeys {
+			t.Setenv(env, "")
+		}
+	}
+	if _, _, ok := modelsAPIFor("Google"); ok {
+		t.Error("expected no Google API without a key")
+	}
+	t.Setenv("GOOGLE_API_KEY", "fallback")
+	if _, key, ok := modelsAPIFor("Google"); !ok || key != "fallback" {
+		t.Errorf("expected the GOOGLE_API_KEY fallback, got %q, %v", key, ok)
+	}
+	t.Setenv("GEMINI_API_KEY", "primary")
+	if _, key, _ := modelsAPIFor("Google"); key != "primary" {
+		t.Errorf("expected GEMINI_API_KEY to take precedence, got %q", key)
+	}
+	if _, _, ok := modelsAPIFor("Perplexity"); ok {
+		t.Error("Perplexity has no model-list endpoint")
+	}
+}
+
+func TestModelsAPIsMatchProviders(t *testing.T) {
+	for name, api := range modelsAPIs {
+		p, ok := models.Providers[name]
+		if !ok {
+			t.Errorf("%s: not a registry provider", name)
+			continue
+		}
+		if api.EnvKeys[0] != p.AuthEnvVar {
+			t.Errorf("%s: first API key variable %s, registry says %s", name, api.EnvKeys[0], p.AuthEnvVar)
+		}
+		if _, docs := providerSources[name]; !docs && !slices.ContainsFunc(undocumented, func(u struct{ Name, Hint string }) bool { return u.Name == name }) {
+			t.Errorf("%s: has neither docs nor an undocumented entry, so it is never checked", name)
+		}
+	}
+}
+
+// sourceRules returns the extraction and cleanup rules of a provider's
+// configured source.
+func sourceRules(t *testing.T, provider string) DocSource {
+	t.Helper()
+	switch src := providerSources[provider].(type) {
+	case DocSource:
+		return src
+	case TableSource:
+		return src.DocSource
+	case GitHubFileSource:
+		return src.DocSource
+	}
+	t.Fatalf("%s has no source with extraction rules", provider)
+	return DocSource{}
+}
+
+func TestHTMLColumn(t *testing.T) {
+	const page = `<p>command-r-08-2024 is retired.</p>
+<table><thead><tr><th>Model Name</th><th>Description</th></tr></thead>
+<tbody>
+<tr><td><code>command-a-03-2025</code></td><td>Replaces command-r-plus.</td></tr>
+<tr><td>command-r7b-12-2024</td><td>Small &amp; fast</td></tr>
+</tbody></table>
+<table><tr><th>Endpoint</th></tr><tr><td>command-nightly</td></tr></table>`
+	cells, found := htmlColumn(page, regexp.MustCompile(`(?i)^model name$`))
+	if !found || strings.Join(cells, ",") != "command-a-03-2025,command-r7b-12-2024" {
+		t.Errorf("htmlColumn = %q, %v; want only the Model Name cells", cells, found)
+	}
+	if _, found := htmlColumn(page, regexp.MustCompile(`(?i)^pricing$`)); found {
+		t.Error("htmlColumn should report a missing column")
+	}
+}
+
+func TestTableSource_Fetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/pricing" {
+			fmt.Fprint(w, "<p>No tables here.</p>")
+			return
+		}
+		fmt.Fprint(w, `<table><tr><th>Model</th><th>Input</th></tr>
+<tr><td>Sonar Pro</td><td>$3</td></tr><tr><td>sonar</td><td>$1</td></tr>
+<tr><td>Sonar Deep Research</td><td>$2</td></tr></table>`)
+	}))
+	defer srv.Close()
+
+	src := providerSources["Perplexity"].(TableSource)
+	src.URLs = []string{srv.URL + "/pricing", srv.URL + "/models"}
+	ids, rec, err := src.Fetch(context.Background(), srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(ids, ",") != "sonar-pro,sonar,sonar-deep-research" || rec.Kind != "table" || rec.URL != srv.URL+"/models" {
+		t.Errorf("ids = %v, record = %+v", ids, rec)
+	}
+	for _, id := range ids {
+		if !knownModels["Perplexity"][id] {
+			t.Errorf("%s does not match a tracked Perplexity ID", id)
+		}
+	}
+}
+
+func TestGitHubFileSource_URLs(t *testing.T) {
+	src := providerSources["OpenAI"].(GitHubFileSource)
+	want := []string{
+		"https://raw.githubusercontent.com/openai/openai-python/main/src/openai/types/shared/chat_model.py",
+		"https://cdn.jsdelivr.net/gh/openai/openai-python@main/src/openai/types/shared/chat_model.py",
+		"https://raw.githubusercontent.com/openai/openai-python/main/src/openai/types/shared/all_models.py",
+		"https://cdn.jsdelivr.net/gh/openai/openai-python@main/src/openai/types/shared/all_models.py",
+	}
+	if got := src.Locations(); !slices.Equal(got, want) {
+		t.Errorf("Locations() = %v, want %v", got, want)
+	}
+}
+
+func TestFetchFromSources_FallsBack(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/models" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `<code>gpt-5</code>`)
+	}))
+	defer srv.Close()
+
+	api := APISource{ModelsAPI: ModelsAPI{URL: srv.URL + "/v1/models"}, Key: "bad"}
+	docs := DocSource{URLs: []string{srv.URL + "/docs"}, Pattern: regexp.MustCompile(`<code>(gpt-[\w.-]+)</code>`)}
+	var log strings.Builder
+	logf := func(format string, args ...any) { fmt.Fprintf(&log, format, args...) }
+
+	ids, rec, err := fetchFromSources(context.Background(), srv.Client(), "OpenAI", []ProviderSource{api, docs}, logf)
+	if err != nil || strings.Join(ids, ",") != "gpt-5" || rec.Kind != "docs" {
+		t.Fatalf("ids = %v, record = %+v, err = %v", ids, rec, err)
+	}
+	if !strings.Contains(log.String(), "falling back to docs scraping") {
+		t.Errorf("fallback not logged: %q", log.String())
+	}
+	if _, _, err := fetchFromSources(context.Background(), srv.Client(), "OpenAI", []ProviderSource{api}, logf); err == nil || !strings.Contains(err.Error(), "no docs to fall back to") {
+		t.Errorf("API-only failure: err = %v", err)
+	}
+}
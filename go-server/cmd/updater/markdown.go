@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+var (
+	// mdSeparatorRe matches a table header separator row such as
+	// "|---|:---:|" or "--- | ---".
+	mdSeparatorRe = regexp.MustCompile(`^\|?\s*:?-+:?\s*(?:\|\s*:?-+:?\s*)*\|?$`)
+	// mdLinkTargetRe drops link targets so URLs in a cell can't match.
+	mdLinkTargetRe = regexp.MustCompile(`\]\([^)]*\)`)
+)
+
+// fetchAndExtractMarkdown fetches a raw markdown/MDX file and extracts model
+// IDs from the table column whose header matches column, applying pattern to
+// each cell. Reading the source table rather than rendered HTML means layout
+// changes, navigation, and code samples can't add or hide IDs. The returned
+// record is filled in as for fetchAndExtract.
+func fetchAndExtractMarkdown(ctx context.Context, client *http.Client, url string, column, pattern *regexp.Regexp, limit int64) ([]string, sourceRecord, error) {
+	if column == nil {
+		return nil, sourceRecord{}, fmt.Errorf("no IDColumn configured for %s", url)
+	}
+	body, truncated, err := fetchPage(ctx, client, url, limit)
+	if err != nil {
+		return nil, sourceRecord{}, err
+	}
+	cells, found := markdownColumn(body, column)
+	if !found {
+		return nil, sourceRecord{}, fmt.Errorf("no table column matching %q in %s", column, url)
+	}
+	return matchCells(cells, pattern), sourceRecord{URL: url, SHA256: bodyDigest(body), Truncated: truncated}, nil
+}
+
+// markdownColumn returns the cells under every table column whose header
+// matches header, across all pipe tables in doc. YAML frontmatter and fenced
+// code blocks are skipped. found reports whether any table had such a column.
+func markdownColumn(doc string, header *regexp.Regexp) (cells []string, found bool) {
+	lines := strings.Split(stripFrontmatter(doc), "\n")
+	inFence := false
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || !strings.Contains(line, "|") || i+1 >= len(lines) || !mdSeparatorRe.MatchString(strings.TrimSpace(lines[i+1])) {
+			continue
+		}
+
+		var cols []int
+		for c, h := range splitMarkdownRow(line) {
+			if header.MatchString(h) {
+				cols = append(cols, c)
+			}
+		}
+		// Skip the separator, then read rows until the table ends.
+		for i += 2; i < len(lines); i++ {
+			row := strings.TrimSpace(lines[i])
+			if !strings.Contains(row, "|") {
+				i--
+				break
+			}
+			values := splitMarkdownRow(row)
+			for _, c := range cols {
+				if c < len(values) {
+					cells = append(cells, values[c])
+				}
+			}
+		}
+		if len(cols) > 0 {
+			found = true
+		}
+	}
+	return cells, found
+}
+
+// splitMarkdownRow splits a pipe table row into trimmed cells, honoring
+// escaped pipes and dropping link targets.
+func splitMarkdownRow(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, `\|`) {
+		row = row[:len(row)-1]
+	}
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(row); i++ {
+		switch {
+		case row[i] == '\\' && i+1 < len(row) && row[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case row[i] == '|':
+			cells = append(cells, cell.String())
+			cell.Reset()
+		default:
+			cell.WriteByte(row[i])
+		}
+	}
+	cells = append(cells, cell.String())
+	for i, c := range cells {
+		cells[i] = strings.TrimSpace(mdLinkTargetRe.ReplaceAllString(c, "]"))
+	}
+	return cells
+}
+
+// stripFrontmatter removes a leading YAML frontmatter block.
+func stripFrontmatter(doc string) string {
+	if !strings.HasPrefix(doc, "---\n") {
+		return doc
+	}
+	if end := strings.Index(doc[4:], "\n---"); end >= 0 {
+		rest := doc[4+end+4:]
+		return strings.TrimPrefix(rest, "\n")
+	}
+	return doc
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// mutedFile is the mute list's path relative to go-server, where review mode
+// rewrites it.
+const mutedFile = "cmd/updater/muted.json"
+
+//go:embed muted.json
+var mutedJSON []byte
+
+// mutedIDs are the IDs a maintainer rejected in review mode for one
+// provider: New are scraped IDs never to report as new, Missing are tracked
+// IDs never to report as missing or announced deprecated.
+type mutedIDs struct {
+	New     []string `json:"new,omitempty"`
+	Missing []string `json:"missing,omitempty"`
+}
+
+// muteList maps lowercase provider names to their muted IDs, like
+// normalization.json.
+type muteList map[string]*mutedIDs
+
+var mutes = loadMutes(mutedJSON)
+
+func loadMutes(data []byte) muteList {
+	var m muteList
+	if err := json.Unmarshal(data, &m); err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: failed to parse muted.json: %v\n", err)
+	}
+	if m == nil {
+		m = muteList{}
+	}
+	return m
+}
+
+// filter drops muted IDs from a provider's diff.
+func (m muteList) filter(provider string, newModels, missing []string) (keptNew, keptMissing []string, muted int) {
+	ids := m[strings.ToLower(provider)]
+	if ids == nil {
+		return newModels, missing, 0
+	}
+	for _, id := range newModels {
+		if slices.Contains(ids.New, id) {
+			muted++
+		} else {
+			keptNew = append(keptNew, id)
+		}
+	}
+	for _, id := range missing {
+		if slices.Contains(ids.Missing, id) {
+			muted++
+		} else {
+			keptMissing = append(keptMissing, id)
+		}
+	}
+	return keptNew, keptMissing, muted
+}
+
+// mutedMissing reports whether deprecation signals for id are muted.
+func (m muteList) mutedMissing(provider, id string) bool {
+	ids := m[strings.ToLower(provider)]
+	return ids != nil && slices.Contains(ids.Missing, id)
+}
+
+// add mutes a rejected review item.
+func (m muteList) add(it reviewItem) {
+	key := strings.ToLower(it.Provider)
+	if m[key] == nil {
+		m[key] = &mutedIDs{}
+	}
+	list := &m[key].Missing
+	if it.Kind == reviewNew {
+		list = &m[key].New
+	}
+	if !slices.Contains(*list, it.ModelID) {
+		*list = append(*list, it.ModelID)
+		slices.Sort(*list)
+	}
+}
+
+// save writes the mute list as indented JSON.
+func (m muteList) save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
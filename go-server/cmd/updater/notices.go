@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go-server/internal/forge"
+	"go-server/internal/models"
+)
+
+// deprecationPages are provider-published deprecation notice pages, scraped
+// as their own source type. Unlike docs pages, a listing here is an explicit
+// announcement, not an inference from a model disappearing.
+var deprecationPages = map[string]string{
+	"OpenAI":    "https://platform.openai.com/docs/deprecations",
+	"Anthropic": "https://docs.anthropic.com/en/docs/about-claude/model-deprecations",
+}
+
+// deprecationNotice is one model's entry on a provider deprecation page.
+type deprecationNotice struct {
+	ModelID         string // registry ID
+	DeprecationDate string // YYYY-MM-DD the deprecation was announced, if given
+	SunsetDate      string // YYYY-MM-DD the API stops serving the model, if given
+	Replacement     string // recommended registry ID, if given
+	Source          string
+}
+
+// describe summarizes the notice's dates and replacement for reports.
+func (n deprecationNotice) describe() string {
+	var parts []string
+	if n.DeprecationDate != "" {
+		parts = append(parts, "deprecated "+n.DeprecationDate)
+	}
+	if n.SunsetDate != "" {
+		parts = append(parts, "sunset "+n.SunsetDate)
+	}
+	if n.Replacement != "" {
+		parts = append(parts, "replacement "+n.Replacement)
+	}
+	if len(parts) == 0 {
+		return "listed as deprecated"
+	}
+	return strings.Join(parts, ", ")
+}
+
+var (
+	// noticeBlockRe walks a page in document order: headings carry the
+	// announcement date, table rows carry model, sunset date, and replacement.
+	noticeBlockRe = regexp.MustCompile(`(?is)<h[1-4][^>]*>(.*?)</h[1-4]>|<tr[^>]*>(.*?)</tr>`)
+	noticeCellRe  = regexp.MustCompile(`(?is)<t[dh][^>]*>(.*?)</t[dh]>`)
+	noticeTagRe   = regexp.MustCompile(`(?s)<[^>]+>`)
+	noticeTokenRe = regexp.MustCompile(`[a-z0-9][a-z0-9.\-]*[a-z0-9]`)
+	noticeDateRe  = regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}\b|\b(?:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Sept|Oct|Nov|Dec)[a-z]*\.? \d{1,2}, \d{4}\b`)
+	// noticeRetiringRe marks a row as announcing a deprecation or
+	// retirement when it carries no date.
+	noticeRetiringRe = regexp.MustCompile(`(?i)\b(?:deprecat\w*|retir\w*|sunset\w*|shut ?down|legacy|discontinued)\b`)
+	// noticeActiveRe matches a status cell of a model that is still
+	// served, as in the model status tables listing every model.
+	noticeActiveRe = regexp.MustCompile(`(?i)^(?:active|available|supported|generally available|ga)$`)
+)
+
+// fetchDeprecationNotices fetches a provider deprecation page and parses it.
+// truncated reports whether the page exceeded defaultMaxBodyBytes.
+func fetchDeprecationNotices(ctx context.Context, client *http.Client, provider, url string) (notices []deprecationNotice, truncated bool, err error) {
+	body, truncated, err := fetchPage(ctx, client, url, defaultMaxBodyBytes)
+	if err != nil {
+		return nil, false, err
+	}
+	notices = parseDeprecationNotices(body, providerModelIDs(provider))
+	for i := range notices {
+		notices[i].Source = url
+	}
+	return notices, truncated, nil
+}
+
+// providerModelIDs maps every ID and lowercase full-ID alias for a
+// provider's models to the registry ID, so notices naming dated snapshots
+// still resolve. Shorthand aliases such as "sonnet" or "claude-opus" are
+// left out: they match words of display names like "Claude Sonnet 3.7"
+// and would pin the notice on the wrong model.
+func providerModelIDs(provider string) map[string]string {
+	ids := make(map[string]string)
+	for id, m := range models.Models {
+		if m.Provider == provider {
+			ids[strings.ToLower(id)] = id
+		}
+	}
+	for alias, target := range models.Aliases {
+		if m, ok := models.Models[target]; ok && m.Provider == provider && fullIDAlias(strings.ToLower(alias), strings.ToLower(target)) {
+			ids[strings.ToLower(alias)] = target
+		}
+	}
+	for id := range knownModels[provider] {
+		if _, ok := ids[id]; !ok {
+			ids[id] = id
+		}
+	}
+	return ids
+}
+
+// fullIDAlias reports whether alias reads as a complete model ID rather
+// than shorthand: it starts with the same word as target, as in
+// "claude-opus-4-20250514" for "claude-opus-4-0", and names a version.
+func fullIDAlias(alias, target string) bool {
+	if strings.Contains(alias, "/") || !strings.ContainsAny(alias, "0123456789") {
+		return false
+	}
+	word, _, _ := strings.Cut(target, "-")
+	return alias == word || strings.HasPrefix(alias, word+"-")
+}
+
+// parseDeprecationNotices extracts notices from a deprecation page. Each
+// table row naming a known model, with a date or a deprecation or
+// retirement status and no status saying it is still served, becomes a
+// notice: the first known model in
+// the row is the deprecated one, a later different one the replacement, the
+// row's first date the sunset date, and the nearest preceding heading's date
+// the deprecation date. The first notice for a model wins.
+func parseDeprecationNotices(body string, known map[string]string) []deprecationNotice {
+	var notices []deprecationNotice
+	seen := make(map[string]bool)
+	announced := ""
+	for _, block := range noticeBlockRe.FindAllStringSubmatch(body, -1) {
+		if block[1] != "" {
+			if d := findNoticeDate(noticeText(block[1])); d != "" {
+				announced = d
+			}
+			continue
+		}
+
+		var cells []string
+		for _, c := range noticeCellRe.FindAllStringSubmatch(block[2], -1) {
+			cells = append(cells, noticeText(c[1]))
+		}
+		if !announcesRetirement(cells) {
+			continue
+		}
+		var n deprecationNotice
+		for _, cell := range cells {
+			for _, tok := range noticeTokenRe.FindAllString(strings.ToLower(cell), -1) {
+				id, ok := known[tok]
+				switch {
+				case !ok:
+				case n.ModelID == "":
+					n.ModelID = id
+				case n.Replacement == "" && id != n.ModelID:
+					n.Replacement = id
+				}
+			}
+			if n.SunsetDate == "" {
+				n.SunsetDate = findNoticeDate(cell)
+			}
+		}
+		if n.ModelID == "" || seen[n.ModelID] {
+			continue
+		}
+		seen[n.ModelID] = true
+		n.DeprecationDate = announced
+		notices = append(notices, n)
+	}
+	return notices
+}
+
+// announcesRetirement reports whether a table row's cells announce a
+// deprecation or retirement: a date or a retirement status, and no cell
+// giving an active status. Model status tables list current models with
+// "Active" next to a tentative retirement date.
+func announcesRetirement(cells []string) bool {
+	found := false
+	for _, cell := range cells {
+		if noticeActiveRe.MatchString(cell) {
+			return false
+		}
+		found = found || findNoticeDate(cell) != "" || noticeRetiringRe.MatchString(cell)
+	}
+	return found
+}
+
+// noticeText strips tags and collapses whitespace.
+func noticeText(s string) string {
+	return strings.Join(strings.Fields(noticeTagRe.ReplaceAllString(s, " ")), " ")
+}
+
+// findNoticeDate returns the first date in s as YYYY-MM-DD, or "".
+func findNoticeDate(s string) string {
+	for _, m := range noticeDateRe.FindAllString(s, -1) {
+		m = strings.Replace(strings.Replace(m, ".", "", 1), "Sept ", "Sep ", 1)
+		for _, layout := range []string{"2006-01-02", "January 2, 2006", "Jan 2, 2006"} {
+			if t, err := time.Parse(layout, m); err == nil {
+				return t.Format("2006-01-02")
+			}
+		}
+	}
+	return ""
+}
+
+// pendingNotices returns notices for models the registry doesn't yet mark
+// deprecated, sorted by sunset date (soonest first) then ID.
+func pendingNotices(notices map[string]deprecationNotice) []deprecationNotice {
+	var pending []deprecationNotice
+	for id, n := range notices {
+		if m, ok := models.Models[id]; ok && m.Status != "deprecated" {
+			pending = append(pending, n)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		a, b := pending[i], pending[j]
+		if a.SunsetDate != b.SunsetDate {
+			return a.SunsetDate != "" && (b.SunsetDate == "" || a.SunsetDate < b.SunsetDate)
+		}
+		return a.ModelID < b.ModelID
+	})
+	return pending
+}
+
+// createDeprecationNoticeIssue opens an issue for models a provider has
+// announced as deprecated that the registry still lists as current or
+// legacy. Deduplicated by fingerprint like the other updater issues.
+func createDeprecationNoticeIssue(ctx context.Context, host forge.Forge, pending []deprecationNotice, reportBody string) {
+	if host == nil || len(pending) == 0 {
+		return
+	}
+
+	keys := make([]string, len(pending))
+	for i, n := range pending {
+		keys[i] = "notice:" + n.ModelID + "@" + n.SunsetDate
+	}
+	fp := fingerprintModels(keys)
+	if existingIssueWithFingerprint(ctx, host, fp) {
+		fmt.Printf("[%s] Existing open issue already covers these deprecation notices (fingerprint match), skipping.\n", host.Name())
+		return
+	}
+
+	title := "Provider deprecation notices - " + time.Now().Format("2006-01-02")
+	createIssue(ctx, host, title, deprecationNoticeIssueBody(pending, reportBody, fp))
+}
+
+// deprecationNoticeIssueBody renders the issue body for
+// createDeprecationNoticeIssue.
+func deprecationNoticeIssueBody(pending []deprecationNotice, reportBody, fp string) string {
+	var body strings.Builder
+	body.WriteString("## Provider Deprecation Notices\n\n")
+	body.WriteString("These models are listed on their provider's deprecation page but are not yet marked deprecated in the registry.\n\n")
+	body.WriteString("| Model | Registry status | Deprecation date | Sunset date | Replacement | Source |\n|---|---|---|---|---|---|\n")
+	for _, n := range pending {
+		body.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s | %s | %s |\n",
+			n.ModelID, models.Models[n.ModelID].Status, orDash(n.DeprecationDate), orDash(n.SunsetDate), orDash(n.Replacement), n.Source))
+	}
+	body.WriteString("\n### Action Items\n\n")
+	body.WriteString("- [ ] Set `\"status\": \"deprecated\"` in `go-server/internal/models/models.json` (or `\"legacy\"` until the sunset date)\n")
+	body.WriteString("- [ ] Set `deprecation_date` and `retirement_date` (the sunset date) and record the replacement in the model's `Notes`\n")
+	body.WriteString("- [ ] Run `go test ./... -v` to verify\n")
+	body.WriteString("\n<details>\n<summary>Full update report</summary>\n\n```\n")
+	body.WriteString(reportBody)
+	body.WriteString("\n```\n</details>\n")
+	body.WriteString("\n<!-- fingerprint:" + fp + " -->\n")
+	return body.String()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "—"
+	}
+	return s
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go-server/internal/models"
+)
+
+// priceListOffer is the part of an AWS Price List offer file the updater
+// reads: every product SKU with its attributes.
+type priceListOffer struct {
+	Products map[string]struct {
+		Attributes map[string]string `json:"attributes"`
+	} `json:"products"`
+}
+
+// fetchAndExtractOffer fetches an AWS Price List offer file and extracts
+// model IDs from the product attribute named attribute, applying pattern to
+// each value. The offer is machine-readable and lists every model AWS bills
+// for, so it tracks Bedrock's lineup without scraping console pages. A
+// truncated offer cannot be decoded, so it is an error rather than a
+// partial result. The returned record is filled in as for fetchAndExtract.
+func fetchAndExtractOffer(ctx context.Context, client *http.Client, url, attribute string, pattern *regexp.Regexp, limit int64) ([]string, sourceRecord, error) {
+	if attribute == "" {
+		return nil, sourceRecord{}, fmt.Errorf("no OfferAttribute configured for %s", url)
+	}
+	body, truncated, err := fetchPage(ctx, client, url, limit)
+	if err != nil {
+		return nil, sourceRecord{}, err
+	}
+	if truncated {
+		return nil, sourceRecord{}, fmt.Errorf("offer file %s exceeded the %s-byte limit; raise MaxBodyBytes for this source", url, models.FormatInt(int(limit)))
+	}
+	var offer priceListOffer
+	if err := json.Unmarshal([]byte(body), &offer); err != nil {
+		return nil, sourceRecord{}, fmt.Errorf("decoding offer file %s: %w", url, err)
+	}
+
+	// Products are keyed by SKU; sort the values so IDs come out in a
+	// stable order.
+	var values []string
+	for _, p := range offer.Products {
+		if v := strings.TrimSpace(p.Attributes[attribute]); v != "" {
+			values = append(values, v)
+		}
+	}
+	sort.Strings(values)
+
+	seen := make(map[string]bool)
+	var ids []string
+	for _, v := range values {
+		if m := pattern.FindStringSubmatch(v); len(m) >= 2 && !seen[m[1]] {
+			seen[m[1]] = true
+			ids = append(ids, m[1])
+		}
+	}
+	return ids, sourceRecord{URL: url, SHA256: bodyDigest(body)}, nil
+}
+
+// bedrockSpaceRe matches the spaces in a Price List model name.
+var bedrockSpaceRe = regexp.MustCompile(`\s+`)
+
+// normalizeBedrockID converts a Price List model name to the registry's ID
+// for Amazon's own models:
+//
+//	Nova Pro    → amazon-nova-pro
+//	Nova 2 Lite → amazon-nova-2-lite
+func normalizeBedrockID(name string) string {
+	id := strings.ToLower(bedrockSpaceRe.ReplaceAllString(strings.TrimSpace(name), "-"))
+	if !strings.HasPrefix(id, "amazon-") {
+		id = "amazon-" + id
+	}
+	return id
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sourceRecord is one fetch that fed this run's results.
+type sourceRecord struct {
+	Provider string
+	Kind     string // "docs", "markdown", "offer", "table", "github", "api", "notices", or "aggregator"
+	URL      string
+	SHA256   string // of the normalized page body, when the body was kept
+	IDs      int    // model IDs (or notices) extracted
+	// Truncated is set when the page exceeded its size cap, so IDs past the
+	// cutoff were missed.
+	Truncated bool
+}
+
+// provenance is the evidence behind one updater run: where it ran, which
+// version of the extraction rules it used, and every source it read. It is
+// appended to each issue the run files so maintainers can audit exactly what
+// drove an automated report.
+type provenance struct {
+	RunID          string
+	RunURL         string
+	Commit         string
+	PatternVersion string
+	Started        time.Time
+	Sources        []sourceRecord
+}
+
+// runProvenance is the current run's record; createIssue appends it to every
+// issue body. Nil outside main (tests, library use).
+var runProvenance *provenance
+
+// newProvenance identifies the run from the CI environment: GitHub Actions
+// (also Gitea and Forgejo Actions) or GitLab CI. Local runs get a
+// timestamp-based ID.
+func newProvenance(started time.Time) *provenance {
+	p := &provenance{Started: started.UTC(), PatternVersion: patternVersion()}
+	switch {
+	case os.Getenv("GITHUB_RUN_ID") != "":
+		p.RunID = os.Getenv("GITHUB_RUN_ID")
+		if server, repo := os.Getenv("GITHUB_SERVER_URL"), os.Getenv("GITHUB_REPOSITORY"); server != "" && repo != "" {
+			p.RunURL = server + "/" + repo + "/actions/runs/" + p.RunID
+		}
+		p.Commit = os.Getenv("GITHUB_SHA")
+	case os.Getenv("CI_PIPELINE_ID") != "":
+		p.RunID = os.Getenv("CI_PIPELINE_ID")
+		p.RunURL = os.Getenv("CI_PIPELINE_URL")
+		p.Commit = os.Getenv("CI_COMMIT_SHA")
+	default:
+		p.RunID = "local-" + p.Started.Format("20060102T150405Z")
+	}
+	return p
+}
+
+// record adds a source to the run's evidence.
+func (p *provenance) record(s sourceRecord) {
+	if p != nil {
+		p.Sources = append(p.Sources, s)
+	}
+}
+
+// section renders the provenance as a collapsible markdown block.
+func (p *provenance) section() string {
+	var b strings.Builder
+	b.WriteString("\n<details>\n<summary>Provenance</summary>\n\n")
+	b.WriteString("| Field | Value |\n|---|---|\n")
+	run := "`" + p.RunID + "`"
+	if p.RunURL != "" {
+		run = fmt.Sprintf("[%s](%s)", p.RunID, p.RunURL)
+	}
+	b.WriteString(fmt.Sprintf("| Run | %s |\n", run))
+	if p.Commit != "" {
+		b.WriteString(fmt.Sprintf("| Commit | `%s` |\n", p.Commit))
+	}
+	b.WriteString(fmt.Sprintf("| Started | %s |\n", p.Started.Format(time.RFC3339)))
+	b.WriteString(fmt.Sprintf("| Extraction rules | `%s` |\n", p.PatternVersion))
+	if len(p.Sources) > 0 {
+		b.WriteString("\n| Provider | Kind | URL | IDs | SHA-256 |\n|---|---|---|---|---|\n")
+		for _, s := range p.Sources {
+			sum := "—"
+			if s.SHA256 != "" {
+				sum = "`" + s.SHA256[:16] + "`"
+			}
+			ids := strconv.Itoa(s.IDs)
+			if s.Truncated {
+				ids += " (truncated)"
+			}
+			b.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", s.Provider, s.Kind, s.URL, ids, sum))
+		}
+	}
+	b.WriteString("\n</details>\n")
+	return b.String()
+}
+
+// patternVersion fingerprints everything that decides which IDs a page
+// yields: each provider source's URLs and patterns, deprecation pages, and the
+// normalization rules. Any edit to those changes the version, so two reports
+// with the same version were produced by the same extraction logic.
+func patternVersion() string {
+	var parts []string
+	for name, src := range providerSources {
+		parts = append(parts, name+"\x00"+src.Fingerprint())
+	}
+	for name, url := range deprecationPages {
+		parts = append(parts, "notices\x00"+name+"\x00"+url)
+	}
+	sort.Strings(parts)
+	h := sha256.New()
+	h.Write([]byte(strings.Join(parts, "\n")))
+	h.Write(normalizationJSON)
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// bodyDigest returns the hex SHA-256 of a fetched page body.
+func bodyDigest(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
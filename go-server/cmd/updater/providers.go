@@ -0,0 +1,322 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-server/internal/forge"
+	"go-server/internal/modelid"
+	"go-server/internal/models"
+)
+
+// aggregatorModelsURL lists every model OpenRouter serves. Model IDs are
+// "<provider-slug>/<model>", so the slugs double as a provider directory.
+// Public endpoint, no API key needed.
+const aggregatorModelsURL = "https://openrouter.ai/api/v1/models"
+
+// providerSlugAliases maps aggregator provider slugs to the registry's
+// provider names when they differ from a lowercase match.
+var providerSlugAliases = map[string]string{
+	"meta-llama": "Meta",
+	"x-ai":       "xAI",
+	"mistralai":  "Mistral",
+	"moonshotai": "Moonshot",
+	"z-ai":       "Zhipu",
+	"thudm":      "Zhipu",
+	"kwaipilot":  "Kuaishou",
+	"ai21":       "AI21",
+	"nvidia":     "NVIDIA",
+	"minimax":    "MiniMax",
+	"xiaomi":     "Xiaomi",
+	"tencent":    "Tencent",
+	"microsoft":  "Microsoft",
+	"amazon":     "Amazon",
+	"cohere":     "Cohere",
+	"perplexity": "Perplexity",
+	"deepseek":   "DeepSeek",
+	"google":     "Google",
+	"anthropic":  "Anthropic",
+	"openai":     "OpenAI",
+}
+
+// ignoredProviderSlugs are aggregator slugs that are not model vendors
+// (routers, meta-models) and should never be reported as candidates.
+var ignoredProviderSlugs = map[string]bool{
+	"openrouter": true,
+}
+
+// aggregatorModel is one entry of the aggregator catalog. Created is when
+// the aggregator listed it, which is no later than its release, or zero if
+// not reported.
+type aggregatorModel struct {
+	ID      string
+	Created time.Time
+}
+
+// aggregatorSuffixes are the endings the aggregator adds to instruction-
+// tuned checkpoints whose providers publish them without one, as in
+// "llama-3.3-70b-instruct" for the tracked "llama-3.3-70b".
+var aggregatorSuffixes = []string{"-instruct", "-it", "-hf"}
+
+// providerCandidate is an aggregator provider slug not tracked in the registry.
+type providerCandidate struct {
+	Slug   string
+	Models int
+}
+
+// fetchAggregatorCatalog fetches the aggregator model list.
+func fetchAggregatorCatalog(ctx context.Context, client *http.Client, url string) ([]aggregatorModel, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "ModelRegistryUpdater/1.0")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("aggregator request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("aggregator returned HTTP %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data []struct {
+			ID      string `json:"id"`
+			Created int64  `json:"created"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 8*1024*1024)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse aggregator response: %w", err)
+	}
+
+	catalog := make([]aggregatorModel, 0, len(result.Data))
+	for _, m := range result.Data {
+		am := aggregatorModel{ID: m.ID}
+		if m.Created > 0 {
+			am.Created = time.Unix(m.Created, 0).UTC()
+		}
+		catalog = append(catalog, am)
+	}
+	return catalog, nil
+}
+
+// slugCounts returns the number of catalog models per provider slug.
+func slugCounts(catalog []aggregatorModel) map[string]int {
+	counts := make(map[string]int)
+	for _, m := range catalog {
+		slug, _, ok := strings.Cut(m.ID, "/")
+		if !ok || slug == "" {
+			continue
+		}
+		counts[strings.ToLower(slug)]++
+	}
+	return counts
+}
+
+// slugProvider returns the registry provider name for an aggregator slug:
+// its providerSlugAliases entry, else the slug itself.
+func slugProvider(slug string) string {
+	if p, ok := providerSlugAliases[slug]; ok {
+		return p
+	}
+	return slug
+}
+
+// aggregatorIDs picks provider's models out of the aggregator catalog, for
+// cross-checking providers with no scrapable docs. Each ID is canonicalized
+// against the provider's known models with the modelid rules, so
+// "meta-llama/llama-4-scout:free" counts as the tracked "llama-4-scout",
+// and failing that, retried without an aggregatorSuffixes ending, so
+// "llama-3.3-70b-instruct" counts as "llama-3.3-70b". IDs that resolve to
+// nothing keep the rest of the ID without the router variant or suffix,
+// and without the vendor slug unless the provider's known IDs carry one
+// (NVIDIA's "nvidia/..." IDs do).
+//
+// The aggregator keeps serving models long after their providers move on,
+// so untracked IDs that predate what the registry tracks are returned in
+// older rather than ids; see olderThanTracked.
+func aggregatorIDs(catalog []aggregatorModel, provider string, known map[string]bool) (ids []string, older []filteredID) {
+	tracked := make(map[string]models.Model, len(known))
+	keepSlug := false
+	for id := range known {
+		tracked[id] = models.Model{ID: id}
+		keepSlug = keepSlug || strings.Contains(id, "/")
+	}
+	lookup := modelid.LookupIn(tracked)
+	newest := newestRelease(provider)
+
+	seen := make(map[string]bool)
+	for _, am := range catalog {
+		full := strings.ToLower(am.ID)
+		slug, rest, ok := strings.Cut(full, "/")
+		if !ok || !strings.EqualFold(slugProvider(slug), provider) {
+			continue
+		}
+		rest, _, _ = strings.Cut(rest, ":")
+		id, found := rest, false
+		if res := modelid.CanonicalizeWith(full, lookup); res.Found {
+			id, found = res.ID, true
+		}
+		if !found {
+			for _, suffix := range aggregatorSuffixes {
+				stripped, ok := strings.CutSuffix(rest, suffix)
+				if !ok || stripped == "" {
+					continue
+				}
+				id = stripped
+				if res := modelid.CanonicalizeWith(slug+"/"+stripped, lookup); res.Found {
+					id, found = res.ID, true
+				}
+				break
+			}
+		}
+		if !found && keepSlug {
+			id = slug + "/" + id
+		}
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if !found {
+			if reason := olderThanTracked(id, am.Created, known, newest); reason != "" {
+				older = append(older, filteredID{ID: id, Reason: reason})
+				continue
+			}
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, older
+}
+
+// versionRe finds an ID's family and version: the words before its first
+// number, and that number, as in "llama-guard" and "3" in
+// "llama-guard-3-8b".
+var versionRe = regexp.MustCompile(`^(?:[a-z0-9-]+/)?([a-z]+(?:-[a-z]+)*)-(\d+(?:\.\d+)?)(?:-|$)`)
+
+// familyVersion returns id's family and version; ok is false for IDs
+// without a numbered family.
+func familyVersion(id string) (family string, version float64, ok bool) {
+	m := versionRe.FindStringSubmatch(id)
+	if m == nil {
+		return "", 0, false
+	}
+	v, err := strconv.ParseFloat(m[2], 64)
+	return m[1], v, err == nil
+}
+
+// newestRelease returns the latest release month ("YYYY-MM") of provider's
+// models in the registry, or "" if none has a date.
+func newestRelease(provider string) string {
+	newest := ""
+	for _, m := range models.Models {
+		if strings.EqualFold(m.Provider, provider) && len(m.ReleaseDate) >= 7 {
+			newest = max(newest, m.ReleaseDate[:7])
+		}
+	}
+	return newest
+}
+
+// olderThanTracked explains why an untracked aggregator ID is not news, or
+// returns "" if it may be: its family is tracked at a higher version, as
+// Llama 3.2 is under the tracked Llama 4, or the aggregator listed it no
+// later than the month of the provider's newest tracked release, so it was
+// passed over when the registry last caught up.
+func olderThanTracked(id string, listed time.Time, known map[string]bool, newestRelease string) string {
+	if family, version, ok := familyVersion(id); ok {
+		highest, tracked := 0.0, false
+		for k := range known {
+			if f, v, ok := familyVersion(k); ok && f == family {
+				highest, tracked = max(highest, v), true
+			}
+		}
+		if tracked && version < highest {
+			return fmt.Sprintf("older than the tracked %s %g", family, highest)
+		}
+	}
+	if !listed.IsZero() && newestRelease != "" && listed.Format("2006-01") <= newestRelease {
+		return fmt.Sprintf("listed on OpenRouter %s, before the newest tracked release (%s)", listed.Format("2006-01"), newestRelease)
+	}
+	return ""
+}
+
+// newProviderCandidates returns aggregator slugs that don't correspond to any
+// registry provider, most models first. Slugs map to providers via
+// providerSlugAliases or a case-insensitive name match.
+func newProviderCandidates(slugCounts map[string]int) []providerCandidate {
+	tracked := make(map[string]bool)
+	for _, m := range models.Models {
+		tracked[strings.ToLower(m.Provider)] = true
+	}
+
+	var candidates []providerCandidate
+	for slug, n := range slugCounts {
+		if ignoredProviderSlugs[slug] {
+			continue
+		}
+		if tracked[strings.ToLower(slugProvider(slug))] {
+			continue
+		}
+		candidates = append(candidates, providerCandidate{Slug: slug, Models: n})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Models != candidates[j].Models {
+			return candidates[i].Models > candidates[j].Models
+		}
+		return candidates[i].Slug < candidates[j].Slug
+	})
+	return candidates
+}
+
+// createNewProvidersIssue creates an issue listing provider candidates
+// found in the aggregator but not tracked in the registry. Deduplicated by
+// fingerprint like the other updater issues.
+func createNewProvidersIssue(ctx context.Context, host forge.Forge, candidates []providerCandidate, reportBody string) {
+	if host == nil {
+		return
+	}
+
+	slugs := make([]string, len(candidates))
+	for i, c := range candidates {
+		slugs[i] = "provider:" + c.Slug
+	}
+	fp := fingerprintModels(slugs)
+	if existingIssueWithFingerprint(ctx, host, fp) {
+		fmt.Printf("[%s] Existing open issue already covers these provider candidates (fingerprint match), skipping.\n", host.Name())
+		return
+	}
+
+	today := time.Now().Format("2006-01-02")
+	title := "New provider candidates detected - " + today
+
+	var body strings.Builder
+	body.WriteString("## New Provider Candidates\n\n")
+	body.WriteString("The following providers are listed on OpenRouter but are not tracked in the registry:\n\n")
+	body.WriteString("| Provider slug | Models on OpenRouter |\n|---|---|\n")
+	for _, c := range candidates {
+		body.WriteString(fmt.Sprintf("| `%s` | %d |\n", c.Slug, c.Models))
+	}
+	body.WriteString("\n### Action Items\n\n")
+	body.WriteString("- [ ] Decide whether each provider should be covered\n")
+	body.WriteString("- [ ] Add models to `go-server/internal/models/models.json` and the provider to `Providers` in `go-server/internal/models/providers.go`\n")
+	body.WriteString("- [ ] Add the slug to `providerSlugAliases` (or `ignoredProviderSlugs`) in `go-server/cmd/updater/providers.go`\n")
+	body.WriteString("- [ ] Update `TestProviderCounts` in `data_test.go`\n")
+	body.WriteString("\n<details>\n<summary>Full update report</summary>\n\n```\n")
+	body.WriteString(reportBody)
+	body.WriteString("\n```\n</details>\n")
+	body.WriteString("\n<!-- fingerprint:" + fp + " -->\n")
+
+	createIssue(ctx, host, title, body.String())
+}
@@ -0,0 +1,374 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-server/internal/forge"
+	"go-server/internal/models"
+)
+
+// Paths review mode edits, relative to go-server.
+const (
+	dataFile    = "internal/models/models.json"
+	updaterFile = "cmd/updater/main.go"
+)
+
+// reviewKind is the kind of change a review item proposes.
+type reviewKind string
+
+const (
+	reviewNew     reviewKind = "new"     // scraped ID not in the registry
+	reviewMissing reviewKind = "missing" // tracked ID gone from the docs
+	reviewNotice  reviewKind = "notice"  // tracked ID on a deprecation page
+)
+
+// reviewItem is one detected change for a maintainer to decide on.
+type reviewItem struct {
+	Kind     reviewKind
+	Provider string
+	ModelID  string
+	Detail   string
+}
+
+func (it reviewItem) String() string {
+	switch it.Kind {
+	case reviewNew:
+		return fmt.Sprintf("NEW %s model %s", it.Provider, it.ModelID)
+	case reviewMissing:
+		return fmt.Sprintf("MISSING %s model %s (gone from the docs; accept deprecates it)", it.Provider, it.ModelID)
+	default:
+		return fmt.Sprintf("DEPRECATION NOTICE for %s model %s (accept deprecates it)", it.Provider, it.ModelID)
+	}
+}
+
+// reviewOutcome is the result of a review session.
+type reviewOutcome struct {
+	Accepted, Rejected, Deferred []reviewItem
+}
+
+// reviewMode reports whether the updater should walk a maintainer through
+// each change interactively (UPDATER_REVIEW=true) and open one consolidated
+// pull request, instead of filing issues.
+func reviewMode() bool {
+	on, _ := strconv.ParseBool(os.Getenv("UPDATER_REVIEW"))
+	return on
+}
+
+// reviewBase returns the branch the review pull request targets
+// (UPDATER_REVIEW_BASE, default main).
+func reviewBase() string {
+	if b := os.Getenv("UPDATER_REVIEW_BASE"); b != "" {
+		return b
+	}
+	return "main"
+}
+
+// reviewItems lists the run's changes in provider order: new and missing IDs
+// per provider, then pending deprecation notices not already covered by a
+// missing item.
+func reviewItems(providerOrder []string, newByProvider, missingByProvider map[string][]string, pending []deprecationNotice) []reviewItem {
+	var items []reviewItem
+	covered := make(map[string]bool)
+	for _, p := range providerOrder {
+		for _, id := range newByProvider[p] {
+			items = append(items, reviewItem{Kind: reviewNew, Provider: p, ModelID: id})
+		}
+		for _, id := range missingByProvider[p] {
+			items = append(items, reviewItem{Kind: reviewMissing, Provider: p, ModelID: id})
+			covered[id] = true
+		}
+	}
+	for _, n := range pending {
+		if !covered[n.ModelID] {
+			items = append(items, reviewItem{Kind: reviewNotice, Provider: models.Models[n.ModelID].Provider, ModelID: n.ModelID, Detail: n.describe()})
+		}
+	}
+	return items
+}
+
+// runReview prompts for a decision on each item: accept stages it for the
+// pull request, reject mutes it, defer leaves it for the next run. Quitting,
+// or reaching the end of input, defers everything left.
+func runReview(items []reviewItem, in io.Reader, out io.Writer) reviewOutcome {
+	var o reviewOutcome
+	sc := bufio.NewScanner(in)
+	for i, it := range items {
+		fmt.Fprintf(out, "\n[%d/%d] %s\n", i+1, len(items), it)
+		if it.Detail != "" {
+			fmt.Fprintf(out, "  %s\n", it.Detail)
+		}
+		for {
+			fmt.Fprint(out, "  [a]ccept  [r]eject (mute)  [d]efer  [q]uit > ")
+			if !sc.Scan() {
+				fmt.Fprintln(out)
+				o.Deferred = append(o.Deferred, items[i:]...)
+				return o
+			}
+			switch strings.ToLower(strings.TrimSpace(sc.Text())) {
+			case "a", "accept":
+				o.Accepted = append(o.Accepted, it)
+			case "r", "reject":
+				o.Rejected = append(o.Rejected, it)
+			case "d", "defer", "":
+				o.Deferred = append(o.Deferred, it)
+			case "q", "quit":
+				o.Deferred = append(o.Deferred, items[i:]...)
+				return o
+			default:
+				fmt.Fprintln(out, "  Please answer a, r, d, or q.")
+				continue
+			}
+			break
+		}
+	}
+	return o
+}
+
+// stageReview applies the outcome to the working tree: accepted missing and
+// notice items are marked deprecated in models.json and dropped from
+// knownModels, and rejected items are added to the mute list. It returns the
+// files it changed. Accepted new models only appear in the pull request
+// body, since their metadata has to be written by hand.
+func stageReview(o reviewOutcome, now time.Time) ([]string, error) {
+	var changed []string
+	var deprecate []reviewItem
+	for _, it := range o.Accepted {
+		if it.Kind != reviewNew {
+			deprecate = append(deprecate, it)
+		}
+	}
+	if len(deprecate) > 0 {
+		data, err := os.ReadFile(dataFile)
+		if err != nil {
+			return nil, err
+		}
+		updater, err := os.ReadFile(updaterFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, it := range deprecate {
+			note := fmt.Sprintf("Removed from %s docs %s.", it.Provider, now.Format("Jan 2006"))
+			if it.Kind == reviewNotice {
+				note = fmt.Sprintf("Deprecated by %s: %s.", it.Provider, it.Detail)
+			}
+			if data, err = deprecateEntry(data, it.ModelID, note); err != nil {
+				return nil, err
+			}
+			updater = removeKnownModel(updater, it.ModelID)
+		}
+		if err := os.WriteFile(dataFile, data, 0o644); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(updaterFile, updater, 0o644); err != nil {
+			return nil, err
+		}
+		changed = append(changed, dataFile, updaterFile)
+	}
+	if len(o.Rejected) > 0 {
+		for _, it := range o.Rejected {
+			mutes.add(it)
+		}
+		if err := mutes.save(mutedFile); err != nil {
+			return nil, err
+		}
+		changed = append(changed, mutedFile)
+	}
+	return changed, nil
+}
+
+// deprecateEntry sets the Status of id's entry in the registry data file to
+// deprecated and prepends note to its Notes. The file is re-encoded in
+// canonical form, so only that entry's lines change.
+func deprecateEntry(src []byte, id, note string) ([]byte, error) {
+	ms, err := models.Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", dataFile, err)
+	}
+	m, ok := ms[id]
+	if !ok {
+		return nil, fmt.Errorf("%s: no entry for %s", dataFile, id)
+	}
+	m.Status = "deprecated"
+	m.Notes = strings.TrimSpace(note + " " + m.Notes)
+	ms[id] = m
+	return models.Encode(ms)
+}
+
+// removeKnownModel drops id's line from the knownModels literal in the
+// updater source, so a deprecated model stops being reported as missing.
+func removeKnownModel(src []byte, id string) []byte {
+	re := regexp.MustCompile(`\n\t\t` + regexp.QuoteMeta(strconv.Quote(id)) + `:\s+true,[^\n]*`)
+	return re.ReplaceAll(src, nil)
+}
+
+// reviewPRBody describes the staged changes and what is left to do by hand.
+func reviewPRBody(o reviewOutcome) string {
+	var b strings.Builder
+	b.WriteString("## Reviewed model registry update\n\n")
+	b.WriteString("Changes detected by the updater and accepted in review mode.\n")
+	section := func(title string, items []reviewItem, keep func(reviewItem) bool) {
+		var lines []string
+		for _, it := range items {
+			if keep(it) {
+				line := fmt.Sprintf("- `%s` (%s)", it.ModelID, it.Provider)
+				if it.Detail != "" {
+					line += ": " + it.Detail
+				}
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) > 0 {
+			b.WriteString("\n### " + title + "\n\n" + strings.Join(lines, "\n") + "\n")
+		}
+	}
+	section("Deprecated", o.Accepted, func(it reviewItem) bool { return it.Kind != reviewNew })
+	section("New models to add", o.Accepted, func(it reviewItem) bool { return it.Kind == reviewNew })
+	section("Muted", o.Rejected, func(reviewItem) bool { return true })
+	section("Deferred to the next run", o.Deferred, func(reviewItem) bool { return true })
+
+	b.WriteString("\n### Before merging\n\n")
+	for _, it := range o.Accepted {
+		if it.Kind == reviewNew {
+			b.WriteString("- [ ] Add each new model to `go-server/internal/models/models.json` (every field plus docs_url) and to `knownModels`\n")
+			b.WriteString("- [ ] Update `TestTotalModelCount` and `TestProviderCounts` in `data_test.go`\n")
+			break
+		}
+	}
+	b.WriteString("- [ ] Check each deprecated model's Notes and add a successor if there is one\n")
+	b.WriteString("- [ ] Run `go test ./... -v` to verify\n")
+	if runProvenance != nil {
+		b.WriteString(runProvenance.section())
+	}
+	return b.String()
+}
+
+// openReviewPR commits the staged files on a new branch, pushes it to each
+// host's git remote, and opens one pull request per host it reached.
+func openReviewPR(ctx context.Context, hosts []forge.Forge, files []string, o reviewOutcome, now time.Time) error {
+	branch := "updater/review-" + now.UTC().Format("20060102-150405")
+	title := fmt.Sprintf("Reviewed model updates - %s", now.Format("2006-01-02"))
+	hosts, err := pushToHosts(ctx, hosts, branch, title, files)
+	if err != nil {
+		return err
+	}
+	body := reviewPRBody(o)
+	forEachHost(hosts, func(host forge.Forge) {
+		if host == nil {
+			return
+		}
+		pr, err := host.CreatePullRequest(ctx, branch, reviewBase(), title, body, []string{"auto-update"}, false)
+		switch {
+		case pr != nil && err != nil:
+			fmt.Printf("[%s] Pull request opened: %s (not labelled: %v)\n", host.Name(), pr.URL, err)
+		case err != nil:
+			fmt.Printf("[%s] Failed to open pull request: %v\n", host.Name(), err)
+		default:
+			fmt.Printf("[%s] Pull request opened: %s\n", host.Name(), pr.URL)
+		}
+	})
+	return nil
+}
+
+// pushToHosts commits files on branch and pushes it to the git remote of
+// every host (see forge.Remote), returning the hosts whose remote received
+// it: a pull request can only be opened where the branch exists. Hosts
+// without a remote are skipped with a note.
+func pushToHosts(ctx context.Context, hosts []forge.Forge, branch, message string, files []string) ([]forge.Forge, error) {
+	var remotes []string
+	for _, host := range hosts {
+		if host == nil {
+			continue
+		}
+		if r := forge.Remote(host); r == "" {
+			fmt.Printf("[%s] No git remote configured for this target; not opening a pull request.\n", host.Name())
+		} else if !slices.Contains(remotes, r) {
+			remotes = append(remotes, r)
+		}
+	}
+	if len(remotes) == 0 {
+		return nil, errors.New("no target has a git remote to push the branch to")
+	}
+	pushed, err := pushBranch(ctx, branch, message, files, remotes)
+	if err != nil {
+		return nil, err
+	}
+	var reached []forge.Forge
+	for _, host := range hosts {
+		if host != nil && pushed[forge.Remote(host)] {
+			reached = append(reached, host)
+		}
+	}
+	return reached, nil
+}
+
+// pushBranch commits files on a new branch named branch, with message as the
+// commit message, and pushes it to each of remotes, reporting which ones
+// took it. A push failure to some remotes is logged; if every push or an
+// earlier step fails it checks out the branch it started on again, so the
+// rest of the run doesn't work on the half-made branch.
+func pushBranch(ctx context.Context, branch, message string, files, remotes []string) (pushed map[string]bool, err error) {
+	start, err := currentRef(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if out, cerr := exec.Command("git", "checkout", start).CombinedOutput(); cerr != nil {
+				err = fmt.Errorf("%w (and returning to %s: %v: %s)", err, start, cerr, bytes.TrimSpace(out))
+			}
+		}
+	}()
+	steps := [][]string{
+		{"checkout", "-b", branch},
+		append([]string{"add", "--"}, files...),
+		{"commit", "-m", message},
+	}
+	for _, args := range steps {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("git %s: %v: %s", args[0], err, bytes.TrimSpace(out))
+		}
+	}
+	pushed = make(map[string]bool)
+	var errs []error
+	for _, remote := range remotes {
+		out, err := exec.CommandContext(ctx, "git", "push", "-u", remote, branch).CombinedOutput()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("git push %s: %v: %s", remote, err, bytes.TrimSpace(out)))
+			continue
+		}
+		pushed[remote] = true
+	}
+	if len(pushed) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	for _, err := range errs {
+		fmt.Printf("WARNING: %v\n", err)
+	}
+	return pushed, nil
+}
+
+// currentRef returns the checked-out branch, or the commit for a detached
+// HEAD as CI checkouts usually are.
+func currentRef(ctx context.Context) (string, error) {
+	if out, err := exec.CommandContext(ctx, "git", "symbolic-ref", "-q", "--short", "HEAD").Output(); err == nil {
+		return string(bytes.TrimSpace(out)), nil
+	}
+	out, err := exec.CommandContext(ctx, "git", "rev-parse", "HEAD").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse: %v: %s", err, bytes.TrimSpace(out))
+	}
+	return string(bytes.TrimSpace(out)), nil
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-server/internal/models"
+)
+
+// defaultSnapshotDir is where anomaly page snapshots are written. Override
+// with UPDATER_SNAPSHOT_DIR; in CI, upload it as an artifact.
+const defaultSnapshotDir = "updater-snapshots"
+
+// defaultSnapshotBytes caps how much of each page a snapshot keeps, so one
+// fits gzipped in an issue body. Override with UPDATER_SNAPSHOT_BYTES.
+const defaultSnapshotBytes = 32 << 10
+
+// maxIssueBody is GitHub's issue body limit in characters, the tightest of
+// the supported forges. Snapshots only fill what the report leaves of it;
+// the rest are left to the snapshot dir.
+const maxIssueBody = 65536
+
+// fetchedPage is the last response read from a URL this run, cut to the
+// snapshot limit.
+type fetchedPage struct {
+	Status    int
+	Body      []byte
+	Truncated bool
+}
+
+// pageSnapshot is a fetched page saved because its provider looked wrong:
+// the fetch failed, the pattern rotted, or most models vanished at once.
+type pageSnapshot struct {
+	Provider string
+	Reason   string
+	URL      string
+	Page     fetchedPage
+}
+
+// snapshotStore remembers every page fetched this run and saves the ones
+// behind an anomaly, so the exact page state can be replayed when the
+// extraction pattern is fixed days later.
+type snapshotStore struct {
+	dir   string
+	limit int
+	pages map[string]fetchedPage
+	taken []pageSnapshot
+}
+
+// runSnapshots is the current run's store; fetchPage records into it. Nil
+// outside main (tests, library use).
+var runSnapshots *snapshotStore
+
+// newSnapshotStore reads the snapshot dir and size cap from the environment.
+func newSnapshotStore() *snapshotStore {
+	s := &snapshotStore{dir: defaultSnapshotDir, limit: defaultSnapshotBytes, pages: make(map[string]fetchedPage)}
+	if d := os.Getenv("UPDATER_SNAPSHOT_DIR"); d != "" {
+		s.dir = d
+	}
+	if n, err := strconv.Atoi(os.Getenv("UPDATER_SNAPSHOT_BYTES")); err == nil && n > 0 {
+		s.limit = n
+	}
+	return s
+}
+
+// seen records the raw body read from url, successful or not. Error pages
+// (anti-bot challenges, 5xx bodies) are often the most telling.
+func (s *snapshotStore) seen(url string, status int, raw []byte) {
+	if s == nil {
+		return
+	}
+	p := fetchedPage{Status: status}
+	if len(raw) > s.limit {
+		raw, p.Truncated = raw[:s.limit], true
+	}
+	p.Body = append([]byte(nil), raw...)
+	s.pages[url] = p
+}
+
+// capture saves the pages fetched from urls for provider, gzipped into the
+// snapshot dir, and keeps them for issue bodies. URLs never fetched this run
+// are skipped. It returns the files written.
+func (s *snapshotStore) capture(provider, reason string, urls ...string) []string {
+	if s == nil {
+		return nil
+	}
+	var paths []string
+	for _, url := range urls {
+		page, ok := s.pages[url]
+		if !ok {
+			continue
+		}
+		snap := pageSnapshot{Provider: provider, Reason: reason, URL: url, Page: page}
+		if path, err := s.write(snap); err != nil {
+			fmt.Printf("[%s] WARNING: could not save page snapshot of %s: %v\n", provider, url, err)
+		} else {
+			paths = append(paths, path)
+		}
+		s.taken = append(s.taken, snap)
+	}
+	return paths
+}
+
+// write stores one snapshot as <provider>-<url digest>.gz. The gzip header
+// comment records the URL, status, and reason.
+func (s *snapshotStore) write(snap pageSnapshot) (string, error) {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return "", err
+	}
+	name := strings.ToLower(snap.Provider) + "-" + bodyDigest(snap.URL)[:8] + ".gz"
+	path := filepath.Join(s.dir, name)
+	data, err := gzipSnapshot(snap)
+	if err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, data, 0o644)
+}
+
+// gzipSnapshot compresses a snapshot's body with its origin in the header.
+func gzipSnapshot(snap pageSnapshot) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Comment = fmt.Sprintf("%s HTTP %d: %s", snap.URL, snap.Page.Status, snap.Reason)
+	zw.ModTime = time.Now()
+	if _, err := zw.Write(snap.Page.Body); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// section renders the snapshots of providers as collapsed markdown blocks
+// holding the base64 of the gzipped body, each snapshot once however often
+// its provider is named. Blocks that would take the section past room bytes
+// become a one-line pointer to the snapshot dir, or are dropped when even
+// that doesn't fit. Empty when nothing was captured.
+func (s *snapshotStore) section(room int, providers ...string) string {
+	if s == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, snap := range s.taken {
+		if !slices.Contains(providers, snap.Provider) {
+			continue
+		}
+		data, err := gzipSnapshot(snap)
+		if err != nil {
+			continue
+		}
+		size := models.FormatInt(len(snap.Page.Body)) + " bytes"
+		if snap.Page.Truncated {
+			size = "first " + size
+		}
+		block := fmt.Sprintf("\n<details>\n<summary>Page snapshot: %s (%s)</summary>\n\n", snap.URL, snap.Reason) +
+			fmt.Sprintf("HTTP %d, %s. Decode with `base64 -d | gunzip`.\n\n```\n", snap.Page.Status, size) +
+			base64.StdEncoding.EncodeToString(data) + "\n```\n</details>\n"
+		if b.Len()+len(block) > room {
+			block = fmt.Sprintf("\nPage snapshot of %s (%s) is too large for this issue; see the run's %s artifact.\n", snap.URL, snap.Reason, s.dir)
+			if b.Len()+len(block) > room {
+				continue
+			}
+		}
+		b.WriteString(block)
+	}
+	return b.String()
+}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ProviderSource is one place the updater can list a provider's model IDs
+// from: a docs page scraped with a regex, a model-list API, an HTML table,
+// or a file in the provider's GitHub repository. Providers whose listings a
+// single regex can't express get a source that parses their structure.
+type ProviderSource interface {
+	// Fetch returns the source's model IDs, cleaned, and a record of what
+	// was read. An empty result is an error, so callers can fall back.
+	Fetch(ctx context.Context, client *http.Client) ([]string, sourceRecord, error)
+	// Locations lists the public URLs Fetch may read, for snapshots when it
+	// fails. Sources that need credentials list none.
+	Locations() []string
+	// Fingerprint describes everything that decides which IDs Fetch
+	// yields; see patternVersion.
+	Fingerprint() string
+}
+
+// Fetch scrapes the source's pages; see fetchModelsFromDocs.
+func (src DocSource) Fetch(ctx context.Context, client *http.Client) ([]string, sourceRecord, error) {
+	return fetchModelsFromDocs(ctx, client, src)
+}
+
+func (src DocSource) Locations() []string {
+	return slices.Concat(src.OfferURLs, src.MarkdownURLs, src.URLs)
+}
+
+func (src DocSource) Fingerprint() string {
+	part := strings.Join(src.URLs, " ") + "\x00" + src.Pattern.String()
+	if src.ExcludePattern != nil {
+		part += "\x00exclude:" + src.ExcludePattern.String()
+	}
+	if src.NormalizeRe != nil {
+		part += "\x00normalize:" + src.NormalizeRe.String() + "=" + src.NormalizeRepl
+	}
+	if src.NormalizeFunc != nil {
+		part += "\x00normalizefunc"
+	}
+	if src.Lowercase {
+		part += "\x00lowercase"
+	}
+	if len(src.MarkdownURLs) > 0 {
+		part += "\x00markdown:" + strings.Join(src.MarkdownURLs, " ")
+	}
+	if src.IDColumn != nil {
+		part += "\x00column:" + src.IDColumn.String()
+	}
+	if len(src.OfferURLs) > 0 {
+		part += "\x00offer:" + strings.Join(src.OfferURLs, " ") + "\x00attribute:" + src.OfferAttribute
+	}
+	return part
+}
+
+// APISource lists models from a provider's authenticated model-list
+// endpoint. Clean is the cleanup of the provider's configured source, so API
+// and docs runs report the same IDs.
+type APISource struct {
+	ModelsAPI
+	Key   string
+	Clean func([]string) []string
+}
+
+func (src APISource) Fetch(ctx context.Context, client *http.Client) ([]string, sourceRecord, error) {
+	ids, err := src.fetch(ctx, client, src.Key)
+	if err != nil {
+		return nil, sourceRecord{}, err
+	}
+	if src.Clean != nil {
+		ids = src.Clean(ids)
+	}
+	if len(ids) == 0 {
+		return nil, sourceRecord{}, fmt.Errorf("%s listed no tracked model IDs", src.URL)
+	}
+	return ids, sourceRecord{Kind: "api", URL: src.URL}, nil
+}
+
+func (src APISource) Locations() []string { return nil }
+
+func (src APISource) Fingerprint() string {
+	part := "api:" + src.URL
+	if src.Keep != nil {
+		part += "\x00keep:" + src.Keep.String()
+	}
+	return part
+}
+
+// TableSource reads model IDs from the column of HTML tables whose header
+// matches IDColumn, on the pages in URLs, applying Pattern to each cell.
+// Reading one column means prose, navigation, and code samples on the page
+// can't add IDs, which matters on pages that mention retired models or
+// other vendors' names. The embedded DocSource's cleanup rules apply.
+type TableSource struct {
+	DocSource
+}
+
+func (src TableSource) Fetch(ctx context.Context, client *http.Client) ([]string, sourceRecord, error) {
+	if src.IDColumn == nil {
+		return nil, sourceRecord{}, fmt.Errorf("no IDColumn configured for %s", strings.Join(src.URLs, ", "))
+	}
+	var lastErr error
+	for _, url := range src.URLs {
+		body, truncated, err := fetchPage(ctx, client, url, src.bodyLimit())
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		cells, found := htmlColumn(body, src.IDColumn)
+		if !found {
+			lastErr = fmt.Errorf("no table column matching %q in %s", src.IDColumn, url)
+			continue
+		}
+		if ids := src.clean(matchCells(cells, src.Pattern)); len(ids) > 0 {
+			return ids, sourceRecord{Kind: "table", URL: url, SHA256: bodyDigest(body), Truncated: truncated}, nil
+		}
+	}
+	if lastErr != nil {
+		return nil, sourceRecord{}, fmt.Errorf("all URLs failed: %w", lastErr)
+	}
+	return nil, sourceRecord{}, fmt.Errorf("no model IDs found in any URL")
+}
+
+func (src TableSource) Locations() []string { return src.URLs }
+
+func (src TableSource) Fingerprint() string { return "table:" + src.DocSource.Fingerprint() }
+
+// GitHubFileSource reads model IDs from files in a provider's GitHub
+// repository, such as the model list in its SDK, through
+// raw.githubusercontent.com with a jsDelivr mirror as fallback. The
+// embedded DocSource supplies the pattern and cleanup rules; its URLs are
+// ignored.
+type GitHubFileSource struct {
+	Repo  string   // owner/name
+	Ref   string   // branch or tag; "main" when empty
+	Paths []string // files to try in order
+	DocSource
+}
+
+// urls returns the raw and mirror URL of each path, in the order tried.
+func (src GitHubFileSource) urls() []string {
+	ref := src.Ref
+	if ref == "" {
+		ref = "main"
+	}
+	var urls []string
+	for _, p := range src.Paths {
+		urls = append(urls,
+			"https://raw.githubusercontent.com/"+src.Repo+"/"+ref+"/"+p,
+			"https://cdn.jsdelivr.net/gh/"+src.Repo+"@"+ref+"/"+p)
+	}
+	return urls
+}
+
+func (src GitHubFileSource) Fetch(ctx context.Context, client *http.Client) ([]string, sourceRecord, error) {
+	docs := src.DocSource
+	docs.URLs = src.urls()
+	ids, rec, err := fetchModelsFromDocs(ctx, client, docs)
+	if err == nil {
+		rec.Kind = "github"
+	}
+	return ids, rec, err
+}
+
+func (src GitHubFileSource) Locations() []string { return src.urls() }
+
+func (src GitHubFileSource) Fingerprint() string {
+	docs := src.DocSource
+	docs.URLs = src.urls()
+	return "github:" + docs.Fingerprint()
+}
+
+// sourcesFor lists a provider's sources in the order to try them: its
+// model-list API when a key is set, then its configured source.
+func sourcesFor(provider string) []ProviderSource {
+	var sources []ProviderSource
+	src, configured := providerSources[provider]
+	if api, key, ok := modelsAPIFor(provider); ok {
+		sources = append(sources, APISource{ModelsAPI: api, Key: key, Clean: cleanerFor(src)})
+	}
+	if configured {
+		sources = append(sources, src)
+	}
+	return sources
+}
+
+// cleanerFor returns the cleanup rules of src, or the universal ones (mode
+// suffixes and duplicates) for sources without rules of their own.
+func cleanerFor(src ProviderSource) func([]string) []string {
+	if c, ok := src.(interface{ clean([]string) []string }); ok {
+		return c.clean
+	}
+	return DocSource{}.clean
+}
+
+// sourceLimit returns the page size cap of src, for truncation warnings.
+func sourceLimit(src ProviderSource) int64 {
+	if l, ok := src.(interface{ bodyLimit() int64 }); ok {
+		return l.bodyLimit()
+	}
+	return defaultMaxBodyBytes
+}
+
+// fetchFromSources tries sources in order and returns the IDs of the first
+// that lists any, logging each fallback with logf.
+func fetchFromSources(ctx context.Context, client *http.Client, provider string, sources []ProviderSource, logf func(string, ...any)) ([]string, sourceRecord, error) {
+	var lastErr error
+	for i, src := range sources {
+		ids, rec, err := src.Fetch(ctx, client)
+		if err == nil {
+			if rec.Kind == "api" {
+				logf("[%s] Fetched %d models via API\n", provider, len(ids))
+			}
+			return ids, rec, nil
+		}
+		_, isAPI := src.(APISource)
+		switch {
+		case i+1 < len(sources) && isAPI:
+			logf("[%s] API fetch failed (%v), falling back to docs scraping\n", provider, err)
+		case i+1 < len(sources):
+			logf("[%s] %v; trying the next source\n", provider, err)
+		case isAPI && len(sources) == 1:
+			err = fmt.Errorf("API fetch failed and there are no docs to fall back to: %w", err)
+		}
+		lastErr = err
+	}
+	return nil, sourceRecord{}, lastErr
+}
+
+// allLocations lists the public URLs of every source, for snapshots.
+func allLocations(sources []ProviderSource) []string {
+	var urls []string
+	for _, src := range sources {
+		urls = append(urls, src.Locations()...)
+	}
+	return urls
+}
+
+var (
+	htmlTableRe = regexp.MustCompile(`(?is)<table\b.*?</table>`)
+	htmlRowRe   = regexp.MustCompile(`(?is)<tr\b.*?</tr>`)
+	htmlCellRe  = regexp.MustCompile(`(?is)<t[hd]\b[^>]*>(.*?)</t[hd]>`)
+	htmlTagRe   = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// htmlColumn returns the cells under every column whose header matches
+// header, across all tables in page. The first row of a table is its
+// header. Cells are reduced to their text. Column spans aren't expanded, so
+// tables that use them in data rows may shift cells. found reports whether
+// any table had such a column.
+func htmlColumn(page string, header *regexp.Regexp) (cells []string, found bool) {
+	for _, table := range htmlTableRe.FindAllString(page, -1) {
+		rows := htmlRowRe.FindAllString(table, -1)
+		if len(rows) == 0 {
+			continue
+		}
+		var cols []int
+		for c, h := range htmlRowCells(rows[0]) {
+			if header.MatchString(h) {
+				cols = append(cols, c)
+			}
+		}
+		if len(cols) == 0 {
+			continue
+		}
+		found = true
+		for _, row := range rows[1:] {
+			values := htmlRowCells(row)
+			for _, c := range cols {
+				if c < len(values) {
+					cells = append(cells, values[c])
+				}
+			}
+		}
+	}
+	return cells, found
+}
+
+// htmlRowCells returns the text of each cell in a table row.
+func htmlRowCells(row string) []string {
+	var cells []string
+	for _, m := range htmlCellRe.FindAllStringSubmatch(row, -1) {
+		text := html.UnescapeString(htmlTagRe.ReplaceAllString(m[1], " "))
+		cells = append(cells, strings.Join(strings.Fields(text), " "))
+	}
+	return cells
+}
+
+// matchCells applies pattern to each cell and returns the first capture
+// group of every match, without duplicates, in order.
+func matchCells(cells []string, pattern *regexp.Regexp) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, cell := range cells {
+		for _, m := range pattern.FindAllStringSubmatch(cell, -1) {
+			if len(m) >= 2 && !seen[m[1]] {
+				seen[m[1]] = true
+				ids = append(ids, m[1])
+			}
+		}
+	}
+	return ids
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// defaultStatusFile stores the last run's per-provider sync status for the
+// MCP server's model://registry/sync-status resource. Override with
+// UPDATER_STATUS_FILE, and point the server's MCP_SYNC_STATUS_FILE at the
+// same path (a shared volume in containers).
+const defaultStatusFile = "updater-status.json"
+
+// providerSync is one provider's outcome. VerifiedAt and the drift lists
+// carry over from earlier runs when this run couldn't check the provider, so
+// a failed fetch doesn't make stale data look fresh or hide open drift.
+type providerSync struct {
+	CheckedAt  string   `json:"checked_at"`
+	VerifiedAt string   `json:"verified_at,omitempty"`
+	Source     string   `json:"source,omitempty"` // api, docs, or aggregator
+	Scraped    int      `json:"scraped"`
+	New        []string `json:"new,omitempty"`
+	Missing    []string `json:"missing,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// syncStatus is the file the updater writes at the end of every run.
+type syncStatus struct {
+	RunID      string                  `json:"run_id"`
+	FinishedAt string                  `json:"finished_at"`
+	Providers  map[string]providerSync `json:"providers"`
+}
+
+// statusFile returns the status path from the environment or the default.
+func statusFile() string {
+	if p := os.Getenv("UPDATER_STATUS_FILE"); p != "" {
+		return p
+	}
+	return defaultStatusFile
+}
+
+// loadSyncStatus reads the previous run's status. A missing file is an empty
+// status.
+func loadSyncStatus(path string) (*syncStatus, error) {
+	s := &syncStatus{Providers: make(map[string]providerSync)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return &syncStatus{Providers: make(map[string]providerSync)}, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if s.Providers == nil {
+		s.Providers = make(map[string]providerSync)
+	}
+	return s, nil
+}
+
+// verified records a provider checked successfully at at.
+func (s *syncStatus) verified(name string, at time.Time, source string, scraped int, newModels, missing []string) {
+	ts := at.UTC().Format(time.RFC3339)
+	s.Providers[name] = providerSync{
+		CheckedAt:  ts,
+		VerifiedAt: ts,
+		Source:     source,
+		Scraped:    scraped,
+		New:        newModels,
+		Missing:    missing,
+	}
+}
+
+// failed records a provider that couldn't be checked, keeping its last
+// verification and drift.
+func (s *syncStatus) failed(name string, at time.Time, reason string) {
+	p := s.Providers[name]
+	p.CheckedAt = at.UTC().Format(time.RFC3339)
+	p.Error = reason
+	s.Providers[name] = p
+}
+
+// save stamps the run and writes the status file.
+func (s *syncStatus) save(path, runID string, finished time.Time) error {
+	s.RunID = runID
+	s.FinishedAt = finished.UTC().Format(time.RFC3339)
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -2,10 +2,11 @@ module go-server
 
 go 1.23.0
 
-require github.com/modelcontextprotocol/go-sdk v1.3.0
-
 require (
-	github.com/google/jsonschema-go v0.4.2 // indirect
-	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
-	golang.org/x/oauth2 v0.30.0 // indirect
+	github.com/google/jsonschema-go v0.4.2
+	github.com/modelcontextprotocol/go-sdk v1.3.0
+	github.com/yosida95/uritemplate/v3 v3.0.2
+	gopkg.in/yaml.v3 v3.0.1
 )
+
+require golang.org/x/oauth2 v0.30.0 // indirect
@@ -0,0 +1,100 @@
+// Package buildinfo reports the server's version, commit, and build date.
+// Release builds stamp them with -ldflags:
+//
+//	go build -ldflags "-X go-server/internal/buildinfo.version=1.4.0 \
+//	  -X go-server/internal/buildinfo.commit=$(git rev-parse HEAD) \
+//	  -X go-server/internal/buildinfo.date=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/server
+//
+// (make build does this). Unstamped builds fall back to the VCS details the
+// Go toolchain records, so a plain go build from a checkout still reports
+// its commit.
+package buildinfo
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+// Set with -ldflags -X; see the package doc.
+var (
+	version = ""
+	commit  = ""
+	date    = ""
+)
+
+// Info is the build's identity. /health, get_registry_version, the MCP
+// initialize result, and --version all report it.
+type Info struct {
+	Version string `json:"version"`          // release version, or "dev" when unstamped
+	Commit  string `json:"commit,omitempty"` // full VCS revision, "+dirty" when built from a modified tree
+	Date    string `json:"date,omitempty"`   // build (or, unstamped, commit) time, RFC 3339
+}
+
+var (
+	once sync.Once
+	info Info
+)
+
+// Get returns the build's Info.
+func Get() Info {
+	once.Do(func() { info = resolve(version, commit, date, debug.ReadBuildInfo) })
+	return info
+}
+
+// resolve fills in whatever -ldflags left empty from the toolchain's
+// build info.
+func resolve(version, commit, date string, read func() (*debug.BuildInfo, bool)) Info {
+	i := Info{Version: version, Commit: commit, Date: date}
+	if bi, ok := read(); ok {
+		var modified bool
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if i.Commit == "" {
+					i.Commit = s.Value
+				}
+			case "vcs.time":
+				if i.Date == "" {
+					i.Date = s.Value
+				}
+			case "vcs.modified":
+				modified = s.Value == "true"
+			}
+		}
+		if modified && commit == "" && i.Commit != "" {
+			i.Commit += "+dirty"
+		}
+	}
+	if i.Version == "" {
+		i.Version = "dev"
+	}
+	return i
+}
+
+// Short returns the first 12 characters of the commit, keeping any +dirty
+// suffix.
+func (i Info) Short() string {
+	rev, dirty := i.Commit, ""
+	if n := len(rev) - len("+dirty"); n > 0 && rev[n:] == "+dirty" {
+		rev, dirty = rev[:n], "+dirty"
+	}
+	if len(rev) > 12 {
+		rev = rev[:12]
+	}
+	return rev + dirty
+}
+
+// String renders the Info on one line, e.g. "1.4.0 (3f2a9c1b7d4e,
+// 2026-10-16T09:30:00Z)".
+func (i Info) String() string {
+	s := i.Version
+	switch {
+	case i.Commit != "" && i.Date != "":
+		s += " (" + i.Short() + ", " + i.Date + ")"
+	case i.Commit != "":
+		s += " (" + i.Short() + ")"
+	case i.Date != "":
+		s += " (" + i.Date + ")"
+	}
+	return s
+}
@@ -0,0 +1,51 @@
+package buildinfo
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func vcs(settings ...string) func() (*debug.BuildInfo, bool) {
+	return func() (*debug.BuildInfo, bool) {
+		bi := &debug.BuildInfo{}
+		for i := 0; i+1 < len(settings); i += 2 {
+			bi.Settings = append(bi.Settings, debug.BuildSetting{Key: settings[i], Value: settings[i+1]})
+		}
+		return bi, true
+	}
+}
+
+func TestResolve(t *testing.T) {
+	const rev = "3f2a9c1b7d4e5f60718293a4b5c6d7e8f9012345"
+	tests := []struct {
+		name                  string
+		version, commit, date string
+		read                  func() (*debug.BuildInfo, bool)
+		want                  Info
+		str                   string
+	}{
+		{"stamped wins over vcs", "1.4.0", "abc123", "2026-10-16T09:30:00Z",
+			vcs("vcs.revision", rev, "vcs.time", "2026-01-01T00:00:00Z", "vcs.modified", "true"),
+			Info{"1.4.0", "abc123", "2026-10-16T09:30:00Z"}, "1.4.0 (abc123, 2026-10-16T09:30:00Z)"},
+		{"vcs fallback", "", "", "",
+			vcs("vcs.revision", rev, "vcs.time", "2026-01-01T00:00:00Z"),
+			Info{"dev", rev, "2026-01-01T00:00:00Z"}, "dev (3f2a9c1b7d4e, 2026-01-01T00:00:00Z)"},
+		{"dirty tree", "", "", "",
+			vcs("vcs.revision", rev, "vcs.modified", "true"),
+			Info{"dev", rev + "+dirty", ""}, "dev (3f2a9c1b7d4e+dirty)"},
+		{"nothing known", "", "", "",
+			func() (*debug.BuildInfo, bool) { return nil, false },
+			Info{Version: "dev"}, "dev"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolve(tt.version, tt.commit, tt.date, tt.read)
+			if got != tt.want {
+				t.Errorf("resolve = %+v, want %+v", got, tt.want)
+			}
+			if s := got.String(); s != tt.str {
+				t.Errorf("String = %q, want %q", s, tt.str)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+// Package changelog records registry mutations as an append-only, sequenced
+// log so downstream mirrors can sync incrementally. Entries are appended with
+// `go run ./cmd/regdiff -changelog internal/changelog/changelog.json OLD`
+// when a release changes the registry.
+package changelog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"go-server/internal/models"
+	"go-server/internal/tools"
+)
+
+// Kind is the type of mutation an entry records.
+type Kind string
+
+const (
+	KindAdded   Kind = "added"
+	KindRemoved Kind = "removed"
+	KindChanged Kind = "changed"
+)
+
+// Entry is one model mutation. Seq increases by one per entry and doubles as
+// the sync cursor.
+type Entry struct {
+	Seq     int      `json:"seq"`
+	Date    string   `json:"date"` // YYYY-MM-DD
+	Kind    Kind     `json:"kind"`
+	ModelID string   `json:"model_id"`
+	Fields  []string `json:"fields,omitempty"` // JSON field names, for KindChanged
+}
+
+//go:embed changelog.json
+var changelogJSON []byte
+
+// Entries is the embedded changelog, oldest first.
+var Entries = mustParse(changelogJSON)
+
+func mustParse(data []byte) []Entry {
+	entries, err := Parse(data)
+	if err != nil {
+		panic("changelog.json: " + err.Error())
+	}
+	return entries
+}
+
+// Parse decodes a changelog file and checks that sequence numbers run
+// 1, 2, 3, … without gaps.
+func Parse(data []byte) ([]Entry, error) {
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	for i, e := range entries {
+		if e.Seq != i+1 {
+			return nil, fmt.Errorf("entry %d has seq %d, want %d", i, e.Seq, i+1)
+		}
+	}
+	return entries, nil
+}
+
+// Cursor returns the sequence number of the newest entry, or 0 for an empty
+// log.
+func Cursor() int {
+	return len(Entries)
+}
+
+// Since returns the entries after cursor. ok is false when cursor is ahead of
+// the log (e.g. the server was rolled back), meaning the caller must resync
+// from a full copy of the registry.
+func Since(cursor int) (entries []Entry, ok bool) {
+	if cursor < 0 || cursor > len(Entries) {
+		return nil, false
+	}
+	return Entries[cursor:], true
+}
+
+// Append adds entries for d to log, dated date, continuing its sequence.
+// Removed models come first, then added, then changed, each sorted by ID.
+func Append(log []Entry, d tools.RegistryDiff, date string) []Entry {
+	next := len(log) + 1
+	add := func(kind Kind, id string, fields []string) {
+		log = append(log, Entry{Seq: next, Date: date, Kind: kind, ModelID: id, Fields: fields})
+		next++
+	}
+	for _, id := range sorted(d.Removed) {
+		add(KindRemoved, id, nil)
+	}
+	for _, id := range sorted(d.Added) {
+		add(KindAdded, id, nil)
+	}
+	changed := append([]tools.ModelChange(nil), d.Changed...)
+	sort.Slice(changed, func(i, j int) bool { return changed[i].ID < changed[j].ID })
+	for _, c := range changed {
+		fields := make([]string, len(c.Fields))
+		for i, f := range c.Fields {
+			fields[i] = f.Field
+		}
+		add(KindChanged, c.ID, fields)
+	}
+	return log
+}
+
+// ProviderChurn counts entries dated on or after since (YYYY-MM-DD) per
+// provider. Providers come from ms; entries for models no longer in ms are
+// not counted, since their provider is unknown.
+func ProviderChurn(entries []Entry, ms map[string]models.Model, since string) map[string]int {
+	churn := make(map[string]int)
+	for _, e := range entries {
+		if e.Date < since {
+			continue
+		}
+		if m, ok := ms[e.ModelID]; ok {
+			churn[m.Provider]++
+		}
+	}
+	return churn
+}
+
+// StatusChanges returns the date of the newest entry that changed each
+// model's status, for dating deprecations.
+func StatusChanges(entries []Entry) map[string]time.Time {
+	dates := make(map[string]time.Time)
+	for _, e := range entries {
+		if e.Kind != KindChanged || !slices.Contains(e.Fields, "status") {
+			continue
+		}
+		if t, err := time.Parse("2006-01-02", e.Date); err == nil {
+			dates[e.ModelID] = t
+		}
+	}
+	return dates
+}
+
+func sorted(ids []string) []string {
+	out := append([]string(nil), ids...)
+	sort.Strings(out)
+	return out
+}
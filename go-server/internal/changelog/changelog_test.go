@@ -0,0 +1,116 @@
+package changelog
+
+import (
+	"testing"
+
+	"go-server/internal/models"
+	"go-server/internal/tools"
+)
+
+func TestEmbeddedChangelog(t *testing.T) {
+	for _, e := range Entries {
+		switch e.Kind {
+		case KindAdded, KindChanged:
+			if _, ok := models.Models[e.ModelID]; !ok && !removedLater(e) {
+				t.Errorf("seq %d: %s %q is not in the registry and never removed", e.Seq, e.Kind, e.ModelID)
+			}
+		case KindRemoved:
+		default:
+			t.Errorf("seq %d: unknown kind %q", e.Seq, e.Kind)
+		}
+	}
+}
+
+// removedLater reports whether e's model is removed by a later entry.
+func removedLater(e Entry) bool {
+	for _, later := range Entries[e.Seq:] {
+		if later.Kind == KindRemoved && later.ModelID == e.ModelID {
+			return true
+		}
+	}
+	return false
+}
+
+func TestProviderChurn(t *testing.T) {
+	ms := map[string]models.Model{
+		"a-model": {ID: "a-model", Provider: "Acme"},
+		"b-model": {ID: "b-model", Provider: "Acme"},
+		"c-model": {ID: "c-model", Provider: "Beta"},
+	}
+	log := Append(nil, tools.RegistryDiff{Added: []string{"a-model", "c-model"}}, "2026-01-15")
+	log = Append(log, tools.RegistryDiff{
+		Added:   []string{"b-model"},
+		Removed: []string{"gone-model"},
+		Changed: []tools.ModelChange{{ID: "a-model", Fields: []tools.FieldChange{{Field: "pricing_input"}}}},
+	}, "2026-03-01")
+
+	got := ProviderChurn(log, ms, "2026-03-01")
+	if len(got) != 1 || got["Acme"] != 2 {
+		t.Errorf("ProviderChurn since 2026-03-01 = %v, want map[Acme:2]", got)
+	}
+	if got := ProviderChurn(log, ms, "2026-01-01"); got["Acme"] != 3 || got["Beta"] != 1 {
+		t.Errorf("ProviderChurn since 2026-01-01 = %v, want Acme 3, Beta 1", got)
+	}
+}
+
+func TestStatusChanges(t *testing.T) {
+	log := Append(nil, tools.RegistryDiff{Changed: []tools.ModelChange{
+		{ID: "a-model", Fields: []tools.FieldChange{{Field: "status"}, {Field: "notes"}}},
+		{ID: "b-model", Fields: []tools.FieldChange{{Field: "pricing_input"}}},
+	}}, "2026-01-15")
+	log = Append(log, tools.RegistryDiff{Changed: []tools.ModelChange{
+		{ID: "a-model", Fields: []tools.FieldChange{{Field: "status"}}},
+	}}, "2026-04-02")
+	got := StatusChanges(log)
+	if len(got) != 1 || got["a-model"].Format("2006-01-02") != "2026-04-02" {
+		t.Errorf("StatusChanges = %v, want a-model at its newest status change", got)
+	}
+}
+
+func TestParseRejectsGaps(t *testing.T) {
+	if _, err := Parse([]byte(`[{"seq":1},{"seq":3}]`)); err == nil {
+		t.Error("expected error for sequence gap")
+	}
+}
+
+func TestAppendAndSince(t *testing.T) {
+	log := Append(nil, tools.RegistryDiff{
+		Added:   []string{"b-model", "a-model"},
+		Removed: []string{"old-model"},
+		Changed: []tools.ModelChange{{ID: "c-model", Fields: []tools.FieldChange{{Field: "status"}, {Field: "pricing_input"}}}},
+	}, "2026-03-01")
+
+	want := []struct {
+		kind Kind
+		id   string
+	}{{KindRemoved, "old-model"}, {KindAdded, "a-model"}, {KindAdded, "b-model"}, {KindChanged, "c-model"}}
+	if len(log) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(log), len(want), log)
+	}
+	for i, w := range want {
+		if log[i].Seq != i+1 || log[i].Kind != w.kind || log[i].ModelID != w.id || log[i].Date != "2026-03-01" {
+			t.Errorf("entry %d = %+v, want seq %d %s %s", i, log[i], i+1, w.kind, w.id)
+		}
+	}
+	if f := log[3].Fields; len(f) != 2 || f[0] != "status" {
+		t.Errorf("changed fields = %v", f)
+	}
+
+	log = Append(log, tools.RegistryDiff{Added: []string{"d-model"}}, "2026-04-01")
+	if log[4].Seq != 5 {
+		t.Errorf("expected sequence to continue at 5, got %d", log[4].Seq)
+	}
+
+	saved := Entries
+	t.Cleanup(func() { Entries = saved })
+	Entries = log
+	if got, ok := Since(3); !ok || len(got) != 2 || got[0].Seq != 4 {
+		t.Errorf("Since(3) = %+v, %v", got, ok)
+	}
+	if got, ok := Since(Cursor()); !ok || len(got) != 0 {
+		t.Errorf("Since(cursor) = %+v, %v; want no entries", got, ok)
+	}
+	if _, ok := Since(Cursor() + 1); ok {
+		t.Error("expected a cursor ahead of the log to require a resync")
+	}
+}
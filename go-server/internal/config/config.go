@@ -0,0 +1,520 @@
+// Package config loads server settings from an optional YAML file, applies
+// environment variable overrides, and validates the result at startup.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"go-server/internal/middleware"
+	"go-server/internal/models"
+	"go-server/internal/tools"
+)
+
+// Transports accepted for Config.Transport.
+var Transports = []string{"stdio", "sse", "streamable-http", "both"}
+
+// Listeners accepted for Config.Listener: a plain TCP socket, one bound with
+// SO_REUSEPORT so a new process can take over the port before the old one
+// exits, or a socket inherited from systemd socket activation.
+var Listeners = []string{"tcp", "reuseport", "systemd"}
+
+// DefaultShutdownGrace is how long a stopping server waits for open
+// requests and SSE streams before closing them.
+const DefaultShutdownGrace = 10 * time.Second
+
+// DefaultModelsReload is how often the server checks models_file for
+// changes when no interval is configured.
+const DefaultModelsReload = 30 * time.Second
+
+// Config is the full server configuration. Zero-valued sections in the YAML
+// file keep their defaults; environment variables override the file.
+type Config struct {
+	Transport        string             `yaml:"transport"`
+	Port             int                `yaml:"port"`
+	Listener         string             `yaml:"listener"`
+	ShutdownGrace    time.Duration      `yaml:"shutdown_grace"`
+	Stateless        bool               `yaml:"stateless"`
+	ModelsFile       string             `yaml:"models_file"`
+	ModelsReload     time.Duration      `yaml:"models_reload"` // how often models_file is checked for changes; 0 disables reloading
+	PolicyFile       string             `yaml:"policy_file"`
+	CoverageFile     string             `yaml:"coverage_file"`
+	SyncStatusFile   string             `yaml:"sync_status_file"`
+	CORS             CORS               `yaml:"cors"`
+	RateLimit        RateLimit          `yaml:"rate_limit"`
+	AccessLog        AccessLog          `yaml:"access_log"`
+	Sessions         Sessions           `yaml:"sessions"`
+	OutputBudget     OutputBudget       `yaml:"output_budget"`
+	ToolTimeout      ToolTimeout        `yaml:"tool_timeout"`
+	RecommendWeights map[string]float64 `yaml:"recommend_weights"`
+	BlendRatio       float64            `yaml:"blend_ratio"`
+	Features         Features           `yaml:"features"`
+	Tenants          map[string]Tenant  `yaml:"tenants"`
+}
+
+// CORS controls which browser origins may call the MCP endpoints. With no
+// allowed_origins and no origins entries, any origin is allowed.
+type CORS struct {
+	AllowedOrigins []string `yaml:"allowed_origins"`
+	// AllowCredentials sends Access-Control-Allow-Credentials so browsers
+	// include cookies and HTTP auth. It requires an explicit origin list.
+	AllowCredentials bool `yaml:"allow_credentials"`
+	// PrivateNetwork answers Chrome's private network access preflight
+	// (Access-Control-Request-Private-Network), which a public web IDE
+	// sends before calling a server on localhost or a LAN address. Like
+	// AllowCredentials it requires an explicit origin list.
+	PrivateNetwork bool `yaml:"private_network"`
+	// Origins allows each listed origin with extra settings on top of the
+	// ones above.
+	Origins map[string]CORSOrigin `yaml:"origins"`
+}
+
+// CORSOrigin is one origin's CORS settings.
+type CORSOrigin struct {
+	AllowCredentials bool `yaml:"allow_credentials"`
+	PrivateNetwork   bool `yaml:"private_network"`
+}
+
+// For returns the settings for a browser origin, or false if the origin is
+// not allowed. An origin let in only by * or an empty list gets neither
+// credentials nor private network access, whatever the config says.
+func (c CORS) For(origin string) (CORSOrigin, bool) {
+	s := CORSOrigin{AllowCredentials: c.AllowCredentials, PrivateNetwork: c.PrivateNetwork}
+	for o, extra := range c.Origins {
+		if strings.EqualFold(o, origin) {
+			s.AllowCredentials = s.AllowCredentials || extra.AllowCredentials
+			s.PrivateNetwork = s.PrivateNetwork || extra.PrivateNetwork
+			return s, true
+		}
+	}
+	if len(c.AllowedOrigins) == 0 && len(c.Origins) == 0 {
+		return CORSOrigin{}, true
+	}
+	for _, a := range c.AllowedOrigins {
+		if strings.EqualFold(a, origin) {
+			return s, true
+		}
+	}
+	if slices.Contains(c.AllowedOrigins, "*") {
+		return CORSOrigin{}, true
+	}
+	return s, false
+}
+
+// RateLimit mirrors middleware.Config.
+type RateLimit struct {
+	RequestsPerWindow int           `yaml:"requests_per_window"`
+	Window            time.Duration `yaml:"window"`
+	MaxConnsPerIP     int           `yaml:"max_conns_per_ip"`
+	MaxTotalConns     int           `yaml:"max_total_conns"`
+	MaxBodyBytes      int64         `yaml:"max_body_bytes"`
+}
+
+// Middleware converts the settings to a middleware.Config.
+func (r RateLimit) Middleware() middleware.Config {
+	return middleware.Config{
+		RequestsPerWindow: r.RequestsPerWindow,
+		Window:            r.Window,
+		MaxConnsPerIP:     r.MaxConnsPerIP,
+		MaxTotalConns:     r.MaxTotalConns,
+		MaxBodyBytes:      r.MaxBodyBytes,
+	}
+}
+
+// String summarizes the effective limits for the startup log.
+func (r RateLimit) String() string {
+	return fmt.Sprintf("%d req/%s per IP, %d conns per IP, %d conns total, %d-byte bodies",
+		r.RequestsPerWindow, r.Window, r.MaxConnsPerIP, r.MaxTotalConns, r.MaxBodyBytes)
+}
+
+// Default session lifetimes. Keepalive pings stay under the 100-second idle
+// cutoff of common proxies such as Cloudflare.
+const (
+	DefaultKeepAlive   = 30 * time.Second
+	DefaultIdleTimeout = 30 * time.Minute
+)
+
+// Sessions controls how long HTTP MCP sessions live. Zero disables either
+// setting.
+type Sessions struct {
+	// KeepAlive is how often SSE sessions are pinged. The traffic stops
+	// proxies from cutting idle streams, and a session whose client misses
+	// a ping is closed.
+	KeepAlive time.Duration `yaml:"keepalive"`
+	// IdleTimeout closes SSE and stateful streamable HTTP sessions whose
+	// client has sent nothing for this long, freeing their connection slot.
+	IdleTimeout time.Duration `yaml:"idle_timeout"`
+}
+
+// AccessLog turns on structured per-request logging for the MCP and API
+// endpoints. /health and /metrics are never logged.
+type AccessLog struct {
+	Enabled bool `yaml:"enabled"`
+	// SampleRate is the fraction of requests logged, from 0 to 1. Server
+	// errors are logged regardless.
+	SampleRate float64 `yaml:"sample_rate"`
+	// Redact names extra query parameters whose values are never logged,
+	// on top of middleware.DefaultRedactedParams.
+	Redact []string `yaml:"redact"`
+}
+
+// Middleware converts the settings to a middleware.AccessLogConfig.
+func (a AccessLog) Middleware() middleware.AccessLogConfig {
+	return middleware.AccessLogConfig{SampleRate: a.SampleRate, Redact: a.Redact}
+}
+
+// OutputBudget sets tool output size limits in bytes. 0 disables a limit.
+type OutputBudget struct {
+	Default int            `yaml:"default"`
+	PerTool map[string]int `yaml:"per_tool"`
+}
+
+// DefaultToolTimeout bounds a tool call when no timeout is configured. It
+// leaves room for the network-backed tools' own 5-second HTTP timeouts.
+const DefaultToolTimeout = 10 * time.Second
+
+// ToolTimeout bounds how long a tool call may run before the server gives
+// up and returns an error, so a stuck dependency can't hang a session.
+// 0 disables a limit.
+type ToolTimeout struct {
+	Default time.Duration            `yaml:"default"`
+	PerTool map[string]time.Duration `yaml:"per_tool"`
+}
+
+// For returns the timeout for the named tool.
+func (t ToolTimeout) For(tool string) time.Duration {
+	if d, ok := t.PerTool[tool]; ok {
+		return d
+	}
+	return t.Default
+}
+
+// ScoringWeights returns the recommend_model weights: the defaults with
+// RecommendWeights applied.
+func (c Config) ScoringWeights() (tools.ScoringWeights, error) {
+	return tools.DefaultScoringWeights().With(c.RecommendWeights)
+}
+
+// Features toggles tools and behaviors that make outbound calls, to the
+// network or to the client's model, or that shape what tools tell agents.
+type Features struct {
+	// ProviderStatus enables check_provider_status and recommend_model's
+	// avoid_outages option.
+	ProviderStatus bool `yaml:"provider_status"`
+	// RemoteSnapshots lets diff_registries fetch snapshot_url, from public
+	// addresses only. Off by default, since the URL comes from the client.
+	RemoteSnapshots bool `yaml:"remote_snapshots"`
+	// SamplingEnrichment lets get_model_info ask a client that supports
+	// MCP sampling whether an unknown model ID looks like a new model or a
+	// typo. Off by default, since it spends the client's tokens.
+	SamplingEnrichment bool `yaml:"sampling_enrichment"`
+	// UseInCodeFooter ends list_models and search_models tables with a
+	// USE IN CODE line naming the newest model per provider that org
+	// policy allows. Callers can override it with the footer parameter.
+	UseInCodeFooter bool `yaml:"use_in_code_footer"`
+}
+
+// Tenant is a namespaced registry served on /mcp/{name}: the base registry
+// plus an overlay of extra models, under the tenant's own policy.
+type Tenant struct {
+	// OverlayFile is a registry snapshot (model://registry/all format) of
+	// models to add or override. Optional.
+	OverlayFile string `yaml:"overlay_file"`
+	// PolicyFile is the tenant's org policy JSON. Empty uses policy_file.
+	PolicyFile string `yaml:"policy_file"`
+	// APIKeysEnv names an environment variable holding the tenant's API
+	// keys, comma-separated. Only requests carrying one see overlay models
+	// marked "internal": true. Optional.
+	APIKeysEnv string `yaml:"api_keys_env"`
+}
+
+// APIKeys returns the keys in the tenant's APIKeysEnv variable.
+func (t Tenant) APIKeys() []string {
+	if t.APIKeysEnv == "" {
+		return nil
+	}
+	return splitList(os.Getenv(t.APIKeysEnv))
+}
+
+// tenantNameRe restricts tenant names to URL-safe path segments.
+var tenantNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// Default returns the settings used when no file or environment is given.
+func Default() Config {
+	rl := middleware.DefaultConfig()
+	return Config{
+		Transport: "stdio",
+		Port:      8000,
+		Listener:  "tcp",
+		RateLimit: RateLimit{
+			RequestsPerWindow: rl.RequestsPerWindow,
+			Window:            rl.Window,
+			MaxConnsPerIP:     rl.MaxConnsPerIP,
+			MaxTotalConns:     rl.MaxTotalConns,
+			MaxBodyBytes:      rl.MaxBodyBytes,
+		},
+		AccessLog:     AccessLog{SampleRate: 1},
+		Sessions:      Sessions{KeepAlive: DefaultKeepAlive, IdleTimeout: DefaultIdleTimeout},
+		OutputBudget:  OutputBudget{Default: tools.DefaultOutputBudget},
+		ToolTimeout:   ToolTimeout{Default: DefaultToolTimeout},
+		ShutdownGrace: DefaultShutdownGrace,
+		ModelsReload:  DefaultModelsReload,
+		BlendRatio:    models.DefaultBlendRatio,
+		Features:      Features{ProviderStatus: true, UseInCodeFooter: true},
+	}
+}
+
+// Load builds the configuration: defaults, then the YAML file at path (if
+// non-empty), then environment overrides. The result is validated.
+func Load(path string) (Config, error) {
+	cfg := Default()
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("reading config: %w", err)
+		}
+		if err := cfg.decode(data); err != nil {
+			return Config{}, fmt.Errorf("parsing config %s: %w", path, err)
+		}
+	}
+	if err := cfg.applyEnv(os.Environ()); err != nil {
+		return Config{}, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// decode merges YAML over cfg. Unknown keys are rejected so typos fail at
+// startup instead of silently keeping a default.
+func (c *Config) decode(data []byte) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// budgetPrefix is the per-tool output budget variable prefix, e.g.
+// MCP_MAX_OUTPUT_BYTES_LIST_MODELS.
+const budgetPrefix = "MCP_MAX_OUTPUT_BYTES_"
+
+// timeoutPrefix is the per-tool timeout variable prefix, e.g.
+// MCP_TOOL_TIMEOUT_DIFF_REGISTRIES.
+const timeoutPrefix = "MCP_TOOL_TIMEOUT_"
+
+// applyEnv overrides settings from KEY=value pairs as returned by os.Environ.
+// Empty values are ignored.
+func (c *Config) applyEnv(environ []string) error {
+	for _, kv := range environ {
+		key, val, _ := strings.Cut(kv, "=")
+		if val == "" {
+			continue
+		}
+		var err error
+		switch {
+		case key == "MCP_TRANSPORT":
+			c.Transport = val
+		case key == "PORT":
+			c.Port, err = strconv.Atoi(val)
+		case key == "MCP_LISTENER":
+			c.Listener = val
+		case key == "MCP_SHUTDOWN_GRACE":
+			c.ShutdownGrace, err = time.ParseDuration(val)
+		case key == "MCP_STATELESS":
+			c.Stateless, err = strconv.ParseBool(val)
+		case key == "MCP_BLEND_RATIO":
+			c.BlendRatio, err = strconv.ParseFloat(val, 64)
+		case key == "MCP_MODELS_FILE":
+			c.ModelsFile = val
+		case key == "MCP_MODELS_RELOAD":
+			c.ModelsReload, err = time.ParseDuration(val)
+		case key == "MCP_USE_IN_CODE_FOOTER":
+			c.Features.UseInCodeFooter, err = strconv.ParseBool(val)
+		case key == "MCP_POLICY_FILE":
+			c.PolicyFile = val
+		case key == "MCP_COVERAGE_FILE":
+			c.CoverageFile = val
+		case key == "MCP_SYNC_STATUS_FILE":
+			c.SyncStatusFile = val
+		case key == "MCP_CORS_ORIGINS":
+			c.CORS.AllowedOrigins = splitList(val)
+		case key == "MCP_CORS_CREDENTIALS":
+			c.CORS.AllowCredentials, err = strconv.ParseBool(val)
+		case key == "MCP_CORS_PRIVATE_NETWORK":
+			c.CORS.PrivateNetwork, err = strconv.ParseBool(val)
+		case key == "MCP_RATE_LIMIT_RPM":
+			c.RateLimit.RequestsPerWindow, err = strconv.Atoi(val)
+			c.RateLimit.Window = time.Minute
+		case key == "MCP_MAX_CONNS_PER_IP":
+			c.RateLimit.MaxConnsPerIP, err = strconv.Atoi(val)
+		case key == "MCP_MAX_TOTAL_CONNS":
+			c.RateLimit.MaxTotalConns, err = strconv.Atoi(val)
+		case key == "MCP_MAX_BODY_BYTES":
+			c.RateLimit.MaxBodyBytes, err = strconv.ParseInt(val, 10, 64)
+		case key == "MCP_SSE_KEEPALIVE":
+			c.Sessions.KeepAlive, err = time.ParseDuration(val)
+		case key == "MCP_SESSION_IDLE_TIMEOUT":
+			c.Sessions.IdleTimeout, err = time.ParseDuration(val)
+		case key == "MCP_ACCESS_LOG":
+			c.AccessLog.Enabled, err = strconv.ParseBool(val)
+		case key == "MCP_ACCESS_LOG_SAMPLE_RATE":
+			c.AccessLog.SampleRate, err = strconv.ParseFloat(val, 64)
+		case key == "MCP_ACCESS_LOG_REDACT":
+			c.AccessLog.Redact = splitList(val)
+		case key == "MCP_MAX_OUTPUT_BYTES":
+			c.OutputBudget.Default, err = strconv.Atoi(val)
+		case strings.HasPrefix(key, budgetPrefix):
+			var n int
+			n, err = strconv.Atoi(val)
+			if c.OutputBudget.PerTool == nil {
+				c.OutputBudget.PerTool = make(map[string]int)
+			}
+			c.OutputBudget.PerTool[strings.ToLower(strings.TrimPrefix(key, budgetPrefix))] = n
+		case key == "MCP_TOOL_TIMEOUT":
+			c.ToolTimeout.Default, err = time.ParseDuration(val)
+		case strings.HasPrefix(key, timeoutPrefix):
+			var d time.Duration
+			d, err = time.ParseDuration(val)
+			if c.ToolTimeout.PerTool == nil {
+				c.ToolTimeout.PerTool = make(map[string]time.Duration)
+			}
+			c.ToolTimeout.PerTool[strings.ToLower(strings.TrimPrefix(key, timeoutPrefix))] = d
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s=%q: %w", key, val, err)
+		}
+	}
+	return nil
+}
+
+// Validate reports every invalid setting at once.
+func (c Config) Validate() error {
+	var errs []error
+	if !slices.Contains(Transports, c.Transport) {
+		errs = append(errs, fmt.Errorf("transport %q must be one of %s", c.Transport, strings.Join(Transports, ", ")))
+	}
+	if c.Port < 1 || c.Port > 65535 {
+		errs = append(errs, fmt.Errorf("port %d out of range 1-65535", c.Port))
+	}
+	if !slices.Contains(Listeners, c.Listener) {
+		errs = append(errs, fmt.Errorf("listener %q must be one of %s", c.Listener, strings.Join(Listeners, ", ")))
+	}
+	if c.ShutdownGrace < 0 {
+		errs = append(errs, fmt.Errorf("shutdown_grace %s is negative", c.ShutdownGrace))
+	}
+	if c.ModelsReload < 0 {
+		errs = append(errs, fmt.Errorf("models_reload %s is negative", c.ModelsReload))
+	}
+	if c.Sessions.KeepAlive < 0 || c.Sessions.IdleTimeout < 0 {
+		errs = append(errs, fmt.Errorf("sessions.keepalive %s and sessions.idle_timeout %s must not be negative", c.Sessions.KeepAlive, c.Sessions.IdleTimeout))
+	}
+	rl := c.RateLimit
+	for _, f := range []struct {
+		name string
+		n    int64
+	}{
+		{"requests_per_window", int64(rl.RequestsPerWindow)},
+		{"window", int64(rl.Window)},
+		{"max_conns_per_ip", int64(rl.MaxConnsPerIP)},
+		{"max_total_conns", int64(rl.MaxTotalConns)},
+		{"max_body_bytes", rl.MaxBodyBytes},
+	} {
+		if f.n <= 0 {
+			errs = append(errs, fmt.Errorf("rate_limit.%s must be positive", f.name))
+		}
+	}
+	if rl.MaxConnsPerIP > rl.MaxTotalConns {
+		errs = append(errs, fmt.Errorf("rate_limit.max_conns_per_ip (%d) exceeds max_total_conns (%d)", rl.MaxConnsPerIP, rl.MaxTotalConns))
+	}
+	if c.AccessLog.SampleRate < 0 || c.AccessLog.SampleRate > 1 {
+		errs = append(errs, fmt.Errorf("access_log.sample_rate %g out of range 0-1", c.AccessLog.SampleRate))
+	}
+	if c.OutputBudget.Default < 0 {
+		errs = append(errs, fmt.Errorf("output_budget.default %d is negative", c.OutputBudget.Default))
+	}
+	for tool, n := range c.OutputBudget.PerTool {
+		if n < 0 {
+			errs = append(errs, fmt.Errorf("output_budget.per_tool.%s %d is negative", tool, n))
+		}
+	}
+	if c.ToolTimeout.Default < 0 {
+		errs = append(errs, fmt.Errorf("tool_timeout.default %s is negative", c.ToolTimeout.Default))
+	}
+	for tool, d := range c.ToolTimeout.PerTool {
+		if d < 0 {
+			errs = append(errs, fmt.Errorf("tool_timeout.per_tool.%s %s is negative", tool, d))
+		}
+	}
+	if c.BlendRatio <= 0 || math.IsNaN(c.BlendRatio) || math.IsInf(c.BlendRatio, 0) {
+		errs = append(errs, fmt.Errorf("blend_ratio %g must be a positive number of input tokens per output token", c.BlendRatio))
+	}
+	if _, err := c.ScoringWeights(); err != nil {
+		errs = append(errs, fmt.Errorf("recommend_weights: %w", err))
+	}
+	for _, o := range c.CORS.AllowedOrigins {
+		if o != "*" && !strings.HasPrefix(o, "http://") && !strings.HasPrefix(o, "https://") {
+			errs = append(errs, fmt.Errorf("cors origin %q must be * or start with http:// or https://", o))
+		}
+	}
+	for o := range c.CORS.Origins {
+		if !strings.HasPrefix(o, "http://") && !strings.HasPrefix(o, "https://") {
+			errs = append(errs, fmt.Errorf("cors.origins key %q must start with http:// or https://", o))
+		}
+	}
+	if c.CORS.AllowCredentials && (len(c.CORS.AllowedOrigins) == 0 || slices.Contains(c.CORS.AllowedOrigins, "*")) {
+		errs = append(errs, errors.New("cors.allow_credentials needs an explicit allowed_origins list without *; otherwise any site could make credentialed requests"))
+	}
+	if c.CORS.PrivateNetwork && (slices.Contains(c.CORS.AllowedOrigins, "*") || len(c.CORS.AllowedOrigins) == 0 && len(c.CORS.Origins) == 0) {
+		errs = append(errs, errors.New("cors.private_network needs an explicit allowed_origins or origins list without *; otherwise any site could reach this server from inside the network"))
+	}
+	for _, f := range [][2]string{{"models_file", c.ModelsFile}, {"policy_file", c.PolicyFile}, {"coverage_file", c.CoverageFile}} {
+		if f[1] == "" {
+			continue
+		}
+		if _, err := os.Stat(f[1]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f[0], err))
+		}
+	}
+	for name, t := range c.Tenants {
+		if !tenantNameRe.MatchString(name) {
+			errs = append(errs, fmt.Errorf("tenant name %q must be lowercase letters, digits, and dashes", name))
+		}
+		for _, f := range [][2]string{{"overlay_file", t.OverlayFile}, {"policy_file", t.PolicyFile}} {
+			if f[1] == "" {
+				continue
+			}
+			if _, err := os.Stat(f[1]); err != nil {
+				errs = append(errs, fmt.Errorf("tenants.%s.%s: %w", name, f[0], err))
+			}
+		}
+		if t.APIKeysEnv != "" && len(t.APIKeys()) == 0 {
+			errs = append(errs, fmt.Errorf("tenants.%s.api_keys_env: $%s holds no keys", name, t.APIKeysEnv))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// splitList splits a comma-separated value, dropping blanks.
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
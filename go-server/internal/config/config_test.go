@@ -0,0 +1,320 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-server/internal/tools"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDefaultIsValid(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Fatalf("default config should validate: %v", err)
+	}
+}
+
+func TestLoadYAML(t *testing.T) {
+	policy := writeFile(t, "policy.json", "{}")
+	path := writeFile(t, "config.yaml", `
+transport: both
+port: 9090
+policy_file: `+policy+`
+cors:
+  allowed_origins: [https://app.example.com]
+rate_limit:
+  requests_per_window: 60
+  window: 30s
+output_budget:
+  per_tool:
+    list_models: 16384
+features:
+  remote_snapshots: true
+  sampling_enrichment: true
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Transport != "both" || cfg.Port != 9090 || cfg.PolicyFile != policy {
+		t.Errorf("unexpected top-level settings: %+v", cfg)
+	}
+	if cfg.RateLimit.RequestsPerWindow != 60 || cfg.RateLimit.Window != 30*time.Second {
+		t.Errorf("unexpected rate limit: %+v", cfg.RateLimit)
+	}
+	// Keys not in the file keep their defaults.
+	if cfg.RateLimit.MaxTotalConns != Default().RateLimit.MaxTotalConns {
+		t.Errorf("expected default max_total_conns, got %d", cfg.RateLimit.MaxTotalConns)
+	}
+	if cfg.OutputBudget.PerTool["list_models"] != 16384 || cfg.OutputBudget.Default != Default().OutputBudget.Default {
+		t.Errorf("unexpected output budget: %+v", cfg.OutputBudget)
+	}
+	if !cfg.Features.RemoteSnapshots || !cfg.Features.ProviderStatus || !cfg.Features.SamplingEnrichment || !cfg.Features.UseInCodeFooter {
+		t.Errorf("unexpected features: %+v", cfg.Features)
+	}
+}
+
+func TestLoadRejectsUnknownKeys(t *testing.T) {
+	path := writeFile(t, "config.yaml", "prot: 9090\n")
+	if _, err := Load(path); err == nil {
+		t.Fatal("expected error for unknown key")
+	}
+}
+
+func TestLoadEmptyFile(t *testing.T) {
+	path := writeFile(t, "config.yaml", "")
+	if _, err := Load(path); err != nil {
+		t.Fatalf("empty file should load defaults: %v", err)
+	}
+}
+
+func TestEnvOverridesFile(t *testing.T) {
+	path := writeFile(t, "config.yaml", "transport: sse\nport: 9090\n")
+	t.Setenv("MCP_TRANSPORT", "streamable-http")
+	t.Setenv("PORT", "7000")
+	t.Setenv("MCP_STATELESS", "true")
+	t.Setenv("MCP_CORS_ORIGINS", "https://a.example.com, https://b.example.com")
+	t.Setenv("MCP_MAX_OUTPUT_BYTES", "4096")
+	t.Setenv("MCP_MAX_OUTPUT_BYTES_LIST_MODELS", "0")
+	t.Setenv("MCP_TOOL_TIMEOUT", "3s")
+	t.Setenv("MCP_TOOL_TIMEOUT_DIFF_REGISTRIES", "30s")
+	history := writeFile(t, "history.json", "{}")
+	t.Setenv("MCP_COVERAGE_FILE", history)
+	t.Setenv("MCP_MODELS_FILE", history)
+	t.Setenv("MCP_SYNC_STATUS_FILE", "/shared/updater-status.json")
+	t.Setenv("MCP_LISTENER", "reuseport")
+	t.Setenv("MCP_SHUTDOWN_GRACE", "5m")
+	t.Setenv("MCP_MODELS_RELOAD", "0s")
+	t.Setenv("MCP_USE_IN_CODE_FOOTER", "false")
+	t.Setenv("MCP_ACCESS_LOG", "true")
+	t.Setenv("MCP_ACCESS_LOG_SAMPLE_RATE", "0.1")
+	t.Setenv("MCP_ACCESS_LOG_REDACT", "tenant_key, invite")
+	t.Setenv("MCP_BLEND_RATIO", "1")
+	t.Setenv("MCP_SSE_KEEPALIVE", "15s")
+	t.Setenv("MCP_SESSION_IDLE_TIMEOUT", "0")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Transport != "streamable-http" || cfg.Port != 7000 || !cfg.Stateless {
+		t.Errorf("env did not override file: %+v", cfg)
+	}
+	if len(cfg.CORS.AllowedOrigins) != 2 || cfg.CORS.AllowedOrigins[1] != "https://b.example.com" {
+		t.Errorf("unexpected origins: %v", cfg.CORS.AllowedOrigins)
+	}
+	if cfg.OutputBudget.Default != 4096 {
+		t.Errorf("expected default budget 4096, got %d", cfg.OutputBudget.Default)
+	}
+	if n, ok := cfg.OutputBudget.PerTool["list_models"]; !ok || n != 0 {
+		t.Errorf("expected list_models budget 0, got %d (set=%v)", n, ok)
+	}
+	if cfg.ToolTimeout.For("list_models") != 3*time.Second || cfg.ToolTimeout.For("diff_registries") != 30*time.Second {
+		t.Errorf("unexpected tool timeouts: %+v", cfg.ToolTimeout)
+	}
+	if cfg.ModelsFile != history {
+		t.Errorf("expected models file %s, got %q", history, cfg.ModelsFile)
+	}
+	if cfg.CoverageFile != history {
+		t.Errorf("expected coverage file %s, got %q", history, cfg.CoverageFile)
+	}
+	if cfg.SyncStatusFile != "/shared/updater-status.json" {
+		t.Errorf("expected sync status file from env, got %q (it need not exist yet)", cfg.SyncStatusFile)
+	}
+	if cfg.BlendRatio != 1 {
+		t.Errorf("expected blend ratio 1, got %g", cfg.BlendRatio)
+	}
+	if cfg.ModelsReload != 0 {
+		t.Errorf("expected models reload disabled from env, got %s", cfg.ModelsReload)
+	}
+	if cfg.Features.UseInCodeFooter {
+		t.Error("expected the USE IN CODE footer disabled from env")
+	}
+	if cfg.Listener != "reuseport" || cfg.ShutdownGrace != 5*time.Minute {
+		t.Errorf("unexpected listener settings: %q, %s", cfg.Listener, cfg.ShutdownGrace)
+	}
+	if !cfg.AccessLog.Enabled || cfg.AccessLog.SampleRate != 0.1 || len(cfg.AccessLog.Redact) != 2 || cfg.AccessLog.Redact[1] != "invite" {
+		t.Errorf("unexpected access log settings: %+v", cfg.AccessLog)
+	}
+	if cfg.Sessions.KeepAlive != 15*time.Second || cfg.Sessions.IdleTimeout != 0 {
+		t.Errorf("unexpected session settings: %+v", cfg.Sessions)
+	}
+}
+
+func TestInvalidEnvFails(t *testing.T) {
+	t.Setenv("PORT", "eighty")
+	if _, err := Load(""); err == nil || !strings.Contains(err.Error(), "PORT") {
+		t.Fatalf("expected PORT error, got %v", err)
+	}
+}
+
+func TestRateLimitEnv(t *testing.T) {
+	path := writeFile(t, "config.yaml", "rate_limit:\n  requests_per_window: 10\n  window: 10s\n  max_conns_per_ip: 5\n")
+	t.Setenv("MCP_RATE_LIMIT_RPM", "600")
+	t.Setenv("MCP_MAX_TOTAL_CONNS", "1000")
+	t.Setenv("MCP_MAX_BODY_BYTES", "131072")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rl := cfg.RateLimit
+	if rl.RequestsPerWindow != 600 || rl.Window != time.Minute || rl.MaxConnsPerIP != 5 || rl.MaxTotalConns != 1000 || rl.MaxBodyBytes != 131072 {
+		t.Errorf("unexpected rate limits: %+v", rl)
+	}
+	if got, want := rl.String(), "600 req/1m0s per IP, 5 conns per IP, 1000 conns total, 131072-byte bodies"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	t.Setenv("MCP_MAX_CONNS_PER_IP", "lots")
+	if _, err := Load(""); err == nil || !strings.Contains(err.Error(), "MCP_MAX_CONNS_PER_IP") {
+		t.Errorf("expected a parse error naming the variable, got %v", err)
+	}
+	t.Setenv("MCP_MAX_CONNS_PER_IP", "0")
+	if _, err := Load(""); err == nil || !strings.Contains(err.Error(), "rate_limit.max_conns_per_ip must be positive") {
+		t.Errorf("expected a validation error, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := Default()
+	cfg.Transport = "websocket"
+	cfg.Port = 0
+	cfg.RateLimit.MaxConnsPerIP = 500
+	cfg.CORS.AllowedOrigins = []string{"example.com"}
+	cfg.PolicyFile = filepath.Join(t.TempDir(), "missing.json")
+	cfg.CoverageFile = filepath.Join(t.TempDir(), "history.json")
+	cfg.ModelsFile = filepath.Join(t.TempDir(), "models.json")
+	cfg.ToolTimeout.PerTool = map[string]time.Duration{"list_models": -time.Second}
+	cfg.RecommendWeights = map[string]float64{"speed": 2}
+	cfg.Listener = "inetd"
+	cfg.ShutdownGrace = -time.Second
+	cfg.ModelsReload = -time.Second
+	cfg.AccessLog.SampleRate = 1.5
+	cfg.BlendRatio = -3
+	cfg.Sessions.KeepAlive = -time.Second
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{"transport", "port", "max_conns_per_ip", "cors origin", "models_file", "policy_file", "coverage_file", "tool_timeout.per_tool.list_models", "recommend_weights", "listener", "shutdown_grace", "models_reload", "access_log.sample_rate", "blend_ratio", "sessions.keepalive"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got: %v", want, err)
+		}
+	}
+}
+
+func TestCORSOrigins(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+cors:
+  allowed_origins: [https://app.example.com]
+  private_network: true
+  origins:
+    https://ide.example.dev:
+      allow_credentials: true
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := cfg.CORS.For("https://APP.example.com"); !ok || s.AllowCredentials || !s.PrivateNetwork {
+		t.Errorf("app origin: %+v, %v", s, ok)
+	}
+	if s, ok := cfg.CORS.For("https://ide.example.dev"); !ok || !s.AllowCredentials || !s.PrivateNetwork {
+		t.Errorf("ide origin: %+v, %v", s, ok)
+	}
+	if _, ok := cfg.CORS.For("https://evil.example.com"); ok {
+		t.Error("unlisted origin should not be allowed")
+	}
+	if _, ok := (CORS{}).For("https://any.example.com"); !ok {
+		t.Error("an empty CORS config should allow any origin")
+	}
+	if s, ok := (CORS{AllowedOrigins: []string{"*"}, PrivateNetwork: true, AllowCredentials: true}).For("https://any.example.com"); !ok || s.PrivateNetwork || s.AllowCredentials {
+		t.Errorf("a wildcard origin should get neither private network access nor credentials: %+v, %v", s, ok)
+	}
+
+	cfg = Default()
+	cfg.CORS.AllowCredentials = true
+	cfg.CORS.PrivateNetwork = true
+	cfg.CORS.AllowedOrigins = []string{"*"}
+	cfg.CORS.Origins = map[string]CORSOrigin{"ide.example.dev": {}}
+	err = cfg.Validate()
+	for _, want := range []string{"cors.allow_credentials", "cors.private_network", "cors.origins key"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got: %v", want, err)
+		}
+	}
+}
+
+func TestScoringWeights(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+recommend_weights:
+  recency: 0
+  reasoning: 8
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := cfg.ScoringWeights()
+	if err != nil {
+		t.Fatal(err)
+	}
+	def := tools.DefaultScoringWeights()
+	if w.Recency != 0 || w.Reasoning != 8 || w.Vision != def.Vision {
+		t.Errorf("expected recency and reasoning overridden, the rest default: %+v", w)
+	}
+}
+
+func TestLoadTenants(t *testing.T) {
+	overlay := writeFile(t, "acme.json", "{}")
+	path := writeFile(t, "config.yaml", `
+tenants:
+  acme:
+    overlay_file: `+overlay+`
+  beta-team: {}
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Tenants) != 2 || cfg.Tenants["acme"].OverlayFile != overlay {
+		t.Errorf("unexpected tenants: %+v", cfg.Tenants)
+	}
+
+	t.Setenv("ACME_MCP_KEYS", " key-one, ,key-two ")
+	if got := (Tenant{APIKeysEnv: "ACME_MCP_KEYS"}).APIKeys(); strings.Join(got, "|") != "key-one|key-two" {
+		t.Errorf("APIKeys() = %q", got)
+	}
+
+	cfg = Default()
+	cfg.Tenants = map[string]Tenant{
+		"Acme/Team": {},
+		"beta":      {PolicyFile: filepath.Join(t.TempDir(), "missing.json")},
+		"gamma":     {APIKeysEnv: "GAMMA_MCP_KEYS_UNSET"},
+	}
+	err = cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{`tenant name "Acme/Team"`, "tenants.beta.policy_file", "tenants.gamma.api_keys_env: $GAMMA_MCP_KEYS_UNSET holds no keys"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got: %v", want, err)
+		}
+	}
+}
+
+func TestExampleConfigLoads(t *testing.T) {
+	if _, err := Load("../../config.example.yaml"); err != nil {
+		t.Fatalf("config.example.yaml should load: %v", err)
+	}
+}
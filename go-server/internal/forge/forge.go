@@ -0,0 +1,166 @@
+// Package forge abstracts the code host the updater files issues and pull
+// requests on. GitHub, GitLab, and Gitea are supported, selected by
+// UPDATER_FORGE, so self-hosted mirrors get the same auto-issue workflow.
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go-server/internal/github"
+)
+
+// Issue is the subset of issue fields the updater uses.
+type Issue struct {
+	Number int
+	Title  string
+	Body   string
+	URL    string
+}
+
+// PullRequest is an opened pull (GitHub, Gitea) or merge (GitLab) request.
+// Body is only set on requests returned by OpenPullRequests.
+type PullRequest struct {
+	Number int
+	Body   string
+	URL    string
+}
+
+// Forge is a code host the updater reports to.
+type Forge interface {
+	// Name is the host's display name, used as a log prefix.
+	Name() string
+	// OpenIssues returns every open issue carrying label.
+	OpenIssues(ctx context.Context, label string) ([]Issue, error)
+	// OpenPullRequests returns every open pull request carrying label.
+	// Hosts list them apart from issues, so OpenIssues doesn't see them.
+	OpenPullRequests(ctx context.Context, label string) ([]PullRequest, error)
+	// CreateIssue opens an issue with the given labels.
+	CreateIssue(ctx context.Context, title, body string, labels []string) (*Issue, error)
+	// CreatePullRequest opens a request to merge head into base, with
+	// labels. A draft request is marked as not ready to merge.
+	CreatePullRequest(ctx context.Context, head, base, title, body string, labels []string, draft bool) (*PullRequest, error)
+}
+
+// FromEnv builds the forge named by UPDATER_FORGE (github by default) from
+// its environment variables:
+//
+//	github: GITHUB_TOKEN, GITHUB_REPO (owner/name)
+//	gitlab: GITLAB_TOKEN, GITLAB_PROJECT (group/name or numeric ID), GITLAB_URL (default https://gitlab.com)
+//	gitea:  GITEA_TOKEN, GITEA_REPO (owner/name), GITEA_URL
+//
+// It returns nil, nil if the selected forge's variables are unset, and an
+// error for an unknown UPDATER_FORGE.
+func FromEnv(httpClient *http.Client) (Forge, error) {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	switch kind := strings.ToLower(os.Getenv("UPDATER_FORGE")); kind {
+	case "", "github":
+		c, ok := github.NewClientFromEnv(httpClient)
+		if !ok {
+			return nil, nil
+		}
+		return NewGitHub(c), nil
+	case "gitlab":
+		token, project := os.Getenv("GITLAB_TOKEN"), os.Getenv("GITLAB_PROJECT")
+		if token == "" || project == "" {
+			return nil, nil
+		}
+		return NewGitLab(httpClient, os.Getenv("GITLAB_URL"), token, project), nil
+	case "gitea":
+		token, repo, base := os.Getenv("GITEA_TOKEN"), os.Getenv("GITEA_REPO"), os.Getenv("GITEA_URL")
+		if token == "" || repo == "" || base == "" {
+			return nil, nil
+		}
+		return NewGitea(httpClient, base, token, repo), nil
+	default:
+		return nil, fmt.Errorf("unknown UPDATER_FORGE %q (want github, gitlab, or gitea)", kind)
+	}
+}
+
+// APIError is returned for non-2xx responses from GitLab and Gitea.
+type APIError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s %s: HTTP %d: %s", e.Method, e.URL, e.StatusCode, e.Body)
+}
+
+// restClient sends JSON requests with retries for the GitLab and Gitea
+// clients. Transport errors and 5xx responses are retried with linear
+// back-off; other failures return an *APIError.
+type restClient struct {
+	http       *http.Client
+	auth       func(*http.Request)
+	maxRetries int
+	sleep      func(time.Duration) // overridable in tests
+}
+
+func newRESTClient(httpClient *http.Client, auth func(*http.Request)) restClient {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return restClient{http: httpClient, auth: auth, maxRetries: 3, sleep: time.Sleep}
+}
+
+// do sends the request and decodes a 2xx body into out when out is non-nil.
+func (c restClient) do(ctx context.Context, method, u string, payload, out any) (http.Header, error) {
+	var body []byte
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("marshal %s %s: %w", method, u, err)
+		}
+		body = b
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= max(c.maxRetries, 1); attempt++ {
+		if attempt > 1 {
+			c.sleep(time.Duration(attempt-1) * 2 * time.Second)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		c.auth(req)
+		req.Header.Set("Accept", "application/json")
+		if payload != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			defer resp.Body.Close()
+			if out != nil && resp.StatusCode != http.StatusNoContent {
+				if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+					return resp.Header, fmt.Errorf("decode %s %s: %w", method, u, err)
+				}
+			}
+			return resp.Header, nil
+		}
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		lastErr = &APIError{StatusCode: resp.StatusCode, Method: method, URL: u, Body: strings.TrimSpace(string(respBody))}
+		if resp.StatusCode < 500 {
+			return nil, lastErr
+		}
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,367 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-server/internal/github"
+)
+
+func noSleep(time.Duration) {}
+
+func TestFromEnv(t *testing.T) {
+	for _, tc := range []struct {
+		env  map[string]string
+		want string // Name(), or "" for nil
+		err  bool
+	}{
+		{env: map[string]string{}, want: ""},
+		{env: map[string]string{"GITHUB_TOKEN": "t", "GITHUB_REPO": "o/r"}, want: "GitHub"},
+		{env: map[string]string{"UPDATER_FORGE": "gitlab", "GITLAB_TOKEN": "t", "GITLAB_PROJECT": "g/p"}, want: "GitLab"},
+		{env: map[string]string{"UPDATER_FORGE": "gitlab", "GITHUB_TOKEN": "t", "GITHUB_REPO": "o/r"}, want: ""},
+		{env: map[string]string{"UPDATER_FORGE": "Gitea", "GITEA_TOKEN": "t", "GITEA_REPO": "o/r", "GITEA_URL": "https://git.example.com"}, want: "Gitea"},
+		{env: map[string]string{"UPDATER_FORGE": "gitea", "GITEA_TOKEN": "t", "GITEA_REPO": "o/r"}, want: ""},
+		{env: map[string]string{"UPDATER_FORGE": "bitbucket"}, err: true},
+	} {
+		for _, k := range []string{"UPDATER_FORGE", "GITHUB_TOKEN", "GITHUB_REPO", "GITLAB_TOKEN", "GITLAB_PROJECT", "GITLAB_URL", "GITEA_TOKEN", "GITEA_REPO", "GITEA_URL"} {
+			t.Setenv(k, tc.env[k])
+		}
+		f, err := FromEnv(nil)
+		if (err != nil) != tc.err {
+			t.Errorf("%v: err = %v", tc.env, err)
+			continue
+		}
+		got := ""
+		if f != nil {
+			got = f.Name()
+		}
+		if got != tc.want {
+			t.Errorf("%v: got forge %q, want %q", tc.env, got, tc.want)
+		}
+	}
+}
+
+func TestAllFromEnv_Targets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "targets.json")
+	if err := os.WriteFile(path, []byte(`[
+		{"repo": "upstream/registry", "token_env": "UPSTREAM_TOKEN"},
+		{"forge": "gitlab", "repo": "platform/registry", "url": "https://gitlab.internal", "token_env": "MIRROR_TOKEN"},
+		{"forge": "gitea", "repo": "ops/registry", "token_env": "MIRROR_TOKEN"},
+		{"repo": "fork/registry", "token_env": "UNSET_TOKEN"},
+		{"repo": "backup/registry", "token_env": "MIRROR_TOKEN", "remote": "backup"}
+	]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("UPDATER_TARGETS", path)
+	t.Setenv("UPSTREAM_TOKEN", "a")
+	t.Setenv("MIRROR_TOKEN", "b")
+	t.Setenv("UNSET_TOKEN", "")
+
+	forges, err := AllFromEnv(nil)
+	var names []string
+	for _, f := range forges {
+		names = append(names, f.Name())
+	}
+	if strings.Join(names, ", ") != "GitHub upstream/registry, GitLab platform/registry, GitHub backup/registry" {
+		t.Fatalf("opened %v", names)
+	}
+	// The first target defaults to origin; others only push where told to.
+	for i, want := range []string{"origin", "", "backup"} {
+		if got := Remote(forges[i]); got != want {
+			t.Errorf("%s: remote = %q, want %q", forges[i].Name(), got, want)
+		}
+	}
+	if err == nil || !strings.Contains(err.Error(), "gitea targets need a url") || !strings.Contains(err.Error(), `"UNSET_TOKEN" is unset`) {
+		t.Errorf("expected errors for the bad targets, got %v", err)
+	}
+}
+
+func TestAllFromEnv_FallsBackToSingleForge(t *testing.T) {
+	for _, k := range []string{"UPDATER_TARGETS", "UPDATER_FORGE", "GITHUB_TOKEN", "GITHUB_REPO"} {
+		t.Setenv(k, "")
+	}
+	if forges, err := AllFromEnv(nil); len(forges) != 0 || err != nil {
+		t.Errorf("nothing configured: got %v, %v", forges, err)
+	}
+	t.Setenv("GITHUB_TOKEN", "t")
+	t.Setenv("GITHUB_REPO", "o/r")
+	if forges, err := AllFromEnv(nil); len(forges) != 1 || forges[0].Name() != "GitHub" || Remote(forges[0]) != "origin" || err != nil {
+		t.Errorf("single forge: got %v, %v", forges, err)
+	}
+}
+
+func TestTargetOpen_GitHubEnterpriseURL(t *testing.T) {
+	t.Setenv("GHE_TOKEN", "t")
+	f, err := Target{Repo: "o/r", URL: "https://ghe.example.com/api/v3/", TokenEnv: "GHE_TOKEN"}.Open(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.(named).Forge.(*GitHub).Client.BaseURL; got != "https://ghe.example.com/api/v3" {
+		t.Errorf("BaseURL = %q", got)
+	}
+}
+
+func TestGitHubOpenIssues(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if q := r.URL.Query().Get("q"); q != "repo:owner/repo is:issue state:open label:auto-update" {
+			t.Errorf("q = %q", q)
+		}
+		fmt.Fprint(w, `{"items":[{"number":7,"body":"fp","html_url":"https://github.com/owner/repo/issues/7"}]}`)
+	}))
+	defer srv.Close()
+	c := github.NewClient(srv.Client(), "t", "owner/repo")
+	c.BaseURL = srv.URL
+
+	issues, err := NewGitHub(c).OpenIssues(context.Background(), "auto-update")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].Number != 7 || issues[0].URL != "https://github.com/owner/repo/issues/7" {
+		t.Errorf("unexpected issues: %+v", issues)
+	}
+}
+
+func TestGitHubOpenIssues_SearchesOncePerRun(t *testing.T) {
+	var searches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/search/issues" {
+			searches.Add(1)
+			fmt.Fprint(w, `{"items":[{"number":7,"body":"fp"}]}`)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"number":8,"body":"new"}`)
+	}))
+	defer srv.Close()
+	c := github.NewClient(srv.Client(), "t", "owner/repo")
+	c.BaseURL = srv.URL
+	g := NewGitHub(c)
+	ctx := context.Background()
+
+	if _, err := g.OpenIssues(ctx, "auto-update"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.CreateIssue(ctx, "t", "new", []string{"auto-update", "deprecation"}); err != nil {
+		t.Fatal(err)
+	}
+	issues, err := g.OpenIssues(ctx, "auto-update")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if searches.Load() != 1 {
+		t.Errorf("expected one search, got %d", searches.Load())
+	}
+	if len(issues) != 2 || issues[1].Number != 8 {
+		t.Errorf("created issue should join the cached list: %+v", issues)
+	}
+}
+
+func TestGitLabOpenIssues_Pagination(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("PRIVATE-TOKEN"); got != "secret" {
+			t.Errorf("PRIVATE-TOKEN = %q", got)
+		}
+		if r.URL.EscapedPath() != "/api/v4/projects/group%2Fregistry/issues" {
+			t.Errorf("path = %s", r.URL.EscapedPath())
+		}
+		q := r.URL.Query()
+		if q.Get("state") != "opened" || q.Get("labels") != "auto-update" {
+			t.Errorf("query = %s", r.URL.RawQuery)
+		}
+		if q.Get("page") == "1" {
+			w.Header().Set("X-Next-Page", "2")
+			fmt.Fprint(w, `[{"iid":1,"description":"a","web_url":"u1"}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"iid":2,"description":"b","web_url":"u2"}]`)
+	}))
+	defer srv.Close()
+
+	issues, err := NewGitLab(srv.Client(), srv.URL+"/", "secret", "group/registry").OpenIssues(context.Background(), "auto-update")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 2 || issues[0].Body != "a" || issues[1].Number != 2 {
+		t.Errorf("expected issues 1 and 2 across pages, got %+v", issues)
+	}
+}
+
+func TestGitLabCreateIssueAndMergeRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		json.NewDecoder(r.Body).Decode(&payload)
+		switch r.URL.Path {
+		case "/api/v4/projects/42/issues":
+			if payload["description"] != "body" || payload["labels"] != "auto-update,scraper-health" {
+				t.Errorf("issue payload = %v", payload)
+			}
+			fmt.Fprint(w, `{"iid":3,"title":"t","description":"body","web_url":"https://gitlab.example.com/g/p/-/issues/3"}`)
+		case "/api/v4/projects/42/merge_requests":
+			if payload["source_branch"] != "auto-update/x" || payload["target_branch"] != "main" || payload["title"] != "Draft: t" {
+				t.Errorf("merge request payload = %v", payload)
+			}
+			fmt.Fprint(w, `{"iid":9,"web_url":"https://gitlab.example.com/g/p/-/merge_requests/9"}`)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+	g := NewGitLab(srv.Client(), srv.URL, "secret", "42")
+
+	issue, err := g.CreateIssue(context.Background(), "t", "body", []string{"auto-update", "scraper-health"})
+	if err != nil || issue.Number != 3 || !strings.HasSuffix(issue.URL, "/issues/3") {
+		t.Fatalf("CreateIssue = %+v, %v", issue, err)
+	}
+	mr, err := g.CreatePullRequest(context.Background(), "auto-update/x", "main", "t", "b", nil, true)
+	if err != nil || mr.Number != 9 {
+		t.Fatalf("CreatePullRequest = %+v, %v", mr, err)
+	}
+}
+
+func TestGiteaCreateIssue_ResolvesAndCreatesLabels(t *testing.T) {
+	var createdLabel string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "token secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/repos/owner/repo/labels":
+			fmt.Fprint(w, `[{"id":11,"name":"auto-update"}]`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/repos/owner/repo/labels":
+			var l map[string]string
+			json.NewDecoder(r.Body).Decode(&l)
+			createdLabel = l["name"]
+			fmt.Fprint(w, `{"id":12,"name":"scraper-health"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/repos/owner/repo/issues":
+			var payload struct {
+				Labels []int64 `json:"labels"`
+			}
+			json.NewDecoder(r.Body).Decode(&payload)
+			if fmt.Sprint(payload.Labels) != "[11 12]" {
+				t.Errorf("labels = %v, want IDs [11 12]", payload.Labels)
+			}
+			fmt.Fprint(w, `{"number":5,"html_url":"https://git.example.com/owner/repo/issues/5"}`)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	issue, err := NewGitea(srv.Client(), srv.URL, "secret", "owner/repo").CreateIssue(context.Background(), "t", "b", []string{"auto-update", "scraper-health"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.Number != 5 || createdLabel != "scraper-health" {
+		t.Errorf("issue = %+v, created label %q", issue, createdLabel)
+	}
+}
+
+func TestGiteaOpenIssues_Pagination(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("type") != "issues" || r.URL.Query().Get("labels") != "auto-update" {
+			t.Errorf("query = %s", r.URL.RawQuery)
+		}
+		var batch []giteaIssue
+		if r.URL.Query().Get("page") == "1" {
+			for i := range giteaPageSize {
+				batch = append(batch, giteaIssue{Number: i + 1})
+			}
+		} else {
+			batch = append(batch, giteaIssue{Number: giteaPageSize + 1, Body: "last"})
+		}
+		json.NewEncoder(w).Encode(batch)
+	}))
+	defer srv.Close()
+
+	issues, err := NewGitea(srv.Client(), srv.URL, "secret", "owner/repo").OpenIssues(context.Background(), "auto-update")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != giteaPageSize+1 || issues[giteaPageSize].Body != "last" {
+		t.Errorf("expected %d issues across pages, got %d", giteaPageSize+1, len(issues))
+	}
+}
+
+func TestOpenPullRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/group%2Fregistry/merge_requests":
+			if r.URL.Query().Get("state") != "opened" || r.URL.Query().Get("labels") != "auto-update" {
+				t.Errorf("merge request query = %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `[{"iid":4,"description":"fp","web_url":"mr4"}]`)
+		case "/api/v1/repos/owner/repo/issues":
+			if r.URL.Query().Get("type") != "pulls" {
+				t.Errorf("gitea query = %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `[{"number":6,"body":"fp","html_url":"pr6"}]`)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	for _, f := range []Forge{
+		NewGitLab(srv.Client(), srv.URL, "secret", "group/registry"),
+		NewGitea(srv.Client(), srv.URL, "secret", "owner/repo"),
+	} {
+		prs, err := f.OpenPullRequests(context.Background(), "auto-update")
+		if err != nil {
+			t.Fatalf("%s: %v", f.Name(), err)
+		}
+		if len(prs) != 1 || prs[0].Body != "fp" || prs[0].URL == "" {
+			t.Errorf("%s: unexpected pull requests %+v", f.Name(), prs)
+		}
+	}
+}
+
+func TestRESTClient_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			http.Error(w, "unavailable", http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, `[]`)
+	}))
+	defer srv.Close()
+
+	g := NewGitLab(srv.Client(), srv.URL, "secret", "1")
+	g.rest.sleep = noSleep
+	if _, err := g.OpenIssues(context.Background(), "auto-update"); err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls.Load())
+	}
+}
+
+func TestRESTClient_ClientErrorsFailFast(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, `{"message":"401 Unauthorized"}`, http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	g := NewGitea(srv.Client(), srv.URL, "bad", "owner/repo")
+	g.rest.sleep = noSleep
+	_, err := g.OpenIssues(context.Background(), "auto-update")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 APIError, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("4xx should not be retried, got %d attempts", calls.Load())
+	}
+}
@@ -0,0 +1,173 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// giteaPageSize is the page size for Gitea list calls. Instances cap it at
+// their MAX_RESPONSE_ITEMS (50 by default).
+const giteaPageSize = 50
+
+// giteaLabelColor is used for labels the updater has to create.
+const giteaLabelColor = "#ededed"
+
+// Gitea files issues and pull requests on a Gitea (or Forgejo) repository
+// via the v1 API.
+type Gitea struct {
+	BaseURL string // instance root, e.g. https://gitea.example.com
+	Repo    string // "owner/name"
+
+	rest restClient
+}
+
+// NewGitea returns a client for repo on the instance at baseURL,
+// authenticated with an access token.
+func NewGitea(httpClient *http.Client, baseURL, token, repo string) *Gitea {
+	return &Gitea{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Repo:    repo,
+		rest: newRESTClient(httpClient, func(r *http.Request) {
+			r.Header.Set("Authorization", "token "+token)
+		}),
+	}
+}
+
+// Name implements Forge.
+func (g *Gitea) Name() string { return "Gitea" }
+
+type giteaIssue struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+}
+
+func (is giteaIssue) issue() Issue {
+	return Issue{Number: is.Number, Title: is.Title, Body: is.Body, URL: is.HTMLURL}
+}
+
+// OpenIssues implements Forge, paging until a short page.
+func (g *Gitea) OpenIssues(ctx context.Context, label string) ([]Issue, error) {
+	return g.openIssues(ctx, "issues", label)
+}
+
+// OpenPullRequests implements Forge. Gitea lists pull requests through
+// the issues endpoint too, filtered by type.
+func (g *Gitea) OpenPullRequests(ctx context.Context, label string) ([]PullRequest, error) {
+	found, err := g.openIssues(ctx, "pulls", label)
+	if err != nil {
+		return nil, err
+	}
+	prs := make([]PullRequest, len(found))
+	for i, is := range found {
+		prs[i] = PullRequest{Number: is.Number, Body: is.Body, URL: is.URL}
+	}
+	return prs, nil
+}
+
+// openIssues lists open issues of kind ("issues" or "pulls") carrying
+// label, paging until a short page.
+func (g *Gitea) openIssues(ctx context.Context, kind, label string) ([]Issue, error) {
+	var issues []Issue
+	for page := 1; ; page++ {
+		q := url.Values{"state": {"open"}, "type": {kind}, "labels": {label}, "limit": {strconv.Itoa(giteaPageSize)}, "page": {strconv.Itoa(page)}}
+		var batch []giteaIssue
+		if _, err := g.rest.do(ctx, http.MethodGet, g.repoURL("/issues")+"?"+q.Encode(), nil, &batch); err != nil {
+			return nil, err
+		}
+		for _, is := range batch {
+			issues = append(issues, is.issue())
+		}
+		if len(batch) < giteaPageSize {
+			return issues, nil
+		}
+	}
+}
+
+// CreateIssue implements Forge. Gitea takes label IDs, so names are resolved
+// (and created if missing) first.
+func (g *Gitea) CreateIssue(ctx context.Context, title, body string, labels []string) (*Issue, error) {
+	ids, err := g.labelIDs(ctx, labels)
+	if err != nil {
+		return nil, err
+	}
+	payload := map[string]any{"title": title, "body": body, "labels": ids}
+	var is giteaIssue
+	if _, err := g.rest.do(ctx, http.MethodPost, g.repoURL("/issues"), payload, &is); err != nil {
+		return nil, err
+	}
+	out := is.issue()
+	return &out, nil
+}
+
+// CreatePullRequest implements Forge. Gitea has no draft flag in its API
+// and treats a "WIP:" title prefix as work in progress instead.
+func (g *Gitea) CreatePullRequest(ctx context.Context, head, base, title, body string, labels []string, draft bool) (*PullRequest, error) {
+	if draft {
+		title = "WIP: " + title
+	}
+	ids, err := g.labelIDs(ctx, labels)
+	if err != nil {
+		return nil, err
+	}
+	payload := map[string]any{"head": head, "base": base, "title": title, "body": body, "labels": ids}
+	var pr struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	if _, err := g.rest.do(ctx, http.MethodPost, g.repoURL("/pulls"), payload, &pr); err != nil {
+		return nil, err
+	}
+	return &PullRequest{Number: pr.Number, URL: pr.HTMLURL}, nil
+}
+
+// labelIDs maps label names to repository label IDs, creating any that
+// don't exist yet.
+func (g *Gitea) labelIDs(ctx context.Context, names []string) ([]int64, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	type label struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	existing := make(map[string]int64)
+	for page := 1; ; page++ {
+		q := url.Values{"limit": {strconv.Itoa(giteaPageSize)}, "page": {strconv.Itoa(page)}}
+		var batch []label
+		if _, err := g.rest.do(ctx, http.MethodGet, g.repoURL("/labels")+"?"+q.Encode(), nil, &batch); err != nil {
+			return nil, fmt.Errorf("listing labels: %w", err)
+		}
+		for _, l := range batch {
+			existing[l.Name] = l.ID
+		}
+		if len(batch) < giteaPageSize {
+			break
+		}
+	}
+
+	ids := make([]int64, 0, len(names))
+	for _, name := range names {
+		id, ok := existing[name]
+		if !ok {
+			var created label
+			payload := map[string]string{"name": name, "color": giteaLabelColor}
+			if _, err := g.rest.do(ctx, http.MethodPost, g.repoURL("/labels"), payload, &created); err != nil {
+				return nil, fmt.Errorf("creating label %q: %w", name, err)
+			}
+			id = created.ID
+			existing[name] = id
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (g *Gitea) repoURL(path string) string {
+	return g.BaseURL + "/api/v1/repos/" + g.Repo + path
+}
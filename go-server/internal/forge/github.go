@@ -0,0 +1,109 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go-server/internal/github"
+)
+
+// GitHub adapts a github.Client to the Forge interface. Open issues are
+// searched once per label and then kept up to date as issues are created,
+// since every duplicate check in a run would otherwise spend a request from
+// the search quota of 30 a minute.
+type GitHub struct {
+	Client *github.Client
+
+	mu   sync.Mutex
+	open map[string][]Issue // by label
+}
+
+// NewGitHub wraps c.
+func NewGitHub(c *github.Client) *GitHub {
+	return &GitHub{Client: c}
+}
+
+// Name implements Forge.
+func (g *GitHub) Name() string { return "GitHub" }
+
+// OpenIssues implements Forge using the issue search API, searching each
+// label once per GitHub value.
+func (g *GitHub) OpenIssues(ctx context.Context, label string) ([]Issue, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if issues, ok := g.open[label]; ok {
+		return append([]Issue(nil), issues...), nil
+	}
+	found, err := g.Client.SearchIssues(ctx, "is:issue state:open label:"+label)
+	if err != nil {
+		return nil, err
+	}
+	issues := make([]Issue, len(found))
+	for i, is := range found {
+		issues[i] = Issue{Number: is.Number, Title: is.Title, Body: is.Body, URL: is.HTMLURL}
+	}
+	if g.open == nil {
+		g.open = make(map[string][]Issue)
+	}
+	g.open[label] = issues
+	return append([]Issue(nil), issues...), nil
+}
+
+// OpenPullRequests implements Forge using the issue search API. Unlike
+// OpenIssues it isn't cached: the updater checks pull requests at most once
+// per host and run.
+func (g *GitHub) OpenPullRequests(ctx context.Context, label string) ([]PullRequest, error) {
+	found, err := g.Client.SearchIssues(ctx, "is:pr state:open label:"+label)
+	if err != nil {
+		return nil, err
+	}
+	prs := make([]PullRequest, len(found))
+	for i, is := range found {
+		prs[i] = PullRequest{Number: is.Number, Body: is.Body, URL: is.HTMLURL}
+	}
+	return prs, nil
+}
+
+// CreateIssue implements Forge. Labels are sent with the issue rather than
+// added afterwards, so each issue costs one request.
+func (g *GitHub) CreateIssue(ctx context.Context, title, body string, labels []string) (*Issue, error) {
+	is, err := g.Client.CreateIssue(ctx, title, body, labels)
+	if err != nil {
+		return nil, err
+	}
+	created := Issue{Number: is.Number, Title: is.Title, Body: is.Body, URL: is.HTMLURL}
+	g.mu.Lock()
+	for _, l := range labels {
+		if issues, ok := g.open[l]; ok {
+			g.open[l] = append(issues, created)
+		}
+	}
+	g.mu.Unlock()
+	return &created, nil
+}
+
+// CreatePullRequest implements Forge. Labels are added after the pull
+// request is opened, since the pulls API doesn't accept them, so the rate
+// limit budget is checked for both requests before opening it. If labelling
+// still fails the opened pull request is returned along with the error.
+func (g *GitHub) CreatePullRequest(ctx context.Context, head, base, title, body string, labels []string, draft bool) (*PullRequest, error) {
+	calls := 1
+	if len(labels) > 0 {
+		calls++
+	}
+	if err := g.Client.Reserve(calls); err != nil {
+		return nil, err
+	}
+	pr, err := g.Client.CreatePullRequest(ctx, github.PullRequestRequest{Title: title, Body: body, Head: head, Base: base, Draft: draft})
+	if err != nil {
+		return nil, err
+	}
+	opened := &PullRequest{Number: pr.Number, URL: pr.HTMLURL}
+	if len(labels) > 0 {
+		if err := g.Client.AddLabels(ctx, pr.Number, labels); err != nil {
+			return opened, fmt.Errorf("labelling pull request #%d: %w", pr.Number, err)
+		}
+	}
+	return opened, nil
+}
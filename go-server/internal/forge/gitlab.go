@@ -0,0 +1,131 @@
+package forge
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultGitLabURL is gitlab.com; self-managed instances set GITLAB_URL.
+const DefaultGitLabURL = "https://gitlab.com"
+
+// GitLab files issues and merge requests on a GitLab project via the v4 API.
+type GitLab struct {
+	BaseURL string // instance root, e.g. https://gitlab.example.com
+	Project string // "group/name" or numeric project ID
+
+	rest restClient
+}
+
+// NewGitLab returns a client for project on the instance at baseURL (empty
+// means gitlab.com), authenticated with a personal or project access token.
+func NewGitLab(httpClient *http.Client, baseURL, token, project string) *GitLab {
+	if baseURL == "" {
+		baseURL = DefaultGitLabURL
+	}
+	return &GitLab{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Project: project,
+		rest: newRESTClient(httpClient, func(r *http.Request) {
+			r.Header.Set("PRIVATE-TOKEN", token)
+		}),
+	}
+}
+
+// Name implements Forge.
+func (g *GitLab) Name() string { return "GitLab" }
+
+type gitlabIssue struct {
+	IID         int    `json:"iid"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	WebURL      string `json:"web_url"`
+}
+
+func (is gitlabIssue) issue() Issue {
+	return Issue{Number: is.IID, Title: is.Title, Body: is.Description, URL: is.WebURL}
+}
+
+// OpenIssues implements Forge, following X-Next-Page pagination.
+func (g *GitLab) OpenIssues(ctx context.Context, label string) ([]Issue, error) {
+	var issues []Issue
+	page := "1"
+	for page != "" {
+		q := url.Values{"state": {"opened"}, "labels": {label}, "per_page": {"100"}, "page": {page}}
+		var batch []gitlabIssue
+		h, err := g.rest.do(ctx, http.MethodGet, g.projectURL("/issues")+"?"+q.Encode(), nil, &batch)
+		if err != nil {
+			return nil, err
+		}
+		for _, is := range batch {
+			issues = append(issues, is.issue())
+		}
+		page = h.Get("X-Next-Page")
+	}
+	return issues, nil
+}
+
+// OpenPullRequests implements Forge by listing open merge requests,
+// following X-Next-Page pagination.
+func (g *GitLab) OpenPullRequests(ctx context.Context, label string) ([]PullRequest, error) {
+	var prs []PullRequest
+	page := "1"
+	for page != "" {
+		q := url.Values{"state": {"opened"}, "labels": {label}, "per_page": {"100"}, "page": {page}}
+		var batch []gitlabIssue
+		h, err := g.rest.do(ctx, http.MethodGet, g.projectURL("/merge_requests")+"?"+q.Encode(), nil, &batch)
+		if err != nil {
+			return nil, err
+		}
+		for _, mr := range batch {
+			prs = append(prs, PullRequest{Number: mr.IID, Body: mr.Description, URL: mr.WebURL})
+		}
+		page = h.Get("X-Next-Page")
+	}
+	return prs, nil
+}
+
+// CreateIssue implements Forge. Missing labels are created by GitLab.
+func (g *GitLab) CreateIssue(ctx context.Context, title, body string, labels []string) (*Issue, error) {
+	payload := map[string]string{"title": title, "description": body, "labels": strings.Join(labels, ",")}
+	var is gitlabIssue
+	if _, err := g.rest.do(ctx, http.MethodPost, g.projectURL("/issues"), payload, &is); err != nil {
+		return nil, err
+	}
+	out := is.issue()
+	return &out, nil
+}
+
+// CreatePullRequest implements Forge by opening a merge request. GitLab
+// marks a merge request as draft by its title prefix.
+func (g *GitLab) CreatePullRequest(ctx context.Context, head, base, title, body string, labels []string, draft bool) (*PullRequest, error) {
+	if draft {
+		title = "Draft: " + title
+	}
+	payload := map[string]string{
+		"source_branch": head,
+		"target_branch": base,
+		"title":         title,
+		"description":   body,
+		"labels":        strings.Join(labels, ","),
+	}
+	var mr struct {
+		IID    int    `json:"iid"`
+		WebURL string `json:"web_url"`
+	}
+	if _, err := g.rest.do(ctx, http.MethodPost, g.projectURL("/merge_requests"), payload, &mr); err != nil {
+		return nil, err
+	}
+	return &PullRequest{Number: mr.IID, URL: mr.WebURL}, nil
+}
+
+// projectURL addresses the project by URL-encoded path or numeric ID.
+func (g *GitLab) projectURL(path string) string {
+	id := g.Project
+	if _, err := strconv.Atoi(id); err != nil {
+		id = url.PathEscape(id)
+	}
+	return g.BaseURL + "/api/v4/projects/" + id + path
+}
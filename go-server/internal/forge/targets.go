@@ -0,0 +1,127 @@
+package forge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go-server/internal/github"
+)
+
+// Target is one repository the updater reports to, listed in the
+// UPDATER_TARGETS file. The token is read from the environment variable
+// named by TokenEnv, so the file itself holds no secrets.
+type Target struct {
+	Forge    string `json:"forge"`            // github (default), gitlab, or gitea
+	Repo     string `json:"repo"`             // owner/name, or a GitLab project path or numeric ID
+	URL      string `json:"url,omitempty"`    // API root for GitHub Enterprise, or the GitLab/Gitea instance
+	TokenEnv string `json:"token_env"`        // environment variable holding the token
+	Remote   string `json:"remote,omitempty"` // git remote (name or URL) of Repo, for pushing pull request branches
+}
+
+// LoadTargets reads a JSON array of targets.
+func LoadTargets(path string) ([]Target, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var targets []Target
+	if err := json.Unmarshal(data, &targets); err != nil {
+		return nil, fmt.Errorf("parse targets %s: %w", path, err)
+	}
+	return targets, nil
+}
+
+// Open builds the forge for t. Its Name includes the repository so log lines
+// from several targets can be told apart.
+func (t Target) Open(httpClient *http.Client) (Forge, error) {
+	if t.Repo == "" {
+		return nil, errors.New("target has no repo")
+	}
+	token := os.Getenv(t.TokenEnv)
+	if token == "" {
+		return nil, fmt.Errorf("%s: token_env %q is unset or empty", t.Repo, t.TokenEnv)
+	}
+	var f Forge
+	switch kind := strings.ToLower(t.Forge); kind {
+	case "", "github":
+		c := github.NewClient(httpClient, token, t.Repo)
+		if t.URL != "" {
+			c.BaseURL = strings.TrimRight(t.URL, "/")
+		}
+		f = NewGitHub(c)
+	case "gitlab":
+		f = NewGitLab(httpClient, t.URL, token, t.Repo)
+	case "gitea":
+		if t.URL == "" {
+			return nil, fmt.Errorf("%s: gitea targets need a url", t.Repo)
+		}
+		f = NewGitea(httpClient, t.URL, token, t.Repo)
+	default:
+		return nil, fmt.Errorf("%s: unknown forge %q (want github, gitlab, or gitea)", t.Repo, kind)
+	}
+	return named{Forge: f, name: f.Name() + " " + t.Repo, remote: t.Remote}, nil
+}
+
+// named overrides a forge's display name and records its git remote.
+type named struct {
+	Forge
+	name   string
+	remote string
+}
+
+// Name implements Forge.
+func (n named) Name() string { return n.name }
+
+// Remote returns the git remote a pull request branch must be pushed to
+// before f can open a pull request from it, or "" if none is configured. A
+// forge from FromEnv is the repository the updater runs in, origin.
+func Remote(f Forge) string {
+	if n, ok := f.(named); ok {
+		return n.remote
+	}
+	return "origin"
+}
+
+// AllFromEnv returns every forge the updater should report to. When
+// UPDATER_TARGETS names a targets file, each target in it is opened;
+// otherwise it falls back to the single forge from FromEnv. The first
+// target's remote defaults to origin; other targets without one get issues
+// but no pull requests. Targets that fail to open are reported in the error
+// while the rest are still returned, so one bad token doesn't stop
+// reporting to the others.
+func AllFromEnv(httpClient *http.Client) ([]Forge, error) {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	path := os.Getenv("UPDATER_TARGETS")
+	if path == "" {
+		f, err := FromEnv(httpClient)
+		if f == nil {
+			return nil, err
+		}
+		return []Forge{f}, err
+	}
+	targets, err := LoadTargets(path)
+	if err != nil {
+		return nil, err
+	}
+	var forges []Forge
+	var errs []error
+	for i, t := range targets {
+		if i == 0 && t.Remote == "" {
+			t.Remote = "origin"
+		}
+		f, err := t.Open(httpClient)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		forges = append(forges, f)
+	}
+	return forges, errors.Join(errs...)
+}
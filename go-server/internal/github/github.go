@@ -0,0 +1,533 @@
+// Package github is a minimal GitHub REST client used by the updater.
+//
+// It covers the handful of endpoints the auto-update workflow needs (issue
+// search, issues, contents, refs, pull requests, labels) and adds retries,
+// Link-header pagination, rate-limit back-off, and budget tracking so callers
+// don't have to hand-roll HTTP requests.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBaseURL is the public GitHub REST API endpoint.
+const DefaultBaseURL = "https://api.github.com"
+
+// maxRateLimitWait caps how long a single request will sleep waiting for a
+// rate-limit window to reset. Longer waits fail fast instead of hanging a cron run.
+const maxRateLimitWait = 2 * time.Minute
+
+// secondaryLimitWait is the first back-off after a secondary rate limit that
+// doesn't say how long to wait; GitHub asks for at least a minute, doubling
+// on each further rejection.
+const secondaryLimitWait = time.Minute
+
+// ErrBudgetExhausted is wrapped by errors from requests skipped because the
+// rate-limit budget would not cover them.
+var ErrBudgetExhausted = errors.New("github: rate-limit budget exhausted")
+
+// Client talks to the GitHub REST API for a single repository.
+type Client struct {
+	HTTP       *http.Client
+	BaseURL    string // API root, defaults to DefaultBaseURL
+	Token      string
+	Repo       string // "owner/name"
+	MaxRetries int    // attempts per request, including the first
+	// MinRemaining is how many requests of each quota the client leaves
+	// unused, so a token shared across an org's automation isn't drained
+	// to zero by one run.
+	MinRemaining int
+	// WriteInterval is the minimum gap between mutating requests. GitHub
+	// recommends a second to stay clear of secondary rate limits.
+	WriteInterval time.Duration
+
+	sleep func(time.Duration) // overridable in tests
+
+	mu        sync.Mutex
+	limits    map[string]RateLimit // by X-RateLimit-Resource
+	lastWrite time.Time
+}
+
+// RateLimit is a quota's state as of the latest response that reported it.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// NewClient returns a client for repo authenticated with token.
+func NewClient(httpClient *http.Client, token, repo string) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Client{
+		HTTP:          httpClient,
+		BaseURL:       DefaultBaseURL,
+		Token:         token,
+		Repo:          repo,
+		MaxRetries:    3,
+		MinRemaining:  10,
+		WriteInterval: time.Second,
+		sleep:         time.Sleep,
+	}
+}
+
+// NewClientFromEnv builds a client from GITHUB_TOKEN and GITHUB_REPO.
+// It returns false if either variable is unset.
+func NewClientFromEnv(httpClient *http.Client) (*Client, bool) {
+	token := os.Getenv("GITHUB_TOKEN")
+	repo := os.Getenv("GITHUB_REPO")
+	if token == "" || repo == "" {
+		return nil, false
+	}
+	return NewClient(httpClient, token, repo), true
+}
+
+// APIError is returned for non-2xx responses.
+type APIError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("github: %s %s: HTTP %d: %s", e.Method, e.URL, e.StatusCode, e.Body)
+}
+
+// Label is a GitHub issue label.
+type Label struct {
+	Name string `json:"name"`
+}
+
+// Issue is the subset of GitHub issue fields the updater uses.
+type Issue struct {
+	Number  int     `json:"number"`
+	Title   string  `json:"title"`
+	Body    string  `json:"body"`
+	State   string  `json:"state"`
+	HTMLURL string  `json:"html_url"`
+	Labels  []Label `json:"labels"`
+}
+
+// IssueUpdate holds the fields to change on an existing issue. Nil fields are left as-is.
+type IssueUpdate struct {
+	Title *string `json:"title,omitempty"`
+	Body  *string `json:"body,omitempty"`
+	State *string `json:"state,omitempty"`
+}
+
+// FileContent is a decoded file from the contents API.
+type FileContent struct {
+	Path    string
+	SHA     string
+	Content []byte
+}
+
+// PutContentsRequest creates or updates a file on a branch.
+type PutContentsRequest struct {
+	Message string
+	Content []byte
+	SHA     string // blob SHA of the file being replaced; empty for new files
+	Branch  string
+}
+
+// PullRequestRequest describes a pull request to open.
+type PullRequestRequest struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Draft bool   `json:"draft,omitempty"`
+}
+
+// PullRequest is the subset of GitHub pull request fields the updater uses.
+type PullRequest struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	State   string `json:"state"`
+}
+
+// SearchIssues runs an issue search scoped to the client's repository and
+// follows pagination until all results are collected. query is appended to
+// "repo:<owner/name>".
+func (c *Client) SearchIssues(ctx context.Context, query string) ([]Issue, error) {
+	q := "repo:" + c.Repo
+	if query != "" {
+		q += " " + query
+	}
+	next := c.BaseURL + "/search/issues?per_page=100&q=" + url.QueryEscape(q)
+
+	var all []Issue
+	for next != "" {
+		var page struct {
+			Items []Issue `json:"items"`
+		}
+		resp, err := c.do(ctx, http.MethodGet, next, nil, &page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Items...)
+		next = nextPageURL(resp.Header.Get("Link"))
+	}
+	return all, nil
+}
+
+// CreateIssue opens a new issue with the given labels.
+func (c *Client) CreateIssue(ctx context.Context, title, body string, labels []string) (*Issue, error) {
+	payload := map[string]any{"title": title, "body": body}
+	if len(labels) > 0 {
+		payload["labels"] = labels
+	}
+	var issue Issue
+	if _, err := c.do(ctx, http.MethodPost, c.repoURL("/issues"), payload, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// UpdateIssue edits an existing issue.
+func (c *Client) UpdateIssue(ctx context.Context, number int, update IssueUpdate) (*Issue, error) {
+	var issue Issue
+	if _, err := c.do(ctx, http.MethodPatch, c.repoURL(fmt.Sprintf("/issues/%d", number)), update, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// AddComment posts a comment on an issue or pull request.
+func (c *Client) AddComment(ctx context.Context, number int, body string) error {
+	_, err := c.do(ctx, http.MethodPost, c.repoURL(fmt.Sprintf("/issues/%d/comments", number)),
+		map[string]string{"body": body}, nil)
+	return err
+}
+
+// AddLabels adds labels to an issue or pull request.
+func (c *Client) AddLabels(ctx context.Context, number int, labels []string) error {
+	_, err := c.do(ctx, http.MethodPost, c.repoURL(fmt.Sprintf("/issues/%d/labels", number)),
+		map[string][]string{"labels": labels}, nil)
+	return err
+}
+
+// GetContents fetches and decodes a file at ref (branch, tag, or SHA).
+// An empty ref uses the repository's default branch.
+func (c *Client) GetContents(ctx context.Context, path, ref string) (*FileContent, error) {
+	u := c.repoURL("/contents/" + strings.TrimPrefix(path, "/"))
+	if ref != "" {
+		u += "?ref=" + url.QueryEscape(ref)
+	}
+	var raw struct {
+		Path     string `json:"path"`
+		SHA      string `json:"sha"`
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	if _, err := c.do(ctx, http.MethodGet, u, nil, &raw); err != nil {
+		return nil, err
+	}
+	content := []byte(raw.Content)
+	if raw.Encoding == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(raw.Content, "\n", ""))
+		if err != nil {
+			return nil, fmt.Errorf("github: decode %s: %w", path, err)
+		}
+		content = decoded
+	}
+	return &FileContent{Path: raw.Path, SHA: raw.SHA, Content: content}, nil
+}
+
+// PutContents creates or updates a file and commits it to req.Branch.
+func (c *Client) PutContents(ctx context.Context, path string, req PutContentsRequest) error {
+	payload := map[string]string{
+		"message": req.Message,
+		"content": base64.StdEncoding.EncodeToString(req.Content),
+	}
+	if req.SHA != "" {
+		payload["sha"] = req.SHA
+	}
+	if req.Branch != "" {
+		payload["branch"] = req.Branch
+	}
+	_, err := c.do(ctx, http.MethodPut, c.repoURL("/contents/"+strings.TrimPrefix(path, "/")), payload, nil)
+	return err
+}
+
+// GetRef returns the commit SHA a ref points to, e.g. "heads/main".
+func (c *Client) GetRef(ctx context.Context, ref string) (string, error) {
+	var out struct {
+		Object struct {
+			SHA string `json:"sha"`
+		} `json:"object"`
+	}
+	if _, err := c.do(ctx, http.MethodGet, c.repoURL("/git/ref/"+ref), nil, &out); err != nil {
+		return "", err
+	}
+	return out.Object.SHA, nil
+}
+
+// CreateRef creates a new ref (e.g. "refs/heads/auto-update/foo") at sha.
+func (c *Client) CreateRef(ctx context.Context, ref, sha string) error {
+	_, err := c.do(ctx, http.MethodPost, c.repoURL("/git/refs"),
+		map[string]string{"ref": ref, "sha": sha}, nil)
+	return err
+}
+
+// CreatePullRequest opens a pull request.
+func (c *Client) CreatePullRequest(ctx context.Context, req PullRequestRequest) (*PullRequest, error) {
+	var pr PullRequest
+	if _, err := c.do(ctx, http.MethodPost, c.repoURL("/pulls"), req, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+func (c *Client) repoURL(path string) string {
+	return c.BaseURL + "/repos/" + c.Repo + path
+}
+
+// RateLimit returns the state of the named quota ("core" or "search") as of
+// the latest response that reported it. ok is false before any has.
+func (c *Client) RateLimit(resource string) (RateLimit, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rl, ok := c.limits[resource]
+	return rl, ok
+}
+
+// Reserve checks that the core quota covers n more requests beyond
+// MinRemaining, so callers can confirm a multi-request operation (open a
+// pull request, then label it) will finish before starting it. When the
+// quota resets within maxRateLimitWait it waits for the reset; otherwise it
+// returns an error wrapping ErrBudgetExhausted. An unknown quota passes.
+func (c *Client) Reserve(n int) error {
+	return c.reserve("core", n)
+}
+
+func (c *Client) reserve(resource string, n int) error {
+	rl, ok := c.RateLimit(resource)
+	if !ok || rl.Remaining-n >= c.MinRemaining {
+		return nil
+	}
+	wait := time.Until(rl.Reset)
+	if wait <= 0 {
+		return nil
+	}
+	if wait > maxRateLimitWait {
+		return fmt.Errorf("%w: %d %s requests needed, %d of %d left with %d held back, resets at %s",
+			ErrBudgetExhausted, n, resource, rl.Remaining, rl.Limit, c.MinRemaining, rl.Reset.UTC().Format(time.RFC3339))
+	}
+	c.sleeper()(wait + time.Second)
+	return nil
+}
+
+// recordRateLimit stores the quota reported by resp's X-RateLimit headers.
+func (c *Client) recordRateLimit(resp *http.Response) {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+	limit, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	resource := resp.Header.Get("X-RateLimit-Resource")
+	if resource == "" {
+		resource = resourceFor(resp.Request.URL.Path)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.limits == nil {
+		c.limits = make(map[string]RateLimit)
+	}
+	c.limits[resource] = RateLimit{Limit: limit, Remaining: remaining, Reset: time.Unix(reset, 0)}
+}
+
+// resourceFor names the quota a request path draws on. Search sits under
+// the API root, which has a path prefix on GitHub Enterprise.
+func resourceFor(path string) string {
+	if strings.Contains(path, "/search/") {
+		return "search"
+	}
+	return "core"
+}
+
+// paceWrite waits until WriteInterval has passed since the previous
+// mutating request. The slot is claimed before sleeping, so concurrent
+// writers queue up rather than firing together.
+func (c *Client) paceWrite() {
+	c.mu.Lock()
+	now := time.Now()
+	wait := c.WriteInterval - now.Sub(c.lastWrite)
+	c.lastWrite = now.Add(max(wait, 0))
+	c.mu.Unlock()
+	if wait > 0 {
+		c.sleeper()(wait)
+	}
+}
+
+func (c *Client) sleeper() func(time.Duration) {
+	if c.sleep == nil {
+		return time.Sleep
+	}
+	return c.sleep
+}
+
+// do sends a request with retries. It first checks the request's quota
+// against MinRemaining and paces mutating requests by WriteInterval. 5xx
+// responses and transport errors are retried with linear back-off for
+// idempotent methods only: a POST or PATCH may have been applied before it
+// failed, and repeating it could open a duplicate issue or pull request.
+// Rate-limited responses (403/429 with an exhausted quota, Retry-After, or a
+// secondary-limit message) were rejected unapplied, so any method waits for
+// the reset window and tries again. On success the
+// response body is decoded into out when out is non-nil.
+func (c *Client) do(ctx context.Context, method, u string, payload, out any) (*http.Response, error) {
+	var body []byte
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("github: marshal %s %s: %w", method, u, err)
+		}
+		body = b
+	}
+
+	attempts := c.MaxRetries
+	if attempts < 1 {
+		attempts = 1
+	}
+	sleep := c.sleeper()
+	retryFailures := idempotent(method)
+
+	if parsed, err := url.Parse(u); err == nil {
+		if err := c.reserve(resourceFor(parsed.Path), 1); err != nil {
+			return nil, fmt.Errorf("github: %s %s: %w", method, u, err)
+		}
+	}
+	if method != http.MethodGet && method != http.MethodHead {
+		c.paceWrite()
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+		if payload != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := c.HTTP.Do(req)
+		if err != nil {
+			lastErr = err
+			if !retryFailures {
+				return nil, err
+			}
+			if attempt < attempts {
+				sleep(time.Duration(attempt) * 2 * time.Second)
+			}
+			continue
+		}
+		c.recordRateLimit(resp)
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			defer resp.Body.Close()
+			if out != nil && resp.StatusCode != http.StatusNoContent {
+				if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+					return resp, fmt.Errorf("github: decode %s %s: %w", method, u, err)
+				}
+			}
+			return resp, nil
+		}
+
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		lastErr = &APIError{StatusCode: resp.StatusCode, Method: method, URL: u, Body: strings.TrimSpace(string(respBody))}
+
+		if wait, limited := rateLimitWait(resp, string(respBody), time.Now(), attempt); limited {
+			if attempt >= attempts || wait > maxRateLimitWait {
+				return nil, lastErr
+			}
+			sleep(wait)
+			continue
+		}
+		if resp.StatusCode >= 500 && retryFailures && attempt < attempts {
+			sleep(time.Duration(attempt) * 2 * time.Second)
+			continue
+		}
+		return nil, lastErr
+	}
+	return nil, lastErr
+}
+
+// idempotent reports whether repeating a request with method has the same
+// effect as sending it once.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// rateLimitWait reports whether resp is a rate-limit rejection and, if so,
+// how long to wait before retrying. It honours Retry-After (secondary limits)
+// and X-RateLimit-Reset when the primary quota is exhausted. A secondary
+// limit without Retry-After, recognised by its message in body, backs off
+// from secondaryLimitWait, doubling with each attempt.
+func rateLimitWait(resp *http.Response, body string, now time.Time, attempt int) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if ra := resp.Header.Get("Retry-After"); ra != "" {
+		if secs, err := strconv.Atoi(ra); err == nil {
+			return time.Duration(secs) * time.Second, true
+		}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			wait := time.Unix(reset, 0).Sub(now)
+			if wait < 0 {
+				wait = 0
+			}
+			return wait + time.Second, true
+		}
+		return time.Minute, true
+	}
+	if lower := strings.ToLower(body); strings.Contains(lower, "secondary rate limit") || strings.Contains(lower, "abuse detection") {
+		return secondaryLimitWait << (attempt - 1), true
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return time.Minute, true
+	}
+	return 0, false
+}
+
+// linkNextRe extracts the rel="next" URL from a Link header.
+var linkNextRe = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// nextPageURL returns the next-page URL from a Link header, or "" if there is none.
+func nextPageURL(link string) string {
+	if m := linkNextRe.FindStringSubmatch(link); m != nil {
+		return m[1]
+	}
+	return ""
+}
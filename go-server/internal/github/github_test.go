@@ -0,0 +1,368 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client pointed at srv that records sleeps instead of sleeping.
+func newTestClient(srv *httptest.Server, slept *[]time.Duration) *Client {
+	c := NewClient(srv.Client(), "test-token", "owner/repo")
+	c.BaseURL = srv.URL
+	c.sleep = func(d time.Duration) {
+		if slept != nil {
+			*slept = append(*slept, d)
+		}
+	}
+	return c
+}
+
+func TestSearchIssues_Pagination(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if r.URL.Query().Get("page") == "" {
+			if q := r.URL.Query().Get("q"); q != "repo:owner/repo state:open" {
+				t.Errorf("q = %q", q)
+			}
+			w.Header().Set("Link", fmt.Sprintf(`<%s/search/issues?page=2>; rel="next", <%s/search/issues?page=2>; rel="last"`, srv.URL, srv.URL))
+			fmt.Fprint(w, `{"items":[{"number":1,"body":"a"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"items":[{"number":2,"body":"b"}]}`)
+	}))
+	defer srv.Close()
+
+	issues, err := newTestClient(srv, nil).SearchIssues(context.Background(), "state:open")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 2 || issues[0].Number != 1 || issues[1].Number != 2 {
+		t.Errorf("expected issues 1 and 2 across pages, got %+v", issues)
+	}
+}
+
+func TestCreateIssue(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repos/owner/repo/issues" {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		var payload struct {
+			Title  string   `json:"title"`
+			Labels []string `json:"labels"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		if payload.Title != "hello" || len(payload.Labels) != 1 || payload.Labels[0] != "auto-update" {
+			t.Errorf("unexpected payload %+v", payload)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"number":7,"html_url":"https://github.com/owner/repo/issues/7"}`)
+	}))
+	defer srv.Close()
+
+	issue, err := newTestClient(srv, nil).CreateIssue(context.Background(), "hello", "body", []string{"auto-update"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.Number != 7 || issue.HTMLURL == "" {
+		t.Errorf("unexpected issue %+v", issue)
+	}
+}
+
+func TestDo_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, `{"object":{"sha":"abc123"}}`)
+	}))
+	defer srv.Close()
+
+	var slept []time.Duration
+	sha, err := newTestClient(srv, &slept).GetRef(context.Background(), "heads/main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sha != "abc123" {
+		t.Errorf("sha = %q, want abc123", sha)
+	}
+	if calls.Load() != 3 || len(slept) != 2 {
+		t.Errorf("expected 3 calls and 2 back-offs, got %d calls, %v", calls.Load(), slept)
+	}
+}
+
+func TestDo_ServerErrorOnPostNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	var slept []time.Duration
+	_, err := newTestClient(srv, &slept).CreateIssue(context.Background(), "hello", "body", nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected 502 APIError, got %v", err)
+	}
+	if calls.Load() != 1 || len(slept) != 0 {
+		t.Errorf("a POST may already have created the issue; expected 1 call and no back-off, got %d calls, %v", calls.Load(), slept)
+	}
+}
+
+func TestDo_ClientErrorNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	_, err := newTestClient(srv, nil).GetContents(context.Background(), "missing.go", "")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 APIError, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected 1 call for 404, got %d", calls.Load())
+	}
+}
+
+func TestDo_RateLimitWaitsForReset(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(5*time.Second).Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var slept []time.Duration
+	if err := newTestClient(srv, &slept).AddLabels(context.Background(), 1, []string{"x"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(slept) != 1 || slept[0] < time.Second || slept[0] > 10*time.Second {
+		t.Errorf("expected one rate-limit wait of a few seconds, got %v", slept)
+	}
+}
+
+func TestDo_RateLimitTooLongFailsFast(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	var slept []time.Duration
+	err := newTestClient(srv, &slept).AddComment(context.Background(), 1, "hi")
+	if err == nil {
+		t.Fatal("expected error when rate-limit reset is too far away")
+	}
+	if len(slept) != 0 {
+		t.Errorf("expected no sleeping, got %v", slept)
+	}
+}
+
+func TestDo_SecondaryRateLimitBacksOff(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			http.Error(w, `{"message":"You have exceeded a secondary rate limit. Please wait a few minutes before you try again."}`, http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var slept []time.Duration
+	if err := newTestClient(srv, &slept).AddLabels(context.Background(), 1, []string{"x"}); err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != 2 || len(slept) != 1 || slept[0] != secondaryLimitWait {
+		t.Errorf("expected one retry after %v, got %d calls, %v", secondaryLimitWait, calls.Load(), slept)
+	}
+}
+
+func TestReserve_BudgetExhausted(t *testing.T) {
+	var calls atomic.Int32
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("X-RateLimit-Reset", reset)
+		if r.URL.Path == "/search/issues" {
+			w.Header().Set("X-RateLimit-Resource", "search")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			fmt.Fprint(w, `{"items":[]}`)
+			return
+		}
+		w.Header().Set("X-RateLimit-Resource", "core")
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "11")
+		fmt.Fprint(w, `{"object":{"sha":"abc123"}}`)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv, nil)
+	ctx := context.Background()
+	if _, err := c.GetRef(ctx, "heads/main"); err != nil {
+		t.Fatal(err)
+	}
+	if rl, ok := c.RateLimit("core"); !ok || rl.Remaining != 11 || rl.Limit != 5000 {
+		t.Errorf("core quota = %+v, %v", rl, ok)
+	}
+	if err := c.Reserve(1); err != nil {
+		t.Errorf("one request fits above MinRemaining: %v", err)
+	}
+	if err := c.Reserve(2); !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("expected ErrBudgetExhausted for two requests, got %v", err)
+	}
+
+	// An exhausted search quota doesn't block core requests, but does block
+	// further searches without calling the API.
+	if _, err := c.SearchIssues(ctx, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetRef(ctx, "heads/main"); err != nil {
+		t.Errorf("core request blocked by the search quota: %v", err)
+	}
+	before := calls.Load()
+	if _, err := c.SearchIssues(ctx, ""); !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("expected ErrBudgetExhausted for a search, got %v", err)
+	}
+	if calls.Load() != before {
+		t.Error("a request over budget should not reach the API")
+	}
+}
+
+func TestDo_PacesWrites(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{}`)
+	}))
+	defer srv.Close()
+
+	var slept []time.Duration
+	c := newTestClient(srv, &slept)
+	ctx := context.Background()
+	for range 2 {
+		if err := c.AddComment(ctx, 1, "hi"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Reads are not paced.
+	if _, err := c.GetRef(ctx, "heads/main"); err != nil {
+		t.Fatal(err)
+	}
+	if len(slept) != 1 || slept[0] <= 0 || slept[0] > c.WriteInterval {
+		t.Errorf("expected the second write (only) to wait up to %v, got %v", c.WriteInterval, slept)
+	}
+}
+
+func TestGetAndPutContents(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			if r.URL.Query().Get("ref") != "main" {
+				t.Errorf("ref = %q", r.URL.Query().Get("ref"))
+			}
+			enc := base64.StdEncoding.EncodeToString([]byte("package models\n"))
+			fmt.Fprintf(w, `{"path":"data.go","sha":"s1","encoding":"base64","content":%q}`, enc[:8]+"\n"+enc[8:])
+		case http.MethodPut:
+			var payload map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			decoded, _ := base64.StdEncoding.DecodeString(payload["content"])
+			if string(decoded) != "new" || payload["sha"] != "s1" || payload["branch"] != "auto" {
+				t.Errorf("unexpected put payload %v", payload)
+			}
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv, nil)
+	fc, err := c.GetContents(context.Background(), "data.go", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(fc.Content) != "package models\n" || fc.SHA != "s1" {
+		t.Errorf("unexpected content %+v", fc)
+	}
+	if err := c.PutContents(context.Background(), "data.go", PutContentsRequest{
+		Message: "update", Content: []byte("new"), SHA: fc.SHA, Branch: "auto",
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreatePullRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.URL.Path != "/repos/owner/repo/pulls" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		var req PullRequestRequest
+		_ = json.Unmarshal(body, &req)
+		if req.Head != "auto" || req.Base != "main" || !req.Draft {
+			t.Errorf("unexpected request %+v", req)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"number":3,"html_url":"u","state":"open"}`)
+	}))
+	defer srv.Close()
+
+	pr, err := newTestClient(srv, nil).CreatePullRequest(context.Background(), PullRequestRequest{
+		Title: "t", Head: "auto", Base: "main", Draft: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Number != 3 {
+		t.Errorf("pr number = %d, want 3", pr.Number)
+	}
+}
+
+func TestNextPageURL(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+	}{
+		{"", ""},
+		{`<https://api.github.com/x?page=2>; rel="next", <https://api.github.com/x?page=5>; rel="last"`, "https://api.github.com/x?page=2"},
+		{`<https://api.github.com/x?page=1>; rel="prev"`, ""},
+	}
+	for _, tc := range tests {
+		if got := nextPageURL(tc.link); got != tc.want {
+			t.Errorf("nextPageURL(%q) = %q, want %q", tc.link, got, tc.want)
+		}
+	}
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GITHUB_REPO", "owner/repo")
+	if _, ok := NewClientFromEnv(nil); ok {
+		t.Error("expected no client without GITHUB_TOKEN")
+	}
+	t.Setenv("GITHUB_TOKEN", "tok")
+	c, ok := NewClientFromEnv(nil)
+	if !ok || c.Repo != "owner/repo" || c.Token != "tok" {
+		t.Errorf("unexpected client %+v, ok=%v", c, ok)
+	}
+}
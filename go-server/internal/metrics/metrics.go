@@ -0,0 +1,188 @@
+// Package metrics records per-tool call latency and result sizes and serves
+// them in the Prometheus text exposition format on /metrics.
+//
+// It is deliberately minimal — a histogram and two counters per tool, no
+// client library — so operators can spot slow tools (fuzzy lookups on misses,
+// large list_models tables) and size the lookup caches and output budgets.
+// State owned elsewhere, such as open sessions and the rate limiter, is read
+// at scrape time through Collect.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DurationBuckets are the histogram upper bounds in seconds. Most tools run
+// in well under a millisecond; the top buckets catch status page fetches.
+var DurationBuckets = []float64{0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// toolStats holds one tool's series.
+type toolStats struct {
+	buckets     []uint64 // non-cumulative counts per DurationBuckets entry, plus +Inf
+	count       uint64
+	sum         float64
+	resultBytes uint64
+	errors      uint64
+}
+
+// Sample is one value of a collected series. Labels are name, value pairs.
+type Sample struct {
+	Labels []string
+	Value  float64
+}
+
+// collector is a series read at scrape time.
+type collector struct {
+	name, help, kind string
+	collect          func() []Sample
+}
+
+// Recorder collects tool call metrics. It is safe for concurrent use.
+type Recorder struct {
+	mu           sync.Mutex
+	tools        map[string]*toolStats
+	shadowBlocks map[string]uint64 // policy rule → would-be blocks
+	collectors   []collector
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{tools: make(map[string]*toolStats), shadowBlocks: make(map[string]uint64)}
+}
+
+// ObserveShadowBlock records one model that a shadow-mode org policy would
+// have blocked under rule, the policy field it violates.
+func (r *Recorder) ObserveShadowBlock(rule string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.shadowBlocks[rule]++
+}
+
+// Collect adds a series whose samples collect returns on every scrape. kind
+// is its Prometheus type, "gauge" or "counter". Series are written after the
+// tool series, in the order they were first added. Adding a name again
+// replaces its series, so a scrape never repeats a metric.
+func (r *Recorder) Collect(name, help, kind string, collect func() []Sample) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := collector{name, help, kind, collect}
+	for i := range r.collectors {
+		if r.collectors[i].name == name {
+			r.collectors[i] = c
+			return
+		}
+	}
+	r.collectors = append(r.collectors, c)
+}
+
+// Observe records one call of tool that took d and returned resultBytes of
+// output. isError marks calls whose result was a tool error.
+func (r *Recorder) Observe(tool string, d time.Duration, resultBytes int, isError bool) {
+	secs := d.Seconds()
+	i := sort.SearchFloat64s(DurationBuckets, secs)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.tools[tool]
+	if !ok {
+		s = &toolStats{buckets: make([]uint64, len(DurationBuckets)+1)}
+		r.tools[tool] = s
+	}
+	s.buckets[i]++
+	s.count++
+	s.sum += secs
+	s.resultBytes += uint64(max(resultBytes, 0))
+	if isError {
+		s.errors++
+	}
+}
+
+// WriteTo writes all series in the Prometheus text format, tools sorted by
+// name so scrapes are stable.
+func (r *Recorder) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.tools))
+	for name := range r.tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	snapshot := make([]toolStats, len(names))
+	for i, name := range names {
+		s := *r.tools[name]
+		s.buckets = append([]uint64(nil), s.buckets...)
+		snapshot[i] = s
+	}
+	rules := make([]string, 0, len(r.shadowBlocks))
+	for rule := range r.shadowBlocks {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	blocks := make([]uint64, len(rules))
+	for i, rule := range rules {
+		blocks[i] = r.shadowBlocks[rule]
+	}
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("# HELP mcp_tool_call_duration_seconds Tool call latency in seconds.\n")
+	b.WriteString("# TYPE mcp_tool_call_duration_seconds histogram\n")
+	for i, name := range names {
+		s := snapshot[i]
+		var cum uint64
+		for j, le := range DurationBuckets {
+			cum += s.buckets[j]
+			fmt.Fprintf(&b, "mcp_tool_call_duration_seconds_bucket{tool=%q,le=%q} %d\n", name, strconv.FormatFloat(le, 'g', -1, 64), cum)
+		}
+		fmt.Fprintf(&b, "mcp_tool_call_duration_seconds_bucket{tool=%q,le=\"+Inf\"} %d\n", name, s.count)
+		fmt.Fprintf(&b, "mcp_tool_call_duration_seconds_sum{tool=%q} %s\n", name, strconv.FormatFloat(s.sum, 'g', -1, 64))
+		fmt.Fprintf(&b, "mcp_tool_call_duration_seconds_count{tool=%q} %d\n", name, s.count)
+	}
+	b.WriteString("# HELP mcp_tool_result_bytes_total Bytes of tool output returned, after output budgets.\n")
+	b.WriteString("# TYPE mcp_tool_result_bytes_total counter\n")
+	for i, name := range names {
+		fmt.Fprintf(&b, "mcp_tool_result_bytes_total{tool=%q} %d\n", name, snapshot[i].resultBytes)
+	}
+	b.WriteString("# HELP mcp_tool_errors_total Tool calls that returned a tool error.\n")
+	b.WriteString("# TYPE mcp_tool_errors_total counter\n")
+	for i, name := range names {
+		fmt.Fprintf(&b, "mcp_tool_errors_total{tool=%q} %d\n", name, snapshot[i].errors)
+	}
+	b.WriteString("# HELP mcp_policy_shadow_blocks_total Models in tool results that a shadow-mode org policy would have blocked, by policy rule.\n")
+	b.WriteString("# TYPE mcp_policy_shadow_blocks_total counter\n")
+	for i, rule := range rules {
+		fmt.Fprintf(&b, "mcp_policy_shadow_blocks_total{rule=%q} %d\n", rule, blocks[i])
+	}
+	for _, c := range collectors {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", c.name, c.help, c.name, c.kind)
+		for _, s := range c.collect() {
+			b.WriteString(c.name)
+			if len(s.Labels) > 0 {
+				pairs := make([]string, 0, len(s.Labels)/2)
+				for i := 0; i+1 < len(s.Labels); i += 2 {
+					pairs = append(pairs, fmt.Sprintf("%s=%q", s.Labels[i], s.Labels[i+1]))
+				}
+				b.WriteString("{" + strings.Join(pairs, ",") + "}")
+			}
+			b.WriteString(" " + strconv.FormatFloat(s.Value, 'g', -1, 64) + "\n")
+		}
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (r *Recorder) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = r.WriteTo(w)
+	})
+}
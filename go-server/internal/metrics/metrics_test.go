@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorderHistogram(t *testing.T) {
+	r := NewRecorder()
+	r.Observe("search_models", 300*time.Microsecond, 100, false)
+	r.Observe("search_models", 20*time.Millisecond, 50, true)
+	r.Observe("list_models", 10*time.Second, 8192, false)
+
+	var b strings.Builder
+	if _, err := r.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		`mcp_tool_call_duration_seconds_bucket{tool="search_models",le="0.00025"} 0`,
+		`mcp_tool_call_duration_seconds_bucket{tool="search_models",le="0.0005"} 1`,
+		`mcp_tool_call_duration_seconds_bucket{tool="search_models",le="0.025"} 2`,
+		`mcp_tool_call_duration_seconds_bucket{tool="search_models",le="+Inf"} 2`,
+		`mcp_tool_call_duration_seconds_count{tool="search_models"} 2`,
+		`mcp_tool_call_duration_seconds_bucket{tool="list_models",le="5"} 0`,
+		`mcp_tool_call_duration_seconds_bucket{tool="list_models",le="+Inf"} 1`,
+		`mcp_tool_result_bytes_total{tool="search_models"} 150`,
+		`mcp_tool_errors_total{tool="search_models"} 1`,
+		`mcp_tool_errors_total{tool="list_models"} 0`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, `tool="list_models"`) > strings.Index(out, `tool="search_models"`) {
+		t.Error("tools should be sorted by name")
+	}
+}
+
+func TestRecorderBucketBoundaryIsInclusive(t *testing.T) {
+	r := NewRecorder()
+	r.Observe("x", time.Millisecond, 0, false)
+	var b strings.Builder
+	r.WriteTo(&b)
+	if !strings.Contains(b.String(), `mcp_tool_call_duration_seconds_bucket{tool="x",le="0.001"} 1`) {
+		t.Errorf("a 1ms call belongs in the le=0.001 bucket:\n%s", b.String())
+	}
+}
+
+func TestHandler(t *testing.T) {
+	r := NewRecorder()
+	r.Observe("get_model_info", time.Millisecond, 10, false)
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "# TYPE mcp_tool_call_duration_seconds histogram") {
+		t.Errorf("missing TYPE line:\n%s", rec.Body.String())
+	}
+}
+
+func TestShadowBlocks(t *testing.T) {
+	r := NewRecorder()
+	r.ObserveShadowBlock("banned_models")
+	r.ObserveShadowBlock("banned_models")
+	r.ObserveShadowBlock("allowed_providers")
+	var b strings.Builder
+	r.WriteTo(&b)
+	for _, want := range []string{
+		"# TYPE mcp_policy_shadow_blocks_total counter",
+		`mcp_policy_shadow_blocks_total{rule="allowed_providers"} 1`,
+		`mcp_policy_shadow_blocks_total{rule="banned_models"} 2`,
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("missing %q:\n%s", want, b.String())
+		}
+	}
+}
+
+func TestCollect(t *testing.T) {
+	r := NewRecorder()
+	open := 2
+	r.Collect("mcp_sse_sessions_active", "Open SSE sessions.", "gauge", func() []Sample {
+		return []Sample{{Value: float64(open)}}
+	})
+	r.Collect("mcp_rate_limit_rejections_total", "Rejected requests.", "counter", func() []Sample {
+		return []Sample{{Labels: []string{"reason", "rate_limit"}, Value: 7}}
+	})
+	open = 3
+
+	var b strings.Builder
+	r.WriteTo(&b)
+	out := b.String()
+	for _, want := range []string{
+		"# TYPE mcp_sse_sessions_active gauge\nmcp_sse_sessions_active 3\n",
+		"# TYPE mcp_rate_limit_rejections_total counter\n",
+		`mcp_rate_limit_rejections_total{reason="rate_limit"} 7`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+
+	r.Collect("mcp_sse_sessions_active", "Open SSE sessions.", "gauge", func() []Sample {
+		return []Sample{{Value: 5}}
+	})
+	b.Reset()
+	r.WriteTo(&b)
+	out = b.String()
+	if n := strings.Count(out, "# TYPE mcp_sse_sessions_active"); n != 1 {
+		t.Errorf("re-adding a series should replace it, got %d copies in:\n%s", n, out)
+	}
+	if !strings.Contains(out, "mcp_sse_sessions_active 5\n") {
+		t.Errorf("expected the replacement's samples, got:\n%s", out)
+	}
+}
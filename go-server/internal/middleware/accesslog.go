@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultRedactedParams are query parameters whose values are never logged.
+// AccessLogConfig.Redact adds to them.
+var DefaultRedactedParams = []string{"token", "access_token", "api_key", "apikey", "key", "password", "secret", "signature", "sig", "code", "auth"}
+
+// sessionHeader is the streamable HTTP transport's session header; the SSE
+// transport passes the session in the sessionid query parameter instead.
+const sessionHeader = "Mcp-Session-Id"
+
+// AccessLogConfig holds access logging settings.
+type AccessLogConfig struct {
+	// Fraction of requests logged, from 0 to 1. Server errors (5xx) are
+	// always logged.
+	SampleRate float64
+	// Extra query parameter names whose values are replaced with
+	// "[REDACTED]", on top of DefaultRedactedParams. Case-insensitive.
+	Redact []string
+}
+
+// AccessLogger logs one structured line per sampled request: method, path,
+// redacted query, status, duration, client IP, request ID, and session.
+// Session IDs are logged as a short hash, so lines from one session can be
+// grouped without the log exposing a usable session credential.
+type AccessLogger struct {
+	logger *slog.Logger
+	rate   float64
+	redact map[string]bool
+	sample func() float64 // returns [0, 1); replaced in tests
+}
+
+// NewAccessLogger returns an AccessLogger writing to logger.
+func NewAccessLogger(logger *slog.Logger, cfg AccessLogConfig) *AccessLogger {
+	a := &AccessLogger{logger: logger, rate: cfg.SampleRate, redact: make(map[string]bool), sample: rand.Float64}
+	for _, list := range [][]string{DefaultRedactedParams, cfg.Redact} {
+		for _, p := range list {
+			a.redact[strings.ToLower(p)] = true
+		}
+	}
+	return a
+}
+
+// Wrap logs requests to next. The sampling decision is made before the
+// request runs, so an unsampled request costs one random number and a
+// status-recording writer. Long-lived SSE streams are logged when they
+// close, with their full duration.
+func (a *AccessLogger) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sampled := a.rate >= 1 || a.rate > 0 && a.sample() < a.rate
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		if !sampled && rec.status < 500 {
+			return
+		}
+
+		session := r.Header.Get(sessionHeader)
+		if session == "" {
+			session = rec.Header().Get(sessionHeader)
+		}
+		if session == "" {
+			session = r.URL.Query().Get("sessionid")
+		}
+		attrs := []slog.Attr{
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", rec.status),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.Int64("bytes", rec.bytes),
+			slog.String("ip", extractIP(r)),
+		}
+		if q := a.redactQuery(r.URL.RawQuery); q != "" {
+			attrs = append(attrs, slog.String("query", q))
+		}
+		if id := RequestIDFrom(r.Context()); id != "" {
+			attrs = append(attrs, slog.String("request_id", id))
+		}
+		if session != "" {
+			attrs = append(attrs, slog.String("session", hashSession(session)))
+		}
+		a.logger.LogAttrs(r.Context(), slog.LevelInfo, "http request", attrs...)
+	})
+}
+
+// redactQuery re-encodes raw with sensitive values replaced. The session ID
+// is dropped, since it is logged hashed on its own.
+func (a *AccessLogger) redactQuery(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	q, err := url.ParseQuery(raw)
+	if err != nil {
+		return "[unparseable]"
+	}
+	for k := range q {
+		switch lk := strings.ToLower(k); {
+		case lk == "sessionid":
+			q.Del(k)
+		case a.redact[lk]:
+			for i := range q[k] {
+				q[k][i] = "[REDACTED]"
+			}
+		}
+	}
+	return q.Encode()
+}
+
+// hashSession returns a short, stable stand-in for a session ID.
+func hashSession(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:6])
+}
+
+// statusRecorder captures the response status and size. It passes Flush
+// through so SSE streams keep working, and Unwrap for
+// http.ResponseController.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	if !s.wroteHeader {
+		s.status, s.wroteHeader = code, true
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	s.wroteHeader = true
+	n, err := s.ResponseWriter.Write(b)
+	s.bytes += int64(n)
+	return n, err
+}
+
+func (s *statusRecorder) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
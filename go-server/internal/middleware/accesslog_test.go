@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// accessLines runs requests through an AccessLogger and returns the decoded
+// log lines.
+func accessLines(t *testing.T, cfg AccessLogConfig, sample float64, h http.Handler, reqs ...*http.Request) []map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	a := NewAccessLogger(slog.New(slog.NewJSONHandler(&buf, nil)), cfg)
+	a.sample = func() float64 { return sample }
+	wrapped := RequestID(a.Wrap(h))
+	for _, r := range reqs {
+		wrapped.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	var lines []map[string]any
+	for _, l := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if l == "" {
+			continue
+		}
+		var m map[string]any
+		if err := json.Unmarshal([]byte(l), &m); err != nil {
+			t.Fatalf("log line is not JSON: %q", l)
+		}
+		lines = append(lines, m)
+	}
+	return lines
+}
+
+func TestAccessLogFields(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(sessionHeader, "secret-session")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("hello"))
+	})
+	req := httptest.NewRequest("POST", "/mcp?token=abc&Invite=xyz&limit=5", nil)
+	req.RemoteAddr = "1.2.3.4:1234"
+	lines := accessLines(t, AccessLogConfig{SampleRate: 1, Redact: []string{"invite"}}, 0, h, req)
+	if len(lines) != 1 {
+		t.Fatalf("expected one line, got %d", len(lines))
+	}
+	l := lines[0]
+	if l["method"] != "POST" || l["path"] != "/mcp" || l["status"] != float64(202) || l["bytes"] != float64(5) || l["ip"] != "1.2.3.4" {
+		t.Errorf("unexpected fields: %v", l)
+	}
+	if q := l["query"].(string); strings.Contains(q, "abc") || strings.Contains(q, "xyz") || !strings.Contains(q, "limit=5") {
+		t.Errorf("query should be redacted but keep other params: %q", q)
+	}
+	if s, _ := l["session"].(string); s == "" || s == "secret-session" || s != hashSession("secret-session") {
+		t.Errorf("session should be logged hashed, got %q", s)
+	}
+	if id, _ := l["request_id"].(string); id == "" {
+		t.Error("expected the request ID")
+	}
+	if _, ok := l["duration_ms"]; !ok {
+		t.Error("expected a duration")
+	}
+}
+
+func TestAccessLogSSESessionQuery(t *testing.T) {
+	lines := accessLines(t, AccessLogConfig{SampleRate: 1}, 0, okHandler(), httptest.NewRequest("POST", "/sse?sessionid=abc123", nil))
+	if lines[0]["session"] != hashSession("abc123") {
+		t.Errorf("SSE session should come from the query: %v", lines[0])
+	}
+	if _, ok := lines[0]["query"]; ok {
+		t.Errorf("sessionid should not be logged in the query: %v", lines[0])
+	}
+}
+
+func TestAccessLogSampling(t *testing.T) {
+	fail := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	cfg := AccessLogConfig{SampleRate: 0.25}
+	if lines := accessLines(t, cfg, 0.5, okHandler(), httptest.NewRequest("GET", "/mcp", nil)); len(lines) != 0 {
+		t.Errorf("unsampled request was logged: %v", lines)
+	}
+	if lines := accessLines(t, cfg, 0.1, okHandler(), httptest.NewRequest("GET", "/mcp", nil)); len(lines) != 1 {
+		t.Errorf("sampled request should be logged, got %d lines", len(lines))
+	}
+	if lines := accessLines(t, cfg, 0.5, fail, httptest.NewRequest("GET", "/mcp", nil)); len(lines) != 1 {
+		t.Errorf("server errors should be logged regardless of sampling, got %d lines", len(lines))
+	}
+	if lines := accessLines(t, AccessLogConfig{}, 0, okHandler(), httptest.NewRequest("GET", "/mcp", nil)); len(lines) != 0 {
+		t.Errorf("sample rate 0 should log nothing but errors: %v", lines)
+	}
+}
+
+func TestAccessLogKeepsFlusher(t *testing.T) {
+	var flushable bool
+	h := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, flushable = w.(http.Flusher)
+	})
+	accessLines(t, AccessLogConfig{SampleRate: 1}, 0, h, httptest.NewRequest("GET", "/sse", nil))
+	if !flushable {
+		t.Error("SSE handlers need the wrapped writer to implement http.Flusher")
+	}
+}
@@ -42,11 +42,26 @@ type ipState struct {
 	windowStart time.Time
 }
 
+// Rejection reasons counted in LimiterStats.
+const (
+	RejectServerBusy    = "server_busy"    // MaxTotalConns reached
+	RejectRateLimit     = "rate_limit"     // RequestsPerWindow reached
+	RejectIPConnections = "ip_connections" // MaxConnsPerIP reached
+)
+
+// LimiterStats is a snapshot of a Limiter's state.
+type LimiterStats struct {
+	TrackedIPs  int               // IPs with a live window or open connection
+	Connections int               // requests and streams in flight
+	Rejections  map[string]uint64 // by reason, since start
+}
+
 // Limiter is an in-memory per-IP rate limiter and connection tracker.
 type Limiter struct {
 	mu        sync.Mutex
 	ips       map[string]*ipState
 	totalConn int
+	rejected  map[string]uint64
 	cfg       Config
 	stopCh    chan struct{}
 	stopOnce  sync.Once
@@ -55,9 +70,10 @@ type Limiter struct {
 // NewLimiter creates a new rate limiter with the given config.
 func NewLimiter(cfg Config) *Limiter {
 	l := &Limiter{
-		ips:    make(map[string]*ipState),
-		cfg:    cfg,
-		stopCh: make(chan struct{}),
+		ips:      make(map[string]*ipState),
+		rejected: make(map[string]uint64),
+		cfg:      cfg,
+		stopCh:   make(chan struct{}),
 	}
 	// Periodically clean up stale entries.
 	go l.cleanup()
@@ -91,6 +107,17 @@ func (l *Limiter) Stop() {
 	})
 }
 
+// Stats returns the limiter's current state.
+func (l *Limiter) Stats() LimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s := LimiterStats{TrackedIPs: len(l.ips), Connections: l.totalConn, Rejections: make(map[string]uint64, 3)}
+	for _, reason := range []string{RejectServerBusy, RejectRateLimit, RejectIPConnections} {
+		s.Rejections[reason] = l.rejected[reason]
+	}
+	return s
+}
+
 func extractIP(r *http.Request) string {
 	// Trust X-Forwarded-For from Railway's reverse proxy.
 	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
@@ -127,8 +154,9 @@ func (l *Limiter) Wrap(next http.Handler) http.Handler {
 
 		// Check total connection limit.
 		if l.totalConn >= l.cfg.MaxTotalConns {
+			l.rejected[RejectServerBusy]++
 			l.mu.Unlock()
-			http.Error(w, "server busy", http.StatusServiceUnavailable)
+			Error(w, r, "server busy", http.StatusServiceUnavailable)
 			return
 		}
 
@@ -143,16 +171,18 @@ func (l *Limiter) Wrap(next http.Handler) http.Handler {
 		// Check rate limit.
 		if s.requests >= l.cfg.RequestsPerWindow {
 			retryAfter := l.cfg.Window - now.Sub(s.windowStart)
+			l.rejected[RejectRateLimit]++
 			l.mu.Unlock()
 			w.Header().Set("Retry-After", fmt.Sprintf("%d", int(retryAfter.Seconds())+1))
-			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			Error(w, r, "rate limit exceeded", http.StatusTooManyRequests)
 			return
 		}
 
 		// Check per-IP connection limit.
 		if s.connections >= l.cfg.MaxConnsPerIP {
+			l.rejected[RejectIPConnections]++
 			l.mu.Unlock()
-			http.Error(w, "too many connections", http.StatusTooManyRequests)
+			Error(w, r, "too many connections", http.StatusTooManyRequests)
 			return
 		}
 
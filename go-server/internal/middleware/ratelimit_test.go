@@ -46,6 +46,9 @@ func TestRateLimitExceeded(t *testing.T) {
 	if rr.Header().Get("Retry-After") == "" {
 		t.Fatal("expected Retry-After header")
 	}
+	if s := limiter.Stats(); s.Rejections[RejectRateLimit] != 1 || s.Rejections[RejectServerBusy] != 0 || s.TrackedIPs != 1 || s.Connections != 0 {
+		t.Errorf("Stats() = %+v, want one rate_limit rejection for one idle IP", s)
+	}
 }
 
 func TestDifferentIPsIndependent(t *testing.T) {
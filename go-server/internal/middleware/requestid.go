@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+)
+
+// RequestIDHeader carries the request ID on requests and responses.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen bounds client-supplied IDs so they can't bloat logs.
+const maxRequestIDLen = 64
+
+type requestIDKey struct{}
+
+// RequestID tags every request with an ID: the client's X-Request-ID when it
+// is short and log-safe, otherwise a fresh random one. The ID is echoed in
+// the response header, stored in the request context, and written back to
+// the request header so MCP tool handlers see it in CallToolRequest.Extra.
+// Users quoting the ID from a response let operators find the request in the
+// logs.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		r.Header.Set(RequestIDHeader, id)
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), id)))
+	})
+}
+
+// WithRequestID returns ctx carrying the request ID id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFrom returns the request ID in ctx, or "" if there is none.
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Error replies like http.Error, naming the request ID in the body so users
+// can quote it, and logs the failure under that ID.
+func Error(w http.ResponseWriter, r *http.Request, msg string, code int) {
+	id := RequestIDFrom(r.Context())
+	if id == "" {
+		http.Error(w, msg, code)
+		return
+	}
+	log.Printf("request %s: %s %s: %d %s", id, r.Method, r.URL.Path, code, msg)
+	http.Error(w, msg+" (request "+id+")", code)
+}
+
+// validRequestID accepts IDs of letters, digits, '-', '_', and '.' only, so
+// a client-supplied ID can't inject into log lines.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
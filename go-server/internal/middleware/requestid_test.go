@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestIDGeneratedAndPropagated(t *testing.T) {
+	var seen, header string
+	h := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestIDFrom(r.Context())
+		header = r.Header.Get(RequestIDHeader)
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	id := rec.Header().Get(RequestIDHeader)
+	if len(id) != 16 {
+		t.Fatalf("expected a 16-character generated ID, got %q", id)
+	}
+	if seen != id || header != id {
+		t.Errorf("handler saw context ID %q and header %q, want %q", seen, header, id)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Header().Get(RequestIDHeader) == id {
+		t.Error("each request should get a fresh ID")
+	}
+}
+
+func TestRequestIDHonorsClientID(t *testing.T) {
+	h := RequestID(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	for in, keep := range map[string]bool{
+		"abc-123.x_Y":           true,
+		"has space":             false,
+		"inject\nline":          false,
+		strings.Repeat("a", 64): true,
+		strings.Repeat("a", 65): false,
+	} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(RequestIDHeader, in)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if got := rec.Header().Get(RequestIDHeader); (got == in) != keep {
+			t.Errorf("client ID %q: got %q, keep=%v", in, got, keep)
+		}
+	}
+}
+
+func TestErrorNamesRequestID(t *testing.T) {
+	cfg := Config{RequestsPerWindow: 1, Window: time.Minute, MaxConnsPerIP: 10, MaxTotalConns: 10, MaxBodyBytes: 1024}
+	l := NewLimiter(cfg)
+	defer l.Stop()
+	h := RequestID(l.Wrap(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(RequestIDHeader, "req-42")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", rec.Code)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, "rate limit exceeded (request req-42)") {
+		t.Errorf("error body should name the request ID, got %q", body)
+	}
+}
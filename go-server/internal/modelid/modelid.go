@@ -0,0 +1,244 @@
+// Package modelid maps vendor- and platform-specific model IDs to the
+// registry's canonical IDs and back. The rules are specified in
+// docs/model-id-canonicalization.md; routers that can't import Go should
+// implement them from there so every consumer converges on the same names.
+package modelid
+
+import (
+	"regexp"
+	"strings"
+
+	"go-server/internal/models"
+)
+
+// Lookup resolves an exact candidate name (a model ID or alias, compared
+// case-insensitively) to a canonical model ID. It must not guess: partial
+// matches would make canonicalization depend on what else is in the registry.
+type Lookup func(name string) (id string, ok bool)
+
+// Step is one rewrite rule that fired while canonicalizing, with the name it
+// produced.
+type Step struct {
+	Rule   string `json:"rule"`
+	Result string `json:"result"`
+}
+
+// Result is the outcome of canonicalizing one input.
+type Result struct {
+	Input string `json:"input"`
+	ID    string `json:"id,omitempty"` // canonical registry ID; empty when not found
+	Found bool   `json:"found"`
+	Steps []Step `json:"steps,omitempty"`
+}
+
+var (
+	routerVariantRe = regexp.MustCompile(`:(?:free|beta|thinking|online|nitro|floor|extended|exacto)$`)
+	vertexVersionRe = regexp.MustCompile(`@[a-z0-9.-]+$`)
+	bedrockRegionRe = regexp.MustCompile(`^(?:us|eu|apac|au|jp|ca|us-gov|global)\.`)
+	bedrockVerRe    = regexp.MustCompile(`(?:-v\d+)?:\d+$|-v\d+$`)
+	bedrockVendorRe = regexp.MustCompile(`^(anthropic|amazon|meta|mistral|cohere|ai21|deepseek|openai|moonshot|minimax)\.`)
+	dateSuffixRe    = regexp.MustCompile(`-(?:\d{4}-\d{2}-\d{2}|\d{8})$`)
+)
+
+// rule rewrites a name, reporting false when it doesn't apply. Rules run in
+// order and each sees the previous rule's output, so a Bedrock ID such as
+// "us.anthropic.claude-opus-4-6-v1:0" loses its region, version, and vendor
+// in turn. A repeating rule runs until it stops applying or a name resolves.
+type rule struct {
+	name    string
+	rewrite func(string) (string, bool)
+	repeat  bool
+}
+
+var rules = []rule{
+	{name: "strip router variant suffix", rewrite: replaceRe(routerVariantRe, "")},
+	{name: "strip platform path segment", repeat: true, rewrite: func(s string) (string, bool) {
+		_, rest, ok := strings.Cut(s, "/")
+		return rest, ok
+	}},
+	{name: "strip Vertex AI version", rewrite: replaceRe(vertexVersionRe, "")},
+	{name: "strip Bedrock region", rewrite: replaceRe(bedrockRegionRe, "")},
+	{name: "strip Bedrock version", rewrite: replaceRe(bedrockVerRe, "")},
+	{name: "strip Bedrock vendor", rewrite: replaceRe(bedrockVendorRe, "")},
+	{name: "strip date snapshot", rewrite: replaceRe(dateSuffixRe, "")},
+	{name: "strip -latest", rewrite: func(s string) (string, bool) {
+		t := strings.TrimSuffix(s, "-latest")
+		return t, t != s
+	}},
+}
+
+func replaceRe(re *regexp.Regexp, repl string) func(string) (string, bool) {
+	return func(s string) (string, bool) {
+		t := re.ReplaceAllString(s, repl)
+		return t, t != s
+	}
+}
+
+// Canonicalize resolves id against the built-in registry.
+func Canonicalize(id string) Result {
+	return CanonicalizeWith(id, BuiltinLookup)
+}
+
+// CanonicalizeWith resolves id with lookup. The trimmed, lowercased input is
+// tried first, then the output of each rule that fires, so an ID the
+// registry already knows is never rewritten. Bedrock vendor prefixes are
+// also tried joined to the rest with "/" and "-", since the registry names
+// some vendors' models (amazon-nova-2-lite) with their vendor.
+func CanonicalizeWith(id string, lookup Lookup) Result {
+	res := Result{Input: id}
+	s := strings.ToLower(strings.TrimSpace(id))
+	if s != id {
+		res.Steps = append(res.Steps, Step{"trim and lowercase", s})
+	}
+	if res.resolve(s, lookup) {
+		return res
+	}
+	for _, r := range rules {
+		for {
+			t, ok := r.rewrite(s)
+			if !ok || t == "" {
+				break
+			}
+			prev := s
+			s = t
+			res.Steps = append(res.Steps, Step{r.name, s})
+			if res.resolve(s, lookup) {
+				return res
+			}
+			if r.name == "strip Bedrock vendor" {
+				vendor := bedrockVendorRe.FindStringSubmatch(prev)[1]
+				for _, joined := range []string{vendor + "/" + s, vendor + "-" + s} {
+					if _, ok := lookup(joined); ok {
+						res.Steps = append(res.Steps, Step{"rejoin Bedrock vendor", joined})
+						res.resolve(joined, lookup)
+						return res
+					}
+				}
+			}
+			if !r.repeat {
+				break
+			}
+		}
+	}
+	return res
+}
+
+func (res *Result) resolve(name string, lookup Lookup) bool {
+	id, ok := lookup(name)
+	if !ok {
+		return false
+	}
+	if id != name {
+		res.Steps = append(res.Steps, Step{"resolve alias", id})
+	}
+	res.ID, res.Found = id, true
+	return true
+}
+
+// BuiltinLookup resolves name against the models and aliases in use.
+func BuiltinLookup(name string) (string, bool) {
+	ms, _ := models.Snapshot()
+	return LookupIn(ms)(name)
+}
+
+// LookupIn returns a Lookup over ms, following the aliases in use only to
+// models present in ms.
+func LookupIn(ms map[string]models.Model) Lookup {
+	_, aliases := models.Snapshot()
+	return func(name string) (string, bool) {
+		if _, ok := ms[name]; ok {
+			return name, true
+		}
+		if target, ok := aliases[name]; ok {
+			if _, ok := ms[target]; ok {
+				return target, true
+			}
+		}
+		for id := range ms {
+			if strings.EqualFold(id, name) {
+				return id, true
+			}
+		}
+		for alias, target := range aliases {
+			if _, ok := ms[target]; ok && strings.EqualFold(alias, name) {
+				return target, true
+			}
+		}
+		return "", false
+	}
+}
+
+// Fold returns the key under which spellings of one model ID compare equal:
+// lowercased, with "_", ".", and spaces turned into "-" and runs of "-"
+// collapsed. "MiniMax-M2.1", "minimax_m2.1", and "minimax-m2-1" all fold to
+// "minimax-m2-1". The key is for comparing IDs only, never for display.
+func Fold(id string) string {
+	var b strings.Builder
+	b.Grow(len(id))
+	var prev rune
+	for _, c := range strings.ToLower(strings.TrimSpace(id)) {
+		switch c {
+		case '_', '.', ' ':
+			c = '-'
+		}
+		if c == '-' && prev == '-' {
+			continue
+		}
+		b.WriteRune(c)
+		prev = c
+	}
+	return b.String()
+}
+
+// Platforms lists the platforms PlatformID can produce IDs for.
+var Platforms = []string{"openrouter", "litellm"}
+
+// litellmRoutes is LiteLLM's provider route per registry provider. Providers
+// LiteLLM reaches only through a cloud platform with versioned IDs (Bedrock,
+// Azure) are omitted, since their IDs can't be derived from the registry's.
+var litellmRoutes = map[string]string{
+	"OpenAI":     "openai",
+	"Anthropic":  "anthropic",
+	"Google":     "gemini",
+	"xAI":        "xai",
+	"Mistral":    "mistral",
+	"DeepSeek":   "deepseek",
+	"Perplexity": "perplexity",
+	"Cohere":     "cohere_chat",
+	"Moonshot":   "moonshot",
+	"NVIDIA":     "nvidia_nim",
+	"Meta":       "meta_llama",
+}
+
+var claudeVersionRe = regexp.MustCompile(`-(\d+)-(\d+)$`)
+
+// PlatformID returns m's ID on platform, the reverse of Canonicalize. It
+// reports false when the platform doesn't serve the provider or its ID
+// can't be derived from the registry's.
+func PlatformID(m models.Model, platform string) (string, bool) {
+	switch strings.ToLower(platform) {
+	case "openrouter":
+		prefixes := models.ProviderPrefixes(m.Provider)
+		if len(prefixes) == 0 {
+			return "", false
+		}
+		id := m.ID
+		switch {
+		case strings.Contains(id, "/"):
+			// Already namespaced, as OpenRouter lists it.
+			return id, true
+		case m.Provider == "Anthropic":
+			// OpenRouter drops the snapshot date and dots the version:
+			// claude-sonnet-4-5-20250929 is anthropic/claude-sonnet-4.5.
+			id = claudeVersionRe.ReplaceAllString(dateSuffixRe.ReplaceAllString(id, ""), "-$1.$2")
+		}
+		return prefixes[0] + "/" + id, true
+	case "litellm":
+		route, ok := litellmRoutes[m.Provider]
+		if !ok {
+			return "", false
+		}
+		return route + "/" + m.ID, true
+	}
+	return "", false
+}
@@ -0,0 +1,96 @@
+package modelid
+
+import (
+	"testing"
+
+	"go-server/internal/models"
+)
+
+func TestCanonicalize(t *testing.T) {
+	for in, want := range map[string]string{
+		"gpt-5.2":                                 "gpt-5.2",
+		"  GPT-5.2 ":                              "gpt-5.2",
+		"openai/gpt-5.2":                          "gpt-5.2",
+		"openai/gpt-5.2:free":                     "gpt-5.2",
+		"anthropic/claude-sonnet-4.5":             "claude-sonnet-4-5-20250929",
+		"openrouter/anthropic/claude-opus-4.6":    "claude-opus-4-6",
+		"us.anthropic.claude-opus-4-6-v1:0":       "claude-opus-4-6",
+		"claude-opus-4-6@20260205":                "claude-opus-4-6",
+		"amazon.nova-2-lite-v1:0":                 "amazon-nova-2-lite",
+		"publishers/google/models/gemini-2.5-pro": "gemini-2.5-pro",
+		"vertex_ai/gemini-2.5-pro":                "gemini-2.5-pro",
+		"gpt-5.2-2025-12-11":                      "gpt-5.2",
+	} {
+		got := Canonicalize(in)
+		if !got.Found || got.ID != want {
+			t.Errorf("Canonicalize(%q) = %q (found=%v, steps %+v), want %q", in, got.ID, got.Found, got.Steps, want)
+		}
+	}
+}
+
+func TestCanonicalizeUnknown(t *testing.T) {
+	got := Canonicalize("bedrock/acme.widget-v1:0")
+	if got.Found || got.ID != "" {
+		t.Errorf("expected no match, got %+v", got)
+	}
+	if len(got.Steps) == 0 {
+		t.Error("expected the rewrites tried to be reported")
+	}
+}
+
+func TestCanonicalizeLeavesKnownIDsAlone(t *testing.T) {
+	for id := range models.Models {
+		if got := Canonicalize(id); got.ID != id || len(got.Steps) != 0 {
+			t.Errorf("Canonicalize(%q) = %q with steps %+v; registry IDs must map to themselves", id, got.ID, got.Steps)
+		}
+	}
+}
+
+func TestPlatformIDRoundTrips(t *testing.T) {
+	for id, m := range models.Models {
+		for _, p := range Platforms {
+			pid, ok := PlatformID(m, p)
+			if !ok {
+				continue
+			}
+			if got := Canonicalize(pid); got.ID != id {
+				t.Errorf("%s on %s is %q, which canonicalizes to %q", id, p, pid, got.ID)
+			}
+		}
+	}
+	if got, _ := PlatformID(models.Models["claude-sonnet-4-5-20250929"], "openrouter"); got != "anthropic/claude-sonnet-4.5" {
+		t.Errorf("OpenRouter ID = %q, want anthropic/claude-sonnet-4.5", got)
+	}
+	if _, ok := PlatformID(models.Models["amazon-nova-2-lite"], "litellm"); ok {
+		t.Error("Bedrock-only models have no derivable LiteLLM ID")
+	}
+}
+
+func TestFold(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"GLM-4.7", "glm-4-7"},
+		{"glm-4.7", "glm-4-7"},
+		{"MiniMax-M2.1", "minimax-m2-1"},
+		{"minimax_m2.1", "minimax-m2-1"},
+		{" Claude Opus 4.6 ", "claude-opus-4-6"},
+		{"claude--opus__4-6", "claude-opus-4-6"},
+		{"nvidia/Nemotron-3-Nano", "nvidia/nemotron-3-nano"},
+	}
+	for _, tt := range tests {
+		if got := Fold(tt.in); got != tt.want {
+			t.Errorf("Fold(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// Fold must keep registry IDs apart, or FindModel and the updater's diff
+// would treat two models as spellings of one.
+func TestFoldKeepsRegistryIDsDistinct(t *testing.T) {
+	seen := make(map[string]string)
+	for id := range models.Models {
+		if other, ok := seen[Fold(id)]; ok {
+			t.Errorf("%s and %s fold to the same key %q", id, other, Fold(id))
+		}
+		seen[Fold(id)] = id
+	}
+}
@@ -0,0 +1,66 @@
+package models
+
+// API is an API surface a model can be called through.
+type API string
+
+const (
+	APIChatCompletions API = "chat/completions"
+	APIResponses       API = "responses"
+	APIRealtime        API = "realtime"
+	APIAssistants      API = "assistants"
+	APIEmbeddings      API = "embeddings"
+	APIImages          API = "images"
+)
+
+// Valid reports whether a is one of the known API values.
+func (a API) Valid() bool {
+	switch a {
+	case APIChatCompletions, APIResponses, APIRealtime, APIAssistants, APIEmbeddings, APIImages:
+		return true
+	}
+	return false
+}
+
+var (
+	chatAndResponses = []API{APIChatCompletions, APIResponses}
+	withAssistants   = []API{APIChatCompletions, APIResponses, APIAssistants}
+	responsesOnly    = []API{APIResponses}
+)
+
+// APIs lists the API surfaces each OpenAI model supports. OpenAI is the only
+// provider whose models differ here: some are served only by the Responses
+// API and reject chat/completions calls. Other providers expose every model
+// through one chat endpoint (see Endpoints).
+var APIs = map[string][]API{
+	"gpt-5.4":             chatAndResponses,
+	"gpt-5.4-pro":         responsesOnly,
+	"gpt-5.3-chat-latest": chatAndResponses,
+	"gpt-5.3-codex":       responsesOnly,
+	"gpt-5.2":             chatAndResponses,
+	"gpt-5.2-pro":         responsesOnly,
+	"gpt-5.2-codex":       responsesOnly,
+	"gpt-5.1":             chatAndResponses,
+	"gpt-5.1-codex":       responsesOnly,
+	"gpt-5.1-codex-mini":  responsesOnly,
+	"gpt-5.1-mini":        chatAndResponses,
+	"gpt-5":               chatAndResponses,
+	"gpt-5-mini":          chatAndResponses,
+	"gpt-5-nano":          chatAndResponses,
+	"gpt-4.1":             withAssistants,
+	"gpt-4.1-mini":        withAssistants,
+	"gpt-4.1-nano":        withAssistants,
+	"gpt-4o":              withAssistants,
+	"gpt-4o-mini":         withAssistants,
+	"o3":                  withAssistants,
+	"o3-mini":             withAssistants,
+	"o4-mini":             withAssistants,
+	"o3-pro":              responsesOnly,
+	"o3-deep-research":    responsesOnly,
+}
+
+// ResponsesOnly reports whether a model can only be called through the
+// Responses API.
+func ResponsesOnly(id string) bool {
+	apis := APIs[id]
+	return len(apis) == 1 && apis[0] == APIResponses
+}
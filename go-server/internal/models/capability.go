@@ -0,0 +1,128 @@
+package models
+
+import "strings"
+
+// Capability is a feature a model may support. It is the one vocabulary for
+// capabilities across the model data, list_models filtering, recommend
+// scoring, and the list_models input schema, so a misspelled capability is
+// rejected instead of quietly matching nothing.
+type Capability string
+
+const (
+	// CapVision is image (and often video or document) input.
+	CapVision Capability = "vision"
+	// CapReasoning is an extended thinking or chain-of-thought mode.
+	CapReasoning Capability = "reasoning"
+	// CapToolUse is function or tool calling.
+	CapToolUse Capability = "tool_use"
+	// CapStructuredOutput is schema-constrained JSON output.
+	CapStructuredOutput Capability = "structured_output"
+	// CapJSONMode is a JSON mode that guarantees valid JSON without
+	// enforcing a schema.
+	CapJSONMode Capability = "json_mode"
+	// CapAudioIn is native audio input.
+	CapAudioIn Capability = "audio_in"
+	// CapAudioOut is native audio output.
+	CapAudioOut Capability = "audio_out"
+	// CapCaching is discounted prompt caching: the model has a recorded
+	// cached-input rate.
+	CapCaching Capability = "caching"
+	// CapBatch is a discounted asynchronous batch API.
+	CapBatch Capability = "batch"
+)
+
+// Capabilities lists every known capability in display order.
+var Capabilities = []Capability{
+	CapVision, CapReasoning, CapToolUse, CapStructuredOutput, CapJSONMode,
+	CapAudioIn, CapAudioOut, CapCaching, CapBatch,
+}
+
+// capabilityAliases maps accepted spellings to their capability.
+var capabilityAliases = map[string]Capability{
+	"thinking":         CapReasoning,
+	"tools":            CapToolUse,
+	"function_calling": CapToolUse,
+	"json":             CapStructuredOutput,
+	"json_schema":      CapStructuredOutput,
+	"json_object":      CapJSONMode,
+	"prompt_caching":   CapCaching,
+	"batch_capable":    CapBatch,
+	"batch_api":        CapBatch,
+}
+
+// ParseCapability resolves a capability name or alias, ignoring case and
+// treating hyphens as underscores. ok is false for unknown names.
+func ParseCapability(s string) (c Capability, ok bool) {
+	name := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), "-", "_")
+	for _, known := range Capabilities {
+		if string(known) == name {
+			return known, true
+		}
+	}
+	c, ok = capabilityAliases[name]
+	return c, ok
+}
+
+// Tracked reports whether the registry records c for each model. Untracked
+// capabilities are part of the vocabulary but no model reports them until
+// their data is added.
+func (c Capability) Tracked() bool {
+	switch c {
+	case CapVision, CapReasoning, CapToolUse, CapStructuredOutput, CapJSONMode, CapCaching, CapBatch:
+		return true
+	}
+	return false
+}
+
+// Has reports whether m supports c. It is false for untracked capabilities.
+func (m Model) Has(c Capability) bool {
+	switch c {
+	case CapVision:
+		return m.Vision
+	case CapReasoning:
+		return m.Reasoning
+	case CapToolUse:
+		return m.ToolCalling
+	case CapStructuredOutput:
+		return m.StructuredOutput
+	case CapJSONMode:
+		return m.JSONMode
+	case CapCaching:
+		return m.PricingCachedInput > 0
+	case CapBatch:
+		return m.BatchAPI
+	}
+	return false
+}
+
+// Capabilities returns the capabilities m supports, in display order.
+func (m Model) Capabilities() []Capability {
+	var out []Capability
+	for _, c := range Capabilities {
+		if m.Has(c) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// TrackedCapabilities returns the capabilities the registry records per
+// model.
+func TrackedCapabilities() []Capability {
+	var out []Capability
+	for _, c := range Capabilities {
+		if c.Tracked() {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// JoinCapabilities renders cs as a comma-separated list.
+func JoinCapabilities(cs []Capability) string {
+	names := make([]string, len(cs))
+	for i, c := range cs {
+		names[i] = string(c)
+	}
+	return strings.Join(names, ", ")
+}
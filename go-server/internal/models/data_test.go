@@ -1,8 +1,13 @@
 package models
 
 import (
+	"bytes"
+	"maps"
 	"regexp"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestAllModelsHaveRequiredFields(t *testing.T) {
@@ -58,6 +63,336 @@ func TestStatusValuesAreValid(t *testing.T) {
 	}
 }
 
+func TestSystemPromptValuesAreValid(t *testing.T) {
+	for key, m := range Models {
+		if !m.SystemPrompt.Valid() {
+			t.Errorf("%s: invalid SystemPrompt %q", key, m.SystemPrompt)
+		}
+	}
+}
+
+func TestSourceURLs(t *testing.T) {
+	for key, m := range Models {
+		if m.DocsURL == "" {
+			t.Errorf("%s: missing DocsURL", key)
+		}
+		for _, u := range []string{m.DocsURL, m.ModelCardURL, m.AnnouncementURL} {
+			if u != "" && !strings.HasPrefix(u, "https://") {
+				t.Errorf("%s: source URL %q must use https", key, u)
+			}
+		}
+	}
+}
+
+func TestBatchAPIProviders(t *testing.T) {
+	batch := map[string]bool{"OpenAI": true, "Anthropic": true, "Google": true, "Mistral": true, "Amazon": true}
+	for key, m := range Models {
+		if m.BatchAPI != batch[m.Provider] {
+			t.Errorf("%s (%s): BatchAPI = %v, want %v", key, m.Provider, m.BatchAPI, batch[m.Provider])
+		}
+	}
+}
+
+func TestBatchPricing(t *testing.T) {
+	m := Models["gpt-5.2"]
+	in, out, ok := m.BatchPricing()
+	if !ok || in != m.PricingInput*BatchDiscount || out != m.PricingOutput*BatchDiscount {
+		t.Errorf("gpt-5.2 BatchPricing() = %v, %v, %v", in, out, ok)
+	}
+	if _, _, ok := Models["deepseek-chat"].BatchPricing(); ok {
+		t.Error("deepseek-chat should have no batch pricing")
+	}
+	// A recorded rate wins; the other side keeps the discount.
+	m = Model{BatchAPI: true, PricingInput: 2, PricingOutput: 8, PricingBatchInput: 0.8}
+	if in, out, ok := m.BatchPricing(); !ok || in != 0.8 || out != 4 || !m.BatchRecorded() {
+		t.Errorf("recorded BatchPricing() = %v, %v, %v", in, out, ok)
+	}
+}
+
+func TestCachedInputPricingFor(t *testing.T) {
+	m := Models["claude-opus-4-6"]
+	if p, ok := m.CachedInputPricingFor(10_000); !ok || p != m.PricingCachedInput {
+		t.Errorf("short prompt: got %v, %v, want %v", p, ok, m.PricingCachedInput)
+	}
+	// Past the long-context breakpoint the input rate doubles, and so does
+	// the cached rate.
+	if p, ok := m.CachedInputPricingFor(300_000); !ok || p != 2*m.PricingCachedInput {
+		t.Errorf("long prompt: got %v, %v, want %v", p, ok, 2*m.PricingCachedInput)
+	}
+	if _, ok := (Model{PricingInput: 1}).CachedInputPricingFor(100); ok {
+		t.Error("a model without a recorded cached rate should report none")
+	}
+}
+
+func TestFormatPrice(t *testing.T) {
+	for p, want := range map[float64]string{
+		0:       "$0.00",
+		2:       "$2.00",
+		0.5:     "$0.50",
+		0.125:   "$0.125",
+		0.005:   "$0.005",
+		0.0375:  "$0.0375",
+		1.0 / 3: "$0.3333",
+	} {
+		if got := FormatPrice(p); got != want {
+			t.Errorf("FormatPrice(%v) = %q, want %q", p, got, want)
+		}
+	}
+}
+
+func TestParamConstraintsReferenceRegistryModels(t *testing.T) {
+	for id, c := range ParamConstraints {
+		if _, ok := Models[id]; !ok {
+			t.Errorf("ParamConstraints has %q, which is not in Models", id)
+		}
+		if len(c.Unsupported)+len(c.Fixed)+len(c.Exclusive)+len(c.Supported) == 0 && c.Note == "" {
+			t.Errorf("%s: empty constraint entry", id)
+		}
+		for _, p := range c.Unsupported {
+			if _, fixed := c.Fixed[p]; fixed {
+				t.Errorf("%s: %s is both unsupported and fixed", id, p)
+			}
+		}
+	}
+}
+
+func TestSpeedsReferenceRegistryModels(t *testing.T) {
+	for id, s := range Speeds {
+		if _, ok := Models[id]; !ok {
+			t.Errorf("Speeds has %q, which is not in Models", id)
+		}
+		if s.OutputTokensPerSec <= 0 || s.TTFTMillis <= 0 {
+			t.Errorf("%s: speed values must be positive, got %+v", id, s)
+		}
+		if s.Source == "" || !regexp.MustCompile(`^\d{4}-\d{2}$`).MatchString(s.MeasuredAt) {
+			t.Errorf("%s: needs a Source and YYYY-MM MeasuredAt, got %+v", id, s)
+		}
+	}
+}
+
+func TestEndpointsReferenceRegistryProviders(t *testing.T) {
+	providers := make(map[string]bool)
+	for _, m := range Models {
+		providers[m.Provider] = true
+	}
+	for name, e := range Endpoints {
+		if !providers[name] {
+			t.Errorf("Endpoints has %q, which is not a provider in Models", name)
+		}
+		if e.BaseURL != "" && !strings.HasPrefix(e.BaseURL, "https://") {
+			t.Errorf("%s: base URL must be https, got %q", name, e.BaseURL)
+		}
+		if e.BaseURL == "" && e.Note == "" {
+			t.Errorf("%s: entries without a base URL need a Note explaining why", name)
+		}
+	}
+}
+
+func TestProvidersCoverRegistry(t *testing.T) {
+	for id, m := range Models {
+		if _, ok := Providers[m.Provider]; !ok {
+			t.Errorf("%s: provider %q has no Providers entry", id, m.Provider)
+		}
+	}
+	owner := make(map[string]string)
+	for key, p := range Providers {
+		if p.Name != key {
+			t.Errorf("Providers[%q].Name = %q, want the key", key, p.Name)
+		}
+		if !strings.HasPrefix(p.DocsURL, "https://") {
+			t.Errorf("%s: docs URL must be https, got %q", key, p.DocsURL)
+		}
+		for _, u := range []string{p.APIBaseURL, p.StatusPageURL} {
+			if u != "" && !strings.HasPrefix(u, "https://") {
+				t.Errorf("%s: URL must be https, got %q", key, u)
+			}
+		}
+		for _, a := range p.Aliases {
+			if a != strings.ToLower(a) {
+				t.Errorf("%s: alias %q must be lowercase", key, a)
+			}
+			if prev, ok := owner[a]; ok {
+				t.Errorf("alias %q belongs to both %s and %s", a, prev, key)
+			}
+			owner[a] = key
+		}
+	}
+}
+
+func TestLookupProvider(t *testing.T) {
+	for in, want := range map[string]string{"Anthropic": "Anthropic", "xai": "xAI", " Kimi ": "Moonshot", "GLM": "Zhipu"} {
+		p, ok := LookupProvider(in)
+		if !ok || p.Name != want {
+			t.Errorf("LookupProvider(%q) = %q, %v; want %q", in, p.Name, ok, want)
+		}
+	}
+	if _, ok := LookupProvider("acme"); ok {
+		t.Error("LookupProvider(\"acme\") should not match")
+	}
+}
+
+func TestProviderDefaults(t *testing.T) {
+	seen := make(map[string]string)
+	for id, m := range Models {
+		if !m.ProviderDefault {
+			continue
+		}
+		if m.Status != "current" {
+			t.Errorf("%s: provider default must be current, got %q", id, m.Status)
+		}
+		if prev, ok := seen[m.Provider]; ok {
+			t.Errorf("%s has two provider defaults: %s and %s", m.Provider, prev, id)
+		}
+		seen[m.Provider] = id
+	}
+}
+
+func TestLongContextPricingReferencesRegistryModels(t *testing.T) {
+	for id, lc := range LongContextPricing {
+		m, ok := Models[id]
+		if !ok {
+			t.Errorf("LongContextPricing has %q, which is not in Models", id)
+			continue
+		}
+		if lc.Input < m.PricingInput || lc.Output < m.PricingOutput {
+			t.Errorf("%s: long-context rate $%.2f/$%.2f is below the base rate", id, lc.Input, lc.Output)
+		}
+		if lc.AboveInputTokens >= m.ContextWindow && lc.Note == "" {
+			t.Errorf("%s: breakpoint %d is beyond the %d context window; add a Note explaining how to reach it", id, lc.AboveInputTokens, m.ContextWindow)
+		}
+	}
+}
+
+func TestAPIsReferenceRegistryModels(t *testing.T) {
+	for id, apis := range APIs {
+		if _, ok := Models[id]; !ok {
+			t.Errorf("APIs has %q, which is not in Models", id)
+		}
+		if len(apis) == 0 {
+			t.Errorf("%s: empty API list", id)
+		}
+		for _, a := range apis {
+			if !a.Valid() {
+				t.Errorf("%s: invalid API %q", id, a)
+			}
+		}
+	}
+	for id, m := range Models {
+		if m.Provider == "OpenAI" && m.Status != "deprecated" {
+			if _, ok := APIs[id]; !ok {
+				t.Errorf("%s: OpenAI models need an APIs entry", id)
+			}
+		}
+	}
+}
+
+func TestMaturityValues(t *testing.T) {
+	for id, m := range Models {
+		if !m.Maturity.Valid() {
+			t.Errorf("%s: invalid Maturity %q", id, m.Maturity)
+		}
+		// Previews and betas announce themselves in their names; make sure
+		// the field agrees so maturity filters don't let one through.
+		name := strings.ToLower(m.ID + " " + m.DisplayName)
+		if (strings.Contains(name, "preview") || strings.Contains(name, "beta")) && m.Maturity == MaturityStable {
+			t.Errorf("%s: named as a preview or beta but Maturity is stable", id)
+		}
+		if m.ProviderDefault && m.Maturity != MaturityStable {
+			t.Errorf("%s: provider default should be a stable release, got %q", id, m.Maturity)
+		}
+	}
+}
+
+func TestMaturityAtLeast(t *testing.T) {
+	tests := []struct {
+		m, min Maturity
+		want   bool
+	}{
+		{MaturityStable, MaturityStable, true},
+		{MaturityPreview, MaturityStable, false},
+		{MaturityPreview, MaturityPreview, true},
+		{MaturityStable, MaturityExperimental, true},
+		{MaturityExperimental, MaturityPreview, false},
+	}
+	for _, tt := range tests {
+		if got := tt.m.AtLeast(tt.min); got != tt.want {
+			t.Errorf("%s.AtLeast(%s) = %v, want %v", tt.m, tt.min, got, tt.want)
+		}
+	}
+}
+
+func TestParseCapability(t *testing.T) {
+	for in, want := range map[string]Capability{
+		"Vision": CapVision, "thinking": CapReasoning, "tool-use": CapToolUse,
+		"function_calling": CapToolUse, "batch_capable": CapBatch, " caching ": CapCaching,
+	} {
+		if got, ok := ParseCapability(in); !ok || got != want {
+			t.Errorf("ParseCapability(%q) = %q, %v; want %q", in, got, ok, want)
+		}
+	}
+	if _, ok := ParseCapability("teleportation"); ok {
+		t.Error("unknown capabilities must not parse")
+	}
+}
+
+func TestModelHasMatchesFields(t *testing.T) {
+	for id, m := range Models {
+		if m.Has(CapVision) != m.Vision || m.Has(CapReasoning) != m.Reasoning || m.Has(CapBatch) != m.BatchAPI ||
+			m.Has(CapToolUse) != m.ToolCalling || m.Has(CapStructuredOutput) != m.StructuredOutput || m.Has(CapJSONMode) != m.JSONMode ||
+			m.Has(CapCaching) != (m.PricingCachedInput > 0) {
+			t.Errorf("%s: Has disagrees with the capability fields", id)
+		}
+		for _, c := range m.Capabilities() {
+			if !c.Tracked() {
+				t.Errorf("%s: reports untracked capability %s", id, c)
+			}
+		}
+	}
+}
+
+func TestAgentGuidanceIsWellFormed(t *testing.T) {
+	for id, m := range Models {
+		for _, g := range m.AgentGuidance {
+			if g == "" || strings.TrimSpace(g) != g || strings.HasSuffix(g, ".") || strings.Contains(g, "\n") {
+				t.Errorf("%s: guidance %q should be one trimmed line without a trailing period", id, g)
+			}
+		}
+	}
+}
+
+func TestReasoningControlsAreConsistent(t *testing.T) {
+	for id, c := range ReasoningControls {
+		// Models whose thinking is off by default (Flash-Lite) may still
+		// have Reasoning false, so only existence is checked here.
+		if _, ok := Models[id]; !ok {
+			t.Errorf("ReasoningControls has %q, which is not in Models", id)
+		}
+		if pc, ok := ParamConstraints[id]; ok && !slices.Contains(pc.Supported, c.Param) {
+			t.Errorf("%s: %s is missing from ParamConstraints Supported %v", id, c.Param, pc.Supported)
+		}
+		if c.Default != "" && !slices.Contains(c.Levels, c.Default) {
+			t.Errorf("%s: default %q is not one of %v", id, c.Default, c.Levels)
+		}
+		if (len(c.Levels) > 0) == (c.MinBudget > 0 || c.MaxBudget > 0) {
+			t.Errorf("%s: a control takes either levels or a budget range", id)
+		}
+		if c.MaxBudget > 0 && c.MinBudget > c.MaxBudget {
+			t.Errorf("%s: budget range %d-%d is inverted", id, c.MinBudget, c.MaxBudget)
+		}
+		if c.Example == "" {
+			t.Errorf("%s: missing example", id)
+		}
+	}
+}
+
+func TestOSeriesUseDeveloperMessages(t *testing.T) {
+	for _, id := range []string{"o3", "o3-pro", "o4-mini", "o3-mini"} {
+		if got := Models[id].SystemPrompt; got != SystemPromptDeveloper {
+			t.Errorf("%s: SystemPrompt = %q, want %q", id, got, SystemPromptDeveloper)
+		}
+	}
+}
+
 func TestPricingIsNonNegative(t *testing.T) {
 	for key, m := range Models {
 		if m.PricingInput < 0 {
@@ -183,6 +518,28 @@ func TestNoDuplicateDisplayNames(t *testing.T) {
 	}
 }
 
+func TestPrefixedAliasesForEveryModel(t *testing.T) {
+	for id, m := range Models {
+		prefixes, ok := providerPrefixes[m.Provider]
+		if !ok {
+			t.Errorf("provider %s has no entry in providerPrefixes", m.Provider)
+			continue
+		}
+		if strings.Contains(id, "/") {
+			continue
+		}
+		for _, p := range prefixes {
+			if target := Aliases[p+"/"+id]; target != id {
+				t.Errorf("alias %s/%s = %q, want %q", p, id, target, id)
+			}
+		}
+	}
+	// Hand-written aliases must not be overwritten by the generated ones.
+	if got := Aliases["moonshot/kimi-k2"]; got != "kimi-k2-0905-preview" {
+		t.Errorf("moonshot/kimi-k2 = %q, want hand-written kimi-k2-0905-preview", got)
+	}
+}
+
 func TestAliasesPointToValidModels(t *testing.T) {
 	for alias, target := range Aliases {
 		if _, ok := Models[target]; !ok {
@@ -191,6 +548,142 @@ func TestAliasesPointToValidModels(t *testing.T) {
 	}
 }
 
+func TestNoAliasConflicts(t *testing.T) {
+	for _, c := range AliasConflicts() {
+		t.Error(c)
+	}
+}
+
+func TestFloatingAliasesTrackNewestModel(t *testing.T) {
+	for _, fa := range floatingAliases {
+		target := Models[Aliases[fa.names[0]]]
+		for id, m := range Models {
+			if m.Status == "current" && fa.pattern.MatchString(id) && m.ReleaseDate > target.ReleaseDate {
+				t.Errorf("%q points at %s (%s) but %s (%s) is newer", fa.names[0], target.ID, target.ReleaseDate, id, m.ReleaseDate)
+			}
+		}
+		for _, name := range fa.names[1:] {
+			if Aliases[name] != target.ID {
+				t.Errorf("%q = %q, want %q like %q", name, Aliases[name], target.ID, fa.names[0])
+			}
+		}
+	}
+}
+
+func TestFloatingAliasRetargetsOnNewRelease(t *testing.T) {
+	ms := maps.Clone(Models)
+	ms["claude-opus-9"] = Model{ID: "claude-opus-9", Provider: "Anthropic", ReleaseDate: "2099-01", Status: "current"}
+	aliases := map[string]string{}
+	addFloatingAliases(ms, aliases)
+
+	if got := aliases["opus"]; got != "claude-opus-9" {
+		t.Errorf("opus = %q, want the newly added claude-opus-9", got)
+	}
+}
+
+func TestUseRederivesAliases(t *testing.T) {
+	savedModels, savedAliases, savedConflicts := Models, Aliases, aliasConflicts
+	t.Cleanup(func() { Models, Aliases, aliasConflicts = savedModels, savedAliases, savedConflicts })
+
+	ms := maps.Clone(savedModels)
+	ms["claude-opus-9"] = Model{ID: "claude-opus-9", Provider: "Anthropic", ReleaseDate: "2099-01", Status: "current"}
+	Use(ms)
+
+	if got := Aliases["opus"]; got != "claude-opus-9" {
+		t.Errorf("opus = %q, want the newly added claude-opus-9", got)
+	}
+	if got := Aliases["anthropic/claude-opus-9"]; got != "claude-opus-9" {
+		t.Errorf("anthropic/claude-opus-9 = %q, want a prefixed alias", got)
+	}
+	if got := savedAliases["opus"]; got == "claude-opus-9" {
+		t.Error("Use must not modify the previous Aliases map")
+	}
+}
+
+func TestSnapshotSeesUse(t *testing.T) {
+	savedModels, savedAliases, savedConflicts := Models, Aliases, aliasConflicts
+	t.Cleanup(func() { Models, Aliases, aliasConflicts = savedModels, savedAliases, savedConflicts })
+
+	before, beforeAliases := Snapshot()
+	ms := maps.Clone(savedModels)
+	delete(ms, "claude-opus-4-6")
+	Use(ms)
+
+	after, afterAliases := Snapshot()
+	if _, ok := after["claude-opus-4-6"]; ok || len(after) != len(savedModels)-1 {
+		t.Errorf("Snapshot after Use has %d models, want the new set", len(after))
+	}
+	if _, ok := afterAliases["anthropic/claude-opus-4-6"]; ok {
+		t.Error("aliases for a removed model should be dropped")
+	}
+	if _, ok := before["claude-opus-4-6"]; !ok || beforeAliases["anthropic/claude-opus-4-6"] == "" {
+		t.Error("an earlier snapshot must keep the data it was taken with")
+	}
+}
+
+func TestModelsFileIsCanonical(t *testing.T) {
+	out, err := Encode(Models)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, modelsJSON) {
+		t.Error("models.json is not in canonical form; re-encode it with models.Encode")
+	}
+}
+
+func TestParseRejectsInvalidData(t *testing.T) {
+	valid := `"gpt-x": {"id": "gpt-x", "display_name": "GPT X", "provider": "OpenAI", "context_window": 1000, "max_output_tokens": 100, "system_prompt": "full", "status": "current", "maturity": "stable"`
+	if _, err := Parse([]byte("{" + valid + "}}")); err != nil {
+		t.Fatalf("valid entry rejected: %v", err)
+	}
+	for name, data := range map[string]string{
+		"empty":         `{}`,
+		"unknown field": `{` + valid + `, "pricing": 1}}`,
+		"key mismatch":  `{"gpt-y": {"id": "gpt-x"` + strings.TrimPrefix(valid, `"gpt-x": {"id": "gpt-x"`) + `}}`,
+		"bad status":    `{` + strings.Replace(valid, `"current"`, `"retired"`, 1) + `}}`,
+		"bad maturity":  `{` + strings.Replace(valid, `"stable"`, `""`, 1) + `}}`,
+		"output > ctx":  `{` + strings.Replace(valid, `"max_output_tokens": 100`, `"max_output_tokens": 2000`, 1) + `}}`,
+		"negative cost": `{` + valid + `, "pricing_input": -1}}`,
+		"cached > list": `{` + valid + `, "pricing_input": 1, "pricing_cached_input": 2}}`,
+		"batch w/o api": `{` + valid + `, "pricing_batch_input": 1}}`,
+		"bad date":      `{` + valid + `, "retirement_date": "2026-06"}}`,
+		"retires early": `{` + valid + `, "deprecation_date": "2026-06-01", "retirement_date": "2026-05-01"}}`,
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestDaysUntil(t *testing.T) {
+	m := Model{RetirementDate: "2026-06-01"}
+	day, ok := m.Retirement()
+	if !ok {
+		t.Fatal("expected a retirement date")
+	}
+	for now, want := range map[string]int{
+		"2026-05-02T23:30:00Z": 30,
+		"2026-06-01T08:00:00Z": 0,
+		"2026-06-03T00:00:00Z": -2,
+	} {
+		at, _ := time.Parse(time.RFC3339, now)
+		if got := DaysUntil(day, at); got != want {
+			t.Errorf("DaysUntil(2026-06-01, %s) = %d, want %d", now, got, want)
+		}
+	}
+	if _, ok := (Model{}).Retirement(); ok {
+		t.Error("a model without a date should report none")
+	}
+}
+
+func TestNewerForAliasPrefersStable(t *testing.T) {
+	stable := Model{ID: "gemini-9-pro", ReleaseDate: "2026-01", Maturity: MaturityStable}
+	preview := Model{ID: "gemini-9-pro-preview", ReleaseDate: "2026-01", Maturity: MaturityPreview}
+	if !newerForAlias(stable, preview) || newerForAlias(preview, stable) {
+		t.Error("expected the stable release to win a same-month tie")
+	}
+}
+
 func TestFormatInt(t *testing.T) {
 	tests := []struct {
 		input int
@@ -231,3 +724,74 @@ func TestEveryProviderHasAtLeastOneCurrentModel(t *testing.T) {
 		}
 	}
 }
+
+func TestMistralModelsAreEUHosted(t *testing.T) {
+	for key, m := range Models {
+		if m.Provider == "Mistral" && !m.EUHosted {
+			t.Errorf("%s: Mistral models are served from EU infrastructure and should set EUHosted", key)
+		}
+	}
+}
+
+func TestPriceRatio(t *testing.T) {
+	if r := (Model{PricingInput: 2, PricingOutput: 8}).PriceRatio(); r != 4 {
+		t.Errorf("PriceRatio() = %v, want 4", r)
+	}
+	if got := FormatRatio((Model{PricingOutput: 8}).PriceRatio()); got != "—" {
+		t.Errorf("unknown input price should format as —, got %q", got)
+	}
+	if got := FormatRatio(7.5); got != "7.5×" {
+		t.Errorf("FormatRatio(7.5) = %q", got)
+	}
+}
+
+func TestTaskShortlistsAreCurrent(t *testing.T) {
+	tasks := make(map[string]bool)
+	for _, sl := range TaskShortlists {
+		if tasks[sl.Task] {
+			t.Errorf("duplicate shortlist task %q", sl.Task)
+		}
+		tasks[sl.Task] = true
+		if sl.Name == "" || sl.Summary == "" || len(sl.Keywords) == 0 || len(sl.Picks) < 3 {
+			t.Errorf("%s: needs a name, summary, keywords, and at least 3 picks", sl.Task)
+		}
+		seen := make(map[string]bool)
+		for _, p := range sl.Picks {
+			m, ok := Models[p.ModelID]
+			switch {
+			case !ok:
+				t.Errorf("%s: pick %q is not in Models", sl.Task, p.ModelID)
+			case m.Status != "current":
+				t.Errorf("%s: pick %q is %s; replace it with a current model", sl.Task, p.ModelID, m.Status)
+			}
+			if seen[p.ModelID] {
+				t.Errorf("%s: %q is picked twice", sl.Task, p.ModelID)
+			}
+			seen[p.ModelID] = true
+			if p.Role == "" || p.Why == "" {
+				t.Errorf("%s: pick %q needs a role and a reason", sl.Task, p.ModelID)
+			}
+		}
+		for _, kw := range sl.Keywords {
+			if kw != strings.ToLower(kw) {
+				t.Errorf("%s: keyword %q must be lowercase", sl.Task, kw)
+			}
+		}
+	}
+}
+
+func TestBlendedPrice(t *testing.T) {
+	m := Model{PricingInput: 1, PricingOutput: 9}
+	if got := m.BlendedPrice(); got != 3 || BlendLabel() != "3:1" {
+		t.Errorf("default 3:1 blend = %g (%s), want 3", got, BlendLabel())
+	}
+	SetBlendRatio(1)
+	defer SetBlendRatio(0)
+	if got := m.BlendedPrice(); got != 5 || BlendLabel() != "1:1" {
+		t.Errorf("1:1 blend = %g (%s), want 5", got, BlendLabel())
+	}
+	SetBlendRatio(-2)
+	if BlendRatio() != DefaultBlendRatio {
+		t.Errorf("a non-positive ratio should restore the default, got %g", BlendRatio())
+	}
+}
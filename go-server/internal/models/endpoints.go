@@ -0,0 +1,35 @@
+package models
+
+// Endpoint describes how to reach a provider's models from the OpenAI SDK.
+// Agents use it to generate client code with the correct base_url instead of
+// pulling in a provider-specific SDK.
+type Endpoint struct {
+	OpenAICompatible bool   `json:"openai_compatible"`
+	BaseURL          string `json:"base_url,omitempty"` // empty when it varies per account or region
+	Note             string `json:"note,omitempty"`
+}
+
+// Endpoints holds API endpoint metadata keyed by provider name, as used in
+// Model.Provider. Providers without an entry are unknown, not incompatible.
+var Endpoints = map[string]Endpoint{
+	"OpenAI": {OpenAICompatible: true, BaseURL: "https://api.openai.com/v1"},
+	"Anthropic": {OpenAICompatible: true, BaseURL: "https://api.anthropic.com/v1/",
+		Note: "Compatibility layer; prompt caching and some thinking options need the native Messages API."},
+	"Google": {OpenAICompatible: true, BaseURL: "https://generativelanguage.googleapis.com/v1beta/openai/",
+		Note: "Gemini API only; Vertex AI uses a per-project endpoint."},
+	"Mistral":    {OpenAICompatible: true, BaseURL: "https://api.mistral.ai/v1"},
+	"xAI":        {OpenAICompatible: true, BaseURL: "https://api.x.ai/v1"},
+	"DeepSeek":   {OpenAICompatible: true, BaseURL: "https://api.deepseek.com"},
+	"Moonshot":   {OpenAICompatible: true, BaseURL: "https://api.moonshot.ai/v1"},
+	"Zhipu":      {OpenAICompatible: true, BaseURL: "https://api.z.ai/api/paas/v4/", Note: "Mainland China accounts use https://open.bigmodel.cn/api/paas/v4/."},
+	"MiniMax":    {OpenAICompatible: true, BaseURL: "https://api.minimax.io/v1"},
+	"Perplexity": {OpenAICompatible: true, BaseURL: "https://api.perplexity.ai"},
+	"Cohere":     {OpenAICompatible: true, BaseURL: "https://api.cohere.ai/compatibility/v1"},
+	"NVIDIA":     {OpenAICompatible: true, BaseURL: "https://integrate.api.nvidia.com/v1"},
+	"Meta":       {OpenAICompatible: true, BaseURL: "https://api.llama.com/compat/v1/"},
+	"Tencent":    {OpenAICompatible: true, BaseURL: "https://api.hunyuan.cloud.tencent.com/v1"},
+	"Microsoft": {OpenAICompatible: true,
+		Note: "Served through Azure AI Foundry; the base URL is your resource's endpoint."},
+	"Amazon": {OpenAICompatible: false,
+		Note: "Use the Bedrock Converse API via the AWS SDK."},
+}
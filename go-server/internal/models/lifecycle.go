@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// DateLayout is the format of DeprecationDate and RetirementDate. Unlike
+// ReleaseDate's YYYY-MM, providers announce these to the day.
+const DateLayout = "2006-01-02"
+
+// Deprecation returns the day m was, or will be, deprecated, and false when
+// the registry has no date for it.
+func (m Model) Deprecation() (time.Time, bool) {
+	return parseDate(m.DeprecationDate)
+}
+
+// Retirement returns the day the provider stops serving m, and false when
+// the registry has no date for it.
+func (m Model) Retirement() (time.Time, bool) {
+	return parseDate(m.RetirementDate)
+}
+
+func parseDate(s string) (time.Time, bool) {
+	if s == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(DateLayout, s)
+	return t, err == nil
+}
+
+// DaysUntil returns the number of calendar days from now's date (in UTC) to
+// day, negative once day has passed.
+func DaysUntil(day, now time.Time) int {
+	y, m, d := now.UTC().Date()
+	return int(day.Sub(time.Date(y, m, d, 0, 0, 0, 0, time.UTC)).Hours() / 24)
+}
@@ -0,0 +1,168 @@
+package models
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"sort"
+	"sync"
+)
+
+// modelsJSON is the built-in registry data. Edit models.json, not Go source,
+// to add or update a model; Encode keeps the file in canonical form.
+//
+//go:embed models.json
+var modelsJSON []byte
+
+// Models contains all AI model entries in the registry, loaded from the
+// embedded models.json unless replaced at startup by Use.
+var Models = mustParse(modelsJSON)
+
+// handAliases is Aliases as written, before floating and prefixed aliases are
+// derived from Models.
+var handAliases = maps.Clone(Aliases)
+
+func mustParse(data []byte) map[string]Model {
+	ms, err := Parse(data)
+	if err != nil {
+		panic("models: embedded models.json: " + err.Error())
+	}
+	return ms
+}
+
+// Parse decodes a registry data file: a JSON object of models keyed by ID,
+// in the format Encode writes. Unknown fields are rejected so typos in a
+// hand-edited file fail loudly, and every entry must pass Validate.
+func Parse(data []byte) (map[string]Model, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var ms map[string]Model
+	if err := dec.Decode(&ms); err != nil {
+		return nil, err
+	}
+	if len(ms) == 0 {
+		return nil, errors.New("no models")
+	}
+	if err := Validate(ms); err != nil {
+		return nil, err
+	}
+	return ms, nil
+}
+
+// LoadFile reads and parses a registry data file, such as a copy of
+// models.json with local edits.
+func LoadFile(path string) (map[string]Model, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ms, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return ms, nil
+}
+
+// Validate checks the fields lookups and rendering depend on and reports
+// every problem found, in ID order.
+func Validate(ms map[string]Model) error {
+	ids := make([]string, 0, len(ms))
+	for id := range ms {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var errs []error
+	for _, id := range ids {
+		m := ms[id]
+		bad := func(format string, args ...any) {
+			errs = append(errs, fmt.Errorf("%s: "+format, append([]any{id}, args...)...))
+		}
+		switch {
+		case m.ID != id:
+			bad("key does not match id %q", m.ID)
+		case m.DisplayName == "" || m.Provider == "":
+			bad("display_name and provider are required")
+		}
+		if m.Status != "current" && m.Status != "legacy" && m.Status != "deprecated" {
+			bad("invalid status %q", m.Status)
+		}
+		if !m.Maturity.Valid() {
+			bad("invalid maturity %q", m.Maturity)
+		}
+		if !m.SystemPrompt.Valid() {
+			bad("invalid system_prompt %q", m.SystemPrompt)
+		}
+		if m.ContextWindow <= 0 || m.MaxOutputTokens <= 0 || m.MaxOutputTokens > m.ContextWindow {
+			bad("need 0 < max_output_tokens (%d) <= context_window (%d)", m.MaxOutputTokens, m.ContextWindow)
+		}
+		if m.PricingInput < 0 || m.PricingOutput < 0 || m.PricingCachedInput < 0 || m.PricingBatchInput < 0 || m.PricingBatchOutput < 0 {
+			bad("pricing must not be negative")
+		}
+		if m.PricingCachedInput > m.PricingInput {
+			bad("pricing_cached_input $%.4f exceeds pricing_input $%.4f", m.PricingCachedInput, m.PricingInput)
+		}
+		if m.BatchRecorded() && !m.BatchAPI {
+			bad("pricing_batch_input and pricing_batch_output need batch_api")
+		}
+		dep, depOK := m.Deprecation()
+		ret, retOK := m.Retirement()
+		if m.DeprecationDate != "" && !depOK {
+			bad("deprecation_date %q is not YYYY-MM-DD", m.DeprecationDate)
+		}
+		if m.RetirementDate != "" && !retOK {
+			bad("retirement_date %q is not YYYY-MM-DD", m.RetirementDate)
+		}
+		if depOK && retOK && ret.Before(dep) {
+			bad("retirement_date %s is before deprecation_date %s", m.RetirementDate, m.DeprecationDate)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Encode writes ms in the canonical models.json form: two-space indent,
+// keys sorted, HTML left unescaped, and a trailing newline. Tools that edit
+// the data file round-trip through Parse and Encode so diffs stay minimal.
+func Encode(ms map[string]Model) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(ms); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// mu guards swapping Models, Aliases, and aliasConflicts in Use.
+var mu sync.RWMutex
+
+// Snapshot returns the models and aliases in use. Use swaps in new maps
+// rather than editing these, so a snapshot stays consistent while the
+// server reloads its data. Callers must not modify them.
+func Snapshot() (map[string]Model, map[string]string) {
+	mu.RLock()
+	defer mu.RUnlock()
+	return Models, Aliases
+}
+
+// Use replaces Models with ms and re-derives floating and prefixed aliases
+// from it. Hand-written aliases for models ms leaves out are dropped. The
+// new aliases are built before the swap, so readers going through Snapshot
+// see the old data or the new, never a mix; code reading Models or Aliases
+// directly must not run while it is called.
+func Use(ms map[string]Model) {
+	aliases := maps.Clone(handAliases)
+	maps.DeleteFunc(aliases, func(_, target string) bool {
+		_, ok := ms[target]
+		return !ok
+	})
+	conflicts := deriveAliases(ms, aliases)
+	mu.Lock()
+	Models, Aliases, aliasConflicts = ms, aliases, conflicts
+	mu.Unlock()
+}
@@ -2,24 +2,182 @@ package models
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 )
 
 // Model represents an AI model entry in the registry.
 type Model struct {
-	ID              string  `json:"id"`
-	DisplayName     string  `json:"display_name"`
-	Provider        string  `json:"provider"`
-	ContextWindow   int     `json:"context_window"`
-	MaxOutputTokens int     `json:"max_output_tokens"`
-	Vision          bool    `json:"vision"`
-	Reasoning       bool    `json:"reasoning"`
-	PricingInput    float64 `json:"pricing_input"`
-	PricingOutput   float64 `json:"pricing_output"`
-	KnowledgeCutoff string  `json:"knowledge_cutoff"`
-	ReleaseDate     string  `json:"release_date"`
-	Status          string  `json:"status"`
-	Notes           string  `json:"notes"`
+	ID               string              `json:"id"`
+	DisplayName      string              `json:"display_name"`
+	Provider         string              `json:"provider"`
+	ContextWindow    int                 `json:"context_window"`
+	MaxOutputTokens  int                 `json:"max_output_tokens"`
+	Vision           bool                `json:"vision"`
+	Reasoning        bool                `json:"reasoning"`
+	ToolCalling      bool                `json:"tool_calling"`
+	StructuredOutput bool                `json:"structured_output"`
+	JSONMode         bool                `json:"json_mode"`
+	EUHosted         bool                `json:"eu_hosted"`
+	SystemPrompt     SystemPromptSupport `json:"system_prompt"`
+	BatchAPI         bool                `json:"batch_api"`
+	PricingInput     float64             `json:"pricing_input"`
+	PricingOutput    float64             `json:"pricing_output"`
+	// Optional per-1M-token rates, 0 when not recorded: input read from the
+	// provider's prompt cache, and batch API jobs when they aren't list
+	// price times BatchDiscount.
+	PricingCachedInput float64  `json:"pricing_cached_input,omitempty"`
+	PricingBatchInput  float64  `json:"pricing_batch_input,omitempty"`
+	PricingBatchOutput float64  `json:"pricing_batch_output,omitempty"`
+	KnowledgeCutoff    string   `json:"knowledge_cutoff"`
+	ReleaseDate        string   `json:"release_date"`
+	Status             string   `json:"status"`
+	DeprecationDate    string   `json:"deprecation_date,omitempty"` // YYYY-MM-DD, empty until announced
+	RetirementDate     string   `json:"retirement_date,omitempty"`  // YYYY-MM-DD the provider stops serving it, empty until announced
+	Maturity           Maturity `json:"maturity"`
+	ProviderDefault    bool     `json:"provider_default,omitempty"`
+	Notes              string   `json:"notes"`
+	AgentGuidance      []string `json:"agent_guidance,omitempty"`
+	DocsURL            string   `json:"docs_url,omitempty"`
+	ModelCardURL       string   `json:"model_card_url,omitempty"`
+	AnnouncementURL    string   `json:"announcement_url,omitempty"`
+}
+
+// SystemPromptSupport describes how a model handles system prompts.
+type SystemPromptSupport string
+
+const (
+	// SystemPromptFull means the model honors a standard system message.
+	SystemPromptFull SystemPromptSupport = "full"
+	// SystemPromptDeveloper means instructions should be sent as a developer
+	// message; system messages are converted or rejected (OpenAI reasoning models).
+	SystemPromptDeveloper SystemPromptSupport = "developer"
+	// SystemPromptLimited means system prompts are accepted but weakly followed
+	// or discouraged; put instructions in the user turn instead.
+	SystemPromptLimited SystemPromptSupport = "limited"
+	// SystemPromptNone means the model does not accept system prompts at all.
+	SystemPromptNone SystemPromptSupport = "none"
+)
+
+// Valid reports whether s is one of the known SystemPromptSupport values.
+func (s SystemPromptSupport) Valid() bool {
+	switch s {
+	case SystemPromptFull, SystemPromptDeveloper, SystemPromptLimited, SystemPromptNone:
+		return true
+	}
+	return false
+}
+
+// Guidance returns a short prompt-construction hint for agents.
+func (s SystemPromptSupport) Guidance() string {
+	switch s {
+	case SystemPromptFull:
+		return "Supports system prompts"
+	case SystemPromptDeveloper:
+		return "Use a developer message instead of a system prompt"
+	case SystemPromptLimited:
+		return "System prompts are discouraged; put instructions in the user message"
+	case SystemPromptNone:
+		return "No system prompt support; put instructions in the user message"
+	}
+	return "Unknown"
+}
+
+// Maturity is how finished a release is, independent of its lifecycle
+// Status: a current model can be a preview, and a stable model can be
+// deprecated.
+type Maturity string
+
+const (
+	// MaturityStable is a generally available release.
+	MaturityStable Maturity = "stable"
+	// MaturityPreview is a preview or beta: usable in production but subject
+	// to change, often with tighter rate limits and shorter notice before
+	// shutdown.
+	MaturityPreview Maturity = "preview"
+	// MaturityExperimental is an experimental release that may change or
+	// disappear without notice.
+	MaturityExperimental Maturity = "experimental"
+)
+
+// Valid reports whether m is one of the known Maturity values.
+func (m Maturity) Valid() bool {
+	switch m {
+	case MaturityStable, MaturityPreview, MaturityExperimental:
+		return true
+	}
+	return false
+}
+
+// rank orders maturities from most to least finished.
+func (m Maturity) rank() int {
+	switch m {
+	case MaturityStable:
+		return 0
+	case MaturityPreview:
+		return 1
+	}
+	return 2
+}
+
+// AtLeast reports whether m is at least as finished as min: a preview is at
+// least experimental, and a stable release is at least anything.
+func (m Maturity) AtLeast(min Maturity) bool {
+	return m.rank() <= min.rank()
+}
+
+// BatchDiscount is the fraction of list price charged for batch API jobs.
+// OpenAI, Anthropic, Google, Mistral, and Amazon Bedrock all discount
+// asynchronous batch requests by 50%.
+const BatchDiscount = 0.5
+
+// BatchPricing returns the per-1M-token input and output prices for batch
+// API jobs: the recorded batch rates, or list price times BatchDiscount
+// where none is recorded. ok is false if the model has no batch API.
+func (m Model) BatchPricing() (input, output float64, ok bool) {
+	if !m.BatchAPI {
+		return 0, 0, false
+	}
+	input, output = m.PricingBatchInput, m.PricingBatchOutput
+	if input == 0 {
+		input = m.PricingInput * BatchDiscount
+	}
+	if output == 0 {
+		output = m.PricingOutput * BatchDiscount
+	}
+	return input, output, true
+}
+
+// BatchRecorded reports whether the model's batch prices are recorded
+// rather than derived from BatchDiscount.
+func (m Model) BatchRecorded() bool {
+	return m.PricingBatchInput > 0 || m.PricingBatchOutput > 0
+}
+
+// CachedInputPricing returns the per-1M-token price of input read from the
+// provider's prompt cache. ok is false if it isn't recorded: discounts vary
+// too much by provider and generation (10% of list price at Anthropic and
+// for GPT-5, 50% for GPT-4o) to assume one.
+func (m Model) CachedInputPricing() (price float64, ok bool) {
+	return m.PricingCachedInput, m.PricingCachedInput > 0
+}
+
+// PriceRatio returns the output price as a multiple of the input price, or 0
+// if the input price is unknown. Reasoning models often run 8-10x.
+func (m Model) PriceRatio() float64 {
+	if m.PricingInput <= 0 {
+		return 0
+	}
+	return m.PricingOutput / m.PricingInput
+}
+
+// FormatRatio renders a PriceRatio for tables, e.g. "8.0×", or "—" if unknown.
+func FormatRatio(r float64) string {
+	if r <= 0 {
+		return "—"
+	}
+	return fmt.Sprintf("%.1f×", r)
 }
 
 // Aliases maps common shorthand model IDs to their canonical registry key.
@@ -104,9 +262,6 @@ var Aliases = map[string]string{
 
 	// ─── Anthropic Aliases ─────────────────────────────────────────
 	// Claude Opus 4.6
-	"claude-opus":        "claude-opus-4-6",
-	"opus":               "claude-opus-4-6",
-	"claude-opus-latest": "claude-opus-4-6",
 	"opus-4-6":           "claude-opus-4-6",
 	"claude-opus-4.6":    "claude-opus-4-6",
 	"claude-4.6-opus":    "claude-opus-4-6",
@@ -116,9 +271,6 @@ var Aliases = map[string]string{
 	"sonnet-4-6":                 "claude-sonnet-4-6",
 	"claude-4.6-sonnet":          "claude-sonnet-4-6",
 	"claude-sonnet-4-6-20260217": "claude-sonnet-4-6",
-	"sonnet":                     "claude-sonnet-4-6",
-	"claude-sonnet":              "claude-sonnet-4-6",
-	"claude-sonnet-latest":       "claude-sonnet-4-6",
 
 	// Claude Sonnet 4.5
 	"claude-sonnet-4-5":  "claude-sonnet-4-5-20250929",
@@ -128,8 +280,6 @@ var Aliases = map[string]string{
 
 	// Claude Haiku 4.5
 	"claude-haiku-4-5":  "claude-haiku-4-5-20251001",
-	"haiku":             "claude-haiku-4-5-20251001",
-	"claude-haiku":      "claude-haiku-4-5-20251001",
 	"haiku-4-5":         "claude-haiku-4-5-20251001",
 	"claude-4.5-haiku":  "claude-haiku-4-5-20251001",
 	"claude-haiku-4.5":  "claude-haiku-4-5-20251001",
@@ -166,47 +316,33 @@ var Aliases = map[string]string{
 	"gemini-3-1-flash":                    "gemini-3.1-flash",
 	"gemini31flash":                       "gemini-3.1-flash",
 	"gemini3.1flash":                      "gemini-3.1-flash",
-	"google/gemini-3.1-flash":             "gemini-3.1-flash",
 	"gemini-3-1-pro-preview":              "gemini-3.1-pro-preview",
 	"gemini31pro":                         "gemini-3.1-pro-preview",
 	"gemini3.1pro":                        "gemini-3.1-pro-preview",
 	"gemini-3.1-pro":                      "gemini-3.1-pro-preview",
-	"google/gemini-3.1-pro-preview":       "gemini-3.1-pro-preview",
-	"models/gemini-3.1-pro-preview":       "gemini-3.1-pro-preview",
 	"gemini-3-1-flash-lite-preview":       "gemini-3.1-flash-lite-preview",
 	"gemini31flashlite":                   "gemini-3.1-flash-lite-preview",
 	"gemini3.1flashlite":                  "gemini-3.1-flash-lite-preview",
 	"gemini-3.1-flash-lite":               "gemini-3.1-flash-lite-preview",
-	"google/gemini-3.1-flash-lite-preview": "gemini-3.1-flash-lite-preview",
 
 	// Gemini 3 series
 	"gemini-3-pro":                  "gemini-3-pro-preview",
 	"gemini3pro":                    "gemini-3-pro-preview",
-	"gemini-pro":                    "gemini-3-pro-preview",
-	"google/gemini-3-pro-preview":   "gemini-3-pro-preview",
-	"models/gemini-3-pro-preview":   "gemini-3-pro-preview",
 	"gemini-3-pro-image":                "gemini-3-pro-image-preview",
 	"gemini3proimage":                   "gemini-3-pro-image-preview",
-	"google/gemini-3-pro-image-preview": "gemini-3-pro-image-preview",
 	"gemini-3-flash":                    "gemini-3-flash-preview",
 	"gemini3flash":                      "gemini-3-flash-preview",
-	"google/gemini-3-flash-preview":     "gemini-3-flash-preview",
-	"models/gemini-3-flash-preview":     "gemini-3-flash-preview",
-	"gemini-flash":                      "gemini-3-flash-preview",
 
 	// Gemini 2.5 series
 	"gemini-2-5-pro":          "gemini-2.5-pro",
 	"gemini25pro":             "gemini-2.5-pro",
 	"gemini2.5pro":            "gemini-2.5-pro",
-	"google/gemini-2.5-pro":   "gemini-2.5-pro",
 	"gemini-2-5-flash":        "gemini-2.5-flash",
 	"gemini25flash":           "gemini-2.5-flash",
 	"gemini2.5flash":          "gemini-2.5-flash",
-	"google/gemini-2.5-flash": "gemini-2.5-flash",
 	"gemini-2-5-flash-lite":        "gemini-2.5-flash-lite",
 	"gemini25flashlite":            "gemini-2.5-flash-lite",
 	"gemini2.5flashlite":           "gemini-2.5-flash-lite",
-	"google/gemini-2.5-flash-lite": "gemini-2.5-flash-lite",
 
 	// Gemini 2.0 series
 	"gemini-2-0-flash-lite": "gemini-2.0-flash-lite",
@@ -246,7 +382,6 @@ var Aliases = map[string]string{
 	"llama3.370b":     "llama-3.3-70b",
 
 	// ─── Mistral Aliases ───────────────────────────────────────────
-	"mistral-large":        "mistral-large-2512",
 	"mistral-large-3":      "mistral-large-2512",
 	"ministral-3b":         "ministral-3b-2512",
 	"ministral-8b":         "ministral-8b-2512",
@@ -325,14 +460,12 @@ var Aliases = map[string]string{
 	"k2.5":                          "kimi-k2.5",
 	"k25":                           "kimi-k2.5",
 	"moonshot-kimi":                 "kimi-k2.5",
-	"moonshot/kimi-k2.5":            "kimi-k2.5",
 	"kimi-k2-think":                 "kimi-k2-thinking",
 	"kimi-thinking":                 "kimi-k2-thinking",
 	"kimi-reasoner":                 "kimi-k2-thinking",
 	"kimi-k2thinking":               "kimi-k2-thinking",
 	"k2-thinking":                   "kimi-k2-thinking",
 	"k2thinking":                    "kimi-k2-thinking",
-	"moonshot/kimi-k2-thinking":     "kimi-k2-thinking",
 	"kimi-k2":                       "kimi-k2-0905-preview",
 	"kimi-k2-preview":               "kimi-k2-0905-preview",
 	"kimi-0905":                     "kimi-k2-0905-preview",
@@ -347,32 +480,26 @@ var Aliases = map[string]string{
 	"glm-5-0":                  "glm-5",
 	"zhipu-glm-5":              "glm-5",
 	"chatglm-5":                "glm-5",
-	"zhipu/glm-5":              "glm-5",
 	"glm-4-7":                  "glm-4.7",
 	"glm47":                    "glm-4.7",
 	"glm4.7":                   "glm-4.7",
 	"zhipu-glm-4.7":            "glm-4.7",
 	"chatglm-4.7":              "glm-4.7",
-	"zhipu/glm-4.7":            "glm-4.7",
 	"glm-4-7-flash":            "glm-4.7-flash",
 	"glm47flash":               "glm-4.7-flash",
 	"glm4.7flash":              "glm-4.7-flash",
-	"zhipu/glm-4.7-flash":      "glm-4.7-flash",
 	"glm-5-code":               "glm-5-code",
 	"glm5code":                 "glm-5-code",
-	"zhipu/glm-5-code":         "glm-5-code",
 	"glm-flashx":               "glm-4.7-flashx",
 	"glm-flash":                "glm-4.7-flashx",
 	"glm-4-7-flashx":           "glm-4.7-flashx",
 	"glm47flashx":              "glm-4.7-flashx",
 	"glm4.7flashx":             "glm-4.7-flashx",
-	"zhipu/glm-4.7-flashx":     "glm-4.7-flashx",
 	"glm-vision":               "glm-4.6v",
 	"glm-v":                    "glm-4.6v",
 	"glm-4-6v":                 "glm-4.6v",
 	"glm46v":                   "glm-4.6v",
 	"glm4.6v":                  "glm-4.6v",
-	"zhipu/glm-4.6v":           "glm-4.6v",
 
 	// ─── NVIDIA Aliases (NEW PROVIDER) ────────────────────────────
 	"nemotron-3-nano":                         "nvidia/nemotron-3-nano-30b-a3b",
@@ -394,31 +521,24 @@ var Aliases = map[string]string{
 	"hunyuan-turbo-s":          "hunyuan-turbos",
 	"hunyuan-turbo":            "hunyuan-turbos",
 	"tencent-hunyuan-turbos":   "hunyuan-turbos",
-	"tencent/hunyuan-turbos":   "hunyuan-turbos",
 	"hunyuan-t-1":              "hunyuan-t1",
 	"tencent-hunyuan-t1":       "hunyuan-t1",
 	"hunyuan-thinking":         "hunyuan-t1",
-	"tencent/hunyuan-t1":       "hunyuan-t1",
 	"hunyuan-a-13b":            "hunyuan-a13b",
 	"hunyuan-13b":              "hunyuan-a13b",
 	"tencent-hunyuan-a13b":     "hunyuan-a13b",
-	"tencent/hunyuan-a13b":     "hunyuan-a13b",
 
 	// ─── Microsoft/Phi Aliases (NEW PROVIDER) ─────────────────────
 	"phi4":                                    "phi-4",
 	"microsoft-phi-4":                         "phi-4",
-	"microsoft/phi-4":                         "phi-4",
 	"phi4multimodal":                          "phi-4-multimodal-instruct",
 	"phi-4-multimodal":                        "phi-4-multimodal-instruct",
 	"phi-4-mm":                                "phi-4-multimodal-instruct",
 	"microsoft/phi-4-multimodal":              "phi-4-multimodal-instruct",
-	"microsoft/phi-4-multimodal-instruct":     "phi-4-multimodal-instruct",
 	"phi4reasoning":                           "phi-4-reasoning",
 	"phi-4-r":                                 "phi-4-reasoning",
-	"microsoft/phi-4-reasoning":               "phi-4-reasoning",
 	"phi4reasoningplus":                       "phi-4-reasoning-plus",
 	"phi-4-rp":                                "phi-4-reasoning-plus",
-	"microsoft/phi-4-reasoning-plus":          "phi-4-reasoning-plus",
 
 	// ─── MiniMax Aliases (NEW PROVIDER) ───────────────────────────
 	"minimax":                "minimax-m2.5",
@@ -452,7 +572,6 @@ var Aliases = map[string]string{
 	"mimo-flash":           "mimo-v2-flash",
 	"mimov2flash":          "mimo-v2-flash",
 	"xiaomi-mimo":          "mimo-v2-flash",
-	"xiaomi/mimo-v2-flash": "mimo-v2-flash",
 
 	// ─── Kuaishou/KAT Aliases (NEW PROVIDER) ──────────────────────
 	"kat":                     "kat-coder-pro",
@@ -460,8 +579,159 @@ var Aliases = map[string]string{
 	"kat-coder":               "kat-coder-pro",
 	"katcoder":                "kat-coder-pro",
 	"katcoderpro":             "kat-coder-pro",
-	"kwaipilot/kat-coder-pro": "kat-coder-pro",
-	"kuaishou/kat-coder-pro":  "kat-coder-pro",
+}
+
+// providerPrefixes lists the "<prefix>/" forms (OpenRouter slugs plus common
+// short names) under which each provider's models are also looked up.
+var providerPrefixes = map[string][]string{
+	"OpenAI":     {"openai"},
+	"Anthropic":  {"anthropic"},
+	"Google":     {"google", "models"},
+	"Mistral":    {"mistralai", "mistral"},
+	"xAI":        {"x-ai", "xai"},
+	"DeepSeek":   {"deepseek"},
+	"Meta":       {"meta-llama", "meta"},
+	"Amazon":     {"amazon", "aws"},
+	"Cohere":     {"cohere"},
+	"Perplexity": {"perplexity"},
+	"AI21":       {"ai21"},
+	"Moonshot":   {"moonshotai", "moonshot"},
+	"Zhipu":      {"z-ai", "zhipu"},
+	"NVIDIA":     {"nvidia"},
+	"Tencent":    {"tencent"},
+	"Microsoft":  {"microsoft"},
+	"MiniMax":    {"minimax"},
+	"Xiaomi":     {"xiaomi"},
+	"Kuaishou":   {"kwaipilot", "kuaishou"},
+}
+
+// ProviderPrefixes returns the "<prefix>/" forms provider's models are looked
+// up under, OpenRouter's slug first.
+func ProviderPrefixes(provider string) []string {
+	return providerPrefixes[provider]
+}
+
+// floatingAlias is a shorthand that always resolves to the newest current
+// model whose ID matches pattern, so adding a new Opus or Gemini Pro
+// retargets it without editing Aliases.
+type floatingAlias struct {
+	names   []string
+	pattern *regexp.Regexp
+}
+
+var floatingAliases = []floatingAlias{
+	{[]string{"opus", "claude-opus", "claude-opus-latest"}, regexp.MustCompile(`^claude-opus-\d`)},
+	{[]string{"sonnet", "claude-sonnet", "claude-sonnet-latest"}, regexp.MustCompile(`^claude-sonnet-\d`)},
+	{[]string{"haiku", "claude-haiku", "claude-haiku-latest"}, regexp.MustCompile(`^claude-haiku-\d`)},
+	{[]string{"gemini-pro", "gemini-pro-latest"}, regexp.MustCompile(`^gemini-[\d.]+-pro(-preview)?$`)},
+	{[]string{"gemini-flash", "gemini-flash-latest"}, regexp.MustCompile(`^gemini-[\d.]+-flash(-preview)?$`)},
+	{[]string{"mistral-large", "mistral-large-latest"}, regexp.MustCompile(`^mistral-large-\d+$`)},
+}
+
+// IsFloatingAlias reports whether name is a floating alias, one that
+// retargets to the newest matching model instead of staying fixed.
+func IsFloatingAlias(name string) bool {
+	for _, fa := range floatingAliases {
+		for _, n := range fa.names {
+			if n == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// aliasConflicts collects problems found while building Aliases; see
+// AliasConflicts.
+var aliasConflicts []string
+
+func init() {
+	aliasConflicts = deriveAliases(Models, Aliases)
+}
+
+// deriveAliases adds the floating and prefixed aliases for ms to aliases and
+// returns the conflicts found.
+func deriveAliases(ms map[string]Model, aliases map[string]string) []string {
+	conflicts := addFloatingAliases(ms, aliases)
+	addPrefixedAliases(ms, aliases)
+	return conflicts
+}
+
+// addFloatingAliases points each floating alias at the newest current model
+// matching its pattern: latest ReleaseDate first, then stable over preview,
+// then the highest ID.
+func addFloatingAliases(ms map[string]Model, aliases map[string]string) (conflicts []string) {
+	for _, fa := range floatingAliases {
+		var best Model
+		for id, m := range ms {
+			if m.Status != "current" || !fa.pattern.MatchString(id) {
+				continue
+			}
+			if best.ID == "" || newerForAlias(m, best) {
+				best = m
+			}
+		}
+		if best.ID == "" {
+			conflicts = append(conflicts, fmt.Sprintf("floating alias %q matches no current model", fa.names[0]))
+			continue
+		}
+		for _, name := range fa.names {
+			if target, ok := aliases[name]; ok && target != best.ID {
+				conflicts = append(conflicts, fmt.Sprintf("alias %q is hand-written as %q but floats to %q", name, target, best.ID))
+			}
+			aliases[name] = best.ID
+		}
+	}
+	return conflicts
+}
+
+// newerForAlias reports whether a should win a floating alias over b.
+func newerForAlias(a, b Model) bool {
+	if a.ReleaseDate != b.ReleaseDate {
+		return a.ReleaseDate > b.ReleaseDate
+	}
+	if a.Maturity != b.Maturity {
+		return a.Maturity.rank() < b.Maturity.rank()
+	}
+	return a.ID > b.ID
+}
+
+// AliasConflicts reports aliases that shadow a model ID, point at a missing
+// model, or disagree with a floating alias. It is checked by the data tests,
+// so conflicts fail the build instead of silently resolving one way.
+func AliasConflicts() []string {
+	mu.RLock()
+	ms, aliases := Models, Aliases
+	conflicts := append([]string(nil), aliasConflicts...)
+	mu.RUnlock()
+	for alias, target := range aliases {
+		if _, ok := ms[alias]; ok && alias != target {
+			conflicts = append(conflicts, fmt.Sprintf("alias %q shadows the model ID of the same name", alias))
+		}
+		if _, ok := ms[target]; !ok {
+			conflicts = append(conflicts, fmt.Sprintf("alias %q points at unknown model %q", alias, target))
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+// addPrefixedAliases registers a "<prefix>/<id>" alias for every model and
+// each of its provider's prefixes, so OpenRouter-style IDs such as
+// "openai/gpt-5.1" resolve without hand-listing them. Hand-written aliases
+// win, and IDs that already contain a slash are left alone.
+func addPrefixedAliases(ms map[string]Model, aliases map[string]string) {
+	for id, m := range ms {
+		if strings.Contains(id, "/") {
+			continue
+		}
+		for _, prefix := range providerPrefixes[m.Provider] {
+			key := prefix + "/" + id
+			if _, ok := aliases[key]; !ok {
+				aliases[key] = id
+			}
+		}
+	}
 }
 
 // FormatInt formats an integer with comma separators.
@@ -0,0 +1,109 @@
+package models
+
+// ParamConstraint lists request parameters a model's API rejects, pins, or
+// adds beyond the usual chat completion set. Code generators use it to avoid
+// requests that fail with a 400.
+type ParamConstraint struct {
+	Unsupported []string          `json:"unsupported,omitempty"` // rejected if sent
+	Fixed       map[string]string `json:"fixed,omitempty"`       // param → the only accepted value
+	Exclusive   []string          `json:"exclusive,omitempty"`   // send at most one of these
+	Supported   []string          `json:"supported,omitempty"`   // model-specific params worth setting
+	Note        string            `json:"note,omitempty"`
+}
+
+var (
+	openAIReasoningParams = ParamConstraint{
+		Unsupported: []string{"presence_penalty", "frequency_penalty", "logprobs", "max_tokens"},
+		Fixed:       map[string]string{"temperature": "1", "top_p": "1"},
+		Supported:   []string{"reasoning_effort", "max_completion_tokens"},
+		Note:        "Use max_completion_tokens; it also covers hidden reasoning tokens.",
+	}
+	gpt5Params = ParamConstraint{
+		Unsupported: []string{"logprobs", "max_tokens"},
+		Fixed:       map[string]string{"temperature": "1", "top_p": "1"},
+		Supported:   []string{"reasoning_effort", "verbosity", "max_completion_tokens"},
+		Note:        "Use max_completion_tokens; it also covers hidden reasoning tokens.",
+	}
+	gpt5NoneEffortParams = ParamConstraint{
+		Unsupported: []string{"max_tokens"},
+		Supported:   []string{"reasoning_effort", "verbosity", "max_completion_tokens"},
+		Note:        "temperature, top_p, and logprobs are only accepted with reasoning_effort \"none\".",
+	}
+	claudeThinkingParams = ParamConstraint{
+		Exclusive: []string{"temperature", "top_p"},
+		Supported: []string{"thinking"},
+		Note:      "With extended thinking enabled, temperature must be 1, top_k is rejected, and top_p must be 0.95-1.",
+	}
+	claudeLegacyThinkingParams = ParamConstraint{
+		Supported: []string{"thinking"},
+		Note:      "With extended thinking enabled, temperature must be 1, top_k is rejected, and top_p must be 0.95-1.",
+	}
+	gemini3Params = ParamConstraint{
+		Supported: []string{"thinking_level"},
+		Note:      "Keep temperature at the default 1.0; lower values can cause looping or degraded reasoning.",
+	}
+	gemini25Params = ParamConstraint{
+		Supported: []string{"thinking_budget"},
+	}
+	grokReasoningParams = ParamConstraint{
+		Unsupported: []string{"presence_penalty", "frequency_penalty", "stop", "reasoning_effort"},
+		Note:        "Always reasons; effort is not configurable.",
+	}
+	deepSeekReasonerParams = ParamConstraint{
+		Unsupported: []string{"logprobs", "top_logprobs"},
+		Note:        "temperature, top_p, presence_penalty, and frequency_penalty are accepted but have no effect.",
+	}
+)
+
+// ParamConstraints holds known parameter restrictions keyed by model ID.
+// Models without an entry accept the provider's standard parameters.
+var ParamConstraints = map[string]ParamConstraint{
+	"o3":                 openAIReasoningParams,
+	"o3-pro":             openAIReasoningParams,
+	"o3-mini":            openAIReasoningParams,
+	"o3-deep-research":   openAIReasoningParams,
+	"o4-mini":            openAIReasoningParams,
+	"gpt-5":              gpt5Params,
+	"gpt-5-mini":         gpt5Params,
+	"gpt-5-nano":         gpt5Params,
+	"gpt-5.1":            gpt5NoneEffortParams,
+	"gpt-5.1-mini":       gpt5NoneEffortParams,
+	"gpt-5.1-codex":      gpt5Params,
+	"gpt-5.1-codex-mini": gpt5Params,
+	"gpt-5.2":            gpt5NoneEffortParams,
+	"gpt-5.2-codex":      gpt5Params,
+	"gpt-5.2-pro":        gpt5Params,
+	"gpt-5.3-codex":      gpt5Params,
+	"gpt-5.4":            gpt5NoneEffortParams,
+	"gpt-5.4-pro":        gpt5Params,
+
+	"claude-opus-4-6":            claudeThinkingParams,
+	"claude-sonnet-4-6":          claudeThinkingParams,
+	"claude-opus-4-5":            claudeThinkingParams,
+	"claude-sonnet-4-5-20250929": claudeThinkingParams,
+	"claude-haiku-4-5-20251001":  claudeThinkingParams,
+	"claude-opus-4-1":            claudeThinkingParams,
+	"claude-opus-4-0":            claudeLegacyThinkingParams,
+	"claude-sonnet-4-0":          claudeLegacyThinkingParams,
+	"claude-3-7-sonnet-20250219": claudeLegacyThinkingParams,
+
+	"gemini-3.1-pro-preview":        gemini3Params,
+	"gemini-3.1-flash":              gemini3Params,
+	"gemini-3.1-flash-lite-preview": gemini3Params,
+	"gemini-3-pro-preview":          gemini3Params,
+	"gemini-3-flash-preview":        gemini3Params,
+	"gemini-2.5-pro":                gemini25Params,
+	"gemini-2.5-flash":              gemini25Params,
+	"gemini-2.5-flash-lite":         gemini25Params,
+
+	"grok-4":      grokReasoningParams,
+	"grok-4.1":    grokReasoningParams,
+	"grok-4-fast": grokReasoningParams,
+	"grok-3-mini": {
+		Unsupported: []string{"presence_penalty", "frequency_penalty", "stop"},
+		Supported:   []string{"reasoning_effort"},
+	},
+
+	"deepseek-reasoner": deepSeekReasonerParams,
+	"deepseek-r1":       deepSeekReasonerParams,
+}
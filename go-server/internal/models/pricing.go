@@ -0,0 +1,111 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// LongContextPrice is the higher per-1M-token rate a model bills once a
+// request's input exceeds a threshold. The whole request is billed at the
+// higher rate, not just the tokens past the threshold.
+type LongContextPrice struct {
+	AboveInputTokens int     `json:"above_input_tokens"`
+	Input            float64 `json:"input"`
+	Output           float64 `json:"output"`
+	Note             string  `json:"note,omitempty"`
+}
+
+// LongContextPricing maps model IDs to their long-context rate. Models not
+// listed bill the same rate at every prompt length.
+var LongContextPricing = map[string]LongContextPrice{
+	// OpenAI
+	"gpt-5.4":     {AboveInputTokens: 272_000, Input: 5.00, Output: 22.50},
+	"gpt-5.4-pro": {AboveInputTokens: 272_000, Input: 60.00, Output: 270.00},
+
+	// Anthropic: only reachable with the 1M-token context beta.
+	"claude-opus-4-6":            {AboveInputTokens: 200_000, Input: 10.00, Output: 37.50, Note: "1M context beta only"},
+	"claude-sonnet-4-6":          {AboveInputTokens: 200_000, Input: 6.00, Output: 22.50, Note: "1M context beta only"},
+	"claude-sonnet-4-5-20250929": {AboveInputTokens: 200_000, Input: 6.00, Output: 22.50, Note: "1M context beta only"},
+
+	// Google
+	"gemini-3.1-pro-preview": {AboveInputTokens: 200_000, Input: 4.00, Output: 18.00},
+	"gemini-3-pro-preview":   {AboveInputTokens: 200_000, Input: 4.00, Output: 18.00},
+	"gemini-2.5-pro":         {AboveInputTokens: 200_000, Input: 2.50, Output: 15.00},
+}
+
+// PricingFor returns the per-1M-token input and output prices for a request
+// with inputTokens of input. long reports whether the long-context rate
+// applies.
+func (m Model) PricingFor(inputTokens int) (input, output float64, long bool) {
+	if lc, ok := LongContextPricing[m.ID]; ok && inputTokens > lc.AboveInputTokens {
+		return lc.Input, lc.Output, true
+	}
+	return m.PricingInput, m.PricingOutput, false
+}
+
+// CachedInputPricingFor returns the per-1M-token price of cached input for
+// a request with inputTokens of input, or false if the model has no
+// recorded cached rate. Above the long-context threshold the cached rate
+// rises in proportion to the input rate, as providers bill it.
+func (m Model) CachedInputPricingFor(inputTokens int) (float64, bool) {
+	cached, ok := m.CachedInputPricing()
+	if !ok {
+		return 0, false
+	}
+	if in, _, long := m.PricingFor(inputTokens); long && m.PricingInput > 0 {
+		cached *= in / m.PricingInput
+	}
+	return cached, true
+}
+
+// FormatPrice renders a per-1M-token price in dollars with two to four
+// decimals, keeping the sub-cent digits of cached and small-model rates
+// such as $0.125 or $0.005 that rounding to cents would misstate.
+func FormatPrice(p float64) string {
+	s := strconv.FormatFloat(math.Round(p*1e4)/1e4, 'f', -1, 64)
+	if dot := strings.IndexByte(s, '.'); dot < 0 || len(s)-dot-1 < 2 {
+		return fmt.Sprintf("$%.2f", p)
+	}
+	return "$" + s
+}
+
+// DefaultBlendRatio is the input:output token ratio BlendedPrice weights
+// prices by: 3 input tokens per output token, typical of chat and RAG
+// traffic.
+const DefaultBlendRatio = 3.0
+
+var blendRatio atomic.Uint64 // math.Float64bits; 0 until SetBlendRatio
+
+// SetBlendRatio sets how many input tokens BlendedPrice counts per output
+// token. Values <= 0 restore DefaultBlendRatio.
+func SetBlendRatio(r float64) {
+	if r <= 0 {
+		blendRatio.Store(0)
+		return
+	}
+	blendRatio.Store(math.Float64bits(r))
+}
+
+// BlendRatio returns the input:output ratio BlendedPrice uses.
+func BlendRatio() float64 {
+	if bits := blendRatio.Load(); bits != 0 {
+		return math.Float64frombits(bits)
+	}
+	return DefaultBlendRatio
+}
+
+// BlendLabel renders the blend ratio for column headers, e.g. "3:1".
+func BlendLabel() string {
+	return strconv.FormatFloat(BlendRatio(), 'g', -1, 64) + ":1"
+}
+
+// BlendedPrice returns one per-1M-token price for the model's mix of input
+// and output tokens at BlendRatio. Ranking by input price alone flatters
+// reasoning models, whose output runs 8-10x the input rate.
+func (m Model) BlendedPrice() float64 {
+	r := BlendRatio()
+	return (r*m.PricingInput + m.PricingOutput) / (r + 1)
+}
@@ -0,0 +1,177 @@
+package models
+
+import "strings"
+
+// Provider describes a model vendor: where its API and documentation live,
+// how callers authenticate, and the other names users know it by. Whether
+// its API speaks the OpenAI protocol is recorded in Endpoints.
+type Provider struct {
+	Name          string   `json:"name"`                   // as used in Model.Provider
+	APIBaseURL    string   `json:"api_base_url,omitempty"` // native API root; empty when it varies per account or region
+	DocsURL       string   `json:"docs_url"`
+	AuthEnvVar    string   `json:"auth_env_var,omitempty"` // the variable the provider's SDKs read the API key from
+	StatusPageURL string   `json:"status_page_url,omitempty"`
+	Aliases       []string `json:"aliases,omitempty"` // lowercase alternative names accepted as provider filters
+}
+
+// Providers holds metadata for every provider in the registry, keyed by
+// Model.Provider.
+var Providers = map[string]Provider{
+	"OpenAI": {
+		Name:          "OpenAI",
+		APIBaseURL:    "https://api.openai.com/v1",
+		DocsURL:       "https://platform.openai.com/docs/models",
+		AuthEnvVar:    "OPENAI_API_KEY",
+		StatusPageURL: "https://status.openai.com",
+		Aliases:       []string{"gpt", "chatgpt"},
+	},
+	"Anthropic": {
+		Name:          "Anthropic",
+		APIBaseURL:    "https://api.anthropic.com/v1",
+		DocsURL:       "https://docs.anthropic.com/en/docs/about-claude/models",
+		AuthEnvVar:    "ANTHROPIC_API_KEY",
+		StatusPageURL: "https://status.anthropic.com",
+		Aliases:       []string{"claude"},
+	},
+	"Google": {
+		Name:          "Google",
+		APIBaseURL:    "https://generativelanguage.googleapis.com/v1beta",
+		DocsURL:       "https://ai.google.dev/gemini-api/docs/models",
+		AuthEnvVar:    "GEMINI_API_KEY",
+		StatusPageURL: "https://status.cloud.google.com",
+		Aliases:       []string{"gemini"},
+	},
+	"xAI": {
+		Name:          "xAI",
+		APIBaseURL:    "https://api.x.ai/v1",
+		DocsURL:       "https://docs.x.ai/docs/models",
+		AuthEnvVar:    "XAI_API_KEY",
+		StatusPageURL: "https://status.x.ai",
+		Aliases:       []string{"grok"},
+	},
+	"Meta": {
+		Name:       "Meta",
+		APIBaseURL: "https://api.llama.com/v1",
+		DocsURL:    "https://llama.developer.meta.com/docs",
+		AuthEnvVar: "LLAMA_API_KEY",
+		Aliases:    []string{"llama"},
+	},
+	"Mistral": {
+		Name:          "Mistral",
+		APIBaseURL:    "https://api.mistral.ai/v1",
+		DocsURL:       "https://docs.mistral.ai/getting-started/models/",
+		AuthEnvVar:    "MISTRAL_API_KEY",
+		StatusPageURL: "https://status.mistral.ai",
+		Aliases:       []string{"devstral", "magistral", "ministral"},
+	},
+	"DeepSeek": {
+		Name:          "DeepSeek",
+		APIBaseURL:    "https://api.deepseek.com",
+		DocsURL:       "https://api-docs.deepseek.com/",
+		AuthEnvVar:    "DEEPSEEK_API_KEY",
+		StatusPageURL: "https://status.deepseek.com",
+	},
+	"Amazon": {
+		Name:          "Amazon",
+		DocsURL:       "https://docs.aws.amazon.com/nova/latest/userguide/what-is-nova.html",
+		AuthEnvVar:    "AWS_BEARER_TOKEN_BEDROCK",
+		StatusPageURL: "https://health.aws.amazon.com/health/status",
+		Aliases:       []string{"nova", "bedrock"},
+	},
+	"Cohere": {
+		Name:          "Cohere",
+		APIBaseURL:    "https://api.cohere.com/v2",
+		DocsURL:       "https://docs.cohere.com/docs/models",
+		AuthEnvVar:    "CO_API_KEY",
+		StatusPageURL: "https://status.cohere.com",
+		Aliases:       []string{"command"},
+	},
+	"Perplexity": {
+		Name:          "Perplexity",
+		APIBaseURL:    "https://api.perplexity.ai",
+		DocsURL:       "https://docs.perplexity.ai/getting-started/models",
+		AuthEnvVar:    "PERPLEXITY_API_KEY",
+		StatusPageURL: "https://status.perplexity.com",
+		Aliases:       []string{"sonar", "pplx"},
+	},
+	"AI21": {
+		Name:       "AI21",
+		APIBaseURL: "https://api.ai21.com/studio/v1",
+		DocsURL:    "https://docs.ai21.com/docs/jamba-foundation-models",
+		AuthEnvVar: "AI21_API_KEY",
+		Aliases:    []string{"jamba"},
+	},
+	"Moonshot": {
+		Name:       "Moonshot",
+		APIBaseURL: "https://api.moonshot.ai/v1",
+		DocsURL:    "https://platform.moonshot.ai/docs",
+		AuthEnvVar: "MOONSHOT_API_KEY",
+		Aliases:    []string{"kimi"},
+	},
+	"Zhipu": {
+		Name:       "Zhipu",
+		APIBaseURL: "https://api.z.ai/api/paas/v4",
+		DocsURL:    "https://docs.z.ai/guides/overview/pricing",
+		AuthEnvVar: "ZAI_API_KEY",
+		Aliases:    []string{"zhipuai", "z.ai", "bigmodel", "glm"},
+	},
+	"NVIDIA": {
+		Name:       "NVIDIA",
+		APIBaseURL: "https://integrate.api.nvidia.com/v1",
+		DocsURL:    "https://docs.api.nvidia.com/nim/reference/models-1",
+		AuthEnvVar: "NVIDIA_API_KEY",
+		Aliases:    []string{"nemotron", "nim"},
+	},
+	"Tencent": {
+		Name:       "Tencent",
+		APIBaseURL: "https://api.hunyuan.cloud.tencent.com/v1",
+		DocsURL:    "https://cloud.tencent.com/document/product/1729",
+		AuthEnvVar: "HUNYUAN_API_KEY",
+		Aliases:    []string{"hunyuan"},
+	},
+	"Microsoft": {
+		Name:          "Microsoft",
+		DocsURL:       "https://learn.microsoft.com/azure/ai-foundry/concepts/models-featured",
+		AuthEnvVar:    "AZURE_INFERENCE_CREDENTIAL",
+		StatusPageURL: "https://azure.status.microsoft/status",
+		Aliases:       []string{"phi", "azure"},
+	},
+	"MiniMax": {
+		Name:       "MiniMax",
+		APIBaseURL: "https://api.minimax.io/v1",
+		DocsURL:    "https://platform.minimax.io/docs/guides/models-intro",
+		AuthEnvVar: "MINIMAX_API_KEY",
+	},
+	"Xiaomi": {
+		Name:       "Xiaomi",
+		DocsURL:    "https://huggingface.co/XiaomiMiMo",
+		AuthEnvVar: "MIMO_API_KEY",
+		Aliases:    []string{"mimo"},
+	},
+	"Kuaishou": {
+		Name:    "Kuaishou",
+		DocsURL: "https://huggingface.co/Kwaipilot",
+		Aliases: []string{"kwai", "kwaipilot", "kat"},
+	},
+}
+
+// providerIndex maps lowercase provider names and aliases to Providers keys.
+var providerIndex = func() map[string]string {
+	idx := make(map[string]string)
+	for key, p := range Providers {
+		idx[strings.ToLower(key)] = key
+		for _, a := range p.Aliases {
+			idx[a] = key
+		}
+	}
+	return idx
+}()
+
+// LookupProvider finds a provider by name or alias, case-insensitively.
+func LookupProvider(name string) (Provider, bool) {
+	key, ok := providerIndex[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return Provider{}, false
+	}
+	return Providers[key], true
+}
@@ -0,0 +1,165 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReasoningControl describes the request knob that sets how much a model
+// reasons before answering: a level parameter (OpenAI reasoning_effort,
+// Gemini 3 thinking_level) or a token budget (Anthropic thinking, Gemini 2.5
+// thinking_budget). Code generators use it to emit values the API accepts.
+type ReasoningControl struct {
+	Param   string   `json:"param"`             // request parameter, as named in ParamConstraint.Supported
+	Levels  []string `json:"levels,omitempty"`  // accepted values, least reasoning first
+	Default string   `json:"default,omitempty"` // level used when the parameter is omitted
+	// Budget range in tokens, for budget-style controls.
+	MinBudget int    `json:"min_budget,omitempty"`
+	MaxBudget int    `json:"max_budget,omitempty"`
+	Example   string `json:"example"` // request body fragment setting the control
+	Note      string `json:"note,omitempty"`
+}
+
+var (
+	oSeriesEffort = ReasoningControl{
+		Param:   "reasoning_effort",
+		Levels:  []string{"low", "medium", "high"},
+		Default: "medium",
+		Example: `"reasoning_effort": "high"`,
+	}
+	gpt5Effort = ReasoningControl{
+		Param:   "reasoning_effort",
+		Levels:  []string{"minimal", "low", "medium", "high"},
+		Default: "medium",
+		Example: `"reasoning_effort": "minimal"`,
+		Note:    "minimal skips most reasoning for latency-sensitive calls.",
+	}
+	gpt51Effort = ReasoningControl{
+		Param:   "reasoning_effort",
+		Levels:  []string{"none", "low", "medium", "high"},
+		Default: "none",
+		Example: `"reasoning_effort": "medium"`,
+		Note:    "Defaults to none; raise it for multi-step tasks.",
+	}
+	gpt52Effort = ReasoningControl{
+		Param:   "reasoning_effort",
+		Levels:  []string{"none", "low", "medium", "high", "xhigh"},
+		Default: "none",
+		Example: `"reasoning_effort": "medium"`,
+		Note:    "Defaults to none; raise it for multi-step tasks.",
+	}
+	codexEffort = ReasoningControl{
+		Param:   "reasoning_effort",
+		Levels:  []string{"low", "medium", "high", "xhigh"},
+		Default: "medium",
+		Example: `"reasoning_effort": "high"`,
+	}
+	proEffort = ReasoningControl{
+		Param:   "reasoning_effort",
+		Levels:  []string{"medium", "high", "xhigh"},
+		Default: "high",
+		Example: `"reasoning_effort": "xhigh"`,
+	}
+	claudeThinking = ReasoningControl{
+		Param:     "thinking",
+		MinBudget: 1024,
+		Example:   `"thinking": {"type": "enabled", "budget_tokens": 10000}`,
+		Note:      "Off unless enabled. budget_tokens must be below max_tokens.",
+	}
+	claudeAdaptiveThinking = ReasoningControl{
+		Param:     "thinking",
+		MinBudget: 1024,
+		Example:   `"thinking": {"type": "adaptive"}`,
+		Note:      "Off unless enabled. Adaptive thinking lets the model pick its budget; {\"type\": \"enabled\", \"budget_tokens\": N} sets one explicitly (below max_tokens).",
+	}
+	gemini3ProThinking = ReasoningControl{
+		Param:   "thinking_level",
+		Levels:  []string{"low", "high"},
+		Default: "high",
+		Example: `"generationConfig": {"thinkingConfig": {"thinkingLevel": "low"}}`,
+	}
+	gemini3FlashThinking = ReasoningControl{
+		Param:   "thinking_level",
+		Levels:  []string{"minimal", "low", "medium", "high"},
+		Default: "high",
+		Example: `"generationConfig": {"thinkingConfig": {"thinkingLevel": "minimal"}}`,
+	}
+)
+
+// ReasoningControls holds the reasoning knob for each configurable
+// reasoning model, keyed by model ID. Reasoning models without an entry
+// either always reason at a fixed depth (Grok 4, DeepSeek R1) or are not yet
+// recorded.
+var ReasoningControls = map[string]ReasoningControl{
+	"o3":                 oSeriesEffort,
+	"o3-pro":             oSeriesEffort,
+	"o3-mini":            oSeriesEffort,
+	"o4-mini":            oSeriesEffort,
+	"o3-deep-research":   {Param: "reasoning_effort", Levels: []string{"medium"}, Default: "medium", Example: `"reasoning_effort": "medium"`},
+	"gpt-5":              gpt5Effort,
+	"gpt-5-mini":         gpt5Effort,
+	"gpt-5-nano":         gpt5Effort,
+	"gpt-5.1":            gpt51Effort,
+	"gpt-5.1-mini":       gpt51Effort,
+	"gpt-5.1-codex":      {Param: "reasoning_effort", Levels: []string{"low", "medium", "high"}, Default: "medium", Example: `"reasoning_effort": "high"`},
+	"gpt-5.1-codex-mini": {Param: "reasoning_effort", Levels: []string{"medium", "high"}, Default: "medium", Example: `"reasoning_effort": "high"`},
+	"gpt-5.2":            gpt52Effort,
+	"gpt-5.2-codex":      codexEffort,
+	"gpt-5.2-pro":        proEffort,
+	"gpt-5.3-codex":      codexEffort,
+	"gpt-5.4":            gpt52Effort,
+	"gpt-5.4-pro":        proEffort,
+
+	"claude-opus-4-6":            claudeAdaptiveThinking,
+	"claude-sonnet-4-6":          claudeAdaptiveThinking,
+	"claude-opus-4-5":            claudeThinking,
+	"claude-sonnet-4-5-20250929": claudeThinking,
+	"claude-haiku-4-5-20251001":  claudeThinking,
+	"claude-opus-4-1":            claudeThinking,
+	"claude-opus-4-0":            claudeThinking,
+	"claude-sonnet-4-0":          claudeThinking,
+	"claude-3-7-sonnet-20250219": claudeThinking,
+
+	"gemini-3.1-pro-preview":        gemini3ProThinking,
+	"gemini-3-pro-preview":          gemini3ProThinking,
+	"gemini-3.1-flash":              gemini3FlashThinking,
+	"gemini-3.1-flash-lite-preview": gemini3FlashThinking,
+	"gemini-3-flash-preview":        gemini3FlashThinking,
+	"gemini-2.5-pro": {
+		Param: "thinking_budget", MinBudget: 128, MaxBudget: 32_768,
+		Example: `"generationConfig": {"thinkingConfig": {"thinkingBudget": 8192}}`,
+		Note:    "Thinking can't be turned off. -1 lets the model choose the budget (the default).",
+	},
+	"gemini-2.5-flash": {
+		Param: "thinking_budget", MaxBudget: 24_576,
+		Example: `"generationConfig": {"thinkingConfig": {"thinkingBudget": 0}}`,
+		Note:    "0 turns thinking off; -1 lets the model choose the budget (the default).",
+	},
+	"gemini-2.5-flash-lite": {
+		Param: "thinking_budget", MinBudget: 512, MaxBudget: 24_576,
+		Example: `"generationConfig": {"thinkingConfig": {"thinkingBudget": 1024}}`,
+		Note:    "Thinking is off by default (budget 0); -1 lets the model choose.",
+	},
+
+	"grok-3-mini": {Param: "reasoning_effort", Levels: []string{"low", "high"}, Default: "low", Example: `"reasoning_effort": "high"`},
+}
+
+// Describe summarizes the control in one line, e.g. "reasoning_effort:
+// none / low / medium / high (default none)". Levels aren't separated with
+// pipes, which would split the markdown table cell it is shown in.
+func (c ReasoningControl) Describe() string {
+	var b strings.Builder
+	b.WriteString(c.Param)
+	switch {
+	case len(c.Levels) > 0:
+		b.WriteString(": " + strings.Join(c.Levels, " / "))
+		if c.Default != "" {
+			b.WriteString(" (default " + c.Default + ")")
+		}
+	case c.MaxBudget > 0:
+		fmt.Fprintf(&b, ": %s-%s token budget", FormatInt(c.MinBudget), FormatInt(c.MaxBudget))
+	case c.MinBudget > 0:
+		fmt.Fprintf(&b, ": budget of at least %s tokens", FormatInt(c.MinBudget))
+	}
+	return b.String()
+}
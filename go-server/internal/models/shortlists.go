@@ -0,0 +1,104 @@
+package models
+
+// TaskShortlist is a hand-curated, ranked set of models for a common task.
+// Unlike recommend_model's scoring it doesn't move when prices or release
+// dates change, so agents asking the same question get the same answer.
+// Review the picks whenever a listed model is deprecated.
+type TaskShortlist struct {
+	Task     string          `json:"task"` // stable key, e.g. "agentic-coding"
+	Name     string          `json:"name"`
+	Summary  string          `json:"summary"`  // what the task needs from a model
+	Keywords []string        `json:"keywords"` // lowercase phrases that select this shortlist
+	Picks    []ShortlistPick `json:"picks"`    // best first
+}
+
+// ShortlistPick is one model on a shortlist and why it's there.
+type ShortlistPick struct {
+	ModelID string `json:"model_id"`
+	Role    string `json:"role"` // e.g. "top pick", "budget", "open weights"
+	Why     string `json:"why"`
+}
+
+// TaskShortlists lists the curated shortlists in display order. Every pick
+// must be a current model (data_test.go enforces it).
+var TaskShortlists = []TaskShortlist{
+	{
+		Task:     "agentic-coding",
+		Name:     "Agentic coding",
+		Summary:  "Multi-step code changes driven by tool calls: reading a repo, editing files, running tests, and recovering from failures.",
+		Keywords: []string{"coding", "code", "programming", "software", "swe", "refactor", "coding agent"},
+		Picks: []ShortlistPick{
+			{ModelID: "claude-opus-4-6", Role: "top pick", Why: "Most reliable on long-horizon edits and tool use"},
+			{ModelID: "gpt-5.1-codex", Role: "top pick", Why: "Tuned for long-running code tasks in Codex-style harnesses"},
+			{ModelID: "claude-sonnet-4-6", Role: "balanced", Why: "Near-flagship coding at a third of Opus pricing"},
+			{ModelID: "grok-code-fast-1", Role: "budget", Why: "Fast, cheap agentic coding; 70.8% SWE-Bench"},
+			{ModelID: "minimax-m2.5", Role: "open weights", Why: "80.2% SWE-Bench Verified under an MIT license"},
+			{ModelID: "devstral-2512", Role: "open weights", Why: "Mistral's coding agent model, self-hostable"},
+		},
+	},
+	{
+		Task:     "rag-summarization",
+		Name:     "RAG and summarization",
+		Summary:  "Answering from retrieved passages and condensing long documents without inventing facts.",
+		Keywords: []string{"rag", "retrieval", "summarization", "summarize", "summary", "grounded", "long document"},
+		Picks: []ShortlistPick{
+			{ModelID: "gemini-2.5-pro", Role: "top pick", Why: "1M context holds whole document sets without chunking"},
+			{ModelID: "command-a-03-2025", Role: "top pick", Why: "Built for RAG with grounded, cited answers"},
+			{ModelID: "gemini-2.5-flash", Role: "balanced", Why: "1M context at a fraction of Pro pricing"},
+			{ModelID: "gpt-5-nano", Role: "budget", Why: "Cheapest GPT-5 variant, strong at summarization"},
+			{ModelID: "llama-4-scout", Role: "long context", Why: "10M context for corpora too big for anything else"},
+		},
+	},
+	{
+		Task:     "data-extraction",
+		Name:     "Data extraction",
+		Summary:  "Pulling structured fields out of unstructured text into JSON that validates against a schema.",
+		Keywords: []string{"extraction", "extract", "structured output", "json", "parsing", "classification", "entity"},
+		Picks: []ShortlistPick{
+			{ModelID: "gpt-5-mini", Role: "top pick", Why: "Reliable strict-schema JSON at a low price"},
+			{ModelID: "gemini-2.5-flash", Role: "top pick", Why: "Schema-constrained output with 1M context for long inputs"},
+			{ModelID: "claude-haiku-4-5-20251001", Role: "balanced", Why: "Fast and accurate on messy inputs"},
+			{ModelID: "gpt-4.1-nano", Role: "budget", Why: "Cheapest OpenAI model for high-volume field extraction"},
+			{ModelID: "mistral-small-2506", Role: "open weights", Why: "Cheap, self-hostable, with JSON mode"},
+		},
+	},
+	{
+		Task:     "translation",
+		Name:     "Translation",
+		Summary:  "Translating documents and UI strings while keeping tone, formatting, and terminology.",
+		Keywords: []string{"translation", "translate", "multilingual", "localization", "localisation", "i18n", "l10n"},
+		Picks: []ShortlistPick{
+			{ModelID: "command-a-translate-08-2025", Role: "top pick", Why: "Translation-specialized fine-tune covering 23 languages"},
+			{ModelID: "gemini-2.5-pro", Role: "top pick", Why: "Broad language coverage and whole-document context"},
+			{ModelID: "mistral-large-2512", Role: "balanced", Why: "Strong multilingual flagship at a low price"},
+			{ModelID: "mistral-saba-2502", Role: "regional", Why: "Specialized for Middle Eastern and South Asian languages"},
+			{ModelID: "gemini-2.5-flash", Role: "budget", Why: "Cheap, fast, and handles long documents"},
+		},
+	},
+	{
+		Task:     "ocr",
+		Name:     "OCR and document understanding",
+		Summary:  "Reading text, tables, and forms from scans, screenshots, and PDFs.",
+		Keywords: []string{"ocr", "scan", "pdf", "document understanding", "invoice", "receipt", "handwriting", "image to text"},
+		Picks: []ShortlistPick{
+			{ModelID: "gemini-2.5-pro", Role: "top pick", Why: "Strongest on dense layouts and multi-page PDFs"},
+			{ModelID: "claude-sonnet-4-6", Role: "top pick", Why: "Accurate on tables, charts, and handwriting"},
+			{ModelID: "gemini-2.5-flash", Role: "balanced", Why: "Good page-level OCR at volume pricing"},
+			{ModelID: "command-a-vision-07-2025", Role: "enterprise", Why: "Up to 20 images per request, open weights"},
+			{ModelID: "amazon-nova-lite", Role: "budget", Why: "Low-cost multimodal on Bedrock"},
+		},
+	},
+	{
+		Task:     "customer-support",
+		Name:     "Customer support",
+		Summary:  "Answering customers in chat with low latency, consistent tone, and reliable tool calls into ticketing and order systems.",
+		Keywords: []string{"customer support", "support", "helpdesk", "help desk", "chatbot", "customer service", "ticket"},
+		Picks: []ShortlistPick{
+			{ModelID: "claude-haiku-4-5-20251001", Role: "top pick", Why: "Fast, on-tone responses with dependable tool use"},
+			{ModelID: "gpt-5-mini", Role: "top pick", Why: "Cheap and consistent for high-volume chat"},
+			{ModelID: "grok-4.1-fast", Role: "balanced", Why: "Fast tool calling with low hallucination"},
+			{ModelID: "gemini-2.5-flash", Role: "balanced", Why: "Long context for full conversation and policy history"},
+			{ModelID: "amazon-nova-micro", Role: "budget", Why: "Lowest-latency Nova for simple FAQ routing"},
+		},
+	},
+}
@@ -0,0 +1,35 @@
+package models
+
+// Speed is measured serving performance for a model on its first-party API.
+// Numbers vary with load, region, and prompt length, so treat them as typical
+// values, not guarantees.
+type Speed struct {
+	OutputTokensPerSec float64 `json:"output_tokens_per_sec"`
+	TTFTMillis         int     `json:"ttft_ms"`     // time to first token, including any hidden reasoning
+	Source             string  `json:"source"`      // where the numbers came from
+	MeasuredAt         string  `json:"measured_at"` // YYYY-MM
+}
+
+// speedSourceAA marks median figures published by Artificial Analysis.
+const speedSourceAA = "Artificial Analysis (median, first-party API)"
+
+// Speeds holds throughput and latency benchmarks keyed by model ID. Refresh
+// entries with `go run ./cmd/bench` when API keys are available; it prints
+// lines in this format with Source set to "cmd/bench".
+var Speeds = map[string]Speed{
+	"gpt-4.1":                    {OutputTokensPerSec: 90, TTFTMillis: 500, Source: speedSourceAA, MeasuredAt: "2026-02"},
+	"gpt-4.1-mini":               {OutputTokensPerSec: 75, TTFTMillis: 450, Source: speedSourceAA, MeasuredAt: "2026-02"},
+	"gpt-4.1-nano":               {OutputTokensPerSec: 140, TTFTMillis: 350, Source: speedSourceAA, MeasuredAt: "2026-02"},
+	"gpt-4o":                     {OutputTokensPerSec: 110, TTFTMillis: 450, Source: speedSourceAA, MeasuredAt: "2026-02"},
+	"gpt-4o-mini":                {OutputTokensPerSec: 70, TTFTMillis: 450, Source: speedSourceAA, MeasuredAt: "2026-02"},
+	"claude-haiku-4-5-20251001":  {OutputTokensPerSec: 110, TTFTMillis: 600, Source: speedSourceAA, MeasuredAt: "2026-02"},
+	"claude-sonnet-4-5-20250929": {OutputTokensPerSec: 65, TTFTMillis: 1200, Source: speedSourceAA, MeasuredAt: "2026-02"},
+	"gemini-2.5-flash":           {OutputTokensPerSec: 250, TTFTMillis: 400, Source: speedSourceAA, MeasuredAt: "2026-02"},
+	"gemini-2.5-flash-lite":      {OutputTokensPerSec: 400, TTFTMillis: 300, Source: speedSourceAA, MeasuredAt: "2026-02"},
+	"gemini-2.0-flash":           {OutputTokensPerSec: 200, TTFTMillis: 350, Source: speedSourceAA, MeasuredAt: "2026-02"},
+	"gemini-2.0-flash-lite":      {OutputTokensPerSec: 220, TTFTMillis: 300, Source: speedSourceAA, MeasuredAt: "2026-02"},
+	"mistral-small-2506":         {OutputTokensPerSec: 130, TTFTMillis: 350, Source: speedSourceAA, MeasuredAt: "2026-02"},
+	"deepseek-chat":              {OutputTokensPerSec: 25, TTFTMillis: 3000, Source: speedSourceAA, MeasuredAt: "2026-02"},
+	"amazon-nova-micro":          {OutputTokensPerSec: 250, TTFTMillis: 350, Source: speedSourceAA, MeasuredAt: "2026-02"},
+	"amazon-nova-lite":           {OutputTokensPerSec: 200, TTFTMillis: 350, Source: speedSourceAA, MeasuredAt: "2026-02"},
+}
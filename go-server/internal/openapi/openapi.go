@@ -0,0 +1,288 @@
+// Package openapi describes the server's REST endpoints (/api/registry,
+// /api/changes, the /api/v1 query endpoints, and /health) as an OpenAPI 3.1
+// document. The response types
+// here are the ones the handlers encode, and their schemas are derived from
+// them, so the spec cannot drift from what the server sends. cmd/genclients
+// generates the TypeScript and Python clients under clients/ from it.
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"go-server/internal/changelog"
+	"go-server/internal/models"
+)
+
+// Version is the REST API contract version reported in the spec's
+// info.version and by the generated clients. The server build's own version
+// is in package buildinfo, and /health reports that.
+const Version = "1.4.0"
+
+// RegistryResponse is the body of GET /api/registry.
+type RegistryResponse struct {
+	Cursor string                  `json:"cursor" jsonschema:"Changelog cursor this snapshot corresponds to; pass it to /api/changes as since"`
+	Models map[string]models.Model `json:"models" jsonschema:"Every model in the registry, keyed by model ID"`
+}
+
+// Change is one /api/changes entry. Model carries the model's current state
+// for added and changed entries, so mirrors can upsert it directly.
+type Change struct {
+	changelog.Entry
+	Model *models.Model `json:"model,omitempty" jsonschema:"The model's current state, for added and changed entries"`
+}
+
+// ChangesResponse is the body of GET /api/changes.
+type ChangesResponse struct {
+	Cursor  string   `json:"cursor" jsonschema:"Cursor to pass as since on the next poll"`
+	Resync  bool     `json:"resync" jsonschema:"True when since is unknown to this server; refetch /api/registry"`
+	Changes []Change `json:"changes" jsonschema:"Registry mutations after since, oldest first"`
+}
+
+// ModelList is the body of the /api/v1 endpoints that return several models:
+// /api/v1/models, /api/v1/search, and /api/v1/compare.
+type ModelList struct {
+	Count  int            `json:"count"`
+	Models []models.Model `json:"models" jsonschema:"Matching models, grouped by provider and newest first; /api/v1/compare keeps the requested order"`
+}
+
+// Recommendation is one /api/v1/recommend pick.
+type Recommendation struct {
+	Rank      int          `json:"rank" jsonschema:"1 for the best pick"`
+	Score     float64      `json:"score" jsonschema:"Relative score from the recommend_model heuristics; only comparable within one response"`
+	ChurnRisk string       `json:"churn_risk,omitempty" jsonschema:"Predecessors deprecated within months of release, for tasks that ask for stability"`
+	Model     models.Model `json:"model"`
+}
+
+// RecommendResponse is the body of GET /api/v1/recommend.
+type RecommendResponse struct {
+	Task            string           `json:"task"`
+	Budget          string           `json:"budget" jsonschema:"Normalized budget: cheap, moderate, or expensive"`
+	Recommendations []Recommendation `json:"recommendations" jsonschema:"Up to 3 picks, best first"`
+}
+
+// Health is the body of GET /health.
+type Health struct {
+	Status     string `json:"status"`
+	Models     int    `json:"models"`
+	Version    string `json:"version" jsonschema:"Server build version; dev when not stamped at build time"`
+	Commit     string `json:"commit,omitempty" jsonschema:"VCS revision the server was built from"`
+	BuildDate  string `json:"build_date,omitempty" jsonschema:"Build time, RFC 3339"`
+	Registry   string `json:"registry" jsonschema:"Content hash of the base registry; changes whenever model data does"`
+	UptimeSecs int    `json:"uptime_secs"`
+	Transport  string `json:"transport"`
+	Tenants    int    `json:"tenants"`
+}
+
+// Param is a path or query parameter of an Operation.
+type Param struct {
+	Name        string
+	Type        string // JSON schema type, e.g. "integer"
+	Required    bool
+	Description string
+}
+
+// Operation is one GET endpoint. Response names a schema in Schemas. Each
+// PathParams entry fills a {name} segment of Path and is always required.
+type Operation struct {
+	ID         string
+	Path       string
+	Summary    string
+	PathParams []Param
+	Query      []Param
+	Response   string
+}
+
+// Operations lists every endpoint in the spec.
+var Operations = []Operation{
+	{
+		ID:       "getRegistry",
+		Path:     "/api/registry",
+		Summary:  "Full registry snapshot plus the changelog cursor it corresponds to",
+		Response: "RegistryResponse",
+	},
+	{
+		ID:      "getChanges",
+		Path:    "/api/changes",
+		Summary: "Registry mutations after a cursor, for delta sync",
+		Query: []Param{{
+			Name:        "since",
+			Type:        "integer",
+			Required:    true,
+			Description: "Cursor from /api/registry or a previous /api/changes response",
+		}},
+		Response: "ChangesResponse",
+	},
+	{
+		ID:      "listModels",
+		Path:    "/api/v1/models",
+		Summary: "Models matching optional filters, after org policy",
+		Query: []Param{
+			{Name: "provider", Type: "string", Description: "Provider name or alias, e.g. openai or google"},
+			{Name: "status", Type: "string", Description: "current, legacy, or deprecated"},
+			{Name: "capability", Type: "string", Description: "Capability name, e.g. vision or reasoning, or default for each provider's recommended default"},
+			{Name: "sovereignty", Type: "string", Description: "eu for EU-hosted models only"},
+		},
+		Response: "ModelList",
+	},
+	{
+		ID:      "getModel",
+		Path:    "/api/v1/models/{model_id}",
+		Summary: "One model by ID or alias, with the same fallback matching as get_model_info",
+		PathParams: []Param{{
+			Name:        "model_id",
+			Type:        "string",
+			Description: "Model ID or alias; may contain slashes, e.g. openai/gpt-5.2",
+		}},
+		Response: "Model",
+	},
+	{
+		ID:      "searchModels",
+		Path:    "/api/v1/search",
+		Summary: "Models whose names, provider, notes, capabilities, or aliases match every word of a query",
+		Query: []Param{{
+			Name:        "q",
+			Type:        "string",
+			Required:    true,
+			Description: "Search words, e.g. vision or claude sonnet",
+		}},
+		Response: "ModelList",
+	},
+	{
+		ID:      "compareModels",
+		Path:    "/api/v1/compare",
+		Summary: "Two to five models side by side, in the order requested",
+		Query: []Param{{
+			Name:        "ids",
+			Type:        "string",
+			Required:    true,
+			Description: "Comma-separated model IDs or aliases",
+		}},
+		Response: "ModelList",
+	},
+	{
+		ID:      "recommendModels",
+		Path:    "/api/v1/recommend",
+		Summary: "Top picks for a task, scored like the recommend_model tool",
+		Query: []Param{
+			{Name: "task", Type: "string", Required: true, Description: "What the model is for, e.g. coding agent or cheap batch summarization"},
+			{Name: "budget", Type: "string", Description: "cheap, moderate (default), or expensive"},
+			{Name: "sovereignty", Type: "string", Description: "eu to recommend EU-hosted models only"},
+			{Name: "min_providers", Type: "integer", Description: "Require the picks to span this many providers (max 3)"},
+		},
+		Response: "RecommendResponse",
+	},
+	{
+		ID:       "getHealth",
+		Path:     "/health",
+		Summary:  "Liveness, registry size, and registry content hash",
+		Response: "Health",
+	},
+}
+
+// schemaTypes maps each component schema name to its Go type.
+var schemaTypes = []struct {
+	name string
+	typ  reflect.Type
+}{
+	{"Model", reflect.TypeFor[models.Model]()},
+	{"Change", reflect.TypeFor[Change]()},
+	{"RegistryResponse", reflect.TypeFor[RegistryResponse]()},
+	{"ChangesResponse", reflect.TypeFor[ChangesResponse]()},
+	{"ModelList", reflect.TypeFor[ModelList]()},
+	{"Recommendation", reflect.TypeFor[Recommendation]()},
+	{"RecommendResponse", reflect.TypeFor[RecommendResponse]()},
+	{"Health", reflect.TypeFor[Health]()},
+}
+
+func ref(name string) string { return "#/components/schemas/" + name }
+
+// Schemas returns the component schemas by name. Nested component types are
+// $refs rather than inlined copies.
+func Schemas() map[string]*jsonschema.Schema {
+	out := make(map[string]*jsonschema.Schema, len(schemaTypes))
+	for _, st := range schemaTypes {
+		refs := make(map[reflect.Type]*jsonschema.Schema)
+		for _, other := range schemaTypes {
+			if other.typ != st.typ {
+				refs[other.typ] = &jsonschema.Schema{Ref: ref(other.name)}
+			}
+		}
+		s, err := jsonschema.ForType(st.typ, &jsonschema.ForOptions{TypeSchemas: refs})
+		if err != nil {
+			// The types above are plain structs, so this can't happen.
+			panic(err)
+		}
+		for _, p := range s.Properties {
+			// A pointer to a component infers as type null alongside the
+			// $ref, which would allow only null. Optionality comes from
+			// required instead.
+			if p.Ref != "" {
+				p.Type, p.Types = "", nil
+			}
+		}
+		out[st.name] = s
+	}
+	return out
+}
+
+func param(p Param, in string, required bool) map[string]any {
+	return map[string]any{
+		"name":        p.Name,
+		"in":          in,
+		"required":    required,
+		"description": p.Description,
+		"schema":      map[string]any{"type": p.Type},
+	}
+}
+
+// Spec returns the OpenAPI 3.1 document.
+func Spec() map[string]any {
+	paths := make(map[string]any, len(Operations))
+	for _, op := range Operations {
+		var params []any
+		for _, p := range op.PathParams {
+			params = append(params, param(p, "path", true))
+		}
+		for _, p := range op.Query {
+			params = append(params, param(p, "query", p.Required))
+		}
+		get := map[string]any{
+			"operationId": op.ID,
+			"summary":     op.Summary,
+			"responses": map[string]any{
+				"200": map[string]any{
+					"description": "OK",
+					"content": map[string]any{
+						"application/json": map[string]any{"schema": map[string]any{"$ref": ref(op.Response)}},
+					},
+				},
+			},
+		}
+		if len(params) > 0 {
+			get["parameters"] = params
+		}
+		paths[op.Path] = map[string]any{"get": get}
+	}
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":       "Model ID Cheatsheet REST API",
+			"version":     Version,
+			"description": "Read-only registry queries, snapshots, and delta sync for mirrors, dashboards, and scripts.",
+		},
+		"paths":      paths,
+		"components": map[string]any{"schemas": Schemas()},
+	}
+}
+
+// JSON returns the spec as indented JSON.
+func JSON() []byte {
+	out, err := json.MarshalIndent(Spec(), "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
@@ -0,0 +1,55 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSpecRefsResolve(t *testing.T) {
+	schemas := Schemas()
+	for _, op := range Operations {
+		if schemas[op.Response] == nil {
+			t.Errorf("%s responds with unknown schema %q", op.Path, op.Response)
+		}
+	}
+	var refs []string
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			for k, x := range v {
+				if s, ok := x.(string); ok && k == "$ref" {
+					refs = append(refs, s)
+				}
+				walk(x)
+			}
+		case []any:
+			for _, x := range v {
+				walk(x)
+			}
+		}
+	}
+	var doc any
+	if err := json.Unmarshal(JSON(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	walk(doc)
+	if len(refs) == 0 {
+		t.Fatal("expected component references in the spec")
+	}
+	for _, r := range refs {
+		if schemas[strings.TrimPrefix(r, "#/components/schemas/")] == nil {
+			t.Errorf("dangling $ref %s", r)
+		}
+	}
+}
+
+func TestModelSchemaCoversFields(t *testing.T) {
+	m := Schemas()["Model"]
+	for _, field := range []string{"id", "provider", "pricing_input", "context_window", "status"} {
+		if m.Properties[field] == nil {
+			t.Errorf("Model schema is missing %q", field)
+		}
+	}
+}
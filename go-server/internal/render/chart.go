@@ -0,0 +1,78 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Series is one set of bars in a grouped bar chart, one value per label.
+type Series struct {
+	Name   string
+	Color  string // any SVG color, e.g. "#4e79a7"
+	Values []float64
+}
+
+// Chart layout in SVG user units.
+const (
+	chartBarWidth = 22
+	chartGap      = 28
+	chartHeight   = 220
+	chartTop      = 60
+	chartLeft     = 60
+	chartLabelH   = 70
+	chartLegendW  = 140
+)
+
+// BarChart draws a grouped bar chart as a standalone SVG: one group per
+// label, one bar per series, scaled to the largest value and annotated with
+// valueFormat (e.g. "$%.2f"). It needs no fonts or image libraries, so any
+// client or browser that displays SVG can show it.
+func BarChart(title string, labels []string, series []Series, valueFormat string) string {
+	peak := 0.0
+	for _, s := range series {
+		for _, v := range s.Values {
+			peak = max(peak, v)
+		}
+	}
+	if peak <= 0 {
+		peak = 1
+	}
+	groupW := len(series)*chartBarWidth + chartGap
+	width := max(chartLeft+len(labels)*groupW+chartGap, chartLeft+len(series)*chartLegendW)
+	height := chartTop + chartHeight + chartLabelH
+	baseline := chartTop + chartHeight
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`+"\n", width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", width, height)
+	fmt.Fprintf(&b, `<text x="%d" y="22" font-size="14" font-weight="bold">%s</text>`+"\n", chartLeft, html.EscapeString(title))
+	fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#57606a"/>`+"\n", chartLeft, baseline, width-chartGap/2, baseline)
+
+	for i, label := range labels {
+		x0 := chartLeft + chartGap/2 + i*groupW
+		for j, s := range series {
+			v := 0.0
+			if i < len(s.Values) {
+				v = s.Values[i]
+			}
+			h := int(v / peak * chartHeight)
+			x := x0 + j*chartBarWidth
+			fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"><title>%s: %s</title></rect>`+"\n",
+				x, baseline-h, chartBarWidth-2, h, html.EscapeString(s.Color), html.EscapeString(s.Name), html.EscapeString(fmt.Sprintf(valueFormat, v)))
+			fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="middle" font-size="9">%s</text>`+"\n",
+				x+chartBarWidth/2-1, baseline-h-3, html.EscapeString(fmt.Sprintf(valueFormat, v)))
+		}
+		cx := x0 + len(series)*chartBarWidth/2
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end" transform="rotate(-30 %d %d)">%s</text>`+"\n",
+			cx, baseline+14, cx, baseline+14, html.EscapeString(label))
+	}
+
+	for j, s := range series {
+		x := chartLeft + j*chartLegendW
+		fmt.Fprintf(&b, `<rect x="%d" y="32" width="10" height="10" fill="%s"/>`+"\n", x, html.EscapeString(s.Color))
+		fmt.Fprintf(&b, `<text x="%d" y="41">%s</text>`+"\n", x+15, html.EscapeString(s.Name))
+	}
+	b.WriteString("</svg>")
+	return b.String()
+}
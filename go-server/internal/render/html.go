@@ -0,0 +1,81 @@
+package render
+
+import (
+	"html"
+	"strconv"
+	"strings"
+)
+
+// htmlStyle keeps the document readable when pasted into docs or opened
+// directly, without pulling in external stylesheets.
+const htmlStyle = `body{font-family:system-ui,sans-serif;margin:2rem;color:#1f2328}` +
+	`table{border-collapse:collapse;margin:1rem 0}` +
+	`th,td{border:1px solid #d0d7de;padding:.35rem .7rem;text-align:left}` +
+	`th{background:#f6f8fa}`
+
+// ToHTML renders markdown as a standalone HTML document: headings, tables
+// with a header row, and paragraphs, with "- " lines as lists. All text is
+// escaped.
+func ToHTML(markdown string) string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<style>" + htmlStyle + "</style>\n</head>\n<body>\n")
+	for _, blk := range Parse(markdown) {
+		switch blk.Type {
+		case "heading":
+			level := strconv.Itoa(min(max(blk.Level, 1), 6))
+			b.WriteString("<h" + level + ">" + html.EscapeString(blk.Text) + "</h" + level + ">\n")
+		case "table":
+			b.WriteString("<table>\n<thead><tr>")
+			for _, c := range blk.Columns {
+				b.WriteString("<th>" + html.EscapeString(c) + "</th>")
+			}
+			b.WriteString("</tr></thead>\n<tbody>\n")
+			for _, row := range blk.Rows {
+				b.WriteString("<tr>")
+				for _, c := range blk.Columns {
+					b.WriteString("<td>" + html.EscapeString(row[c]) + "</td>")
+				}
+				b.WriteString("</tr>\n")
+			}
+			b.WriteString("</tbody>\n</table>\n")
+		default:
+			writeHTMLText(&b, blk.Text)
+		}
+	}
+	b.WriteString("</body>\n</html>")
+	return b.String()
+}
+
+// writeHTMLText writes a text block as paragraphs, grouping runs of "- "
+// lines into a list.
+func writeHTMLText(b *strings.Builder, text string) {
+	var para []string
+	inList := false
+	flush := func() {
+		if len(para) > 0 {
+			b.WriteString("<p>" + strings.Join(para, "<br>\n") + "</p>\n")
+			para = nil
+		}
+	}
+	for _, line := range strings.Split(text, "\n") {
+		item, isItem := strings.CutPrefix(line, "- ")
+		switch {
+		case isItem && !inList:
+			flush()
+			b.WriteString("<ul>\n")
+			inList = true
+		case !isItem && inList:
+			b.WriteString("</ul>\n")
+			inList = false
+		}
+		if isItem {
+			b.WriteString("<li>" + html.EscapeString(item) + "</li>\n")
+		} else {
+			para = append(para, html.EscapeString(line))
+		}
+	}
+	if inList {
+		b.WriteString("</ul>\n")
+	}
+	flush()
+}
@@ -0,0 +1,199 @@
+// Package render converts tool output from the markdown every tool produces
+// into the format a client asked for. Tools keep writing markdown; the server
+// renders once at the edge, so adding a format never touches a tool.
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Output formats accepted by the shared format tool parameter.
+const (
+	Markdown = "markdown"
+	JSON     = "json"
+	Compact  = "compact"
+	HTML     = "html"
+)
+
+// Formats lists the accepted formats, default first.
+var Formats = []string{Markdown, JSON, Compact, HTML}
+
+// Block is one piece of a parsed markdown document.
+type Block struct {
+	Type    string              `json:"type"` // "heading", "table", or "text"
+	Level   int                 `json:"level,omitempty"`
+	Text    string              `json:"text,omitempty"`
+	Columns []string            `json:"columns,omitempty"`
+	Rows    []map[string]string `json:"rows,omitempty"`
+}
+
+// Document is a parsed markdown document: the value the JSON format encodes
+// and the server returns as a tool's structured content.
+type Document struct {
+	Blocks []Block `json:"blocks"`
+}
+
+// Structured parses markdown into a Document.
+func Structured(markdown string) Document {
+	return Document{Blocks: Parse(markdown)}
+}
+
+// Normalize lowercases format and maps empty to Markdown. It reports false
+// for formats it doesn't know.
+func Normalize(format string) (string, bool) {
+	f := strings.ToLower(strings.TrimSpace(format))
+	if f == "" {
+		return Markdown, true
+	}
+	for _, known := range Formats {
+		if f == known {
+			return f, true
+		}
+	}
+	return f, false
+}
+
+// Apply renders markdown in the requested format. Unknown formats return an
+// error naming the accepted ones.
+func Apply(markdown, format string) (string, error) {
+	f, ok := Normalize(format)
+	if !ok {
+		return "", fmt.Errorf("unknown format %q; use %s", format, strings.Join(Formats, ", "))
+	}
+	switch f {
+	case JSON:
+		return ToJSON(markdown), nil
+	case Compact:
+		return ToCompact(markdown), nil
+	case HTML:
+		return ToHTML(markdown), nil
+	}
+	return markdown, nil
+}
+
+// ToJSON parses markdown into blocks and encodes them as
+// {"blocks": [...]}. Table rows become objects keyed by column header.
+func ToJSON(markdown string) string {
+	return Structured(markdown).JSON()
+}
+
+// JSON encodes d as the json format's text.
+func (d Document) JSON() string {
+	out, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		// Blocks hold only strings and ints, so this can't happen.
+		panic(err)
+	}
+	return string(out)
+}
+
+// ToCompact strips markdown decoration to save context: tables lose their
+// outer pipes, padding, and separator rows; emphasis and blank lines go.
+func ToCompact(markdown string) string {
+	var lines []string
+	for _, b := range Parse(markdown) {
+		switch b.Type {
+		case "heading":
+			lines = append(lines, b.Text)
+		case "table":
+			lines = append(lines, strings.Join(b.Columns, "|"))
+			for _, row := range b.Rows {
+				cells := make([]string, len(b.Columns))
+				for i, c := range b.Columns {
+					cells[i] = row[c]
+				}
+				lines = append(lines, strings.Join(cells, "|"))
+			}
+		default:
+			lines = append(lines, b.Text)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Parse splits markdown into heading, table, and text blocks. Consecutive
+// non-table lines form one text block; blank lines end it.
+func Parse(markdown string) []Block {
+	var blocks []Block
+	var text []string
+	var table *Block
+	flushText := func() {
+		if len(text) > 0 {
+			blocks = append(blocks, Block{Type: "text", Text: strings.Join(text, "\n")})
+			text = nil
+		}
+	}
+	flushTable := func() {
+		if table != nil {
+			blocks = append(blocks, *table)
+			table = nil
+		}
+	}
+
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "|"):
+			flushText()
+			cells := splitRow(trimmed)
+			if table == nil {
+				table = &Block{Type: "table", Columns: cells}
+				continue
+			}
+			if isSeparator(cells) {
+				continue
+			}
+			row := make(map[string]string, len(table.Columns))
+			for i, c := range table.Columns {
+				if i < len(cells) {
+					row[c] = cells[i]
+				}
+			}
+			table.Rows = append(table.Rows, row)
+		case trimmed == "":
+			flushTable()
+			flushText()
+		case strings.HasPrefix(trimmed, "#"):
+			flushTable()
+			flushText()
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			blocks = append(blocks, Block{Type: "heading", Level: level, Text: plain(trimmed[level:])})
+		default:
+			flushTable()
+			text = append(text, plain(trimmed))
+		}
+	}
+	flushTable()
+	flushText()
+	return blocks
+}
+
+// splitRow returns the trimmed, de-decorated cells of a table row.
+func splitRow(line string) []string {
+	line = strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|")
+	parts := strings.Split(line, "|")
+	cells := make([]string, len(parts))
+	for i, p := range parts {
+		cells[i] = strings.TrimPrefix(plain(p), "★ ")
+	}
+	return cells
+}
+
+// isSeparator reports whether cells form a header separator row (---, :--:).
+func isSeparator(cells []string) bool {
+	for _, c := range cells {
+		if strings.Trim(c, "-: ") != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// plain removes bold and code markers.
+func plain(s string) string {
+	s = strings.ReplaceAll(s, "**", "")
+	s = strings.ReplaceAll(s, "`", "")
+	return strings.TrimSpace(s)
+}
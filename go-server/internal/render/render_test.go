@@ -0,0 +1,104 @@
+package render
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const sample = `## GPT-5 (` + "`gpt-5`" + `)
+
+| Field | Value |
+|-------|-------|
+| **Provider** | OpenAI |
+| Model ID | ★ ` + "`gpt-5`" + ` |
+
+**→ USE IN CODE: gpt-5**
+Second line`
+
+func TestParse(t *testing.T) {
+	blocks := Parse(sample)
+	if len(blocks) != 3 {
+		t.Fatalf("expected heading, table, text; got %+v", blocks)
+	}
+	if blocks[0].Type != "heading" || blocks[0].Level != 2 || blocks[0].Text != "GPT-5 (gpt-5)" {
+		t.Errorf("heading = %+v", blocks[0])
+	}
+	table := blocks[1]
+	if strings.Join(table.Columns, ",") != "Field,Value" || len(table.Rows) != 2 {
+		t.Fatalf("table = %+v", table)
+	}
+	if table.Rows[0]["Field"] != "Provider" || table.Rows[1]["Value"] != "gpt-5" {
+		t.Errorf("cells should drop emphasis, code, and ★ markers: %+v", table.Rows)
+	}
+	if blocks[2].Text != "→ USE IN CODE: gpt-5\nSecond line" {
+		t.Errorf("text = %q", blocks[2].Text)
+	}
+}
+
+func TestApply(t *testing.T) {
+	if out, err := Apply(sample, ""); err != nil || out != sample {
+		t.Errorf("default format should pass markdown through, got %q, %v", out, err)
+	}
+
+	out, err := Apply(sample, "JSON")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct{ Blocks []Block }
+	if err := json.Unmarshal([]byte(out), &doc); err != nil || len(doc.Blocks) != 3 {
+		t.Errorf("json output = %s (%v)", out, err)
+	}
+
+	out, err = Apply(sample, "compact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "GPT-5 (gpt-5)\nField|Value\nProvider|OpenAI\nModel ID|gpt-5\n→ USE IN CODE: gpt-5\nSecond line"
+	if out != want {
+		t.Errorf("compact = %q, want %q", out, want)
+	}
+
+	if _, err := Apply(sample, "yaml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestToHTML(t *testing.T) {
+	out, err := Apply(sample+"\n\n- first <b>\n- second", "html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<h2>GPT-5 (gpt-5)</h2>",
+		"<thead><tr><th>Field</th><th>Value</th></tr></thead>",
+		"<tr><td>Model ID</td><td>gpt-5</td></tr>",
+		"<p>→ USE IN CODE: gpt-5<br>\nSecond line</p>",
+		"<ul>\n<li>first &lt;b&gt;</li>\n<li>second</li>\n</ul>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestBarChart(t *testing.T) {
+	svg := BarChart("Price <1M>", []string{"a", "b"}, []Series{
+		{Name: "Input", Color: "#111", Values: []float64{1, 4}},
+		{Name: "Output", Color: "#222", Values: []float64{2, 8}},
+	}, "$%.2f")
+	if !strings.HasPrefix(svg, "<svg xmlns=\"http://www.w3.org/2000/svg\"") || !strings.HasSuffix(svg, "</svg>") {
+		t.Fatalf("not an SVG document:\n%s", svg)
+	}
+	if strings.Count(svg, "<title>") != 4 || !strings.Contains(svg, "<title>Output: $8.00</title>") {
+		t.Errorf("expected one titled bar per value:\n%s", svg)
+	}
+	// The largest value fills the plot height; half of it fills half.
+	if !strings.Contains(svg, `height="220" fill="#222"`) || !strings.Contains(svg, `height="110" fill="#111"`) {
+		t.Errorf("bars not scaled to the peak value:\n%s", svg)
+	}
+	if !strings.Contains(svg, "Price &lt;1M&gt;") {
+		t.Error("title should be escaped")
+	}
+}
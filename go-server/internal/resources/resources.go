@@ -3,25 +3,65 @@ package resources
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"slices"
 	"sort"
 	"strings"
 
 	"go-server/internal/models"
 )
 
-// AllModels returns JSON of all models in the registry.
-func AllModels() string {
-	data, err := json.MarshalIndent(models.Models, "", "  ")
-	if err != nil {
+// AllModels returns JSON of all models in ms, normally models.Models or a
+// tenant registry.
+func AllModels(ms map[string]models.Model) string {
+	var b strings.Builder
+	if err := WriteAllModels(&b, ms); err != nil {
 		return fmt.Sprintf(`{"error": %q}`, err.Error())
 	}
-	return string(data)
+	return b.String()
+}
+
+// WriteAllModels writes the same document as AllModels to w one model at a
+// time, in ID order, rather than encoding the whole map as one value. It
+// does not bound memory by itself: AllModels collects the output in a
+// strings.Builder, and Registry.AllModelsJSON keeps that string for every
+// read.
+func WriteAllModels(w io.Writer, ms map[string]models.Model) error {
+	ids := slices.Sorted(maps.Keys(ms))
+	if len(ids) == 0 {
+		_, err := io.WriteString(w, "{}")
+		return err
+	}
+	if _, err := io.WriteString(w, "{"); err != nil {
+		return err
+	}
+	for i, id := range ids {
+		key, err := json.Marshal(id)
+		if err != nil {
+			return err
+		}
+		entry, err := json.MarshalIndent(ms[id], "  ", "  ")
+		if err != nil {
+			return err
+		}
+		sep := ",\n  "
+		if i == 0 {
+			sep = "\n  "
+		}
+		if _, err := fmt.Fprintf(w, "%s%s: %s", sep, key, entry); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "\n}")
+	return err
 }
 
-// CurrentModels returns JSON of only current-status models.
-func CurrentModels() string {
+// CurrentModels returns JSON of only the current-status models in ms.
+func CurrentModels(ms map[string]models.Model) string {
 	current := make(map[string]models.Model)
-	for k, m := range models.Models {
+	for k, m := range ms {
 		if m.Status == "current" {
 			current[k] = m
 		}
@@ -33,30 +73,173 @@ func CurrentModels() string {
 	return string(data)
 }
 
-// PricingSummary returns a markdown pricing table of current models sorted by input price.
-func PricingSummary() string {
+// DeprecatedIDs returns names, the legacy and deprecated model IDs and their
+// aliases, as a flat JSON array.
+func DeprecatedIDs(names []string) string {
+	if names == nil {
+		names = []string{}
+	}
+	data, err := json.MarshalIndent(names, "", "  ")
+	if err != nil {
+		return fmt.Sprintf(`{"error": %q}`, err.Error())
+	}
+	return string(data)
+}
+
+// PricingSummary returns a markdown pricing table of the current models in
+// ms, cheapest input price first.
+func PricingSummary(ms map[string]models.Model) string {
+	return PricingTable(ms, PricingQuery{})
+}
+
+// PricingTemplate is the URI template of the scoped pricing resource. The
+// parameters may come in any order; each is optional.
+const PricingTemplate = "model://registry/pricing{?provider,capability,sort}"
+
+// PricingSorts are the accepted sort orders of the pricing resource. Prices
+// sort cheapest first, context largest first. blended is the input and
+// output price weighted by models.BlendRatio.
+var PricingSorts = []string{"input", "output", "blended", "ratio", "context"}
+
+// PricingQuery scopes the pricing table to one provider and capability and
+// picks its sort order. The zero value is the full table sorted by input
+// price.
+type PricingQuery struct {
+	Provider   string
+	Capability string // a tracked capability or "default"
+	Sort       string // one of PricingSorts; empty means input
+}
+
+// ParsePricingQuery reads the parameters of a model://registry/pricing URI.
+// Unknown parameters, capabilities the registry does not record, and
+// unknown sort orders are errors, so a typo is not mistaken for an empty
+// result.
+func ParsePricingQuery(uri string) (PricingQuery, error) {
+	var q PricingQuery
+	u, err := url.Parse(uri)
+	if err != nil {
+		return q, fmt.Errorf("invalid pricing URI: %w", err)
+	}
+	params, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return q, fmt.Errorf("invalid pricing query: %w", err)
+	}
+	for k, v := range params {
+		val := strings.TrimSpace(v[len(v)-1])
+		switch k {
+		case "provider":
+			q.Provider = val
+		case "capability":
+			q.Capability = strings.ToLower(val)
+		case "sort":
+			q.Sort = strings.ToLower(val)
+		default:
+			return q, fmt.Errorf("unknown pricing parameter %q: use provider, capability, or sort", k)
+		}
+	}
+	if q.Capability != "" && q.Capability != "default" {
+		c, ok := models.ParseCapability(q.Capability)
+		if !ok || !c.Tracked() {
+			return q, fmt.Errorf("capability %q cannot be filtered on: use %s, or default", q.Capability, models.JoinCapabilities(models.TrackedCapabilities()))
+		}
+		q.Capability = string(c)
+	}
+	if q.Sort != "" && !slices.Contains(PricingSorts, q.Sort) {
+		return q, fmt.Errorf("unknown sort %q: use %s", q.Sort, strings.Join(PricingSorts, ", "))
+	}
+	return q, nil
+}
+
+// matches reports whether m belongs in the table for q.
+func (q PricingQuery) matches(m models.Model) bool {
+	if q.Provider != "" && !strings.EqualFold(m.Provider, q.Provider) {
+		return false
+	}
+	switch q.Capability {
+	case "":
+		return true
+	case "default":
+		return m.ProviderDefault
+	default:
+		return m.Has(models.Capability(q.Capability))
+	}
+}
+
+// PricingTable returns a markdown pricing table of the current models in ms
+// that match q, in q's sort order. Ties fall back to model ID.
+func PricingTable(ms map[string]models.Model, q PricingQuery) string {
 	var current []models.Model
-	for _, m := range models.Models {
-		if m.Status == "current" {
+	for _, m := range ms {
+		if m.Status == "current" && q.matches(m) {
 			current = append(current, m)
 		}
 	}
+	if len(current) == 0 {
+		if f := q.describe(); f != "" {
+			return "No current models match " + f + "."
+		}
+		return "No current models."
+	}
+	key := func(m models.Model) float64 {
+		switch q.Sort {
+		case "output":
+			return m.PricingOutput
+		case "blended":
+			return m.BlendedPrice()
+		case "ratio":
+			return m.PriceRatio()
+		case "context":
+			return -float64(m.ContextWindow)
+		default:
+			return m.PricingInput
+		}
+	}
 	sort.SliceStable(current, func(i, j int) bool {
-		if current[i].PricingInput != current[j].PricingInput {
-			return current[i].PricingInput < current[j].PricingInput
+		if ki, kj := key(current[i]), key(current[j]); ki != kj {
+			return ki < kj
 		}
 		return current[i].ID < current[j].ID
 	})
 
 	rows := []string{
-		"| Model ID | Provider | Input $/1M | Output $/1M | Context |",
-		"|----------|----------|------------|-------------|---------|",
+		"| Model ID | Provider | Input $/1M | Output $/1M | Blended $/1M (" + models.BlendLabel() + " in:out) | Out/In | Context | Cached input $/1M | Batch in/out $/1M |",
+		"|----------|----------|------------|-------------|--------------------|--------|---------|-------------------|-------------------|",
 	}
 	for _, m := range current {
 		rows = append(rows, fmt.Sprintf(
-			"| %s | %s | $%.2f | $%.2f | %s |",
-			m.ID, m.Provider, m.PricingInput, m.PricingOutput, models.FormatInt(m.ContextWindow),
+			"| %s | %s | $%.2f | $%.2f | $%.2f | %s | %s | %s | %s |",
+			m.ID, m.Provider, m.PricingInput, m.PricingOutput, m.BlendedPrice(), models.FormatRatio(m.PriceRatio()), models.FormatInt(m.ContextWindow),
+			cachedPrice(m), batchPrices(m),
 		))
 	}
 	return strings.Join(rows, "\n")
 }
+
+// cachedPrice renders m's cached-input rate, or "—" if none is recorded.
+func cachedPrice(m models.Model) string {
+	if p, ok := m.CachedInputPricing(); ok {
+		return models.FormatPrice(p)
+	}
+	return "—"
+}
+
+// batchPrices renders m's batch API rates, or "—" without a batch API.
+func batchPrices(m models.Model) string {
+	in, out, ok := m.BatchPricing()
+	if !ok {
+		return "—"
+	}
+	return models.FormatPrice(in) + " / " + models.FormatPrice(out)
+}
+
+// describe renders q's filters for the empty-result message.
+func (q PricingQuery) describe() string {
+	var parts []string
+	if q.Provider != "" {
+		parts = append(parts, "provider="+q.Provider)
+	}
+	if q.Capability != "" {
+		parts = append(parts, "capability="+q.Capability)
+	}
+	return strings.Join(parts, " and ")
+}
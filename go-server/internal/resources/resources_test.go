@@ -2,14 +2,18 @@ package resources
 
 import (
 	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"go-server/internal/models"
 )
 
 func TestAllModels_ReturnsValidJSON(t *testing.T) {
-	result := AllModels()
+	result := AllModels(models.Models)
 	var parsed map[string]models.Model
 	if err := json.Unmarshal([]byte(result), &parsed); err != nil {
 		t.Fatalf("AllModels() returned invalid JSON: %v", err)
@@ -20,7 +24,7 @@ func TestAllModels_ReturnsValidJSON(t *testing.T) {
 }
 
 func TestAllModels_ContainsAllModelIDs(t *testing.T) {
-	result := AllModels()
+	result := AllModels(models.Models)
 	for id := range models.Models {
 		if !strings.Contains(result, id) {
 			t.Errorf("AllModels() missing model ID %q", id)
@@ -28,8 +32,24 @@ func TestAllModels_ContainsAllModelIDs(t *testing.T) {
 	}
 }
 
+func TestWriteAllModels_MatchesMarshalIndent(t *testing.T) {
+	for _, ms := range []map[string]models.Model{models.Models, {}} {
+		want, err := json.MarshalIndent(ms, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b strings.Builder
+		if err := WriteAllModels(&b, ms); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != string(want) {
+			t.Errorf("WriteAllModels() over %d models differs from json.MarshalIndent", len(ms))
+		}
+	}
+}
+
 func TestCurrentModels_ReturnsValidJSON(t *testing.T) {
-	result := CurrentModels()
+	result := CurrentModels(models.Models)
 	var parsed map[string]models.Model
 	if err := json.Unmarshal([]byte(result), &parsed); err != nil {
 		t.Fatalf("CurrentModels() returned invalid JSON: %v", err)
@@ -42,7 +62,7 @@ func TestCurrentModels_ReturnsValidJSON(t *testing.T) {
 }
 
 func TestCurrentModels_ExcludesLegacyAndDeprecated(t *testing.T) {
-	result := CurrentModels()
+	result := CurrentModels(models.Models)
 	var parsed map[string]models.Model
 	if err := json.Unmarshal([]byte(result), &parsed); err != nil {
 		t.Fatalf("CurrentModels() returned invalid JSON: %v", err)
@@ -62,7 +82,7 @@ func TestCurrentModels_ExcludesLegacyAndDeprecated(t *testing.T) {
 }
 
 func TestPricingSummary_ReturnsMarkdownTable(t *testing.T) {
-	result := PricingSummary()
+	result := PricingSummary(models.Models)
 	if !strings.Contains(result, "Model ID") {
 		t.Error("expected 'Model ID' header in pricing summary")
 	}
@@ -75,7 +95,7 @@ func TestPricingSummary_ReturnsMarkdownTable(t *testing.T) {
 }
 
 func TestPricingSummary_OnlyCurrentModels(t *testing.T) {
-	result := PricingSummary()
+	result := PricingSummary(models.Models)
 	for id, m := range models.Models {
 		if m.Status != "current" {
 			if strings.Contains(result, "| "+id+" |") {
@@ -86,7 +106,7 @@ func TestPricingSummary_OnlyCurrentModels(t *testing.T) {
 }
 
 func TestPricingSummary_SortedByInputPrice(t *testing.T) {
-	result := PricingSummary()
+	result := PricingSummary(models.Models)
 	lines := strings.Split(result, "\n")
 	var prices []float64
 	for _, line := range lines[2:] { // skip header and separator
@@ -114,6 +134,77 @@ func TestPricingSummary_SortedByInputPrice(t *testing.T) {
 	}
 }
 
+func TestParsePricingQuery(t *testing.T) {
+	q, err := ParsePricingQuery("model://registry/pricing?sort=Context&capability=Vision&provider=google")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q != (PricingQuery{Provider: "google", Capability: "vision", Sort: "context"}) {
+		t.Errorf("parameters in any order should parse: %+v", q)
+	}
+	if q, err := ParsePricingQuery("model://registry/pricing"); err != nil || q != (PricingQuery{}) {
+		t.Errorf("no parameters should be the full table: %+v, %v", q, err)
+	}
+	for _, uri := range []string{
+		"model://registry/pricing?vendor=openai",
+		"model://registry/pricing?sort=cheapest",
+		"model://registry/pricing?capability=teleportation",
+		"model://registry/pricing?capability=audio_in", // known, not recorded yet
+	} {
+		if _, err := ParsePricingQuery(uri); err == nil {
+			t.Errorf("ParsePricingQuery(%q): expected an error", uri)
+		}
+	}
+}
+
+func TestPricingTable_Query(t *testing.T) {
+	rows := func(table string) []string {
+		var ids []string
+		for _, line := range strings.Split(table, "\n")[2:] {
+			ids = append(ids, strings.TrimSpace(strings.Split(line, "|")[1]))
+		}
+		return ids
+	}
+
+	vision := PricingTable(models.Models, PricingQuery{Provider: "OPENAI", Capability: "vision"})
+	for _, id := range rows(vision) {
+		if m := models.Models[id]; m.Provider != "OpenAI" || !m.Vision {
+			t.Errorf("%s should not be in the OpenAI vision table", id)
+		}
+	}
+
+	byContext := rows(PricingTable(models.Models, PricingQuery{Sort: "context"}))
+	for i := 1; i < len(byContext); i++ {
+		if models.Models[byContext[i]].ContextWindow > models.Models[byContext[i-1]].ContextWindow {
+			t.Errorf("context not sorted largest first: %s after %s", byContext[i], byContext[i-1])
+		}
+	}
+
+	byOutput := rows(PricingTable(models.Models, PricingQuery{Sort: "output"}))
+	for i := 1; i < len(byOutput); i++ {
+		if models.Models[byOutput[i]].PricingOutput < models.Models[byOutput[i-1]].PricingOutput {
+			t.Errorf("output price not sorted cheapest first: %s after %s", byOutput[i], byOutput[i-1])
+		}
+	}
+
+	byBlended := rows(PricingTable(models.Models, PricingQuery{Sort: "blended"}))
+	for i := 1; i < len(byBlended); i++ {
+		if models.Models[byBlended[i]].BlendedPrice() < models.Models[byBlended[i-1]].BlendedPrice() {
+			t.Errorf("blended price not sorted cheapest first: %s after %s", byBlended[i], byBlended[i-1])
+		}
+	}
+
+	for _, id := range rows(PricingTable(models.Models, PricingQuery{Capability: "default"})) {
+		if !models.Models[id].ProviderDefault {
+			t.Errorf("%s is not a provider default", id)
+		}
+	}
+
+	if got := PricingTable(models.Models, PricingQuery{Provider: "nobody"}); got != "No current models match provider=nobody." {
+		t.Errorf("unexpected empty result: %q", got)
+	}
+}
+
 func TestFormatInt_Resources(t *testing.T) {
 	tests := []struct {
 		input int
@@ -131,3 +222,100 @@ func TestFormatInt_Resources(t *testing.T) {
 		}
 	}
 }
+
+func TestPricingSummary_PriceRatioColumn(t *testing.T) {
+	result := PricingSummary(models.Models)
+	if !strings.Contains(result, "| Out/In |") {
+		t.Fatal("expected Out/In header in pricing summary")
+	}
+	m := models.Models["gpt-5"]
+	want := fmt.Sprintf("| gpt-5 | OpenAI | $%.2f | $%.2f | $%.2f | %s |", m.PricingInput, m.PricingOutput, m.BlendedPrice(), models.FormatRatio(m.PriceRatio()))
+	if !strings.Contains(result, want) {
+		t.Errorf("expected %q in pricing summary", want)
+	}
+}
+
+func TestPricingSummary_CachedAndBatchColumns(t *testing.T) {
+	result := PricingSummary(models.Models)
+	if !strings.Contains(result, "| Cached input $/1M | Batch in/out $/1M |") {
+		t.Fatal("expected cached-input and batch headers in pricing summary")
+	}
+	if !strings.Contains(result, "| $0.125 | $0.625 / $5.00 |") {
+		t.Errorf("expected gpt-5's cached and batch rates in pricing summary")
+	}
+	if !strings.Contains(result, "| deepseek-chat |") || !strings.Contains(result, "| $0.028 | — |") {
+		t.Errorf("expected deepseek-chat's cached rate and no batch API in pricing summary")
+	}
+}
+
+func TestSyncStatusReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status.json")
+	os.WriteFile(path, []byte(`{
+  "run_id": "run-9",
+  "finished_at": "2026-03-10T06:00:00Z",
+  "providers": {
+    "OpenAI": {"checked_at": "2026-03-10T06:00:00Z", "verified_at": "2026-03-10T06:00:00Z", "source": "api", "scraped": 40, "new": ["gpt-9"]},
+    "Google": {"checked_at": "2026-03-10T06:00:00Z", "verified_at": "2026-02-20T06:00:00Z", "source": "docs", "scraped": 25, "error": "all 3 attempts failed"}
+  }
+}`), 0o644)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	result := SyncStatusReport(models.Models, path, now)
+	for _, want := range []string{
+		"`run-9`",
+		"| OpenAI | 2026-03-10 (6h ago) | api | 40 | 1 new, 0 missing | ok |",
+		"| Google | 2026-02-20 (18 days ago) ⚠ | docs | 25 | none | failed: all 3 attempts failed |",
+		"**Stale:** Google",
+		"- **OpenAI** new: `gpt-9`",
+		"**Not checked by the updater:**",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("missing %q in:\n%s", want, result)
+		}
+	}
+}
+
+func TestCachedSyncStatus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status.json")
+	if err := os.WriteFile(path, []byte(`{"run_id": "run-1"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	first, err := CachedSyncStatus(path)
+	if err != nil || first.RunID != "run-1" {
+		t.Fatalf("CachedSyncStatus = %+v, %v", first, err)
+	}
+	if again, _ := CachedSyncStatus(path); again != first {
+		t.Error("an unchanged file should be served from the cache")
+	}
+	if err := os.WriteFile(path, []byte(`{"run_id": "run-22"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if next, err := CachedSyncStatus(path); err != nil || next.RunID != "run-22" {
+		t.Errorf("a rewritten file should be reread, got %+v, %v", next, err)
+	}
+	if _, err := CachedSyncStatus(filepath.Join(t.TempDir(), "nope.json")); err == nil {
+		t.Error("a missing file should be an error")
+	}
+}
+
+func TestSyncStatusReport_Unavailable(t *testing.T) {
+	if got := SyncStatusReport(models.Models, "", time.Now()); !strings.Contains(got, "No updater status is configured") {
+		t.Errorf("unexpected report: %s", got)
+	}
+	missing := filepath.Join(t.TempDir(), "nope.json")
+	if got := SyncStatusReport(models.Models, missing, time.Now()); !strings.Contains(got, "not written a status file yet") {
+		t.Errorf("unexpected report: %s", got)
+	}
+}
+
+func TestDeprecatedIDs_FlatArray(t *testing.T) {
+	var got []string
+	if err := json.Unmarshal([]byte(DeprecatedIDs([]string{"gpt-4", "openai/gpt-4"})), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(got) != 2 || got[1] != "openai/gpt-4" {
+		t.Errorf("got %v", got)
+	}
+	if DeprecatedIDs(nil) != "[]" {
+		t.Errorf("an empty list should be [], got %s", DeprecatedIDs(nil))
+	}
+}
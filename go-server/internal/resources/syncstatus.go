@@ -0,0 +1,200 @@
+package resources
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-server/internal/models"
+)
+
+// StaleAfter is how long after its last verification a provider's data is
+// flagged as stale. The updater runs daily, so a week means several runs in a
+// row have failed or the updater has stopped.
+const StaleAfter = 7 * 24 * time.Hour
+
+// SyncStatus is the updater's last-run report (UPDATER_STATUS_FILE). The
+// server only reads it.
+type SyncStatus struct {
+	RunID      string                  `json:"run_id"`
+	FinishedAt time.Time               `json:"finished_at"`
+	Providers  map[string]ProviderSync `json:"providers"`
+}
+
+// ProviderSync is one provider's outcome in the updater's last run.
+// VerifiedAt is the last run that checked the provider successfully; Error
+// is set when the last run could not.
+type ProviderSync struct {
+	CheckedAt  time.Time `json:"checked_at"`
+	VerifiedAt time.Time `json:"verified_at"`
+	Source     string    `json:"source"`
+	Scraped    int       `json:"scraped"`
+	New        []string  `json:"new"`
+	Missing    []string  `json:"missing"`
+	Error      string    `json:"error"`
+}
+
+// LoadSyncStatus reads an updater status file.
+func LoadSyncStatus(path string) (*SyncStatus, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s SyncStatus
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse sync status %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// syncStatusCache is the last status file CachedSyncStatus read.
+var syncStatusCache struct {
+	sync.Mutex
+	path    string
+	modTime time.Time
+	size    int64
+	status  *SyncStatus
+}
+
+// CachedSyncStatus is LoadSyncStatus that rereads the file only when its
+// path, size, or modification time changed since the last call, so tools can
+// consult it on every call. Callers must not modify the result.
+func CachedSyncStatus(path string) (*SyncStatus, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &syncStatusCache
+	c.Lock()
+	defer c.Unlock()
+	if c.status != nil && c.path == path && c.size == info.Size() && c.modTime.Equal(info.ModTime()) {
+		return c.status, nil
+	}
+	s, err := LoadSyncStatus(path)
+	if err != nil {
+		return nil, err
+	}
+	c.path, c.modTime, c.size, c.status = path, info.ModTime(), info.Size(), s
+	return s, nil
+}
+
+// SyncStatusReport renders when each provider in ms was last verified by the
+// updater and its unresolved drift, read from the status file at path. The
+// file is checked on every call so a long-running server picks up each new
+// updater run.
+func SyncStatusReport(ms map[string]models.Model, path string, now time.Time) string {
+	if path == "" {
+		return "# Registry Sync Status\n\nNo updater status is configured. Set `sync_status_file` (or `MCP_SYNC_STATUS_FILE`) to the updater's `UPDATER_STATUS_FILE`."
+	}
+	s, err := CachedSyncStatus(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "# Registry Sync Status\n\nThe updater has not written a status file yet. Treat every provider's data as unverified."
+	}
+	if err != nil {
+		return fmt.Sprintf("# Registry Sync Status\n\nCould not read the updater status: %v", err)
+	}
+
+	providerSet := make(map[string]bool)
+	for _, m := range ms {
+		providerSet[m.Provider] = true
+	}
+	for p := range s.Providers {
+		providerSet[p] = true
+	}
+	providers := make([]string, 0, len(providerSet))
+	for p := range providerSet {
+		providers = append(providers, p)
+	}
+	sort.Strings(providers)
+
+	lines := []string{
+		"# Registry Sync Status",
+		"",
+		fmt.Sprintf("Last updater run: `%s`, finished %s (%s).", s.RunID, s.FinishedAt.UTC().Format(time.RFC3339), age(now.Sub(s.FinishedAt))),
+		"",
+		"| Provider | Last verified | Source | IDs scraped | Unresolved drift | Last run |",
+		"|----------|---------------|--------|-------------|------------------|----------|",
+	}
+	var stale, unchecked []string
+	var drift []string
+	for _, name := range providers {
+		p, ok := s.Providers[name]
+		if !ok {
+			unchecked = append(unchecked, name)
+			continue
+		}
+		verified := "never"
+		if !p.VerifiedAt.IsZero() {
+			verified = fmt.Sprintf("%s (%s)", p.VerifiedAt.UTC().Format("2006-01-02"), age(now.Sub(p.VerifiedAt)))
+		}
+		if p.VerifiedAt.IsZero() || now.Sub(p.VerifiedAt) > StaleAfter {
+			stale = append(stale, name)
+			verified += " ⚠"
+		}
+		last := "ok"
+		if p.Error != "" {
+			last = "failed: " + p.Error
+		}
+		pending := "none"
+		if len(p.New) > 0 || len(p.Missing) > 0 {
+			pending = fmt.Sprintf("%d new, %d missing", len(p.New), len(p.Missing))
+			drift = append(drift, fmt.Sprintf("- **%s**%s%s", name, idList(" new: ", p.New), idList(" missing: ", p.Missing)))
+		}
+		lines = append(lines, fmt.Sprintf("| %s | %s | %s | %d | %s | %s |",
+			name, verified, orDash(p.Source), p.Scraped, pending, last))
+	}
+
+	if len(stale) > 0 {
+		lines = append(lines, "", fmt.Sprintf("**Stale:** %s not verified in the last %d days. Caveat answers about these providers: newer models may exist and listed ones may have been retired.",
+			strings.Join(stale, ", "), int(StaleAfter.Hours()/24)))
+	}
+	if len(drift) > 0 {
+		lines = append(lines, "", "## Unresolved Drift", "",
+			"Model IDs the provider lists that the registry lacks (new), or the registry lists that the provider no longer does (missing).", "")
+		lines = append(lines, drift...)
+	}
+	if len(unchecked) > 0 {
+		lines = append(lines, "", fmt.Sprintf("**Not checked by the updater:** %s. These providers have no scrapable model listing, so their entries are maintained by hand.",
+			strings.Join(unchecked, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// age renders a duration as a coarse "N days ago".
+func age(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return "just now"
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 48*time.Hour:
+		return "1 day ago"
+	default:
+		return fmt.Sprintf("%d days ago", int(d.Hours()/24))
+	}
+}
+
+// idList renders ids as a labelled list of code spans, or "" when empty.
+func idList(label string, ids []string) string {
+	if len(ids) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(ids))
+	for i, id := range ids {
+		quoted[i] = "`" + id + "`"
+	}
+	return label + strings.Join(quoted, ", ")
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "—"
+	}
+	return s
+}
@@ -0,0 +1,324 @@
+// Package status fetches provider status pages and reports active incidents.
+//
+// This is the only part of the server that makes outbound calls, and only
+// when the check_provider_status tool (or recommend_model with
+// avoid_outages) is used. Results are cached per provider so repeated
+// tool calls don't hammer the status pages.
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Indicator values, ordered by severity. They follow the Atlassian
+// Statuspage vocabulary, which most provider status pages use.
+const (
+	IndicatorNone     = "none"
+	IndicatorMinor    = "minor"
+	IndicatorMajor    = "major"
+	IndicatorCritical = "critical"
+	IndicatorUnknown  = "unknown"
+)
+
+// FeedKind selects how a status feed is parsed.
+type FeedKind int
+
+const (
+	// Statuspage is an Atlassian Statuspage /api/v2/summary.json feed.
+	Statuspage FeedKind = iota
+	// GoogleCloud is the Google Cloud incidents.json feed, filtered to AI products.
+	GoogleCloud
+)
+
+// Feed describes where to read a provider's status.
+type Feed struct {
+	URL  string
+	Page string // human-facing status page
+	Kind FeedKind
+}
+
+// DefaultFeeds maps registry provider names to their public status feeds.
+var DefaultFeeds = map[string]Feed{
+	"OpenAI": {
+		URL:  "https://status.openai.com/api/v2/summary.json",
+		Page: "https://status.openai.com",
+		Kind: Statuspage,
+	},
+	"Anthropic": {
+		URL:  "https://status.anthropic.com/api/v2/summary.json",
+		Page: "https://status.anthropic.com",
+		Kind: Statuspage,
+	},
+	"Google": {
+		URL:  "https://status.cloud.google.com/incidents.json",
+		Page: "https://status.cloud.google.com",
+		Kind: GoogleCloud,
+	},
+}
+
+// Incident is a single active incident.
+type Incident struct {
+	Name   string
+	Impact string
+	Status string
+	URL    string
+}
+
+// ProviderStatus is the current status of one provider.
+type ProviderStatus struct {
+	Provider    string
+	Indicator   string
+	Description string
+	Page        string
+	Incidents   []Incident
+	CheckedAt   time.Time
+	Err         error
+}
+
+// HasOutage reports whether the provider has a major or critical incident.
+func (s ProviderStatus) HasOutage() bool {
+	return s.Indicator == IndicatorMajor || s.Indicator == IndicatorCritical
+}
+
+// errorTTL caps how long a failed fetch is cached, so a status page that
+// was briefly unreachable doesn't read as "unknown" for the full TTL.
+const errorTTL = 15 * time.Second
+
+// Checker fetches and caches provider status.
+type Checker struct {
+	client *http.Client
+	ttl    time.Duration
+	feeds  map[string]Feed
+
+	mu    sync.Mutex
+	cache map[string]ProviderStatus
+}
+
+// NewChecker returns a Checker using feeds, caching results for ttl.
+func NewChecker(client *http.Client, ttl time.Duration, feeds map[string]Feed) *Checker {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &Checker{
+		client: client,
+		ttl:    ttl,
+		feeds:  feeds,
+		cache:  make(map[string]ProviderStatus),
+	}
+}
+
+// Providers returns the provider names that have a status feed, sorted.
+func (c *Checker) Providers() []string {
+	names := make([]string, 0, len(c.feeds))
+	for p := range c.feeds {
+		names = append(names, p)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Check returns the status for provider (case-insensitive). ok is false if
+// no feed is configured for it.
+func (c *Checker) Check(ctx context.Context, provider string) (ProviderStatus, bool) {
+	for name, feed := range c.feeds {
+		if strings.EqualFold(name, provider) {
+			return c.check(ctx, name, feed), true
+		}
+	}
+	return ProviderStatus{}, false
+}
+
+// CheckAll returns the status of every configured provider, sorted by name.
+func (c *Checker) CheckAll(ctx context.Context) []ProviderStatus {
+	names := c.Providers()
+	out := make([]ProviderStatus, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			out[i] = c.check(ctx, name, c.feeds[name])
+		}(i, name)
+	}
+	wg.Wait()
+	return out
+}
+
+// OutageProviders returns the providers currently reporting a major or
+// critical incident.
+func (c *Checker) OutageProviders(ctx context.Context) []string {
+	var out []string
+	for _, s := range c.CheckAll(ctx) {
+		if s.HasOutage() {
+			out = append(out, s.Provider)
+		}
+	}
+	return out
+}
+
+func (c *Checker) check(ctx context.Context, name string, feed Feed) ProviderStatus {
+	c.mu.Lock()
+	cached, ok := c.cache[name]
+	c.mu.Unlock()
+	if ok && time.Since(cached.CheckedAt) < c.cacheFor(cached) {
+		return cached
+	}
+
+	s := ProviderStatus{Provider: name, Page: feed.Page, CheckedAt: time.Now()}
+	body, err := c.fetch(ctx, feed.URL)
+	if err == nil {
+		switch feed.Kind {
+		case GoogleCloud:
+			err = parseGoogleCloud(body, &s)
+		default:
+			err = parseStatuspage(body, &s)
+		}
+	}
+	if err != nil {
+		s.Indicator = IndicatorUnknown
+		s.Description = "Status unavailable"
+		s.Err = err
+	}
+
+	c.mu.Lock()
+	c.cache[name] = s
+	c.mu.Unlock()
+	return s
+}
+
+// cacheFor returns how long s stays cached: the TTL, or at most errorTTL
+// when the fetch failed.
+func (c *Checker) cacheFor(s ProviderStatus) time.Duration {
+	if s.Err != nil {
+		return min(c.ttl, errorTTL)
+	}
+	return c.ttl
+}
+
+func (c *Checker) fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d from %s", resp.StatusCode, url)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 2*1024*1024))
+}
+
+// parseStatuspage reads an Atlassian Statuspage summary.json document.
+func parseStatuspage(body []byte, s *ProviderStatus) error {
+	var doc struct {
+		Status struct {
+			Indicator   string `json:"indicator"`
+			Description string `json:"description"`
+		} `json:"status"`
+		Incidents []struct {
+			Name      string `json:"name"`
+			Status    string `json:"status"`
+			Impact    string `json:"impact"`
+			Shortlink string `json:"shortlink"`
+		} `json:"incidents"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return fmt.Errorf("parse statuspage summary: %w", err)
+	}
+	s.Indicator = doc.Status.Indicator
+	if s.Indicator == "" {
+		s.Indicator = IndicatorUnknown
+	}
+	s.Description = doc.Status.Description
+	for _, inc := range doc.Incidents {
+		if inc.Status == "resolved" || inc.Status == "postmortem" {
+			continue
+		}
+		s.Incidents = append(s.Incidents, Incident{
+			Name: inc.Name, Impact: inc.Impact, Status: inc.Status, URL: inc.Shortlink,
+		})
+	}
+	return nil
+}
+
+// googleAIProducts are substrings of Google Cloud product titles that affect
+// Gemini API availability.
+var googleAIProducts = []string{"Vertex", "Gemini", "Generative AI", "AI Studio"}
+
+// parseGoogleCloud reads the Google Cloud incidents.json feed and keeps only
+// unresolved incidents affecting AI products.
+func parseGoogleCloud(body []byte, s *ProviderStatus) error {
+	var doc []struct {
+		ExternalDesc     string  `json:"external_desc"`
+		End              *string `json:"end"`
+		Severity         string  `json:"severity"`
+		URI              string  `json:"uri"`
+		AffectedProducts []struct {
+			Title string `json:"title"`
+		} `json:"affected_products"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return fmt.Errorf("parse google cloud incidents: %w", err)
+	}
+	s.Indicator = IndicatorNone
+	for _, inc := range doc {
+		if inc.End != nil && *inc.End != "" {
+			continue
+		}
+		relevant := false
+		for _, p := range inc.AffectedProducts {
+			for _, want := range googleAIProducts {
+				if strings.Contains(p.Title, want) {
+					relevant = true
+				}
+			}
+		}
+		if !relevant {
+			continue
+		}
+		impact := IndicatorMinor
+		if inc.Severity == "high" {
+			impact = IndicatorMajor
+		}
+		if severityRank(impact) > severityRank(s.Indicator) {
+			s.Indicator = impact
+		}
+		url := ""
+		if inc.URI != "" {
+			url = "https://status.cloud.google.com/" + strings.TrimPrefix(inc.URI, "/")
+		}
+		s.Incidents = append(s.Incidents, Incident{
+			Name: inc.ExternalDesc, Impact: impact, Status: "ongoing", URL: url,
+		})
+	}
+	if len(s.Incidents) == 0 {
+		s.Description = "No active AI incidents"
+	} else {
+		s.Description = fmt.Sprintf("%d active AI incident(s)", len(s.Incidents))
+	}
+	return nil
+}
+
+func severityRank(indicator string) int {
+	switch indicator {
+	case IndicatorMinor:
+		return 1
+	case IndicatorMajor:
+		return 2
+	case IndicatorCritical:
+		return 3
+	}
+	return 0
+}
@@ -0,0 +1,173 @@
+package status
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const statuspageOK = `{
+  "status": {"indicator": "none", "description": "All Systems Operational"},
+  "incidents": []
+}`
+
+const statuspageOutage = `{
+  "status": {"indicator": "major", "description": "Partial System Outage"},
+  "incidents": [
+    {"name": "Elevated errors on API", "status": "investigating", "impact": "major", "shortlink": "https://stspg.io/abc"},
+    {"name": "Old incident", "status": "resolved", "impact": "minor", "shortlink": "https://stspg.io/old"}
+  ]
+}`
+
+const googleIncidents = `[
+  {"external_desc": "Vertex Gemini API errors", "end": null, "severity": "high", "uri": "incidents/xyz",
+   "affected_products": [{"title": "Vertex Gemini API"}]},
+  {"external_desc": "Cloud SQL latency", "end": null, "severity": "high", "uri": "incidents/sql",
+   "affected_products": [{"title": "Cloud SQL"}]},
+  {"external_desc": "Resolved AI issue", "end": "2026-01-01T00:00:00Z", "severity": "high", "uri": "incidents/old",
+   "affected_products": [{"title": "Vertex AI Studio"}]}
+]`
+
+func serve(body string, hits *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits != nil {
+			atomic.AddInt32(hits, 1)
+		}
+		w.Write([]byte(body))
+	}))
+}
+
+func TestCheck_StatuspageOperational(t *testing.T) {
+	srv := serve(statuspageOK, nil)
+	defer srv.Close()
+
+	c := NewChecker(srv.Client(), time.Minute, map[string]Feed{"OpenAI": {URL: srv.URL, Kind: Statuspage}})
+	s, ok := c.Check(context.Background(), "openai")
+	if !ok {
+		t.Fatal("expected feed for openai (case-insensitive)")
+	}
+	if s.Indicator != IndicatorNone || s.HasOutage() || len(s.Incidents) != 0 {
+		t.Errorf("unexpected status: %+v", s)
+	}
+}
+
+func TestCheck_StatuspageOutage(t *testing.T) {
+	srv := serve(statuspageOutage, nil)
+	defer srv.Close()
+
+	c := NewChecker(srv.Client(), time.Minute, map[string]Feed{"Anthropic": {URL: srv.URL, Kind: Statuspage}})
+	s, _ := c.Check(context.Background(), "Anthropic")
+	if !s.HasOutage() {
+		t.Errorf("expected outage, got indicator %q", s.Indicator)
+	}
+	if len(s.Incidents) != 1 || s.Incidents[0].Name != "Elevated errors on API" {
+		t.Errorf("expected only the unresolved incident, got %+v", s.Incidents)
+	}
+}
+
+func TestCheck_GoogleCloudFiltersToAIProducts(t *testing.T) {
+	srv := serve(googleIncidents, nil)
+	defer srv.Close()
+
+	c := NewChecker(srv.Client(), time.Minute, map[string]Feed{"Google": {URL: srv.URL, Kind: GoogleCloud}})
+	s, _ := c.Check(context.Background(), "Google")
+	if len(s.Incidents) != 1 {
+		t.Fatalf("expected 1 active AI incident, got %+v", s.Incidents)
+	}
+	if s.Indicator != IndicatorMajor {
+		t.Errorf("expected major indicator for high severity, got %q", s.Indicator)
+	}
+	if s.Incidents[0].URL != "https://status.cloud.google.com/incidents/xyz" {
+		t.Errorf("unexpected incident URL %q", s.Incidents[0].URL)
+	}
+}
+
+func TestCheck_FetchErrorIsUnknown(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	c := NewChecker(srv.Client(), time.Minute, map[string]Feed{"OpenAI": {URL: srv.URL}})
+	s, _ := c.Check(context.Background(), "OpenAI")
+	if s.Indicator != IndicatorUnknown || s.Err == nil {
+		t.Errorf("expected unknown status with error, got %+v", s)
+	}
+	if s.HasOutage() {
+		t.Error("an unreachable status page should not count as an outage")
+	}
+}
+
+func TestCheck_UnknownProvider(t *testing.T) {
+	c := NewChecker(nil, time.Minute, map[string]Feed{})
+	if _, ok := c.Check(context.Background(), "Nobody"); ok {
+		t.Error("expected ok=false for provider without a feed")
+	}
+}
+
+func TestCheck_CachesWithinTTL(t *testing.T) {
+	var hits int32
+	srv := serve(statuspageOK, &hits)
+	defer srv.Close()
+
+	c := NewChecker(srv.Client(), time.Minute, map[string]Feed{"OpenAI": {URL: srv.URL}})
+	for i := 0; i < 3; i++ {
+		c.Check(context.Background(), "OpenAI")
+	}
+	if hits != 1 {
+		t.Errorf("expected 1 fetch within TTL, got %d", hits)
+	}
+}
+
+func TestCheck_ErrorsExpireBeforeTTL(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(statuspageOK))
+	}))
+	defer srv.Close()
+
+	c := NewChecker(srv.Client(), time.Hour, map[string]Feed{"OpenAI": {URL: srv.URL}})
+	if s, _ := c.Check(context.Background(), "OpenAI"); s.Err == nil {
+		t.Fatal("expected the first fetch to fail")
+	}
+	if s, _ := c.Check(context.Background(), "OpenAI"); s.Err == nil || hits != 1 {
+		t.Errorf("a fresh error should be served from cache, got %d fetches", hits)
+	}
+
+	// Age the cached error past errorTTL but well inside the TTL.
+	c.mu.Lock()
+	s := c.cache["OpenAI"]
+	s.CheckedAt = time.Now().Add(-errorTTL - time.Second)
+	c.cache["OpenAI"] = s
+	c.mu.Unlock()
+
+	if s, _ := c.Check(context.Background(), "OpenAI"); s.Err != nil || s.Indicator != IndicatorNone {
+		t.Errorf("expected a refetch after errorTTL, got %+v", s)
+	}
+	if hits != 2 {
+		t.Errorf("expected 2 fetches, got %d", hits)
+	}
+}
+
+func TestOutageProviders(t *testing.T) {
+	ok := serve(statuspageOK, nil)
+	defer ok.Close()
+	down := serve(statuspageOutage, nil)
+	defer down.Close()
+
+	c := NewChecker(nil, time.Minute, map[string]Feed{
+		"OpenAI":    {URL: ok.URL},
+		"Anthropic": {URL: down.URL},
+	})
+	got := c.OutageProviders(context.Background())
+	if len(got) != 1 || got[0] != "Anthropic" {
+		t.Errorf("expected [Anthropic], got %v", got)
+	}
+}
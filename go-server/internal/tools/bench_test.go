@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"testing"
+
+	"go-server/internal/models"
+)
+
+// Hot-path benchmarks. `make bench` runs them and compares the results with
+// testdata/benchmarks.txt; `make bench-baseline` rewrites that file. Update
+// the baseline in the same change as any refactor that moves these numbers.
+
+// ── Lookup ───────────────────────────────────────────────────────────
+
+func BenchmarkFindModel_Exact(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		BaseRegistry().findModel("claude-sonnet-4-6")
+	}
+}
+
+func BenchmarkFindModel_Alias(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		BaseRegistry().findModel("openai/gpt-5.2")
+	}
+}
+
+func BenchmarkFindModel_PartialCached(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		BaseRegistry().FindModel("haiku-4-5-2025")
+	}
+}
+
+func BenchmarkFindModel_PartialUncached(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		BaseRegistry().findModel("haiku-4-5-2025")
+	}
+}
+
+func BenchmarkSuggestModels_Cached(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		BaseRegistry().SuggestModels("gpt-55", 3)
+	}
+}
+
+func BenchmarkSuggestModels_Uncached(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		BaseRegistry().suggestModels("gpt-55", 3)
+	}
+}
+
+// ── Listing and search ───────────────────────────────────────────────
+
+func BenchmarkFilterModels_All(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		BaseRegistry().FilterModels("", "", "", "", Exclusions{})
+	}
+}
+
+func BenchmarkFilterModels_ProviderCapability(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		BaseRegistry().FilterModels("openai", "current", "reasoning", "", Exclusions{})
+	}
+}
+
+func BenchmarkFilterModels_Sovereignty(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		BaseRegistry().FilterModels("", "", "", "eu", Exclusions{})
+	}
+}
+
+func BenchmarkFormatTable_All(b *testing.B) {
+	ms := make([]models.Model, 0, len(models.Models))
+	for _, m := range models.Models {
+		ms = append(ms, m)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		FormatTable(ms)
+	}
+}
+
+func BenchmarkSearchModels_Keyword(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		BaseRegistry().SearchModels("reasoning", nil)
+	}
+}
+
+func BenchmarkSearchModels_MultiWord(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		BaseRegistry().SearchModels("google flash", nil)
+	}
+}
+
+func BenchmarkSearchModels_NoMatch(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		BaseRegistry().SearchModels("zzz-no-such-model", nil)
+	}
+}
@@ -0,0 +1,187 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"go-server/internal/render"
+)
+
+// DefaultOutputBudget is the maximum tool output size in bytes when no
+// budget is configured. Large outputs (e.g. an unfiltered list_models) can
+// crowd out the client's context window.
+const DefaultOutputBudget = 8 * 1024
+
+// outputBudgets holds the configured default and per-tool budgets.
+type outputBudgets struct {
+	def     int
+	perTool map[string]int
+}
+
+var budgets atomic.Pointer[outputBudgets]
+
+// SetOutputBudgets installs the output budgets: def for every tool, with
+// perTool (keyed by tool name) taking precedence. A value of 0 disables the
+// budget.
+func SetOutputBudgets(def int, perTool map[string]int) {
+	b := &outputBudgets{def: def, perTool: make(map[string]int, len(perTool))}
+	for tool, n := range perTool {
+		b.perTool[strings.ToLower(tool)] = n
+	}
+	budgets.Store(b)
+}
+
+// OutputBudget returns the output size budget in bytes for the named tool,
+// or DefaultOutputBudget when SetOutputBudgets has not been called.
+func OutputBudget(tool string) int {
+	b := budgets.Load()
+	if b == nil {
+		return DefaultOutputBudget
+	}
+	if n, ok := b.perTool[tool]; ok {
+		return n
+	}
+	return b.def
+}
+
+// ApplyBudget shortens text to at most maxBytes. Markdown table rows are
+// dropped from the end of the table first, and any lines after the table
+// (such as the USE IN CODE footer) are kept, followed by a note telling the
+// caller how many rows were omitted. Non-table text is cut at a line
+// boundary. maxBytes <= 0 means no limit.
+func ApplyBudget(text string, maxBytes int) string {
+	if maxBytes <= 0 || len(text) <= maxBytes {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	lastRow := -1
+	for i, l := range lines {
+		if strings.HasPrefix(l, "|") {
+			lastRow = i
+		}
+	}
+
+	// Preserve everything after the final table row (footers).
+	var tail []string
+	body := lines
+	if lastRow >= 0 {
+		tail = lines[lastRow+1:]
+		body = lines[:lastRow+1]
+	}
+
+	tailLen := 0
+	for _, l := range tail {
+		tailLen += len(l) + 1
+	}
+
+	// Reserve room for the truncation note.
+	const noteReserve = 160
+	limit := maxBytes - tailLen - noteReserve
+	if limit < 0 {
+		// Footer alone would blow the budget — fall back to a plain cut.
+		tail = nil
+		limit = maxBytes - noteReserve
+	}
+
+	var kept []string
+	size := 0
+	for _, l := range body {
+		if size+len(l)+1 > limit {
+			break
+		}
+		kept = append(kept, l)
+		size += len(l) + 1
+	}
+
+	omitted := 0
+	omittedLines := len(body) - len(kept)
+	for _, l := range body[len(kept):] {
+		if strings.HasPrefix(l, "|") {
+			omitted++
+		}
+	}
+
+	note := "_" + budgetNote(omitted, omittedLines, maxBytes) + "_"
+	out := append(kept, "", note)
+	out = append(out, tail...)
+	return strings.Join(out, "\n")
+}
+
+// budgetNote tells the caller how much output a budget cut: rows if any
+// table rows went, lines otherwise.
+func budgetNote(rows, lines, maxBytes int) string {
+	if rows > 0 {
+		return fmt.Sprintf("… +%d more rows not shown (output limit %d bytes) — refine filters to narrow results.", rows, maxBytes)
+	}
+	return fmt.Sprintf("… +%d more lines not shown (output limit %d bytes).", lines, maxBytes)
+}
+
+// ApplyRenderedBudget shortens markdown with ApplyBudget until its
+// rendering by render fits in maxBytes, and returns that rendering. Formats
+// render to a different size than the markdown (compact is smaller, html
+// larger), so the budget applies to what the client receives.
+func ApplyRenderedBudget(markdown string, maxBytes int, render func(string) string) string {
+	out := render(markdown)
+	if maxBytes <= 0 || len(out) <= maxBytes {
+		return out
+	}
+	// Start from the budget scaled by the format's size ratio, then tighten
+	// by the overshoot until the rendering fits.
+	limit := maxBytes * len(markdown) / len(out)
+	for range 8 {
+		out = render(ApplyBudget(markdown, limit))
+		if len(out) <= maxBytes || limit <= 0 {
+			break
+		}
+		limit -= len(out) - maxBytes
+	}
+	return out
+}
+
+// ApplyDocumentBudget is ApplyBudget for the json format: it drops rows
+// from the end of doc's tables, last table first, until the encoded
+// document fits in maxBytes, and adds a text block saying how many went.
+// Headings, text, and table columns are kept, so the result parses the
+// same way. Only if that isn't enough are trailing blocks dropped whole.
+func ApplyDocumentBudget(doc render.Document, maxBytes int) render.Document {
+	if maxBytes <= 0 || len(doc.JSON()) <= maxBytes {
+		return doc
+	}
+	blocks := append(slices.Clone(doc.Blocks), render.Block{Type: "text"})
+	noteAt := len(blocks) - 1
+	rows, lines := 0, 0
+	fits := func() bool {
+		blocks[noteAt].Text = budgetNote(rows, lines, maxBytes)
+		return len(render.Document{Blocks: blocks}.JSON()) <= maxBytes
+	}
+	for i := noteAt - 1; i >= 0 && !fits(); i-- {
+		all := blocks[i].Rows
+		if blocks[i].Type != "table" || len(all) == 0 {
+			continue
+		}
+		// The fewest rows to drop from this table, with every later table
+		// already emptied.
+		drop := sort.Search(len(all)+1, func(n int) bool {
+			blocks[i].Rows = all[:len(all)-n]
+			rows += n
+			defer func() { rows -= n }()
+			return fits()
+		})
+		// Past len(all), even an empty table doesn't fit: drop every row
+		// and move on to earlier blocks.
+		drop = min(drop, len(all))
+		blocks[i].Rows = all[:len(all)-drop]
+		rows += drop
+	}
+	for noteAt > 0 && !fits() {
+		dropped := blocks[noteAt-1]
+		lines += strings.Count(dropped.Text, "\n") + 1 + len(dropped.Rows)
+		blocks = append(blocks[:noteAt-1], blocks[noteAt])
+		noteAt--
+	}
+	return render.Document{Blocks: blocks}
+}
@@ -0,0 +1,146 @@
+package tools
+
+import (
+	"container/list"
+	"sort"
+	"strings"
+	"sync"
+
+	"go-server/internal/modelid"
+	"go-server/internal/models"
+)
+
+// lookupCacheSize bounds the number of distinct queries remembered by the
+// FindModel and SuggestModels caches. Agents tend to hammer the same handful
+// of IDs, so a small cache catches nearly all repeat lookups.
+const lookupCacheSize = 512
+
+// lruCache is a minimal thread-safe least-recently-used cache.
+type lruCache[K comparable, V any] struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[K]*list.Element
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+func newLRUCache[K comparable, V any](size int) *lruCache[K, V] {
+	return &lruCache[K, V]{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[K]*list.Element),
+	}
+}
+
+// Get returns the cached value for key and marks it most recently used.
+func (c *lruCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		c.ll.MoveToFront(el)
+		return el.Value.(*lruEntry[K, V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Add stores value under key, evicting the least recently used entry when full.
+func (c *lruCache[K, V]) Add(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		el.Value.(*lruEntry[K, V]).value = value
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&lruEntry[K, V]{key: key, value: value})
+	if c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry[K, V]).key)
+	}
+}
+
+// Len returns the number of cached entries.
+func (c *lruCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// Purge removes all entries.
+func (c *lruCache[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ll.Init()
+	c.items = make(map[K]*list.Element)
+}
+
+// lowerKey pairs a registry key with its lowercase and folded forms.
+type lowerKey struct {
+	id     string
+	lower  string
+	folded string // modelid.Fold
+}
+
+// lowerKeys returns every key in the registry with its lowercase and folded
+// forms, computed once instead of on every lookup.
+func (r *Registry) lowerKeys() []lowerKey {
+	r.lowerKeysOnce.Do(func() {
+		r.lowerKeysList = make([]lowerKey, 0, len(r.models))
+		for key := range r.models {
+			r.lowerKeysList = append(r.lowerKeysList, lowerKey{id: key, lower: strings.ToLower(key), folded: modelid.Fold(key)})
+		}
+	})
+	return r.lowerKeysList
+}
+
+// aliasesByModel returns the alias keys pointing at each model in the
+// registry, sorted, computed once.
+func (r *Registry) aliasesByModel() map[string][]string {
+	r.byModelOnce.Do(func() {
+		r.byModel = make(map[string][]string)
+		for alias, id := range r.aliases {
+			if _, ok := r.models[id]; ok {
+				r.byModel[id] = append(r.byModel[id], strings.ToLower(alias))
+			}
+		}
+		for _, names := range r.byModel {
+			sort.Strings(names)
+		}
+	})
+	return r.byModel
+}
+
+// aliasIndex returns the registry's aliases keyed by their lowercase form,
+// computed once. Where two aliases differ only in case, the lowercase one
+// wins, then the one that sorts first.
+func (r *Registry) aliasIndex() map[string]string {
+	r.aliasIdxOnce.Do(func() {
+		r.aliasIdx = make(map[string]string, len(r.aliases))
+		owner := make(map[string]string, len(r.aliases))
+		for alias, id := range r.aliases {
+			lower := strings.ToLower(alias)
+			if prev, ok := owner[lower]; ok && (prev == lower || (alias != lower && prev < alias)) {
+				continue
+			}
+			owner[lower] = alias
+			r.aliasIdx[lower] = id
+		}
+	})
+	return r.aliasIdx
+}
+
+type findResult struct {
+	model models.Model
+	found bool
+}
+
+type suggestKey struct {
+	input string
+	n     int
+}
@@ -0,0 +1,60 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"go-server/internal/modelid"
+)
+
+// CanonicalizeIDInput holds parameters for the canonicalize_id tool.
+type CanonicalizeIDInput struct {
+	ModelID string `json:"model_id" jsonschema:"A model ID in any vendor or platform form, e.g. us.anthropic.claude-opus-4-6-v1:0, openai/gpt-5.2:free, or claude-opus-4-6@20260205"`
+	FormatInput
+}
+
+// CanonicalizeID maps a vendor- or platform-specific model ID to its
+// canonical registry ID, showing each rewrite rule that fired, and lists the
+// model's IDs on the platforms modelid can derive. Unlike get_model_info it
+// never falls back to partial matching: an ID either canonicalizes exactly
+// or not at all.
+func (r *Registry) CanonicalizeID(id string) string {
+	if strings.TrimSpace(id) == "" {
+		return "Please provide a model ID. Example: `canonicalize_id(model_id=\"us.anthropic.claude-opus-4-6-v1:0\")`"
+	}
+	res := modelid.CanonicalizeWith(id, modelid.LookupIn(r.models))
+
+	var b strings.Builder
+	if !res.Found {
+		fmt.Fprintf(&b, "`%s` does not canonicalize to a registry model.\n", id)
+		writeSteps(&b, "Rewrites tried", res.Steps)
+		if suggestions := r.SuggestModels(id, 3); len(suggestions) > 0 {
+			fmt.Fprintf(&b, "\nDid you mean: %s\n", strings.Join(suggestions, ", "))
+		}
+		return b.String()
+	}
+
+	m := r.models[res.ID]
+	fmt.Fprintf(&b, "## `%s` → `%s`\n\n", id, m.ID)
+	fmt.Fprintf(&b, "%s (%s), status **%s**.\n", m.DisplayName, m.Provider, m.Status)
+	writeSteps(&b, "Rules applied", res.Steps)
+	b.WriteString("\n### Platform IDs\n\n| Platform | ID |\n|----------|----|\n")
+	fmt.Fprintf(&b, "| registry | `%s` |\n", m.ID)
+	for _, p := range modelid.Platforms {
+		if pid, ok := modelid.PlatformID(m, p); ok {
+			fmt.Fprintf(&b, "| %s | `%s` |\n", p, pid)
+		}
+	}
+	return b.String()
+}
+
+// writeSteps renders canonicalization steps as a numbered table.
+func writeSteps(b *strings.Builder, title string, steps []modelid.Step) {
+	if len(steps) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n### %s\n\n| # | Rule | Result |\n|---|------|--------|\n", title)
+	for i, s := range steps {
+		fmt.Fprintf(b, "| %d | %s | `%s` |\n", i+1, s.Rule, s.Result)
+	}
+}
@@ -5,15 +5,18 @@ import (
 	"strings"
 
 	"go-server/internal/models"
+	"go-server/internal/render"
 )
 
 // CompareModelsInput holds parameters for the compare_models tool.
 type CompareModelsInput struct {
 	ModelIDs []string `json:"model_ids" jsonschema:"List of 2-5 model IDs to compare"`
+	Chart    bool     `json:"chart,omitempty" jsonschema:"Also return an SVG bar chart of input and output prices per 1M tokens, for clients that display images"`
+	FormatInput
 }
 
 // CompareModels returns a side-by-side markdown comparison table for 2-5 models.
-func CompareModels(modelIDs []string) string {
+func (r *Registry) CompareModels(modelIDs []string) string {
 	if len(modelIDs) < 2 {
 		return "Please provide at least 2 model IDs to compare."
 	}
@@ -25,7 +28,7 @@ func CompareModels(modelIDs []string) string {
 	var found []models.Model
 	var notFound []string
 	for _, mid := range modelIDs {
-		m, ok := FindModel(mid)
+		m, ok := r.FindModel(mid)
 		if ok {
 			found = append(found, m)
 		} else {
@@ -36,7 +39,7 @@ func CompareModels(modelIDs []string) string {
 	if len(notFound) > 0 {
 		var parts []string
 		for _, nf := range notFound {
-			suggestions := SuggestModels(nf, 3)
+			suggestions := r.SuggestModels(nf, 3)
 			parts = append(parts, fmt.Sprintf("`%s` (did you mean: %s)", nf, strings.Join(suggestions, ", ")))
 		}
 		return fmt.Sprintf("Model(s) not found: %s", strings.Join(parts, "; "))
@@ -58,6 +61,8 @@ func CompareModels(modelIDs []string) string {
 	capabilities := make([]string, len(found))
 	inputPrices := make([]string, len(found))
 	outputPrices := make([]string, len(found))
+	blended := make([]string, len(found))
+	ratios := make([]string, len(found))
 	cutoffs := make([]string, len(found))
 	releases := make([]string, len(found))
 
@@ -69,6 +74,8 @@ func CompareModels(modelIDs []string) string {
 		capabilities[i] = caps(m)
 		inputPrices[i] = fmt.Sprintf("$%.2f", m.PricingInput)
 		outputPrices[i] = fmt.Sprintf("$%.2f", m.PricingOutput)
+		blended[i] = fmt.Sprintf("$%.2f", m.BlendedPrice())
+		ratios[i] = models.FormatRatio(m.PriceRatio())
 		cutoffs[i] = m.KnowledgeCutoff
 		releases[i] = m.ReleaseDate
 	}
@@ -83,13 +90,43 @@ func CompareModels(modelIDs []string) string {
 		"| Capabilities | " + strings.Join(capabilities, " | ") + " |",
 		"| Input $/1M | " + strings.Join(inputPrices, " | ") + " |",
 		"| Output $/1M | " + strings.Join(outputPrices, " | ") + " |",
+		"| Blended $/1M (" + models.BlendLabel() + " in:out) | " + strings.Join(blended, " | ") + " |",
+		"| Output/Input Price | " + strings.Join(ratios, " | ") + " |",
 		"| Knowledge Cutoff | " + strings.Join(cutoffs, " | ") + " |",
 		"| Release Date | " + strings.Join(releases, " | ") + " |",
 	}
+	if note := r.shadowNote(found); note != "" {
+		rows = append(rows, "", note)
+	}
 
 	return strings.Join(rows, "\n")
 }
 
+// CompareChart draws the input and output prices of the models compare_models
+// would show as an SVG bar chart. ok is false when CompareModels would not
+// produce a table (fewer than 2 IDs or an unknown one).
+func (r *Registry) CompareChart(modelIDs []string) (svg string, ok bool) {
+	if len(modelIDs) < 2 {
+		return "", false
+	}
+	if len(modelIDs) > 5 {
+		modelIDs = modelIDs[:5]
+	}
+	labels := make([]string, len(modelIDs))
+	in := render.Series{Name: "Input $/1M", Color: "#4e79a7"}
+	out := render.Series{Name: "Output $/1M", Color: "#f28e2b"}
+	for i, id := range modelIDs {
+		m, found := r.FindModel(id)
+		if !found {
+			return "", false
+		}
+		labels[i] = m.ID
+		in.Values = append(in.Values, m.PricingInput)
+		out.Values = append(out.Values, m.PricingOutput)
+	}
+	return render.BarChart("Price per 1M tokens", labels, []render.Series{in, out}, "$%.2f"), true
+}
+
 // caps returns a comma-separated capability string for a model.
 func caps(m models.Model) string {
 	var c []string
@@ -99,6 +136,15 @@ func caps(m models.Model) string {
 	if m.Reasoning {
 		c = append(c, "Reasoning")
 	}
+	if m.ToolCalling {
+		c = append(c, "Tools")
+	}
+	if m.StructuredOutput {
+		c = append(c, "Structured Output")
+	}
+	if m.JSONMode {
+		c = append(c, "JSON Mode")
+	}
 	if len(c) == 0 {
 		return "None"
 	}
@@ -0,0 +1,341 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go-server/internal/models"
+)
+
+// MonthlyCostProjectionInput holds parameters for the monthly_cost_projection tool.
+type MonthlyCostProjectionInput struct {
+	ModelIDs       []string `json:"model_ids" jsonschema:"List of 1-10 model IDs to project costs for"`
+	RequestsPerDay int      `json:"requests_per_day" jsonschema:"Average API requests per day"`
+	InputTokens    int      `json:"input_tokens" jsonschema:"Average input (prompt) tokens per request"`
+	OutputTokens   int      `json:"output_tokens" jsonschema:"Average output (completion) tokens per request"`
+	Days           int      `json:"days,omitempty" jsonschema:"Days in the projected month (default 30)"`
+	FormatInput
+}
+
+// maxProjectionModels caps how many models one projection covers.
+const maxProjectionModels = 10
+
+// outputDominantShare is the share of a model's projected spend above which
+// output tokens are flagged as the cost driver.
+const outputDominantShare = 0.75
+
+// costProjection is one model's projected spend.
+type costProjection struct {
+	model          models.Model
+	input, output  float64
+	total, savings float64
+}
+
+// MonthlyCostProjection projects monthly spend for each model from a daily
+// request volume and a per-request token profile, cheapest first, with the
+// savings of each model relative to the most expensive candidate.
+func (r *Registry) MonthlyCostProjection(modelIDs []string, requestsPerDay, inputTokens, outputTokens, days int) string {
+	if len(modelIDs) == 0 {
+		return "Please provide at least 1 model ID to project costs for."
+	}
+	if requestsPerDay <= 0 || inputTokens < 0 || outputTokens < 0 || inputTokens+outputTokens == 0 {
+		return "Provide requests_per_day > 0 and a token profile (input_tokens and/or output_tokens per request)."
+	}
+	if days <= 0 {
+		days = 30
+	}
+	if len(modelIDs) > maxProjectionModels {
+		modelIDs = modelIDs[:maxProjectionModels]
+	}
+
+	found, missing := r.resolveCostModels(modelIDs)
+	if missing != "" {
+		return missing
+	}
+
+	requests := float64(requestsPerDay) * float64(days)
+	inMTok := requests * float64(inputTokens) / 1e6
+	outMTok := requests * float64(outputTokens) / 1e6
+
+	projections := make([]costProjection, len(found))
+	maxTotal := 0.0
+	var longContext []string
+	for i, m := range found {
+		inPrice, outPrice, long := m.PricingFor(inputTokens)
+		if long {
+			longContext = append(longContext, fmt.Sprintf("`%s` ($%.2f/$%.2f)", m.ID, inPrice, outPrice))
+		}
+		p := costProjection{model: m, input: inMTok * inPrice, output: outMTok * outPrice}
+		p.total = p.input + p.output
+		maxTotal = max(maxTotal, p.total)
+		projections[i] = p
+	}
+	for i := range projections {
+		projections[i].savings = maxTotal - projections[i].total
+	}
+	sort.SliceStable(projections, func(i, j int) bool {
+		if projections[i].total != projections[j].total {
+			return projections[i].total < projections[j].total
+		}
+		return projections[i].model.ID < projections[j].model.ID
+	})
+
+	rows := []string{
+		fmt.Sprintf("## Projected monthly cost (%d days)", days),
+		"",
+		fmt.Sprintf("%s requests/day × (%s input + %s output tokens) = %s requests, %.1fM input and %.1fM output tokens per month.",
+			models.FormatInt(requestsPerDay), models.FormatInt(inputTokens), models.FormatInt(outputTokens),
+			models.FormatInt(int(requests)), inMTok, outMTok),
+		"",
+		"| Model ID | Provider | Input cost | Output cost | Monthly total | Savings vs most expensive |",
+		"|----------|----------|------------|-------------|---------------|---------------------------|",
+	}
+	for _, p := range projections {
+		rows = append(rows, fmt.Sprintf("| `%s` | %s | %s | %s | **%s** | %s |",
+			p.model.ID, p.model.Provider, formatUSD(p.input), formatUSD(p.output), formatUSD(p.total), savingsDetail(p.savings, maxTotal)))
+	}
+	if warnings := outputCostWarnings(projections); len(warnings) > 0 {
+		rows = append(rows, "")
+		rows = append(rows, warnings...)
+	}
+	if len(longContext) > 0 {
+		rows = append(rows, "", fmt.Sprintf("**Long-context pricing:** %s input tokens per request crosses the breakpoint, so every request to %s bills at the higher per-1M rate shown.",
+			models.FormatInt(inputTokens), strings.Join(longContext, ", ")))
+	}
+	rows = append(rows, "", "_List prices only; excludes batch and cached-input discounts, taxes, and volume deals._")
+	return strings.Join(rows, "\n")
+}
+
+// EstimateCostInput holds parameters for the estimate_cost tool.
+type EstimateCostInput struct {
+	ModelID      string   `json:"model_id,omitempty" jsonschema:"A model ID to estimate cost for; use model_ids to compare several"`
+	ModelIDs     []string `json:"model_ids,omitempty" jsonschema:"List of 1-5 model IDs to estimate and compare costs for"`
+	InputTokens  int      `json:"input_tokens" jsonschema:"Estimated input (prompt) tokens per request"`
+	OutputTokens int      `json:"output_tokens" jsonschema:"Estimated output (completion) tokens per request, including reasoning tokens"`
+	Requests     int      `json:"requests,omitempty" jsonschema:"Number of requests (default 1)"`
+	CacheHitRate float64  `json:"cache_hit_rate,omitempty" jsonschema:"Share of input tokens read from the provider's prompt cache, 0-1 (e.g. 0.8 when a long system prompt is reused); billed at each model's cached-input rate where one is recorded. Default 0"`
+	FormatInput
+}
+
+// maxEstimateModels caps how many models one estimate compares.
+const maxEstimateModels = 5
+
+// CostQuery is the workload estimate_cost prices: Requests requests of
+// InputTokens in and OutputTokens out each, for each of ModelIDs. Zero
+// Requests means one. CacheHitRate of the input tokens is billed at each
+// model's cached-input rate; models without a recorded rate bill all input
+// at list price, and the estimate says which.
+type CostQuery struct {
+	ModelIDs     []string
+	InputTokens  int
+	OutputTokens int
+	Requests     int
+	CacheHitRate float64
+}
+
+// EstimateCost breaks down the cost of q for each model: input, output, and
+// total cost, the cost per request, and the batch API total where one
+// exists. With several models it ranks them cheapest first against the
+// cheapest.
+func (r *Registry) EstimateCost(q CostQuery) string {
+	if len(q.ModelIDs) == 0 {
+		return "Please provide a model ID (model_id) or 1-5 model IDs (model_ids) to estimate cost for."
+	}
+	if q.InputTokens < 0 || q.OutputTokens < 0 || q.InputTokens+q.OutputTokens == 0 || q.Requests < 0 {
+		return "Provide a token estimate (input_tokens and/or output_tokens per request) and, optionally, requests > 0."
+	}
+	if q.CacheHitRate < 0 || q.CacheHitRate > 1 {
+		return fmt.Sprintf("cache_hit_rate must be between 0 and 1, got %g.", q.CacheHitRate)
+	}
+	if q.Requests == 0 {
+		q.Requests = 1
+	}
+	if len(q.ModelIDs) > maxEstimateModels {
+		return fmt.Sprintf("estimate_cost compares at most %d models, got %d (model_id counts as one). Split the call, or drop some of: %s.",
+			maxEstimateModels, len(q.ModelIDs), strings.Join(q.ModelIDs, ", "))
+	}
+	found, missing := r.resolveCostModels(q.ModelIDs)
+	if missing != "" {
+		return missing
+	}
+
+	inTokens := float64(q.Requests) * float64(q.InputTokens)
+	outTokens := float64(q.Requests) * float64(q.OutputTokens)
+
+	estimates := make([]costProjection, len(found))
+	var longContext, uncached []string
+	for i, m := range found {
+		inPrice, outPrice, long := m.PricingFor(q.InputTokens)
+		if long {
+			longContext = append(longContext, fmt.Sprintf("`%s` ($%.2f/$%.2f)", m.ID, inPrice, outPrice))
+		}
+		cachedPrice, ok := m.CachedInputPricingFor(q.InputTokens)
+		if !ok {
+			cachedPrice = inPrice
+			if q.CacheHitRate > 0 {
+				uncached = append(uncached, "`"+m.ID+"`")
+			}
+		}
+		input := inTokens / 1e6 * ((1-q.CacheHitRate)*inPrice + q.CacheHitRate*cachedPrice)
+		e := costProjection{model: m, input: input, output: outTokens / 1e6 * outPrice}
+		e.total = e.input + e.output
+		estimates[i] = e
+	}
+	sort.SliceStable(estimates, func(i, j int) bool {
+		if estimates[i].total != estimates[j].total {
+			return estimates[i].total < estimates[j].total
+		}
+		return estimates[i].model.ID < estimates[j].model.ID
+	})
+
+	summary := fmt.Sprintf("%s request(s) × (%s input + %s output tokens) = %s input and %s output tokens.",
+		models.FormatInt(q.Requests), models.FormatInt(q.InputTokens), models.FormatInt(q.OutputTokens),
+		models.FormatInt(int(inTokens)), models.FormatInt(int(outTokens)))
+	if q.CacheHitRate > 0 {
+		summary += fmt.Sprintf(" %.0f%% of input tokens are cache hits, billed at the cached-input rate.", q.CacheHitRate*100)
+	}
+	rows := []string{"## Estimated cost", "", summary, ""}
+	header := "| Model ID | Provider | Input cost | Output cost | Total | Per request | Batch API total |"
+	sep := "|----------|----------|------------|-------------|-------|-------------|-----------------|"
+	if len(estimates) > 1 {
+		header += " vs cheapest |"
+		sep += "-------------|"
+	}
+	rows = append(rows, header, sep)
+	cheapest := estimates[0].total
+	for _, e := range estimates {
+		row := fmt.Sprintf("| `%s` | %s | %s | %s | **%s** | %s | %s |",
+			e.model.ID, e.model.Provider, formatCost(e.input), formatCost(e.output), formatCost(e.total),
+			formatCost(e.total/float64(q.Requests)), batchTotal(e))
+		if len(estimates) > 1 {
+			row += " " + vsCheapest(e.total, cheapest) + " |"
+		}
+		rows = append(rows, row)
+	}
+	if warnings := outputCostWarnings(estimates); len(warnings) > 0 {
+		rows = append(rows, "")
+		rows = append(rows, warnings...)
+	}
+	if len(longContext) > 0 {
+		rows = append(rows, "", fmt.Sprintf("**Long-context pricing:** %s input tokens per request crosses the breakpoint, so %s bills at the higher per-1M rate shown.",
+			models.FormatInt(q.InputTokens), strings.Join(longContext, ", ")))
+	}
+	if len(uncached) > 0 {
+		rows = append(rows, "", fmt.Sprintf("**No cached-input rate recorded** for %s, so all of its input is billed at list price; check the provider's caching terms.",
+			strings.Join(uncached, ", ")))
+	}
+	if note := r.shadowNote(found); note != "" {
+		rows = append(rows, "", note)
+	}
+	if q.CacheHitRate > 0 {
+		rows = append(rows, "", "_List and cached-input prices; excludes cache write surcharges and storage, taxes, and volume deals. Use monthly_cost_projection for a daily volume._")
+	} else {
+		rows = append(rows, "", "_List prices only; excludes cached-input discounts (set cache_hit_rate), taxes, and volume deals. Use monthly_cost_projection for a daily volume._")
+	}
+	return strings.Join(rows, "\n")
+}
+
+// resolveCostModels looks up each ID, dropping duplicates that resolve to
+// the same model. missing is a not-found message with suggestions when any
+// ID is unknown.
+func (r *Registry) resolveCostModels(modelIDs []string) (found []models.Model, missing string) {
+	var notFound []string
+	seen := make(map[string]bool)
+	for _, mid := range modelIDs {
+		m, ok := r.FindModel(mid)
+		if !ok {
+			notFound = append(notFound, mid)
+			continue
+		}
+		if !seen[m.ID] {
+			seen[m.ID] = true
+			found = append(found, m)
+		}
+	}
+	if len(notFound) > 0 {
+		var parts []string
+		for _, nf := range notFound {
+			suggestions := r.SuggestModels(nf, 3)
+			parts = append(parts, fmt.Sprintf("`%s` (did you mean: %s)", nf, strings.Join(suggestions, ", ")))
+		}
+		return nil, fmt.Sprintf("Model(s) not found: %s", strings.Join(parts, "; "))
+	}
+	return found, ""
+}
+
+// batchTotal renders an estimate's total at the model's batch API rates,
+// or "—" when the model has no batch API. Batch rates scale the estimate's
+// input and output cost by the same fraction they take off list price, so
+// cache hits keep their discount.
+func batchTotal(e costProjection) string {
+	in, out, ok := e.model.BatchPricing()
+	if !ok {
+		return "—"
+	}
+	return formatCost(e.input*batchFraction(in, e.model.PricingInput) + e.output*batchFraction(out, e.model.PricingOutput))
+}
+
+// batchFraction returns batch as a fraction of list, or BatchDiscount for
+// a free list price.
+func batchFraction(batch, list float64) float64 {
+	if list <= 0 {
+		return models.BatchDiscount
+	}
+	return batch / list
+}
+
+// vsCheapest renders how much more a total costs than the cheapest one.
+func vsCheapest(total, cheapest float64) string {
+	if total <= cheapest {
+		return "cheapest"
+	}
+	if cheapest <= 0 {
+		return "+" + formatCost(total-cheapest)
+	}
+	return fmt.Sprintf("+%s (%.1f×)", formatCost(total-cheapest), total/cheapest)
+}
+
+// formatCost renders a dollar amount like formatUSD, keeping four decimal
+// places below a cent so single-request costs don't round to $0.00.
+func formatCost(v float64) string {
+	if v > 0 && v < 0.01 {
+		return fmt.Sprintf("$%.4f", v)
+	}
+	return formatUSD(v)
+}
+
+// outputCostWarnings flags models whose projected spend is mostly output
+// tokens. Users tend to size budgets by prompt length, and reasoning models
+// bill their hidden thinking as output, so these are the projections most
+// likely to be low.
+func outputCostWarnings(projections []costProjection) []string {
+	var warnings []string
+	reasoning := false
+	for _, p := range projections {
+		if p.total <= 0 || p.output/p.total < outputDominantShare {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("**Output-dominated cost:** `%s` spends %.0f%% on output tokens (output is priced %s input).",
+			p.model.ID, p.output/p.total*100, models.FormatRatio(p.model.PriceRatio())))
+		reasoning = reasoning || p.model.Reasoning
+	}
+	if reasoning {
+		warnings = append(warnings, "Reasoning models bill hidden thinking tokens as output, so actual output is often several times the visible answer; size output_tokens accordingly.")
+	}
+	return warnings
+}
+
+// savingsDetail renders savings against the most expensive total.
+func savingsDetail(savings, maxTotal float64) string {
+	if savings <= 0 || maxTotal <= 0 {
+		return "—"
+	}
+	return fmt.Sprintf("%s (%.0f%%)", formatUSD(savings), savings/maxTotal*100)
+}
+
+// formatUSD renders a dollar amount with thousands separators and cents.
+func formatUSD(v float64) string {
+	cents := int64(v*100 + 0.5)
+	return fmt.Sprintf("$%s.%02d", models.FormatInt(int(cents/100)), cents%100)
+}
@@ -0,0 +1,200 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"go-server/internal/models"
+)
+
+// ScrapeHistory is the updater's per-source scrape count history
+// (UPDATER_HISTORY_FILE), keyed by "<provider>/<source>" where source is
+// "api" or "docs". The server only reads it.
+type ScrapeHistory struct {
+	Counts map[string][]ScrapeSample `json:"counts"`
+}
+
+// ScrapeSample is one updater run's scraped ID count.
+type ScrapeSample struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// LoadScrapeHistory reads an updater history file.
+func LoadScrapeHistory(path string) (*ScrapeHistory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var h ScrapeHistory
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("parse scrape history %s: %w", path, err)
+	}
+	return &h, nil
+}
+
+var scrapeHistory atomic.Pointer[ScrapeHistory]
+
+// SetScrapeHistory installs the history get_coverage reports against. nil
+// removes it.
+func SetScrapeHistory(h *ScrapeHistory) {
+	scrapeHistory.Store(h)
+}
+
+// scrapeCount is a provider's most recent scraped count.
+type scrapeCount struct {
+	Source string
+	Date   string
+	Count  int
+}
+
+// latestScrape returns the newest sample for provider across sources. On
+// the same date docs wins over api: API listings include embeddings and
+// other non-chat models, so docs counts compare better with the registry.
+func (h *ScrapeHistory) latestScrape(provider string) (scrapeCount, bool) {
+	var best scrapeCount
+	found := false
+	if h == nil {
+		return best, false
+	}
+	for _, source := range []string{"docs", "api"} {
+		samples := h.Counts[provider+"/"+source]
+		if len(samples) == 0 {
+			continue
+		}
+		s := samples[len(samples)-1]
+		if !found || s.Date > best.Date {
+			best = scrapeCount{Source: source, Date: s.Date, Count: s.Count}
+			found = true
+		}
+	}
+	return best, found
+}
+
+// GetCoverageInput holds parameters for the get_coverage tool.
+type GetCoverageInput struct {
+	Provider string `json:"provider,omitempty" jsonschema:"Only report this provider (case-insensitive). Omit for every provider"`
+	FormatInput
+}
+
+// providerCoverage is one provider's registry count against its scrape.
+type providerCoverage struct {
+	Provider string
+	Tracked  int // every status
+	Active   int // current and legacy, the models a docs page still lists
+	Scrape   scrapeCount
+	Scraped  bool
+}
+
+// percent is Active as a share of the scraped count, capped at 100.
+func (c providerCoverage) percent() int {
+	if c.Scrape.Count == 0 {
+		return 100
+	}
+	return min(100, c.Active*100/c.Scrape.Count)
+}
+
+// coverage computes per-provider coverage, sorted by provider name.
+func (r *Registry) coverage() []providerCoverage {
+	byProvider := make(map[string]*providerCoverage)
+	for _, m := range r.models {
+		c, ok := byProvider[m.Provider]
+		if !ok {
+			c = &providerCoverage{Provider: m.Provider}
+			byProvider[m.Provider] = c
+		}
+		c.Tracked++
+		if m.Status != "deprecated" {
+			c.Active++
+		}
+	}
+	h := scrapeHistory.Load()
+	out := make([]providerCoverage, 0, len(byProvider))
+	for _, c := range byProvider {
+		c.Scrape, c.Scraped = h.latestScrape(c.Provider)
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Provider < out[j].Provider })
+	return out
+}
+
+// GetCoverage reports, per provider, how many models the registry tracks
+// against how many IDs the updater last scraped from that provider's docs or
+// API, so callers can judge how complete the registry is.
+func (r *Registry) GetCoverage(provider string) string {
+	all := r.coverage()
+	rows := all
+	if provider != "" {
+		want := resolveProvider(provider)
+		rows = nil
+		for _, c := range all {
+			if strings.ToLower(c.Provider) == want {
+				rows = append(rows, c)
+			}
+		}
+		if len(rows) == 0 {
+			return fmt.Sprintf("No models tracked for provider '%s'.", provider)
+		}
+	}
+
+	lines := []string{
+		"| Provider | Tracked | Current + Legacy | Scraped | Source | As Of | Coverage |",
+		"|----------|---------|------------------|---------|--------|-------|----------|",
+	}
+	scraped := 0
+	for _, c := range rows {
+		if !c.Scraped {
+			lines = append(lines, fmt.Sprintf("| %s | %d | %d | — | — | — | not scraped |", c.Provider, c.Tracked, c.Active))
+			continue
+		}
+		scraped++
+		lines = append(lines, fmt.Sprintf("| %s | %d | %d | %d | %s | %s | %d%% |",
+			c.Provider, c.Tracked, c.Active, c.Scrape.Count, c.Scrape.Source, c.Scrape.Date, c.percent()))
+	}
+
+	lines = append(lines, "")
+	if scrapeHistory.Load() == nil {
+		lines = append(lines, "No updater history is loaded, so scraped counts are unavailable. Set `coverage_file` (or `MCP_COVERAGE_FILE`) to the updater's `UPDATER_HISTORY_FILE`.")
+	} else {
+		lines = append(lines, "Coverage compares current and legacy models against the IDs the updater last scraped. "+
+			"Below 100% means the provider lists variants (snapshots, regional or fine-tune IDs) the registry doesn't track.")
+		if scraped < len(rows) {
+			lines = append(lines, "\"not scraped\" providers have no scrapable model listing; their coverage is unknown.")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// coverageNote is a one-line coverage summary for provider, or "" when the
+// provider has no scrape history.
+func (r *Registry) coverageNote(provider string) string {
+	h := scrapeHistory.Load()
+	if h == nil {
+		return ""
+	}
+	for _, c := range r.coverage() {
+		if c.Provider == provider && c.Scraped {
+			return fmt.Sprintf("Coverage: %d current and legacy %s models tracked against %d IDs scraped from %s on %s (%d%%). See get_coverage.",
+				c.Active, c.Provider, c.Scrape.Count, c.Scrape.Source, c.Scrape.Date, c.percent())
+		}
+	}
+	return ""
+}
+
+// providerOf returns the single provider all of ms share, or "".
+func providerOf(ms []models.Model) string {
+	if len(ms) == 0 {
+		return ""
+	}
+	p := ms[0].Provider
+	for _, m := range ms[1:] {
+		if m.Provider != p {
+			return ""
+		}
+	}
+	return p
+}
@@ -0,0 +1,228 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"reflect"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"go-server/internal/models"
+)
+
+// DiffRegistriesInput holds parameters for the diff_registries tool.
+type DiffRegistriesInput struct {
+	SnapshotURL string `json:"snapshot_url,omitempty" jsonschema:"HTTPS URL of a registry JSON snapshot (the model://registry/all format) to compare against the live registry"`
+	Snapshot    string `json:"snapshot,omitempty" jsonschema:"Inline registry JSON snapshot to compare against the live registry (used when snapshot_url is empty)"`
+	FormatInput
+}
+
+// maxSnapshotBytes bounds snapshot downloads.
+const maxSnapshotBytes = 8 * 1024 * 1024
+
+// FieldChange is one field that differs between two versions of a model.
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// ModelChange lists the changed fields of a model present in both snapshots.
+type ModelChange struct {
+	ID     string
+	Fields []FieldChange
+}
+
+// RegistryDiff is the difference between two registry snapshots.
+type RegistryDiff struct {
+	Added   []string
+	Removed []string
+	Changed []ModelChange
+}
+
+// Empty reports whether the snapshots are identical.
+func (d RegistryDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// ParseSnapshot decodes a registry snapshot. Both the model://registry/all
+// map format and a plain JSON array of models are accepted.
+func ParseSnapshot(data []byte) (map[string]models.Model, error) {
+	var byID map[string]models.Model
+	if err := json.Unmarshal(data, &byID); err == nil {
+		return byID, nil
+	}
+	var list []models.Model
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("snapshot is neither a model map nor a model array: %w", err)
+	}
+	byID = make(map[string]models.Model, len(list))
+	for _, m := range list {
+		byID[m.ID] = m
+	}
+	return byID, nil
+}
+
+// maxSnapshotRedirects bounds the redirects a snapshot fetch follows.
+const maxSnapshotRedirects = 5
+
+// NewSnapshotClient returns the HTTP client for fetching snapshot_url. The
+// URL comes from an MCP client, so the server must not be usable to reach
+// hosts only it can see: every connection, including each redirect hop, is
+// refused unless it goes to a public address, and redirects must stay on
+// https. Proxies from the environment are ignored, since the check would
+// otherwise see only the proxy's address.
+func NewSnapshotClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: publicAddressOnly}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxSnapshotRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxSnapshotRedirects)
+			}
+			if req.URL.Scheme != "https" {
+				return fmt.Errorf("redirect to non-https URL %s", req.URL.Redacted())
+			}
+			return nil
+		},
+	}
+}
+
+// errPrivateAddress is returned for connections to non-public addresses.
+var errPrivateAddress = errors.New("snapshot_url must resolve to a public address")
+
+// publicAddressOnly is a net.Dialer Control that refuses loopback, private,
+// link-local, multicast, and unspecified addresses. It runs after DNS
+// resolution, on the address actually dialed, so a public name that
+// resolves to an internal address is refused too.
+func publicAddressOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("%w, not %s", errPrivateAddress, ip)
+	}
+	return nil
+}
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which
+// netip.Addr.IsPrivate doesn't cover but cloud providers use internally.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// FetchSnapshot downloads and parses a registry snapshot over HTTP(S).
+func FetchSnapshot(ctx context.Context, client *http.Client, url string) (map[string]models.Model, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d from %s", resp.StatusCode, url)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSnapshotBytes))
+	if err != nil {
+		return nil, err
+	}
+	return ParseSnapshot(data)
+}
+
+// DiffRegistries compares two snapshots field by field, before → after.
+// Field names are the JSON names so the report matches what consumers see
+// in the resources.
+func DiffRegistries(before, after map[string]models.Model) RegistryDiff {
+	var d RegistryDiff
+	for id := range after {
+		if _, ok := before[id]; !ok {
+			d.Added = append(d.Added, id)
+		}
+	}
+	for id, o := range before {
+		n, ok := after[id]
+		if !ok {
+			d.Removed = append(d.Removed, id)
+			continue
+		}
+		if fields := diffModel(o, n); len(fields) > 0 {
+			d.Changed = append(d.Changed, ModelChange{ID: id, Fields: fields})
+		}
+	}
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	sort.Slice(d.Changed, func(i, j int) bool { return d.Changed[i].ID < d.Changed[j].ID })
+	return d
+}
+
+func diffModel(o, n models.Model) []FieldChange {
+	var changes []FieldChange
+	ov, nv := reflect.ValueOf(o), reflect.ValueOf(n)
+	t := ov.Type()
+	for i := 0; i < t.NumField(); i++ {
+		a, b := fmt.Sprint(ov.Field(i).Interface()), fmt.Sprint(nv.Field(i).Interface())
+		if a == b {
+			continue
+		}
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "" {
+			name = t.Field(i).Name
+		}
+		changes = append(changes, FieldChange{Field: name, Old: a, New: b})
+	}
+	return changes
+}
+
+// FormatRegistryDiff renders a diff as markdown.
+func FormatRegistryDiff(d RegistryDiff, oldLabel, newLabel string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Registry diff: %s → %s\n\n", oldLabel, newLabel)
+	if d.Empty() {
+		b.WriteString("No differences.")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "**%d added, %d removed, %d changed**\n", len(d.Added), len(d.Removed), len(d.Changed))
+
+	if len(d.Added) > 0 {
+		b.WriteString("\n### Added\n\n")
+		for _, id := range d.Added {
+			fmt.Fprintf(&b, "- `%s`\n", id)
+		}
+	}
+	if len(d.Removed) > 0 {
+		b.WriteString("\n### Removed\n\n")
+		for _, id := range d.Removed {
+			fmt.Fprintf(&b, "- `%s`\n", id)
+		}
+	}
+	if len(d.Changed) > 0 {
+		b.WriteString("\n### Changed\n\n| Model | Field | Old | New |\n|-------|-------|-----|-----|\n")
+		for _, c := range d.Changed {
+			for _, f := range c.Fields {
+				fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", c.ID, f.Field, f.Old, f.New)
+			}
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -1,10 +1,14 @@
 package tools
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
+	"go-server/internal/modelid"
 	"go-server/internal/models"
 )
 
@@ -30,13 +34,15 @@ func newestPerProvider(ms []models.Model) map[string]bool {
 // Models are grouped by provider and sorted newest-first within each group.
 // The newest model per provider is marked with ★.
 func FormatTable(ms []models.Model) string {
-	if len(ms) == 0 {
-		return "No models found matching the criteria."
-	}
+	return formatTable(ms, nil, anyModel)
+}
 
-	newest := newestPerProvider(ms)
+// anyModel accepts every model for the USE IN CODE footer.
+func anyModel(models.Model) bool { return true }
 
-	// Sort: by provider name ascending, then by release date descending within provider
+// SortedByProvider returns a copy of ms in FormatTable order: by provider
+// name, then newest release first, then by ID.
+func SortedByProvider(ms []models.Model) []models.Model {
 	sorted := make([]models.Model, len(ms))
 	copy(sorted, ms)
 	sort.SliceStable(sorted, func(i, j int) bool {
@@ -48,35 +54,89 @@ func FormatTable(ms []models.Model) string {
 		}
 		return sorted[i].ID < sorted[j].ID
 	})
+	return sorted
+}
+
+// formatTable renders ms like FormatTable. When aka is non-nil it adds an
+// "Also Known As" column listing aka[id] for each row. The USE IN CODE
+// footer names the newest model per provider among those footer accepts;
+// a nil footer, or one that accepts none of ms, leaves it out.
+func formatTable(ms []models.Model, aka map[string][]string, footer func(models.Model) bool) string {
+	if len(ms) == 0 {
+		return "No models found matching the criteria."
+	}
+
+	newest := newestPerProvider(ms)
+	sorted := SortedByProvider(ms)
 
 	rows := []string{
 		"| Model ID | Display Name | Provider | Status | Context | Input $/1M | Output $/1M |",
 		"|----------|-------------|----------|--------|---------|-----------|-------------|",
 	}
-	// Track newest model IDs for the footer instruction
-	var newestIDs []string
+	if aka != nil {
+		rows[0] += " Also Known As |"
+		rows[1] += "---------------|"
+	}
 	for _, m := range sorted {
 		star := ""
 		if newest[m.ID] {
 			star = "★ "
-			newestIDs = append(newestIDs, m.ID)
 		}
-		rows = append(rows, fmt.Sprintf(
+		row := fmt.Sprintf(
 			"| %s%s | %s | %s | %s | %s | $%.2f | $%.2f |",
 			star, m.ID, m.DisplayName, m.Provider, m.Status,
 			models.FormatInt(m.ContextWindow),
 			m.PricingInput, m.PricingOutput,
-		))
+		)
+		if aka != nil {
+			names := "—"
+			if len(aka[m.ID]) > 0 {
+				names = strings.Join(aka[m.ID], ", ")
+			}
+			row += " " + names + " |"
+		}
+		rows = append(rows, row)
 	}
 
 	// Add explicit instruction footer so the agent knows which model to use
-	if len(newestIDs) > 0 {
-		rows = append(rows, "")
-		rows = append(rows, "**→ USE IN CODE: "+strings.Join(newestIDs, ", ")+" (★ = newest by release date, ALWAYS prefer these)**")
+	if line := useInCodeLine(sorted, newest, footer); line != "" {
+		rows = append(rows, "", line)
 	}
 	return strings.Join(rows, "\n")
 }
 
+// useInCodeLine returns the USE IN CODE footer for sorted, a table in
+// SortedByProvider order whose ★ models are newest: the newest model per
+// provider that accept allows, or "" if accept is nil or allows none. When
+// accept passes over a ★ model, the line says the picks are the newest
+// allowed rather than pointing at ★.
+func useInCodeLine(sorted []models.Model, newest map[string]bool, accept func(models.Model) bool) string {
+	if accept == nil {
+		return ""
+	}
+	var ids []string
+	starred := true
+	picked := make(map[string]bool)
+	for _, m := range sorted {
+		if picked[m.Provider] || !accept(m) {
+			continue
+		}
+		// sorted is newest first within each provider, so the first
+		// accepted model is the provider's newest allowed one.
+		picked[m.Provider] = true
+		ids = append(ids, m.ID)
+		starred = starred && newest[m.ID]
+	}
+	switch {
+	case len(ids) == 0:
+		return ""
+	case starred && len(ids) == len(newest):
+		return "**→ USE IN CODE: " + strings.Join(ids, ", ") + " (★ = newest by release date, ALWAYS prefer these)**"
+	default:
+		return "**→ USE IN CODE: " + strings.Join(ids, ", ") + " (newest per provider that org policy allows, ALWAYS prefer these)**"
+	}
+}
+
 // ModelDetail renders full specs for a single model as markdown.
 func ModelDetail(m models.Model) string {
 	var caps []string
@@ -86,6 +146,15 @@ func ModelDetail(m models.Model) string {
 	if m.Reasoning {
 		caps = append(caps, "Reasoning/Thinking")
 	}
+	if m.ToolCalling {
+		caps = append(caps, "Tool Calling")
+	}
+	if m.StructuredOutput {
+		caps = append(caps, "Structured Output (JSON schema)")
+	}
+	if m.JSONMode {
+		caps = append(caps, "JSON Mode")
+	}
 	capsStr := "None"
 	if len(caps) > 0 {
 		capsStr = strings.Join(caps, ", ")
@@ -96,32 +165,224 @@ func ModelDetail(m models.Model) string {
 		notes = "—"
 	}
 
-	return fmt.Sprintf(`## %s (`+"`%s`"+`)
+	detail := fmt.Sprintf(`## %s (`+"`%s`"+`)
 
-| Field | Value |
+%s| Field | Value |
 |-------|-------|
 | Provider | %s |
-| Status | **%s** |
+| Status | **%s**%s |
+| Maturity | %s |
+| Provider Default | %s |
 | Context Window | %s tokens |
 | Max Output | %s tokens |
 | Capabilities | %s |
+| EU Hosted | %s |
+| System Prompt | %s (%s) |
+| Batch API | %s |
 | Pricing (input) | $%.2f / 1M tokens |
 | Pricing (output) | $%.2f / 1M tokens |
+| Pricing (cached input) | %s |
+| Long-Context Pricing | %s |
+| Speed | %s |
+| Parameters | %s |
+| Reasoning Control | %s |
+| OpenAI SDK | %s |
+| APIs | %s |
 | Knowledge Cutoff | %s |
 | Release Date | %s |
 | Notes | %s |`,
 		m.DisplayName, m.ID,
+		guidanceSection(m),
 		m.Provider,
-		m.Status,
+		m.Status, lifecycle(m, time.Now()),
+		m.Maturity,
+		yesNo(m.ProviderDefault),
 		models.FormatInt(m.ContextWindow),
 		models.FormatInt(m.MaxOutputTokens),
 		capsStr,
+		yesNo(m.EUHosted),
+		m.SystemPrompt, m.SystemPrompt.Guidance(),
+		batchDetail(m),
 		m.PricingInput,
 		m.PricingOutput,
+		cachedInputDetail(m),
+		longContextDetail(m.ID),
+		speedDetail(m.ID),
+		paramDetail(m.ID),
+		reasoningDetail(m.ID),
+		endpointDetail(m.Provider),
+		apiDetail(m.ID),
 		m.KnowledgeCutoff,
 		m.ReleaseDate,
 		notes,
 	)
+
+	// Source links let callers verify the figures above.
+	sources := []struct{ label, url string }{
+		{"Docs", m.DocsURL},
+		{"Model Card", m.ModelCardURL},
+		{"Announcement", m.AnnouncementURL},
+	}
+	for _, src := range sources {
+		if src.url != "" {
+			detail += fmt.Sprintf("\n| %s | %s |", src.label, src.url)
+		}
+	}
+	return detail
+}
+
+// guidanceSection lists a model's agent guidance above its spec table, where
+// a caller skimming for the model ID can't miss it. It is empty for models
+// without guidance.
+func guidanceSection(m models.Model) string {
+	if len(m.AgentGuidance) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("**Agent guidance:**\n")
+	for _, g := range m.AgentGuidance {
+		b.WriteString("- " + g + "\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// batchDetail describes batch API support and the discounted batch prices.
+func batchDetail(m models.Model) string {
+	in, out, ok := m.BatchPricing()
+	if !ok {
+		return "No"
+	}
+	if m.BatchRecorded() {
+		return fmt.Sprintf("Yes — %s input / %s output per 1M tokens", models.FormatPrice(in), models.FormatPrice(out))
+	}
+	return fmt.Sprintf("Yes — $%.2f input / $%.2f output per 1M tokens (%.0f%% off)", in, out, (1-models.BatchDiscount)*100)
+}
+
+// cachedInputDetail describes the price of input read from the provider's
+// prompt cache, with its discount off the list input price.
+func cachedInputDetail(m models.Model) string {
+	cached, ok := m.CachedInputPricing()
+	if !ok {
+		return "Not recorded"
+	}
+	detail := models.FormatPrice(cached) + " / 1M tokens"
+	if m.PricingInput > 0 {
+		detail += fmt.Sprintf(" (%.0f%% off input)", (1-cached/m.PricingInput)*100)
+	}
+	return detail
+}
+
+// paramDetail describes a model's request parameter constraints so callers
+// don't send parameters the API rejects.
+func paramDetail(id string) string {
+	c, ok := models.ParamConstraints[id]
+	if !ok {
+		return "Standard (no known restrictions)"
+	}
+	var parts []string
+	if len(c.Unsupported) > 0 {
+		parts = append(parts, "Rejects "+strings.Join(c.Unsupported, ", "))
+	}
+	if len(c.Fixed) > 0 {
+		var fixed []string
+		for p, v := range c.Fixed {
+			fixed = append(fixed, p+"="+v)
+		}
+		sort.Strings(fixed)
+		parts = append(parts, "only "+strings.Join(fixed, ", "))
+	}
+	if len(c.Exclusive) > 0 {
+		parts = append(parts, "send only one of "+strings.Join(c.Exclusive, " or "))
+	}
+	if len(c.Supported) > 0 {
+		parts = append(parts, "supports "+strings.Join(c.Supported, ", "))
+	}
+	detail := strings.Join(parts, "; ")
+	if c.Note != "" {
+		if detail != "" {
+			detail += ". "
+		}
+		detail += c.Note
+	}
+	return strings.ToUpper(detail[:1]) + detail[1:]
+}
+
+// reasoningDetail describes the knob that sets a model's reasoning depth,
+// with a request fragment generated code can copy.
+func reasoningDetail(id string) string {
+	c, ok := models.ReasoningControls[id]
+	if !ok {
+		return "—"
+	}
+	detail := c.Describe() + ". Example: `" + c.Example + "`"
+	if c.Note != "" {
+		detail += ". " + c.Note
+	}
+	return detail
+}
+
+// longContextDetail describes a model's long-context rate, if it has one.
+func longContextDetail(id string) string {
+	lc, ok := models.LongContextPricing[id]
+	if !ok {
+		return "Same rate at any prompt length"
+	}
+	detail := fmt.Sprintf("$%.2f / $%.2f per 1M tokens when input exceeds %s tokens (whole request)",
+		lc.Input, lc.Output, models.FormatInt(lc.AboveInputTokens))
+	if lc.Note != "" {
+		detail += "; " + lc.Note
+	}
+	return detail
+}
+
+// apiDetail lists the API surfaces a model supports, calling out models
+// that reject chat/completions.
+func apiDetail(id string) string {
+	apis, ok := models.APIs[id]
+	if !ok {
+		return "—"
+	}
+	if models.ResponsesOnly(id) {
+		return "responses only — chat/completions calls are rejected"
+	}
+	names := make([]string, len(apis))
+	for i, a := range apis {
+		names[i] = string(a)
+	}
+	return strings.Join(names, ", ")
+}
+
+// endpointDetail describes whether the OpenAI SDK can reach the provider and
+// with which base_url.
+func endpointDetail(provider string) string {
+	e, ok := models.Endpoints[provider]
+	switch {
+	case !ok:
+		return "Unknown"
+	case !e.OpenAICompatible:
+		detail := "Not OpenAI-compatible"
+		if e.Note != "" {
+			detail += ". " + e.Note
+		}
+		return detail
+	}
+	detail := "Compatible"
+	if e.BaseURL != "" {
+		detail += " — base_url `" + e.BaseURL + "`"
+	}
+	if e.Note != "" {
+		detail += ". " + e.Note
+	}
+	return detail
+}
+
+// yesNo renders a boolean as "Yes" or "No" for markdown tables.
+func yesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
 }
 
 // levenshteinDistance computes the Levenshtein edit distance between two strings.
@@ -164,17 +425,56 @@ func levenshteinDistance(a, b string) int {
 	return prev[lb]
 }
 
+// foldQuery normalizes text pasted from CJK input methods so model lookups
+// match: full-width ASCII and the ideographic space become their ASCII forms,
+// CJK and typographic punctuation becomes the ASCII used in model IDs, and
+// zero-width characters and bracket quotes are dropped.
+func foldQuery(s string) string {
+	return strings.TrimSpace(strings.Map(func(c rune) rune {
+		switch {
+		case c >= 0xFF01 && c <= 0xFF5E: // full-width ASCII block
+			c -= 0xFEE0
+		case c == '\u3000':
+			return ' '
+		}
+		switch c {
+		case '。', '·', '・', '•':
+			return '.'
+		case '‐', '‑', '‒', '–', '—', '―', '−':
+			return '-'
+		case '、':
+			return ','
+		case '\u200b', '\u200c', '\u200d', '\ufeff',
+			'“', '”', '‘', '’', '「', '」', '『', '』', '【', '】', '《', '》', '〈', '〉':
+			return -1
+		}
+		return c
+	}, s))
+}
+
 // SuggestModels returns the n closest model IDs to the input by Levenshtein distance.
-func SuggestModels(input string, n int) []string {
+// Results are cached per (input, n), so repeated misses don't rescan the registry.
+func (r *Registry) SuggestModels(input string, n int) []string {
+	key := suggestKey{input: input, n: n}
+	if cached, ok := r.suggestCache.Get(key); ok {
+		return append([]string(nil), cached...)
+	}
+	result := r.suggestModels(input, n)
+	r.suggestCache.Add(key, result)
+	return append([]string(nil), result...)
+}
+
+func (r *Registry) suggestModels(input string, n int) []string {
 	type candidate struct {
 		id   string
 		dist int
 	}
-	lower := strings.ToLower(input)
-	var candidates []candidate
-	for key := range models.Models {
-		dist := levenshteinDistance(lower, strings.ToLower(key))
-		candidates = append(candidates, candidate{id: key, dist: dist})
+	lower := strings.ToLower(foldQuery(input))
+	keys := r.lowerKeys()
+	candidates := make([]candidate, 0, len(keys))
+	for _, k := range keys {
+		dist := levenshteinDistance(lower, k.lower)
+		candidates = append(candidates, candidate{id: k.id, dist: dist})
 	}
 	sort.SliceStable(candidates, func(i, j int) bool {
 		if candidates[i].dist != candidates[j].dist {
@@ -191,32 +491,47 @@ func SuggestModels(input string, n int) []string {
 
 // FindModel finds a model by exact match, alias, case-insensitive, or partial match.
 // Partial matching is deterministic: shortest ID first, then alphabetically.
-func FindModel(modelID string) (models.Model, bool) {
+// Results are cached per query.
+func (r *Registry) FindModel(modelID string) (models.Model, bool) {
 	if modelID == "" {
 		return models.Model{}, false
 	}
+	if cached, ok := r.findCache.Get(modelID); ok {
+		return cached.model, cached.found
+	}
+	m, found := r.findModel(modelID)
+	r.findCache.Add(modelID, findResult{model: m, found: found})
+	return m, found
+}
+
+func (r *Registry) findModel(modelID string) (models.Model, bool) {
+	modelID = foldQuery(modelID)
 
 	// Exact match
-	if m, ok := models.Models[modelID]; ok {
+	if m, ok := r.models[modelID]; ok {
 		return m, true
 	}
 
-	// Alias resolution
-	if canonical, ok := models.Aliases[modelID]; ok {
-		if m, ok := models.Models[canonical]; ok {
+	// Alias resolution, ignoring case ("OpenAI/gpt-5.1" → "openai/gpt-5.1",
+	// "minimax-vl-01" → "MiniMax-VL-01")
+	lower := strings.ToLower(modelID)
+	if canonical, ok := r.resolveAlias(modelID); ok {
+		if m, ok := r.models[canonical]; ok {
 			return m, true
 		}
 	}
 
-	// Case-insensitive / partial match — collect all candidates, then sort deterministically
-	lower := strings.ToLower(modelID)
+	// Match ignoring case and separators (modelid.Fold, so "GLM_4.7" finds
+	// glm-4.7), else partial match — collect all candidates, then sort
+	// deterministically
+	folded := modelid.Fold(modelID)
 	var candidates []models.Model
-	for key, m := range models.Models {
-		if strings.ToLower(key) == lower {
-			return m, true // Exact case-insensitive — return immediately
+	for _, k := range r.lowerKeys() {
+		if k.folded == folded {
+			return r.models[k.id], true // Same ID spelled differently — return immediately
 		}
-		if strings.Contains(strings.ToLower(key), lower) {
-			candidates = append(candidates, m)
+		if strings.Contains(k.lower, lower) {
+			candidates = append(candidates, r.models[k.id])
 		}
 	}
 
@@ -234,50 +549,86 @@ func FindModel(modelID string) (models.Model, bool) {
 	return models.Model{}, false
 }
 
-// providerAliases maps common alternative names to canonical provider names.
-var providerAliases = map[string]string{
-	"kimi":      "moonshot",
-	"zhipuai":   "zhipu",
-	"z.ai":      "zhipu",
-	"bigmodel":  "zhipu",
-	"glm":       "zhipu",
-	"phi":       "microsoft",
-	"azure":     "microsoft",
-	"nemotron":  "nvidia",
-	"nim":       "nvidia",
-	"hunyuan":   "tencent",
-	"mimo":      "xiaomi",
-	"kwai":      "kuaishou",
-	"kwaipilot": "kuaishou",
-	"kat":       "kuaishou",
-	"gpt":       "openai",
-	"chatgpt":   "openai",
-	"claude":    "anthropic",
-	"gemini":    "google",
-	"grok":      "xai",
-	"llama":     "meta",
-	"nova":      "amazon",
-	"sonar":     "perplexity",
-	"jamba":     "ai21",
-	"devstral":  "mistral",
-	"magistral": "mistral",
-	"ministral": "mistral",
-}
-
-// FilterModels returns models matching the given provider, status, and capability filters.
-// Empty string means no filter for that field. Provider supports common aliases.
-func FilterModels(provider, status, capability string) []models.Model {
+// Exclusions lists providers, statuses, and model IDs to drop from results.
+// It lets org policies such as "never xAI" or "never deprecated" be expressed
+// in a single call. Providers resolve common aliases and IDs resolve registry
+// aliases; all matching is case-insensitive.
+type Exclusions struct {
+	Providers []string
+	Statuses  []string
+	IDs       []string
+}
+
+// resolveProvider lowercases a provider name and maps common aliases to the
+// canonical provider name.
+func resolveProvider(provider string) string {
+	if p, ok := models.LookupProvider(provider); ok {
+		return strings.ToLower(p.Name)
+	}
+	return strings.ToLower(provider)
+}
+
+// resolveAlias returns the canonical ID the alias id points at, exact
+// spelling first, then ignoring case.
+func (r *Registry) resolveAlias(id string) (string, bool) {
+	if canonical, ok := r.aliases[id]; ok {
+		return canonical, true
+	}
+	canonical, ok := r.aliasIndex()[strings.ToLower(id)]
+	return canonical, ok
+}
+
+// resolveModelID maps an ID or alias, in any case, to its lowercased
+// canonical registry key. Unlike FindModel it never falls back to partial
+// matching, so exclusions cannot accidentally drop unrelated models.
+func (r *Registry) resolveModelID(id string) string {
+	if canonical, ok := r.resolveAlias(id); ok {
+		return strings.ToLower(canonical)
+	}
+	return strings.ToLower(id)
+}
+
+// applyExclusions returns the models in ms that e does not exclude.
+func (r *Registry) applyExclusions(e Exclusions, ms []models.Model) []models.Model {
+	if len(e.Providers) == 0 && len(e.Statuses) == 0 && len(e.IDs) == 0 {
+		return ms
+	}
+	providers := make(map[string]bool, len(e.Providers))
+	for _, p := range e.Providers {
+		providers[resolveProvider(strings.TrimSpace(p))] = true
+	}
+	statuses := make(map[string]bool, len(e.Statuses))
+	for _, s := range e.Statuses {
+		statuses[strings.ToLower(strings.TrimSpace(s))] = true
+	}
+	ids := make(map[string]bool, len(e.IDs))
+	for _, id := range e.IDs {
+		ids[r.resolveModelID(strings.TrimSpace(id))] = true
+	}
+
+	var filtered []models.Model
+	for _, m := range ms {
+		if providers[strings.ToLower(m.Provider)] ||
+			statuses[strings.ToLower(m.Status)] ||
+			ids[strings.ToLower(m.ID)] {
+			continue
+		}
+		filtered = append(filtered, m)
+	}
+	return filtered
+}
+
+// FilterModels returns models matching the given provider, status, capability, and
+// sovereignty filters, minus anything listed in exclude. Empty string means no
+// filter for that field. Provider supports common aliases.
+func (r *Registry) FilterModels(provider, status, capability, sovereignty string, exclude Exclusions) []models.Model {
 	var results []models.Model
-	for _, m := range models.Models {
+	for _, m := range r.models {
 		results = append(results, m)
 	}
 
 	if provider != "" {
-		p := strings.ToLower(provider)
-		// Resolve provider alias to canonical name
-		if canonical, ok := providerAliases[p]; ok {
-			p = canonical
-		}
+		p := resolveProvider(provider)
 		var filtered []models.Model
 		for _, m := range results {
 			if strings.ToLower(m.Provider) == p {
@@ -299,26 +650,97 @@ func FilterModels(provider, status, capability string) []models.Model {
 	}
 
 	if capability != "" {
-		c := strings.ToLower(capability)
-		var filtered []models.Model
-		switch c {
-		case "vision":
-			for _, m := range results {
-				if m.Vision {
-					filtered = append(filtered, m)
-				}
+		results = filterCapability(results, capability)
+	}
+
+	if sovereignty != "" {
+		results = filterSovereignty(results, sovereignty)
+	}
+
+	return r.applyPolicy(r.applyExclusions(exclude, results))
+}
+
+// filterCapability keeps only models with the given capability, or with
+// "default", each provider's own recommended default. Unknown names return no
+// results; ListModels rejects them before filtering.
+func filterCapability(ms []models.Model, capability string) []models.Model {
+	var filtered []models.Model
+	switch strings.ToLower(capability) {
+	case "default", "provider_default", "provider-default":
+		// The provider's own recommended default, which is often not its
+		// newest release (previews and betas rarely are).
+		for _, m := range ms {
+			if m.ProviderDefault {
+				filtered = append(filtered, m)
 			}
-		case "reasoning", "thinking":
-			for _, m := range results {
-				if m.Reasoning {
-					filtered = append(filtered, m)
-				}
+		}
+		return filtered
+	}
+	c, ok := models.ParseCapability(capability)
+	if !ok {
+		return nil
+	}
+	for _, m := range ms {
+		if m.Has(c) {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered
+}
+
+// filterMaturity keeps only models at least as mature as min.
+func filterMaturity(ms []models.Model, min models.Maturity) []models.Model {
+	var filtered []models.Model
+	for _, m := range ms {
+		if m.Maturity.AtLeast(min) {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered
+}
+
+// filterRetiring keeps only models retiring between now and withinDays days
+// from now. Models already retired are left out: they need replacing, not
+// scheduling, and check_model_status says so.
+func filterRetiring(ms []models.Model, now time.Time, withinDays int) []models.Model {
+	var filtered []models.Model
+	for _, m := range ms {
+		if day, ok := m.Retirement(); ok {
+			if n := models.DaysUntil(day, now); n >= 0 && n <= withinDays {
+				filtered = append(filtered, m)
 			}
-		default:
-			// Unknown capability — return no results (no models have this capability).
 		}
-		results = filtered
 	}
+	return filtered
+}
 
-	return results
+// retirementList lists the retirement dates of ms, soonest first.
+func retirementList(ms []models.Model, now time.Time) string {
+	sorted := slices.Clone(ms)
+	slices.SortFunc(sorted, func(a, b models.Model) int {
+		return cmp.Or(strings.Compare(a.RetirementDate, b.RetirementDate), strings.Compare(a.ID, b.ID))
+	})
+	var b strings.Builder
+	b.WriteString("**Retiring:**")
+	for _, m := range sorted {
+		day, _ := m.Retirement()
+		fmt.Fprintf(&b, "\n- `%s`: %s", m.ID, inDays(m.RetirementDate, models.DaysUntil(day, now)))
+	}
+	return b.String()
+}
+
+// filterSovereignty keeps only models that satisfy the given data-sovereignty
+// requirement. Currently only "eu" (EU-hosted) is supported; unknown values
+// return no results, matching the unknown-capability behavior.
+func filterSovereignty(ms []models.Model, sovereignty string) []models.Model {
+	var filtered []models.Model
+	switch strings.ToLower(sovereignty) {
+	case "eu", "europe", "eu-hosted":
+		for _, m := range ms {
+			if m.EUHosted {
+				filtered = append(filtered, m)
+			}
+		}
+	}
+	return filtered
 }
@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go-server/internal/models"
+)
+
+// DeprecationImpactInput holds parameters for the deprecation_impact tool.
+type DeprecationImpactInput struct {
+	ModelID string `json:"model_id" jsonschema:"The model ID or alias whose retirement you want to scope"`
+	FormatInput
+}
+
+// DeprecationImpact lists every name a model is reachable under — shorthand
+// aliases, floating aliases, and provider-prefixed platform IDs — plus a grep
+// command and checklist for finding those names in a codebase before the
+// model is retired.
+func (r *Registry) DeprecationImpact(modelID string) string {
+	if modelID == "" {
+		return "Please provide a model ID. Example: `deprecation_impact(model_id=\"gpt-4o\")`"
+	}
+	m, found := r.FindModel(modelID)
+	if !found {
+		suggestions := r.SuggestModels(modelID, 3)
+		return fmt.Sprintf("Model `%s` not found in registry. Did you mean: %s",
+			modelID, strings.Join(suggestions, ", "))
+	}
+
+	var shorthand, floating, platform []string
+	for _, a := range r.aliasesByModel()[m.ID] {
+		switch {
+		case strings.Contains(a, "/"):
+			platform = append(platform, a)
+		case models.IsFloatingAlias(a):
+			floating = append(floating, a)
+		default:
+			shorthand = append(shorthand, a)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Deprecation impact: %s (`%s`)\n\n", m.DisplayName, m.ID)
+	fmt.Fprintf(&b, "Status: **%s**.", m.Status)
+	var rep models.Model
+	hasRep := false
+	if m.Status == "legacy" || m.Status == "deprecated" {
+		rep, hasRep = r.replacementFor(m)
+	}
+	if hasRep {
+		fmt.Fprintf(&b, " Suggested replacement: **%s** (`%s`).", rep.DisplayName, rep.ID)
+	}
+	b.WriteString("\n")
+	if m.Notes != "" {
+		fmt.Fprintf(&b, "\nNote: %s\n", m.Notes)
+	}
+
+	fmt.Fprintf(&b, "\n### Names that resolve to `%s` (%d)\n\n", m.ID, 1+len(shorthand)+len(floating)+len(platform))
+	b.WriteString("| Name | Kind | Behavior |\n|------|------|----------|\n")
+	fmt.Fprintf(&b, "| `%s` | canonical ID | Stops working when the provider retires the model |\n", m.ID)
+	for _, a := range shorthand {
+		fmt.Fprintf(&b, "| `%s` | alias | Fixed; breaks with the canonical ID |\n", a)
+	}
+	for _, a := range floating {
+		fmt.Fprintf(&b, "| `%s` | floating alias | Moves to the newest matching model; code using it changes model silently |\n", a)
+	}
+	if len(platform) > 0 {
+		quoted := make([]string, len(platform))
+		for i, p := range platform {
+			quoted[i] = "`" + p + "`"
+		}
+		fmt.Fprintf(&b, "| %s | platform IDs | Router/gateway forms (OpenRouter, LiteLLM); check each platform's own retirement date |\n", strings.Join(quoted, ", "))
+	}
+
+	b.WriteString("\n### Codebase checklist\n\n")
+	b.WriteString("Search for every form of the name. Prefixed platform IDs match too, since `/` counts as a boundary:\n\n")
+	fmt.Fprintf(&b, "```sh\n%s\n```\n\n", impactGrep(append([]string{m.ID}, shorthand...)))
+	b.WriteString("- [ ] Hard-coded IDs in source, config files, environment variables, and infrastructure templates\n")
+	b.WriteString("- [ ] Test fixtures, snapshots, and eval configs that assert on the ID\n")
+	if len(platform) > 0 {
+		b.WriteString("- [ ] Router and gateway configs that use the prefixed forms\n")
+	}
+	if len(floating) > 0 {
+		fmt.Fprintf(&b, "- [ ] Callers of %s follow the newest matching model, so they move off `%s` when a newer one ships; confirm that is acceptable or pin an explicit ID\n", strings.Join(floating, ", "), m.ID)
+	}
+	if hasRep {
+		fmt.Fprintf(&b, "- [ ] Limits and cost: `%s` has %s context / %s max output at $%.2f/$%.2f per 1M tokens; `%s` has %s / %s at $%.2f/$%.2f\n",
+			m.ID, models.FormatInt(m.ContextWindow), models.FormatInt(m.MaxOutputTokens), m.PricingInput, m.PricingOutput,
+			rep.ID, models.FormatInt(rep.ContextWindow), models.FormatInt(rep.MaxOutputTokens), rep.PricingInput, rep.PricingOutput)
+	}
+	return b.String()
+}
+
+// impactGrep builds a case-insensitive extended-regex grep matching any of
+// names as a whole ID, so `gpt-4o` doesn't also hit `gpt-4o-mini`.
+func impactGrep(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = regexp.QuoteMeta(n)
+	}
+	return fmt.Sprintf("grep -rniE '(^|[^[:alnum:]._-])(%s)([^[:alnum:]._-]|$)' .", strings.Join(quoted, "|"))
+}
+
+// RetiredNames returns every legacy or deprecated model ID in the registry
+// plus every alias and platform ID that resolves to one, lowercased, sorted,
+// and deduplicated: one list code-audit tooling can grep a codebase against.
+// Floating aliases never appear, since they only point at current models.
+func (r *Registry) RetiredNames() []string {
+	seen := make(map[string]bool)
+	for id, m := range r.models {
+		if m.Status != "legacy" && m.Status != "deprecated" {
+			continue
+		}
+		seen[strings.ToLower(id)] = true
+		for _, a := range r.aliasesByModel()[id] {
+			seen[a] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for n := range seen {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
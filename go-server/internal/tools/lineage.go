@@ -0,0 +1,178 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go-server/internal/models"
+)
+
+// fastDeprecationMonths is the lifetime, release to deprecation, under which
+// a model counts as deprecated unusually fast.
+const fastDeprecationMonths = 6
+
+// predecessorWindow is how many of a lineage's most recent deprecated
+// models count as a candidate's immediate predecessors.
+const predecessorWindow = 2
+
+// stabilityRe matches the whole words that mark a recommend_model task
+// asking for a model it can keep using, which turns on the churn risk
+// penalty. "unstable" and "long-lasting" don't match "stable" and "lasting"
+// by accident; the first is a different word and the second is fine.
+var stabilityRe = regexp.MustCompile(`\b(?:stable|long[- ]term|longevity|durable|lasting)\b`)
+
+// stabilityNegations cancel a stability word within the three words before
+// it: "not stable", "don't need long-term support".
+var stabilityNegations = map[string]bool{
+	"not": true, "no": true, "never": true, "without": true,
+	"don't": true, "dont": true, "doesn't": true, "isn't": true, "needn't": true,
+}
+
+// wantsStability reports whether a task asks for a long-lived pick: it names
+// a stability word that isn't negated.
+func wantsStability(taskLower string) bool {
+	for _, loc := range stabilityRe.FindAllStringIndex(taskLower, -1) {
+		before := strings.Fields(taskLower[:loc[0]])
+		negated := false
+		for _, w := range before[max(len(before)-3, 0):] {
+			if stabilityNegations[strings.Trim(w, ",.;:!?()")] {
+				negated = true
+			}
+		}
+		if !negated {
+			return true
+		}
+	}
+	return false
+}
+
+// lineageKey groups a provider's successive versions of one model line:
+// version numbers, date stamps, and preview tags are dropped, so gpt-5.2-codex
+// and gpt-5.3-codex share "OpenAI/gpt-codex" and o3-mini and o4-mini share
+// "OpenAI/o-mini".
+func lineageKey(m models.Model) string {
+	var parts []string
+	for _, tok := range strings.Split(m.ID, "-") {
+		tok = strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' || r == '.' {
+				return -1
+			}
+			return r
+		}, tok)
+		switch tok {
+		case "", "preview", "latest", "exp":
+			continue
+		}
+		parts = append(parts, tok)
+	}
+	return m.Provider + "/" + strings.Join(parts, "-")
+}
+
+// deprecationNoteRe finds when a model left the provider's docs or was
+// retired, in the wording data.go notes use: "Removed from OpenAI docs Feb
+// 2026", "Retiring March 31, 2026".
+var deprecationNoteRe = regexp.MustCompile(`(?:Removed from \S+ docs|Retir(?:ing|ed)(?: from \S+)?) ((?:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec)[a-z]*) (?:\d{1,2}, )?(\d{4})`)
+
+// deprecationDates is when the registry changelog last recorded each
+// model's status changing. See SetDeprecationDates.
+var deprecationDates atomic.Pointer[map[string]time.Time]
+
+// SetDeprecationDates sets when each model's status last changed, from the
+// registry changelog, so churn risk dates a deprecation by when the registry
+// recorded it. Models the changelog doesn't cover fall back to the dates in
+// their notes.
+func SetDeprecationDates(dates map[string]time.Time) {
+	deprecationDates.Store(&dates)
+}
+
+// deprecatedMonth returns the month a deprecated model was deprecated, from
+// the changelog when it has the model and from its notes otherwise.
+func deprecatedMonth(m models.Model) (time.Time, bool) {
+	if m.Status != "deprecated" {
+		return time.Time{}, false
+	}
+	if dates := deprecationDates.Load(); dates != nil {
+		if t, ok := (*dates)[m.ID]; ok {
+			return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC), true
+		}
+	}
+	g := deprecationNoteRe.FindStringSubmatch(m.Notes)
+	if g == nil {
+		return time.Time{}, false
+	}
+	t, err := time.Parse("Jan 2006", g[1][:3]+" "+g[2])
+	return t, err == nil
+}
+
+// lifetimeMonths is how long a deprecated model lasted, in whole months from
+// release to deprecation.
+func lifetimeMonths(m models.Model) (int, bool) {
+	dep, ok := deprecatedMonth(m)
+	if !ok {
+		return 0, false
+	}
+	rel, err := time.Parse("2006-01", m.ReleaseDate)
+	if err != nil {
+		return 0, false
+	}
+	months := (dep.Year()-rel.Year())*12 + int(dep.Month()-rel.Month())
+	return max(months, 0), true
+}
+
+// predecessorLifetime is a deprecated predecessor and how long it lasted.
+type predecessorLifetime struct {
+	ID     string
+	Months int
+}
+
+// churnRisk returns the fraction of m's immediate predecessors that were
+// deprecated within fastDeprecationMonths, and those fast ones. Models with
+// no dated deprecated predecessor have no churn risk.
+func (r *Registry) churnRisk(m models.Model) (float64, []predecessorLifetime) {
+	key := lineageKey(m)
+	var preds []models.Model
+	for _, p := range r.models {
+		if p.ID == m.ID || p.ReleaseDate > m.ReleaseDate || lineageKey(p) != key {
+			continue
+		}
+		if _, ok := lifetimeMonths(p); ok {
+			preds = append(preds, p)
+		}
+	}
+	if len(preds) == 0 {
+		return 0, nil
+	}
+	sort.Slice(preds, func(i, j int) bool {
+		if preds[i].ReleaseDate != preds[j].ReleaseDate {
+			return preds[i].ReleaseDate > preds[j].ReleaseDate
+		}
+		return preds[i].ID < preds[j].ID
+	})
+	if len(preds) > predecessorWindow {
+		preds = preds[:predecessorWindow]
+	}
+	var fast []predecessorLifetime
+	for _, p := range preds {
+		if months, _ := lifetimeMonths(p); months < fastDeprecationMonths {
+			fast = append(fast, predecessorLifetime{p.ID, months})
+		}
+	}
+	return float64(len(fast)) / float64(len(preds)), fast
+}
+
+// describeChurn renders fast-deprecated predecessors for recommendations.
+func describeChurn(fast []predecessorLifetime) string {
+	parts := make([]string, len(fast))
+	for i, p := range fast {
+		unit := "months"
+		if p.Months == 1 {
+			unit = "month"
+		}
+		parts[i] = fmt.Sprintf("`%s` deprecated after %d %s", p.ID, p.Months, unit)
+	}
+	return strings.Join(parts, ", ")
+}
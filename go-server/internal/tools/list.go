@@ -1,14 +1,170 @@
 package tools
 
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go-server/internal/models"
+)
+
 // ListModelsInput defines the input parameters for the list_models tool.
 type ListModelsInput struct {
-	Provider   string `json:"provider,omitempty" jsonschema:"Filter by provider name (case-insensitive)"`
-	Status     string `json:"status,omitempty" jsonschema:"Filter by status: current, legacy, or deprecated"`
-	Capability string `json:"capability,omitempty" jsonschema:"Filter by capability: vision or reasoning"`
+	Provider           string `json:"provider,omitempty" jsonschema:"Filter by provider name (case-insensitive)"`
+	Status             string `json:"status,omitempty" jsonschema:"Filter by status: current, legacy, or deprecated"`
+	Capability         string `json:"capability,omitempty" jsonschema:"Filter by capability: vision, reasoning, tool_use (function calling), structured_output (schema-constrained JSON), json_mode (valid JSON without a schema), caching (has a discounted cached-input rate), or batch (supports a discounted batch API); audio_in and audio_out are accepted but not yet recorded per model. Or default (each provider's own recommended default model). Case-insensitive; aliases such as thinking, tools, or function_calling are accepted"`
+	Sovereignty        string `json:"sovereignty,omitempty" jsonschema:"Filter by data sovereignty: eu (only models that can be hosted in the EU)"`
+	Maturity           string `json:"maturity,omitempty" jsonschema:"Least mature release to include: stable (GA only), preview (stable plus previews and betas), or experimental (everything, the default)"`
+	RetiringWithinDays int    `json:"retiring_within_days,omitempty" jsonschema:"Only models the provider retires within this many days from today (e.g. 90), with their retirement dates, for planning migrations"`
+	ExcludeInput
+	FooterInput
+	FormatInput
+}
+
+// FooterInput holds the USE IN CODE footer toggle shared by list_models and
+// search_models.
+type FooterInput struct {
+	Footer *bool `json:"footer,omitempty" jsonschema:"End the table with a USE IN CODE line naming the newest model per provider that org policy allows: true or false. Omit for the server default (on unless the operator turned it off)"`
+}
+
+var useInCodeFooterOff atomic.Bool
+
+// SetUseInCodeFooter sets whether model tables end with the USE IN CODE
+// footer when a call doesn't say. It is on until set otherwise.
+func SetUseInCodeFooter(on bool) {
+	useInCodeFooterOff.Store(!on)
+}
+
+// footerFilter returns which models the USE IN CODE footer may name, or nil
+// for no footer. show is the caller's choice; nil means the server default.
+// Models the registry's policy blocks are passed over even when the policy
+// is in shadow mode and they stay in the table, so the footer never steers
+// code toward a model that is about to be blocked, such as a preview under
+// require_stable.
+func (r *Registry) footerFilter(show *bool) func(models.Model) bool {
+	on := !useInCodeFooterOff.Load()
+	if show != nil {
+		on = *show
+	}
+	if !on {
+		return nil
+	}
+	p := r.Policy()
+	if p == nil {
+		return anyModel
+	}
+	return func(m models.Model) bool { return p.BlockReason(m) == "" }
+}
+
+// ExcludeInput holds the exclusion parameters shared by list_models and recommend_model.
+type ExcludeInput struct {
+	ExcludeProviders []string `json:"exclude_providers,omitempty" jsonschema:"Provider names to exclude (e.g. xai)"`
+	ExcludeStatus    []string `json:"exclude_status,omitempty" jsonschema:"Statuses to exclude: current, legacy, or deprecated"`
+	ExcludeIDs       []string `json:"exclude_ids,omitempty" jsonschema:"Model IDs or aliases to exclude"`
+}
+
+// FormatInput holds the output format parameter shared by every tool. The
+// server renders the tool's markdown into the requested format.
+type FormatInput struct {
+	Format string `json:"format,omitempty" jsonschema:"Output format: markdown (default), json (structured blocks for programmatic use, also returned as structuredContent), compact (plain text that saves context), or html (a standalone page for docs and decision records)"`
+}
+
+// ListQuery holds list_models' filters. The zero value lists every model
+// with the server's default footer. Empty strings and a zero
+// RetiringWithinDays mean no filter.
+type ListQuery struct {
+	Provider    string
+	Status      string
+	Capability  string
+	Sovereignty string
+	// Maturity is the least mature release to include, so the ★ newest
+	// marker can mean "newest stable" rather than "newest including
+	// previews".
+	Maturity string
+	// RetiringWithinDays, when positive, keeps only models with a
+	// retirement date in the next that many days and lists those dates,
+	// soonest first, under the table.
+	RetiringWithinDays int
+	// Footer turns the USE IN CODE footer on or off; nil uses the server
+	// default. See FooterInput.
+	Footer  *bool
+	Exclude Exclusions
+}
+
+// ListModels returns a markdown table of the models matching q. When
+// filtering by provider, a coverage line notes how much of the provider's
+// lineup the registry tracks.
+func (r *Registry) ListModels(q ListQuery) string {
+	now := time.Now()
+	results, msg := r.listMatches(q, now)
+	if msg != "" {
+		return msg
+	}
+	table := formatTable(results, nil, r.footerFilter(q.Footer))
+	if q.RetiringWithinDays > 0 && len(results) > 0 {
+		table += "\n\n" + retirementList(results, now)
+	}
+	if note := r.shadowNote(results); note != "" {
+		table += "\n\n" + note
+	}
+	if q.Provider != "" {
+		if note := r.coverageNote(providerOf(results)); note != "" {
+			table += "\n\n" + note
+		}
+	}
+	return table
+}
+
+// ListMatches returns the models ListModels lists, in table order, or nil
+// when a filter is invalid.
+func (r *Registry) ListMatches(q ListQuery) []models.Model {
+	results, msg := r.listMatches(q, time.Now())
+	if msg != "" {
+		return nil
+	}
+	return SortedByProvider(results)
+}
+
+// listMatches applies list_models' filters, or explains why one of them is
+// invalid.
+func (r *Registry) listMatches(q ListQuery, now time.Time) ([]models.Model, string) {
+	if msg := CheckCapability(q.Capability); msg != "" {
+		return nil, msg
+	}
+	if q.RetiringWithinDays < 0 {
+		return nil, fmt.Sprintf("retiring_within_days must be positive, got %d.", q.RetiringWithinDays)
+	}
+	results := r.FilterModels(q.Provider, q.Status, q.Capability, q.Sovereignty, q.Exclude)
+	if q.Maturity != "" {
+		min := models.Maturity(strings.ToLower(strings.TrimSpace(q.Maturity)))
+		if !min.Valid() {
+			return nil, fmt.Sprintf("Unknown maturity '%s'. Use stable, preview, or experimental.", q.Maturity)
+		}
+		results = filterMaturity(results, min)
+	}
+	if q.RetiringWithinDays > 0 {
+		results = filterRetiring(results, now, q.RetiringWithinDays)
+	}
+	return results, ""
 }
 
-// ListModels returns a markdown table of models with optional filters.
-func ListModels(provider, status, capability string) string {
-	results := FilterModels(provider, status, capability)
-	return FormatTable(results)
+// CheckCapability explains why capability cannot be filtered on, or returns
+// "" if it can.
+func CheckCapability(capability string) string {
+	if capability == "" {
+		return ""
+	}
+	switch strings.ToLower(capability) {
+	case "default", "provider_default", "provider-default":
+		return ""
+	}
+	c, ok := models.ParseCapability(capability)
+	if !ok {
+		return fmt.Sprintf("Unknown capability '%s'. Use one of: %s, or default.", capability, models.JoinCapabilities(models.Capabilities))
+	}
+	if !c.Tracked() {
+		return fmt.Sprintf("Capability '%s' is not recorded in the registry data yet, so no model can be matched on it. Filterable now: %s, or default.", c, models.JoinCapabilities(models.TrackedCapabilities()))
+	}
+	return ""
 }
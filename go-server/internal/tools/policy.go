@@ -0,0 +1,176 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"go-server/internal/models"
+)
+
+// Policy is an organization allowlist/denylist enforced across all tools.
+// Blocked models are dropped from list_models, search_models, and
+// recommend_model, and check_model_status explains why a model is blocked.
+// Zero-valued fields impose no restriction.
+//
+// A Shadow policy is evaluated but not enforced: blocked models stay in
+// results, each output notes which of its models would be blocked and why,
+// and every would-be block is reported to the shadow observer (the
+// mcp_policy_shadow_blocks_total metric), so a policy can be trialled
+// before it is switched on.
+type Policy struct {
+	AllowedProviders []string `json:"allowed_providers,omitempty"`
+	BannedModels     []string `json:"banned_models,omitempty"`
+	MaxPricingInput  float64  `json:"max_pricing_input,omitempty"`
+	MaxPricingOutput float64  `json:"max_pricing_output,omitempty"`
+	RequireStable    bool     `json:"require_stable,omitempty"`
+	Shadow           bool     `json:"shadow,omitempty"`
+
+	allowed map[string]bool
+	banned  map[string]bool
+}
+
+// LoadPolicy reads a JSON policy file. Unknown fields are rejected so a
+// typo can't silently disable a restriction.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var p Policy
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("parse policy %s: %w", path, err)
+	}
+	p.compile()
+	return &p, nil
+}
+
+var activePolicy atomic.Pointer[Policy]
+
+// SetPolicy installs p as the organization policy. nil removes it.
+// p must not be modified afterwards.
+func SetPolicy(p *Policy) {
+	if p != nil {
+		p.compile()
+	}
+	activePolicy.Store(p)
+}
+
+// ActivePolicy returns the installed policy, or nil.
+func ActivePolicy() *Policy {
+	return activePolicy.Load()
+}
+
+// compile resolves provider and model aliases once so lookups are cheap.
+// Banned IDs resolve through the base registry's aliases, which tenant
+// overlays do not change.
+func (p *Policy) compile() {
+	base := BaseRegistry()
+	p.allowed = make(map[string]bool, len(p.AllowedProviders))
+	for _, name := range p.AllowedProviders {
+		p.allowed[resolveProvider(strings.TrimSpace(name))] = true
+	}
+	p.banned = make(map[string]bool, len(p.BannedModels))
+	for _, id := range p.BannedModels {
+		p.banned[base.resolveModelID(strings.TrimSpace(id))] = true
+	}
+}
+
+// BlockReason returns why m is blocked by the policy, or "" if it is allowed.
+// It reports the rules' verdict in shadow mode too; use blocks to decide
+// whether to drop a model.
+func (p *Policy) BlockReason(m models.Model) string {
+	_, reason := p.violation(m)
+	return reason
+}
+
+// violation returns the policy field m violates and why, or "", "".
+func (p *Policy) violation(m models.Model) (rule, reason string) {
+	if p == nil {
+		return "", ""
+	}
+	switch {
+	case p.banned[strings.ToLower(m.ID)]:
+		return "banned_models", "model is banned"
+	case len(p.allowed) > 0 && !p.allowed[strings.ToLower(m.Provider)]:
+		return "allowed_providers", fmt.Sprintf("provider %s is not in the allowed providers", m.Provider)
+	case p.MaxPricingInput > 0 && m.PricingInput > p.MaxPricingInput:
+		return "max_pricing_input", fmt.Sprintf("input price $%.2f exceeds the $%.2f ceiling", m.PricingInput, p.MaxPricingInput)
+	case p.MaxPricingOutput > 0 && m.PricingOutput > p.MaxPricingOutput:
+		return "max_pricing_output", fmt.Sprintf("output price $%.2f exceeds the $%.2f ceiling", m.PricingOutput, p.MaxPricingOutput)
+	case p.RequireStable && !isStable(m):
+		return "require_stable", "only stable models are allowed"
+	}
+	return "", ""
+}
+
+// blocks reports whether the policy is enforced and blocks m.
+func (p *Policy) blocks(m models.Model) bool {
+	return p != nil && !p.Shadow && p.BlockReason(m) != ""
+}
+
+var shadowObserver atomic.Pointer[func(rule string)]
+
+// SetShadowObserver installs fn to be called once per would-be block under a
+// shadow policy, with the policy field that would block the model. nil
+// removes it.
+func SetShadowObserver(fn func(rule string)) {
+	if fn == nil {
+		shadowObserver.Store(nil)
+		return
+	}
+	shadowObserver.Store(&fn)
+}
+
+// shadowNote returns a note naming the models in ms that a shadow policy
+// would block, and reports each to the shadow observer. It returns "" when
+// the policy is enforced or absent, or blocks none of ms.
+func (r *Registry) shadowNote(ms []models.Model) string {
+	p := r.Policy()
+	if p == nil || !p.Shadow {
+		return ""
+	}
+	observe := shadowObserver.Load()
+	var blocked []string
+	for _, m := range ms {
+		rule, reason := p.violation(m)
+		if rule == "" {
+			continue
+		}
+		blocked = append(blocked, fmt.Sprintf("`%s` (%s)", m.ID, reason))
+		if observe != nil {
+			(*observe)(rule)
+		}
+	}
+	if len(blocked) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("**Org policy (shadow mode):** %d model(s) here would be blocked once the policy is enforced: %s.", len(blocked), strings.Join(blocked, ", "))
+}
+
+// isStable reports whether m is current and not a preview or beta release.
+func isStable(m models.Model) bool {
+	id := strings.ToLower(m.ID)
+	return m.Status == "current" && !strings.Contains(id, "preview") && !strings.Contains(id, "beta")
+}
+
+// applyPolicy drops models blocked by the active policy. A shadow policy
+// drops nothing; callers annotate what it would block with shadowNote.
+func (r *Registry) applyPolicy(ms []models.Model) []models.Model {
+	p := r.Policy()
+	if p == nil || p.Shadow {
+		return ms
+	}
+	var filtered []models.Model
+	for _, m := range ms {
+		if !p.blocks(m) {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"go-server/internal/status"
+)
+
+// CheckProviderStatusInput holds parameters for the check_provider_status tool.
+type CheckProviderStatusInput struct {
+	Provider string `json:"provider,omitempty" jsonschema:"Provider to check (e.g. openai, anthropic, google). Omit to check all providers with a status feed"`
+	FormatInput
+}
+
+// FormatProviderStatus renders provider status results as a markdown table
+// followed by any active incidents.
+func FormatProviderStatus(statuses []status.ProviderStatus) string {
+	if len(statuses) == 0 {
+		return "No provider status feeds configured."
+	}
+
+	rows := []string{
+		"| Provider | Status | Details | Status Page |",
+		"|----------|--------|---------|-------------|",
+	}
+	var incidents []string
+	for _, s := range statuses {
+		label := s.Indicator
+		if s.HasOutage() {
+			label = "**" + s.Indicator + " outage**"
+		}
+		details := s.Description
+		if s.Err != nil {
+			details = fmt.Sprintf("%s (%v)", s.Description, s.Err)
+		}
+		rows = append(rows, fmt.Sprintf("| %s | %s | %s | %s |", s.Provider, label, details, s.Page))
+		for _, inc := range s.Incidents {
+			line := fmt.Sprintf("- **%s**: %s (impact: %s, status: %s)", s.Provider, inc.Name, inc.Impact, inc.Status)
+			if inc.URL != "" {
+				line += " — " + inc.URL
+			}
+			incidents = append(incidents, line)
+		}
+	}
+
+	out := strings.Join(rows, "\n")
+	if len(incidents) > 0 {
+		out += "\n\n### Active Incidents\n\n" + strings.Join(incidents, "\n")
+	}
+	return out
+}
+
+// ProviderStatusUnknown returns the message for a provider without a status feed.
+func ProviderStatusUnknown(provider string, available []string) string {
+	return fmt.Sprintf("No status feed configured for `%s`. Available: %s",
+		provider, strings.Join(available, ", "))
+}
@@ -0,0 +1,304 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"go-server/internal/models"
+)
+
+// ListProvidersInput holds parameters for the list_providers tool.
+type ListProvidersInput struct {
+	FormatInput
+}
+
+// GetProviderInfoInput holds parameters for the get_provider_info tool.
+type GetProviderInfoInput struct {
+	Provider string `json:"provider" jsonschema:"Provider name or alias, e.g. anthropic, kimi, or glm (case-insensitive)"`
+	FormatInput
+}
+
+// providerCounts tallies the registry's models per provider and status.
+func (r *Registry) providerCounts() map[string]map[string]int {
+	counts := make(map[string]map[string]int)
+	for _, m := range r.models {
+		if counts[m.Provider] == nil {
+			counts[m.Provider] = make(map[string]int)
+		}
+		counts[m.Provider][m.Status]++
+	}
+	return counts
+}
+
+// listedProviders returns the providers list_providers shows in
+// alphabetical order: every provider the registry has models for, including
+// ones only a tenant overlay adds, plus any in Providers without models.
+func listedProviders(stats map[string]*providerStats) []string {
+	names := providerNames()
+	for name := range stats {
+		if _, ok := models.Providers[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return strings.ToLower(names[i]) < strings.ToLower(names[j]) })
+	return names
+}
+
+// providerNames returns the Providers keys in alphabetical order.
+func providerNames() []string {
+	names := make([]string, 0, len(models.Providers))
+	for name := range models.Providers {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return strings.ToLower(names[i]) < strings.ToLower(names[j]) })
+	return names
+}
+
+// ChurnWindowDays is how far back list_providers counts registry changes
+// per provider.
+const ChurnWindowDays = 90
+
+// churnTemperature labels a provider's change count over ChurnWindowDays, so
+// agents can weigh a stable provider against a fast-moving one.
+func churnTemperature(n int) string {
+	switch {
+	case n == 0:
+		return "cold"
+	case n <= 3:
+		return "mild"
+	case n <= 9:
+		return "warm"
+	default:
+		return "hot"
+	}
+}
+
+// providerStats summarizes one provider's models for list_providers.
+type providerStats struct {
+	counts             map[string]int // by status
+	total              int
+	cheapest, flagship models.Model // current models; zero when none
+	caps               map[models.Capability]int
+}
+
+// providerStats computes providerStats for every provider with models. The
+// cheapest model has the lowest blended price among current, priced models;
+// the flagship has the highest, preferring stable releases over previews.
+// Models blocked by policy are counted but never picked.
+func (r *Registry) providerStats() map[string]*providerStats {
+	policy := r.Policy()
+	stats := make(map[string]*providerStats)
+	for _, m := range r.models {
+		s := stats[m.Provider]
+		if s == nil {
+			s = &providerStats{counts: make(map[string]int), caps: make(map[models.Capability]int)}
+			stats[m.Provider] = s
+		}
+		s.counts[m.Status]++
+		s.total++
+		if m.Status != "current" {
+			continue
+		}
+		for _, c := range models.TrackedCapabilities() {
+			if m.Has(c) {
+				s.caps[c]++
+			}
+		}
+		if policy.blocks(m) || m.BlendedPrice() <= 0 {
+			continue
+		}
+		if s.cheapest.ID == "" || m.BlendedPrice() < s.cheapest.BlendedPrice() ||
+			(m.BlendedPrice() == s.cheapest.BlendedPrice() && m.ID < s.cheapest.ID) {
+			s.cheapest = m
+		}
+		if s.flagship.ID == "" || outranksFlagship(m, s.flagship) {
+			s.flagship = m
+		}
+	}
+	return stats
+}
+
+// outranksFlagship reports whether m is a better flagship pick than cur: a
+// stable release over a preview, then the higher blended price, then the
+// newer release.
+func outranksFlagship(m, cur models.Model) bool {
+	if ms, cs := m.Maturity == models.MaturityStable, cur.Maturity == models.MaturityStable; ms != cs {
+		return ms
+	}
+	if m.BlendedPrice() != cur.BlendedPrice() {
+		return m.BlendedPrice() > cur.BlendedPrice()
+	}
+	if m.ReleaseDate != cur.ReleaseDate {
+		return m.ReleaseDate > cur.ReleaseDate
+	}
+	return m.ID < cur.ID
+}
+
+// ListProviders lists every provider in three tables: an overview with model
+// counts by status, the cheapest and flagship current models, when the
+// updater last verified the provider (verified, from its status file), and
+// churn (how many changelog entries touched its models in the last
+// ChurnWindowDays); how many current models have each capability; and how
+// to reach its API. A nil churn means the changelog has no entries yet, so
+// the Changes column shows — instead of rating every provider cold.
+func (r *Registry) ListProviders(churn map[string]int, verified map[string]time.Time) string {
+	stats := r.providerStats()
+	names := listedProviders(stats)
+	tracked := models.TrackedCapabilities()
+
+	lines := []string{
+		fmt.Sprintf("| Provider | Models | Current | Legacy | Deprecated | Cheapest | Flagship | Last verified | Changes (%dd) |", ChurnWindowDays),
+		"|----------|--------|---------|--------|------------|----------|----------|---------------|--------------|",
+	}
+	for _, name := range names {
+		s := stats[name]
+		if s == nil {
+			s = &providerStats{}
+		}
+		last := "—"
+		if t, ok := verified[name]; ok && !t.IsZero() {
+			last = t.UTC().Format("2006-01-02")
+		}
+		changes := "—"
+		if churn != nil {
+			changes = fmt.Sprintf("%d (%s)", churn[name], churnTemperature(churn[name]))
+		}
+		lines = append(lines, fmt.Sprintf("| %s | %d | %d | %d | %d | %s | %s | %s | %s |",
+			name, s.total, s.counts["current"], s.counts["legacy"], s.counts["deprecated"],
+			priceTag(s.cheapest), priceTag(s.flagship), last, changes))
+	}
+
+	header := "| Provider |"
+	rule := "|----------|"
+	for _, c := range tracked {
+		header += " " + string(c) + " |"
+		rule += strings.Repeat("-", len(c)+2) + "|"
+	}
+	lines = append(lines, "", "**Capability coverage** (current models with each capability):", "", header, rule)
+	for _, name := range names {
+		s := stats[name]
+		if s == nil || s.counts["current"] == 0 {
+			continue
+		}
+		row := "| " + name + " |"
+		for _, c := range tracked {
+			row += fmt.Sprintf(" %d/%d |", s.caps[c], s.counts["current"])
+		}
+		lines = append(lines, row)
+	}
+
+	lines = append(lines, "", "**Access:**", "",
+		"| Provider | OpenAI-compatible | API Base URL | Auth Env Var |",
+		"|----------|-------------------|--------------|--------------|")
+	for _, name := range names {
+		p, ok := models.Providers[name]
+		if !ok {
+			p.Name = name
+		}
+		lines = append(lines, fmt.Sprintf("| %s | %s | %s | %s |",
+			p.Name, compatibility(p.Name), codeOrDash(p.APIBaseURL), codeOrDash(p.AuthEnvVar)))
+	}
+
+	changesNote := "Changes counts registry additions, removals, and edits to the provider's models: cold (none), mild (1-3), warm (4-9), hot (10+). A cold provider's defaults stay put; a hot one ships often but may need more upkeep."
+	if churn == nil {
+		changesNote = "Changes is — because the registry changelog has no entries yet, so there is no change history to count."
+	}
+	lines = append(lines, "",
+		"Cheapest and Flagship are the current models with the lowest and highest blended price per 1M tokens, the flagship preferring stable releases. Last verified is the updater's last successful check of the provider's docs or API; — means never, or no status file is configured.",
+		changesNote,
+		"Use get_provider_info for docs and status pages, aliases, and the OpenAI SDK base_url.")
+	var shown []models.Model
+	for _, name := range names {
+		if s := stats[name]; s != nil {
+			for _, m := range []models.Model{s.cheapest, s.flagship} {
+				if m.ID != "" && !slices.ContainsFunc(shown, func(x models.Model) bool { return x.ID == m.ID }) {
+					shown = append(shown, m)
+				}
+			}
+		}
+	}
+	if note := r.shadowNote(shown); note != "" {
+		lines = append(lines, "", note)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// priceTag renders a model as its ID and blended price, or "—" for none.
+func priceTag(m models.Model) string {
+	if m.ID == "" {
+		return "—"
+	}
+	return fmt.Sprintf("`%s` ($%.2f)", m.ID, m.BlendedPrice())
+}
+
+// GetProviderInfo describes one provider: its endpoints, docs, auth, status
+// page, aliases, and the models the registry tracks for it.
+func (r *Registry) GetProviderInfo(provider string) string {
+	p, ok := models.LookupProvider(provider)
+	if !ok {
+		return fmt.Sprintf("Provider '%s' not found. Known providers: %s", provider, strings.Join(providerNames(), ", "))
+	}
+
+	counts := r.providerCounts()[p.Name]
+	var current []string
+	for _, m := range r.models {
+		if m.Provider == p.Name && m.Status == "current" {
+			current = append(current, "`"+m.ID+"`")
+		}
+	}
+	sort.Strings(current)
+	aliases := "—"
+	if len(p.Aliases) > 0 {
+		aliases = strings.Join(p.Aliases, ", ")
+	}
+
+	lines := []string{
+		"## " + p.Name,
+		"",
+		"| Field | Value |",
+		"|-------|-------|",
+		"| API Base URL | " + codeOrDash(p.APIBaseURL) + " |",
+		"| OpenAI SDK | " + endpointDetail(p.Name) + " |",
+		"| Auth Env Var | " + codeOrDash(p.AuthEnvVar) + " |",
+		"| Docs | " + p.DocsURL + " |",
+		"| Status Page | " + orDash(p.StatusPageURL) + " |",
+		"| Aliases | " + aliases + " |",
+		fmt.Sprintf("| Models | %d current, %d legacy, %d deprecated |", counts["current"], counts["legacy"], counts["deprecated"]),
+	}
+	if len(current) > 0 {
+		lines = append(lines, "", "**Current models:** "+strings.Join(current, ", "))
+	}
+	if note := r.coverageNote(p.Name); note != "" {
+		lines = append(lines, "", note)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// compatibility renders a provider's OpenAI-compatible flag from Endpoints,
+// distinguishing "no" from providers whose compatibility hasn't been recorded.
+func compatibility(provider string) string {
+	e, ok := models.Endpoints[provider]
+	if !ok {
+		return "Unknown"
+	}
+	return yesNo(e.OpenAICompatible)
+}
+
+// codeOrDash renders s as inline code, or "—" when empty.
+func codeOrDash(s string) string {
+	if s == "" {
+		return "—"
+	}
+	return "`" + s + "`"
+}
+
+// orDash renders s, or "—" when empty.
+func orDash(s string) string {
+	if s == "" {
+		return "—"
+	}
+	return s
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"math"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
@@ -13,12 +12,18 @@ import (
 
 // RecommendModelInput holds parameters for the recommend_model tool.
 type RecommendModelInput struct {
-	Task   string `json:"task" jsonschema:"Description of the task you need a model for"`
-	Budget string `json:"budget,omitempty" jsonschema:"Budget level: cheap/low, moderate/medium, or expensive/high/unlimited"`
+	Task         string             `json:"task" jsonschema:"Description of the task you need a model for"`
+	Budget       string             `json:"budget,omitempty" jsonschema:"Budget level: cheap/low, moderate/medium, or expensive/high/unlimited"`
+	Sovereignty  string             `json:"sovereignty,omitempty" jsonschema:"Data sovereignty requirement: eu (only recommend EU-hosted models)"`
+	MinProviders int                `json:"min_providers,omitempty" jsonschema:"Require the top recommendations to span at least this many distinct providers (max 3)"`
+	AvoidOutages bool               `json:"avoid_outages,omitempty" jsonschema:"Skip providers whose status page reports a major or critical outage"`
+	Weights      map[string]float64 `json:"weights,omitempty" jsonschema:"Override scoring weights by name, e.g. {\"reasoning\": 8, \"recency\": 0}. Names: reasoning, coding_reasoning, coding_specialist, vision, vision_missing, long_context, recency, cheap_penalty_over_3, cheap_penalty_over_10, moderate_penalty_over_10, churn_risk"`
+	ExcludeInput
+	FormatInput
 }
 
-// normalizeBudget maps common budget synonyms to canonical values.
-func normalizeBudget(b string) string {
+// NormalizeBudget maps common budget synonyms to cheap, moderate, or expensive.
+func NormalizeBudget(b string) string {
 	switch strings.ToLower(b) {
 	case "low", "cheap", "budget", "free", "minimal":
 		return "cheap"
@@ -31,24 +36,124 @@ func normalizeBudget(b string) string {
 	}
 }
 
-// RecommendModel scores current models against a task description and budget,
-// returning the top 3 recommendations as a markdown list.
-func RecommendModel(task, budget string) string {
-	budget = normalizeBudget(budget)
-	taskLower := strings.ToLower(task)
+// RecommendQuery describes what recommend_model picks a model for. A
+// non-empty Sovereignty and any exclusions restrict candidates the same way
+// as FilterModels. MinProviders > 1 requires the recommendations to span
+// that many distinct providers. Weights overrides the named scoring weights
+// for this call, on top of the server's configured weights.
+type RecommendQuery struct {
+	Task         string
+	Budget       string
+	Sovereignty  string
+	MinProviders int
+	Exclude      Exclusions
+	Weights      map[string]float64
+}
 
-	// Collect current models
-	var current []models.Model
-	for _, m := range models.Models {
-		if m.Status == "current" {
-			current = append(current, m)
+// RecommendModel scores current models against q's task description and
+// budget, returning the top 3 recommendations as a markdown list.
+func (r *Registry) RecommendModel(q RecommendQuery) string {
+	top, err := r.Recommend(q)
+	return r.FormatRecommendations(q, top, err)
+}
+
+// FormatRecommendations renders the picks and error Recommend returned for
+// q as RecommendModel's markdown, so callers that also need the picks as
+// data score the registry once.
+func (r *Registry) FormatRecommendations(q RecommendQuery, top []Recommendation, err error) string {
+	if err != nil {
+		return fmt.Sprintf("Invalid weights: %v.", err)
+	}
+	w, _ := activeScoringWeights().With(q.Weights) // Recommend already validated them
+	q.Budget = NormalizeBudget(q.Budget)
+	if len(top) == 0 {
+		return "No current models remain after applying the sovereignty, exclusion, and org policy filters."
+	}
+	stability := wantsStability(strings.ToLower(q.Task))
+
+	lines := []string{
+		fmt.Sprintf("## Recommendations for: *%s*", q.Task),
+		fmt.Sprintf("**Budget:** %s", q.Budget),
+	}
+	if q.Sovereignty != "" {
+		lines = append(lines, fmt.Sprintf("**Sovereignty:** %s", strings.ToLower(q.Sovereignty)))
+	}
+	if stability {
+		lines = append(lines, fmt.Sprintf("**Stability:** penalizing lineages whose recent versions were deprecated within %d months of release", fastDeprecationMonths))
+	}
+	if custom := w.diff(DefaultScoringWeights()); len(custom) > 0 {
+		lines = append(lines, fmt.Sprintf("**Scoring weights:** %s", strings.Join(custom, ", ")))
+	}
+	lines = append(lines, "")
+	picks := make([]models.Model, len(top))
+	for i, rec := range top {
+		m := rec.Model
+		picks[i] = m
+		capStr := "standard"
+		if caps := m.Capabilities(); len(caps) > 0 {
+			capStr = models.JoinCapabilities(caps)
+		}
+		lines = append(lines, fmt.Sprintf(
+			"%d. **%s** (`%s`)\n   - Provider: %s | Capabilities: %s\n   - Pricing: $%.2f / $%.2f per 1M tokens\n   - Context: %s tokens\n",
+			i+1, m.DisplayName, m.ID,
+			m.Provider, capStr,
+			m.PricingInput, m.PricingOutput,
+			models.FormatInt(m.ContextWindow),
+		))
+		if fast := rec.churn; len(fast) > 0 {
+			lines[len(lines)-1] = strings.TrimSuffix(lines[len(lines)-1], "\n") + "\n   - Churn risk: " + describeChurn(fast) + "\n"
 		}
 	}
+	if note := r.shadowNote(picks); note != "" {
+		lines = append(lines, note)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Recommendation is one of recommend_model's picks, best first.
+type Recommendation struct {
+	Model models.Model
+	Score float64
+	churn []predecessorLifetime // fast-deprecated predecessors, for stability tasks
+}
+
+// ChurnRisk describes the model's predecessors that were deprecated within
+// months of release, or returns "" when the task didn't ask for stability or
+// none were.
+func (rec Recommendation) ChurnRisk() string {
+	return strings.ReplaceAll(describeChurn(rec.churn), "`", "")
+}
+
+// Recommend returns RecommendModel's picks as data, for callers that render
+// them themselves. It returns no picks when filters leave no current
+// models, and an error for unknown or invalid weights.
+func (r *Registry) Recommend(q RecommendQuery) ([]Recommendation, error) {
+	w, err := activeScoringWeights().With(q.Weights)
+	if err != nil {
+		return nil, err
+	}
+	q.Budget = NormalizeBudget(q.Budget)
+	return r.recommend(q, w), nil
+}
+
+// recommend scores current models against q.Task and returns the top 3,
+// scored with w rather than q.Weights. q.Budget must already be normalized.
+func (r *Registry) recommend(q RecommendQuery, w ScoringWeights) []Recommendation {
+	taskLower := strings.ToLower(q.Task)
+
+	// Collect current models
+	current := r.FilterModels("", "current", "", q.Sovereignty, q.Exclude)
+	if len(current) == 0 {
+		return nil
+	}
 
 	type scored struct {
 		score float64
 		model models.Model
 	}
+	stability := wantsStability(taskLower)
+	churn := make(map[string][]predecessorLifetime)
 
 	var results []scored
 	for _, m := range current {
@@ -61,7 +166,7 @@ func RecommendModel(task, budget string) string {
 			strings.Contains(taskLower, "code") ||
 			strings.Contains(taskLower, "programming") {
 			if m.Reasoning {
-				score += 3
+				score += w.CodingReasoning
 			}
 			if m.ContextWindow >= 200_000 {
 				score += 1
@@ -69,7 +174,7 @@ func RecommendModel(task, budget string) string {
 			if strings.Contains(m.ID, "codestral") || strings.Contains(m.ID, "devstral") ||
 				strings.Contains(m.ID, "codex") || strings.Contains(m.ID, "-code-") ||
 				strings.Contains(m.ID, "kat-coder") {
-				score += 2
+				score += w.CodingSpecialist
 			}
 		}
 
@@ -77,10 +182,10 @@ func RecommendModel(task, budget string) string {
 		if strings.Contains(taskLower, "vision") ||
 			strings.Contains(taskLower, "image") ||
 			strings.Contains(taskLower, "screenshot") {
-			if m.Vision {
-				score += 4
+			if m.Has(models.CapVision) {
+				score += w.Vision
 			} else {
-				score -= 10
+				score -= w.VisionMissing
 			}
 		}
 
@@ -88,8 +193,8 @@ func RecommendModel(task, budget string) string {
 		if (strings.Contains(taskLower, "reason") ||
 			strings.Contains(taskLower, "think") ||
 			strings.Contains(taskLower, "math") ||
-			strings.Contains(taskLower, "logic")) && m.Reasoning {
-			score += 5
+			strings.Contains(taskLower, "logic")) && m.Has(models.CapReasoning) {
+			score += w.Reasoning
 		}
 
 		// Long context
@@ -97,9 +202,9 @@ func RecommendModel(task, budget string) string {
 			strings.Contains(taskLower, "large document") ||
 			strings.Contains(taskLower, "summariz") {
 			if m.ContextWindow >= 1_000_000 {
-				score += 4
+				score += w.LongContext
 			} else if m.ContextWindow >= 200_000 {
-				score += 2
+				score += w.LongContext / 2
 			}
 		}
 
@@ -129,15 +234,15 @@ func RecommendModel(task, budget string) string {
 		}
 
 		// ── Budget modifier ──
-		switch budget {
+		switch q.Budget {
 		case "cheap":
 			// Strongly reward cheap models, heavily penalize expensive ones
 			score += math.Max(0, 3-m.PricingInput)
 			if m.PricingInput > 3 {
-				score -= 3
+				score -= w.CheapPenaltyOver3
 			}
 			if m.PricingInput > 10 {
-				score -= 5
+				score -= w.CheapPenaltyOver10
 			}
 			// Invert quality signal: reward cheap models
 			score += math.Max(0, 2-m.PricingInput*0.5)
@@ -148,14 +253,22 @@ func RecommendModel(task, budget string) string {
 		default: // "moderate"
 			// Slight penalty for very expensive models
 			if m.PricingInput > 10 {
-				score -= 2
+				score -= w.ModeratePenaltyOver10
 			}
 			// Mild quality signal
 			score += math.Min(m.PricingInput*0.2, 1)
 		}
 
-		// Recency bonus: newer models get a boost (0 to 1.5 points)
-		score += recencyBonus(m.ReleaseDate)
+		// Recency bonus: newer models get a boost (0 to w.Recency points)
+		score += recencyBonus(m.ReleaseDate) / 1.5 * w.Recency
+
+		// Churn risk: for stable, long-term picks, avoid lineages whose
+		// recent versions were deprecated within months of release.
+		if stability {
+			risk, fast := r.churnRisk(m)
+			score -= risk * w.ChurnRisk
+			churn[m.ID] = fast
+		}
 
 		results = append(results, scored{score: score, model: m})
 	}
@@ -171,56 +284,77 @@ func RecommendModel(task, budget string) string {
 		return results[i].model.DisplayName < results[j].model.DisplayName
 	})
 
-	top := results
-	if len(top) > 3 {
-		top = top[:3]
+	ranked := make([]models.Model, len(results))
+	scores := make(map[string]float64, len(results))
+	for i, r := range results {
+		ranked[i] = r.model
+		scores[r.model.ID] = r.score
 	}
 
-	lines := []string{
-		fmt.Sprintf("## Recommendations for: *%s*", task),
-		fmt.Sprintf("**Budget:** %s", budget),
-		"",
+	picks := pickDiverse(ranked, 3, q.MinProviders)
+	top := make([]Recommendation, len(picks))
+	for i, m := range picks {
+		top[i] = Recommendation{Model: m, Score: scores[m.ID], churn: churn[m.ID]}
 	}
-	for i, s := range top {
-		var caps []string
-		if s.model.Vision {
-			caps = append(caps, "vision")
+	return top
+}
+
+// pickDiverse selects up to n models from ranked (best first) while ensuring
+// the selection spans at least minProviders distinct providers when enough
+// providers are available. A same-provider model is only taken when the
+// remaining slots can still satisfy the diversity requirement; otherwise the
+// next-best model from an unseen provider is promoted.
+func pickDiverse(ranked []models.Model, n, minProviders int) []models.Model {
+	if minProviders > n {
+		minProviders = n
+	}
+	seen := make(map[string]bool)
+	taken := make([]bool, len(ranked))
+	var picked []models.Model
+	for len(picked) < n {
+		needNew := minProviders - len(seen)
+		slotsLeft := n - len(picked)
+		idx := -1
+		for i, m := range ranked {
+			if taken[i] {
+				continue
+			}
+			if needNew >= slotsLeft && seen[m.Provider] {
+				continue
+			}
+			idx = i
+			break
 		}
-		if s.model.Reasoning {
-			caps = append(caps, "reasoning")
+		if idx == -1 {
+			// Not enough distinct providers — relax the constraint and fill
+			// remaining slots by rank.
+			for i := range ranked {
+				if !taken[i] {
+					idx = i
+					break
+				}
+			}
 		}
-		capStr := "standard"
-		if len(caps) > 0 {
-			capStr = strings.Join(caps, ", ")
+		if idx == -1 {
+			break
 		}
-		lines = append(lines, fmt.Sprintf(
-			"%d. **%s** (`%s`)\n   - Provider: %s | Capabilities: %s\n   - Pricing: $%.2f / $%.2f per 1M tokens\n   - Context: %s tokens\n",
-			i+1, s.model.DisplayName, s.model.ID,
-			s.model.Provider, capStr,
-			s.model.PricingInput, s.model.PricingOutput,
-			models.FormatInt(s.model.ContextWindow),
-		))
+		taken[idx] = true
+		seen[ranked[idx].Provider] = true
+		picked = append(picked, ranked[idx])
 	}
-
-	return strings.Join(lines, "\n")
+	return picked
 }
 
 // recencyBonus returns a score bonus (0 to 1.5) based on how recent the model
 // release date is. Dates use "YYYY-MM" format. Models released in the last 6
 // months get full bonus, decaying to 0 at 18 months.
 func recencyBonus(releaseDate string) float64 {
-	parts := strings.Split(releaseDate, "-")
-	if len(parts) < 2 {
-		return 0
-	}
-	year, err1 := strconv.Atoi(parts[0])
-	month, err2 := strconv.Atoi(parts[1])
-	if err1 != nil || err2 != nil {
+	releaseMonths, ok := releaseMonth(releaseDate)
+	if !ok {
 		return 0
 	}
 
 	now := time.Now()
-	releaseMonths := year*12 + month
 	currentMonths := now.Year()*12 + int(now.Month())
 	monthsAgo := float64(currentMonths - releaseMonths)
 
@@ -0,0 +1,178 @@
+package tools
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"go-server/internal/models"
+	"go-server/internal/resources"
+)
+
+// Registry is the set of models tools run against, with its own lookup
+// caches. The base registry is the built-in data under the server-wide
+// policy; tenant registries layer an overlay of custom models and their own
+// policy on top of it, so one deployment can serve several teams.
+type Registry struct {
+	tenant  string
+	models  map[string]models.Model
+	aliases map[string]string // models.Aliases when the registry was built
+	policy  *Policy
+
+	lowerKeysOnce sync.Once
+	lowerKeysList []lowerKey
+	byModelOnce   sync.Once
+	byModel       map[string][]string
+	aliasIdxOnce  sync.Once
+	aliasIdx      map[string]string
+	infoOnce      sync.Once
+	info          RegistryInfo
+	allJSONOnce   sync.Once
+	allJSON       string
+	findCache     *lruCache[string, findResult]
+	suggestCache  *lruCache[suggestKey, []string]
+}
+
+// baseRegistry is replaced whole by UseModels. It is an atomic pointer
+// rather than lock-guarded so BaseRegistry stays cheap on the lookup path.
+var baseRegistry atomic.Pointer[Registry]
+
+func init() {
+	baseRegistry.Store(newRegistry("", models.Models, nil))
+}
+
+func newRegistry(tenant string, ms map[string]models.Model, p *Policy) *Registry {
+	_, aliases := models.Snapshot()
+	return &Registry{
+		tenant:       tenant,
+		models:       ms,
+		aliases:      aliases,
+		policy:       p,
+		findCache:    newLRUCache[string, findResult](lookupCacheSize),
+		suggestCache: newLRUCache[suggestKey, []string](lookupCacheSize),
+	}
+}
+
+// UseModels replaces the built-in models, for example with a models_file
+// loaded at startup or reloaded while serving, and swaps in a base registry
+// over them. Registries already handed out keep the data they were built
+// with, so a request in flight never sees a mix of old and new models.
+func UseModels(ms map[string]models.Model) {
+	models.Use(ms)
+	baseRegistry.Store(newRegistry("", ms, nil))
+}
+
+// BaseRegistry returns the built-in registry served on the default endpoints.
+func BaseRegistry() *Registry {
+	return baseRegistry.Load()
+}
+
+// NewTenantRegistry returns the base registry plus overlay, enforcing policy.
+// Overlay entries are keyed by model ID and replace base models with the same
+// ID, so a tenant can add private models or re-price shared ones. A nil
+// policy falls back to the server-wide policy.
+func NewTenantRegistry(tenant string, overlay map[string]models.Model, policy *Policy) (*Registry, error) {
+	base, _ := models.Snapshot()
+	merged := make(map[string]models.Model, len(base)+len(overlay))
+	for id, m := range base {
+		merged[id] = m
+	}
+	for id, m := range overlay {
+		if m.ID == "" {
+			m.ID = id
+		}
+		switch {
+		case m.ID != id:
+			return nil, fmt.Errorf("tenant %s: overlay key %q does not match model ID %q", tenant, id, m.ID)
+		case m.Provider == "":
+			return nil, fmt.Errorf("tenant %s: overlay model %q has no provider", tenant, id)
+		case m.Status != "current" && m.Status != "legacy" && m.Status != "deprecated":
+			return nil, fmt.Errorf("tenant %s: overlay model %q has invalid status %q", tenant, id, m.Status)
+		case m.Maturity != "" && !m.Maturity.Valid():
+			return nil, fmt.Errorf("tenant %s: overlay model %q has invalid maturity %q", tenant, id, m.Maturity)
+		}
+		if m.Maturity == "" {
+			m.Maturity = models.MaturityStable
+		}
+		merged[id] = m
+	}
+	if policy != nil {
+		policy.compile()
+	}
+	return newRegistry(tenant, merged, policy), nil
+}
+
+// Tenant returns the tenant name, or "" for the base registry.
+func (r *Registry) Tenant() string {
+	return r.tenant
+}
+
+// Models returns the registry's models keyed by ID. Callers must not modify it.
+func (r *Registry) Models() map[string]models.Model {
+	return r.models
+}
+
+// AllModelsJSON returns the model://registry/all document for the
+// registry. It is rendered once, entry by entry, and shared by every read
+// and session, so large registries aren't re-encoded per request.
+func (r *Registry) AllModelsJSON() string {
+	r.allJSONOnce.Do(func() {
+		r.allJSON = resources.AllModels(r.models)
+	})
+	return r.allJSON
+}
+
+// RegistryInfo summarizes a registry so clients can tell at connect time
+// whether a cached copy is stale, without calling any tools.
+type RegistryInfo struct {
+	Version      string         `json:"version"` // content hash of every model entry
+	Models       int            `json:"models"`
+	Providers    int            `json:"providers"`
+	Capabilities map[string]int `json:"capabilities"` // list_models capability filter → models matching it
+}
+
+// Info returns the registry's summary. It ignores org policy, so the version
+// only changes when model data does.
+func (r *Registry) Info() RegistryInfo {
+	r.infoOnce.Do(func() {
+		providers := make(map[string]bool)
+		tracked := models.TrackedCapabilities()
+		caps := map[string]int{"default": 0}
+		for _, c := range tracked {
+			caps[string(c)] = 0
+		}
+		for _, m := range r.models {
+			providers[m.Provider] = true
+			for _, c := range tracked {
+				if m.Has(c) {
+					caps[string(c)]++
+				}
+			}
+			if m.ProviderDefault {
+				caps["default"]++
+			}
+		}
+		// encoding/json sorts map keys, so equal registries hash equally.
+		data, _ := json.Marshal(r.models)
+		sum := sha256.Sum256(data)
+		r.info = RegistryInfo{
+			Version:      hex.EncodeToString(sum[:])[:12],
+			Models:       len(r.models),
+			Providers:    len(providers),
+			Capabilities: caps,
+		}
+	})
+	return r.info
+}
+
+// Policy returns the policy enforced on this registry: the tenant's own, or
+// the server-wide policy if it has none.
+func (r *Registry) Policy() *Policy {
+	if r.policy != nil {
+		return r.policy
+	}
+	return ActivePolicy()
+}
@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// ScoringWeights are recommend_model's tunable scoring constants. Task
+// bonuses apply when the task mentions the matching need; budget penalties
+// apply by input price per 1M tokens.
+type ScoringWeights struct {
+	Reasoning             float64 // reasoning model on a reasoning/math/logic task
+	CodingReasoning       float64 // reasoning model on a coding task
+	CodingSpecialist      float64 // code-specialized model on a coding task
+	Vision                float64 // vision model on an image task
+	VisionMissing         float64 // penalty for a text-only model on an image task
+	LongContext           float64 // 1M+ context on a long-document task; 200k+ earns half
+	Recency               float64 // bonus for a model released in the last 6 months, decaying to 0 at 18
+	CheapPenaltyOver3     float64 // cheap budget, input above $3
+	CheapPenaltyOver10    float64 // cheap budget, input above $10 (on top of the $3 penalty)
+	ModeratePenaltyOver10 float64 // moderate budget, input above $10
+	ChurnRisk             float64 // stability task, scaled by the share of immediate predecessors deprecated within 6 months
+}
+
+// DefaultScoringWeights returns the built-in weights.
+func DefaultScoringWeights() ScoringWeights {
+	return ScoringWeights{
+		Reasoning:             5,
+		CodingReasoning:       3,
+		CodingSpecialist:      2,
+		Vision:                4,
+		VisionMissing:         10,
+		LongContext:           4,
+		Recency:               1.5,
+		CheapPenaltyOver3:     3,
+		CheapPenaltyOver10:    5,
+		ModeratePenaltyOver10: 2,
+		ChurnRisk:             4,
+	}
+}
+
+// fields maps each weight's override name to its field.
+func (w *ScoringWeights) fields() map[string]*float64 {
+	return map[string]*float64{
+		"reasoning":                &w.Reasoning,
+		"coding_reasoning":         &w.CodingReasoning,
+		"coding_specialist":        &w.CodingSpecialist,
+		"vision":                   &w.Vision,
+		"vision_missing":           &w.VisionMissing,
+		"long_context":             &w.LongContext,
+		"recency":                  &w.Recency,
+		"cheap_penalty_over_3":     &w.CheapPenaltyOver3,
+		"cheap_penalty_over_10":    &w.CheapPenaltyOver10,
+		"moderate_penalty_over_10": &w.ModeratePenaltyOver10,
+		"churn_risk":               &w.ChurnRisk,
+	}
+}
+
+// WeightNames lists the override names accepted by With, sorted.
+func WeightNames() []string {
+	var w ScoringWeights
+	names := make([]string, 0, len(w.fields()))
+	for name := range w.fields() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// With returns w with the named weights replaced. Names are case-insensitive;
+// an unknown name or a negative value is an error.
+func (w ScoringWeights) With(overrides map[string]float64) (ScoringWeights, error) {
+	fields := w.fields()
+	for name, v := range overrides {
+		f, ok := fields[strings.ToLower(name)]
+		if !ok {
+			return w, fmt.Errorf("unknown scoring weight %q (valid: %s)", name, strings.Join(WeightNames(), ", "))
+		}
+		if v < 0 {
+			return w, fmt.Errorf("scoring weight %q must not be negative, got %g", name, v)
+		}
+		*f = v
+	}
+	return w, nil
+}
+
+// diff lists the weights that differ from base as "name=value", sorted.
+func (w ScoringWeights) diff(base ScoringWeights) []string {
+	baseFields := base.fields()
+	var out []string
+	for name, f := range w.fields() {
+		if *f != *baseFields[name] {
+			out = append(out, fmt.Sprintf("%s=%g", name, *f))
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+var scoringWeights atomic.Pointer[ScoringWeights]
+
+// SetScoringWeights installs the server-wide recommend_model weights.
+func SetScoringWeights(w ScoringWeights) {
+	scoringWeights.Store(&w)
+}
+
+// activeScoringWeights returns the installed weights, or the defaults.
+func activeScoringWeights() ScoringWeights {
+	if w := scoringWeights.Load(); w != nil {
+		return *w
+	}
+	return DefaultScoringWeights()
+}
@@ -2,20 +2,49 @@ package tools
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"go-server/internal/models"
 )
 
-// SearchModels searches for models by keyword across names, providers, and notes.
-// Multi-word queries require ALL words to match across any combination of fields.
-func SearchModels(query string) string {
+// SearchModels searches for models by keyword across names, providers, notes,
+// and aliases. Multi-word queries require ALL words to match across any
+// combination of fields. When a model only matched through an alias, the
+// results gain an "Also Known As" column showing which one. footer turns the
+// USE IN CODE footer on or off; nil uses the server default. See
+// FooterInput.
+func (r *Registry) SearchModels(query string, footer *bool) string {
 	if query == "" {
 		return "Please provide a search term."
 	}
-	words := strings.Fields(strings.ToLower(query))
+	matches, aka := r.searchMatches(query)
+	if len(matches) == 0 {
+		return fmt.Sprintf("No models found matching '%s'.", query)
+	}
+	table := formatTable(matches, aka, r.footerFilter(footer))
+	if note := r.shadowNote(matches); note != "" {
+		table += "\n\n" + note
+	}
+	return table
+}
+
+// SearchMatches returns the models SearchModels would list for query, in
+// FormatTable order.
+func (r *Registry) SearchMatches(query string) []models.Model {
+	matches, _ := r.searchMatches(query)
+	return SortedByProvider(matches)
+}
+
+// searchMatches returns the models matching every word of query after org
+// policy, and for models matched only through an alias, the aliases that
+// matched.
+func (r *Registry) searchMatches(query string) ([]models.Model, map[string][]string) {
+	words := strings.Fields(strings.ToLower(foldQuery(query)))
+	aliases := r.aliasesByModel()
 	var matches []models.Model
-	for _, m := range models.Models {
+	var aka map[string][]string
+	for _, m := range r.models {
 		// Combine all searchable fields into one string for multi-word matching.
 		// Include capability keywords so users can search "vision" or "reasoning".
 		caps := ""
@@ -25,20 +54,55 @@ func SearchModels(query string) string {
 		if m.Reasoning {
 			caps += " reasoning thinking"
 		}
+		if m.ToolCalling {
+			caps += " tool_use tools function-calling"
+		}
+		if m.StructuredOutput {
+			caps += " structured_output json-schema"
+		}
+		if m.JSONMode {
+			caps += " json_mode"
+		}
+		if m.EUHosted {
+			caps += " eu-hosted sovereignty"
+		}
+		if m.BatchAPI {
+			caps += " batch"
+		}
 		combined := strings.ToLower(m.ID + " " + m.DisplayName + " " + m.Provider + " " + m.Status + " " + m.Notes + caps)
+		aliasText := strings.Join(aliases[m.ID], " ")
 		allMatch := true
+		var via []string
 		for _, w := range words {
-			if !strings.Contains(combined, w) {
+			if strings.Contains(combined, w) {
+				continue
+			}
+			if !strings.Contains(aliasText, w) {
 				allMatch = false
 				break
 			}
+			via = appendMatchingAliases(via, aliases[m.ID], w)
 		}
 		if allMatch {
 			matches = append(matches, m)
+			if len(via) > 0 {
+				if aka == nil {
+					aka = make(map[string][]string)
+				}
+				aka[m.ID] = via
+			}
 		}
 	}
-	if len(matches) == 0 {
-		return fmt.Sprintf("No models found matching '%s'.", query)
+	return r.applyPolicy(matches), aka
+}
+
+// appendMatchingAliases appends the aliases containing w that aren't
+// already in dst.
+func appendMatchingAliases(dst, aliases []string, w string) []string {
+	for _, a := range aliases {
+		if strings.Contains(a, w) && !slices.Contains(dst, a) {
+			dst = append(dst, a)
+		}
 	}
-	return FormatTable(matches)
+	return dst
 }
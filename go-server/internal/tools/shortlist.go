@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"go-server/internal/models"
+)
+
+// GetTaskShortlistInput holds parameters for the get_task_shortlist tool.
+type GetTaskShortlistInput struct {
+	Task string `json:"task,omitempty" jsonschema:"Task key (e.g. agentic-coding, ocr) or a short description such as 'translate product docs'. Omit to list every curated task"`
+	FormatInput
+}
+
+// GetTaskShortlist returns the curated shortlist for task, or an index of
+// every shortlist when task is empty. Picks blocked by org policy or missing
+// from the registry are left out rather than replaced, so the list stays
+// curated.
+func (r *Registry) GetTaskShortlist(task string) string {
+	if strings.TrimSpace(task) == "" {
+		return shortlistIndex()
+	}
+	sl, ok := findShortlist(task)
+	if !ok {
+		return fmt.Sprintf("No curated shortlist matches %q.\n\n%s\n\nFor anything else, use recommend_model for a scored ranking.", task, shortlistIndex())
+	}
+
+	policy := r.Policy()
+	rows := []string{
+		"| # | Role | Model | Provider | Context | Input $/1M | Output $/1M | Why |",
+		"|---|------|-------|----------|---------|-----------|-------------|-----|",
+	}
+	var hidden, stale []string
+	var shown []models.Model
+	n := 0
+	for _, p := range sl.Picks {
+		m, ok := r.models[p.ModelID]
+		if !ok || policy.blocks(m) {
+			hidden = append(hidden, p.ModelID)
+			continue
+		}
+		id := m.ID
+		if m.Status != "current" {
+			id += " (" + m.Status + ")"
+			stale = append(stale, m.ID)
+		}
+		n++
+		shown = append(shown, m)
+		rows = append(rows, fmt.Sprintf("| %d | %s | %s | %s | %s | $%.2f | $%.2f | %s |",
+			n, p.Role, id, m.Provider, models.FormatInt(m.ContextWindow), m.PricingInput, m.PricingOutput, p.Why))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s shortlist\n\n%s\n\n", sl.Name, sl.Summary)
+	if n == 0 {
+		b.WriteString("Every pick on this shortlist is blocked by org policy. Use recommend_model to rank the models you can use.")
+		return b.String()
+	}
+	b.WriteString(strings.Join(rows, "\n"))
+	if len(stale) > 0 {
+		fmt.Fprintf(&b, "\n\nNo longer current in this registry: %s. Run check_model_status for replacements.", strings.Join(stale, ", "))
+	}
+	if len(hidden) > 0 {
+		fmt.Fprintf(&b, "\n\n%d pick(s) hidden by org policy.", len(hidden))
+	}
+	if note := r.shadowNote(shown); note != "" {
+		b.WriteString("\n\n" + note)
+	}
+	b.WriteString("\n\nCurated picks, stable between calls. For a ranking scored against your own constraints, use recommend_model.")
+	return b.String()
+}
+
+// findShortlist matches task against shortlist keys and names, then against
+// keywords as whole words. The longest matching keyword wins, so "customer
+// support chatbot" picks customer-support over any shorter match.
+func findShortlist(task string) (models.TaskShortlist, bool) {
+	words := " " + strings.Join(strings.FieldsFunc(strings.ToLower(task), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}), " ") + " "
+	key := strings.ReplaceAll(strings.TrimSpace(words), " ", "-")
+	for _, sl := range models.TaskShortlists {
+		if key == sl.Task || words == " "+strings.ToLower(sl.Name)+" " {
+			return sl, true
+		}
+	}
+	best, bestLen := -1, 0
+	for i, sl := range models.TaskShortlists {
+		for _, kw := range sl.Keywords {
+			if len(kw) > bestLen && strings.Contains(words, " "+kw+" ") {
+				best, bestLen = i, len(kw)
+			}
+		}
+	}
+	if best < 0 {
+		return models.TaskShortlist{}, false
+	}
+	return models.TaskShortlists[best], true
+}
+
+// shortlistIndex lists every curated task with its top pick.
+func shortlistIndex() string {
+	rows := []string{
+		"| Task | Name | Top pick | Covers |",
+		"|------|------|----------|--------|",
+	}
+	for _, sl := range models.TaskShortlists {
+		rows = append(rows, fmt.Sprintf("| %s | %s | %s | %s |", sl.Task, sl.Name, sl.Picks[0].ModelID, sl.Summary))
+	}
+	return "Curated task shortlists. Pass `task` for the full list.\n\n" + strings.Join(rows, "\n")
+}
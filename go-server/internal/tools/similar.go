@@ -0,0 +1,220 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-server/internal/models"
+)
+
+// GetSimilarModelsInput holds parameters for the get_similar_models tool.
+type GetSimilarModelsInput struct {
+	ModelID            string `json:"model_id" jsonschema:"The model to find alternatives to"`
+	Limit              int    `json:"limit,omitempty" jsonschema:"Number of similar models to return (default 5, max 20)"`
+	OtherProvidersOnly bool   `json:"other_providers_only,omitempty" jsonschema:"Only return models from providers other than the given model's"`
+	FormatInput
+}
+
+// Similarity dimension weights. They sum to 1, so the overall score reads as
+// a percentage.
+const (
+	similarityPriceWeight   = 0.35
+	similarityCapWeight     = 0.30
+	similarityContextWeight = 0.20
+	similarityRecencyWeight = 0.15
+)
+
+// similarity scores how close m is to target on each dimension, from 0 to 1.
+type similarity struct {
+	price, caps, context, recency float64
+}
+
+func (s similarity) total() float64 {
+	return s.price*similarityPriceWeight + s.caps*similarityCapWeight +
+		s.context*similarityContextWeight + s.recency*similarityRecencyWeight
+}
+
+func compareSimilarity(target, m models.Model) similarity {
+	var s similarity
+	// Price: a 10x difference in input or output price scores 0.
+	s.price = (ratioCloseness(target.PricingInput, m.PricingInput, 10) +
+		ratioCloseness(target.PricingOutput, m.PricingOutput, 10)) / 2
+	// Capabilities: the share of flags the two models agree on.
+	tracked := models.TrackedCapabilities()
+	agree := 0
+	for _, c := range tracked {
+		if target.Has(c) == m.Has(c) {
+			agree++
+		}
+	}
+	s.caps = float64(agree) / float64(len(tracked))
+	// Context: a 16x difference scores 0.
+	s.context = ratioCloseness(float64(target.ContextWindow), float64(m.ContextWindow), 16)
+	// Recency: releases two years apart score 0.
+	if a, ok := releaseMonth(target.ReleaseDate); ok {
+		if b, ok := releaseMonth(m.ReleaseDate); ok {
+			s.recency = math.Max(0, 1-math.Abs(float64(a-b))/24)
+		}
+	}
+	return s
+}
+
+// ratioCloseness is 1 when a and b are equal, falling to 0 on a log scale
+// as their ratio reaches span. Two zeros are equal; one zero scores 0.
+func ratioCloseness(a, b, span float64) float64 {
+	if a == b {
+		return 1
+	}
+	if a <= 0 || b <= 0 {
+		return 0
+	}
+	return math.Max(0, 1-math.Abs(math.Log(a/b))/math.Log(span))
+}
+
+// releaseMonth parses a YYYY-MM release date into a month count.
+func releaseMonth(date string) (int, bool) {
+	parts := strings.Split(date, "-")
+	if len(parts) < 2 {
+		return 0, false
+	}
+	year, err1 := strconv.Atoi(parts[0])
+	month, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil {
+		return 0, false
+	}
+	return year*12 + month, true
+}
+
+// GetSimilarModels ranks current models by similarity to modelID over price,
+// context window, capabilities, and release date, and shows how each
+// alternative differs from it.
+func (r *Registry) GetSimilarModels(modelID string, limit int, otherProvidersOnly bool) string {
+	target, ok := r.FindModel(modelID)
+	if !ok {
+		suggestions := r.SuggestModels(modelID, 3)
+		return fmt.Sprintf("Model '%s' not found. Did you mean: %s?", modelID, strings.Join(suggestions, ", "))
+	}
+	if limit <= 0 {
+		limit = 5
+	}
+	if limit > 20 {
+		limit = 20
+	}
+
+	type candidate struct {
+		model models.Model
+		sim   similarity
+	}
+	var ranked []candidate
+	for _, m := range r.FilterModels("", "current", "", "", Exclusions{}) {
+		if m.ID == target.ID || (otherProvidersOnly && m.Provider == target.Provider) {
+			continue
+		}
+		ranked = append(ranked, candidate{m, compareSimilarity(target, m)})
+	}
+	if len(ranked) == 0 {
+		return fmt.Sprintf("No current models to compare with `%s`.", target.ID)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i].sim.total(), ranked[j].sim.total()
+		if a != b {
+			return a > b
+		}
+		return ranked[i].model.ID < ranked[j].model.ID
+	})
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	rows := []string{
+		fmt.Sprintf("## Models similar to %s (`%s`)", target.DisplayName, target.ID),
+		"",
+		fmt.Sprintf("**Baseline:** %s | $%.2f / $%.2f per 1M tokens | %s context | %s | released %s",
+			target.Provider, target.PricingInput, target.PricingOutput,
+			models.FormatInt(target.ContextWindow), caps(target), target.ReleaseDate),
+		"",
+		"| # | Model ID | Provider | Similarity | Price (in / out) | Context | Capabilities | Released |",
+		"|---|----------|----------|------------|------------------|---------|--------------|----------|",
+	}
+	for i, c := range ranked {
+		m := c.model
+		rows = append(rows, fmt.Sprintf("| %d | `%s` | %s | %.0f%% | %s | %s | %s | %s |",
+			i+1, m.ID, m.Provider, c.sim.total()*100,
+			priceDelta(target, m), contextDelta(target, m), capsDelta(target, m), releaseDelta(target, m)))
+	}
+	rows = append(rows, "",
+		fmt.Sprintf("_Similarity weights: price %.0f%%, capabilities %.0f%%, context %.0f%%, recency %.0f%%. Deltas are relative to `%s`._",
+			similarityPriceWeight*100, similarityCapWeight*100, similarityContextWeight*100, similarityRecencyWeight*100, target.ID))
+	shown := make([]models.Model, len(ranked))
+	for i, c := range ranked {
+		shown[i] = c.model
+	}
+	if note := r.shadowNote(shown); note != "" {
+		rows = append(rows, "", note)
+	}
+	return strings.Join(rows, "\n")
+}
+
+// priceDelta shows m's prices and the change in input+output price.
+func priceDelta(target, m models.Model) string {
+	s := fmt.Sprintf("$%.2f / $%.2f", m.PricingInput, m.PricingOutput)
+	base := target.PricingInput + target.PricingOutput
+	if base <= 0 {
+		return s
+	}
+	pct := ((m.PricingInput+m.PricingOutput)/base - 1) * 100
+	if math.Abs(pct) < 0.5 {
+		return s + " (same)"
+	}
+	return fmt.Sprintf("%s (%+.0f%%)", s, pct)
+}
+
+// contextDelta shows m's context window and its difference from target's.
+func contextDelta(target, m models.Model) string {
+	d := m.ContextWindow - target.ContextWindow
+	switch {
+	case d == 0:
+		return models.FormatInt(m.ContextWindow) + " (same)"
+	case d > 0:
+		return fmt.Sprintf("%s (+%s)", models.FormatInt(m.ContextWindow), models.FormatInt(d))
+	default:
+		return fmt.Sprintf("%s (-%s)", models.FormatInt(m.ContextWindow), models.FormatInt(-d))
+	}
+}
+
+// capsDelta lists the capabilities m gains (+) or lacks (-) relative to target.
+func capsDelta(target, m models.Model) string {
+	var d []string
+	for _, c := range models.TrackedCapabilities() {
+		switch {
+		case m.Has(c) && !target.Has(c):
+			d = append(d, "+"+string(c))
+		case target.Has(c) && !m.Has(c):
+			d = append(d, "-"+string(c))
+		}
+	}
+	if len(d) == 0 {
+		return "same"
+	}
+	return strings.Join(d, ", ")
+}
+
+// releaseDelta shows m's release date and how many months apart it is.
+func releaseDelta(target, m models.Model) string {
+	a, ok1 := releaseMonth(target.ReleaseDate)
+	b, ok2 := releaseMonth(m.ReleaseDate)
+	if !ok1 || !ok2 {
+		return m.ReleaseDate
+	}
+	switch d := b - a; {
+	case d == 0:
+		return m.ReleaseDate + " (same month)"
+	case d > 0:
+		return fmt.Sprintf("%s (%d mo newer)", m.ReleaseDate, d)
+	default:
+		return fmt.Sprintf("%s (%d mo older)", m.ReleaseDate, -d)
+	}
+}
@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go-server/internal/models"
+)
+
+// FastestModelsInput holds parameters for the fastest_models tool.
+type FastestModelsInput struct {
+	Metric   string `json:"metric,omitempty" jsonschema:"Rank by throughput (output tokens/sec, default) or ttft (time to first token)"`
+	Provider string `json:"provider,omitempty" jsonschema:"Only rank models from this provider"`
+	Limit    int    `json:"limit,omitempty" jsonschema:"Number of models to return (default 10)"`
+	FormatInput
+}
+
+// FastestModels ranks models with benchmark data by throughput or time to
+// first token. Models without speed data are not ranked.
+func (r *Registry) FastestModels(metric, provider string, limit int) string {
+	byTTFT := false
+	switch strings.ToLower(metric) {
+	case "", "throughput", "tps", "tokens_per_sec":
+	case "ttft", "latency":
+		byTTFT = true
+	default:
+		return fmt.Sprintf("Unknown metric '%s'. Use throughput or ttft.", metric)
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	var ranked []models.Model
+	for _, m := range r.FilterModels(provider, "", "", "", Exclusions{}) {
+		if _, ok := models.Speeds[m.ID]; ok {
+			ranked = append(ranked, m)
+		}
+	}
+	if len(ranked) == 0 {
+		return "No speed benchmark data for the selected models."
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := models.Speeds[ranked[i].ID], models.Speeds[ranked[j].ID]
+		if byTTFT && a.TTFTMillis != b.TTFTMillis {
+			return a.TTFTMillis < b.TTFTMillis
+		}
+		if !byTTFT && a.OutputTokensPerSec != b.OutputTokensPerSec {
+			return a.OutputTokensPerSec > b.OutputTokensPerSec
+		}
+		return ranked[i].ID < ranked[j].ID
+	})
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	title := "Fastest models by output throughput"
+	if byTTFT {
+		title = "Fastest models by time to first token"
+	}
+	rows := []string{
+		"## " + title,
+		"",
+		"| # | Model ID | Provider | Status | Output tok/s | TTFT | Source |",
+		"|---|----------|----------|--------|--------------|------|--------|",
+	}
+	for i, m := range ranked {
+		s := models.Speeds[m.ID]
+		rows = append(rows, fmt.Sprintf("| %d | `%s` | %s | %s | %.0f | %d ms | %s, %s |",
+			i+1, m.ID, m.Provider, m.Status, s.OutputTokensPerSec, s.TTFTMillis, s.Source, s.MeasuredAt))
+	}
+	rows = append(rows, "", "_Typical first-party API figures; real speed varies with load, region, and prompt length._")
+	if note := r.shadowNote(ranked); note != "" {
+		rows = append(rows, "", note)
+	}
+	return strings.Join(rows, "\n")
+}
+
+// speedDetail describes a model's benchmark data for get_model_info.
+func speedDetail(id string) string {
+	s, ok := models.Speeds[id]
+	if !ok {
+		return "—"
+	}
+	return fmt.Sprintf("%.0f output tok/s, %d ms TTFT (%s, %s)", s.OutputTokensPerSec, s.TTFTMillis, s.Source, s.MeasuredAt)
+}
@@ -5,6 +5,7 @@ import (
 	"math"
 	"sort"
 	"strings"
+	"time"
 
 	"go-server/internal/models"
 )
@@ -12,46 +13,46 @@ import (
 // CheckModelStatusInput holds parameters for the check_model_status tool.
 type CheckModelStatusInput struct {
 	ModelID string `json:"model_id" jsonschema:"The model ID to check"`
+	FormatInput
 }
 
 // CheckModelStatus returns status information for a model, including
 // replacement suggestions for legacy/deprecated models.
-func CheckModelStatus(modelID string) string {
-	m, found := FindModel(modelID)
+func (r *Registry) CheckModelStatus(modelID string) string {
+	m, found := r.FindModel(modelID)
 	if !found {
-		suggestions := SuggestModels(modelID, 3)
+		suggestions := r.SuggestModels(modelID, 3)
 		return fmt.Sprintf("`%s` is **not found** in the registry. "+
 			"Did you mean: %s", modelID, strings.Join(suggestions, ", "))
 	}
 
-	result := fmt.Sprintf("**%s** (`%s`): status = **%s**",
-		m.DisplayName, m.ID, m.Status)
+	now := time.Now()
+	result := fmt.Sprintf("**%s** (`%s`): status = **%s**%s",
+		m.DisplayName, m.ID, m.Status, lifecycle(m, now))
 
-	if m.Status == "legacy" || m.Status == "deprecated" {
-		// Find current replacements from the same provider
-		var replacements []models.Model
-		for _, r := range models.Models {
-			if r.Provider == m.Provider && r.Status == "current" {
-				replacements = append(replacements, r)
-			}
+	policy := r.Policy()
+	if policy.blocks(m) {
+		result += fmt.Sprintf("\n\n**Blocked by org policy:** %s. Do not use this model.", policy.BlockReason(m))
+	} else if note := r.shadowNote([]models.Model{m}); note != "" {
+		result += "\n\n" + note
+	}
+
+	retiring := false
+	if day, ok := m.Retirement(); ok {
+		switch n := models.DaysUntil(day, now); {
+		case n < 0:
+			result += fmt.Sprintf("\n\n**Retired:** the provider stopped serving `%s` on %s; requests to it fail.", m.ID, m.RetirementDate)
+			retiring = true
+		case n <= SunsetWarningDays:
+			result += fmt.Sprintf("\n\n**Sunset warning:** `%s` retires %s. Migrate before then.", m.ID, inDays(m.RetirementDate, n))
+			retiring = true
 		}
-		// Sort by newest release date first, then closest price, then ID for determinism
-		sort.SliceStable(replacements, func(i, j int) bool {
-			if replacements[i].ReleaseDate != replacements[j].ReleaseDate {
-				return replacements[i].ReleaseDate > replacements[j].ReleaseDate
-			}
-			di := math.Abs(replacements[i].PricingInput - m.PricingInput)
-			dj := math.Abs(replacements[j].PricingInput - m.PricingInput)
-			// Use epsilon comparison to avoid float equality issues.
-			if math.Abs(di-dj) > 1e-9 {
-				return di < dj
-			}
-			return replacements[i].ID < replacements[j].ID
-		})
-		if len(replacements) > 0 {
-			r := replacements[0]
+	}
+
+	if m.Status == "legacy" || m.Status == "deprecated" || retiring {
+		if rep, ok := r.replacementFor(m); ok {
 			result += fmt.Sprintf("\n\nRecommended replacement: **%s** (`%s`) — newest from %s",
-				r.DisplayName, r.ID, r.Provider)
+				rep.DisplayName, rep.ID, rep.Provider)
 		}
 	}
 
@@ -61,3 +62,72 @@ func CheckModelStatus(modelID string) string {
 
 	return result
 }
+
+// SunsetWarningDays is how close a retirement date must be for
+// check_model_status to warn about it.
+const SunsetWarningDays = 90
+
+// lifecycle describes m's deprecation and retirement dates as a suffix to
+// its status, e.g. " since 2026-01-15, retires 2026-06-01 (in 45 days)", or
+// "" when it has neither.
+func lifecycle(m models.Model, now time.Time) string {
+	var parts []string
+	if day, ok := m.Deprecation(); ok {
+		switch {
+		case models.DaysUntil(day, now) > 0:
+			parts = append(parts, ", deprecated from "+m.DeprecationDate)
+		case m.Status == "deprecated":
+			parts = append(parts, " since "+m.DeprecationDate)
+		default:
+			parts = append(parts, ", deprecated "+m.DeprecationDate)
+		}
+	}
+	if day, ok := m.Retirement(); ok {
+		if n := models.DaysUntil(day, now); n < 0 {
+			parts = append(parts, ", retired "+m.RetirementDate)
+		} else {
+			parts = append(parts, ", retires "+inDays(m.RetirementDate, n))
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// inDays renders an upcoming date with how far off it is.
+func inDays(date string, n int) string {
+	switch n {
+	case 0:
+		return date + " (today)"
+	case 1:
+		return date + " (tomorrow)"
+	}
+	return fmt.Sprintf("%s (in %d days)", date, n)
+}
+
+// replacementFor picks the current, policy-allowed model from m's provider
+// that best replaces it: newest release first, then closest input price.
+func (r *Registry) replacementFor(m models.Model) (models.Model, bool) {
+	policy := r.Policy()
+	var replacements []models.Model
+	for _, c := range r.models {
+		if c.Provider == m.Provider && c.Status == "current" && c.ID != m.ID && !policy.blocks(c) {
+			replacements = append(replacements, c)
+		}
+	}
+	if len(replacements) == 0 {
+		return models.Model{}, false
+	}
+	// Sort by newest release date first, then closest price, then ID for determinism
+	sort.SliceStable(replacements, func(i, j int) bool {
+		if replacements[i].ReleaseDate != replacements[j].ReleaseDate {
+			return replacements[i].ReleaseDate > replacements[j].ReleaseDate
+		}
+		di := math.Abs(replacements[i].PricingInput - m.PricingInput)
+		dj := math.Abs(replacements[j].PricingInput - m.PricingInput)
+		// Use epsilon comparison to avoid float equality issues.
+		if math.Abs(di-dj) > 1e-9 {
+			return di < dj
+		}
+		return replacements[i].ID < replacements[j].ID
+	})
+	return replacements[0], true
+}
@@ -1,18 +1,31 @@
 package tools
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"testing"
 	"time"
 
+	"go-server/internal/buildinfo"
 	"go-server/internal/models"
+	"go-server/internal/render"
+	"go-server/internal/status"
 )
 
 // ── ListModels ────────────────────────────────────────────────────────────
 
 func TestListModels_NoFilters(t *testing.T) {
-	result := ListModels("", "", "")
+	result := BaseRegistry().ListModels(ListQuery{})
 	for id := range models.Models {
 		if !strings.Contains(result, id) {
 			t.Errorf("expected model %q in result", id)
@@ -21,7 +34,7 @@ func TestListModels_NoFilters(t *testing.T) {
 }
 
 func TestListModels_FilterByProvider(t *testing.T) {
-	result := ListModels("Anthropic", "", "")
+	result := BaseRegistry().ListModels(ListQuery{Provider: "Anthropic"})
 	if !strings.Contains(result, "Anthropic") {
 		t.Error("expected 'Anthropic' in result")
 	}
@@ -31,14 +44,14 @@ func TestListModels_FilterByProvider(t *testing.T) {
 }
 
 func TestListModels_FilterByProviderCaseInsensitive(t *testing.T) {
-	result := ListModels("anthropic", "", "")
+	result := BaseRegistry().ListModels(ListQuery{Provider: "anthropic"})
 	if !strings.Contains(result, "Anthropic") {
 		t.Error("expected 'Anthropic' in result for case-insensitive filter")
 	}
 }
 
 func TestListModels_FilterByStatus(t *testing.T) {
-	result := ListModels("", "deprecated", "")
+	result := BaseRegistry().ListModels(ListQuery{Status: "deprecated"})
 	lines := strings.Split(result, "\n")
 	for _, line := range lines[2:] { // skip header
 		line = strings.TrimSpace(line)
@@ -52,7 +65,7 @@ func TestListModels_FilterByStatus(t *testing.T) {
 }
 
 func TestListModels_FilterByVision(t *testing.T) {
-	result := ListModels("", "", "vision")
+	result := BaseRegistry().ListModels(ListQuery{Capability: "vision"})
 	for _, m := range models.Models {
 		if !m.Vision {
 			// Check for both plain and star-prefixed model IDs in the table.
@@ -64,7 +77,7 @@ func TestListModels_FilterByVision(t *testing.T) {
 }
 
 func TestListModels_FilterByReasoning(t *testing.T) {
-	result := ListModels("", "", "reasoning")
+	result := BaseRegistry().ListModels(ListQuery{Capability: "reasoning"})
 	for _, m := range models.Models {
 		if !m.Reasoning {
 			// Check for both plain and star-prefixed model IDs in the table.
@@ -76,7 +89,7 @@ func TestListModels_FilterByReasoning(t *testing.T) {
 }
 
 func TestListModels_NoResults(t *testing.T) {
-	result := ListModels("Nonexistent", "", "")
+	result := BaseRegistry().ListModels(ListQuery{Provider: "Nonexistent"})
 	if !strings.Contains(result, "No models found") {
 		t.Errorf("expected 'No models found' for nonexistent provider, got: %s", result)
 	}
@@ -85,7 +98,7 @@ func TestListModels_NoResults(t *testing.T) {
 // ── GetModelInfo ──────────────────────────────────────────────────────────
 
 func TestGetModelInfo_ExactMatch(t *testing.T) {
-	result := GetModelInfo("gpt-5")
+	result := BaseRegistry().GetModelInfo("gpt-5")
 	if !strings.Contains(result, "GPT-5") {
 		t.Error("expected 'GPT-5' in result")
 	}
@@ -95,21 +108,160 @@ func TestGetModelInfo_ExactMatch(t *testing.T) {
 }
 
 func TestGetModelInfo_CaseInsensitive(t *testing.T) {
-	result := GetModelInfo("GPT-5")
+	result := BaseRegistry().GetModelInfo("GPT-5")
 	if !strings.Contains(result, "GPT-5") {
 		t.Error("expected 'GPT-5' in result for case-insensitive lookup")
 	}
 }
 
 func TestGetModelInfo_PartialMatch(t *testing.T) {
-	result := GetModelInfo("opus-4-6")
+	result := BaseRegistry().GetModelInfo("opus-4-6")
 	if !strings.Contains(result, "Claude Opus 4.6") {
 		t.Error("expected 'Claude Opus 4.6' in result for partial match")
 	}
 }
 
+func TestGetModelInfo_SystemPromptSupport(t *testing.T) {
+	result := BaseRegistry().GetModelInfo("o3")
+	if !strings.Contains(result, "| System Prompt | developer") {
+		t.Errorf("expected developer system prompt support for o3, got: %s", result)
+	}
+	result = BaseRegistry().GetModelInfo("claude-opus-4-6")
+	if !strings.Contains(result, "| System Prompt | full") {
+		t.Errorf("expected full system prompt support for claude-opus-4-6, got: %s", result)
+	}
+}
+
+func TestGetModelInfo_BatchAPI(t *testing.T) {
+	if !strings.Contains(BaseRegistry().GetModelInfo("claude-opus-4-6"), "| Batch API | Yes — $") {
+		t.Error("expected batch pricing for claude-opus-4-6")
+	}
+	if !strings.Contains(BaseRegistry().GetModelInfo("deepseek-chat"), "| Batch API | No |") {
+		t.Error("expected no batch API for deepseek-chat")
+	}
+}
+
+func TestGetModelInfo_CachedInputRow(t *testing.T) {
+	if got := BaseRegistry().GetModelInfo("gpt-5"); !strings.Contains(got, "| Pricing (cached input) | $0.125 / 1M tokens (90% off input) |") {
+		t.Errorf("expected gpt-5's cached-input rate:\n%s", got)
+	}
+	if got := BaseRegistry().GetModelInfo("mistral-large-2512"); !strings.Contains(got, "| Pricing (cached input) | Not recorded |") {
+		t.Errorf("expected no cached-input rate for mistral-large-2512:\n%s", got)
+	}
+}
+
+func TestListModels_BatchCapability(t *testing.T) {
+	for _, c := range []string{"batch", "batch_capable"} {
+		ms := BaseRegistry().FilterModels("", "", c, "", Exclusions{})
+		if len(ms) == 0 {
+			t.Fatalf("capability %q: expected batch-capable models", c)
+		}
+		for _, m := range ms {
+			if !m.BatchAPI {
+				t.Errorf("capability %q returned %s without batch API", c, m.ID)
+			}
+		}
+	}
+}
+
+func TestListModels_CachingCapability(t *testing.T) {
+	for _, c := range []string{"caching", "prompt-caching"} {
+		ms := BaseRegistry().FilterModels("", "", c, "", Exclusions{})
+		if len(ms) == 0 {
+			t.Fatalf("capability %q: expected models with a cached-input rate", c)
+		}
+		for _, m := range ms {
+			if m.PricingCachedInput <= 0 {
+				t.Errorf("capability %q returned %s without a cached-input rate", c, m.ID)
+			}
+		}
+	}
+	if info := BaseRegistry().Info(); info.Capabilities["caching"] == 0 {
+		t.Errorf("registry info should count caching models, got %v", info.Capabilities)
+	}
+}
+
+func TestGetModelInfo_ToolCapabilities(t *testing.T) {
+	if result := BaseRegistry().GetModelInfo("claude-opus-4-6"); !strings.Contains(result, "Tool Calling, Structured Output (JSON schema)") {
+		t.Errorf("expected tool calling and structured output in capabilities, got:\n%s", result)
+	}
+	if result := BaseRegistry().GetModelInfo("sonar"); strings.Contains(result, "Tool Calling") {
+		t.Errorf("sonar has no function calling, got:\n%s", result)
+	}
+}
+
+func TestGetModelInfo_AgentGuidance(t *testing.T) {
+	result := BaseRegistry().GetModelInfo("claude-opus-4-6")
+	guidance := strings.Index(result, "**Agent guidance:**\n- Set max_tokens explicitly")
+	if guidance < 0 || guidance > strings.Index(result, "| Field | Value |") {
+		t.Errorf("expected agent guidance above the spec table, got:\n%s", result)
+	}
+	if result := BaseRegistry().GetModelInfo("mistral-large-2512"); strings.Contains(result, "Agent guidance") {
+		t.Errorf("models without guidance should not show the section, got:\n%s", result)
+	}
+}
+
+func TestListModels_RejectsUnknownCapability(t *testing.T) {
+	result := BaseRegistry().ListModels(ListQuery{Capability: "teleportation"})
+	if !strings.Contains(result, "Unknown capability 'teleportation'") || !strings.Contains(result, "structured_output") {
+		t.Errorf("expected the capability vocabulary in the error, got:\n%s", result)
+	}
+	result = BaseRegistry().ListModels(ListQuery{Capability: "Audio-In"})
+	if !strings.Contains(result, "'audio_in' is not recorded") {
+		t.Errorf("expected an untracked-capability note, got:\n%s", result)
+	}
+}
+
+func TestFilterModels_ToolCalling(t *testing.T) {
+	for _, c := range []string{"tool_use", "function_calling", "structured_output", "json_mode"} {
+		got := BaseRegistry().FilterModels("", "", c, "", Exclusions{})
+		if len(got) == 0 {
+			t.Errorf("capability %q matched no models", c)
+		}
+		want, _ := models.ParseCapability(c)
+		for _, m := range got {
+			if !m.Has(want) {
+				t.Errorf("capability %q returned %s without it", c, m.ID)
+			}
+		}
+	}
+	for _, m := range BaseRegistry().FilterModels("perplexity", "", "tool_use", "", Exclusions{}) {
+		t.Errorf("Perplexity has no function calling, got %s", m.ID)
+	}
+}
+
+func TestGetModelInfo_SourceLinks(t *testing.T) {
+	result := BaseRegistry().GetModelInfo("gpt-5")
+	for _, want := range []string{
+		"| Docs | https://platform.openai.com/docs/models/gpt-5 |",
+		"| Announcement | https://openai.com/index/introducing-gpt-5/ |",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in result, got: %s", want, result)
+		}
+	}
+	if strings.Contains(result, "| Model Card |") {
+		t.Errorf("did not expect an empty Model Card row, got: %s", result)
+	}
+}
+
+func TestGetModelInfo_ProviderPrefixedIDs(t *testing.T) {
+	for input, want := range map[string]string{
+		"openai/gpt-5.1":            "gpt-5.1",
+		"anthropic/claude-opus-4-6": "claude-opus-4-6",
+		"deepseek/deepseek-chat":    "deepseek-chat",
+		"x-ai/grok-4":               "grok-4",
+		"OpenAI/GPT-5.1":            "gpt-5.1",
+	} {
+		m, ok := BaseRegistry().FindModel(input)
+		if !ok || m.ID != want {
+			t.Errorf("FindModel(%q) = %q, %v; want %q", input, m.ID, ok, want)
+		}
+	}
+}
+
 func TestGetModelInfo_NotFound(t *testing.T) {
-	result := GetModelInfo("nonexistent-model")
+	result := BaseRegistry().GetModelInfo("nonexistent-model")
 	if !strings.Contains(strings.ToLower(result), "not found") {
 		t.Errorf("expected 'not found' in result, got: %s", result)
 	}
@@ -118,7 +270,7 @@ func TestGetModelInfo_NotFound(t *testing.T) {
 // ── RecommendModel ────────────────────────────────────────────────────────
 
 func TestRecommendModel_Coding(t *testing.T) {
-	result := RecommendModel("coding", "")
+	result := BaseRegistry().RecommendModel(RecommendQuery{Task: "coding"})
 	if !strings.Contains(result, "Recommendations for") {
 		t.Error("expected 'Recommendations for' in result")
 	}
@@ -128,37 +280,120 @@ func TestRecommendModel_Coding(t *testing.T) {
 }
 
 func TestRecommendModel_Vision(t *testing.T) {
-	result := RecommendModel("image analysis", "")
+	result := BaseRegistry().RecommendModel(RecommendQuery{Task: "image analysis"})
 	if !strings.Contains(strings.ToLower(result), "vision") {
 		t.Error("expected 'vision' mentioned in result")
 	}
 }
 
 func TestRecommendModel_CheapBudget(t *testing.T) {
-	result := RecommendModel("general tasks", "cheap")
+	result := BaseRegistry().RecommendModel(RecommendQuery{Task: "general tasks", Budget: "cheap"})
 	if !strings.Contains(result, "Budget:** cheap") {
 		t.Error("expected 'Budget:** cheap' in result")
 	}
 }
 
 func TestRecommendModel_Reasoning(t *testing.T) {
-	result := RecommendModel("complex math reasoning", "")
+	result := BaseRegistry().RecommendModel(RecommendQuery{Task: "complex math reasoning"})
 	if !strings.Contains(strings.ToLower(result), "reasoning") {
 		t.Error("expected 'reasoning' mentioned in result")
 	}
 }
 
+func TestRecommendModel_CustomWeights(t *testing.T) {
+	if got := BaseRegistry().RecommendModel(RecommendQuery{Task: "coding"}); strings.Contains(got, "Scoring weights") {
+		t.Errorf("default weights should not be listed:\n%s", got)
+	}
+
+	result := BaseRegistry().RecommendModel(RecommendQuery{Task: "coding", Weights: map[string]float64{"coding_specialist": 100, "Recency": 0}})
+	if !strings.Contains(result, "**Scoring weights:** coding_specialist=100, recency=0") {
+		t.Errorf("expected overridden weights listed:\n%s", result)
+	}
+	for _, line := range strings.Split(result, "\n") {
+		if !strings.HasPrefix(line, "1. ") && !strings.HasPrefix(line, "2. ") && !strings.HasPrefix(line, "3. ") {
+			continue
+		}
+		if !regexp.MustCompile(`codestral|devstral|codex|-code-|kat-coder`).MatchString(line) {
+			t.Errorf("a dominant coding_specialist weight should rank only code models, got: %s", line)
+		}
+	}
+}
+
+func TestRecommendModel_InvalidWeights(t *testing.T) {
+	for _, w := range []map[string]float64{{"speed": 1}, {"reasoning": -1}} {
+		if got := BaseRegistry().RecommendModel(RecommendQuery{Task: "coding", Weights: w}); !strings.HasPrefix(got, "Invalid weights:") {
+			t.Errorf("weights %v: expected an error, got:\n%s", w, got)
+		}
+	}
+}
+
+func TestFormatRecommendations(t *testing.T) {
+	q := RecommendQuery{Task: "vision on a budget", Budget: "LOW", Weights: map[string]float64{"vision": 6}}
+	recs, err := BaseRegistry().Recommend(q)
+	if got, want := BaseRegistry().FormatRecommendations(q, recs, err), BaseRegistry().RecommendModel(q); got != want {
+		t.Errorf("FormatRecommendations of Recommend's picks should match RecommendModel:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRecommendMatchesMarkdown(t *testing.T) {
+	task := "stable long-term coding assistant"
+	recs, err := BaseRegistry().Recommend(RecommendQuery{Task: task, Budget: "low", MinProviders: 2})
+	if err != nil || len(recs) != 3 {
+		t.Fatalf("Recommend = %d picks, %v", len(recs), err)
+	}
+	md := BaseRegistry().RecommendModel(RecommendQuery{Task: task, Budget: "low", MinProviders: 2})
+	for i, rec := range recs {
+		if !strings.Contains(md, fmt.Sprintf("%d. **%s** (`%s`)", i+1, rec.Model.DisplayName, rec.Model.ID)) {
+			t.Errorf("pick %d (%s) differs from recommend_model:\n%s", i+1, rec.Model.ID, md)
+		}
+		if strings.Contains(rec.ChurnRisk(), "`") {
+			t.Errorf("ChurnRisk should be plain text, got %q", rec.ChurnRisk())
+		}
+	}
+	if recs[0].Score < recs[len(recs)-1].Score {
+		t.Errorf("picks should be best first: %v then %v", recs[0].Score, recs[len(recs)-1].Score)
+	}
+	if _, err := BaseRegistry().Recommend(RecommendQuery{Task: task, Weights: map[string]float64{"speed": 1}}); err == nil {
+		t.Error("expected an error for an unknown weight")
+	}
+}
+
+func TestSearchMatchesSorted(t *testing.T) {
+	ms := BaseRegistry().SearchMatches("vision")
+	if len(ms) < 2 {
+		t.Fatalf("expected several vision models, got %d", len(ms))
+	}
+	sorted := SortedByProvider(ms)
+	for i := range ms {
+		if ms[i].ID != sorted[i].ID {
+			t.Fatalf("SearchMatches not in FormatTable order at %d: %s vs %s", i, ms[i].ID, sorted[i].ID)
+		}
+	}
+}
+
+func TestSetScoringWeights(t *testing.T) {
+	w, err := DefaultScoringWeights().With(map[string]float64{"vision_missing": 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetScoringWeights(w)
+	t.Cleanup(func() { SetScoringWeights(DefaultScoringWeights()) })
+	if got := BaseRegistry().RecommendModel(RecommendQuery{Task: "image analysis"}); !strings.Contains(got, "**Scoring weights:** vision_missing=0") {
+		t.Errorf("server-wide weights should apply:\n%s", got)
+	}
+}
+
 // ── CheckModelStatus ──────────────────────────────────────────────────────
 
 func TestCheckModelStatus_Current(t *testing.T) {
-	result := CheckModelStatus("gpt-5")
+	result := BaseRegistry().CheckModelStatus("gpt-5")
 	if !strings.Contains(strings.ToLower(result), "current") {
 		t.Errorf("expected 'current' in result, got: %s", result)
 	}
 }
 
 func TestCheckModelStatus_Legacy(t *testing.T) {
-	result := CheckModelStatus("o3-mini")
+	result := BaseRegistry().CheckModelStatus("o3-mini")
 	lower := strings.ToLower(result)
 	if !strings.Contains(lower, "legacy") {
 		t.Error("expected 'legacy' in result")
@@ -169,7 +404,7 @@ func TestCheckModelStatus_Legacy(t *testing.T) {
 }
 
 func TestCheckModelStatus_Deprecated(t *testing.T) {
-	result := CheckModelStatus("gpt-4o")
+	result := BaseRegistry().CheckModelStatus("gpt-4o")
 	if !strings.Contains(strings.ToLower(result), "deprecated") {
 		t.Error("expected 'deprecated' in result")
 	}
@@ -178,7 +413,7 @@ func TestCheckModelStatus_Deprecated(t *testing.T) {
 func TestCheckModelStatus_RecommendsNewestClosestPrice(t *testing.T) {
 	// gpt-4o is deprecated; replacement should be the newest OpenAI model
 	// with the closest price (newest date first, then closest input price).
-	result := CheckModelStatus("gpt-4o")
+	result := BaseRegistry().CheckModelStatus("gpt-4o")
 
 	deprecated := models.Models["gpt-4o"]
 
@@ -216,16 +451,71 @@ func TestCheckModelStatus_RecommendsNewestClosestPrice(t *testing.T) {
 }
 
 func TestCheckModelStatus_NotFound(t *testing.T) {
-	result := CheckModelStatus("fake-model")
+	result := BaseRegistry().CheckModelStatus("fake-model")
 	if !strings.Contains(strings.ToLower(result), "not found") {
 		t.Errorf("expected 'not found' in result, got: %s", result)
 	}
 }
 
+func TestCheckModelStatus_Retired(t *testing.T) {
+	result := BaseRegistry().CheckModelStatus("gemini-3-pro-preview")
+	for _, want := range []string{"status = **deprecated**, retired 2026-03-09", "**Retired:**", "Recommended replacement"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in:\n%s", want, result)
+		}
+	}
+}
+
+// retiringRegistry overlays a current Google model that retires in days
+// days, and one retiring later, so tests don't depend on today's date.
+func retiringRegistry(t *testing.T, days int) *Registry {
+	t.Helper()
+	day := func(n int) string { return time.Now().UTC().AddDate(0, 0, n).Format(models.DateLayout) }
+	reg, err := NewTenantRegistry("acme", map[string]models.Model{
+		"gemini-sunset": {DisplayName: "Gemini Sunset", Provider: "Google", ContextWindow: 1000, MaxOutputTokens: 100,
+			Status: "current", DeprecationDate: day(-10), RetirementDate: day(days)},
+		"gemini-later": {DisplayName: "Gemini Later", Provider: "Google", ContextWindow: 1000, MaxOutputTokens: 100,
+			Status: "legacy", RetirementDate: day(days + 200)},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reg
+}
+
+func TestCheckModelStatus_SunsetWarning(t *testing.T) {
+	result := retiringRegistry(t, 30).CheckModelStatus("gemini-sunset")
+	for _, want := range []string{"status = **current**, deprecated ", "(in 30 days)", "**Sunset warning:**", "Recommended replacement"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in:\n%s", want, result)
+		}
+	}
+	if result := retiringRegistry(t, SunsetWarningDays+1).CheckModelStatus("gemini-sunset"); strings.Contains(result, "Sunset warning") {
+		t.Errorf("no warning expected beyond %d days:\n%s", SunsetWarningDays, result)
+	}
+}
+
+func TestListModels_RetiringWithin(t *testing.T) {
+	reg := retiringRegistry(t, 30)
+	got := reg.ListModels(ListQuery{RetiringWithinDays: 90})
+	if !strings.Contains(got, "`gemini-sunset`") || strings.Contains(got, "gemini-later") {
+		t.Errorf("expected only gemini-sunset within 90 days:\n%s", got)
+	}
+	if !strings.Contains(got, "**Retiring:**\n- `gemini-sunset`: ") {
+		t.Errorf("expected the retirement date under the table:\n%s", got)
+	}
+	if strings.Contains(got, "gemini-3-pro-preview") {
+		t.Errorf("already retired models should be left out:\n%s", got)
+	}
+	if got := reg.ListModels(ListQuery{RetiringWithinDays: -1}); !strings.Contains(got, "must be positive") {
+		t.Errorf("expected an error for a negative window, got:\n%s", got)
+	}
+}
+
 // ── CompareModels ─────────────────────────────────────────────────────────
 
 func TestCompareModels_Two(t *testing.T) {
-	result := CompareModels([]string{"gpt-5", "claude-opus-4-6"})
+	result := BaseRegistry().CompareModels([]string{"gpt-5", "claude-opus-4-6"})
 	if !strings.Contains(result, "GPT-5") {
 		t.Error("expected 'GPT-5' in comparison")
 	}
@@ -235,7 +525,7 @@ func TestCompareModels_Two(t *testing.T) {
 }
 
 func TestCompareModels_Three(t *testing.T) {
-	result := CompareModels([]string{"gpt-5", "claude-opus-4-6", "gemini-2.5-pro"})
+	result := BaseRegistry().CompareModels([]string{"gpt-5", "claude-opus-4-6", "gemini-2.5-pro"})
 	if !strings.Contains(result, "GPT-5") {
 		t.Error("expected 'GPT-5' in comparison")
 	}
@@ -245,21 +535,21 @@ func TestCompareModels_Three(t *testing.T) {
 }
 
 func TestCompareModels_SingleError(t *testing.T) {
-	result := CompareModels([]string{"gpt-5"})
+	result := BaseRegistry().CompareModels([]string{"gpt-5"})
 	if !strings.Contains(strings.ToLower(result), "at least 2") {
 		t.Errorf("expected 'at least 2' error, got: %s", result)
 	}
 }
 
 func TestCompareModels_NotFound(t *testing.T) {
-	result := CompareModels([]string{"gpt-5", "nonexistent"})
+	result := BaseRegistry().CompareModels([]string{"gpt-5", "nonexistent"})
 	if !strings.Contains(strings.ToLower(result), "not found") {
 		t.Errorf("expected 'not found' in result, got: %s", result)
 	}
 }
 
 func TestCompareModels_CaseInsensitive(t *testing.T) {
-	result := CompareModels([]string{"GPT-5", "CLAUDE-OPUS-4-6"})
+	result := BaseRegistry().CompareModels([]string{"GPT-5", "CLAUDE-OPUS-4-6"})
 	if !strings.Contains(result, "GPT-5") {
 		t.Error("expected 'GPT-5' in case-insensitive comparison")
 	}
@@ -271,42 +561,42 @@ func TestCompareModels_CaseInsensitive(t *testing.T) {
 // ── SearchModels ──────────────────────────────────────────────────────────
 
 func TestSearchModels_ByProvider(t *testing.T) {
-	result := SearchModels("OpenAI")
+	result := BaseRegistry().SearchModels("OpenAI", nil)
 	if !strings.Contains(strings.ToLower(result), "gpt") {
 		t.Error("expected 'gpt' models when searching for OpenAI")
 	}
 }
 
 func TestSearchModels_ByName(t *testing.T) {
-	result := SearchModels("Claude")
+	result := BaseRegistry().SearchModels("Claude", nil)
 	if !strings.Contains(result, "Anthropic") {
 		t.Error("expected 'Anthropic' when searching for Claude")
 	}
 }
 
 func TestSearchModels_ByKeyword(t *testing.T) {
-	result := SearchModels("flagship")
+	result := BaseRegistry().SearchModels("flagship", nil)
 	if !strings.Contains(result, "|") {
 		t.Error("expected table output for keyword 'flagship'")
 	}
 }
 
 func TestSearchModels_CaseInsensitive(t *testing.T) {
-	result := SearchModels("GEMINI")
+	result := BaseRegistry().SearchModels("GEMINI", nil)
 	if !strings.Contains(result, "Google") {
 		t.Error("expected 'Google' when searching for GEMINI")
 	}
 }
 
 func TestSearchModels_NoResults(t *testing.T) {
-	result := SearchModels("zzzznonexistent")
+	result := BaseRegistry().SearchModels("zzzznonexistent", nil)
 	if !strings.Contains(result, "No models found") {
 		t.Errorf("expected 'No models found', got: %s", result)
 	}
 }
 
 func TestSearchModels_PartialID(t *testing.T) {
-	result := SearchModels("gpt-5")
+	result := BaseRegistry().SearchModels("gpt-5", nil)
 	if !strings.Contains(result, "GPT-5") {
 		t.Error("expected 'GPT-5' when searching by partial ID")
 	}
@@ -407,7 +697,7 @@ func TestModelDetail_NoCapabilities(t *testing.T) {
 }
 
 func TestFindModel_ExactMatch(t *testing.T) {
-	m, found := FindModel("gpt-5")
+	m, found := BaseRegistry().FindModel("gpt-5")
 	if !found {
 		t.Fatal("expected to find gpt-5")
 	}
@@ -417,7 +707,7 @@ func TestFindModel_ExactMatch(t *testing.T) {
 }
 
 func TestFindModel_CaseInsensitive(t *testing.T) {
-	m, found := FindModel("GPT-5")
+	m, found := BaseRegistry().FindModel("GPT-5")
 	if !found {
 		t.Fatal("expected to find GPT-5 via case-insensitive match")
 	}
@@ -426,15 +716,29 @@ func TestFindModel_CaseInsensitive(t *testing.T) {
 	}
 }
 
+func TestFindModel_SeparatorVariants(t *testing.T) {
+	for query, want := range map[string]string{
+		"GLM_4.7":         "glm-4.7",
+		"glm-4-7":         "glm-4.7",
+		"Claude Opus 4.6": "claude-opus-4-6",
+		"claude-opus-4.6": "claude-opus-4-6",
+	} {
+		m, found := BaseRegistry().findModel(query)
+		if !found || m.ID != want {
+			t.Errorf("findModel(%q) = %q, %v; want %q", query, m.ID, found, want)
+		}
+	}
+}
+
 func TestFindModel_NotFound(t *testing.T) {
-	_, found := FindModel("nonexistent-model-xyz")
+	_, found := BaseRegistry().FindModel("nonexistent-model-xyz")
 	if found {
 		t.Error("did not expect to find nonexistent model")
 	}
 }
 
 func TestFilterModels_CombinedFilters(t *testing.T) {
-	results := FilterModels("OpenAI", "current", "vision")
+	results := BaseRegistry().FilterModels("OpenAI", "current", "vision", "", Exclusions{})
 	for _, m := range results {
 		if m.Provider != "OpenAI" {
 			t.Errorf("expected provider OpenAI, got %s", m.Provider)
@@ -452,7 +756,7 @@ func TestFilterModels_CombinedFilters(t *testing.T) {
 }
 
 func TestFilterModels_UnknownCapability(t *testing.T) {
-	unknown := FilterModels("", "", "teleportation")
+	unknown := BaseRegistry().FilterModels("", "", "teleportation", "", Exclusions{})
 	// Unknown capability should return no results (no models have this capability).
 	if len(unknown) != 0 {
 		t.Errorf("unknown capability should return 0 models, got %d", len(unknown))
@@ -460,7 +764,7 @@ func TestFilterModels_UnknownCapability(t *testing.T) {
 }
 
 func TestFilterModels_ThinkingCapability(t *testing.T) {
-	results := FilterModels("", "", "thinking")
+	results := BaseRegistry().FilterModels("", "", "thinking", "", Exclusions{})
 	for _, m := range results {
 		if !m.Reasoning {
 			t.Errorf("model %s should have reasoning=true when filtering by thinking", m.ID)
@@ -471,6 +775,97 @@ func TestFilterModels_ThinkingCapability(t *testing.T) {
 	}
 }
 
+func TestFilterModels_SovereigntyEU(t *testing.T) {
+	results := BaseRegistry().FilterModels("", "", "", "eu", Exclusions{})
+	if len(results) == 0 {
+		t.Fatal("expected at least one EU-hosted model")
+	}
+	for _, m := range results {
+		if !m.EUHosted {
+			t.Errorf("model %s should be EU-hosted when filtering by sovereignty=eu", m.ID)
+		}
+	}
+}
+
+func TestFilterModels_UnknownSovereignty(t *testing.T) {
+	results := BaseRegistry().FilterModels("", "", "", "mars", Exclusions{})
+	if len(results) != 0 {
+		t.Errorf("unknown sovereignty should return 0 models, got %d", len(results))
+	}
+}
+
+func TestFilterModels_ExcludeProviders(t *testing.T) {
+	results := BaseRegistry().FilterModels("", "", "", "", Exclusions{Providers: []string{"xAI", "grok"}})
+	if len(results) == 0 {
+		t.Fatal("expected models after excluding xAI")
+	}
+	for _, m := range results {
+		if m.Provider == "xAI" {
+			t.Errorf("xAI model %s should be excluded", m.ID)
+		}
+	}
+}
+
+func TestFilterModels_ExcludeStatus(t *testing.T) {
+	results := BaseRegistry().FilterModels("OpenAI", "", "", "", Exclusions{Statuses: []string{"Deprecated", "legacy"}})
+	if len(results) == 0 {
+		t.Fatal("expected current OpenAI models to remain")
+	}
+	for _, m := range results {
+		if m.Status != "current" {
+			t.Errorf("model %s with status %s should be excluded", m.ID, m.Status)
+		}
+	}
+}
+
+func TestFilterModels_ExcludeIDsResolvesAliases(t *testing.T) {
+	results := BaseRegistry().FilterModels("Anthropic", "", "", "", Exclusions{IDs: []string{"opus", "CLAUDE-SONNET-4-6"}})
+	for _, m := range results {
+		if m.ID == "claude-opus-4-6" || m.ID == "claude-sonnet-4-6" {
+			t.Errorf("model %s should be excluded", m.ID)
+		}
+	}
+	if len(results) != len(BaseRegistry().FilterModels("Anthropic", "", "", "", Exclusions{}))-2 {
+		t.Errorf("expected exactly 2 Anthropic models excluded, got %d remaining", len(results))
+	}
+}
+
+func TestFilterModels_ExcludeIDsAliasCaseInsensitive(t *testing.T) {
+	for _, tc := range []struct{ alias, id string }{
+		{"OPUS", "claude-opus-4-6"},
+		{"minimax-vl-01", "minimax-01"},
+	} {
+		for _, m := range BaseRegistry().FilterModels("", "", "", "", Exclusions{IDs: []string{tc.alias}}) {
+			if m.ID == tc.id {
+				t.Errorf("exclude_ids %q should drop %s", tc.alias, tc.id)
+			}
+		}
+	}
+}
+
+func TestFilterModels_ExcludeIDsUsesRegistryAliases(t *testing.T) {
+	r := newRegistry("", map[string]models.Model{
+		"alpha-1": {ID: "alpha-1", Provider: "Acme", Status: "current"},
+		"beta-1":  {ID: "beta-1", Provider: "Acme", Status: "current"},
+	}, nil)
+	r.aliases = map[string]string{"Alpha-Latest": "alpha-1"}
+	results := r.FilterModels("", "", "", "", Exclusions{IDs: []string{"ALPHA-LATEST"}})
+	if len(results) != 1 || results[0].ID != "beta-1" {
+		t.Errorf("exclude_ids should resolve through the registry's aliases, got %v", results)
+	}
+	if m, ok := r.FindModel("alpha-latest"); !ok || m.ID != "alpha-1" {
+		t.Errorf("FindModel(alpha-latest) = %q, %v; want alpha-1", m.ID, ok)
+	}
+}
+
+func TestFilterModels_ExcludeIDsNoPartialMatch(t *testing.T) {
+	all := BaseRegistry().FilterModels("", "", "", "", Exclusions{})
+	results := BaseRegistry().FilterModels("", "", "", "", Exclusions{IDs: []string{"gpt"}})
+	if len(results) != len(all) {
+		t.Errorf("partial ID 'gpt' should not exclude anything, got %d of %d", len(results), len(all))
+	}
+}
+
 func TestCaps_VisionOnly(t *testing.T) {
 	m := models.Model{Vision: true, Reasoning: false}
 	result := caps(m)
@@ -506,14 +901,14 @@ func TestCaps_None(t *testing.T) {
 // ── Additional edge case tests ───────────────────────────────────────────
 
 func TestGetModelInfo_EmptyString(t *testing.T) {
-	result := GetModelInfo("")
+	result := BaseRegistry().GetModelInfo("")
 	if !strings.Contains(result, "Please provide a model ID") {
 		t.Errorf("expected 'Please provide a model ID' for empty input, got: %s", result)
 	}
 }
 
 func TestSearchModels_EmptyString(t *testing.T) {
-	result := SearchModels("")
+	result := BaseRegistry().SearchModels("", nil)
 	// Empty query should return an error message prompting for a search term
 	if !strings.Contains(result, "Please provide a search term") {
 		t.Errorf("expected 'Please provide a search term' for empty query, got: %s", result)
@@ -521,14 +916,14 @@ func TestSearchModels_EmptyString(t *testing.T) {
 }
 
 func TestSearchModels_SpecialCharacters(t *testing.T) {
-	result := SearchModels("!@#$%^&*()")
+	result := BaseRegistry().SearchModels("!@#$%^&*()", nil)
 	if !strings.Contains(result, "No models found") {
 		t.Errorf("expected 'No models found' for special characters, got: %s", result)
 	}
 }
 
 func TestCompareModels_EmptySlice(t *testing.T) {
-	result := CompareModels([]string{})
+	result := BaseRegistry().CompareModels([]string{})
 	if !strings.Contains(strings.ToLower(result), "at least 2") {
 		t.Errorf("expected 'at least 2' for empty slice, got: %s", result)
 	}
@@ -536,7 +931,7 @@ func TestCompareModels_EmptySlice(t *testing.T) {
 
 func TestCompareModels_MoreThanFive(t *testing.T) {
 	ids := []string{"gpt-5", "claude-opus-4-6", "gemini-2.5-pro", "grok-4", "deepseek-chat", "o3"}
-	result := CompareModels(ids)
+	result := BaseRegistry().CompareModels(ids)
 	// Should truncate to 5, so "o3" (6th) may or may not appear depending on ordering
 	// but should not error
 	if strings.Contains(strings.ToLower(result), "not found") {
@@ -548,7 +943,7 @@ func TestCompareModels_MoreThanFive(t *testing.T) {
 }
 
 func TestCompareModels_DuplicateIDs(t *testing.T) {
-	result := CompareModels([]string{"gpt-5", "gpt-5"})
+	result := BaseRegistry().CompareModels([]string{"gpt-5", "gpt-5"})
 	// Should work without error - comparing a model with itself
 	if !strings.Contains(result, "GPT-5") {
 		t.Error("expected 'GPT-5' in duplicate comparison")
@@ -556,7 +951,7 @@ func TestCompareModels_DuplicateIDs(t *testing.T) {
 }
 
 func TestListModels_CombinedProviderAndStatus(t *testing.T) {
-	result := ListModels("OpenAI", "current", "")
+	result := BaseRegistry().ListModels(ListQuery{Provider: "OpenAI", Status: "current"})
 	if strings.Contains(result, "deprecated") {
 		t.Error("should not contain deprecated models when filtering for current")
 	}
@@ -566,14 +961,14 @@ func TestListModels_CombinedProviderAndStatus(t *testing.T) {
 }
 
 func TestListModels_InvalidStatus(t *testing.T) {
-	result := ListModels("", "invalid_status", "")
+	result := BaseRegistry().ListModels(ListQuery{Status: "invalid_status"})
 	if !strings.Contains(result, "No models found") {
 		t.Errorf("expected 'No models found' for invalid status, got: %s", result)
 	}
 }
 
 func TestRecommendModel_EmptyTask(t *testing.T) {
-	result := RecommendModel("", "")
+	result := BaseRegistry().RecommendModel(RecommendQuery{})
 	// Should still return recommendations even with empty task
 	if !strings.Contains(result, "Recommendations for") {
 		t.Error("expected recommendations even for empty task")
@@ -584,7 +979,7 @@ func TestRecommendModel_EmptyTask(t *testing.T) {
 }
 
 func TestRecommendModel_UnlimitedBudget(t *testing.T) {
-	result := RecommendModel("general tasks", "unlimited")
+	result := BaseRegistry().RecommendModel(RecommendQuery{Task: "general tasks", Budget: "unlimited"})
 	// "unlimited" normalizes to "expensive"
 	if !strings.Contains(result, "Budget:** expensive") {
 		t.Error("expected 'Budget:** expensive' in result (unlimited normalizes to expensive)")
@@ -592,21 +987,21 @@ func TestRecommendModel_UnlimitedBudget(t *testing.T) {
 }
 
 func TestRecommendModel_LongContext(t *testing.T) {
-	result := RecommendModel("long context document analysis", "")
+	result := BaseRegistry().RecommendModel(RecommendQuery{Task: "long context document analysis"})
 	if !strings.Contains(result, "1.") {
 		t.Error("expected recommendations for long context task")
 	}
 }
 
 func TestRecommendModel_OpenWeight(t *testing.T) {
-	result := RecommendModel("open weight model for self-hosting", "")
+	result := BaseRegistry().RecommendModel(RecommendQuery{Task: "open weight model for self-hosting"})
 	if !strings.Contains(result, "1.") {
 		t.Error("expected recommendations for open weight task")
 	}
 }
 
 func TestRecommendModel_LowBudgetAvoidsExpensive(t *testing.T) {
-	result := RecommendModel("code generation", "low")
+	result := BaseRegistry().RecommendModel(RecommendQuery{Task: "code generation", Budget: "low"})
 	// "low" should be treated as "cheap" — the top recommendations
 	// must NOT include models costing > $5/M input.
 	if strings.Contains(result, "gpt-5.2-pro") {
@@ -622,21 +1017,21 @@ func TestRecommendModel_LowBudgetAvoidsExpensive(t *testing.T) {
 
 func TestRecommendModel_BudgetNormalization(t *testing.T) {
 	// "low" and "cheap" should produce the same results
-	low := RecommendModel("general tasks", "low")
-	cheap := RecommendModel("general tasks", "cheap")
+	low := BaseRegistry().RecommendModel(RecommendQuery{Task: "general tasks", Budget: "low"})
+	cheap := BaseRegistry().RecommendModel(RecommendQuery{Task: "general tasks", Budget: "cheap"})
 	if low != cheap {
 		t.Error("expected 'low' and 'cheap' budgets to produce identical results")
 	}
 	// "high" and "expensive" should produce the same results
-	high := RecommendModel("general tasks", "high")
-	expensive := RecommendModel("general tasks", "expensive")
+	high := BaseRegistry().RecommendModel(RecommendQuery{Task: "general tasks", Budget: "high"})
+	expensive := BaseRegistry().RecommendModel(RecommendQuery{Task: "general tasks", Budget: "expensive"})
 	if high != expensive {
 		t.Error("expected 'high' and 'expensive' budgets to produce identical results")
 	}
 }
 
 func TestRecommendModel_CodingPrefersCodingModels(t *testing.T) {
-	result := RecommendModel("coding tasks", "moderate")
+	result := BaseRegistry().RecommendModel(RecommendQuery{Task: "coding tasks", Budget: "moderate"})
 	// At least one coding-specialized model should appear
 	hasCodingModel := strings.Contains(result, "codex") ||
 		strings.Contains(result, "devstral") ||
@@ -647,15 +1042,94 @@ func TestRecommendModel_CodingPrefersCodingModels(t *testing.T) {
 	}
 }
 
+func TestRecommendModel_SovereigntyEU(t *testing.T) {
+	result := BaseRegistry().RecommendModel(RecommendQuery{Task: "coding", Sovereignty: "eu"})
+	if !strings.Contains(result, "Sovereignty:** eu") {
+		t.Error("expected 'Sovereignty:** eu' in result")
+	}
+	for _, m := range models.Models {
+		if !m.EUHosted && strings.Contains(result, "(`"+m.ID+"`)") {
+			t.Errorf("non-EU-hosted model %q should not be recommended with sovereignty=eu", m.ID)
+		}
+	}
+}
+
+func TestRecommendModel_ExcludeProviders(t *testing.T) {
+	result := BaseRegistry().RecommendModel(RecommendQuery{Task: "coding", Exclude: Exclusions{Providers: []string{"OpenAI", "Anthropic"}}})
+	for _, m := range models.Models {
+		if (m.Provider == "OpenAI" || m.Provider == "Anthropic") && strings.Contains(result, "(`"+m.ID+"`)") {
+			t.Errorf("excluded provider model %q should not be recommended", m.ID)
+		}
+	}
+}
+
+func TestRecommendModel_ExcludeEverything(t *testing.T) {
+	result := BaseRegistry().RecommendModel(RecommendQuery{Task: "coding", Exclude: Exclusions{Statuses: []string{"current"}}})
+	if !strings.Contains(result, "No current models remain") {
+		t.Errorf("expected empty-candidate message, got: %s", result)
+	}
+}
+
+func TestRecommendModel_MinProviders(t *testing.T) {
+	result := BaseRegistry().RecommendModel(RecommendQuery{Task: "coding", Budget: "expensive", MinProviders: 3})
+	providers := make(map[string]bool)
+	for _, line := range strings.Split(result, "\n") {
+		if idx := strings.Index(line, "Provider: "); idx != -1 {
+			rest := line[idx+len("Provider: "):]
+			providers[strings.TrimSpace(strings.SplitN(rest, "|", 2)[0])] = true
+		}
+	}
+	if len(providers) != 3 {
+		t.Errorf("expected 3 distinct providers with min_providers=3, got %v\n%s", providers, result)
+	}
+}
+
+func TestPickDiverse(t *testing.T) {
+	ranked := []models.Model{
+		{ID: "a1", Provider: "A"},
+		{ID: "a2", Provider: "A"},
+		{ID: "a3", Provider: "A"},
+		{ID: "b1", Provider: "B"},
+		{ID: "c1", Provider: "C"},
+	}
+	ids := func(ms []models.Model) string {
+		var out []string
+		for _, m := range ms {
+			out = append(out, m.ID)
+		}
+		return strings.Join(out, ",")
+	}
+	tests := []struct {
+		minProviders int
+		want         string
+	}{
+		{0, "a1,a2,a3"},
+		{1, "a1,a2,a3"},
+		{2, "a1,a2,b1"},
+		{3, "a1,b1,c1"},
+		{10, "a1,b1,c1"},
+	}
+	for _, tc := range tests {
+		if got := ids(pickDiverse(ranked, 3, tc.minProviders)); got != tc.want {
+			t.Errorf("pickDiverse(min=%d) = %s, want %s", tc.minProviders, got, tc.want)
+		}
+	}
+
+	// Not enough providers: fall back to rank order.
+	if got := ids(pickDiverse(ranked[:3], 3, 2)); got != "a1,a2,a3" {
+		t.Errorf("pickDiverse with one provider = %s, want a1,a2,a3", got)
+	}
+}
+
 func TestCheckModelStatus_CaseInsensitive(t *testing.T) {
-	result := CheckModelStatus("GPT-5")
+	result := BaseRegistry().CheckModelStatus("GPT-5")
 	if !strings.Contains(strings.ToLower(result), "current") {
 		t.Errorf("expected 'current' for case-insensitive GPT-5 lookup, got: %s", result)
 	}
 }
 
 func TestSearchModels_SearchByNotes(t *testing.T) {
-	result := SearchModels("flagship")
+	result := BaseRegistry().SearchModels("flagship", nil)
 	if strings.Contains(result, "No models found") {
 		t.Error("expected to find models with 'flagship' in notes")
 	}
@@ -666,7 +1140,7 @@ func TestSearchModels_SearchByNotes(t *testing.T) {
 
 func TestSearchModels_SearchByStatus(t *testing.T) {
 	// SearchModels searches ID, DisplayName, Provider, Status, and Notes
-	result := SearchModels("deprecated")
+	result := BaseRegistry().SearchModels("deprecated", nil)
 	if strings.Contains(result, "No models found") {
 		t.Error("expected to find deprecated models when searching by status")
 	}
@@ -677,7 +1151,7 @@ func TestSearchModels_SearchByStatus(t *testing.T) {
 
 func TestSearchModels_MultiWord(t *testing.T) {
 	// Multi-word queries should match across different fields
-	result := SearchModels("zhipu glm")
+	result := BaseRegistry().SearchModels("zhipu glm", nil)
 	if strings.Contains(result, "No models found") {
 		t.Error("expected 'zhipu glm' to find Zhipu GLM models (provider + ID)")
 	}
@@ -688,14 +1162,14 @@ func TestSearchModels_MultiWord(t *testing.T) {
 
 func TestSearchModels_VisionCapability(t *testing.T) {
 	// "google vision" should find Google vision models via capability keyword injection
-	result := SearchModels("google vision")
+	result := BaseRegistry().SearchModels("google vision", nil)
 	if strings.Contains(result, "No models found") {
 		t.Error("expected 'google vision' to find Google vision models")
 	}
 }
 
 func TestSearchModels_ReasoningCapability(t *testing.T) {
-	result := SearchModels("openai reasoning")
+	result := BaseRegistry().SearchModels("openai reasoning", nil)
 	if strings.Contains(result, "No models found") {
 		t.Error("expected 'openai reasoning' to find OpenAI reasoning models")
 	}
@@ -703,20 +1177,36 @@ func TestSearchModels_ReasoningCapability(t *testing.T) {
 
 func TestSearchModels_ProviderAlternateNames(t *testing.T) {
 	// z.ai should find Zhipu models via Notes field
-	result := SearchModels("z.ai")
+	result := BaseRegistry().SearchModels("z.ai", nil)
 	if strings.Contains(result, "No models found") {
 		t.Error("expected 'z.ai' to find Zhipu models")
 	}
 	// nim should find NVIDIA models via Notes field
-	result = SearchModels("nim")
+	result = BaseRegistry().SearchModels("nim", nil)
 	if strings.Contains(result, "No models found") {
 		t.Error("expected 'nim' to find NVIDIA models")
 	}
 }
 
+func TestSearchModels_ByAlias(t *testing.T) {
+	result := BaseRegistry().SearchModels("gpt53instant", nil)
+	if !strings.Contains(result, "gpt-5.3-chat-latest") {
+		t.Fatalf("expected alias search to find gpt-5.3-chat-latest:\n%s", result)
+	}
+	if !strings.Contains(result, "| Also Known As |") || !strings.Contains(result, "| gpt53instant |") {
+		t.Errorf("expected the matching alias in an Also Known As column:\n%s", result)
+	}
+}
+
+func TestSearchModels_AliasColumnOnlyWhenAliasMatched(t *testing.T) {
+	if result := BaseRegistry().SearchModels("anthropic", nil); strings.Contains(result, "Also Known As") {
+		t.Errorf("expected no alias column when no alias was needed:\n%s", result)
+	}
+}
+
 func TestListModels_ProviderAlias(t *testing.T) {
 	// "kimi" should resolve to Moonshot provider
-	result := ListModels("kimi", "", "")
+	result := BaseRegistry().ListModels(ListQuery{Provider: "kimi"})
 	if strings.Contains(result, "No models found") {
 		t.Error("expected list_models(provider='kimi') to find Moonshot models")
 	}
@@ -726,7 +1216,7 @@ func TestListModels_ProviderAlias(t *testing.T) {
 }
 
 func TestListModels_ProviderAliasZhipu(t *testing.T) {
-	result := ListModels("z.ai", "", "")
+	result := BaseRegistry().ListModels(ListQuery{Provider: "z.ai"})
 	if strings.Contains(result, "No models found") {
 		t.Error("expected list_models(provider='z.ai') to find Zhipu models")
 	}
@@ -736,7 +1226,7 @@ func TestListModels_ProviderAliasZhipu(t *testing.T) {
 }
 
 func TestListModels_ProviderAliasPhi(t *testing.T) {
-	result := ListModels("phi", "", "")
+	result := BaseRegistry().ListModels(ListQuery{Provider: "phi"})
 	if strings.Contains(result, "No models found") {
 		t.Error("expected list_models(provider='phi') to find Microsoft models")
 	}
@@ -748,7 +1238,7 @@ func TestListModels_ProviderAliasPhi(t *testing.T) {
 // ── Alias resolution tests ───────────────────────────────────────────
 
 func TestFindModel_AliasResolution(t *testing.T) {
-	m, found := FindModel("claude-sonnet-4-5")
+	m, found := BaseRegistry().FindModel("claude-sonnet-4-5")
 	if !found {
 		t.Fatal("expected to find model via alias 'claude-sonnet-4-5'")
 	}
@@ -758,7 +1248,7 @@ func TestFindModel_AliasResolution(t *testing.T) {
 }
 
 func TestFindModel_AliasResolution_Haiku(t *testing.T) {
-	m, found := FindModel("claude-haiku-4-5")
+	m, found := BaseRegistry().FindModel("claude-haiku-4-5")
 	if !found {
 		t.Fatal("expected to find model via alias 'claude-haiku-4-5'")
 	}
@@ -768,7 +1258,7 @@ func TestFindModel_AliasResolution_Haiku(t *testing.T) {
 }
 
 func TestFindModel_AliasResolution_DateAlias(t *testing.T) {
-	m, found := FindModel("gpt-4o-2024-05-13")
+	m, found := BaseRegistry().FindModel("gpt-4o-2024-05-13")
 	if !found {
 		t.Fatal("expected to find model via alias 'gpt-4o-2024-05-13'")
 	}
@@ -777,10 +1267,32 @@ func TestFindModel_AliasResolution_DateAlias(t *testing.T) {
 	}
 }
 
+func TestFindModel_FoldsCJKInput(t *testing.T) {
+	for input, want := range map[string]string{
+		"ＧＬＭ－５":               "glm-5",        // full-width letters, digits, and hyphen
+		"glm-4。7":             "glm-4.7",      // ideographic full stop
+		"kimi‐k2.5":           "kimi-k2.5",    // U+2010 hyphen
+		"「hunyuan-t1」":        "hunyuan-t1",   // corner brackets
+		"\u3000minimax–m2.5 ": "minimax-m2.5", // ideographic space, en dash
+		"glm\u200b-5":         "glm-5",        // zero-width space
+	} {
+		m, found := BaseRegistry().FindModel(input)
+		if !found || m.ID != want {
+			t.Errorf("FindModel(%q) = %q, %v; want %q", input, m.ID, found, want)
+		}
+	}
+}
+
+func TestSearchModels_FoldsCJKInput(t *testing.T) {
+	if got := BaseRegistry().SearchModels("ｈｕｎｙｕａｎ", nil); !strings.Contains(got, "hunyuan-t1") {
+		t.Errorf("expected full-width query to match Hunyuan models:\n%s", got)
+	}
+}
+
 // ── SuggestModels tests ──────────────────────────────────────────────
 
 func TestSuggestModels_ClosestMatch(t *testing.T) {
-	suggestions := SuggestModels("gpt-55", 3)
+	suggestions := BaseRegistry().SuggestModels("gpt-55", 3)
 	if len(suggestions) == 0 {
 		t.Fatal("expected at least one suggestion")
 	}
@@ -791,15 +1303,15 @@ func TestSuggestModels_ClosestMatch(t *testing.T) {
 }
 
 func TestSuggestModels_ReturnsRequestedCount(t *testing.T) {
-	suggestions := SuggestModels("nonexistent", 5)
+	suggestions := BaseRegistry().SuggestModels("nonexistent", 5)
 	if len(suggestions) != 5 {
 		t.Errorf("expected 5 suggestions, got %d", len(suggestions))
 	}
 }
 
 func TestSuggestModels_CaseInsensitive(t *testing.T) {
-	lower := SuggestModels("GPT-55", 3)
-	upper := SuggestModels("gpt-55", 3)
+	lower := BaseRegistry().SuggestModels("GPT-55", 3)
+	upper := BaseRegistry().SuggestModels("gpt-55", 3)
 	if len(lower) != len(upper) {
 		t.Fatal("case should not affect suggestion count")
 	}
@@ -895,7 +1407,7 @@ func TestFormatTable_UseInCodeFooter(t *testing.T) {
 // ── CompareModels field completeness test ────────────────────────────
 
 func TestCompareModels_FieldCompleteness(t *testing.T) {
-	result := CompareModels([]string{"gpt-5", "claude-opus-4-6"})
+	result := BaseRegistry().CompareModels([]string{"gpt-5", "claude-opus-4-6"})
 	requiredFields := []string{
 		"Provider",
 		"Status",
@@ -942,3 +1454,1478 @@ func TestRecencyBonus(t *testing.T) {
 		}
 	}
 }
+
+// ── Output budget tests ──────────────────────────────────────────────
+
+func TestApplyBudget_UnderLimitUnchanged(t *testing.T) {
+	text := "short output"
+	if got := ApplyBudget(text, 1024); got != text {
+		t.Errorf("expected unchanged output, got %q", got)
+	}
+	if got := ApplyBudget(text, 0); got != text {
+		t.Errorf("expected budget 0 to disable truncation, got %q", got)
+	}
+}
+
+func TestApplyBudget_TruncatesTableRowsKeepsFooter(t *testing.T) {
+	full := BaseRegistry().ListModels(ListQuery{})
+	const budget = 2048
+	got := ApplyBudget(full, budget)
+	if len(got) > budget {
+		t.Errorf("expected output <= %d bytes, got %d", budget, len(got))
+	}
+	if !strings.Contains(got, "| Model ID |") {
+		t.Error("expected table header to be kept")
+	}
+	if !strings.Contains(got, "more rows not shown") || !strings.Contains(got, "refine filters") {
+		t.Errorf("expected truncation note, got: %s", got)
+	}
+	if !strings.Contains(got, "USE IN CODE:") {
+		t.Error("expected USE IN CODE footer to survive truncation")
+	}
+}
+
+func TestApplyBudget_PlainText(t *testing.T) {
+	text := strings.Repeat("line of plain text\n", 100)
+	got := ApplyBudget(text, 500)
+	if len(got) > 500 {
+		t.Errorf("expected output <= 500 bytes, got %d", len(got))
+	}
+	if !strings.Contains(got, "more lines not shown") {
+		t.Errorf("expected line truncation note, got: %s", got)
+	}
+}
+
+func TestApplyRenderedBudget_HTML(t *testing.T) {
+	full := BaseRegistry().ListModels(ListQuery{})
+	const budget = 4096
+	toHTML := func(md string) string { return render.ToHTML(md) }
+	got := ApplyRenderedBudget(full, budget, toHTML)
+	if len(got) > budget {
+		t.Errorf("expected rendered output <= %d bytes, got %d", budget, len(got))
+	}
+	if !strings.Contains(got, "<table") || !strings.Contains(got, "more rows not shown") {
+		t.Errorf("expected a truncated html table with the note, got: %s", got)
+	}
+}
+
+func TestApplyDocumentBudget(t *testing.T) {
+	doc := render.Structured(BaseRegistry().ListModels(ListQuery{}))
+	const budget = 4096
+	got := ApplyDocumentBudget(doc, budget)
+	if n := len(got.JSON()); n > budget {
+		t.Errorf("expected encoded document <= %d bytes, got %d", budget, n)
+	}
+	if len(got.Blocks) != len(doc.Blocks)+1 {
+		t.Fatalf("expected every block kept plus a note, got %d blocks for %d", len(got.Blocks), len(doc.Blocks))
+	}
+	var table render.Block
+	for _, b := range got.Blocks {
+		if b.Type == "table" {
+			table = b
+		}
+	}
+	if len(table.Columns) == 0 || len(table.Rows) == 0 {
+		t.Errorf("expected the table to keep its columns and some rows: %+v", table)
+	}
+	if note := got.Blocks[len(got.Blocks)-1]; note.Type != "text" || !strings.Contains(note.Text, "more rows not shown") {
+		t.Errorf("expected a trailing truncation note, got %+v", note)
+	}
+	if again := ApplyDocumentBudget(got, 0); len(again.Blocks) != len(got.Blocks) {
+		t.Error("expected budget 0 to leave the document alone")
+	}
+	// A budget no table fits in empties the tables and drops whole blocks.
+	tight := ApplyDocumentBudget(render.Structured(BaseRegistry().ListProviders(nil, nil)), 400)
+	if n := len(tight.JSON()); n > 400 {
+		t.Errorf("expected encoded document <= 400 bytes, got %d", n)
+	}
+}
+
+func TestOutputBudget_Overrides(t *testing.T) {
+	t.Cleanup(func() { budgets.Store(nil) })
+	if got := OutputBudget("list_models"); got != DefaultOutputBudget {
+		t.Errorf("expected default budget %d, got %d", DefaultOutputBudget, got)
+	}
+	SetOutputBudgets(4096, nil)
+	if got := OutputBudget("list_models"); got != 4096 {
+		t.Errorf("expected global override 4096, got %d", got)
+	}
+	SetOutputBudgets(4096, map[string]int{"LIST_MODELS": 0})
+	if got := OutputBudget("list_models"); got != 0 {
+		t.Errorf("expected per-tool override 0, got %d", got)
+	}
+	if got := OutputBudget("search_models"); got != 4096 {
+		t.Errorf("expected search_models to use global override, got %d", got)
+	}
+}
+
+// ── Org policy tests ─────────────────────────────────────────────────
+
+func withPolicy(t *testing.T, p *Policy) {
+	t.Helper()
+	SetPolicy(p)
+	t.Cleanup(func() { SetPolicy(nil) })
+}
+
+func TestPolicy_AllowedProvidersFilterList(t *testing.T) {
+	withPolicy(t, &Policy{AllowedProviders: []string{"anthropic", "gemini"}})
+	for _, m := range BaseRegistry().FilterModels("", "", "", "", Exclusions{}) {
+		if m.Provider != "Anthropic" && m.Provider != "Google" {
+			t.Errorf("policy should block provider %s (%s)", m.Provider, m.ID)
+		}
+	}
+	if !strings.Contains(BaseRegistry().ListModels(ListQuery{}), "claude-opus-4-6") {
+		t.Error("allowed provider models should still be listed")
+	}
+}
+
+func TestPolicy_BannedModelsResolveAliases(t *testing.T) {
+	withPolicy(t, &Policy{BannedModels: []string{"opus-4-6"}})
+	if strings.Contains(BaseRegistry().ListModels(ListQuery{Provider: "anthropic"}), "`claude-opus-4-6`") {
+		t.Error("banned model (via alias) should not be listed")
+	}
+	if strings.Contains(BaseRegistry().SearchModels("opus", nil), "`claude-opus-4-6`") {
+		t.Error("banned model should not appear in search results")
+	}
+	if strings.Contains(BaseRegistry().RecommendModel(RecommendQuery{Task: "coding", Budget: "unlimited"}), "claude-opus-4-6") {
+		t.Error("banned model should not be recommended")
+	}
+}
+
+func TestPolicy_PriceCeilingAndStable(t *testing.T) {
+	withPolicy(t, &Policy{MaxPricingInput: 1.00, MaxPricingOutput: 5.00, RequireStable: true})
+	for _, m := range BaseRegistry().FilterModels("", "", "", "", Exclusions{}) {
+		if m.PricingInput > 1.00 || m.PricingOutput > 5.00 {
+			t.Errorf("%s exceeds price ceiling", m.ID)
+		}
+		if m.Status != "current" || strings.Contains(m.ID, "preview") || strings.Contains(m.ID, "beta") {
+			t.Errorf("%s is not stable", m.ID)
+		}
+	}
+}
+
+func TestPolicy_CheckModelStatusAnnotates(t *testing.T) {
+	withPolicy(t, &Policy{BannedModels: []string{"gpt-4o"}})
+	result := BaseRegistry().CheckModelStatus("gpt-4o")
+	if !strings.Contains(result, "Blocked by org policy") || !strings.Contains(result, "banned") {
+		t.Errorf("expected org policy annotation, got: %s", result)
+	}
+	if strings.Contains(BaseRegistry().CheckModelStatus("gpt-5.2"), "Blocked by org policy") {
+		t.Error("allowed model should not be annotated")
+	}
+}
+
+func TestPolicy_ReplacementRespectsPolicy(t *testing.T) {
+	withPolicy(t, &Policy{MaxPricingInput: 0.50})
+	result := BaseRegistry().CheckModelStatus("gpt-4o")
+	idx := strings.Index(result, "Recommended replacement:")
+	if idx < 0 {
+		t.Skip("no replacement suggested under this policy")
+	}
+	rest := result[idx:]
+	start := strings.Index(rest, "(`") + 2
+	end := strings.Index(rest[start:], "`)")
+	id := rest[start : start+end]
+	if m := models.Models[id]; m.PricingInput > 0.50 {
+		t.Errorf("replacement %s is blocked by the policy", id)
+	}
+}
+
+func TestPolicy_ShadowAnnotatesAndCounts(t *testing.T) {
+	withPolicy(t, &Policy{BannedModels: []string{"claude-opus-4-6"}, AllowedProviders: []string{"anthropic", "openai"}, Shadow: true})
+	counts := make(map[string]int)
+	SetShadowObserver(func(rule string) { counts[rule]++ })
+	t.Cleanup(func() { SetShadowObserver(nil) })
+
+	list := BaseRegistry().ListModels(ListQuery{Provider: "anthropic"})
+	if !strings.Contains(list, "claude-opus-4-6 | Claude") {
+		t.Error("shadow policy should not drop banned models")
+	}
+	if !strings.Contains(list, "**Org policy (shadow mode):** 1 model(s) here would be blocked") || !strings.Contains(list, "`claude-opus-4-6` (model is banned)") {
+		t.Errorf("expected a shadow annotation:\n%s", list)
+	}
+	if counts["banned_models"] != 1 {
+		t.Errorf("expected one would-be block counted, got %v", counts)
+	}
+
+	status := BaseRegistry().CheckModelStatus("claude-opus-4-6")
+	if strings.Contains(status, "Do not use this model") || !strings.Contains(status, "shadow mode") {
+		t.Errorf("shadow policy should annotate, not block:\n%s", status)
+	}
+	if got := BaseRegistry().ListModels(ListQuery{Provider: "google"}); !strings.Contains(got, "provider Google is not in the allowed providers") {
+		t.Errorf("expected disallowed providers annotated:\n%s", got)
+	}
+	if counts["allowed_providers"] == 0 {
+		t.Errorf("expected allowed_providers blocks counted, got %v", counts)
+	}
+	if strings.Contains(BaseRegistry().ListModels(ListQuery{Provider: "openai", Status: "current"}), "shadow mode") {
+		t.Error("results the policy allows should not be annotated")
+	}
+
+	// Every tool that shows specific models annotates them the same way.
+	for name, out := range map[string]string{
+		"get_model_info": BaseRegistry().GetModelInfo("claude-opus-4-6"),
+		"compare_models": BaseRegistry().CompareModels([]string{"claude-opus-4-6", "gpt-5"}),
+		"estimate_cost":  BaseRegistry().EstimateCost(CostQuery{ModelIDs: []string{"claude-opus-4-6", "gpt-5"}, InputTokens: 1000, OutputTokens: 500, Requests: 10}),
+	} {
+		if !strings.Contains(out, "**Org policy (shadow mode):**") || !strings.Contains(out, "`claude-opus-4-6` (model is banned)") {
+			t.Errorf("%s: expected a shadow annotation:\n%s", name, out)
+		}
+	}
+	if got := BaseRegistry().ListProviders(nil, nil); !strings.Contains(got, "**Org policy (shadow mode):**") || !strings.Contains(got, "is not in the allowed providers") {
+		t.Errorf("list_providers: expected its cheapest and flagship picks annotated:\n%s", got)
+	}
+	if got := BaseRegistry().GetModelInfo("gpt-5"); strings.Contains(got, "shadow mode") {
+		t.Errorf("an allowed model should not be annotated:\n%s", got)
+	}
+}
+
+func TestUseInCodeFooter_SkipsShadowBlockedModels(t *testing.T) {
+	anthropic := SortedByProvider(BaseRegistry().FilterModels("anthropic", "current", "", "", Exclusions{}))
+	if len(anthropic) < 2 {
+		t.Skip("need two current Anthropic models")
+	}
+	newest, next := anthropic[0].ID, anthropic[1].ID
+	withPolicy(t, &Policy{BannedModels: []string{newest}, Shadow: true})
+
+	list := BaseRegistry().ListModels(ListQuery{Provider: "anthropic", Status: "current"})
+	if !strings.Contains(list, "★ "+newest) {
+		t.Errorf("shadow policy should keep the ★ on %s:\n%s", newest, list)
+	}
+	want := "**→ USE IN CODE: " + next + " (newest per provider that org policy allows"
+	if !strings.Contains(list, want) {
+		t.Errorf("expected the footer to skip the banned %s for %s:\n%s", newest, next, list)
+	}
+}
+
+func TestUseInCodeFooter_Toggle(t *testing.T) {
+	off, on := false, true
+	if got := BaseRegistry().ListModels(ListQuery{Provider: "openai", Footer: &off}); strings.Contains(got, "USE IN CODE") {
+		t.Errorf("footer=false should leave the footer out:\n%s", got)
+	}
+	if got := BaseRegistry().SearchModels("gpt", &off); strings.Contains(got, "USE IN CODE") {
+		t.Errorf("footer=false should leave the search footer out:\n%s", got)
+	}
+
+	SetUseInCodeFooter(false)
+	t.Cleanup(func() { SetUseInCodeFooter(true) })
+	if got := BaseRegistry().ListModels(ListQuery{Provider: "openai"}); strings.Contains(got, "USE IN CODE") {
+		t.Errorf("server default off should leave the footer out:\n%s", got)
+	}
+	if got := BaseRegistry().ListModels(ListQuery{Provider: "openai", Footer: &on}); !strings.Contains(got, "★ = newest by release date") {
+		t.Errorf("footer=true should override the server default:\n%s", got)
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+	good := dir + "/policy.json"
+	if err := os.WriteFile(good, []byte(`{"allowed_providers":["OpenAI"],"banned_models":["gpt-4o"],"max_pricing_input":5,"require_stable":true}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := LoadPolicy(good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.BlockReason(models.Models["gpt-4o"]) == "" {
+		t.Error("gpt-4o should be blocked by loaded policy")
+	}
+	if p.BlockReason(models.Models["claude-opus-4-6"]) == "" {
+		t.Error("Anthropic should be blocked by loaded policy")
+	}
+
+	bad := dir + "/typo.json"
+	if err := os.WriteFile(bad, []byte(`{"allowed_provider":["OpenAI"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPolicy(bad); err == nil {
+		t.Error("expected error for unknown policy field")
+	}
+}
+
+// ── Registry diff tests ──────────────────────────────────────────────
+
+func TestDiffRegistries(t *testing.T) {
+	old := map[string]models.Model{
+		"a": {ID: "a", Status: "current", PricingInput: 1},
+		"b": {ID: "b", Status: "current"},
+	}
+	cur := map[string]models.Model{
+		"a": {ID: "a", Status: "legacy", PricingInput: 2},
+		"c": {ID: "c", Status: "current"},
+	}
+	d := DiffRegistries(old, cur)
+	if strings.Join(d.Added, ",") != "c" || strings.Join(d.Removed, ",") != "b" {
+		t.Errorf("added=%v removed=%v", d.Added, d.Removed)
+	}
+	if len(d.Changed) != 1 || d.Changed[0].ID != "a" || len(d.Changed[0].Fields) != 2 {
+		t.Fatalf("unexpected changes: %+v", d.Changed)
+	}
+	fields := map[string]FieldChange{}
+	for _, f := range d.Changed[0].Fields {
+		fields[f.Field] = f
+	}
+	if f := fields["status"]; f.Old != "current" || f.New != "legacy" {
+		t.Errorf("status change = %+v", f)
+	}
+	if f := fields["pricing_input"]; f.Old != "1" || f.New != "2" {
+		t.Errorf("pricing_input change = %+v", f)
+	}
+
+	out := FormatRegistryDiff(d, "old.json", "live")
+	for _, want := range []string{"1 added, 1 removed, 1 changed", "### Added", "- `c`", "| `a` | status | current | legacy |"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestDiffRegistries_LiveAgainstItself(t *testing.T) {
+	data, err := json.Marshal(models.Models)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snap, err := ParseSnapshot(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := DiffRegistries(snap, models.Models)
+	if !d.Empty() {
+		t.Errorf("round-tripped registry should not differ: %+v", d)
+	}
+	if !strings.Contains(FormatRegistryDiff(d, "x", "y"), "No differences.") {
+		t.Error("expected 'No differences.'")
+	}
+}
+
+func TestParseSnapshot_ArrayAndInvalid(t *testing.T) {
+	snap, err := ParseSnapshot([]byte(`[{"id":"gpt-5","provider":"OpenAI"}]`))
+	if err != nil || snap["gpt-5"].Provider != "OpenAI" {
+		t.Errorf("array snapshot: %v, %+v", err, snap)
+	}
+	if _, err := ParseSnapshot([]byte(`"nope"`)); err == nil {
+		t.Error("expected error for invalid snapshot")
+	}
+}
+
+func TestFetchSnapshot(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"gpt-5":{"id":"gpt-5","status":"legacy"}}`))
+	}))
+	defer srv.Close()
+
+	snap, err := FetchSnapshot(context.Background(), srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := DiffRegistries(snap, models.Models)
+	if len(d.Changed) != 1 || d.Changed[0].ID != "gpt-5" {
+		t.Errorf("expected gpt-5 changed, got %+v", d.Changed)
+	}
+}
+
+func TestSnapshotClient_RefusesPrivateAddresses(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	_, err := FetchSnapshot(context.Background(), NewSnapshotClient(time.Second), srv.URL)
+	if !errors.Is(err, errPrivateAddress) {
+		t.Errorf("expected a loopback snapshot_url to be refused, got %v", err)
+	}
+	for addr, private := range map[string]bool{
+		"127.0.0.1:443":       true,
+		"10.1.2.3:443":        true,
+		"169.254.169.254:80":  true,
+		"100.64.0.1:443":      true,
+		"[::1]:443":           true,
+		"[fe80::1]:443":       true,
+		"[::ffff:10.0.0.1]:1": true,
+		"0.0.0.0:443":         true,
+		"93.184.215.14:443":   false,
+	} {
+		if err := publicAddressOnly("tcp", addr, nil); (err != nil) != private {
+			t.Errorf("publicAddressOnly(%s) = %v, want refused=%v", addr, err, private)
+		}
+	}
+}
+
+// ── Speed benchmark tests ────────────────────────────────────────────
+
+func TestFastestModels_Throughput(t *testing.T) {
+	result := BaseRegistry().FastestModels("", "", 3)
+	if !strings.Contains(result, "| 1 | `gemini-2.5-flash-lite` |") {
+		t.Errorf("expected gemini-2.5-flash-lite first by throughput, got:\n%s", result)
+	}
+	if strings.Contains(result, "| 4 |") {
+		t.Errorf("limit 3 should return 3 rows, got:\n%s", result)
+	}
+}
+
+func TestFastestModels_TTFTAndProvider(t *testing.T) {
+	result := BaseRegistry().FastestModels("ttft", "openai", 0)
+	if !strings.Contains(result, "time to first token") || !strings.Contains(result, "| 1 | `gpt-4.1-nano` |") {
+		t.Errorf("expected gpt-4.1-nano first by TTFT, got:\n%s", result)
+	}
+	if strings.Contains(result, "Anthropic") {
+		t.Errorf("provider filter leaked other providers:\n%s", result)
+	}
+}
+
+func TestFastestModels_UnknownMetricAndNoData(t *testing.T) {
+	if !strings.Contains(BaseRegistry().FastestModels("cost", "", 0), "Unknown metric") {
+		t.Error("expected unknown metric message")
+	}
+	if !strings.Contains(BaseRegistry().FastestModels("", "perplexity", 0), "No speed benchmark data") {
+		t.Error("expected no-data message for provider without benchmarks")
+	}
+}
+
+func TestGetModelInfo_Speed(t *testing.T) {
+	if !strings.Contains(BaseRegistry().GetModelInfo("gpt-4.1"), "| Speed | 90 output tok/s, 500 ms TTFT") {
+		t.Error("expected speed row for gpt-4.1")
+	}
+	if !strings.Contains(BaseRegistry().GetModelInfo("sonar"), "| Speed | — |") {
+		t.Error("expected empty speed row for sonar")
+	}
+}
+
+func TestGetModelInfo_ParamConstraints(t *testing.T) {
+	result := BaseRegistry().GetModelInfo("o3")
+	for _, want := range []string{"| Parameters | Rejects presence_penalty", "only temperature=1, top_p=1", "supports reasoning_effort"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in o3 parameters row:\n%s", want, result)
+		}
+	}
+	if !strings.Contains(BaseRegistry().GetModelInfo("claude-opus-4-6"), "| Parameters | Send only one of temperature or top_p") {
+		t.Error("expected exclusive temperature/top_p note for claude-opus-4-6")
+	}
+	if !strings.Contains(BaseRegistry().GetModelInfo("gpt-4.1"), "| Parameters | Standard (no known restrictions) |") {
+		t.Error("expected standard parameters row for gpt-4.1")
+	}
+}
+
+func TestGetModelInfo_ReasoningControl(t *testing.T) {
+	for id, want := range map[string]string{
+		"gpt-5.2":          "| Reasoning Control | reasoning_effort: none / low / medium / high / xhigh (default none). Example: `\"reasoning_effort\": \"medium\"`",
+		"claude-opus-4-5":  "| Reasoning Control | thinking: budget of at least 1,024 tokens. Example: `\"thinking\": {\"type\": \"enabled\", \"budget_tokens\": 10000}`",
+		"gemini-2.5-flash": "| Reasoning Control | thinking_budget: 0-24,576 token budget",
+		"gpt-4.1":          "| Reasoning Control | — |",
+	} {
+		if got := BaseRegistry().GetModelInfo(id); !strings.Contains(got, want) {
+			t.Errorf("expected %q in %s:\n%s", want, id, got)
+		}
+	}
+}
+
+func TestGetUsageExample(t *testing.T) {
+	for id, wants := range map[string][]string{
+		"gpt-5.2": {"client.chat.completions.create(", "max_completion_tokens=1024", `reasoning_effort="medium"`, `os.environ["OPENAI_API_KEY"]`},
+		"o3-pro":  {"client.responses.create(", `reasoning={"effort": "high"}`, "Responses API only"},
+		"claude-opus-4-5": {`base_url="https://api.anthropic.com/v1/"`, "max_tokens=1024",
+			`extra_body={"thinking": {"type": "enabled", "budget_tokens": 10000}}`},
+		"gemini-2.5-flash": {"through the native API", "thinking_budget: 0-24,576 token budget"},
+		"amazon-nova-pro":  {"no OpenAI-compatible endpoint", "Bedrock Converse API"},
+	} {
+		got := BaseRegistry().GetUsageExample(id)
+		for _, want := range wants {
+			if !strings.Contains(got, want) {
+				t.Errorf("expected %q in usage example for %s:\n%s", want, id, got)
+			}
+		}
+	}
+	if got := BaseRegistry().GetUsageExample("gemini-2.5-flash"); strings.Contains(got, "generationConfig=") || strings.Contains(got, "extra_body") {
+		t.Errorf("native-only settings must stay out of the SDK call:\n%s", got)
+	}
+	if !strings.Contains(BaseRegistry().GetUsageExample("gpt-9000"), "not found") {
+		t.Error("expected not-found message")
+	}
+}
+
+// ── Monthly cost projection tests ─────────────────────────────────────
+
+func TestMonthlyCostProjection(t *testing.T) {
+	// 1,000 req/day × 30 days = 30M input and 15M output tokens.
+	result := BaseRegistry().MonthlyCostProjection([]string{"gpt-4.1", "gpt-4o-mini"}, 1000, 1000, 500, 0)
+	for _, want := range []string{
+		"(30 days)",
+		"30.0M input and 15.0M output tokens",
+		"| `gpt-4.1` | OpenAI | $60.00 | $120.00 | **$180.00** | — |",
+		"| `gpt-4o-mini` | OpenAI | $4.50 | $9.00 | **$13.50** | $166.50 (92%) |",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in:\n%s", want, result)
+		}
+	}
+	if strings.Index(result, "gpt-4o-mini") > strings.Index(result, "`gpt-4.1`") {
+		t.Error("expected cheapest model first")
+	}
+}
+
+func TestMonthlyCostProjection_InvalidInput(t *testing.T) {
+	if got := BaseRegistry().MonthlyCostProjection(nil, 1000, 100, 100, 30); !strings.Contains(got, "at least 1 model") {
+		t.Errorf("expected missing model error, got: %s", got)
+	}
+	if got := BaseRegistry().MonthlyCostProjection([]string{"gpt-4.1"}, 0, 100, 100, 30); !strings.Contains(got, "requests_per_day") {
+		t.Errorf("expected volume error, got: %s", got)
+	}
+	if got := BaseRegistry().MonthlyCostProjection([]string{"gpt-4.1", "not-a-model"}, 10, 100, 100, 30); !strings.Contains(got, "not found") {
+		t.Errorf("expected not-found error, got: %s", got)
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	// gpt-4.1: $2 input, $8 output per 1M tokens.
+	result := BaseRegistry().EstimateCost(CostQuery{ModelIDs: []string{"gpt-4.1"}, InputTokens: 2000, OutputTokens: 500, Requests: 1000})
+	for _, want := range []string{
+		"1,000 request(s) × (2,000 input + 500 output tokens) = 2,000,000 input and 500,000 output tokens.",
+		"| `gpt-4.1` | OpenAI | $4.00 | $4.00 | **$8.00** | $0.0080 | $4.00 |",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in result, got:\n%s", want, result)
+		}
+	}
+	if strings.Contains(result, "vs cheapest") {
+		t.Errorf("a single model has nothing to compare against:\n%s", result)
+	}
+
+	// One request is the default.
+	if result := BaseRegistry().EstimateCost(CostQuery{ModelIDs: []string{"gpt-4.1"}, InputTokens: 2000, OutputTokens: 500}); !strings.Contains(result, "**$0.0080**") {
+		t.Errorf("expected the cost of one request, got:\n%s", result)
+	}
+}
+
+func TestEstimateCost_Compare(t *testing.T) {
+	result := BaseRegistry().EstimateCost(CostQuery{ModelIDs: []string{"gpt-4.1", "gpt-4.1-nano", "gpt-4.1"}, InputTokens: 1000, OutputTokens: 1000, Requests: 100})
+	nano, full := strings.Index(result, "`gpt-4.1-nano`"), strings.Index(result, "`gpt-4.1` |")
+	if nano < 0 || full < 0 || nano > full {
+		t.Errorf("expected gpt-4.1-nano first (cheapest), got:\n%s", result)
+	}
+	if strings.Count(result, "`gpt-4.1` |") != 1 {
+		t.Errorf("duplicate IDs should be estimated once:\n%s", result)
+	}
+	if !strings.Contains(result, "| cheapest |") || !strings.Contains(result, "×) |") {
+		t.Errorf("expected a vs-cheapest column, got:\n%s", result)
+	}
+}
+
+func TestEstimateCost_TooManyModels(t *testing.T) {
+	ids := []string{"gpt-4.1", "gpt-4.1-mini", "gpt-4.1-nano", "gpt-5", "gpt-5-mini", "gpt-5-nano"}
+	got := BaseRegistry().EstimateCost(CostQuery{ModelIDs: ids, InputTokens: 1000, OutputTokens: 1000, Requests: 1})
+	if !strings.Contains(got, "at most 5 models, got 6") || !strings.Contains(got, "gpt-5-nano") || strings.Contains(got, "## Estimated cost") {
+		t.Errorf("expected an error naming the models instead of a silently cut estimate:\n%s", got)
+	}
+}
+
+func TestEstimateCost_CacheHitRate(t *testing.T) {
+	// gpt-4.1: $2 input, $0.50 cached input, $8 output per 1M tokens. Of
+	// 2M input tokens, 1M bill at each rate: $2.00 + $0.50.
+	result := BaseRegistry().EstimateCost(CostQuery{ModelIDs: []string{"gpt-4.1", "mistral-large-2512"}, InputTokens: 2000, OutputTokens: 500, Requests: 1000, CacheHitRate: 0.5})
+	for _, want := range []string{
+		"50% of input tokens are cache hits",
+		"| `gpt-4.1` | OpenAI | $2.50 | $4.00 | **$6.50** | $0.0065 | $3.25 |",
+		"**No cached-input rate recorded** for `mistral-large-2512`",
+		"excludes cache write surcharges",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in result, got:\n%s", want, result)
+		}
+	}
+	if got := BaseRegistry().EstimateCost(CostQuery{ModelIDs: []string{"gpt-4.1"}, InputTokens: 2000, OutputTokens: 500, Requests: 1, CacheHitRate: 1.5}); !strings.Contains(got, "cache_hit_rate must be between 0 and 1") {
+		t.Errorf("expected a range error, got %q", got)
+	}
+	if got := BaseRegistry().EstimateCost(CostQuery{ModelIDs: []string{"gpt-4.1"}, InputTokens: 2000, OutputTokens: 500, Requests: 1}); strings.Contains(got, "cache hits") || !strings.Contains(got, "set cache_hit_rate") {
+		t.Errorf("without a hit rate the estimate should bill list prices and point at cache_hit_rate:\n%s", got)
+	}
+}
+
+func TestEstimateCost_InvalidInput(t *testing.T) {
+	if got := BaseRegistry().EstimateCost(CostQuery{InputTokens: 100, OutputTokens: 100, Requests: 1}); !strings.Contains(got, "model_id") {
+		t.Errorf("expected a missing-model message, got %q", got)
+	}
+	if got := BaseRegistry().EstimateCost(CostQuery{ModelIDs: []string{"gpt-4.1"}, Requests: 1}); !strings.Contains(got, "token estimate") {
+		t.Errorf("expected a token-profile message, got %q", got)
+	}
+	if got := BaseRegistry().EstimateCost(CostQuery{ModelIDs: []string{"gpt-4.1", "not-a-model"}, InputTokens: 10, OutputTokens: 10, Requests: 1}); !strings.Contains(got, "not found") {
+		t.Errorf("expected a not-found message, got %q", got)
+	}
+}
+
+func TestMonthlyCostProjection_OutputDominatedWarning(t *testing.T) {
+	result := BaseRegistry().MonthlyCostProjection([]string{"o3", "gpt-4.1"}, 100, 1000, 1000, 30)
+	if !strings.Contains(result, "**Output-dominated cost:** `o3` spends 80% on output tokens (output is priced 4.0× input).") {
+		t.Errorf("expected o3 output warning in:\n%s", result)
+	}
+	if !strings.Contains(result, "hidden thinking tokens") {
+		t.Errorf("expected reasoning-token note for o3 in:\n%s", result)
+	}
+
+	balanced := BaseRegistry().MonthlyCostProjection([]string{"gpt-4.1"}, 1000, 1000, 500, 30)
+	if strings.Contains(balanced, "Output-dominated") {
+		t.Errorf("67%% output share should not warn:\n%s", balanced)
+	}
+	nonReasoning := BaseRegistry().MonthlyCostProjection([]string{"gpt-4.1"}, 100, 100, 1000, 30)
+	if !strings.Contains(nonReasoning, "`gpt-4.1` spends 98%") || strings.Contains(nonReasoning, "hidden thinking") {
+		t.Errorf("expected warning without reasoning note:\n%s", nonReasoning)
+	}
+}
+
+func TestMonthlyCostProjection_LongContextPricing(t *testing.T) {
+	// 300k input tokens crosses gemini-2.5-pro's 200k breakpoint: 10 req/day × 30
+	// days = 90M input at $2.50 and 3M output at $15.00.
+	long := BaseRegistry().MonthlyCostProjection([]string{"gemini-2.5-pro", "gpt-4.1"}, 10, 300_000, 10_000, 30)
+	for _, want := range []string{
+		"| `gemini-2.5-pro` | Google | $225.00 | $45.00 | **$270.00** |",
+		"**Long-context pricing:** 300,000 input tokens per request crosses the breakpoint, so every request to `gemini-2.5-pro` ($2.50/$15.00) bills",
+	} {
+		if !strings.Contains(long, want) {
+			t.Errorf("expected %q in:\n%s", want, long)
+		}
+	}
+	if strings.Contains(long, "`gpt-4.1` ($") {
+		t.Errorf("gpt-4.1 has no breakpoint and should not be listed:\n%s", long)
+	}
+
+	short := BaseRegistry().MonthlyCostProjection([]string{"gemini-2.5-pro"}, 10, 200_000, 10_000, 30)
+	if !strings.Contains(short, "| `gemini-2.5-pro` | Google | $75.00 | $30.00 |") || strings.Contains(short, "Long-context pricing") {
+		t.Errorf("200k input is at the breakpoint, not past it; expected base rates:\n%s", short)
+	}
+}
+
+func TestGetModelInfo_LongContextPricingRow(t *testing.T) {
+	if got := BaseRegistry().GetModelInfo("gemini-2.5-pro"); !strings.Contains(got, "| Long-Context Pricing | $2.50 / $15.00 per 1M tokens when input exceeds 200,000 tokens (whole request) |") {
+		t.Errorf("expected long-context row in:\n%s", got)
+	}
+	if got := BaseRegistry().GetModelInfo("gpt-4.1"); !strings.Contains(got, "| Long-Context Pricing | Same rate at any prompt length |") {
+		t.Errorf("expected flat-rate row in:\n%s", got)
+	}
+}
+
+func TestCompareModels_PriceRatioRow(t *testing.T) {
+	result := BaseRegistry().CompareModels([]string{"o3", "gpt-4.1"})
+	if !strings.Contains(result, "| Output/Input Price | 4.0× | 4.0× |") {
+		t.Errorf("expected price ratio row in:\n%s", result)
+	}
+}
+
+func TestCompareModels_BlendedRow(t *testing.T) {
+	o3, gpt41 := models.Models["o3"], models.Models["gpt-4.1"]
+	row := func(r float64) string {
+		return fmt.Sprintf("| Blended $/1M (%g:1 in:out) | $%.2f | $%.2f |", r,
+			(r*o3.PricingInput+o3.PricingOutput)/(r+1), (r*gpt41.PricingInput+gpt41.PricingOutput)/(r+1))
+	}
+	if result := BaseRegistry().CompareModels([]string{"o3", "gpt-4.1"}); !strings.Contains(result, row(3)) {
+		t.Errorf("expected %q in:\n%s", row(3), result)
+	}
+	models.SetBlendRatio(1)
+	t.Cleanup(func() { models.SetBlendRatio(0) })
+	if result := BaseRegistry().CompareModels([]string{"o3", "gpt-4.1"}); !strings.Contains(result, row(1)) {
+		t.Errorf("configured ratio not applied, expected %q in:\n%s", row(1), result)
+	}
+}
+
+func TestFormatUSD(t *testing.T) {
+	for v, want := range map[float64]string{0: "$0.00", 13.5: "$13.50", 1234567.891: "$1,234,567.89"} {
+		if got := formatUSD(v); got != want {
+			t.Errorf("formatUSD(%v) = %q, want %q", v, got, want)
+		}
+	}
+}
+
+// ── OpenAI-compatible endpoint tests ────────────────────────────────
+
+func TestGetModelInfo_OpenAIEndpoint(t *testing.T) {
+	if r := BaseRegistry().GetModelInfo("deepseek-chat"); !strings.Contains(r, "| OpenAI SDK | Compatible — base_url `https://api.deepseek.com`") {
+		t.Errorf("expected DeepSeek base_url in model info:\n%s", r)
+	}
+	if r := BaseRegistry().GetModelInfo("amazon-nova-pro"); !strings.Contains(r, "| OpenAI SDK | Not OpenAI-compatible.") {
+		t.Errorf("expected Amazon to be marked not compatible:\n%s", r)
+	}
+	if r := BaseRegistry().GetModelInfo("mimo-v2-flash"); !strings.Contains(r, "| OpenAI SDK | Unknown |") {
+		t.Errorf("expected unknown endpoint for a provider without metadata:\n%s", r)
+	}
+}
+
+func TestGetModelInfo_APIsRow(t *testing.T) {
+	if got := BaseRegistry().GetModelInfo("o3-pro"); !strings.Contains(got, "| APIs | responses only — chat/completions calls are rejected |") {
+		t.Errorf("expected responses-only row for o3-pro:\n%s", got)
+	}
+	if got := BaseRegistry().GetModelInfo("gpt-4.1"); !strings.Contains(got, "| APIs | chat/completions, responses, assistants |") {
+		t.Errorf("expected API list for gpt-4.1:\n%s", got)
+	}
+	if got := BaseRegistry().GetModelInfo("claude-sonnet-4-6"); !strings.Contains(got, "| APIs | — |") {
+		t.Errorf("expected no API list outside OpenAI:\n%s", got)
+	}
+}
+
+// ── Deprecation impact tests ────────────────────────────────────────
+
+func TestDeprecationImpact_ListsAliasesAndGrep(t *testing.T) {
+	result := BaseRegistry().DeprecationImpact("gpt-4o")
+	for _, want := range []string{
+		"## Deprecation impact: GPT-4o (`gpt-4o`)",
+		"| `gpt4o` | alias |",
+		"| `openai/gpt-4o` | platform IDs |",
+		"Suggested replacement:",
+		"(gpt-4o|",
+		"- [ ] Limits and cost: `gpt-4o` has 128,000 context",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in impact report:\n%s", want, result)
+		}
+	}
+}
+
+func TestDeprecationImpact_FloatingAliases(t *testing.T) {
+	target := models.Aliases["opus"]
+	result := BaseRegistry().DeprecationImpact(target)
+	if !strings.Contains(result, "| `opus` | floating alias |") || !strings.Contains(result, "- [ ] Callers of claude-opus, claude-opus-latest, opus") {
+		t.Errorf("expected floating aliases for %s:\n%s", target, result)
+	}
+	if strings.Contains(result, "Suggested replacement") {
+		t.Error("current models should not get a replacement suggestion")
+	}
+}
+
+func TestDeprecationImpact_GrepMatchesWholeIDs(t *testing.T) {
+	pattern := impactGrep([]string{"gpt-4o", "gpt4o"})
+	re := regexp.MustCompile(pattern[strings.Index(pattern, "'")+1 : strings.LastIndex(pattern, "'")])
+	for line, want := range map[string]bool{
+		`model = "gpt-4o"`:      true,
+		`"openai/gpt-4o"`:       true,
+		`MODEL=gpt4o`:           true,
+		`model = "gpt-4o-mini"`: false,
+		`"gpt-4o.1"`:            false,
+		`x-gpt-4o`:              false,
+	} {
+		if got := re.MatchString(line); got != want {
+			t.Errorf("grep pattern on %q = %v, want %v", line, got, want)
+		}
+	}
+}
+
+func TestDeprecationImpact_NotFound(t *testing.T) {
+	if result := BaseRegistry().DeprecationImpact("no-such-model-xyz"); !strings.Contains(result, "not found") {
+		t.Errorf("expected not found message, got %s", result)
+	}
+	if result := BaseRegistry().DeprecationImpact(""); !strings.Contains(result, "Please provide a model ID") {
+		t.Errorf("expected prompt for model ID, got %s", result)
+	}
+}
+
+func TestRetiredNames(t *testing.T) {
+	names := BaseRegistry().RetiredNames()
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		set[n] = true
+	}
+	if !sort.StringsAreSorted(names) || len(set) != len(names) {
+		t.Error("expected a sorted list without duplicates")
+	}
+	for alias, id := range models.Aliases {
+		m := models.Models[id]
+		retired := m.Status == "legacy" || m.Status == "deprecated"
+		if retired && !set[strings.ToLower(alias)] {
+			t.Errorf("alias %s of %s model %s missing", alias, m.Status, id)
+		}
+	}
+	for id, m := range models.Models {
+		retired := m.Status == "legacy" || m.Status == "deprecated"
+		if set[strings.ToLower(id)] != retired {
+			t.Errorf("%s (%s): listed = %v", id, m.Status, set[strings.ToLower(id)])
+		}
+	}
+	if set["opus"] || set["gemini-pro"] {
+		t.Error("floating aliases point at current models and must not be listed")
+	}
+}
+
+// ── Coverage tests ──────────────────────────────────────────────────
+
+// withScrapeHistory installs h for the duration of the test.
+func withScrapeHistory(t *testing.T, h *ScrapeHistory) {
+	t.Helper()
+	SetScrapeHistory(h)
+	t.Cleanup(func() { SetScrapeHistory(nil) })
+}
+
+func TestGetCoverage_NoHistory(t *testing.T) {
+	result := BaseRegistry().GetCoverage("")
+	if !strings.Contains(result, "| Mistral |") || !strings.Contains(result, "not scraped") {
+		t.Errorf("expected every provider listed as not scraped:\n%s", result)
+	}
+	if !strings.Contains(result, "No updater history is loaded") {
+		t.Errorf("expected a hint to configure coverage_file:\n%s", result)
+	}
+}
+
+func TestGetCoverage_FromHistory(t *testing.T) {
+	active := 0
+	for _, m := range models.Models {
+		if m.Provider == "Mistral" && m.Status != "deprecated" {
+			active++
+		}
+	}
+	scraped := active * 2
+	withScrapeHistory(t, &ScrapeHistory{Counts: map[string][]ScrapeSample{
+		"Mistral/docs": {{Date: "2026-01-01", Count: 1}, {Date: "2026-02-01", Count: scraped}},
+		"Mistral/api":  {{Date: "2026-01-15", Count: 99}},
+	}})
+
+	result := BaseRegistry().GetCoverage("mistral")
+	want := fmt.Sprintf("| %d | docs | 2026-02-01 | 50%% |", scraped)
+	if !strings.Contains(result, want) {
+		t.Errorf("expected %q (latest docs sample wins):\n%s", want, result)
+	}
+	if strings.Contains(result, "| OpenAI |") {
+		t.Error("provider filter should limit rows")
+	}
+
+	list := BaseRegistry().ListModels(ListQuery{Provider: "mistral"})
+	if !strings.Contains(list, fmt.Sprintf("Coverage: %d current and legacy Mistral models tracked against %d IDs", active, scraped)) {
+		t.Errorf("expected coverage line in provider listing:\n%s", list)
+	}
+	if strings.Contains(BaseRegistry().ListModels(ListQuery{}), "Coverage:") {
+		t.Error("unfiltered listings should not carry a coverage line")
+	}
+}
+
+func TestGetCoverage_UnknownProvider(t *testing.T) {
+	if result := BaseRegistry().GetCoverage("nonexistent"); !strings.Contains(result, "No models tracked") {
+		t.Errorf("unexpected result: %s", result)
+	}
+}
+
+func TestLoadScrapeHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	os.WriteFile(path, []byte(`{"counts": {"OpenAI/docs": [{"date": "2026-02-01", "count": 40}]}}`), 0o644)
+	h, err := LoadScrapeHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := h.latestScrape("OpenAI"); !ok || s.Count != 40 {
+		t.Errorf("latestScrape = %+v, %v", s, ok)
+	}
+	os.WriteFile(path, []byte(`not json`), 0o644)
+	if _, err := LoadScrapeHistory(path); err == nil {
+		t.Error("expected parse error")
+	}
+}
+
+// ── Provider default tests ──────────────────────────────────────────
+
+func TestListModels_ProviderDefault(t *testing.T) {
+	results := BaseRegistry().FilterModels("", "", "default", "", Exclusions{})
+	if len(results) == 0 {
+		t.Fatal("expected provider defaults")
+	}
+	for _, m := range results {
+		if !m.ProviderDefault {
+			t.Errorf("%s is not a provider default", m.ID)
+		}
+	}
+	google := BaseRegistry().FilterModels("google", "", "default", "", Exclusions{})
+	if len(google) != 1 || google[0].ID != "gemini-2.5-pro" {
+		t.Errorf("Google default = %v, want only gemini-2.5-pro (the GA model, not the newest preview)", google)
+	}
+}
+
+func TestGetModelInfo_ProviderDefaultRow(t *testing.T) {
+	if got := BaseRegistry().GetModelInfo("gemini-2.5-pro"); !strings.Contains(got, "| Provider Default | Yes |") {
+		t.Errorf("expected Provider Default Yes row:\n%s", got)
+	}
+	if got := BaseRegistry().GetModelInfo("gemini-3.1-pro-preview"); !strings.Contains(got, "| Provider Default | No |") {
+		t.Errorf("expected Provider Default No row:\n%s", got)
+	}
+}
+
+// ── Similar models tests ────────────────────────────────────────────
+
+func TestGetSimilarModels(t *testing.T) {
+	result := BaseRegistry().GetSimilarModels("claude-sonnet-4-6", 3, false)
+	if !strings.Contains(result, "## Models similar to") {
+		t.Fatalf("unexpected result: %s", result)
+	}
+	if strings.Contains(result, "| `claude-sonnet-4-6` |") {
+		t.Error("the baseline model should not be listed as its own alternative")
+	}
+	if n := strings.Count(result, "\n| ") - 1; n != 3 {
+		t.Errorf("got %d rows, want 3:\n%s", n, result)
+	}
+	for _, want := range []string{"Similarity", "Price (in / out)", "Similarity weights:"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("missing %q:\n%s", want, result)
+		}
+	}
+}
+
+func TestGetSimilarModels_OtherProvidersOnly(t *testing.T) {
+	result := BaseRegistry().GetSimilarModels("gpt-5.4", 10, true)
+	if strings.Contains(result, "| OpenAI |") {
+		t.Errorf("other_providers_only should drop OpenAI models:\n%s", result)
+	}
+}
+
+func TestGetSimilarModels_NotFound(t *testing.T) {
+	if result := BaseRegistry().GetSimilarModels("not-a-model", 5, false); !strings.Contains(result, "not found") {
+		t.Errorf("unexpected result: %s", result)
+	}
+}
+
+func TestCompareSimilarity(t *testing.T) {
+	a := models.Model{PricingInput: 1, PricingOutput: 4, ContextWindow: 200_000, Vision: true, ToolCalling: true, StructuredOutput: true, ReleaseDate: "2025-06"}
+	if got := compareSimilarity(a, a).total(); math.Abs(got-1) > 1e-9 {
+		t.Errorf("identical models score %v, want 1", got)
+	}
+	far := models.Model{PricingInput: 20, PricingOutput: 80, ContextWindow: 8_000, Reasoning: true, JSONMode: true, BatchAPI: true, ReleaseDate: "2023-01"}
+	if got := compareSimilarity(a, far).total(); got > 0.1 {
+		t.Errorf("dissimilar models score %v, want near 0", got)
+	}
+	if got := capsDelta(a, far); got != "-vision, +reasoning, -tool_use, -structured_output, +json_mode, +batch" {
+		t.Errorf("capsDelta = %q", got)
+	}
+}
+
+// ── Maturity tests ──────────────────────────────────────────────────
+
+func TestListModels_MaturityStable(t *testing.T) {
+	all := BaseRegistry().ListModels(ListQuery{Provider: "google", Status: "current"})
+	stable := BaseRegistry().ListModels(ListQuery{Provider: "google", Status: "current", Maturity: "stable"})
+	if !strings.Contains(all, "gemini-3.1-pro-preview") {
+		t.Fatalf("expected previews without a maturity filter:\n%s", all)
+	}
+	if strings.Contains(stable, "preview") {
+		t.Errorf("maturity stable should drop previews:\n%s", stable)
+	}
+	if !strings.Contains(stable, "★ ") {
+		t.Errorf("expected ★ on the newest stable Gemini:\n%s", stable)
+	}
+	if got := BaseRegistry().ListModels(ListQuery{Maturity: "Preview"}); !strings.Contains(got, "grok-4.20-beta-0309") {
+		t.Error("maturity preview should include betas")
+	}
+	if got := BaseRegistry().ListModels(ListQuery{Maturity: "beta"}); !strings.Contains(got, "Unknown maturity") {
+		t.Errorf("unexpected result: %s", got)
+	}
+}
+
+func TestGetModelInfo_MaturityRow(t *testing.T) {
+	if got := BaseRegistry().GetModelInfo("gemini-3-flash-preview"); !strings.Contains(got, "| Maturity | preview |") {
+		t.Errorf("expected Maturity preview row:\n%s", got)
+	}
+}
+
+func TestTenantOverlay_DefaultsMaturity(t *testing.T) {
+	reg, err := NewTenantRegistry("acme", map[string]models.Model{
+		"acme-router": {Provider: "Acme", Status: "current"},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, _ := reg.FindModel("acme-router"); m.Maturity != models.MaturityStable {
+		t.Errorf("overlay maturity = %q, want stable", m.Maturity)
+	}
+	_, err = NewTenantRegistry("acme", map[string]models.Model{
+		"acme-router": {Provider: "Acme", Status: "current", Maturity: "alpha"},
+	}, nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid maturity") {
+		t.Errorf("expected invalid maturity error, got %v", err)
+	}
+}
+
+// ── Tenant registry tests ────────────────────────────────────────────
+
+var tenantOverlay = map[string]models.Model{
+	"acme-router-v2": {
+		DisplayName:   "Acme Router v2",
+		Provider:      "Acme",
+		ContextWindow: 32_000,
+		PricingInput:  0.1,
+		PricingOutput: 0.2,
+		Status:        "current",
+		Notes:         "Internal fine-tune",
+	},
+}
+
+func TestTenantRegistry_OverlayIsScoped(t *testing.T) {
+	reg, err := NewTenantRegistry("acme", tenantOverlay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, ok := reg.FindModel("acme-router-v2")
+	if !ok || m.ID != "acme-router-v2" {
+		t.Fatalf("tenant should find its overlay model, got %q, %v", m.ID, ok)
+	}
+	if _, ok := reg.FindModel("gpt-5"); !ok {
+		t.Error("tenant should still see base models")
+	}
+	if _, ok := BaseRegistry().FindModel("acme-router-v2"); ok {
+		t.Error("overlay model leaked into the base registry")
+	}
+	if !strings.Contains(reg.ListModels(ListQuery{Provider: "acme"}), "acme-router-v2") {
+		t.Error("overlay model should be listed under its provider")
+	}
+}
+
+func TestRegistryInfo(t *testing.T) {
+	base := BaseRegistry().Info()
+	if base.Models != len(models.Models) || len(base.Version) != 12 || base.Capabilities["vision"] == 0 {
+		t.Errorf("unexpected base info: %+v", base)
+	}
+	same, err := NewTenantRegistry("same", nil, &Policy{BannedModels: []string{"gpt-5"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if same.Info().Version != base.Version {
+		t.Error("policy alone should not change the registry version")
+	}
+	acme, err := NewTenantRegistry("acme", tenantOverlay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := acme.Info()
+	if info.Version == base.Version || info.Models != base.Models+1 || info.Providers != base.Providers+1 {
+		t.Errorf("overlay should change version and counts: %+v vs %+v", info, base)
+	}
+}
+
+func TestTenantRegistry_OwnPolicy(t *testing.T) {
+	reg, err := NewTenantRegistry("acme", nil, &Policy{BannedModels: []string{"gpt-5"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(reg.CheckModelStatus("gpt-5"), "Blocked by org policy") {
+		t.Error("tenant policy should block gpt-5")
+	}
+	if strings.Contains(BaseRegistry().CheckModelStatus("gpt-5"), "Blocked by org policy") {
+		t.Error("tenant policy should not apply to the base registry")
+	}
+
+	withPolicy(t, &Policy{BannedModels: []string{"o3"}})
+	if strings.Contains(reg.CheckModelStatus("o3"), "Blocked") {
+		t.Error("a tenant policy replaces the server-wide policy")
+	}
+	inherit, _ := NewTenantRegistry("plain", nil, nil)
+	if !strings.Contains(inherit.CheckModelStatus("o3"), "Blocked") {
+		t.Error("a tenant without a policy should inherit the server-wide policy")
+	}
+}
+
+func TestTenantRegistry_RejectsInvalidOverlay(t *testing.T) {
+	for name, overlay := range map[string]map[string]models.Model{
+		"mismatched ID": {"a": {ID: "b", Provider: "Acme", Status: "current"}},
+		"no provider":   {"a": {Status: "current"}},
+		"bad status":    {"a": {Provider: "Acme", Status: "beta"}},
+	} {
+		if _, err := NewTenantRegistry("acme", overlay, nil); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+// ── Provider status tests ────────────────────────────────────────────
+
+func TestFormatProviderStatus(t *testing.T) {
+	result := FormatProviderStatus([]status.ProviderStatus{
+		{Provider: "OpenAI", Indicator: status.IndicatorNone, Description: "All Systems Operational"},
+		{Provider: "Anthropic", Indicator: status.IndicatorMajor, Description: "Partial System Outage",
+			Incidents: []status.Incident{{Name: "Elevated errors", Impact: "major", Status: "investigating", URL: "https://stspg.io/x"}}},
+	})
+	for _, want := range []string{"| OpenAI | none |", "**major outage**", "### Active Incidents", "Elevated errors", "https://stspg.io/x"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in result, got:\n%s", want, result)
+		}
+	}
+}
+
+func TestFormatProviderStatus_NoIncidents(t *testing.T) {
+	result := FormatProviderStatus([]status.ProviderStatus{
+		{Provider: "OpenAI", Indicator: status.IndicatorNone, Description: "All Systems Operational"},
+	})
+	if strings.Contains(result, "Active Incidents") {
+		t.Errorf("did not expect incidents section, got:\n%s", result)
+	}
+}
+
+// ── Lookup cache tests ───────────────────────────────────────────────
+
+func TestLRUCache_Eviction(t *testing.T) {
+	c := newLRUCache[string, int](2)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	if _, ok := c.Get("a"); !ok { // touch "a" so "b" becomes least recent
+		t.Fatal("expected 'a' in cache")
+	}
+	c.Add("c", 3)
+	if _, ok := c.Get("b"); ok {
+		t.Error("expected 'b' to be evicted")
+	}
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Errorf("expected 'a'=1, got %d, %v", v, ok)
+	}
+	if c.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", c.Len())
+	}
+	c.Purge()
+	if c.Len() != 0 {
+		t.Errorf("expected empty cache after Purge, got %d", c.Len())
+	}
+}
+
+func TestFindModel_CachedMatchesUncached(t *testing.T) {
+	for _, q := range []string{"gpt-5", "GPT-5", "opus", "opus-4-6", "nonexistent-xyz"} {
+		want, wantOK := BaseRegistry().findModel(q)
+		for i := 0; i < 2; i++ {
+			got, ok := BaseRegistry().FindModel(q)
+			if ok != wantOK || got.ID != want.ID {
+				t.Errorf("FindModel(%q) call %d = %q,%v; want %q,%v", q, i, got.ID, ok, want.ID, wantOK)
+			}
+		}
+	}
+}
+
+func TestSuggestModels_CacheReturnsCopy(t *testing.T) {
+	first := BaseRegistry().SuggestModels("gpt-55", 3)
+	first[0] = "mutated"
+	second := BaseRegistry().SuggestModels("gpt-55", 3)
+	if second[0] != "gpt-5" {
+		t.Errorf("mutating a returned slice should not affect the cache, got %q", second[0])
+	}
+}
+
+// ── Churn risk tests ─────────────────────────────────────────────────
+
+func TestLineageKey(t *testing.T) {
+	m := models.Models
+	if lineageKey(m["gpt-5.2-codex"]) != lineageKey(m["gpt-5.3-codex"]) {
+		t.Errorf("gpt-5.2-codex and gpt-5.3-codex should share a lineage: %q vs %q", lineageKey(m["gpt-5.2-codex"]), lineageKey(m["gpt-5.3-codex"]))
+	}
+	if lineageKey(m["o3-mini"]) != lineageKey(m["o4-mini"]) {
+		t.Errorf("o3-mini and o4-mini should share a lineage: %q vs %q", lineageKey(m["o3-mini"]), lineageKey(m["o4-mini"]))
+	}
+	if lineageKey(m["gpt-5.3-codex"]) == lineageKey(m["gpt-5.4"]) {
+		t.Error("codex and base GPT models should be separate lineages")
+	}
+}
+
+func TestLifetimeMonths_FromNotes(t *testing.T) {
+	m := models.Models["gpt-5.3-codex"]
+	if m.Status != "deprecated" {
+		t.Skip("gpt-5.3-codex is no longer deprecated in the registry")
+	}
+	if _, ok := deprecatedMonth(m); !ok {
+		t.Fatalf("no deprecation month parsed from notes %q", m.Notes)
+	}
+	if got, ok := lifetimeMonths(m); !ok || got >= fastDeprecationMonths {
+		t.Errorf("lifetimeMonths(gpt-5.3-codex) = %d, %v; want under %d", got, ok, fastDeprecationMonths)
+	}
+	if _, ok := lifetimeMonths(models.Models["gpt-5.4"]); ok {
+		t.Error("current model should have no lifetime")
+	}
+}
+
+// nextCodex continues the codex lineage, whose last two versions were each
+// deprecated a month after release.
+var nextCodex = map[string]models.Model{
+	"gpt-5.5-codex": {
+		DisplayName:   "GPT-5.5 Codex",
+		Provider:      "OpenAI",
+		ContextWindow: 400_000,
+		Reasoning:     true,
+		PricingInput:  1.75,
+		PricingOutput: 14,
+		ReleaseDate:   "2026-09",
+		Status:        "current",
+		Notes:         "Agentic coding.",
+	},
+}
+
+func TestChurnRisk(t *testing.T) {
+	reg, err := NewTenantRegistry("churn", nextCodex, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, _ := reg.FindModel("gpt-5.5-codex")
+	risk, fast := reg.churnRisk(m)
+	if risk != 1 || len(fast) != predecessorWindow {
+		t.Errorf("churnRisk(gpt-5.5-codex) = %v, %v; want 1 with both predecessors fast", risk, fast)
+	}
+	if risk, _ := reg.churnRisk(models.Models["gpt-5.4"]); risk != 0 {
+		t.Errorf("churnRisk(gpt-5.4) = %v, want 0", risk)
+	}
+}
+
+func TestChurnRisk_ChangelogDates(t *testing.T) {
+	reg, err := NewTenantRegistry("churn", nextCodex, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, _ := reg.FindModel("gpt-5.5-codex")
+	_, fast := reg.churnRisk(m)
+	if len(fast) == 0 {
+		t.Fatal("expected fast predecessors from notes")
+	}
+	// The changelog recording the deprecations two years after release
+	// outranks the dates in the notes.
+	dates := make(map[string]time.Time)
+	for _, p := range fast {
+		rel, err := time.Parse("2006-01", models.Models[p.ID].ReleaseDate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dates[p.ID] = rel.AddDate(2, 0, 14)
+	}
+	SetDeprecationDates(dates)
+	defer SetDeprecationDates(nil)
+	if risk, fast := reg.churnRisk(m); risk != 0 {
+		t.Errorf("churnRisk with changelog dates = %v, %v; want 0", risk, fast)
+	}
+}
+
+func TestWantsStability(t *testing.T) {
+	for task, want := range map[string]bool{
+		"stable coding agent":                  true,
+		"long-term support bot":                true,
+		"long-lasting summaries":               true,
+		"unstable prototypes are fine":         false,
+		"not stable, just cheap":               false,
+		"we don't need long-term support":      false,
+		"no durable storage, but a stable api": true,
+		"prototype without longevity concerns": false,
+		"established workflow":                 false,
+	} {
+		if got := wantsStability(task); got != want {
+			t.Errorf("wantsStability(%q) = %v, want %v", task, got, want)
+		}
+	}
+}
+
+func TestRecommendModel_StabilityPenalizesChurn(t *testing.T) {
+	reg, err := NewTenantRegistry("churn", nextCodex, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := "stable long-term coding agent"
+	result := reg.RecommendModel(RecommendQuery{Task: task})
+	if !strings.Contains(result, "**Stability:**") {
+		t.Errorf("stability task should explain the churn penalty:\n%s", result)
+	}
+	off := reg.RecommendModel(RecommendQuery{Task: task, Weights: map[string]float64{"churn_risk": 100}})
+	if strings.Contains(off, "`gpt-5.5-codex`") {
+		t.Errorf("a heavy churn_risk weight should drop gpt-5.5-codex:\n%s", off)
+	}
+	on := reg.RecommendModel(RecommendQuery{Task: task, Weights: map[string]float64{"churn_risk": 0, "recency": 100}})
+	if !strings.Contains(on, "`gpt-5.5-codex`") {
+		t.Fatalf("without churn_risk, recency should surface gpt-5.5-codex:\n%s", on)
+	}
+	if !strings.Contains(on, "Churn risk:") {
+		t.Errorf("listed churn-prone model should name its fast predecessors:\n%s", on)
+	}
+	if strings.Contains(reg.RecommendModel(RecommendQuery{Task: "coding agent"}), "**Stability:**") {
+		t.Error("non-stability task should not mention churn")
+	}
+}
+
+func TestWeightNames_IncludesChurnRisk(t *testing.T) {
+	if !strings.Contains(","+strings.Join(WeightNames(), ",")+",", ",churn_risk,") {
+		t.Errorf("WeightNames() = %v, want churn_risk", WeightNames())
+	}
+}
+
+func TestGetRegistryVersion(t *testing.T) {
+	build := buildinfo.Info{Version: "1.4.0", Commit: "3f2a9c1b7d4e5f60", Date: "2026-10-16T09:30:00Z"}
+	result := BaseRegistry().GetRegistryVersion(build, 42)
+	info := BaseRegistry().Info()
+	for _, want := range []string{
+		"| Server version | 1.4.0 |",
+		"| Commit | 3f2a9c1b7d4e5f60 |",
+		"| Build date | 2026-10-16T09:30:00Z |",
+		"| Registry hash | " + info.Version + " |",
+		fmt.Sprintf("| Models | %d |", info.Models),
+		"| Changelog cursor | 42 |",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("missing %q:\n%s", want, result)
+		}
+	}
+	if !strings.Contains(BaseRegistry().GetRegistryVersion(buildinfo.Info{Version: "dev"}, 0), "| Commit | unknown |") {
+		t.Error("an unstamped build should report its commit as unknown")
+	}
+}
+
+func TestGetTaskShortlist(t *testing.T) {
+	index := BaseRegistry().GetTaskShortlist("")
+	for _, sl := range models.TaskShortlists {
+		if !strings.Contains(index, "| "+sl.Task+" |") {
+			t.Errorf("index missing %s:\n%s", sl.Task, index)
+		}
+	}
+
+	for task, want := range map[string]string{
+		"agentic-coding":                 "## Agentic coding shortlist",
+		"OCR":                            "## OCR and document understanding shortlist",
+		"Translation":                    "## Translation shortlist",
+		"translate our product docs":     "## Translation shortlist",
+		"customer support chatbot":       "## Customer support shortlist",
+		"extract contact fields as JSON": "## Data extraction shortlist",
+	} {
+		if got := BaseRegistry().GetTaskShortlist(task); !strings.Contains(got, want) {
+			t.Errorf("GetTaskShortlist(%q) missing %q:\n%s", task, want, got)
+		}
+	}
+
+	// Deterministic: the same curated order on every call.
+	coding := BaseRegistry().GetTaskShortlist("agentic-coding")
+	if !strings.Contains(coding, "| 1 | top pick | claude-opus-4-6 |") || coding != BaseRegistry().GetTaskShortlist("agentic-coding") {
+		t.Errorf("unexpected or unstable coding shortlist:\n%s", coding)
+	}
+
+	if got := BaseRegistry().GetTaskShortlist("protein folding"); !strings.Contains(got, "No curated shortlist matches") || !strings.Contains(got, "recommend_model") {
+		t.Errorf("unknown task should list shortlists and point at recommend_model:\n%s", got)
+	}
+	// Keywords match whole words: "decode" is not "code".
+	if _, ok := findShortlist("decode base64"); ok {
+		t.Error("keywords should not match inside other words")
+	}
+}
+
+func TestGetTaskShortlist_Policy(t *testing.T) {
+	withPolicy(t, &Policy{BannedModels: []string{"claude-opus-4-6"}})
+	result := BaseRegistry().GetTaskShortlist("agentic-coding")
+	if strings.Contains(result, "claude-opus-4-6") {
+		t.Errorf("banned pick should be hidden:\n%s", result)
+	}
+	if !strings.Contains(result, "| 1 | top pick | gpt-5.1-codex |") || !strings.Contains(result, "1 pick(s) hidden by org policy") {
+		t.Errorf("expected the remaining picks renumbered and a policy note:\n%s", result)
+	}
+}
+
+func TestGetTaskShortlist_TenantStatus(t *testing.T) {
+	reg, err := NewTenantRegistry("acme", map[string]models.Model{
+		"claude-opus-4-6": func() models.Model {
+			m := models.Models["claude-opus-4-6"]
+			m.Status = "deprecated"
+			return m
+		}(),
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := reg.GetTaskShortlist("coding")
+	if !strings.Contains(result, "claude-opus-4-6 (deprecated)") || !strings.Contains(result, "No longer current in this registry: claude-opus-4-6") {
+		t.Errorf("tenant status should be reflected:\n%s", result)
+	}
+}
+
+// ── Provider tools ──────────────────────────────────────────────────
+
+func TestListProviders(t *testing.T) {
+	verified := map[string]time.Time{"OpenAI": time.Date(2026, 10, 15, 3, 0, 0, 0, time.UTC)}
+	result := BaseRegistry().ListProviders(map[string]int{"OpenAI": 12, "Anthropic": 2}, verified)
+	for name := range models.Providers {
+		if !strings.Contains(result, "| "+name+" |") {
+			t.Errorf("expected a row for %s", name)
+		}
+	}
+	if !strings.Contains(result, "`ANTHROPIC_API_KEY`") {
+		t.Error("expected the auth env var column")
+	}
+	for _, want := range []string{"| Changes (90d) |", "| 12 (hot) |", "| 2 (mild) |", "| 0 (cold) |"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected churn %q:\n%s", want, result)
+		}
+	}
+	for _, want := range []string{"| 2026-10-15 | 12 (hot) |", "| — | 2 (mild) |", "**Capability coverage**", "| Provider | vision | reasoning |"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q:\n%s", want, result)
+		}
+	}
+
+	// With no changelog history, churn is unknown rather than cold.
+	result = BaseRegistry().ListProviders(nil, verified)
+	if strings.Contains(result, "(cold)") || !strings.Contains(result, "| 2026-10-15 | — |") || !strings.Contains(result, "changelog has no entries yet") {
+		t.Errorf("expected churn to be reported as unknown:\n%s", result)
+	}
+}
+
+func TestListProviders_OverlayOnlyProvider(t *testing.T) {
+	reg, err := NewTenantRegistry("acme", map[string]models.Model{
+		"acme-small": {DisplayName: "Acme Small", Provider: "Acme", ContextWindow: 1000, MaxOutputTokens: 100, Status: "current", PricingInput: 0.1, PricingOutput: 0.2, Vision: true},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := reg.ListProviders(map[string]int{"Acme": 1}, nil)
+	for _, want := range []string{"| Acme | 1 | 1 | 0 | 0 | `acme-small` ($0.12) | `acme-small` ($0.12) | — | 1 (mild) |", "| Acme | 1/1 |", "| Acme | Unknown | — | — |"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected the overlay-only provider in every table, missing %q:\n%s", want, result)
+		}
+	}
+	if !strings.Contains(result, "| OpenAI |") {
+		t.Error("base providers should still be listed")
+	}
+}
+
+func TestProviderStats(t *testing.T) {
+	reg, err := NewTenantRegistry("acme", map[string]models.Model{
+		"acme-small":   {DisplayName: "Acme Small", Provider: "Acme", ContextWindow: 1000, MaxOutputTokens: 100, Status: "current", PricingInput: 0.1, PricingOutput: 0.2, Vision: true},
+		"acme-large":   {DisplayName: "Acme Large", Provider: "Acme", ContextWindow: 1000, MaxOutputTokens: 100, Status: "current", PricingInput: 5, PricingOutput: 20},
+		"acme-preview": {DisplayName: "Acme Preview", Provider: "Acme", ContextWindow: 1000, MaxOutputTokens: 100, Status: "current", PricingInput: 50, PricingOutput: 200, Maturity: models.MaturityPreview},
+		"acme-old":     {DisplayName: "Acme Old", Provider: "Acme", ContextWindow: 1000, MaxOutputTokens: 100, Status: "deprecated", PricingInput: 0.01, PricingOutput: 0.01},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := reg.providerStats()["Acme"]
+	if s.total != 4 || s.counts["current"] != 3 || s.counts["deprecated"] != 1 {
+		t.Errorf("counts = %v, total %d", s.counts, s.total)
+	}
+	if s.cheapest.ID != "acme-small" {
+		t.Errorf("cheapest = %s, want acme-small (deprecated models don't count)", s.cheapest.ID)
+	}
+	if s.flagship.ID != "acme-large" {
+		t.Errorf("flagship = %s, want the priciest stable model acme-large", s.flagship.ID)
+	}
+	if s.caps[models.CapVision] != 1 {
+		t.Errorf("vision coverage = %d, want 1", s.caps[models.CapVision])
+	}
+}
+
+func TestGetProviderInfo(t *testing.T) {
+	result := BaseRegistry().GetProviderInfo("kimi")
+	if !strings.Contains(result, "## Moonshot") {
+		t.Fatalf("expected the kimi alias to resolve to Moonshot, got: %s", result)
+	}
+	for _, want := range []string{"`MOONSHOT_API_KEY`", "https://api.moonshot.ai/v1", "**Current models:**"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in result, got: %s", want, result)
+		}
+	}
+}
+
+func TestGetProviderInfo_NotFound(t *testing.T) {
+	result := BaseRegistry().GetProviderInfo("acme")
+	if !strings.Contains(result, "not found") || !strings.Contains(result, "Anthropic") {
+		t.Errorf("expected not found with the known providers, got: %s", result)
+	}
+}
+
+// ── Canonicalization ────────────────────────────────────────────────
+
+func TestCanonicalizeID(t *testing.T) {
+	result := BaseRegistry().CanonicalizeID("us.anthropic.claude-opus-4-6-v1:0")
+	for _, want := range []string{"→ `claude-opus-4-6`", "strip Bedrock region", "| openrouter | `anthropic/claude-opus-4.6` |", "| litellm | `anthropic/claude-opus-4-6` |"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in result, got: %s", want, result)
+		}
+	}
+}
+
+func TestCanonicalizeID_NoPartialMatch(t *testing.T) {
+	// get_model_info partially matches claude-opus-4 to claude-opus-4-0;
+	// canonicalization must not.
+	result := BaseRegistry().CanonicalizeID("claude-opus-4")
+	if strings.Contains(result, "→") || !strings.Contains(result, "does not canonicalize") {
+		t.Errorf("expected no canonical ID for a partial match, got: %s", result)
+	}
+}
@@ -0,0 +1,163 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"go-server/internal/models"
+)
+
+// GetUsageExampleInput holds parameters for the get_usage_example tool.
+type GetUsageExampleInput struct {
+	ModelID string `json:"model_id" jsonschema:"The model ID to generate a request for, e.g. gpt-5.2 or claude-opus-4-6"`
+	FormatInput
+}
+
+// GetUsageExample returns a minimal OpenAI SDK (Python) call for a model,
+// built from the registry's endpoint, API surface, parameter, and reasoning
+// data so the code only sends knobs the API accepts: the provider's
+// base_url, max_completion_tokens where max_tokens is rejected, the
+// Responses API for responses-only models, and the model's own
+// reasoning-effort or thinking setting.
+func (r *Registry) GetUsageExample(modelID string) string {
+	if modelID == "" {
+		return "Please provide a model ID. Example: `get_usage_example(model_id=\"gpt-5\")`"
+	}
+	m, found := r.FindModel(modelID)
+	if !found {
+		suggestions := r.SuggestModels(modelID, 3)
+		return fmt.Sprintf("Model `%s` not found in registry. Did you mean: %s",
+			modelID, strings.Join(suggestions, ", "))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Usage: %s (`%s`)\n\n", m.DisplayName, m.ID)
+	if m.Status != "current" {
+		fmt.Fprintf(&b, "**Warning:** `%s` is %s. Check `check_model_status` for its replacement before shipping this.\n\n", m.ID, m.Status)
+	}
+	e, ok := models.Endpoints[m.Provider]
+	if ok && !e.OpenAICompatible {
+		fmt.Fprintf(&b, "%s has no OpenAI-compatible endpoint. %s\n", m.Provider, e.Note)
+		return b.String()
+	}
+
+	reasoning := usageReasoning(m.ID)
+	b.WriteString("```python\nimport os\nfrom openai import OpenAI\n\nclient = OpenAI(\n")
+	switch {
+	case !ok:
+		b.WriteString("    base_url=\"...\",  # not recorded; see the provider's API docs\n")
+	case e.BaseURL == "":
+		fmt.Fprintf(&b, "    base_url=os.environ[%q],\n", envPrefix(m.Provider)+"_BASE_URL")
+	case m.Provider != "OpenAI":
+		fmt.Fprintf(&b, "    base_url=%q,\n", e.BaseURL)
+	}
+	fmt.Fprintf(&b, "    api_key=os.environ[%q],\n)\n\n", authEnvVar(m.Provider))
+
+	if models.ResponsesOnly(m.ID) {
+		fmt.Fprintf(&b, "response = client.responses.create(\n    model=%q,\n    input=\"Hello\",\n    max_output_tokens=1024,\n", m.ID)
+		if reasoning.kwarg != "" {
+			fmt.Fprintf(&b, "    reasoning={\"effort\": %s},\n", reasoning.value)
+		}
+		b.WriteString(")\nprint(response.output_text)\n```\n")
+	} else {
+		fmt.Fprintf(&b, "response = client.chat.completions.create(\n    model=%q,\n    messages=[{\"role\": \"user\", \"content\": \"Hello\"}],\n", m.ID)
+		fmt.Fprintf(&b, "    %s=1024,\n", maxTokensParam(m.ID))
+		switch {
+		case reasoning.kwarg != "":
+			fmt.Fprintf(&b, "    %s=%s,\n", reasoning.kwarg, reasoning.value)
+		case reasoning.extraBody != "":
+			fmt.Fprintf(&b, "    extra_body={%s},\n", reasoning.extraBody)
+		}
+		b.WriteString(")\nprint(response.choices[0].message.content)\n```\n")
+	}
+
+	var notes []string
+	if models.ResponsesOnly(m.ID) {
+		notes = append(notes, "Responses API only: chat/completions calls are rejected.")
+	}
+	if _, constrained := models.ParamConstraints[m.ID]; constrained {
+		notes = append(notes, "Parameters: "+strings.TrimSuffix(paramDetail(m.ID), ".")+".")
+	}
+	if c, ok := models.ReasoningControls[m.ID]; ok {
+		note := "Reasoning: " + c.Describe() + "."
+		if reasoning.native {
+			note += " The compatible endpoint doesn't take this setting; send `" + c.Example + "` through the native API."
+		}
+		if c.Note != "" {
+			note += " " + c.Note
+		}
+		notes = append(notes, note)
+	}
+	if ok && e.Note != "" {
+		notes = append(notes, "Endpoint: "+e.Note)
+	}
+	if len(notes) > 0 {
+		b.WriteString("\n")
+		for _, n := range notes {
+			b.WriteString("- " + n + "\n")
+		}
+	}
+	return b.String()
+}
+
+// usageSetting is how a model's reasoning control goes into an OpenAI SDK
+// call: as a keyword argument, in extra_body, or only through the native
+// API (native).
+type usageSetting struct {
+	kwarg, value string
+	extraBody    string
+	native       bool
+}
+
+// usageReasoning places a model's ReasoningControl example in an OpenAI SDK
+// call. reasoning_effort is an SDK argument; a body field named after the
+// control, like Anthropic's thinking, passes through extra_body; anything
+// else, like Gemini's generationConfig, is native-API only. Examples are
+// JSON fragments, which read as Python literals as long as they hold no
+// true, false, or null.
+func usageReasoning(id string) usageSetting {
+	c, ok := models.ReasoningControls[id]
+	if !ok {
+		return usageSetting{}
+	}
+	key, value, _ := strings.Cut(c.Example, ": ")
+	switch key = strings.Trim(key, `"`); key {
+	case "reasoning_effort":
+		return usageSetting{kwarg: key, value: value}
+	case c.Param:
+		return usageSetting{extraBody: c.Example}
+	}
+	return usageSetting{native: true}
+}
+
+// maxTokensParam names the output cap parameter a model accepts.
+func maxTokensParam(id string) string {
+	c := models.ParamConstraints[id]
+	if slices.Contains(c.Unsupported, "max_tokens") || slices.Contains(c.Supported, "max_completion_tokens") {
+		return "max_completion_tokens"
+	}
+	return "max_tokens"
+}
+
+// authEnvVar returns the variable a provider's API key is conventionally
+// read from.
+func authEnvVar(provider string) string {
+	if p, ok := models.LookupProvider(provider); ok && p.AuthEnvVar != "" {
+		return p.AuthEnvVar
+	}
+	return envPrefix(provider) + "_API_KEY"
+}
+
+// envPrefix turns a provider name into an environment variable prefix.
+func envPrefix(provider string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, provider)
+}
@@ -0,0 +1,39 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"go-server/internal/buildinfo"
+)
+
+// GetRegistryVersionInput holds parameters for the get_registry_version tool.
+type GetRegistryVersionInput struct {
+	FormatInput
+}
+
+// GetRegistryVersion reports which server build is answering and which
+// registry data it serves: the build's version, commit, and build date, the
+// registry content hash and size, and the changelog cursor (passed in, since
+// the changelog package sits above this one).
+func (r *Registry) GetRegistryVersion(build buildinfo.Info, cursor int) string {
+	info := r.Info()
+	unknown := func(s string) string {
+		if s == "" {
+			return "unknown"
+		}
+		return s
+	}
+	var b strings.Builder
+	b.WriteString("## Registry Version\n\n")
+	b.WriteString("| Field | Value |\n|-------|-------|\n")
+	fmt.Fprintf(&b, "| Server version | %s |\n", build.Version)
+	fmt.Fprintf(&b, "| Commit | %s |\n", unknown(build.Commit))
+	fmt.Fprintf(&b, "| Build date | %s |\n", unknown(build.Date))
+	fmt.Fprintf(&b, "| Registry hash | %s |\n", info.Version)
+	fmt.Fprintf(&b, "| Models | %d |\n", info.Models)
+	fmt.Fprintf(&b, "| Providers | %d |\n", info.Providers)
+	fmt.Fprintf(&b, "| Changelog cursor | %d |\n", cursor)
+	b.WriteString("\nThe registry hash changes whenever model data does; pass the cursor as `since` to /api/changes to fetch only newer changes.")
+	return b.String()
+}